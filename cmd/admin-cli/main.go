@@ -17,6 +17,7 @@ package main
 import (
 	"fmt"
 	"io"
+	"kaf-mirror/internal/auth"
 	"kaf-mirror/internal/config"
 	"kaf-mirror/internal/database"
 	"kaf-mirror/pkg/utils"
@@ -57,6 +58,11 @@ func copyFile(src, dst string) error {
 func main() {
 	var dbPath string
 
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
+
 	var rootCmd = &cobra.Command{
 		Use:   "admin-cli",
 		Short: "A CLI for bootstrapping and emergency maintenance of kaf-mirror.",
@@ -68,6 +74,7 @@ and for emergency maintenance. It interacts directly with the database.`,
 			if err != nil {
 				log.Fatalf("Failed to connect to database: %v", err)
 			}
+			database.ConfigureAuditChain(cfg.Audit.HMACKey)
 		},
 		PersistentPostRun: func(cmd *cobra.Command, args []string) {
 			if db != nil {
@@ -76,10 +83,6 @@ and for emergency maintenance. It interacts directly with the database.`,
 		},
 	}
 
-	cfg, err := config.LoadConfig()
-	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
-	}
 	rootCmd.PersistentFlags().StringVar(&dbPath, "db-path", cfg.Database.Path, "Path to the SQLite database file.")
 
 	var usersCmd = &cobra.Command{
@@ -118,14 +121,31 @@ and for emergency maintenance. It interacts directly with the database.`,
 			if err != nil {
 				log.Fatalf("Failed to list users: %v", err)
 			}
-			fmt.Println("ID\tUsername\tCreated At")
+			fmt.Println("ID\tUsername\tSource\tDisabled\tLocked\tCreated At")
 			for _, user := range users {
-				fmt.Printf("%d\t%s\t%s\n", user.ID, user.Username, user.CreatedAt.Format(time.RFC3339))
+				fmt.Printf("%d\t%s\t%s\t%t\t%t\t%s\n", user.ID, user.Username, user.AuthSource, user.Disabled, user.IsLocked(), user.CreatedAt.Format(time.RFC3339))
+			}
+		},
+	}
+
+	var unlockUserCmd = &cobra.Command{
+		Use:   "unlock [username]",
+		Short: "Unlock a user locked out after failed logins.",
+		Long:  `This command clears the failed login counter of a user and removes any temporary lockout.`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			user, err := database.GetUserByUsername(db, args[0])
+			if err != nil {
+				log.Fatalf("User not found: %v", err)
+			}
+			if err := database.ClearFailedLogins(db, user.ID); err != nil {
+				log.Fatalf("Failed to unlock user: %v", err)
 			}
+			fmt.Printf("User %s unlocked.\n", user.Username)
 		},
 	}
 
-	usersCmd.AddCommand(addUserCmd, listUsersCmd)
+	usersCmd.AddCommand(addUserCmd, listUsersCmd, unlockUserCmd)
 
 	var tokensCmd = &cobra.Command{
 		Use:   "tokens",
@@ -566,11 +586,133 @@ and for emergency maintenance. It interacts directly with the database.`,
 	}
 	importFullCmd.Flags().String("config-path", "configs/default.yml", "Path to configuration file")
 
+	var ldapCmd = &cobra.Command{
+		Use:   "ldap",
+		Short: "Inspect and synchronize LDAP / Active Directory authentication.",
+		Long:  `The ldap command verifies the LDAP configuration and reconciles directory users with the database.`,
+	}
+
+	var ldapTestCmd = &cobra.Command{
+		Use:   "test [username]",
+		Short: "Test the LDAP connection and optionally a user login.",
+		Long: `This command binds with the configured service account and reads the base DN.
+When a username is given, it also looks up the user, verifies the password by bind and prints the mapped role.`,
+		Args: cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if !cfg.Auth.LDAP.Enabled {
+				fmt.Println("Warning: auth.ldap.enabled is false; the server will not use LDAP.")
+			}
+			authenticator := auth.NewLDAPAuthenticator(cfg.Auth.LDAP)
+
+			if err := authenticator.TestConnection(); err != nil {
+				log.Fatalf("LDAP connection test failed: %v", err)
+			}
+			fmt.Printf("Connected to %s and bound as %s\n", cfg.Auth.LDAP.URL, cfg.Auth.LDAP.BindDN)
+
+			if len(args) == 0 {
+				return
+			}
+
+			password, _ := cmd.Flags().GetString("password")
+			var ldapUser *auth.LDAPUser
+			var err error
+			if password == "" {
+				prompt := &survey.Password{Message: fmt.Sprintf("Password for %s (leave empty to skip bind):", args[0])}
+				survey.AskOne(prompt, &password)
+			}
+			if password == "" {
+				ldapUser, err = authenticator.Lookup(args[0])
+			} else {
+				ldapUser, err = authenticator.Authenticate(args[0], password)
+			}
+			if err != nil && err != auth.ErrNoMappedRole {
+				log.Fatalf("LDAP user test failed: %v", err)
+			}
+
+			fmt.Printf("User DN: %s\n", ldapUser.DN)
+			fmt.Printf("Groups:\n")
+			for _, group := range ldapUser.Groups {
+				fmt.Printf("  %s\n", group)
+			}
+			if ldapUser.Role == "" {
+				fmt.Println("Mapped role: none (login would be denied)")
+			} else {
+				fmt.Printf("Mapped role: %s\n", ldapUser.Role)
+			}
+			if password != "" {
+				fmt.Println("Password verified by bind.")
+			}
+		},
+	}
+	ldapTestCmd.Flags().String("password", "", "User's password (prompted when omitted)")
+
+	var ldapSyncCmd = &cobra.Command{
+		Use:   "sync",
+		Short: "Synchronize LDAP users with the directory now.",
+		Long:  `This command disables directory users that were removed from LDAP and updates roles from group membership.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			result, err := auth.NewLDAPAuthenticator(cfg.Auth.LDAP).SyncUsers(db)
+			if err != nil {
+				log.Fatalf("LDAP sync failed: %v", err)
+			}
+			fmt.Printf("Checked %d users: %d disabled, %d re-enabled, %d role updates\n",
+				result.Checked, result.Disabled, result.Enabled, result.RoleUpdates)
+		},
+	}
+
+	ldapCmd.AddCommand(ldapTestCmd, ldapSyncCmd)
+
+	var auditCmd = &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the tamper-evident audit trail.",
+		Long:  `The audit command verifies the hash chain of operational events stored in the database.`,
+	}
+
+	var auditVerifyCmd = &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the audit hash chain for gaps or edits.",
+		Long: `This command recomputes the hash of every operational event and checks that each event links to its predecessor.
+Deleted, inserted or modified events are reported. The command exits with status 1 if the chain is broken.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			hmacKey, _ := cmd.Flags().GetString("hmac-key")
+			if hmacKey == "" {
+				hmacKey = cfg.Audit.HMACKey
+			}
+
+			result, err := database.VerifyAuditChain(db, hmacKey)
+			if err != nil {
+				log.Fatalf("Failed to verify audit chain: %v", err)
+			}
+
+			fmt.Printf("Checked %d events (IDs %d-%d)\n", result.Checked, result.FirstID, result.LastID)
+			if result.Unchained > 0 {
+				fmt.Printf("Legacy events without hash: %d\n", result.Unchained)
+			}
+			if result.HeadHash != "" {
+				fmt.Printf("Head hash: %s\n", result.HeadHash)
+			}
+			if result.Valid {
+				fmt.Println("Audit chain is intact.")
+				return
+			}
+
+			fmt.Printf("Audit chain is BROKEN (%d issues):\n", len(result.Issues))
+			for _, issue := range result.Issues {
+				fmt.Printf("  event %d: %s\n", issue.EventID, issue.Problem)
+			}
+			db.Close()
+			os.Exit(1)
+		},
+	}
+	auditVerifyCmd.Flags().String("hmac-key", "", "HMAC key used to sign the chain (defaults to audit.hmac_key)")
+
+	auditCmd.AddCommand(auditVerifyCmd)
+
 	backupCmd.AddCommand(backupDatabaseCmd, backupConfigCmd, backupFullCmd)
 	restoreCmd.AddCommand(restoreDatabaseCmd, restoreConfigCmd)
 	importCmd.AddCommand(importDatabaseCmd, importConfigCmd, importFullCmd)
 
-	rootCmd.AddCommand(usersCmd, tokensCmd, repairCmd, resetAdminPasswordCmd, backupCmd, restoreCmd, importCmd)
+	rootCmd.AddCommand(usersCmd, tokensCmd, repairCmd, resetAdminPasswordCmd, ldapCmd, auditCmd, backupCmd, restoreCmd, importCmd)
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
 	if err := rootCmd.Execute(); err != nil {
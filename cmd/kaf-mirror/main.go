@@ -20,11 +20,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"kaf-mirror/internal/audit"
 	"kaf-mirror/internal/config"
 	"kaf-mirror/internal/database"
 	"kaf-mirror/internal/manager"
 	"kaf-mirror/internal/server"
+	"kaf-mirror/internal/telemetry"
 	"kaf-mirror/pkg/logger"
 	"kaf-mirror/pkg/utils"
 	"log"
@@ -44,10 +47,10 @@ func main() {
 	}
 	fmt.Println("Configuration loaded successfully.")
 
-	if err := logger.InitializeFromConfig(cfg.Logging.File, cfg.Logging.Level, cfg.Logging.Console); err != nil {
+	if err := logger.InitializeFromConfig(cfg.Logging.File, cfg.Logging.Level, cfg.Logging.Console, cfg.Logging.Format); err != nil {
 		log.Fatalf("Failed to initialize logger: %v", err)
 	}
-	logger.Info("Logger initialized with level %s, console=%t", cfg.Logging.Level, cfg.Logging.Console)
+	logger.Info("Logger initialized with level %s, format=%s, console=%t", cfg.Logging.Level, cfg.Logging.Format, cfg.Logging.Console)
 
 	// Initialize database
 	db, err := database.InitDB(cfg.Database.Path)
@@ -96,6 +99,22 @@ func main() {
 	}
 	fmt.Println("Database initialized successfully.")
 
+	// Chain operational events and stream them to the configured audit sinks
+	database.ConfigureAuditChain(cfg.Audit.HMACKey)
+	auditExporter, err := audit.NewExporter(cfg.Audit)
+	if err != nil {
+		log.Fatalf("Failed to initialize audit export: %v", err)
+	}
+	if auditExporter != nil {
+		database.AddOperationalEventListener(auditExporter.Enqueue)
+	}
+
+	// Export traces to the OTLP collector when tracing is enabled
+	shutdownTelemetry, err := telemetry.Setup(context.Background(), cfg.Monitoring, Version)
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+
 	// Initialize the Hub and JobManager
 	hub := server.NewHub()
 	jobManager := manager.New(db, cfg, hub)
@@ -133,5 +152,13 @@ func main() {
 	if err := srv.Shutdown(); err != nil {
 		log.Printf("API server shutdown error: %v", err)
 	}
+	jobManager.Close()
+	if err := shutdownTelemetry(context.Background()); err != nil {
+		log.Printf("Tracing shutdown error: %v", err)
+	}
+	if auditExporter != nil {
+		database.ResetOperationalEventListeners()
+		auditExporter.Close()
+	}
 	fmt.Println("Shutdown complete.")
 }
@@ -40,6 +40,8 @@ type DataManager struct {
 	fetchJob         func(jobID string) (map[string]interface{}, error)
 	fetchCluster     func(clusterName string) (map[string]interface{}, error)
 	fetchJobMappings func(jobID string) ([]map[string]interface{}, error)
+	fetchPartitions  func(jobID string) (map[string]interface{}, error)
+	fetchJobLogs     func(jobID string) ([]map[string]interface{}, error)
 }
 
 type DataFetchers struct {
@@ -52,6 +54,8 @@ type DataFetchers struct {
 	FetchJob         func(jobID string) (map[string]interface{}, error)
 	FetchCluster     func(clusterName string) (map[string]interface{}, error)
 	FetchJobMappings func(jobID string) ([]map[string]interface{}, error)
+	FetchPartitions  func(jobID string) (map[string]interface{}, error)
+	FetchJobLogs     func(jobID string) ([]map[string]interface{}, error)
 }
 
 func NewDataManager(token string, fetchers DataFetchers) *DataManager {
@@ -67,6 +71,8 @@ func NewDataManager(token string, fetchers DataFetchers) *DataManager {
 		fetchJob:         fetchers.FetchJob,
 		fetchCluster:     fetchers.FetchCluster,
 		fetchJobMappings: fetchers.FetchJobMappings,
+		fetchPartitions:  fetchers.FetchPartitions,
+		fetchJobLogs:     fetchers.FetchJobLogs,
 	}
 }
 
@@ -105,6 +111,17 @@ func (dm *DataManager) getCachedData(key string, ttl time.Duration, fetchFunc fu
 	return data, nil
 }
 
+// setCachedData stores data under key as if it had just been fetched.
+func (dm *DataManager) setCachedData(key string, ttl time.Duration, data interface{}) {
+	dm.cacheMutex.Lock()
+	dm.cache[key] = &CacheEntry{
+		Data:      data,
+		Timestamp: time.Now(),
+		TTL:       ttl,
+	}
+	dm.cacheMutex.Unlock()
+}
+
 func (dm *DataManager) GetJobs() ([]map[string]interface{}, error) {
 	data, err := dm.getCachedData("jobs", ListDataTTL, func() (interface{}, error) {
 		return dm.fetchJobs()
@@ -199,6 +216,29 @@ func (dm *DataManager) GetJobMappings(jobID string) ([]map[string]interface{}, e
 	return data.([]map[string]interface{}), nil
 }
 
+// GetJobLogs returns the most recent log records the server buffered for a job.
+func (dm *DataManager) GetJobLogs(jobID string) ([]map[string]interface{}, error) {
+	key := "job_logs_" + jobID
+	data, err := dm.getCachedData(key, LiveMetricsTTL, func() (interface{}, error) {
+		return dm.fetchJobLogs(jobID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data.([]map[string]interface{}), nil
+}
+
+func (dm *DataManager) GetJobPartitionMetrics(jobID string) (map[string]interface{}, error) {
+	key := "partitions_" + jobID
+	data, err := dm.getCachedData(key, LiveMetricsTTL, func() (interface{}, error) {
+		return dm.fetchPartitions(jobID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data.(map[string]interface{}), nil
+}
+
 func (dm *DataManager) InvalidateCache(key string) {
 	dm.cacheMutex.Lock()
 	delete(dm.cache, key)
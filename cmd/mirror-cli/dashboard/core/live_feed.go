@@ -0,0 +1,171 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fasthttp/websocket"
+)
+
+// liveEventVersion is the WebSocket event schema version the feed understands.
+const liveEventVersion = 1
+
+// liveChannels are the server channels the dashboard follows.
+var liveChannels = []string{"job_metrics", "events", "alerts", "insights"}
+
+// liveEvent is the envelope of a server WebSocket message.
+type liveEvent struct {
+	Version int             `json:"v"`
+	Type    string          `json:"type"`
+	Channel string          `json:"channel"`
+	JobID   string          `json:"job_id"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// LiveFeed keeps the DataManager's cache current from the server's WebSocket
+// event stream, so views refresh as soon as data changes instead of waiting
+// for the cache to expire. Polling remains the fallback while disconnected.
+type LiveFeed struct {
+	url string
+	dm  *DataManager
+
+	// Updates receives a value whenever cached data changed. Bursts are coalesced.
+	Updates chan struct{}
+
+	mu        sync.Mutex
+	conn      *websocket.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewLiveFeed returns a feed for the server at backendURL (http or https).
+func NewLiveFeed(backendURL, token string, dm *DataManager) *LiveFeed {
+	wsURL := strings.Replace(strings.TrimSuffix(backendURL, "/"), "http", "ws", 1) + "/ws?token=" + url.QueryEscape(token)
+	return &LiveFeed{
+		url:     wsURL,
+		dm:      dm,
+		Updates: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+// Run connects and applies events until Close, reconnecting with backoff.
+func (f *LiveFeed) Run() {
+	backoff := time.Second
+	for {
+		connected := f.session()
+		if connected {
+			backoff = time.Second
+		}
+		select {
+		case <-f.done:
+			return
+		case <-time.After(backoff):
+		}
+		if backoff < 30*time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// Close stops the feed.
+func (f *LiveFeed) Close() {
+	f.closeOnce.Do(func() {
+		close(f.done)
+		f.mu.Lock()
+		if f.conn != nil {
+			f.conn.Close()
+		}
+		f.mu.Unlock()
+	})
+}
+
+// session handles one connection and reports whether it was established.
+func (f *LiveFeed) session() bool {
+	conn, _, err := websocket.DefaultDialer.Dial(f.url, nil)
+	if err != nil {
+		return false
+	}
+	f.mu.Lock()
+	select {
+	case <-f.done:
+		f.mu.Unlock()
+		conn.Close()
+		return true
+	default:
+	}
+	f.conn = conn
+	f.mu.Unlock()
+	defer conn.Close()
+
+	for {
+		var ev liveEvent
+		if err := conn.ReadJSON(&ev); err != nil {
+			return true
+		}
+		if ev.Version != liveEventVersion {
+			continue
+		}
+		if ev.Type == "welcome" {
+			var welcome struct {
+				Channels []string `json:"channels"`
+			}
+			json.Unmarshal(ev.Data, &welcome)
+			for _, channel := range liveChannels {
+				if containsString(welcome.Channels, channel) {
+					conn.WriteJSON(map[string]string{"action": "subscribe", "channel": channel})
+				}
+			}
+			continue
+		}
+		if f.apply(ev) {
+			select {
+			case f.Updates <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// apply updates the cache for ev and reports whether anything changed.
+func (f *LiveFeed) apply(ev liveEvent) bool {
+	switch ev.Type {
+	case "job_metrics":
+		var metrics map[string]interface{}
+		if err := json.Unmarshal(ev.Data, &metrics); err != nil || ev.JobID == "" {
+			return false
+		}
+		f.dm.setCachedData("metrics_"+ev.JobID, LiveMetricsTTL, metrics)
+	case "event", "alert":
+		f.dm.InvalidateCache("logs")
+		f.dm.InvalidateCache("jobs")
+	case "insight":
+		f.dm.InvalidateCache("ai_insights")
+	default:
+		return false
+	}
+	return true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
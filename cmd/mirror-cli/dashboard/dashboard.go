@@ -36,6 +36,7 @@ type Dashboard struct {
 	topLevelGrid *ui.Grid
 	updateTicker *time.Ticker
 	focusedCategory core.Category
+	liveFeed *core.LiveFeed
 }
 
 func NewDashboard(token string, fetchers core.DataFetchers) *Dashboard {
@@ -141,6 +142,13 @@ func (d *Dashboard) Run() error {
 	d.updateTicker = time.NewTicker(2 * time.Second)
 	defer d.updateTicker.Stop()
 	
+	var liveUpdates chan struct{}
+	if d.liveFeed != nil {
+		go d.liveFeed.Run()
+		defer d.liveFeed.Close()
+		liveUpdates = d.liveFeed.Updates
+	}
+	
 	uiEvents := ui.PollEvents()
 	
 	for {
@@ -154,10 +162,20 @@ func (d *Dashboard) Run() error {
 		case <-d.updateTicker.C:
 			d.updateData()
 			d.render()
+			
+		case <-liveUpdates:
+			d.updateData()
+			d.render()
 		}
 	}
 }
 
+// EnableLiveUpdates makes the dashboard follow the server's WebSocket event
+// stream at backendURL in addition to polling.
+func (d *Dashboard) EnableLiveUpdates(backendURL, token string) {
+	d.liveFeed = core.NewLiveFeed(backendURL, token, d.dataManager)
+}
+
 func (d *Dashboard) handleEvent(event ui.Event) bool {
 	action := d.router.RouteEvent(event)
 	
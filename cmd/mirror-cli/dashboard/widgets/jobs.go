@@ -3,7 +3,9 @@ package widgets
 import (
 	"fmt"
 	"kaf-mirror/cmd/mirror-cli/dashboard/core"
+	"sort"
 	"strings"
+	"time"
 )
 
 type JobsFactory struct {
@@ -153,6 +155,25 @@ func (jf *JobsFactory) UpdateDetailData(dataManager *core.DataManager, itemID st
 		rows = append(rows, "No metrics available")
 	}
 	
+	rows = append(rows, "")
+	rows = append(rows, "=== PARTITION HEAT MAP (last 2h) ===")
+	partitionData, partitionsErr := dataManager.GetJobPartitionMetrics(itemID)
+	if partitionsErr != nil {
+		rows = append(rows, fmt.Sprintf("Partition Metrics Error: %v", partitionsErr))
+	} else {
+		partitions, _ := partitionData["partitions"].([]interface{})
+		rows = append(rows, RenderPartitionHeatMap(partitions)...)
+	}
+
+	rows = append(rows, "")
+	rows = append(rows, "=== RECENT LOGS ===")
+	jobLogs, logsErr := dataManager.GetJobLogs(itemID)
+	if logsErr != nil {
+		rows = append(rows, fmt.Sprintf("Logs Error: %v", logsErr))
+	} else {
+		rows = append(rows, RenderJobLogs(jobLogs)...)
+	}
+	
 	rows = append(rows, "")
 	rows = append(rows, "=== TOPIC MAPPINGS ===")
 	
@@ -233,11 +254,151 @@ func (jf *JobsFactory) HandleAction(action core.EventAction, dataManager *core.D
 	// Invalidate cache to force refresh of job data
 	dataManager.InvalidateCache("jobs")
 	dataManager.InvalidateCache("metrics_" + jobID)
+	dataManager.InvalidateCache("partitions_" + jobID)
 	dataManager.InvalidateCache("job_details_" + jobID)
 	
 	return nil
 }
 
+// heatLevels shade a partition by its lag relative to the worst partition.
+var heatLevels = []string{"·", "░", "▒", "▓", "█"}
+
+// heatMapWidth is the number of partition cells per heat map line.
+// RenderJobLogs formats buffered job log records one per line, oldest first.
+func RenderJobLogs(logs []map[string]interface{}) []string {
+	if len(logs) == 0 {
+		return []string{"No log records buffered for this job"}
+	}
+	rows := make([]string, 0, len(logs))
+	for _, entry := range logs {
+		timestamp := SafeString(entry["time"], "")
+		if ts, err := time.Parse(time.RFC3339Nano, timestamp); err == nil {
+			timestamp = ts.Local().Format("15:04:05")
+		}
+		component := ""
+		if c := SafeString(entry["component"], ""); c != "" {
+			component = c + ": "
+		}
+		rows = append(rows, fmt.Sprintf("%s %-5s %s%s", timestamp, SafeString(entry["level"], "INFO"), component, SafeString(entry["msg"], "")))
+	}
+	return rows
+}
+
+const heatMapWidth = 32
+
+// RenderPartitionHeatMap draws one row of cells per source topic, one cell per
+// partition, shaded by lag in seconds (or by message lag when no time lag is
+// reported), followed by the hottest partitions.
+func RenderPartitionHeatMap(partitions []interface{}) []string {
+	if len(partitions) == 0 {
+		return []string{"No partition metrics recorded yet"}
+	}
+	
+	type cell struct {
+		topic      string
+		partition  int
+		lagSeconds float64
+		messageLag float64
+		errors     float64
+		replicated float64
+	}
+	
+	var cells []cell
+	var maxSeconds, maxMessages float64
+	for _, raw := range partitions {
+		p, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		c := cell{
+			topic:      SafeString(p["topic"], "unknown"),
+			partition:  int(SafeFloat(p["partition"], 0)),
+			lagSeconds: SafeFloat(p["lag_seconds"], 0),
+			messageLag: SafeFloat(p["message_lag"], 0),
+			errors:     SafeFloat(p["error_count"], 0),
+			replicated: SafeFloat(p["messages_replicated"], 0),
+		}
+		if c.lagSeconds > maxSeconds {
+			maxSeconds = c.lagSeconds
+		}
+		if c.messageLag > maxMessages {
+			maxMessages = c.messageLag
+		}
+		cells = append(cells, c)
+	}
+	
+	intensity := func(c cell) float64 {
+		if maxSeconds > 0 {
+			return c.lagSeconds / maxSeconds
+		}
+		if maxMessages > 0 {
+			return c.messageLag / maxMessages
+		}
+		return 0
+	}
+	shade := func(c cell) string {
+		v := intensity(c)
+		if v <= 0 {
+			return heatLevels[0]
+		}
+		idx := 1 + int(v*float64(len(heatLevels)-1))
+		if idx >= len(heatLevels) {
+			idx = len(heatLevels) - 1
+		}
+		return heatLevels[idx]
+	}
+	
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].topic != cells[j].topic {
+			return cells[i].topic < cells[j].topic
+		}
+		return cells[i].partition < cells[j].partition
+	})
+	
+	var rows []string
+	for start := 0; start < len(cells); {
+		topic := cells[start].topic
+		end := start
+		for end < len(cells) && cells[end].topic == topic {
+			end++
+		}
+		var line strings.Builder
+		for i, c := range cells[start:end] {
+			if i > 0 && i%heatMapWidth == 0 {
+				rows = append(rows, fmt.Sprintf("%-20s %s", TruncateString(topic, 20), line.String()))
+				line.Reset()
+				topic = ""
+			}
+			line.WriteString(shade(c))
+		}
+		rows = append(rows, fmt.Sprintf("%-20s %s", TruncateString(topic, 20), line.String()))
+		start = end
+	}
+	
+	if maxSeconds > 0 {
+		rows = append(rows, fmt.Sprintf("Scale: %s caught up … %s %.1fs behind source", heatLevels[0], heatLevels[len(heatLevels)-1], maxSeconds))
+	} else {
+		rows = append(rows, fmt.Sprintf("Scale: %s caught up … %s %.0f messages behind", heatLevels[0], heatLevels[len(heatLevels)-1], maxMessages))
+	}
+	
+	hottest := append([]cell(nil), cells...)
+	sort.SliceStable(hottest, func(i, j int) bool {
+		if intensity(hottest[i]) != intensity(hottest[j]) {
+			return intensity(hottest[i]) > intensity(hottest[j])
+		}
+		return hottest[i].errors > hottest[j].errors
+	})
+	for i, c := range hottest {
+		if i == 3 || (intensity(c) == 0 && c.errors == 0) {
+			break
+		}
+		rows = append(rows, fmt.Sprintf("Hot: %s[%d] lag %.1fs / %.0f msgs, %.0f replicated, %.0f errors",
+			c.topic, c.partition, c.lagSeconds, c.messageLag, c.replicated, c.errors))
+	}
+	
+	return rows
+}
+
 func GetJobControlFunctions() map[string]interface{} {
 	return map[string]interface{}{
 		"startJob":   nil, // These will be injected from main.go
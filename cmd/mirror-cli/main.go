@@ -12,19 +12,25 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"kaf-mirror/cmd/mirror-cli/dashboard"
 	"kaf-mirror/cmd/mirror-cli/dashboard/core"
 	"kaf-mirror/pkg/utils"
 	"log"
+	"mime"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 	"time"
@@ -936,6 +942,18 @@ It interacts with the kaf-mirror API to perform various tasks.`,
 				"brokers":  brokers,
 			}
 
+			var schemaRegistryURL, schemaRegistryUser, schemaRegistryPassword string
+			survey.AskOne(&survey.Input{Message: "Schema Registry URL (optional, for schema ID remapping):"}, &schemaRegistryURL)
+			if schemaRegistryURL != "" {
+				survey.AskOne(&survey.Input{Message: "Schema Registry username (optional):"}, &schemaRegistryUser)
+				if schemaRegistryUser != "" {
+					survey.AskOne(&survey.Password{Message: "Schema Registry password:"}, &schemaRegistryPassword)
+				}
+				clusterRequest["schema_registry_url"] = schemaRegistryURL
+				clusterRequest["schema_registry_username"] = schemaRegistryUser
+				clusterRequest["schema_registry_password"] = schemaRegistryPassword
+			}
+
 			if provider == "confluent" {
 				clusterRequest["cluster_id"] = clusterID
 				clusterRequest["api_key"] = apiKey
@@ -1595,7 +1613,11 @@ It interacts with the kaf-mirror API to perform various tasks.`,
 
 	jobsCmd := createJobsCommand()
 	docsCmd := createDocsCommand()
-	rootCmd.AddCommand(loginCmd, logoutCmd, usersCmd, clustersCmd, jobsCmd, configCmd, tlsCmd, newDashboardCmd(), whoamiCmd, docsCmd)
+	tokensCmd := createTokensCommand()
+	complianceCmd := createComplianceCommand()
+	alertsCmd := createAlertsCommand()
+	webhooksCmd := createWebhooksCommand()
+	rootCmd.AddCommand(loginCmd, logoutCmd, usersCmd, clustersCmd, jobsCmd, tokensCmd, complianceCmd, alertsCmd, webhooksCmd, configCmd, tlsCmd, newDashboardCmd(), whoamiCmd, docsCmd)
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
 	return rootCmd
@@ -2439,10 +2461,13 @@ func runDashboard(cmd *cobra.Command, args []string) {
 		FetchCluster:     func(clusterName string) (map[string]interface{}, error) { return fetchCluster(token, clusterName) },
 		FetchJobMappings: func(jobID string) ([]map[string]interface{}, error) { return fetchJobMappings(token, jobID) },
 		FetchMetrics:     func(jobID string) (map[string]interface{}, error) { return fetchMetrics(token, jobID) },
+		FetchPartitions:  func(jobID string) (map[string]interface{}, error) { return fetchPartitionMetrics(token, jobID) },
+		FetchJobLogs:     func(jobID string) ([]map[string]interface{}, error) { return fetchJobLogs(token, jobID) },
 	}
 
 	// Create and run the new hierarchical dashboard
 	d := dashboard.NewDashboard(token, fetchers)
+	d.EnableLiveUpdates(BackendURL, token)
 	if err := d.Run(); err != nil {
 		log.Fatalf("Dashboard error: %v", err)
 	}
@@ -2490,6 +2515,35 @@ func fetchMetrics(token, jobID string) (map[string]interface{}, error) {
 	return metrics, nil
 }
 
+func fetchPartitionMetrics(token, jobID string) (map[string]interface{}, error) {
+	req, _ := http.NewRequest("GET", fmt.Sprintf("%s/api/v1/jobs/%s/metrics/partitions?series=false", BackendURL, jobID), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("server returned %s", resp.Status)
+	}
+	var partitions map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&partitions); err != nil {
+		return nil, err
+	}
+	return partitions, nil
+}
+
+// fetchJobLogs returns the latest log records buffered by the server for one job.
+func fetchJobLogs(token, jobID string) ([]map[string]interface{}, error) {
+	var resp struct {
+		Logs []map[string]interface{} `json:"logs"`
+	}
+	if err := apiRequest(token, "GET", "/api/v1/jobs/"+url.PathEscape(jobID)+"/logs?limit=20", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Logs, nil
+}
+
 func fetchLogs(token string) ([]map[string]interface{}, error) {
 	req, _ := http.NewRequest("GET", fmt.Sprintf("%s/api/v1/events", BackendURL), nil)
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -3196,6 +3250,24 @@ func createReplicationJob(token string) {
 	}
 	survey.AskOne(promptPartitions, &preservePartitions)
 
+	var startMode string
+	promptStart := &survey.Select{
+		Message: "Start new partitions from:",
+		Options: []string{"earliest", "latest", "timestamp"},
+		Default: "earliest",
+		Help:    "Only applies to partitions the job has not consumed yet. Use 'mirror-cli jobs reset-offsets' to move an existing job.",
+	}
+	survey.AskOne(promptStart, &startMode)
+
+	var startTimestamp string
+	if startMode == "timestamp" {
+		promptTimestamp := &survey.Input{
+			Message: "Start from (RFC 3339 time, or a duration back such as 7d or 12h):",
+			Default: "7d",
+		}
+		survey.AskOne(promptTimestamp, &startTimestamp, survey.WithValidator(survey.Required))
+	}
+
 	// Topic mappings with custom target names option
 	fmt.Println("\n=== Topic Mapping Configuration ===")
 	var mappings []map[string]interface{}
@@ -3237,6 +3309,9 @@ func createReplicationJob(token string) {
 		"compression":         compression,
 		"preserve_partitions": preservePartitions,
 	}
+	if startMode != "earliest" {
+		jobRequest["start_position"] = map[string]interface{}{"mode": startMode, "timestamp": startTimestamp}
+	}
 
 	fmt.Println("\n=== Job Summary ===")
 	fmt.Printf("Name: %s\n", jobName)
@@ -3247,6 +3322,11 @@ func createReplicationJob(token string) {
 	fmt.Printf("Parallelism: %d\n", parallelism)
 	fmt.Printf("Compression: %s\n", compression)
 	fmt.Printf("Preserve Partitions: %t\n", preservePartitions)
+	if startTimestamp != "" {
+		fmt.Printf("Start Position: %s %s\n", startMode, startTimestamp)
+	} else {
+		fmt.Printf("Start Position: %s\n", startMode)
+	}
 
 	var confirm bool
 	promptConfirm := &survey.Confirm{
@@ -3915,6 +3995,9 @@ func createJobsCommand() *cobra.Command {
 				if lag, ok := metrics["current_lag"]; ok {
 					fmt.Printf("Current Lag: %.0f messages\n", lag)
 				}
+				if p99, ok := metrics["lag_seconds_p99"].(float64); ok {
+					fmt.Printf("Time Lag: p50 %.1fs, p99 %.1fs, max %.1fs behind source\n", metrics["lag_seconds_p50"], p99, metrics["lag_seconds_max"])
+				}
 				if throughput, ok := metrics["messages_replicated"]; ok {
 					fmt.Printf("Messages/sec: %.0f\n", throughput)
 				}
@@ -4256,7 +4339,7 @@ func createJobsCommand() *cobra.Command {
 		},
 	}
 
-	jobsCmd.AddCommand(listJobsCmd, addJobCmd, startJobCmd, stopJobCmd, pauseJobCmd, restartJobCmd, forceRestartJobCmd, deleteJobCmd, statusJobCmd, analyzeJobCmd, healthcheckJobCmd)
+	jobsCmd.AddCommand(listJobsCmd, addJobCmd, startJobCmd, stopJobCmd, pauseJobCmd, restartJobCmd, forceRestartJobCmd, deleteJobCmd, statusJobCmd, analyzeJobCmd, healthcheckJobCmd, createJobSLOCommand(), createJobLogsCommand(), createJobTopicConfigsCommand(), createJobACLSyncCommand(), createJobFailoverCommand(), createJobResetOffsetsCommand(), createJobBackfillCommand(), createJobVerifyCommand(), createJobDLQCommand())
 	return jobsCmd
 }
 
@@ -6740,3 +6823,2345 @@ func fetchJobMappings(token, jobID string) ([]map[string]interface{}, error) {
 	json.NewDecoder(resp.Body).Decode(&mappings)
 	return mappings, nil
 }
+
+// apiRequest sends an authenticated JSON request to the backend and decodes the response into out.
+// Non-2xx responses are returned as errors including the server's error message.
+func apiRequest(token, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %v", err)
+		}
+		reader = bytes.NewBuffer(payload)
+	}
+
+	req, err := http.NewRequest(method, BackendURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to build request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect to backend: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return responseError(resp)
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse response: %v", err)
+	}
+	return nil
+}
+
+// responseError converts a non-2xx response into an error including the server's error message.
+func responseError(resp *http.Response) error {
+	respBody, _ := io.ReadAll(resp.Body)
+	message := strings.TrimSpace(string(respBody))
+	var errResp map[string]interface{}
+	if json.Unmarshal(respBody, &errResp) == nil {
+		if msg, ok := errResp["error"].(string); ok {
+			message = msg
+		}
+	}
+	if message == "" {
+		return fmt.Errorf("server returned %s", resp.Status)
+	}
+	return fmt.Errorf("server returned %s: %s", resp.Status, message)
+}
+
+// apiDownload fetches a file from the backend and returns its content and the server-suggested filename.
+func apiDownload(token, path string) ([]byte, string, error) {
+	req, err := http.NewRequest("GET", BackendURL+path, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to build request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to connect to backend: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, "", responseError(resp)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read response: %v", err)
+	}
+	filename := ""
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		filename = filepath.Base(params["filename"])
+	}
+	return data, filename, nil
+}
+
+type apiTokenInfo struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Permissions []string   `json:"permissions"`
+	JobIDs      []string   `json:"job_ids"`
+	AllowedIPs  []string   `json:"allowed_ips"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	LastUsedIP  string     `json:"last_used_ip"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func createTokensCommand() *cobra.Command {
+	tokensCmd := &cobra.Command{
+		Use:   "tokens",
+		Short: "Manage named API tokens.",
+		Long: `The tokens command manages named API tokens for automation.
+Tokens can be limited to a subset of your permissions, to specific jobs and to an IP allowlist.`,
+	}
+
+	createTokenCmd := &cobra.Command{
+		Use:   "create [name]",
+		Short: "Create a named, scoped API token.",
+		Long: `This command creates a named API token. The token value is printed once and cannot be retrieved later.
+Without --permissions the token inherits all permissions of your role.`,
+		Example: `  mirror-cli tokens create ci-deploy --permissions jobs:view,jobs:start --jobs <job-id> --allow-ip 10.0.0.0/8 --expires-in 2160h`,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token, err := LoadToken()
+			if err != nil {
+				fmt.Println("Error: You must be logged in to perform this action. Please run 'mirror-cli login'.")
+				return
+			}
+
+			description, _ := cmd.Flags().GetString("description")
+			permissions, _ := cmd.Flags().GetStringSlice("permissions")
+			jobIDs, _ := cmd.Flags().GetStringSlice("jobs")
+			allowedIPs, _ := cmd.Flags().GetStringSlice("allow-ip")
+			expiresIn, _ := cmd.Flags().GetDuration("expires-in")
+
+			reqBody := map[string]interface{}{
+				"name":        args[0],
+				"description": description,
+				"permissions": permissions,
+				"job_ids":     jobIDs,
+				"allowed_ips": allowedIPs,
+			}
+			if expiresIn > 0 {
+				reqBody["expires_in"] = expiresIn.String()
+			}
+
+			var created struct {
+				Token    string       `json:"token"`
+				ApiToken apiTokenInfo `json:"api_token"`
+			}
+			if err := apiRequest(token, "POST", "/api/v1/tokens", reqBody, &created); err != nil {
+				fmt.Printf("Error: Failed to create token: %v\n", err)
+				return
+			}
+
+			fmt.Println("=================================================================")
+			fmt.Println("  API TOKEN CREATED")
+			fmt.Println("=================================================================")
+			fmt.Printf("  Name:        %s (ID: %d)\n", created.ApiToken.Name, created.ApiToken.ID)
+			fmt.Printf("  Permissions: %s\n", listOrDefault(created.ApiToken.Permissions, "all role permissions"))
+			fmt.Printf("  Jobs:        %s\n", listOrDefault(created.ApiToken.JobIDs, "all jobs"))
+			fmt.Printf("  Allowed IPs: %s\n", listOrDefault(created.ApiToken.AllowedIPs, "any"))
+			fmt.Printf("  Expires:     %s\n", created.ApiToken.ExpiresAt.Format(time.RFC3339))
+			fmt.Println()
+			fmt.Printf("  Token: %s\n", created.Token)
+			fmt.Println("=================================================================")
+			fmt.Println("  This token will only be shown once. Store it in a secure location.")
+			fmt.Println("=================================================================")
+		},
+	}
+	createTokenCmd.Flags().String("description", "", "Description of the token's purpose")
+	createTokenCmd.Flags().StringSlice("permissions", nil, "Comma-separated permission subset, e.g. jobs:view,jobs:start")
+	createTokenCmd.Flags().StringSlice("jobs", nil, "Comma-separated job IDs the token is limited to")
+	createTokenCmd.Flags().StringSlice("allow-ip", nil, "Comma-separated IP addresses or CIDRs allowed to use the token")
+	createTokenCmd.Flags().Duration("expires-in", 0, "Token lifetime, e.g. 720h (default 90 days)")
+
+	listTokensCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List your API tokens.",
+		Long:  `This command lists your API tokens with their scope, expiry and last use. Admins can list another user's tokens with --user.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			token, err := LoadToken()
+			if err != nil {
+				fmt.Println("Error: You must be logged in to perform this action. Please run 'mirror-cli login'.")
+				return
+			}
+
+			path := "/api/v1/tokens"
+			if username, _ := cmd.Flags().GetString("user"); username != "" {
+				path += "?username=" + url.QueryEscape(username)
+			}
+
+			var tokens []apiTokenInfo
+			if err := apiRequest(token, "GET", path, nil, &tokens); err != nil {
+				fmt.Printf("Error: Failed to list tokens: %v\n", err)
+				return
+			}
+
+			if len(tokens) == 0 {
+				fmt.Println("No tokens found.")
+				return
+			}
+
+			w := new(bytes.Buffer)
+			writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(writer, "ID\tNAME\tPERMISSIONS\tJOBS\tALLOWED IPS\tEXPIRES\tLAST USED")
+			for _, t := range tokens {
+				name := t.Name
+				if name == "" {
+					name = "(" + t.Description + ")"
+				}
+				lastUsed := "never"
+				if t.LastUsedAt != nil {
+					lastUsed = fmt.Sprintf("%s from %s", formatLastActive(*t.LastUsedAt), t.LastUsedIP)
+				}
+				fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", t.ID, name,
+					listOrDefault(t.Permissions, "all"), listOrDefault(t.JobIDs, "all"), listOrDefault(t.AllowedIPs, "any"),
+					t.ExpiresAt.Format("2006-01-02 15:04"), lastUsed)
+			}
+			writer.Flush()
+			fmt.Println(w.String())
+		},
+	}
+	listTokensCmd.Flags().String("user", "", "List tokens of another user (requires users:list)")
+
+	revokeTokenCmd := &cobra.Command{
+		Use:   "revoke [token-id]",
+		Short: "Revoke a single API token.",
+		Long:  `This command revokes one API token by ID. Other tokens of the user stay valid.`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token, err := LoadToken()
+			if err != nil {
+				fmt.Println("Error: You must be logged in to perform this action. Please run 'mirror-cli login'.")
+				return
+			}
+
+			if err := apiRequest(token, "DELETE", "/api/v1/tokens/"+args[0], nil, nil); err != nil {
+				fmt.Printf("Error: Failed to revoke token: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Token %s revoked.\n", args[0])
+		},
+	}
+
+	tokensCmd.AddCommand(createTokenCmd, listTokensCmd, revokeTokenCmd)
+	return tokensCmd
+}
+
+func listOrDefault(values []string, fallback string) string {
+	if len(values) == 0 {
+		return fallback
+	}
+	return strings.Join(values, ",")
+}
+
+type complianceReportInfo struct {
+	ID          int       `json:"id"`
+	Period      string    `json:"period"`
+	StartDate   time.Time `json:"start_date"`
+	EndDate     time.Time `json:"end_date"`
+	GeneratedBy int       `json:"generated_by"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+func createComplianceCommand() *cobra.Command {
+	complianceCmd := &cobra.Command{
+		Use:   "compliance",
+		Short: "List and export compliance reports.",
+		Long:  `The compliance command lists generated compliance reports and exports them as JSON, CSV, HTML, PDF or as a zip evidence bundle.`,
+	}
+
+	listReportsCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List generated compliance reports.",
+		Run: func(cmd *cobra.Command, args []string) {
+			token, err := LoadToken()
+			if err != nil {
+				fmt.Println("Error: You must be logged in to perform this action. Please run 'mirror-cli login'.")
+				return
+			}
+
+			var reports []complianceReportInfo
+			if err := apiRequest(token, "GET", "/api/v1/compliance/reports", nil, &reports); err != nil {
+				fmt.Printf("Error: Failed to list compliance reports: %v\n", err)
+				os.Exit(1)
+			}
+			if len(reports) == 0 {
+				fmt.Println("No compliance reports found.")
+				return
+			}
+
+			w := new(bytes.Buffer)
+			writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(writer, "ID\tPERIOD\tFROM\tTO\tGENERATED")
+			for _, r := range reports {
+				fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\n", r.ID, r.Period,
+					r.StartDate.Format("2006-01-02"), r.EndDate.Format("2006-01-02"), r.GeneratedAt.Format("2006-01-02 15:04"))
+			}
+			writer.Flush()
+			fmt.Println(w.String())
+		},
+	}
+
+	exportReportCmd := &cobra.Command{
+		Use:   "export [report-id]",
+		Short: "Export a compliance report or evidence bundle.",
+		Long: `This command downloads a compliance report. The default format is a zip evidence bundle containing
+the report (JSON, HTML, PDF, CSV), the operational events and inventory snapshots of the period,
+the audit chain verification and a manifest with SHA-256 checksums.`,
+		Example: `  mirror-cli compliance export 12
+  mirror-cli compliance export 12 --format pdf --output report.pdf`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token, err := LoadToken()
+			if err != nil {
+				fmt.Println("Error: You must be logged in to perform this action. Please run 'mirror-cli login'.")
+				return
+			}
+
+			format, _ := cmd.Flags().GetString("format")
+			output, _ := cmd.Flags().GetString("output")
+
+			data, filename, err := apiDownload(token, fmt.Sprintf("/api/v1/compliance/report/%s/export?format=%s", url.PathEscape(args[0]), url.QueryEscape(format)))
+			if err != nil {
+				fmt.Printf("Error: Failed to export compliance report: %v\n", err)
+				os.Exit(1)
+			}
+			if output == "" {
+				output = filename
+			}
+			if output == "" {
+				output = fmt.Sprintf("compliance-report-%s.%s", args[0], format)
+			}
+			if err := os.WriteFile(output, data, 0600); err != nil {
+				fmt.Printf("Error: Failed to write %s: %v\n", output, err)
+				os.Exit(1)
+			}
+			fmt.Printf("Compliance report %s exported to %s (%d bytes)\n", args[0], output, len(data))
+		},
+	}
+	exportReportCmd.Flags().String("format", "bundle", "Export format: bundle, pdf, html, csv or json")
+	exportReportCmd.Flags().StringP("output", "o", "", "Output file (defaults to the server-provided filename)")
+
+	complianceCmd.AddCommand(listReportsCmd, exportReportCmd)
+	return complianceCmd
+}
+
+type alertInfo struct {
+	ID             int        `json:"id"`
+	RuleID         int        `json:"rule_id"`
+	RuleName       string     `json:"rule_name"`
+	RuleType       string     `json:"rule_type"`
+	JobID          string     `json:"job_id"`
+	Severity       string     `json:"severity"`
+	Status         string     `json:"status"`
+	Summary        string     `json:"summary"`
+	Silenced       bool       `json:"silenced"`
+	FiredAt        time.Time  `json:"fired_at"`
+	ResolvedAt     *time.Time `json:"resolved_at"`
+	AcknowledgedBy *string    `json:"acknowledged_by"`
+}
+
+type alertRuleInfo struct {
+	ID        int      `json:"id"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	JobID     string   `json:"job_id"`
+	Threshold float64  `json:"threshold"`
+	For       string   `json:"for"`
+	Severity  string   `json:"severity"`
+	Channels  []string `json:"channels"`
+	Enabled   bool     `json:"enabled"`
+}
+
+type alertSilenceInfo struct {
+	ID        int       `json:"id"`
+	JobID     string    `json:"job_id"`
+	RuleID    *int      `json:"rule_id"`
+	Reason    string    `json:"reason"`
+	CreatedBy string    `json:"created_by"`
+	EndsAt    time.Time `json:"ends_at"`
+}
+
+type webhookInfo struct {
+	ID         int      `json:"id"`
+	Name       string   `json:"name"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	JobID      string   `json:"job_id"`
+	Enabled    bool     `json:"enabled"`
+	CreatedBy  string   `json:"created_by"`
+	Secret     string   `json:"secret"`
+}
+
+type webhookDeliveryInfo struct {
+	ID             int        `json:"id"`
+	WebhookID      int        `json:"webhook_id"`
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	JobID          string     `json:"job_id"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	ResponseStatus *int       `json:"response_status"`
+	LastError      *string    `json:"last_error"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at"`
+	ReplayOf       *int       `json:"replay_of"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// jobSLOInfo is the CLI view of a job SLO.
+type jobSLOInfo struct {
+	ID          int     `json:"id"`
+	JobID       string  `json:"job_id"`
+	Name        string  `json:"name"`
+	Type        string  `json:"type"`
+	Objective   float64 `json:"objective"`
+	Threshold   float64 `json:"threshold"`
+	Description string  `json:"description"`
+	Enabled     bool    `json:"enabled"`
+}
+
+type jobSLOStatusInfo struct {
+	SLO     jobSLOInfo `json:"slo"`
+	Windows []struct {
+		Window                      string  `json:"window"`
+		Bad                         int     `json:"bad"`
+		AttainmentPercent           float64 `json:"attainment_percent"`
+		ErrorBudgetRemainingPercent float64 `json:"error_budget_remaining_percent"`
+		Met                         bool    `json:"met"`
+		NoData                      bool    `json:"no_data"`
+	} `json:"windows"`
+	BurnRate1h float64 `json:"burn_rate_1h"`
+	BurnRate5m float64 `json:"burn_rate_5m"`
+}
+
+func createJobSLOCommand() *cobra.Command {
+	sloCmd := &cobra.Command{
+		Use:   "slo",
+		Short: "Manage the service level objectives of a job.",
+		Long: `SLOs of type lag_seconds, message_lag and error_rate require a share (--objective, in percent) of
+1-minute windows to stay below --threshold. A gaps SLO allows at most --threshold unresolved gaps.
+Attainment and the remaining error budget are reported over rolling 7 and 30 day windows.`,
+	}
+
+	requireToken := func() string {
+		token, err := LoadToken()
+		if err != nil {
+			fmt.Println("Error: You must be logged in to perform this action. Please run 'mirror-cli login'.")
+			os.Exit(1)
+		}
+		return token
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list [job-id]",
+		Short: "Show the SLOs of a job with their attainment and error budget.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			var statuses []jobSLOStatusInfo
+			if err := apiRequest(token, "GET", "/api/v1/jobs/"+url.PathEscape(args[0])+"/slo", nil, &statuses); err != nil {
+				fmt.Printf("Error: Failed to list SLOs: %v\n", err)
+				os.Exit(1)
+			}
+			if len(statuses) == 0 {
+				fmt.Println("No SLOs defined for this job.")
+				return
+			}
+
+			w := new(bytes.Buffer)
+			writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(writer, "ID\tNAME\tTYPE\tOBJECTIVE\tTHRESHOLD\t7D\t30D\tBUDGET LEFT\tBURN 1H\tENABLED")
+			for _, st := range statuses {
+				attainment := make([]string, len(st.Windows))
+				for i, win := range st.Windows {
+					switch {
+					case win.NoData:
+						attainment[i] = "no data"
+					case st.SLO.Type == "gaps":
+						attainment[i] = fmt.Sprintf("%d gaps", win.Bad)
+					default:
+						attainment[i] = fmt.Sprintf("%.3f%%", win.AttainmentPercent)
+					}
+					if !win.Met {
+						attainment[i] += " (breached)"
+					}
+				}
+				for len(attainment) < 2 {
+					attainment = append(attainment, "-")
+				}
+				budget := "-"
+				if n := len(st.Windows); n > 0 {
+					budget = fmt.Sprintf("%.1f%%", st.Windows[n-1].ErrorBudgetRemainingPercent)
+				}
+				objective := fmt.Sprintf("%g%%", st.SLO.Objective)
+				if st.SLO.Type == "gaps" {
+					objective = "-"
+				}
+				fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%g\t%s\t%s\t%s\t%.2fx\t%t\n", st.SLO.ID, st.SLO.Name, st.SLO.Type,
+					objective, st.SLO.Threshold, attainment[0], attainment[1], budget, st.BurnRate1h, st.SLO.Enabled)
+			}
+			writer.Flush()
+			fmt.Println(w.String())
+		},
+	}
+
+	// sloRequestFromFlags only includes the flags the user set, so updates leave other fields untouched.
+	sloRequestFromFlags := func(cmd *cobra.Command) map[string]interface{} {
+		req := map[string]interface{}{}
+		flags := cmd.Flags()
+		for _, name := range []string{"name", "type", "description"} {
+			if flags.Changed(name) {
+				v, _ := flags.GetString(name)
+				req[name] = v
+			}
+		}
+		for _, name := range []string{"objective", "threshold"} {
+			if flags.Changed(name) {
+				v, _ := flags.GetFloat64(name)
+				req[name] = v
+			}
+		}
+		if flags.Changed("enabled") {
+			v, _ := flags.GetBool("enabled")
+			req["enabled"] = v
+		}
+		return req
+	}
+	addSLOFlags := func(cmd *cobra.Command) {
+		cmd.Flags().String("name", "", "SLO name")
+		cmd.Flags().String("type", "", "SLO type: lag_seconds, message_lag, error_rate or gaps")
+		cmd.Flags().Float64("objective", 99.9, "Percentage of 1-minute windows that must meet the threshold")
+		cmd.Flags().Float64("threshold", 0, "Threshold: seconds (lag_seconds), messages (message_lag), percent (error_rate) or allowed gaps")
+		cmd.Flags().String("description", "", "Description")
+		cmd.Flags().Bool("enabled", true, "Whether the SLO is evaluated")
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create [job-id]",
+		Short: "Create an SLO for a job.",
+		Example: `  mirror-cli jobs slo create 3f2a --name time-lag --type lag_seconds --objective 99.9 --threshold 60
+  mirror-cli jobs slo create 3f2a --name no-gaps --type gaps --threshold 0`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			var slo jobSLOInfo
+			if err := apiRequest(token, "POST", "/api/v1/jobs/"+url.PathEscape(args[0])+"/slo", sloRequestFromFlags(cmd), &slo); err != nil {
+				fmt.Printf("Error: Failed to create SLO: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("SLO '%s' created with ID %d.\n", slo.Name, slo.ID)
+		},
+	}
+	addSLOFlags(createCmd)
+	createCmd.MarkFlagRequired("name")
+	createCmd.MarkFlagRequired("type")
+
+	updateCmd := &cobra.Command{
+		Use:     "update [job-id] [slo-id]",
+		Short:   "Update an SLO of a job.",
+		Example: `  mirror-cli jobs slo update 3f2a 2 --threshold 30`,
+		Args:    cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			var slo jobSLOInfo
+			path := "/api/v1/jobs/" + url.PathEscape(args[0]) + "/slo/" + url.PathEscape(args[1])
+			if err := apiRequest(token, "PUT", path, sloRequestFromFlags(cmd), &slo); err != nil {
+				fmt.Printf("Error: Failed to update SLO: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("SLO '%s' updated.\n", slo.Name)
+		},
+	}
+	addSLOFlags(updateCmd)
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete [job-id] [slo-id]",
+		Short: "Delete an SLO of a job.",
+		Args:  cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			path := "/api/v1/jobs/" + url.PathEscape(args[0]) + "/slo/" + url.PathEscape(args[1])
+			if err := apiRequest(token, "DELETE", path, nil, nil); err != nil {
+				fmt.Printf("Error: Failed to delete SLO: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("SLO %s deleted.\n", args[1])
+		},
+	}
+
+	sloCmd.AddCommand(listCmd, createCmd, updateCmd, deleteCmd)
+	return sloCmd
+}
+
+// topicConfigReportInfo is the CLI view of a topic config drift report.
+type topicConfigReportInfo struct {
+	JobID          string `json:"job_id"`
+	Mode           string `json:"mode"`
+	DryRun         bool   `json:"dry_run"`
+	DriftedTopics  int    `json:"drifted_topics"`
+	DriftedConfigs int    `json:"drifted_configs"`
+	AppliedTopics  int    `json:"applied_topics"`
+	Topics         []struct {
+		SourceTopic string `json:"source_topic"`
+		TargetTopic string `json:"target_topic"`
+		Drift       []struct {
+			Key          string `json:"key"`
+			SourceValue  string `json:"source_value"`
+			TargetValue  string `json:"target_value"`
+			DesiredValue string `json:"desired_value"`
+			Override     bool   `json:"override"`
+		} `json:"drift"`
+		Applied bool   `json:"applied"`
+		Error   string `json:"error"`
+	} `json:"topics"`
+}
+
+type topicConfigOverrideInfo struct {
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	UpdatedBy string    `json:"updated_by"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func printTopicConfigReport(report topicConfigReportInfo) {
+	w := new(bytes.Buffer)
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "TARGET TOPIC\tCONFIG\tTARGET\tDESIRED\tSOURCE\tSTATUS")
+	rows := 0
+	for _, topic := range report.Topics {
+		if topic.Error != "" && len(topic.Drift) == 0 {
+			rows++
+			fmt.Fprintf(writer, "%s\t-\t-\t-\t-\terror: %s\n", topic.TargetTopic, topic.Error)
+			continue
+		}
+		for _, d := range topic.Drift {
+			status := "drifted"
+			switch {
+			case topic.Error != "":
+				status = "error: " + topic.Error
+			case topic.Applied:
+				status = "applied"
+			}
+			desired := d.DesiredValue
+			if d.Override {
+				desired += " (override)"
+			}
+			rows++
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\n", topic.TargetTopic, d.Key, d.TargetValue, desired, d.SourceValue, status)
+		}
+	}
+	writer.Flush()
+	if rows > 0 {
+		fmt.Println(w.String())
+	}
+	fmt.Printf("%d of %d topics drifted (%d configs), %d updated. Mode: %s", report.DriftedTopics, len(report.Topics), report.DriftedConfigs, report.AppliedTopics, report.Mode)
+	if report.DryRun {
+		fmt.Print(", dry run")
+	}
+	fmt.Println(".")
+}
+
+func createJobTopicConfigsCommand() *cobra.Command {
+	topicConfigsCmd := &cobra.Command{
+		Use:   "topic-configs",
+		Short: "Compare and sync the topic configs of a job between source and target.",
+		Long: `Target topics are created with the source topic configs that pass the include/exclude lists of
+replication.topic_config_sync, and are periodically checked for drift. Per-job overrides win over the
+copied values, e.g. a longer retention.ms on a disaster recovery cluster.`,
+	}
+
+	requireToken := func() string {
+		token, err := LoadToken()
+		if err != nil {
+			fmt.Println("Error: You must be logged in to perform this action. Please run 'mirror-cli login'.")
+			os.Exit(1)
+		}
+		return token
+	}
+
+	driftCmd := &cobra.Command{
+		Use:   "drift [job-id]",
+		Short: "Show target topic configs that differ from the desired values.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			var report topicConfigReportInfo
+			if err := apiRequest(token, "GET", "/api/v1/jobs/"+url.PathEscape(args[0])+"/topic-configs/drift", nil, &report); err != nil {
+				fmt.Printf("Error: Failed to check topic config drift: %v\n", err)
+				os.Exit(1)
+			}
+			printTopicConfigReport(report)
+		},
+	}
+
+	syncCmd := &cobra.Command{
+		Use:   "sync [job-id]",
+		Short: "Set drifted target topic configs to the desired values.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			path := "/api/v1/jobs/" + url.PathEscape(args[0]) + "/topic-configs/sync"
+			if dryRun {
+				path += "?dry_run=true"
+			}
+			var report topicConfigReportInfo
+			if err := apiRequest(token, "POST", path, nil, &report); err != nil {
+				fmt.Printf("Error: Failed to sync topic configs: %v\n", err)
+				os.Exit(1)
+			}
+			printTopicConfigReport(report)
+		},
+	}
+	syncCmd.Flags().Bool("dry-run", false, "Let the target cluster validate the changes without applying them")
+
+	overridesCmd := &cobra.Command{
+		Use:   "overrides",
+		Short: "Manage the per-job topic config overrides.",
+	}
+
+	listOverridesCmd := &cobra.Command{
+		Use:   "list [job-id]",
+		Short: "List the topic config overrides of a job.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			var overrides []topicConfigOverrideInfo
+			if err := apiRequest(token, "GET", "/api/v1/jobs/"+url.PathEscape(args[0])+"/topic-configs/overrides", nil, &overrides); err != nil {
+				fmt.Printf("Error: Failed to list topic config overrides: %v\n", err)
+				os.Exit(1)
+			}
+			if len(overrides) == 0 {
+				fmt.Println("No topic config overrides defined for this job.")
+				return
+			}
+			w := new(bytes.Buffer)
+			writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(writer, "CONFIG\tVALUE\tUPDATED BY\tUPDATED AT")
+			for _, o := range overrides {
+				fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", o.Key, o.Value, o.UpdatedBy, o.UpdatedAt.Local().Format("2006-01-02 15:04:05"))
+			}
+			writer.Flush()
+			fmt.Println(w.String())
+		},
+	}
+
+	setOverridesCmd := &cobra.Command{
+		Use:     "set [job-id] [key=value]...",
+		Short:   "Replace the topic config overrides of a job.",
+		Example: `  mirror-cli jobs topic-configs overrides set 3f2a retention.ms=1209600000 min.insync.replicas=2`,
+		Args:    cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			overrides := map[string]string{}
+			for _, pair := range args[1:] {
+				key, value, ok := strings.Cut(pair, "=")
+				if !ok || strings.TrimSpace(key) == "" {
+					fmt.Printf("Error: Invalid override %q, expected key=value\n", pair)
+					os.Exit(1)
+				}
+				overrides[strings.TrimSpace(key)] = value
+			}
+			body := map[string]interface{}{"overrides": overrides}
+			if err := apiRequest(token, "PUT", "/api/v1/jobs/"+url.PathEscape(args[0])+"/topic-configs/overrides", body, nil); err != nil {
+				fmt.Printf("Error: Failed to update topic config overrides: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("%d topic config overrides set. They apply on the next sync or job restart.\n", len(overrides))
+		},
+	}
+
+	clearOverridesCmd := &cobra.Command{
+		Use:   "clear [job-id]",
+		Short: "Remove every topic config override of a job.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			body := map[string]interface{}{"overrides": map[string]string{}}
+			if err := apiRequest(token, "PUT", "/api/v1/jobs/"+url.PathEscape(args[0])+"/topic-configs/overrides", body, nil); err != nil {
+				fmt.Printf("Error: Failed to clear topic config overrides: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println("Topic config overrides cleared.")
+		},
+	}
+
+	overridesCmd.AddCommand(listOverridesCmd, setOverridesCmd, clearOverridesCmd)
+	topicConfigsCmd.AddCommand(driftCmd, syncCmd, overridesCmd)
+	return topicConfigsCmd
+}
+
+type aclSyncEntryInfo struct {
+	Principal    string `json:"principal"`
+	Host         string `json:"host"`
+	ResourceType string `json:"resource_type"`
+	ResourceName string `json:"resource_name"`
+	PatternType  string `json:"pattern_type"`
+	Operation    string `json:"operation"`
+	Permission   string `json:"permission"`
+}
+
+type aclSyncReportInfo struct {
+	DryRun     bool               `json:"dry_run"`
+	SourceACLs int                `json:"source_acls"`
+	Missing    []aclSyncEntryInfo `json:"missing"`
+	Skipped    []struct {
+		ACL    aclSyncEntryInfo `json:"acl"`
+		Reason string           `json:"reason"`
+	} `json:"skipped"`
+	CreatedACLs  int  `json:"created_acls"`
+	QuotasSynced bool `json:"quotas_synced"`
+	QuotaDrift   []struct {
+		Entity      map[string]string `json:"entity"`
+		Key         string            `json:"key"`
+		SourceValue float64           `json:"source_value"`
+		TargetValue *float64          `json:"target_value"`
+	} `json:"quota_drift"`
+	AppliedQuotas int      `json:"applied_quotas"`
+	Errors        []string `json:"errors"`
+}
+
+func printACLSyncReport(report aclSyncReportInfo) {
+	status := "created"
+	if report.DryRun {
+		status = "missing"
+	}
+
+	if len(report.Missing) > 0 || len(report.Skipped) > 0 {
+		w := new(bytes.Buffer)
+		writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "PRINCIPAL\tPERMISSION\tOPERATION\tRESOURCE\tPATTERN\tHOST\tSTATUS")
+		for _, acl := range report.Missing {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s:%s\t%s\t%s\t%s\n", acl.Principal, acl.Permission, acl.Operation, acl.ResourceType, acl.ResourceName, acl.PatternType, acl.Host, status)
+		}
+		for _, s := range report.Skipped {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s:%s\t%s\t%s\tskipped: %s\n", s.ACL.Principal, s.ACL.Permission, s.ACL.Operation, s.ACL.ResourceType, s.ACL.ResourceName, s.ACL.PatternType, s.ACL.Host, s.Reason)
+		}
+		writer.Flush()
+		fmt.Println(w.String())
+	}
+
+	if len(report.QuotaDrift) > 0 {
+		w := new(bytes.Buffer)
+		writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(writer, "ENTITY\tQUOTA\tTARGET\tSOURCE")
+		for _, d := range report.QuotaDrift {
+			parts := make([]string, 0, len(d.Entity))
+			for entityType, name := range d.Entity {
+				if name == "" {
+					name = "<default>"
+				}
+				parts = append(parts, entityType+"="+name)
+			}
+			sort.Strings(parts)
+			target := "-"
+			if d.TargetValue != nil {
+				target = strconv.FormatFloat(*d.TargetValue, 'f', -1, 64)
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", strings.Join(parts, ","), d.Key, target, strconv.FormatFloat(d.SourceValue, 'f', -1, 64))
+		}
+		writer.Flush()
+		fmt.Println(w.String())
+	}
+
+	for _, e := range report.Errors {
+		fmt.Printf("Error: %s\n", e)
+	}
+	fmt.Printf("%d source ACLs apply to this job: %d missing on target, %d created, %d skipped.", report.SourceACLs, len(report.Missing), report.CreatedACLs, len(report.Skipped))
+	if report.QuotasSynced {
+		fmt.Printf(" %d client quota values differ, %d entities updated.", len(report.QuotaDrift), report.AppliedQuotas)
+	}
+	if report.DryRun {
+		fmt.Print(" Dry run, nothing was changed.")
+	}
+	fmt.Println()
+}
+
+func createJobACLSyncCommand() *cobra.Command {
+	aclSyncCmd := &cobra.Command{
+		Use:   "acl-sync [job-id]",
+		Short: "Copy the ACLs of a job's topics and consumer groups to the target cluster.",
+		Long: `Read the source cluster ACLs on the job's topics and consumer groups, rewrite topic names through the job's
+mappings and principals through replication.acl_sync.principal_rewrites, and create the ones missing on the target.
+Client quotas are copied as well when replication.acl_sync.sync_quotas is enabled. ACLs and quotas that exist only
+on the target are kept.`,
+		Example: `  mirror-cli jobs acl-sync 3f2a --dry-run`,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token, err := LoadToken()
+			if err != nil {
+				fmt.Println("Error: You must be logged in to perform this action. Please run 'mirror-cli login'.")
+				os.Exit(1)
+			}
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			path := "/api/v1/jobs/" + url.PathEscape(args[0]) + "/acl-sync"
+			if dryRun {
+				path += "?dry_run=true"
+			}
+			var report aclSyncReportInfo
+			if err := apiRequest(token, "POST", path, nil, &report); err != nil {
+				fmt.Printf("Error: Failed to sync ACLs: %v\n", err)
+				os.Exit(1)
+			}
+			printACLSyncReport(report)
+		},
+	}
+	aclSyncCmd.Flags().Bool("dry-run", false, "Only show what would be created")
+	return aclSyncCmd
+}
+
+// failoverInfo is a failover operation or dry-run report returned by /api/v1/jobs/{id}/failover.
+type failoverInfo struct {
+	ID           int     `json:"id"`
+	JobID        string  `json:"job_id"`
+	Status       string  `json:"status"`
+	DryRun       bool    `json:"dry_run"`
+	CheckpointID *int    `json:"checkpoint_id"`
+	ReverseJobID *string `json:"reverse_job_id"`
+	LastError    *string `json:"last_error"`
+	Steps        []struct {
+		Name    string `json:"name"`
+		Status  string `json:"status"`
+		Details string `json:"details"`
+		Error   string `json:"error"`
+	} `json:"steps"`
+}
+
+func printFailover(op failoverInfo) {
+	w := new(bytes.Buffer)
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "STEP\tSTATUS\tDETAILS")
+	for _, step := range op.Steps {
+		details := step.Details
+		if step.Error != "" {
+			if details != "" {
+				details += "; "
+			}
+			details += "error: " + step.Error
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", step.Name, step.Status, details)
+	}
+	writer.Flush()
+	fmt.Println(w.String())
+
+	if op.DryRun {
+		if op.LastError != nil {
+			fmt.Printf("Dry run: the failover would fail: %s\n", *op.LastError)
+		} else {
+			fmt.Println("Dry run: nothing was changed.")
+		}
+		return
+	}
+	fmt.Printf("Failover #%d of job %s is %s.", op.ID, op.JobID, op.Status)
+	if op.CheckpointID != nil {
+		fmt.Printf(" Checkpoint: %d.", *op.CheckpointID)
+	}
+	if op.ReverseJobID != nil {
+		fmt.Printf(" Failback job: %s (paused).", *op.ReverseJobID)
+	}
+	fmt.Println()
+	if op.LastError != nil {
+		fmt.Printf("Error: %s\n", *op.LastError)
+		fmt.Printf("Fix the cause and resume with: mirror-cli jobs failover %s --resume %d\n", op.JobID, op.ID)
+	}
+}
+
+func createJobFailoverCommand() *cobra.Command {
+	failoverCmd := &cobra.Command{
+		Use:   "failover [job-id]",
+		Short: "Fail a job over to its target cluster.",
+		Long: `Run a planned failover of a job: wait until the replication lag is zero, record a migration checkpoint,
+validate the mirror state, copy the committed offsets of consumer groups to the target cluster, stop the job and,
+with --reverse, create a paused job from the target back to the source that starts at the checkpoint for failback.
+Every step is recorded as an operational event. A failed failover can be resumed from the failing step with --resume.`,
+		Example: `  mirror-cli jobs failover 3f2a --dry-run
+  mirror-cli jobs failover 3f2a --reverse --groups 'billing-*' --drain-timeout 10m
+  mirror-cli jobs failover 3f2a --resume 4`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token, err := LoadToken()
+			if err != nil {
+				fmt.Println("Error: You must be logged in to perform this action. Please run 'mirror-cli login'.")
+				os.Exit(1)
+			}
+			jobPath := "/api/v1/jobs/" + url.PathEscape(args[0])
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			resume, _ := cmd.Flags().GetInt("resume")
+			wait, _ := cmd.Flags().GetBool("wait")
+
+			var op failoverInfo
+			if resume > 0 {
+				err = apiRequest(token, "POST", fmt.Sprintf("%s/failovers/%d/resume", jobPath, resume), nil, &op)
+			} else {
+				reverse, _ := cmd.Flags().GetBool("reverse")
+				groups, _ := cmd.Flags().GetStringSlice("groups")
+				drainTimeout, _ := cmd.Flags().GetString("drain-timeout")
+				reason, _ := cmd.Flags().GetString("reason")
+				body := map[string]interface{}{
+					"create_reverse_job": reverse,
+					"consumer_groups":    groups,
+					"drain_timeout":      drainTimeout,
+					"reason":             reason,
+				}
+				path := jobPath + "/failover"
+				if dryRun {
+					path += "?dry_run=true"
+				}
+				err = apiRequest(token, "POST", path, body, &op)
+			}
+			if err != nil {
+				fmt.Printf("Error: Failover failed: %v\n", err)
+				os.Exit(1)
+			}
+
+			if !op.DryRun && wait {
+				fmt.Printf("Failover #%d started, waiting for it to finish...\n", op.ID)
+				for op.Status == "running" {
+					time.Sleep(2 * time.Second)
+					if err := apiRequest(token, "GET", fmt.Sprintf("%s/failovers/%d", jobPath, op.ID), nil, &op); err != nil {
+						fmt.Printf("Error: Failed to get failover status: %v\n", err)
+						os.Exit(1)
+					}
+				}
+			}
+			printFailover(op)
+			if op.Status == "failed" {
+				os.Exit(1)
+			}
+		},
+	}
+	failoverCmd.Flags().Bool("dry-run", false, "Only report what each step would do")
+	failoverCmd.Flags().Bool("reverse", false, "Create a paused target to source job for failback")
+	failoverCmd.Flags().StringSlice("groups", nil, "Glob patterns of the consumer groups whose offsets are synced (default: all)")
+	failoverCmd.Flags().String("drain-timeout", "", "How long to wait for zero lag (default 5m)")
+	failoverCmd.Flags().String("reason", "", "Reason recorded with the checkpoint")
+	failoverCmd.Flags().Int("resume", 0, "Resume the failed failover with this ID")
+	failoverCmd.Flags().Bool("wait", true, "Wait until the failover has finished")
+	return failoverCmd
+}
+
+// offsetResetInfo is the preview or result returned by /api/v1/jobs/{id}/reset-offsets.
+type offsetResetInfo struct {
+	JobID         string `json:"job_id"`
+	ConsumerGroup string `json:"consumer_group"`
+	DryRun        bool   `json:"dry_run"`
+	Position      struct {
+		Mode string `json:"mode"`
+	} `json:"position"`
+	Partitions []struct {
+		Topic           string `json:"topic"`
+		Partition       int32  `json:"partition"`
+		CommittedOffset int64  `json:"committed_offset"`
+		Offset          int64  `json:"offset"`
+		LogStartOffset  int64  `json:"log_start_offset"`
+		HighWaterMark   int64  `json:"high_water_mark"`
+		Lag             int64  `json:"lag"`
+	} `json:"partitions"`
+}
+
+func printOffsetReset(reset offsetResetInfo) {
+	w := new(bytes.Buffer)
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "TOPIC\tPARTITION\tCOMMITTED\tNEW OFFSET\tLOG START\tHIGH WATER MARK\tLAG")
+	for _, p := range reset.Partitions {
+		committed := "-"
+		if p.CommittedOffset >= 0 {
+			committed = strconv.FormatInt(p.CommittedOffset, 10)
+		}
+		fmt.Fprintf(writer, "%s\t%d\t%s\t%d\t%d\t%d\t%d\n", p.Topic, p.Partition, committed, p.Offset, p.LogStartOffset, p.HighWaterMark, p.Lag)
+	}
+	writer.Flush()
+	fmt.Println(w.String())
+
+	if reset.DryRun {
+		fmt.Println("Dry run: nothing was changed.")
+		return
+	}
+	fmt.Printf("Committed %d offsets for consumer group %s. The job continues from them when it is started.\n", len(reset.Partitions), reset.ConsumerGroup)
+}
+
+// parsePartitionOffsets parses topic:partition=offset flags into offsets by topic and partition.
+func parsePartitionOffsets(values []string) (map[string]map[int32]int64, error) {
+	offsets := make(map[string]map[int32]int64)
+	for _, value := range values {
+		target, offsetStr, ok := strings.Cut(value, "=")
+		sep := strings.LastIndex(target, ":")
+		if !ok || sep <= 0 {
+			return nil, fmt.Errorf("invalid offset %q, expected topic:partition=offset", value)
+		}
+		partition, err := strconv.ParseInt(target[sep+1:], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid partition in %q", value)
+		}
+		offset, err := strconv.ParseInt(offsetStr, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid offset in %q", value)
+		}
+		topic := target[:sep]
+		if offsets[topic] == nil {
+			offsets[topic] = make(map[int32]int64)
+		}
+		offsets[topic][int32(partition)] = offset
+	}
+	return offsets, nil
+}
+
+func createJobResetOffsetsCommand() *cobra.Command {
+	resetCmd := &cobra.Command{
+		Use:   "reset-offsets [job-id]",
+		Short: "Move a stopped job to a new start position.",
+		Long: `Set the committed offsets of the job's source consumer group to the earliest or latest offsets, the first
+offsets at or after a timestamp, or explicit partition offsets. The new offsets are checked against the source
+high water marks and shown next to the current ones; use --dry-run to only see the preview. The job must be
+stopped to apply the reset and continues from the new offsets when it is started.`,
+		Example: `  mirror-cli jobs reset-offsets 3f2a --to timestamp --timestamp 7d --dry-run
+  mirror-cli jobs reset-offsets 3f2a --to latest --topics orders,payments
+  mirror-cli jobs reset-offsets 3f2a --offset orders:0=1500 --offset orders:1=1320`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token, err := LoadToken()
+			if err != nil {
+				fmt.Println("Error: You must be logged in to perform this action. Please run 'mirror-cli login'.")
+				os.Exit(1)
+			}
+			mode, _ := cmd.Flags().GetString("to")
+			timestamp, _ := cmd.Flags().GetString("timestamp")
+			offsetFlags, _ := cmd.Flags().GetStringArray("offset")
+			topics, _ := cmd.Flags().GetStringSlice("topics")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			offsets, err := parsePartitionOffsets(offsetFlags)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			if mode == "" {
+				switch {
+				case len(offsets) > 0:
+					mode = "offsets"
+				case timestamp != "":
+					mode = "timestamp"
+				default:
+					fmt.Println("Error: --to is required (earliest, latest, timestamp or offsets)")
+					os.Exit(1)
+				}
+			}
+			position := map[string]interface{}{"mode": mode}
+			if timestamp != "" {
+				position["timestamp"] = timestamp
+			}
+			if len(offsets) > 0 {
+				position["offsets"] = offsets
+			}
+			body := map[string]interface{}{"position": position, "topics": topics}
+
+			path := "/api/v1/jobs/" + url.PathEscape(args[0]) + "/reset-offsets"
+			if dryRun {
+				path += "?dry_run=true"
+			}
+			var reset offsetResetInfo
+			if err := apiRequest(token, "POST", path, body, &reset); err != nil {
+				fmt.Printf("Error: Failed to reset offsets: %v\n", err)
+				os.Exit(1)
+			}
+			printOffsetReset(reset)
+		},
+	}
+	resetCmd.Flags().String("to", "", "Start position: earliest, latest, timestamp or offsets")
+	resetCmd.Flags().String("timestamp", "", "RFC 3339 time or a duration back such as 7d, for --to timestamp")
+	resetCmd.Flags().StringArray("offset", nil, "Explicit offset as topic:partition=offset, repeatable")
+	resetCmd.Flags().StringSlice("topics", nil, "Source topics to reset (default: all mapped topics)")
+	resetCmd.Flags().Bool("dry-run", false, "Only show the new offsets")
+	return resetCmd
+}
+
+// backfillInfo is the progress of a backfill job returned by the API.
+type backfillInfo struct {
+	JobID           string  `json:"job_id"`
+	Name            string  `json:"name"`
+	Status          string  `json:"status"`
+	ProgressPercent float64 `json:"progress_percent"`
+	CopiedOffsets   int64   `json:"copied_offsets"`
+	TotalOffsets    int64   `json:"total_offsets"`
+	Ranges          []struct {
+		SourceTopic string  `json:"source_topic"`
+		PartitionID int32   `json:"partition_id"`
+		StartOffset int64   `json:"start_offset"`
+		EndOffset   int64   `json:"end_offset"`
+		NextOffset  int64   `json:"next_offset"`
+		GapIDs      []int   `json:"gap_ids"`
+		Status      string  `json:"status"`
+		LastError   *string `json:"last_error"`
+	} `json:"ranges"`
+}
+
+func printBackfill(status backfillInfo) {
+	w := new(bytes.Buffer)
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "TOPIC\tPARTITION\tSTART\tEND\tNEXT\tSTATUS\tGAPS")
+	for _, r := range status.Ranges {
+		gaps := "-"
+		if len(r.GapIDs) > 0 {
+			ids := make([]string, len(r.GapIDs))
+			for i, id := range r.GapIDs {
+				ids[i] = strconv.Itoa(id)
+			}
+			gaps = strings.Join(ids, ",")
+		}
+		state := r.Status
+		if r.LastError != nil {
+			state += " (" + *r.LastError + ")"
+		}
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%d\t%d\t%s\t%s\n", r.SourceTopic, r.PartitionID, r.StartOffset, r.EndOffset, r.NextOffset, state, gaps)
+	}
+	writer.Flush()
+	fmt.Println(w.String())
+	fmt.Printf("Backfill job %s (%s) is %s: %.1f%% (%d of %d offsets).\n", status.Name, status.JobID, status.Status,
+		status.ProgressPercent, status.CopiedOffsets, status.TotalOffsets)
+}
+
+// backfillPosition builds a start position from a mode, timestamp and offset flags.
+func backfillPosition(mode, timestamp string, offsetFlags []string) (map[string]interface{}, error) {
+	offsets, err := parsePartitionOffsets(offsetFlags)
+	if err != nil {
+		return nil, err
+	}
+	if mode == "" {
+		switch {
+		case len(offsets) > 0:
+			mode = "offsets"
+		case timestamp != "":
+			mode = "timestamp"
+		default:
+			return nil, nil
+		}
+	}
+	position := map[string]interface{}{"mode": mode}
+	if timestamp != "" {
+		position["timestamp"] = timestamp
+	}
+	if len(offsets) > 0 {
+		position["offsets"] = offsets
+	}
+	return position, nil
+}
+
+func createJobBackfillCommand() *cobra.Command {
+	backfillCmd := &cobra.Command{
+		Use:   "backfill",
+		Short: "Copy a bounded time or offset range of a job again.",
+		Long: `Backfill jobs copy bounded source partition ranges with the clusters, settings and mappings of an existing
+job. They read their ranges without a consumer group, stop on their own once every range is copied and resolve the
+mirror gaps they were created for.`,
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create [job-id]",
+		Short: "Create a backfill job for a time or offset range.",
+		Long: `Create a paused backfill job that copies the records of a job's topics between two positions. The start is
+the earliest offsets, the first offsets at or after a timestamp, or explicit partition offsets; the exclusive end
+is a timestamp, explicit offsets or the current latest offsets (the default).`,
+		Example: `  mirror-cli jobs backfill create 3f2a --from-timestamp 2025-06-01T00:00:00Z --to-timestamp 2025-06-02T00:00:00Z
+  mirror-cli jobs backfill create 3f2a --from earliest --topics orders --start
+  mirror-cli jobs backfill create 3f2a --from-offset orders:0=1500 --to-offset orders:0=2500`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token, err := LoadToken()
+			if err != nil {
+				fmt.Println("Error: You must be logged in to perform this action. Please run 'mirror-cli login'.")
+				os.Exit(1)
+			}
+			fromMode, _ := cmd.Flags().GetString("from")
+			fromTimestamp, _ := cmd.Flags().GetString("from-timestamp")
+			fromOffsets, _ := cmd.Flags().GetStringArray("from-offset")
+			toMode, _ := cmd.Flags().GetString("to")
+			toTimestamp, _ := cmd.Flags().GetString("to-timestamp")
+			toOffsets, _ := cmd.Flags().GetStringArray("to-offset")
+			topics, _ := cmd.Flags().GetStringSlice("topics")
+			name, _ := cmd.Flags().GetString("name")
+			start, _ := cmd.Flags().GetBool("start")
+
+			from, err := backfillPosition(fromMode, fromTimestamp, fromOffsets)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			if from == nil {
+				fmt.Println("Error: --from is required (earliest, timestamp or offsets)")
+				os.Exit(1)
+			}
+			to, err := backfillPosition(toMode, toTimestamp, toOffsets)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			body := map[string]interface{}{"name": name, "topics": topics, "from": from}
+			if to != nil {
+				body["to"] = to
+			}
+
+			path := "/api/v1/jobs/" + url.PathEscape(args[0]) + "/backfills"
+			if start {
+				path += "?start=true"
+			}
+			var status backfillInfo
+			if err := apiRequest(token, "POST", path, body, &status); err != nil {
+				fmt.Printf("Error: Failed to create backfill job: %v\n", err)
+				os.Exit(1)
+			}
+			printBackfill(status)
+		},
+	}
+	createCmd.Flags().String("from", "", "Backfill start: earliest, timestamp or offsets")
+	createCmd.Flags().String("from-timestamp", "", "RFC 3339 time or a duration back such as 7d, for --from timestamp")
+	createCmd.Flags().StringArray("from-offset", nil, "Start offset as topic:partition=offset, repeatable")
+	createCmd.Flags().String("to", "", "Backfill end: latest, timestamp or offsets (default latest)")
+	createCmd.Flags().String("to-timestamp", "", "RFC 3339 time or a duration back such as 1d, for --to timestamp")
+	createCmd.Flags().StringArray("to-offset", nil, "Exclusive end offset as topic:partition=offset, repeatable")
+	createCmd.Flags().StringSlice("topics", nil, "Source topics to backfill (default: all mapped topics)")
+	createCmd.Flags().String("name", "", "Name of the backfill job (default: <job>-backfill-<time>)")
+	createCmd.Flags().Bool("start", false, "Start the backfill job right away")
+
+	gapsCmd := &cobra.Command{
+		Use:   "gaps [job-id]",
+		Short: "Create a backfill job for the mirror gaps of a job.",
+		Long: `Create a paused backfill job that copies the source offsets of the job's unresolved mirror gaps again.
+Gaps of the same partition are merged into one range. The gaps are resolved once their range is copied.`,
+		Example: `  mirror-cli jobs backfill gaps 3f2a --start
+  mirror-cli jobs backfill gaps 3f2a --gap 12 --gap 13`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token, err := LoadToken()
+			if err != nil {
+				fmt.Println("Error: You must be logged in to perform this action. Please run 'mirror-cli login'.")
+				os.Exit(1)
+			}
+			gapIDs, _ := cmd.Flags().GetIntSlice("gap")
+			name, _ := cmd.Flags().GetString("name")
+			start, _ := cmd.Flags().GetBool("start")
+
+			path := "/api/v1/jobs/" + url.PathEscape(args[0]) + "/mirror/gaps/backfill"
+			if start {
+				path += "?start=true"
+			}
+			var status backfillInfo
+			body := map[string]interface{}{"name": name, "gap_ids": gapIDs}
+			if err := apiRequest(token, "POST", path, body, &status); err != nil {
+				fmt.Printf("Error: Failed to create backfill job: %v\n", err)
+				os.Exit(1)
+			}
+			printBackfill(status)
+		},
+	}
+	gapsCmd.Flags().IntSlice("gap", nil, "Mirror gap IDs to close (default: all unresolved gaps)")
+	gapsCmd.Flags().String("name", "", "Name of the backfill job (default: <job>-backfill-<time>)")
+	gapsCmd.Flags().Bool("start", false, "Start the backfill job right away")
+
+	statusCmd := &cobra.Command{
+		Use:   "status [backfill-job-id]",
+		Short: "Show the progress of a backfill job.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token, err := LoadToken()
+			if err != nil {
+				fmt.Println("Error: You must be logged in to perform this action. Please run 'mirror-cli login'.")
+				os.Exit(1)
+			}
+			wait, _ := cmd.Flags().GetBool("wait")
+			path := "/api/v1/jobs/" + url.PathEscape(args[0]) + "/backfill"
+
+			var status backfillInfo
+			if err := apiRequest(token, "GET", path, nil, &status); err != nil {
+				fmt.Printf("Error: Failed to get backfill status: %v\n", err)
+				os.Exit(1)
+			}
+			for wait && status.Status == "running" {
+				fmt.Printf("\r%.1f%% (%d of %d offsets)", status.ProgressPercent, status.CopiedOffsets, status.TotalOffsets)
+				time.Sleep(5 * time.Second)
+				if err := apiRequest(token, "GET", path, nil, &status); err != nil {
+					fmt.Printf("\nError: Failed to get backfill status: %v\n", err)
+					os.Exit(1)
+				}
+			}
+			if wait {
+				fmt.Println()
+			}
+			printBackfill(status)
+			if status.Status == "failed" {
+				os.Exit(1)
+			}
+		},
+	}
+	statusCmd.Flags().Bool("wait", false, "Wait until the backfill job is no longer running")
+
+	backfillCmd.AddCommand(createCmd, gapsCmd, statusCmd)
+	return backfillCmd
+}
+
+// verificationInfo is a content verification returned by the API.
+type verificationInfo struct {
+	ID                int     `json:"id"`
+	JobID             string  `json:"job_id"`
+	Status            string  `json:"status"`
+	SampleRate        float64 `json:"sample_rate"`
+	RecordsCompared   int64   `json:"records_compared"`
+	MismatchedRecords int64   `json:"mismatched_records"`
+	MissingRecords    int64   `json:"missing_records"`
+	DuplicateRecords  int64   `json:"duplicate_records"`
+	Consistent        bool    `json:"consistent"`
+	LastError         *string `json:"last_error"`
+	Partitions        []struct {
+		SourceTopic     string `json:"source_topic"`
+		Partition       int32  `json:"partition"`
+		StartOffset     int64  `json:"start_offset"`
+		EndOffset       int64  `json:"end_offset"`
+		OffsetDelta     int64  `json:"offset_delta"`
+		RecordsCompared int64  `json:"records_compared"`
+		Mismatched      int64  `json:"mismatched"`
+		Missing         int64  `json:"missing"`
+		Duplicates      int64  `json:"duplicates"`
+		Skipped         string `json:"skipped"`
+		Error           string `json:"error"`
+	} `json:"partitions"`
+}
+
+func printVerification(v verificationInfo) {
+	if v.Status == "failed" {
+		reason := ""
+		if v.LastError != nil {
+			reason = *v.LastError
+		}
+		fmt.Printf("Content verification #%d of job %s failed: %s\n", v.ID, v.JobID, reason)
+		return
+	}
+	w := new(bytes.Buffer)
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "TOPIC\tPARTITION\tRANGE\tDELTA\tCOMPARED\tMISMATCHED\tMISSING\tDUPLICATES\tNOTE")
+	for _, p := range v.Partitions {
+		note := p.Skipped
+		if p.Error != "" {
+			note = "error: " + p.Error
+		}
+		if note == "" {
+			note = "-"
+		}
+		fmt.Fprintf(writer, "%s\t%d\t%d-%d\t%d\t%d\t%d\t%d\t%d\t%s\n", p.SourceTopic, p.Partition, p.StartOffset, p.EndOffset,
+			p.OffsetDelta, p.RecordsCompared, p.Mismatched, p.Missing, p.Duplicates, note)
+	}
+	writer.Flush()
+	fmt.Println(w.String())
+	result := "source and target match"
+	if !v.Consistent {
+		result = fmt.Sprintf("%d mismatched, %d missing, %d duplicates", v.MismatchedRecords, v.MissingRecords, v.DuplicateRecords)
+	}
+	fmt.Printf("Content verification #%d of job %s is %s: %d records compared at sample rate %g, %s.\n",
+		v.ID, v.JobID, v.Status, v.RecordsCompared, v.SampleRate, result)
+}
+
+func createJobVerifyCommand() *cobra.Command {
+	verifyCmd := &cobra.Command{
+		Use:   "verify [job-id]",
+		Short: "Compare the records of a job on the source and target clusters.",
+		Long: `Compare the records of a job's topics on both clusters by hashing key, value, headers and timestamp, and
+report mismatched, missing and duplicate records per partition. Source offsets are translated to target offsets,
+so targets holding less history or shifted offsets are compared correctly. With --sample-rate only evenly spaced
+windows of each partition are compared. The command waits for the result and exits non-zero when the clusters differ.`,
+		Example: `  mirror-cli jobs verify 3f2a
+  mirror-cli jobs verify 3f2a --sample-rate 0.05`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token, err := LoadToken()
+			if err != nil {
+				fmt.Println("Error: You must be logged in to perform this action. Please run 'mirror-cli login'.")
+				os.Exit(1)
+			}
+			sampleRate, _ := cmd.Flags().GetFloat64("sample-rate")
+			noWait, _ := cmd.Flags().GetBool("no-wait")
+			if sampleRate <= 0 || sampleRate > 1 {
+				fmt.Println("Error: --sample-rate must be greater than 0 and at most 1")
+				os.Exit(1)
+			}
+
+			jobPath := "/api/v1/jobs/" + url.PathEscape(args[0]) + "/mirror"
+			var v verificationInfo
+			if err := apiRequest(token, "POST", jobPath+"/verify", map[string]interface{}{"sample_rate": sampleRate}, &v); err != nil {
+				fmt.Printf("Error: Failed to start content verification: %v\n", err)
+				os.Exit(1)
+			}
+			if noWait {
+				fmt.Printf("Content verification #%d of job %s started.\n", v.ID, v.JobID)
+				return
+			}
+			path := fmt.Sprintf("%s/verifications/%d", jobPath, v.ID)
+			for v.Status == "running" {
+				time.Sleep(5 * time.Second)
+				if err := apiRequest(token, "GET", path, nil, &v); err != nil {
+					fmt.Printf("Error: Failed to get content verification: %v\n", err)
+					os.Exit(1)
+				}
+			}
+			printVerification(v)
+			if !v.Consistent {
+				os.Exit(1)
+			}
+		},
+	}
+	verifyCmd.Flags().Float64("sample-rate", 1, "Share of each partition to compare, above 0 and at most 1")
+	verifyCmd.Flags().Bool("no-wait", false, "Start the verification without waiting for the result")
+	return verifyCmd
+}
+
+// deadLetterInfo is a record of a job's dead-letter topic returned by the API.
+type deadLetterInfo struct {
+	Partition       int32     `json:"partition"`
+	Offset          int64     `json:"offset"`
+	SourceTopic     string    `json:"source_topic"`
+	SourcePartition int32     `json:"source_partition"`
+	SourceOffset    int64     `json:"source_offset"`
+	TargetTopic     string    `json:"target_topic"`
+	ErrorClass      string    `json:"error_class"`
+	Error           string    `json:"error"`
+	Attempts        int       `json:"attempts"`
+	FailedAt        time.Time `json:"failed_at"`
+	Replayed        bool      `json:"replayed"`
+}
+
+func createJobDLQCommand() *cobra.Command {
+	dlqCmd := &cobra.Command{
+		Use:   "dlq",
+		Short: "Inspect and replay the dead-letter topic of a job.",
+		Long: `Jobs whose error policy sends rejected records to a dead-letter topic keep them there together with the error,
+its class and the source record they came from. After fixing the cause, replay re-drives the records to the target
+topics that rejected them. Replays continue where the last one stopped.`,
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list [job-id]",
+		Short: "List the newest dead-letter records of a job.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token, err := LoadToken()
+			if err != nil {
+				fmt.Println("Error: You must be logged in to perform this action. Please run 'mirror-cli login'.")
+				os.Exit(1)
+			}
+			limit, _ := cmd.Flags().GetInt("limit")
+			path := fmt.Sprintf("/api/v1/jobs/%s/dlq?limit=%d", url.PathEscape(args[0]), limit)
+
+			var list struct {
+				Cluster string           `json:"cluster"`
+				Topic   string           `json:"topic"`
+				Total   int64            `json:"total"`
+				Pending int64            `json:"pending"`
+				Records []deadLetterInfo `json:"records"`
+			}
+			if err := apiRequest(token, "GET", path, nil, &list); err != nil {
+				fmt.Printf("Error: Failed to list dead-letter records: %v\n", err)
+				os.Exit(1)
+			}
+			if len(list.Records) == 0 {
+				fmt.Printf("Dead-letter topic %s on the %s cluster holds no records.\n", list.Topic, list.Cluster)
+				return
+			}
+			w := new(bytes.Buffer)
+			writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(writer, "RECORD\tFAILED AT\tSOURCE\tTARGET TOPIC\tCLASS\tATTEMPTS\tREPLAYED\tERROR")
+			for _, r := range list.Records {
+				message := r.Error
+				if len(message) > 60 {
+					message = message[:57] + "..."
+				}
+				fmt.Fprintf(writer, "%d/%d\t%s\t%s/%d/%d\t%s\t%s\t%d\t%t\t%s\n", r.Partition, r.Offset,
+					r.FailedAt.Local().Format("2006-01-02 15:04:05"), r.SourceTopic, r.SourcePartition, r.SourceOffset,
+					r.TargetTopic, r.ErrorClass, r.Attempts, r.Replayed, message)
+			}
+			writer.Flush()
+			fmt.Println(w.String())
+			fmt.Printf("Dead-letter topic %s on the %s cluster holds %d records, %d not replayed yet.\n",
+				list.Topic, list.Cluster, list.Total, list.Pending)
+		},
+	}
+	listCmd.Flags().Int("limit", 20, "Maximum number of records to show")
+
+	replayCmd := &cobra.Command{
+		Use:   "replay [job-id]",
+		Short: "Re-drive dead-letter records to their target topics.",
+		Long: `Produce records of the job's dead-letter topic to the target topics that rejected them, without the error
+metadata headers. A partition stops at its first record that fails again; the command exits non-zero in that case.`,
+		Example: `  mirror-cli jobs dlq replay 3f2a
+  mirror-cli jobs dlq replay 3f2a --limit 1000`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token, err := LoadToken()
+			if err != nil {
+				fmt.Println("Error: You must be logged in to perform this action. Please run 'mirror-cli login'.")
+				os.Exit(1)
+			}
+			limit, _ := cmd.Flags().GetInt("limit")
+			path := "/api/v1/jobs/" + url.PathEscape(args[0]) + "/dlq/replay"
+
+			var result struct {
+				Topic    string   `json:"topic"`
+				Replayed int      `json:"replayed"`
+				Failed   int      `json:"failed"`
+				Pending  int64    `json:"pending"`
+				Errors   []string `json:"errors"`
+			}
+			if err := apiRequest(token, "POST", path, map[string]interface{}{"limit": limit}, &result); err != nil {
+				fmt.Printf("Error: Failed to replay dead-letter records: %v\n", err)
+				os.Exit(1)
+			}
+			for _, message := range result.Errors {
+				fmt.Printf("Failed: %s\n", message)
+			}
+			fmt.Printf("Replayed %d records of %s, %d failed, %d pending.\n", result.Replayed, result.Topic, result.Failed, result.Pending)
+			if result.Failed > 0 {
+				os.Exit(1)
+			}
+		},
+	}
+	replayCmd.Flags().Int("limit", 100, "Maximum number of records to replay")
+
+	dlqCmd.AddCommand(listCmd, replayCmd)
+	return dlqCmd
+}
+
+// jobLogEntry is a buffered log record returned by /api/v1/jobs/{id}/logs.
+type jobLogEntry struct {
+	Seq       uint64    `json:"seq"`
+	Time      time.Time `json:"time"`
+	Level     string    `json:"level"`
+	Message   string    `json:"msg"`
+	Component string    `json:"component"`
+	Topic     string    `json:"topic"`
+	Partition *int32    `json:"partition"`
+}
+
+// formatJobLogEntry renders a log record as a single line for the terminal.
+func formatJobLogEntry(e jobLogEntry) string {
+	context := e.Component
+	if e.Topic != "" {
+		if context != "" {
+			context += " "
+		}
+		context += e.Topic
+		if e.Partition != nil {
+			context += fmt.Sprintf("/%d", *e.Partition)
+		}
+	}
+	if context != "" {
+		context = "[" + context + "] "
+	}
+	return fmt.Sprintf("%s %-5s %s%s", e.Time.Local().Format("2006-01-02 15:04:05.000"), e.Level, context, e.Message)
+}
+
+func createJobLogsCommand() *cobra.Command {
+	var (
+		follow bool
+		level  string
+		since  string
+		limit  int
+	)
+
+	logsCmd := &cobra.Command{
+		Use:   "logs [job-id]",
+		Short: "Show recent log output of a job.",
+		Long: `Show the log records the server keeps in memory for a job, oldest first.
+With --follow the command keeps streaming new records until interrupted.`,
+		Example: `  mirror-cli jobs logs 3f2a --follow --level warn
+  mirror-cli jobs logs 3f2a --since 15m --limit 0`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token, err := LoadToken()
+			if err != nil {
+				fmt.Println("Error: You must be logged in to perform this action. Please run 'mirror-cli login'.")
+				os.Exit(1)
+			}
+
+			query := url.Values{}
+			query.Set("limit", fmt.Sprintf("%d", limit))
+			if level != "" {
+				query.Set("level", level)
+			}
+			if since != "" {
+				query.Set("since", since)
+			}
+			path := "/api/v1/jobs/" + url.PathEscape(args[0]) + "/logs"
+
+			if !follow {
+				var resp struct {
+					Logs []jobLogEntry `json:"logs"`
+				}
+				if err := apiRequest(token, "GET", path+"?"+query.Encode(), nil, &resp); err != nil {
+					fmt.Printf("Error: Failed to fetch job logs: %v\n", err)
+					os.Exit(1)
+				}
+				if len(resp.Logs) == 0 {
+					fmt.Println("No log records buffered for this job.")
+					return
+				}
+				for _, e := range resp.Logs {
+					fmt.Println(formatJobLogEntry(e))
+				}
+				return
+			}
+
+			query.Set("follow", "true")
+			req, err := http.NewRequest("GET", BackendURL+path+"?"+query.Encode(), nil)
+			if err != nil {
+				fmt.Printf("Error: Failed to build request: %v\n", err)
+				os.Exit(1)
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+
+			// The stream stays open indefinitely, so the shared client's timeout does not apply.
+			streamClient := &http.Client{Transport: httpClient.Transport}
+			resp, err := streamClient.Do(req)
+			if err != nil {
+				fmt.Printf("Error: Failed to connect to backend: %v\n", err)
+				os.Exit(1)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				fmt.Printf("Error: Failed to follow job logs: %v\n", responseError(resp))
+				os.Exit(1)
+			}
+
+			scanner := bufio.NewScanner(resp.Body)
+			scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+			for scanner.Scan() {
+				line := bytes.TrimSpace(scanner.Bytes())
+				if len(line) == 0 {
+					continue // heartbeat
+				}
+				var e jobLogEntry
+				if err := json.Unmarshal(line, &e); err != nil {
+					continue
+				}
+				fmt.Println(formatJobLogEntry(e))
+			}
+			if err := scanner.Err(); err != nil {
+				fmt.Printf("Error: Log stream interrupted: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Println("Log stream closed by server.")
+		},
+	}
+
+	logsCmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep streaming new log records")
+	logsCmd.Flags().StringVar(&level, "level", "", "Minimum level: debug, info, warn or error")
+	logsCmd.Flags().StringVar(&since, "since", "", "Only records newer than this duration (e.g. 15m) or RFC3339 time")
+	logsCmd.Flags().IntVar(&limit, "limit", 200, "Maximum number of buffered records to show first (0 for all)")
+	return logsCmd
+}
+
+func createAlertsCommand() *cobra.Command {
+	alertsCmd := &cobra.Command{
+		Use:   "alerts",
+		Short: "Manage alerts, alert rules, silences and notification channels.",
+		Long: `The alerts command lists fired alerts and acknowledges them, manages the per-job alert rules
+(lag, lag_seconds, error_rate, stalled, job_failed, gap_detected, slo_burn_rate), silences notifications and
+sends test notifications through the channels configured under alerting.channels.`,
+	}
+
+	requireToken := func() string {
+		token, err := LoadToken()
+		if err != nil {
+			fmt.Println("Error: You must be logged in to perform this action. Please run 'mirror-cli login'.")
+			os.Exit(1)
+		}
+		return token
+	}
+
+	listAlertsCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List alerts.",
+		Example: `  mirror-cli alerts list
+  mirror-cli alerts list --status all --job 3f2a`,
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			status, _ := cmd.Flags().GetString("status")
+			jobID, _ := cmd.Flags().GetString("job")
+
+			query := url.Values{}
+			query.Set("status", status)
+			if jobID != "" {
+				query.Set("job_id", jobID)
+			}
+			var alerts []alertInfo
+			if err := apiRequest(token, "GET", "/api/v1/alerts?"+query.Encode(), nil, &alerts); err != nil {
+				fmt.Printf("Error: Failed to list alerts: %v\n", err)
+				os.Exit(1)
+			}
+			if len(alerts) == 0 {
+				fmt.Println("No alerts found.")
+				return
+			}
+
+			w := new(bytes.Buffer)
+			writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(writer, "ID\tSTATUS\tSEVERITY\tRULE\tJOB\tFIRED\tACKED BY\tSUMMARY")
+			for _, a := range alerts {
+				state := a.Status
+				if a.Silenced {
+					state += " (silenced)"
+				}
+				ackedBy := "-"
+				if a.AcknowledgedBy != nil {
+					ackedBy = *a.AcknowledgedBy
+				}
+				fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", a.ID, state, a.Severity, a.RuleName, a.JobID,
+					a.FiredAt.Local().Format("2006-01-02 15:04"), ackedBy, a.Summary)
+			}
+			writer.Flush()
+			fmt.Println(w.String())
+		},
+	}
+	listAlertsCmd.Flags().String("status", "firing", "Alert status: firing, resolved or all")
+	listAlertsCmd.Flags().String("job", "", "Only show alerts of this job ID")
+
+	ackAlertCmd := &cobra.Command{
+		Use:   "ack [alert-id]",
+		Short: "Acknowledge an alert.",
+		Long:  `Acknowledged alerts are no longer re-notified. PagerDuty channels acknowledge the incident.`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			var alert alertInfo
+			if err := apiRequest(token, "POST", "/api/v1/alerts/"+url.PathEscape(args[0])+"/ack", nil, &alert); err != nil {
+				fmt.Printf("Error: Failed to acknowledge alert: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Alert %d (%s on job %s) acknowledged.\n", alert.ID, alert.RuleName, alert.JobID)
+		},
+	}
+
+	rulesCmd := &cobra.Command{
+		Use:   "rules",
+		Short: "Manage alert rules.",
+	}
+
+	listRulesCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List alert rules.",
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			var rules []alertRuleInfo
+			if err := apiRequest(token, "GET", "/api/v1/alerts/rules", nil, &rules); err != nil {
+				fmt.Printf("Error: Failed to list alert rules: %v\n", err)
+				os.Exit(1)
+			}
+			if len(rules) == 0 {
+				fmt.Println("No alert rules defined.")
+				return
+			}
+
+			w := new(bytes.Buffer)
+			writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(writer, "ID\tNAME\tTYPE\tJOB\tTHRESHOLD\tFOR\tSEVERITY\tCHANNELS\tENABLED")
+			for _, r := range rules {
+				jobID := r.JobID
+				if jobID == "" {
+					jobID = "all"
+				}
+				fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%g\t%s\t%s\t%s\t%t\n", r.ID, r.Name, r.Type, jobID, r.Threshold,
+					r.For, r.Severity, listOrDefault(r.Channels, "all"), r.Enabled)
+			}
+			writer.Flush()
+			fmt.Println(w.String())
+		},
+	}
+
+	// ruleRequestFromFlags only includes the flags the user set, so updates leave other fields untouched.
+	ruleRequestFromFlags := func(cmd *cobra.Command) map[string]interface{} {
+		req := map[string]interface{}{}
+		flags := cmd.Flags()
+		if flags.Changed("name") {
+			v, _ := flags.GetString("name")
+			req["name"] = v
+		}
+		if flags.Changed("type") {
+			v, _ := flags.GetString("type")
+			req["type"] = v
+		}
+		if flags.Changed("job") {
+			v, _ := flags.GetString("job")
+			req["job_id"] = v
+		}
+		if flags.Changed("threshold") {
+			v, _ := flags.GetFloat64("threshold")
+			req["threshold"] = v
+		}
+		if flags.Changed("for") {
+			v, _ := flags.GetString("for")
+			req["for"] = v
+		}
+		if flags.Changed("severity") {
+			v, _ := flags.GetString("severity")
+			req["severity"] = v
+		}
+		if flags.Changed("channels") {
+			v, _ := flags.GetStringSlice("channels")
+			req["channels"] = v
+		}
+		if flags.Changed("enabled") {
+			v, _ := flags.GetBool("enabled")
+			req["enabled"] = v
+		}
+		return req
+	}
+	addRuleFlags := func(cmd *cobra.Command) {
+		cmd.Flags().String("name", "", "Rule name")
+		cmd.Flags().String("type", "", "Rule type: lag, lag_seconds, error_rate, stalled, job_failed, gap_detected or slo_burn_rate")
+		cmd.Flags().String("job", "", "Job ID the rule applies to (empty for all jobs)")
+		cmd.Flags().Float64("threshold", 0, "Threshold: messages (lag), seconds (lag_seconds), percent (error_rate), gap count or burn-rate multiple (slo_burn_rate)")
+		cmd.Flags().String("for", "", "How long the condition must hold before firing, e.g. 5m")
+		cmd.Flags().String("severity", "", "Severity: info, warning or critical")
+		cmd.Flags().StringSlice("channels", nil, "Notification channels (default all configured channels)")
+		cmd.Flags().Bool("enabled", true, "Whether the rule is evaluated")
+	}
+
+	createRuleCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an alert rule.",
+		Example: `  mirror-cli alerts rules create --name orders-lag --type lag --job 3f2a --threshold 10000 --for 5m --severity critical --channels pagerduty
+  mirror-cli alerts rules create --name any-job-failed --type job_failed --severity critical`,
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			var rule alertRuleInfo
+			if err := apiRequest(token, "POST", "/api/v1/alerts/rules", ruleRequestFromFlags(cmd), &rule); err != nil {
+				fmt.Printf("Error: Failed to create alert rule: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Alert rule '%s' created with ID %d.\n", rule.Name, rule.ID)
+		},
+	}
+	addRuleFlags(createRuleCmd)
+	createRuleCmd.MarkFlagRequired("name")
+	createRuleCmd.MarkFlagRequired("type")
+
+	updateRuleCmd := &cobra.Command{
+		Use:     "update [rule-id]",
+		Short:   "Update an alert rule.",
+		Example: `  mirror-cli alerts rules update 4 --threshold 50000 --for 10m`,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			var rule alertRuleInfo
+			if err := apiRequest(token, "PUT", "/api/v1/alerts/rules/"+url.PathEscape(args[0]), ruleRequestFromFlags(cmd), &rule); err != nil {
+				fmt.Printf("Error: Failed to update alert rule: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Alert rule '%s' updated.\n", rule.Name)
+		},
+	}
+	addRuleFlags(updateRuleCmd)
+
+	deleteRuleCmd := &cobra.Command{
+		Use:   "delete [rule-id]",
+		Short: "Delete an alert rule.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			if err := apiRequest(token, "DELETE", "/api/v1/alerts/rules/"+url.PathEscape(args[0]), nil, nil); err != nil {
+				fmt.Printf("Error: Failed to delete alert rule: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Alert rule %s deleted.\n", args[0])
+		},
+	}
+	rulesCmd.AddCommand(listRulesCmd, createRuleCmd, updateRuleCmd, deleteRuleCmd)
+
+	silencesCmd := &cobra.Command{
+		Use:   "silences",
+		Short: "Manage alert silences.",
+	}
+
+	listSilencesCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List alert silences.",
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			all, _ := cmd.Flags().GetBool("all")
+			var silences []alertSilenceInfo
+			if err := apiRequest(token, "GET", fmt.Sprintf("/api/v1/alerts/silences?all=%t", all), nil, &silences); err != nil {
+				fmt.Printf("Error: Failed to list alert silences: %v\n", err)
+				os.Exit(1)
+			}
+			if len(silences) == 0 {
+				fmt.Println("No alert silences found.")
+				return
+			}
+
+			w := new(bytes.Buffer)
+			writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(writer, "ID\tJOB\tRULE\tENDS\tCREATED BY\tREASON")
+			for _, s := range silences {
+				jobID, ruleID := s.JobID, "all"
+				if jobID == "" {
+					jobID = "all"
+				}
+				if s.RuleID != nil {
+					ruleID = fmt.Sprint(*s.RuleID)
+				}
+				fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\t%s\n", s.ID, jobID, ruleID,
+					s.EndsAt.Local().Format("2006-01-02 15:04"), s.CreatedBy, s.Reason)
+			}
+			writer.Flush()
+			fmt.Println(w.String())
+		},
+	}
+	listSilencesCmd.Flags().Bool("all", false, "Include expired silences")
+
+	createSilenceCmd := &cobra.Command{
+		Use:     "create",
+		Short:   "Silence notifications for a job, a rule, or both.",
+		Example: `  mirror-cli alerts silences create --job 3f2a --duration 2h --reason "planned broker maintenance"`,
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			jobID, _ := cmd.Flags().GetString("job")
+			ruleID, _ := cmd.Flags().GetInt("rule")
+			duration, _ := cmd.Flags().GetString("duration")
+			reason, _ := cmd.Flags().GetString("reason")
+
+			req := map[string]interface{}{"job_id": jobID, "duration": duration, "reason": reason}
+			if cmd.Flags().Changed("rule") {
+				req["rule_id"] = ruleID
+			}
+			var silence alertSilenceInfo
+			if err := apiRequest(token, "POST", "/api/v1/alerts/silences", req, &silence); err != nil {
+				fmt.Printf("Error: Failed to create alert silence: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Silence %d active until %s.\n", silence.ID, silence.EndsAt.Local().Format("2006-01-02 15:04"))
+		},
+	}
+	createSilenceCmd.Flags().String("job", "", "Job ID to silence")
+	createSilenceCmd.Flags().Int("rule", 0, "Alert rule ID to silence")
+	createSilenceCmd.Flags().String("duration", "1h", "How long the silence lasts")
+	createSilenceCmd.Flags().String("reason", "", "Why notifications are silenced")
+
+	deleteSilenceCmd := &cobra.Command{
+		Use:   "delete [silence-id]",
+		Short: "End an alert silence.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			if err := apiRequest(token, "DELETE", "/api/v1/alerts/silences/"+url.PathEscape(args[0]), nil, nil); err != nil {
+				fmt.Printf("Error: Failed to delete alert silence: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Silence %s ended.\n", args[0])
+		},
+	}
+	silencesCmd.AddCommand(listSilencesCmd, createSilenceCmd, deleteSilenceCmd)
+
+	channelsCmd := &cobra.Command{
+		Use:   "channels",
+		Short: "List the configured notification channels.",
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			var channels []struct {
+				Name string `json:"name"`
+				Type string `json:"type"`
+			}
+			if err := apiRequest(token, "GET", "/api/v1/alerts/channels", nil, &channels); err != nil {
+				fmt.Printf("Error: Failed to list alert channels: %v\n", err)
+				os.Exit(1)
+			}
+			if len(channels) == 0 {
+				fmt.Println("No notification channels configured.")
+				return
+			}
+			for _, c := range channels {
+				fmt.Printf("%s (%s)\n", c.Name, c.Type)
+			}
+		},
+	}
+
+	testChannelCmd := &cobra.Command{
+		Use:   "test [channel]",
+		Short: "Send a test notification through a channel.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			if err := apiRequest(token, "POST", "/api/v1/alerts/channels/"+url.PathEscape(args[0])+"/test", nil, nil); err != nil {
+				fmt.Printf("Error: Test notification failed: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Test notification sent through %s.\n", args[0])
+		},
+	}
+
+	alertsCmd.AddCommand(listAlertsCmd, ackAlertCmd, rulesCmd, silencesCmd, channelsCmd, testChannelCmd)
+	return alertsCmd
+}
+
+func createWebhooksCommand() *cobra.Command {
+	webhooksCmd := &cobra.Command{
+		Use:   "webhooks",
+		Short: "Manage webhook subscriptions and their deliveries.",
+		Long: `The webhooks command registers HTTP endpoints that receive job lifecycle and incident events
+(job.started, job.stopped, job.failed, job.gap_detected, ai.insight_created). Every payload is signed with
+HMAC-SHA256 in the X-Kaf-Mirror-Signature header, failed deliveries are retried with backoff and can be
+replayed from the delivery log.`,
+	}
+
+	requireToken := func() string {
+		token, err := LoadToken()
+		if err != nil {
+			fmt.Println("Error: You must be logged in to perform this action. Please run 'mirror-cli login'.")
+			os.Exit(1)
+		}
+		return token
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List webhooks.",
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			var webhooks []webhookInfo
+			if err := apiRequest(token, "GET", "/api/v1/webhooks", nil, &webhooks); err != nil {
+				fmt.Printf("Error: Failed to list webhooks: %v\n", err)
+				os.Exit(1)
+			}
+			if len(webhooks) == 0 {
+				fmt.Println("No webhooks registered.")
+				return
+			}
+
+			w := new(bytes.Buffer)
+			writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(writer, "ID\tNAME\tURL\tEVENTS\tJOB\tENABLED")
+			for _, h := range webhooks {
+				jobID := h.JobID
+				if jobID == "" {
+					jobID = "all"
+				}
+				fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\t%t\n", h.ID, h.Name, h.URL, listOrDefault(h.EventTypes, "all"), jobID, h.Enabled)
+			}
+			writer.Flush()
+			fmt.Println(w.String())
+		},
+	}
+
+	// webhookRequestFromFlags only includes the flags the user set, so updates leave other fields untouched.
+	webhookRequestFromFlags := func(cmd *cobra.Command) map[string]interface{} {
+		req := map[string]interface{}{}
+		flags := cmd.Flags()
+		for flag, field := range map[string]string{"name": "name", "url": "url", "secret": "secret", "job": "job_id"} {
+			if flags.Changed(flag) {
+				v, _ := flags.GetString(flag)
+				req[field] = v
+			}
+		}
+		if flags.Changed("events") {
+			v, _ := flags.GetStringSlice("events")
+			req["event_types"] = v
+		}
+		if flags.Changed("enabled") {
+			v, _ := flags.GetBool("enabled")
+			req["enabled"] = v
+		}
+		if flags.Changed("rotate-secret") {
+			v, _ := flags.GetBool("rotate-secret")
+			req["rotate_secret"] = v
+		}
+		return req
+	}
+	addWebhookFlags := func(cmd *cobra.Command) {
+		cmd.Flags().String("name", "", "Webhook name")
+		cmd.Flags().String("url", "", "Receiver URL (http or https)")
+		cmd.Flags().StringSlice("events", nil, "Event types to send (default all): job.started, job.stopped, job.failed, job.gap_detected, ai.insight_created")
+		cmd.Flags().String("job", "", "Only send events of this job ID (empty for all jobs)")
+		cmd.Flags().String("secret", "", "Signing secret (generated when omitted)")
+		cmd.Flags().Bool("enabled", true, "Whether events are delivered")
+	}
+	printSecret := func(h webhookInfo) {
+		if h.Secret != "" {
+			fmt.Printf("Signing secret: %s\n", h.Secret)
+			fmt.Println("Store it now, it is not shown again. Verify payloads with HMAC-SHA256 over \"<X-Kaf-Mirror-Timestamp>.<body>\".")
+		}
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Register a webhook.",
+		Example: `  mirror-cli webhooks create --name servicenow --url https://example.service-now.com/api/kaf-mirror --events job.failed,job.gap_detected
+  mirror-cli webhooks create --name chatops --url https://chat.example.com/hooks/kaf-mirror --job 3f2a`,
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			var webhook webhookInfo
+			if err := apiRequest(token, "POST", "/api/v1/webhooks", webhookRequestFromFlags(cmd), &webhook); err != nil {
+				fmt.Printf("Error: Failed to create webhook: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Webhook '%s' created with ID %d.\n", webhook.Name, webhook.ID)
+			printSecret(webhook)
+		},
+	}
+	addWebhookFlags(createCmd)
+	createCmd.MarkFlagRequired("name")
+	createCmd.MarkFlagRequired("url")
+
+	updateCmd := &cobra.Command{
+		Use:   "update [webhook-id]",
+		Short: "Update a webhook.",
+		Example: `  mirror-cli webhooks update 2 --events job.failed --enabled=false
+  mirror-cli webhooks update 2 --rotate-secret`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			var webhook webhookInfo
+			if err := apiRequest(token, "PUT", "/api/v1/webhooks/"+url.PathEscape(args[0]), webhookRequestFromFlags(cmd), &webhook); err != nil {
+				fmt.Printf("Error: Failed to update webhook: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Webhook '%s' updated.\n", webhook.Name)
+			printSecret(webhook)
+		},
+	}
+	addWebhookFlags(updateCmd)
+	updateCmd.Flags().Bool("rotate-secret", false, "Generate a new signing secret")
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete [webhook-id]",
+		Short: "Delete a webhook and its delivery log.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			if err := apiRequest(token, "DELETE", "/api/v1/webhooks/"+url.PathEscape(args[0]), nil, nil); err != nil {
+				fmt.Printf("Error: Failed to delete webhook: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("Webhook %s deleted.\n", args[0])
+		},
+	}
+
+	testCmd := &cobra.Command{
+		Use:   "test [webhook-id]",
+		Short: "Send a webhook.test event.",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			var delivery webhookDeliveryInfo
+			if err := apiRequest(token, "POST", "/api/v1/webhooks/"+url.PathEscape(args[0])+"/test", nil, &delivery); err != nil {
+				fmt.Printf("Error: Failed to send test event: %v\n", err)
+				os.Exit(1)
+			}
+			if delivery.Status == "delivered" {
+				fmt.Printf("Test event delivered (delivery %d).\n", delivery.ID)
+				return
+			}
+			reason := "unknown error"
+			if delivery.LastError != nil {
+				reason = *delivery.LastError
+			}
+			fmt.Printf("Test event failed (delivery %d): %s\n", delivery.ID, reason)
+			os.Exit(1)
+		},
+	}
+
+	deliveriesCmd := &cobra.Command{
+		Use:     "deliveries [webhook-id]",
+		Short:   "Show the delivery log of a webhook.",
+		Example: `  mirror-cli webhooks deliveries 2 --status failed`,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			status, _ := cmd.Flags().GetString("status")
+			limit, _ := cmd.Flags().GetInt("limit")
+			query := url.Values{}
+			if status != "" {
+				query.Set("status", status)
+			}
+			query.Set("limit", fmt.Sprint(limit))
+
+			var deliveries []webhookDeliveryInfo
+			if err := apiRequest(token, "GET", "/api/v1/webhooks/"+url.PathEscape(args[0])+"/deliveries?"+query.Encode(), nil, &deliveries); err != nil {
+				fmt.Printf("Error: Failed to list webhook deliveries: %v\n", err)
+				os.Exit(1)
+			}
+			if len(deliveries) == 0 {
+				fmt.Println("No deliveries found.")
+				return
+			}
+
+			w := new(bytes.Buffer)
+			writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(writer, "ID\tEVENT\tJOB\tSTATUS\tATTEMPTS\tHTTP\tCREATED\tERROR")
+			for _, d := range deliveries {
+				httpStatus, lastError := "-", "-"
+				if d.ResponseStatus != nil {
+					httpStatus = fmt.Sprint(*d.ResponseStatus)
+				}
+				if d.LastError != nil {
+					lastError = *d.LastError
+				}
+				state := d.Status
+				if d.ReplayOf != nil {
+					state += fmt.Sprintf(" (replay of %d)", *d.ReplayOf)
+				}
+				fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%d\t%s\t%s\t%s\n", d.ID, d.EventType, d.JobID, state, d.Attempts,
+					httpStatus, d.CreatedAt.Local().Format("2006-01-02 15:04:05"), lastError)
+			}
+			writer.Flush()
+			fmt.Println(w.String())
+		},
+	}
+	deliveriesCmd.Flags().String("status", "", "Only show pending, delivered or failed deliveries")
+	deliveriesCmd.Flags().Int("limit", 50, "Maximum number of deliveries")
+
+	replayCmd := &cobra.Command{
+		Use:   "replay [webhook-id]",
+		Short: "Replay failed deliveries.",
+		Long: `Without --delivery, every failed delivery of the webhook that was not replayed yet is queued again.
+With --delivery, only that delivery is replayed; delivered events can be replayed too.`,
+		Example: `  mirror-cli webhooks replay 2
+  mirror-cli webhooks replay 2 --delivery 118`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			token := requireToken()
+			deliveryID, _ := cmd.Flags().GetInt("delivery")
+			if deliveryID > 0 {
+				var delivery webhookDeliveryInfo
+				if err := apiRequest(token, "POST", fmt.Sprintf("/api/v1/webhooks/deliveries/%d/replay", deliveryID), nil, &delivery); err != nil {
+					fmt.Printf("Error: Failed to replay delivery: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Printf("Delivery %d queued as delivery %d.\n", deliveryID, delivery.ID)
+				return
+			}
+			var result struct {
+				Replayed int `json:"replayed"`
+			}
+			if err := apiRequest(token, "POST", "/api/v1/webhooks/"+url.PathEscape(args[0])+"/replay", nil, &result); err != nil {
+				fmt.Printf("Error: Failed to replay deliveries: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Printf("%d failed deliveries queued for replay.\n", result.Replayed)
+		},
+	}
+	replayCmd.Flags().Int("delivery", 0, "Replay a single delivery by ID")
+
+	webhooksCmd.AddCommand(listCmd, createCmd, updateCmd, deleteCmd, testCmd, deliveriesCmd, replayCmd)
+	return webhooksCmd
+}
@@ -0,0 +1,604 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/pkg/logger"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// staleSampleAge is how old the newest metric of an active job may be before the
+// job is considered stalled. Metrics are collected every 10 seconds.
+const staleSampleAge = time.Minute
+
+type alertKey struct {
+	ruleID int
+	jobID  string
+}
+
+// evalCache holds per-job lookups shared by the rules of one evaluation.
+type evalCache struct {
+	gapCounts map[string]int
+	sloBurns  map[string]sloBurn
+}
+
+// sloBurn is the fastest-burning SLO of a job.
+type sloBurn struct {
+	slo  string
+	rate float64
+}
+
+// delivery is a notification waiting to be sent to the named channels.
+type delivery struct {
+	names []string
+	n     Notification
+}
+
+type jobSamples struct {
+	prev, last   database.ReplicationMetric
+	hasPrev      bool
+	lastProgress time.Time
+}
+
+// Engine evaluates alert rules against job state and routes fired, acknowledged
+// and resolved alerts to the configured notification channels.
+type Engine struct {
+	db             *sqlx.DB
+	notifiers      map[string]Notifier
+	channelNames   []string
+	repeatInterval time.Duration
+	sendResolved   bool
+
+	mu       sync.Mutex
+	samples  map[string]*jobSamples
+	pending  map[alertKey]time.Time
+	firing   map[alertKey]*database.Alert
+	restored bool
+	outbox   []delivery // sent once e.mu is released
+
+	listeners []func(Notification)
+}
+
+// NewEngine builds the notification channels in cfg and returns an engine.
+func NewEngine(db *sqlx.DB, cfg config.AlertingConfig) (*Engine, error) {
+	e := &Engine{
+		db:           db,
+		notifiers:    make(map[string]Notifier),
+		sendResolved: cfg.SendResolved,
+		samples:      make(map[string]*jobSamples),
+		pending:      make(map[alertKey]time.Time),
+		firing:       make(map[alertKey]*database.Alert),
+	}
+	if cfg.RepeatInterval != "" {
+		d, err := time.ParseDuration(cfg.RepeatInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid alerting repeat_interval: %v", err)
+		}
+		e.repeatInterval = d
+	}
+	for _, channel := range cfg.Channels {
+		notifier, err := NewNotifier(channel)
+		if err != nil {
+			return nil, err
+		}
+		e.notifiers[channel.Name] = notifier
+		e.channelNames = append(e.channelNames, channel.Name)
+	}
+	return e, nil
+}
+
+// AddListener registers fn to be called whenever an alert fires, resolves or is
+// acknowledged, regardless of silences and channel routing. fn must not block.
+func (e *Engine) AddListener(fn func(Notification)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.listeners = append(e.listeners, fn)
+}
+
+// publish passes n to the listeners. Callers hold e.mu.
+func (e *Engine) publish(n Notification) {
+	for _, fn := range e.listeners {
+		fn(n)
+	}
+}
+
+// Channels returns the configured notifiers in configuration order.
+func (e *Engine) Channels() []Notifier {
+	channels := make([]Notifier, 0, len(e.channelNames))
+	for _, name := range e.channelNames {
+		channels = append(channels, e.notifiers[name])
+	}
+	return channels
+}
+
+// TestChannel sends a test notification through a single channel.
+func (e *Engine) TestChannel(ctx context.Context, name string) error {
+	notifier, ok := e.notifiers[name]
+	if !ok {
+		return fmt.Errorf("channel %s is not configured", name)
+	}
+	return notifier.Notify(ctx, Notification{
+		Event: EventTest,
+		Alert: database.Alert{
+			RuleName: "test-notification",
+			RuleType: "test",
+			JobID:    "-",
+			Severity: database.AlertSeverityInfo,
+			Status:   database.AlertStatusFiring,
+			Summary:  fmt.Sprintf("Test notification for channel %s from kaf-mirror", name),
+			FiredAt:  time.Now().UTC(),
+		},
+	})
+}
+
+// ObserveMetric records a metric sample used by the lag, error rate and stall rules.
+func (e *Engine) ObserveMetric(metric database.ReplicationMetric) {
+	if metric.Timestamp.IsZero() {
+		metric.Timestamp = time.Now()
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	s, ok := e.samples[metric.JobID]
+	if !ok {
+		e.samples[metric.JobID] = &jobSamples{last: metric, lastProgress: metric.Timestamp}
+		return
+	}
+	// Counters start from zero when a job restarts; drop the previous sample then.
+	if metric.MessagesConsumed < s.last.MessagesConsumed || metric.MessagesReplicated < s.last.MessagesReplicated {
+		e.samples[metric.JobID] = &jobSamples{last: metric, lastProgress: metric.Timestamp}
+		return
+	}
+	if metric.MessagesConsumed > s.last.MessagesConsumed || metric.CurrentLag == 0 {
+		s.lastProgress = metric.Timestamp
+	}
+	s.prev, s.last, s.hasPrev = s.last, metric, true
+}
+
+// Evaluate checks every enabled rule against every job it applies to. Channels
+// are notified after the engine is unlocked, so a slow channel does not hold up
+// ObserveMetric.
+func (e *Engine) Evaluate(now time.Time) error {
+	e.mu.Lock()
+	err := e.evaluate(now)
+	deliveries := e.takeOutbox()
+	e.mu.Unlock()
+
+	e.deliver(deliveries)
+	return err
+}
+
+func (e *Engine) evaluate(now time.Time) error {
+	if !e.restored {
+		if err := e.restoreFiring(); err != nil {
+			return err
+		}
+		e.restored = true
+	}
+
+	rules, err := database.ListAlertRules(e.db)
+	if err != nil {
+		return err
+	}
+	jobs, err := database.ListJobs(e.db)
+	if err != nil {
+		return err
+	}
+
+	cache := &evalCache{gapCounts: make(map[string]int), sloBurns: make(map[string]sloBurn)}
+	seen := make(map[alertKey]bool)
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.Enabled {
+			continue
+		}
+		forDuration, err := rule.For()
+		if err != nil {
+			logger.Warn("Skipping alert rule %s: %v", rule.Name, err)
+			continue
+		}
+		for j := range jobs {
+			job := &jobs[j]
+			if !rule.AppliesTo(job.ID) {
+				continue
+			}
+			key := alertKey{ruleID: rule.ID, jobID: job.ID}
+			seen[key] = true
+
+			value, active, summary := e.check(rule, job, now, cache)
+			if !active {
+				delete(e.pending, key)
+				if alert, ok := e.firing[key]; ok {
+					e.resolve(key, alert, rule, now)
+				}
+				continue
+			}
+
+			if alert, ok := e.firing[key]; ok {
+				e.renotify(alert, rule, now)
+				continue
+			}
+			since, ok := e.pending[key]
+			if !ok {
+				since = now
+				e.pending[key] = now
+			}
+			if now.Sub(since) >= forDuration {
+				delete(e.pending, key)
+				e.fire(key, rule, value, summary, now)
+			}
+		}
+	}
+
+	// Alerts whose rule or job disappeared, or whose rule was disabled, are resolved.
+	for key, alert := range e.firing {
+		if !seen[key] {
+			e.resolve(key, alert, nil, now)
+		}
+	}
+	for key := range e.pending {
+		if !seen[key] {
+			delete(e.pending, key)
+		}
+	}
+	return nil
+}
+
+// Acknowledge marks an alert as acknowledged and tells the rule's channels about it.
+func (e *Engine) Acknowledge(id int, username string) (*database.Alert, error) {
+	e.mu.Lock()
+	alert, err := e.acknowledge(id, username)
+	deliveries := e.takeOutbox()
+	e.mu.Unlock()
+
+	e.deliver(deliveries)
+	return alert, err
+}
+
+func (e *Engine) acknowledge(id int, username string) (*database.Alert, error) {
+	alert, err := database.AcknowledgeAlert(e.db, id, username)
+	if err != nil {
+		return alert, err
+	}
+	for _, firing := range e.firing {
+		if firing.ID == alert.ID {
+			firing.AcknowledgedBy = alert.AcknowledgedBy
+			firing.AcknowledgedAt = alert.AcknowledgedAt
+		}
+	}
+	e.recordEvent("alert_acknowledged", username, alert)
+	e.publish(Notification{Event: EventAcknowledged, Alert: *alert})
+	if alert.Status == database.AlertStatusFiring && !alert.Silenced {
+		var channels []string
+		if rule, err := database.GetAlertRule(e.db, alert.RuleID); err == nil {
+			channels = rule.ChannelList()
+		}
+		e.notify(channels, Notification{Event: EventAcknowledged, Alert: *alert})
+	}
+	return alert, nil
+}
+
+func (e *Engine) restoreFiring() error {
+	alerts, err := database.ListAlerts(e.db, database.AlertStatusFiring, "", 0)
+	if err != nil {
+		return err
+	}
+	for i := range alerts {
+		alert := alerts[i]
+		e.firing[alertKey{ruleID: alert.RuleID, jobID: alert.JobID}] = &alert
+	}
+	return nil
+}
+
+// check returns the observed value of the rule for the job and whether the condition holds.
+func (e *Engine) check(rule *database.AlertRule, job *database.ReplicationJob, now time.Time, cache *evalCache) (float64, bool, string) {
+	jobLabel := fmt.Sprintf("%s (%s)", job.Name, job.ID)
+
+	switch rule.RuleType {
+	case database.AlertRuleJobFailed:
+		if job.Status != "failed" {
+			return 0, false, ""
+		}
+		reason := "no reason recorded"
+		if job.FailedReason != nil && *job.FailedReason != "" {
+			reason = *job.FailedReason
+		}
+		return 1, true, fmt.Sprintf("Job %s failed: %s", jobLabel, reason)
+
+	case database.AlertRuleGapDetected:
+		count, ok := cache.gapCounts[job.ID]
+		if !ok {
+			gaps, err := database.GetUnresolvedMirrorGaps(e.db, job.ID)
+			if err != nil {
+				logger.Warn("Failed to load mirror gaps for job %s: %v", job.ID, err)
+			}
+			count = len(gaps)
+			cache.gapCounts[job.ID] = count
+		}
+		value := float64(count)
+		return value, value > rule.Threshold, fmt.Sprintf("%d unresolved replication gaps on job %s", count, jobLabel)
+
+	case database.AlertRuleSLOBurnRate:
+		burn, ok := cache.sloBurns[job.ID]
+		if !ok {
+			burn = e.sloBurnRate(job.ID, now)
+			cache.sloBurns[job.ID] = burn
+		}
+		if burn.slo == "" {
+			return 0, false, ""
+		}
+		return burn.rate, burn.rate > rule.Threshold,
+			fmt.Sprintf("SLO %q of job %s is burning its error budget %.1fx too fast (threshold %sx)",
+				burn.slo, jobLabel, burn.rate, formatValue(rule.Threshold))
+	}
+
+	// The remaining rules look at live metrics, which only running jobs produce.
+	if job.Status != "active" {
+		return 0, false, ""
+	}
+	s, ok := e.samples[job.ID]
+	stale := !ok || now.Sub(s.last.Timestamp) > staleSampleAge
+
+	switch rule.RuleType {
+	case database.AlertRuleStalled:
+		stalled := stale
+		if !stale {
+			stalled = s.last.SourceStalled || s.last.TargetStalled ||
+				(s.hasPrev && s.last.CurrentLag > 0 &&
+					s.last.MessagesConsumed == s.prev.MessagesConsumed &&
+					s.last.MessagesReplicated == s.prev.MessagesReplicated)
+		}
+		if !stalled {
+			return 0, false, ""
+		}
+		if stale {
+			return 1, true, fmt.Sprintf("Job %s has not reported metrics for over %s", jobLabel, staleSampleAge)
+		}
+		return 1, true, fmt.Sprintf("Job %s is not making progress (lag %d messages)", jobLabel, s.last.CurrentLag)
+
+	case database.AlertRuleLag:
+		if stale {
+			return 0, false, ""
+		}
+		value := float64(s.last.CurrentLag)
+		return value, value > rule.Threshold,
+			fmt.Sprintf("Consumer lag for job %s is %d messages (threshold %s)", jobLabel, s.last.CurrentLag, formatValue(rule.Threshold))
+
+	case database.AlertRuleLagSeconds:
+		if stale {
+			return 0, false, ""
+		}
+		value := lagSeconds(s)
+		return value, value > rule.Threshold,
+			fmt.Sprintf("Job %s is %.0fs behind its source (threshold %ss)", jobLabel, value, formatValue(rule.Threshold))
+
+	case database.AlertRuleErrorRate:
+		if stale || !s.hasPrev {
+			return 0, false, ""
+		}
+		value := errorRate(s)
+		return value, value > rule.Threshold,
+			fmt.Sprintf("Error rate for job %s is %.1f%% (threshold %s%%)", jobLabel, value, formatValue(rule.Threshold))
+	}
+	return 0, false, ""
+}
+
+// sloBurnRate returns the fastest-burning enabled SLO of the job. An SLO only
+// counts as burning at the lower of its 1h and 5m rates, so a short spike that
+// has already recovered does not page.
+func (e *Engine) sloBurnRate(jobID string, now time.Time) sloBurn {
+	slos, err := database.ListJobSLOs(e.db, jobID)
+	if err != nil {
+		logger.Warn("Failed to load SLOs for job %s: %v", jobID, err)
+		return sloBurn{}
+	}
+	enabled := slos[:0]
+	for _, slo := range slos {
+		if slo.Enabled && slo.SLOType != database.SLOTypeGaps {
+			enabled = append(enabled, slo)
+		}
+	}
+	statuses, err := database.EvaluateSLOs(e.db, enabled, now)
+	if err != nil {
+		logger.Warn("Failed to evaluate SLOs for job %s: %v", jobID, err)
+		return sloBurn{}
+	}
+	var burn sloBurn
+	for _, status := range statuses {
+		rate := math.Min(status.BurnRate1h, status.BurnRate5m)
+		if burn.slo == "" || rate > burn.rate {
+			burn = sloBurn{slo: status.SLO.Name, rate: rate}
+		}
+	}
+	return burn
+}
+
+// lagSeconds returns how far behind the job is. Jobs reporting per-partition time
+// lag use the worst partition; otherwise the lag is estimated by dividing it by the
+// recent consumption rate, or the time since the last progress while nothing is consumed.
+func lagSeconds(s *jobSamples) float64 {
+	if len(s.last.PartitionLags) > 0 {
+		return s.last.LagSecondsMax
+	}
+	lag := float64(s.last.CurrentLag)
+	if lag <= 0 {
+		return 0
+	}
+	if s.hasPrev {
+		elapsed := s.last.Timestamp.Sub(s.prev.Timestamp).Seconds()
+		consumed := float64(s.last.MessagesConsumed - s.prev.MessagesConsumed)
+		if elapsed > 0 && consumed > 0 {
+			return lag / (consumed / elapsed)
+		}
+	}
+	return s.last.Timestamp.Sub(s.lastProgress).Seconds()
+}
+
+// errorRate is the percentage of failed sends between the last two samples.
+func errorRate(s *jobSamples) float64 {
+	errors := float64(s.last.ErrorCount - s.prev.ErrorCount)
+	replicated := float64(s.last.MessagesReplicated - s.prev.MessagesReplicated)
+	if errors <= 0 {
+		return 0
+	}
+	return errors / (errors + replicated) * 100
+}
+
+func (e *Engine) fire(key alertKey, rule *database.AlertRule, value float64, summary string, now time.Time) {
+	silenced, err := database.IsAlertSilenced(e.db, rule.ID, key.jobID, now)
+	if err != nil {
+		logger.Warn("Failed to check alert silences: %v", err)
+	}
+	alert := &database.Alert{
+		RuleID:    rule.ID,
+		RuleName:  rule.Name,
+		RuleType:  rule.RuleType,
+		JobID:     key.jobID,
+		Severity:  rule.Severity,
+		Status:    database.AlertStatusFiring,
+		Value:     value,
+		Threshold: rule.Threshold,
+		Summary:   summary,
+		Silenced:  silenced,
+		FiredAt:   now,
+	}
+	if err := database.CreateAlert(e.db, alert); err != nil {
+		logger.Error("Failed to record alert %s for job %s: %v", rule.Name, key.jobID, err)
+		return
+	}
+	e.firing[key] = alert
+	e.recordEvent("alert_fired", "system", alert)
+	logger.Warn("Alert %s fired for job %s: %s", rule.Name, key.jobID, summary)
+	e.publish(Notification{Event: EventFiring, Alert: *alert})
+
+	if !silenced {
+		e.notify(rule.ChannelList(), Notification{Event: EventFiring, Alert: *alert})
+		e.markNotified(alert, now)
+	}
+}
+
+func (e *Engine) renotify(alert *database.Alert, rule *database.AlertRule, now time.Time) {
+	if alert.AcknowledgedAt != nil || e.repeatInterval <= 0 {
+		return
+	}
+	last := alert.FiredAt
+	if alert.LastNotifiedAt != nil {
+		last = *alert.LastNotifiedAt
+	}
+	if now.Sub(last) < e.repeatInterval {
+		return
+	}
+	silenced, err := database.IsAlertSilenced(e.db, alert.RuleID, alert.JobID, now)
+	if err != nil {
+		logger.Warn("Failed to check alert silences: %v", err)
+	}
+	if silenced != alert.Silenced {
+		alert.Silenced = silenced
+		if err := database.SetAlertSilenced(e.db, alert.ID, silenced); err != nil {
+			logger.Warn("Failed to update silence state of alert %d: %v", alert.ID, err)
+		}
+	}
+	if silenced {
+		return
+	}
+	e.notify(rule.ChannelList(), Notification{Event: EventFiring, Alert: *alert})
+	e.markNotified(alert, now)
+}
+
+func (e *Engine) resolve(key alertKey, alert *database.Alert, rule *database.AlertRule, now time.Time) {
+	delete(e.firing, key)
+	if err := database.ResolveAlert(e.db, alert.ID, now); err != nil {
+		logger.Error("Failed to resolve alert %d: %v", alert.ID, err)
+		return
+	}
+	resolvedAt := now.UTC()
+	alert.Status = database.AlertStatusResolved
+	alert.ResolvedAt = &resolvedAt
+	e.recordEvent("alert_resolved", "system", alert)
+	logger.Info("Alert %s resolved for job %s", alert.RuleName, alert.JobID)
+	e.publish(Notification{Event: EventResolved, Alert: *alert})
+
+	// Only channels that were told about the alert hear about its resolution.
+	if !e.sendResolved || alert.Silenced || alert.LastNotifiedAt == nil {
+		return
+	}
+	var channels []string
+	if rule != nil {
+		channels = rule.ChannelList()
+	} else if stored, err := database.GetAlertRule(e.db, alert.RuleID); err == nil {
+		channels = stored.ChannelList()
+	}
+	e.notify(channels, Notification{Event: EventResolved, Alert: *alert})
+}
+
+func (e *Engine) markNotified(alert *database.Alert, now time.Time) {
+	notifiedAt := now.UTC()
+	alert.LastNotifiedAt = &notifiedAt
+	if err := database.MarkAlertNotified(e.db, alert.ID, now); err != nil {
+		logger.Warn("Failed to record notification time of alert %d: %v", alert.ID, err)
+	}
+}
+
+// notify queues n for the named channels, or for every channel when names is
+// empty. Callers hold e.mu; the queue is sent by deliver once it is released.
+func (e *Engine) notify(names []string, n Notification) {
+	if len(names) == 0 {
+		names = e.channelNames
+	}
+	e.outbox = append(e.outbox, delivery{names: names, n: n})
+}
+
+// takeOutbox returns and clears the queued notifications. Callers hold e.mu.
+func (e *Engine) takeOutbox() []delivery {
+	deliveries := e.outbox
+	e.outbox = nil
+	return deliveries
+}
+
+// deliver sends queued notifications in order. Delivery failures are logged and
+// do not stop delivery to the remaining channels. Callers must not hold e.mu.
+func (e *Engine) deliver(deliveries []delivery) {
+	for _, d := range deliveries {
+		for _, name := range d.names {
+			notifier, ok := e.notifiers[name]
+			if !ok {
+				logger.Warn("Alert rule %s references unknown channel %s", d.n.Alert.RuleName, name)
+				continue
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			if err := notifier.Notify(ctx, d.n); err != nil {
+				logger.Error("Failed to send %s notification for alert %s via %s: %v", d.n.Event, d.n.Alert.RuleName, name, err)
+			}
+			cancel()
+		}
+	}
+}
+
+func (e *Engine) recordEvent(eventType, initiator string, alert *database.Alert) {
+	event := &database.OperationalEvent{
+		EventType: eventType,
+		Initiator: initiator,
+		Details: fmt.Sprintf("Alert %d (%s, %s) for job %s: %s",
+			alert.ID, alert.RuleName, alert.Severity, alert.JobID, alert.Summary),
+	}
+	if err := database.CreateOperationalEvent(e.db, event); err != nil {
+		logger.Warn("Failed to record %s event: %v", eventType, err)
+	}
+}
@@ -0,0 +1,302 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Notification events sent to channels.
+const (
+	EventFiring       = "firing"
+	EventResolved     = "resolved"
+	EventAcknowledged = "acknowledged"
+	EventTest         = "test"
+)
+
+// Notification is a single alert state change delivered to a channel.
+type Notification struct {
+	Event string         `json:"event"`
+	Alert database.Alert `json:"alert"`
+}
+
+// Subject returns a one-line description of the notification.
+func (n Notification) Subject() string {
+	return fmt.Sprintf("[%s] %s: %s", strings.ToUpper(n.Event), n.Alert.Severity, n.Alert.RuleName)
+}
+
+// Notifier delivers notifications to one channel.
+type Notifier interface {
+	Name() string
+	Type() string
+	Notify(ctx context.Context, n Notification) error
+}
+
+// NewNotifier builds the notifier for a configured channel.
+func NewNotifier(cfg config.AlertChannelConfig) (Notifier, error) {
+	timeout := 10 * time.Second
+	if cfg.Timeout != "" {
+		d, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("channel %s: invalid timeout: %v", cfg.Name, err)
+		}
+		timeout = d
+	}
+	client := &http.Client{Timeout: timeout}
+
+	switch cfg.Type {
+	case "webhook":
+		return &WebhookNotifier{name: cfg.Name, url: cfg.URL, headers: cfg.Headers, client: client}, nil
+	case "slack":
+		return &SlackNotifier{name: cfg.Name, url: cfg.URL, client: client}, nil
+	case "pagerduty":
+		url := cfg.URL
+		if url == "" {
+			url = "https://events.pagerduty.com/v2/enqueue"
+		}
+		return &PagerDutyNotifier{name: cfg.Name, url: url, routingKey: cfg.RoutingKey, client: client}, nil
+	case "email":
+		return &EmailNotifier{name: cfg.Name, smtp: cfg.SMTP, timeout: timeout}, nil
+	default:
+		return nil, fmt.Errorf("channel %s: unsupported type %q", cfg.Name, cfg.Type)
+	}
+}
+
+// WebhookNotifier posts the notification as JSON to an arbitrary URL.
+type WebhookNotifier struct {
+	name    string
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func (w *WebhookNotifier) Name() string { return w.name }
+func (w *WebhookNotifier) Type() string { return "webhook" }
+
+func (w *WebhookNotifier) Notify(ctx context.Context, n Notification) error {
+	payload := map[string]interface{}{
+		"source":  "kaf-mirror",
+		"event":   n.Event,
+		"subject": n.Subject(),
+		"alert":   n.Alert,
+	}
+	return postJSON(ctx, w.client, w.url, w.headers, payload)
+}
+
+// SlackNotifier posts a message to a Slack-compatible incoming webhook.
+type SlackNotifier struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+func (s *SlackNotifier) Name() string { return s.name }
+func (s *SlackNotifier) Type() string { return "slack" }
+
+func (s *SlackNotifier) Notify(ctx context.Context, n Notification) error {
+	color := map[string]string{
+		database.AlertSeverityCritical: "#d00000",
+		database.AlertSeverityWarning:  "#f2c744",
+		database.AlertSeverityInfo:     "#439fe0",
+	}[n.Alert.Severity]
+	if n.Event == EventResolved {
+		color = "#2eb886"
+	}
+	payload := map[string]interface{}{
+		"text": n.Subject(),
+		"attachments": []map[string]interface{}{{
+			"color": color,
+			"title": n.Alert.RuleName,
+			"text":  n.Alert.Summary,
+			"fields": []map[string]interface{}{
+				{"title": "Job", "value": n.Alert.JobID, "short": true},
+				{"title": "Severity", "value": n.Alert.Severity, "short": true},
+				{"title": "Value", "value": formatValue(n.Alert.Value), "short": true},
+				{"title": "Threshold", "value": formatValue(n.Alert.Threshold), "short": true},
+			},
+			"ts": n.Alert.FiredAt.Unix(),
+		}},
+	}
+	return postJSON(ctx, s.client, s.url, nil, payload)
+}
+
+// PagerDutyNotifier sends events to a PagerDuty Events API v2 compatible endpoint.
+// Firing, acknowledged and resolved notifications share a dedup key so that the
+// incident follows the alert lifecycle.
+type PagerDutyNotifier struct {
+	name       string
+	url        string
+	routingKey string
+	client     *http.Client
+}
+
+func (p *PagerDutyNotifier) Name() string { return p.name }
+func (p *PagerDutyNotifier) Type() string { return "pagerduty" }
+
+func (p *PagerDutyNotifier) Notify(ctx context.Context, n Notification) error {
+	action := "trigger"
+	switch n.Event {
+	case EventResolved:
+		action = "resolve"
+	case EventAcknowledged:
+		action = "acknowledge"
+	}
+	payload := map[string]interface{}{
+		"routing_key":  p.routingKey,
+		"event_action": action,
+		"dedup_key":    fmt.Sprintf("kaf-mirror-alert-%d", n.Alert.ID),
+	}
+	if action == "trigger" {
+		payload["payload"] = map[string]interface{}{
+			"summary":   n.Alert.Summary,
+			"source":    "kaf-mirror",
+			"severity":  n.Alert.Severity,
+			"timestamp": n.Alert.FiredAt.UTC().Format(time.RFC3339),
+			"component": n.Alert.JobID,
+			"group":     n.Alert.RuleType,
+			"custom_details": map[string]interface{}{
+				"rule":      n.Alert.RuleName,
+				"value":     n.Alert.Value,
+				"threshold": n.Alert.Threshold,
+			},
+		}
+	}
+	return postJSON(ctx, p.client, p.url, nil, payload)
+}
+
+// EmailNotifier sends plain-text mails through an SMTP server. STARTTLS is used
+// whenever the server offers it.
+type EmailNotifier struct {
+	name    string
+	smtp    config.AlertSMTPConfig
+	timeout time.Duration
+}
+
+func (e *EmailNotifier) Name() string { return e.name }
+func (e *EmailNotifier) Type() string { return "email" }
+
+func (e *EmailNotifier) Notify(ctx context.Context, n Notification) error {
+	port := e.smtp.Port
+	if port == 0 {
+		port = 25
+	}
+	addr := net.JoinHostPort(e.smtp.Host, strconv.Itoa(port))
+	dialer := net.Dialer{Timeout: e.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(e.timeout))
+
+	client, err := smtp.NewClient(conn, e.smtp.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: e.smtp.Host}); err != nil {
+			return err
+		}
+	}
+	if e.smtp.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", e.smtp.Username, e.smtp.Password, e.smtp.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(e.smtp.From); err != nil {
+		return err
+	}
+	for _, rcpt := range e.smtp.To {
+		if err := client.Rcpt(rcpt); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(e.message(n)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (e *EmailNotifier) message(n Notification) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", e.smtp.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(e.smtp.To, ", "))
+	fmt.Fprintf(&b, "Subject: kaf-mirror %s\r\n", n.Subject())
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	fmt.Fprintf(&b, "%s\r\n\r\n", n.Alert.Summary)
+	fmt.Fprintf(&b, "Rule:      %s (%s)\r\n", n.Alert.RuleName, n.Alert.RuleType)
+	fmt.Fprintf(&b, "Job:       %s\r\n", n.Alert.JobID)
+	fmt.Fprintf(&b, "Severity:  %s\r\n", n.Alert.Severity)
+	fmt.Fprintf(&b, "Value:     %s\r\n", formatValue(n.Alert.Value))
+	fmt.Fprintf(&b, "Threshold: %s\r\n", formatValue(n.Alert.Threshold))
+	fmt.Fprintf(&b, "Fired at:  %s\r\n", n.Alert.FiredAt.UTC().Format(time.RFC3339))
+	if n.Alert.ResolvedAt != nil {
+		fmt.Fprintf(&b, "Resolved:  %s\r\n", n.Alert.ResolvedAt.UTC().Format(time.RFC3339))
+	}
+	if n.Alert.AcknowledgedBy != nil {
+		fmt.Fprintf(&b, "Acked by:  %s\r\n", *n.Alert.AcknowledgedBy)
+	}
+	return b.Bytes()
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %d: %s", url, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -14,58 +14,162 @@ package analysis
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
 
 // LogEntry represents a single parsed log entry.
 type LogEntry struct {
-	Timestamp time.Time
-	Level     string
-	Component string
-	Message   string
-	AICategory string
+	Timestamp     time.Time
+	Level         string
+	Component     string
+	Message       string
+	AICategory    string
 	AISubcategory string
-	JobID     string
+	JobID         string
+	Cluster       string
+	Topic         string
+	Partition     *int32
 }
 
-// GetLogsForJob retrieves logs for a specific jobID from the log file.
+// jsonLogRecord is a record of the JSON log format.
+type jsonLogRecord struct {
+	Time          time.Time `json:"time"`
+	Level         string    `json:"level"`
+	Msg           string    `json:"msg"`
+	JobID         string    `json:"job_id"`
+	Component     string    `json:"component"`
+	Cluster       string    `json:"cluster"`
+	Topic         string    `json:"topic"`
+	Partition     *int32    `json:"partition"`
+	AICategory    string    `json:"ai_category"`
+	AISubcategory string    `json:"ai_subcategory"`
+}
+
+// GetLogsForJob retrieves logs for a specific jobID written since the given time.
+// Every kaf-mirror log file in logDir that was modified since then is read, so
+// records are found across daily files and rotated backups; text and JSON lines
+// may be mixed. Entries are returned in time order.
 func GetLogsForJob(logDir, jobID string, since time.Time) ([]LogEntry, error) {
 	var entries []LogEntry
 
-	// Assume log file is named with the current date
-	timestamp := time.Now().Format("2006-01-02")
-	logFile := filepath.Join(logDir, fmt.Sprintf("kaf-mirror-%s.log", timestamp))
+	files, err := logFilesSince(logDir, since)
+	if err != nil {
+		return nil, err
+	}
+	for _, logFile := range files {
+		fileEntries, err := readJobLogs(logFile, jobID, since)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	return entries, nil
+}
+
+// logFilesSince lists kaf-mirror log files modified since the given time,
+// oldest first. Compressed backups are skipped.
+func logFilesSince(logDir string, since time.Time) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(logDir, "kaf-mirror*.log*"))
+	if err != nil {
+		return nil, err
+	}
+	type logFile struct {
+		path    string
+		modTime time.Time
+	}
+	var files []logFile
+	for _, path := range matches {
+		if strings.HasSuffix(path, ".gz") {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() || info.ModTime().Before(since) {
+			continue
+		}
+		files = append(files, logFile{path: path, modTime: info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+	paths := make([]string, 0, len(files))
+	for _, f := range files {
+		paths = append(paths, f.path)
+	}
+	return paths, nil
+}
+
+func readJobLogs(logFile, jobID string, since time.Time) ([]LogEntry, error) {
+	var entries []LogEntry
 
 	file, err := os.Open(logFile)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return entries, nil // No log file for today yet
+			return entries, nil // Rotated away while listing
 		}
 		return nil, err
 	}
 	defer file.Close()
 
 	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
 	for scanner.Scan() {
 		line := scanner.Text()
-		// Include both AI-tagged logs for the job and regular job logs
-		if strings.Contains(line, fmt.Sprintf("[job:%s]", jobID)) || 
-		   (strings.Contains(line, "[AI:") && strings.Contains(line, jobID)) {
-			entry, err := parseLogLine(line)
-			if err == nil && entry.Timestamp.After(since) {
-				entries = append(entries, entry)
-			}
+		if !strings.Contains(line, jobID) {
+			continue
+		}
+		var entry LogEntry
+		if strings.HasPrefix(line, "{") {
+			entry, err = parseJSONLogLine(line)
+		} else {
+			entry, err = parseLogLine(line)
+		}
+		if err != nil || !entry.Timestamp.After(since) {
+			continue
+		}
+		// Include both the job's own logs and AI-tagged logs mentioning it
+		if entry.JobID == jobID || (entry.AICategory != "" && strings.Contains(entry.Message, jobID)) {
+			entries = append(entries, entry)
 		}
 	}
 
 	return entries, scanner.Err()
 }
 
-// parseLogLine parses a single line from the log file.
+// parseJSONLogLine parses a record of the JSON log format.
+func parseJSONLogLine(line string) (LogEntry, error) {
+	var record jsonLogRecord
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		return LogEntry{}, err
+	}
+	if record.Time.IsZero() {
+		return LogEntry{}, fmt.Errorf("invalid log record - no time")
+	}
+	return LogEntry{
+		Timestamp:     record.Time,
+		Level:         record.Level,
+		Component:     record.Component,
+		Message:       record.Msg,
+		AICategory:    record.AICategory,
+		AISubcategory: record.AISubcategory,
+		JobID:         record.JobID,
+		Cluster:       record.Cluster,
+		Topic:         record.Topic,
+		Partition:     record.Partition,
+	}, nil
+}
+
+// parseLogLine parses a line of the text log format:
+// [timestamp] LEVEL [caller] [AI:category:subcategory] [job:id] [component:name] ... message
 func parseLogLine(line string) (LogEntry, error) {
 	var entry LogEntry
 
@@ -73,14 +177,14 @@ func parseLogLine(line string) (LogEntry, error) {
 	if !strings.HasPrefix(line, "[") {
 		return entry, fmt.Errorf("invalid log line format - no timestamp")
 	}
-	
+
 	tsEnd := strings.Index(line, "]")
 	if tsEnd == -1 {
 		return entry, fmt.Errorf("invalid log line format - unclosed timestamp")
 	}
-	
+
 	tsStr := line[1:tsEnd]
-	ts, err := time.Parse("2006-01-02 15:04:05.000", tsStr)
+	ts, err := time.ParseInLocation("2006-01-02 15:04:05.000", tsStr, time.Local)
 	if err != nil {
 		return entry, err
 	}
@@ -89,7 +193,7 @@ func parseLogLine(line string) (LogEntry, error) {
 	// Parse level - after timestamp
 	remaining := line[tsEnd+1:]
 	remaining = strings.TrimSpace(remaining)
-	
+
 	levelEnd := strings.Index(remaining, " ")
 	if levelEnd == -1 {
 		return entry, fmt.Errorf("invalid log line format - no level")
@@ -97,50 +201,34 @@ func parseLogLine(line string) (LogEntry, error) {
 	entry.Level = remaining[:levelEnd]
 	remaining = strings.TrimSpace(remaining[levelEnd:])
 
-	// Parse AI tags if present: [AI:category:subcategory]
-	if strings.HasPrefix(remaining, "[") && strings.Contains(remaining, "[AI:") {
-		aiStart := strings.Index(remaining, "[AI:") + 4
-		aiEnd := strings.Index(remaining[aiStart:], "]")
-		if aiEnd != -1 {
-			aiEnd += aiStart
-			aiTag := remaining[aiStart:aiEnd]
-			aiParts := strings.Split(aiTag, ":")
-			if len(aiParts) >= 2 {
-				entry.AICategory = aiParts[0]
-				entry.AISubcategory = aiParts[1]
-			}
-			remaining = strings.TrimSpace(remaining[aiEnd+1:])
-		}
-	}
-
-	// Parse job ID if present: [job:jobID]
-	if strings.Contains(remaining, "[job:") {
-		jobStart := strings.Index(remaining, "[job:") + 5
-		jobEnd := strings.Index(remaining[jobStart:], "]")
-		if jobEnd != -1 {
-			jobEnd += jobStart
-			entry.JobID = remaining[jobStart:jobEnd]
-			remaining = strings.TrimSpace(remaining[jobEnd+1:])
+	// Consume the leading [key:value] tags; the caller tag is skipped.
+	for strings.HasPrefix(remaining, "[") {
+		tagEnd := strings.Index(remaining, "]")
+		if tagEnd == -1 {
+			break
 		}
-	}
-
-	// Parse component if present (legacy format)
-	if strings.Contains(remaining, "[component:") {
-		compStart := strings.Index(remaining, "[component:") + 11
-		compEnd := strings.Index(remaining[compStart:], "]")
-		if compEnd != -1 {
-			compEnd += compStart
-			entry.Component = remaining[compStart:compEnd]
-			remaining = strings.TrimSpace(remaining[compEnd+1:])
+		key, value, ok := strings.Cut(remaining[1:tagEnd], ":")
+		if !ok || strings.Contains(key, " ") {
+			break
 		}
-	}
-
-	// Skip caller info [caller.go:123] and get message
-	if strings.HasPrefix(remaining, "[") {
-		callerEnd := strings.Index(remaining, "]")
-		if callerEnd != -1 {
-			remaining = strings.TrimSpace(remaining[callerEnd+1:])
+		switch key {
+		case "AI":
+			entry.AICategory, entry.AISubcategory, _ = strings.Cut(value, ":")
+		case "job":
+			entry.JobID = value
+		case "component":
+			entry.Component = value
+		case "cluster":
+			entry.Cluster = value
+		case "topic":
+			entry.Topic = value
+		case "partition":
+			if p, err := strconv.ParseInt(value, 10, 32); err == nil {
+				partition := int32(p)
+				entry.Partition = &partition
+			}
 		}
+		remaining = strings.TrimSpace(remaining[tagEnd+1:])
 	}
 
 	entry.Message = remaining
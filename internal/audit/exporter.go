@@ -0,0 +1,109 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/pkg/logger"
+	"sync"
+	"sync/atomic"
+)
+
+const exporterQueueSize = 1024
+
+// Exporter streams committed operational events to the configured sinks.
+// Events are queued so that a slow sink never blocks the request that produced them;
+// when the queue is full the event is dropped and counted. The database remains the
+// source of truth and can be re-exported.
+type Exporter struct {
+	sinks   []Sink
+	queue   chan database.OperationalEvent
+	dropped atomic.Int64
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// NewExporter builds the sinks enabled in cfg. It returns nil when no sink is enabled.
+func NewExporter(cfg config.AuditConfig) (*Exporter, error) {
+	var sinks []Sink
+	if cfg.File.Enabled {
+		sink, err := NewFileSink(cfg.File.Path)
+		if err != nil {
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	if cfg.Syslog.Enabled {
+		sink, err := NewSyslogSink(cfg.Syslog.Network, cfg.Syslog.Address, cfg.Syslog.Format, cfg.Syslog.Facility)
+		if err != nil {
+			for _, s := range sinks {
+				s.Close()
+			}
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	if len(sinks) == 0 {
+		return nil, nil
+	}
+	return NewExporterWithSinks(sinks...), nil
+}
+
+// NewExporterWithSinks starts an exporter that writes to the given sinks.
+func NewExporterWithSinks(sinks ...Sink) *Exporter {
+	e := &Exporter{
+		sinks: sinks,
+		queue: make(chan database.OperationalEvent, exporterQueueSize),
+	}
+	e.wg.Add(1)
+	go e.run()
+	return e
+}
+
+// Enqueue queues an event for export without blocking.
+func (e *Exporter) Enqueue(event database.OperationalEvent) {
+	select {
+	case e.queue <- event:
+	default:
+		if e.dropped.Add(1)%100 == 1 {
+			logger.Warn("Audit export queue full, dropped %d events so far", e.dropped.Load())
+		}
+	}
+}
+
+// Dropped returns the number of events dropped because the queue was full.
+func (e *Exporter) Dropped() int64 {
+	return e.dropped.Load()
+}
+
+func (e *Exporter) run() {
+	defer e.wg.Done()
+	for event := range e.queue {
+		for _, sink := range e.sinks {
+			if err := sink.Write(event); err != nil {
+				logger.Error("Failed to export audit event %d: %v", event.ID, err)
+			}
+		}
+	}
+}
+
+// Close drains the queue and closes all sinks.
+func (e *Exporter) Close() {
+	e.once.Do(func() {
+		close(e.queue)
+		e.wg.Wait()
+		for _, sink := range e.sinks {
+			sink.Close()
+		}
+	})
+}
@@ -0,0 +1,212 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"kaf-mirror/internal/database"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sink receives committed operational events for external storage.
+type Sink interface {
+	Write(event database.OperationalEvent) error
+	Close() error
+}
+
+// FileSink appends events as JSON lines to a local file.
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink opens (or creates) the JSONL file at path for appending.
+func NewFileSink(path string) (*FileSink, error) {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, fmt.Errorf("failed to create audit directory: %v", err)
+		}
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file: %v", err)
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Write appends the event as a single JSON line.
+func (s *FileSink) Write(event database.OperationalEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the underlying file.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+const (
+	// FormatCEF renders events in ArcSight Common Event Format.
+	FormatCEF = "cef"
+	// FormatJSON renders events as JSON.
+	FormatJSON = "json"
+)
+
+// SyslogSink sends events to a syslog receiver as RFC 5424 messages.
+type SyslogSink struct {
+	mu       sync.Mutex
+	network  string
+	address  string
+	format   string
+	facility int
+	hostname string
+	conn     net.Conn
+}
+
+// NewSyslogSink connects to a syslog receiver. The connection is re-established on write errors.
+func NewSyslogSink(network, address, format string, facility int) (*SyslogSink, error) {
+	if format != FormatCEF && format != FormatJSON {
+		return nil, fmt.Errorf("unsupported syslog format %q", format)
+	}
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+	s := &SyslogSink{network: network, address: address, format: format, facility: facility, hostname: hostname}
+	if err := s.dial(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *SyslogSink) dial() error {
+	conn, err := net.DialTimeout(s.network, s.address, 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("failed to connect to syslog %s://%s: %v", s.network, s.address, err)
+	}
+	s.conn = conn
+	return nil
+}
+
+// Write sends a single event. Stream transports use octet-counting framing (RFC 6587).
+func (s *SyslogSink) Write(event database.OperationalEvent) error {
+	msg, err := s.format5424(event)
+	if err != nil {
+		return err
+	}
+	if s.network == "tcp" || s.network == "tcp4" || s.network == "tcp6" {
+		msg = fmt.Sprintf("%d %s", len(msg), msg)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		if err := s.dial(); err != nil {
+			return err
+		}
+	}
+	if _, err := s.conn.Write([]byte(msg)); err != nil {
+		s.conn.Close()
+		s.conn = nil
+		return err
+	}
+	return nil
+}
+
+// Close closes the syslog connection.
+func (s *SyslogSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
+
+func (s *SyslogSink) format5424(event database.OperationalEvent) (string, error) {
+	var body string
+	if s.format == FormatJSON {
+		b, err := json.Marshal(event)
+		if err != nil {
+			return "", err
+		}
+		body = string(b)
+	} else {
+		body = FormatCEFEvent(event)
+	}
+	severity := eventSeverity(event.EventType)
+	pri := s.facility*8 + syslogSeverity(severity)
+	return fmt.Sprintf("<%d>1 %s %s kaf-mirror - %s - %s",
+		pri, event.Timestamp.UTC().Format(time.RFC3339Nano), s.hostname, event.EventType, body), nil
+}
+
+// FormatCEFEvent renders an event as a CEF:0 record.
+func FormatCEFEvent(event database.OperationalEvent) string {
+	ext := []string{
+		"rt=" + cefExtension(fmt.Sprintf("%d", event.Timestamp.UnixMilli())),
+		"suser=" + cefExtension(event.Initiator),
+		"externalId=" + cefExtension(fmt.Sprintf("%d", event.ID)),
+		"msg=" + cefExtension(event.Details),
+		"cs1Label=hash cs1=" + cefExtension(event.Hash),
+		"cs2Label=prevHash cs2=" + cefExtension(event.PrevHash),
+	}
+	return fmt.Sprintf("CEF:0|Scalytics|kaf-mirror|1|%s|%s|%d|%s",
+		cefHeader(event.EventType), cefHeader(event.EventType), eventSeverity(event.EventType), strings.Join(ext, " "))
+}
+
+func cefHeader(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ").Replace(s)
+}
+
+func cefExtension(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`).Replace(s)
+}
+
+// eventSeverity returns a CEF severity (0-10) for an event type.
+func eventSeverity(eventType string) int {
+	switch {
+	case strings.Contains(eventType, "fail"), strings.Contains(eventType, "denied"), strings.Contains(eventType, "error"):
+		return 7
+	case strings.Contains(eventType, "delete"), strings.Contains(eventType, "revoke"), strings.Contains(eventType, "audit_pruned"):
+		return 5
+	default:
+		return 3
+	}
+}
+
+// syslogSeverity maps a CEF severity to a syslog severity.
+func syslogSeverity(cef int) int {
+	switch {
+	case cef >= 7:
+		return 4 // warning
+	case cef >= 5:
+		return 5 // notice
+	default:
+		return 6 // informational
+	}
+}
@@ -0,0 +1,365 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/tls"
+	"database/sql"
+	"errors"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/pkg/logger"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/jmoiron/sqlx"
+)
+
+var (
+	// ErrInvalidCredentials is returned when the directory rejects the user's password.
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrUserNotFound is returned when the user search yields no entry.
+	ErrUserNotFound = errors.New("user not found in directory")
+	// ErrNoMappedRole is returned when none of the user's groups map to a kaf-mirror role.
+	ErrNoMappedRole = errors.New("no kaf-mirror role mapped for user's groups")
+	// ErrLocalAccount is returned when a directory login collides with a local account.
+	ErrLocalAccount = errors.New("username belongs to a local account")
+	// ErrAccountDisabled is returned when a directory user's account was disabled locally.
+	ErrAccountDisabled = errors.New("account is disabled")
+)
+
+// roleRank orders the built-in roles so the most privileged mapped group wins.
+var roleRank = map[string]int{
+	"monitoring": 1,
+	"compliance": 2,
+	"operator":   3,
+	"admin":      4,
+}
+
+// LDAPConn is the subset of an LDAP connection used by the authenticator.
+type LDAPConn interface {
+	Bind(username, password string) error
+	Search(req *ldap.SearchRequest) (*ldap.SearchResult, error)
+	Close() error
+}
+
+var ldapDialer = dialLDAP
+
+// SetLDAPDialerForTest overrides the LDAP dialer, allowing tests to use an in-process directory.
+func SetLDAPDialerForTest(dialer func(cfg config.LDAPConfig) (LDAPConn, error)) {
+	if dialer == nil {
+		ldapDialer = dialLDAP
+		return
+	}
+	ldapDialer = dialer
+}
+
+func dialLDAP(cfg config.LDAPConfig) (LDAPConn, error) {
+	timeout, err := time.ParseDuration(cfg.Timeout)
+	if err != nil || timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+
+	conn, err := ldap.DialURL(cfg.URL,
+		ldap.DialWithDialer(&net.Dialer{Timeout: timeout}),
+		ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %v", cfg.URL, err)
+	}
+	conn.SetTimeout(timeout)
+
+	if cfg.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to start TLS: %v", err)
+		}
+	}
+	return conn, nil
+}
+
+// LDAPUser is a directory entry resolved during authentication or sync.
+type LDAPUser struct {
+	Username string   `json:"username"`
+	DN       string   `json:"dn"`
+	Groups   []string `json:"groups"`
+	Role     string   `json:"role"`
+}
+
+// LDAPSyncResult summarizes a directory synchronization run.
+type LDAPSyncResult struct {
+	Checked     int `json:"checked"`
+	Disabled    int `json:"disabled"`
+	Enabled     int `json:"enabled"`
+	RoleUpdates int `json:"role_updates"`
+}
+
+// LDAPAuthenticator authenticates users against an LDAP / Active Directory server.
+type LDAPAuthenticator struct {
+	cfg config.LDAPConfig
+}
+
+// NewLDAPAuthenticator creates a new LDAP authenticator.
+func NewLDAPAuthenticator(cfg config.LDAPConfig) *LDAPAuthenticator {
+	if cfg.UserFilter == "" {
+		cfg.UserFilter = "(&(objectClass=person)(uid=%s))"
+	}
+	if cfg.GroupAttribute == "" {
+		cfg.GroupAttribute = "memberOf"
+	}
+	return &LDAPAuthenticator{cfg: cfg}
+}
+
+// connect dials the directory and binds with the service account.
+func (a *LDAPAuthenticator) connect() (LDAPConn, error) {
+	conn, err := ldapDialer(a.cfg)
+	if err != nil {
+		return nil, err
+	}
+	if a.cfg.BindDN != "" {
+		if err := conn.Bind(a.cfg.BindDN, a.cfg.BindPassword); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("service account bind failed: %v", err)
+		}
+	}
+	return conn, nil
+}
+
+// TestConnection verifies connectivity, the service account bind and the base DN.
+func (a *LDAPAuthenticator) TestConnection() error {
+	conn, err := a.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	req := ldap.NewSearchRequest(a.cfg.BaseDN, ldap.ScopeBaseObject, ldap.NeverDerefAliases,
+		1, 0, false, "(objectClass=*)", []string{"dn"}, nil)
+	if _, err := conn.Search(req); err != nil {
+		return fmt.Errorf("failed to read base DN %s: %v", a.cfg.BaseDN, err)
+	}
+	return nil
+}
+
+// Authenticate verifies the user's password by binding as the user and resolves their role.
+func (a *LDAPAuthenticator) Authenticate(username, password string) (*LDAPUser, error) {
+	// An empty password would be an unauthenticated bind, which most servers accept.
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := a.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	user, err := a.findUser(conn, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.Bind(user.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("user bind failed: %v", err)
+	}
+
+	if user.Role == "" {
+		return user, ErrNoMappedRole
+	}
+	return user, nil
+}
+
+// Lookup resolves a user's directory entry and role using the service account.
+func (a *LDAPAuthenticator) Lookup(username string) (*LDAPUser, error) {
+	conn, err := a.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return a.findUser(conn, username)
+}
+
+func (a *LDAPAuthenticator) findUser(conn LDAPConn, username string) (*LDAPUser, error) {
+	filter := fmt.Sprintf(a.cfg.UserFilter, ldap.EscapeFilter(username))
+	req := ldap.NewSearchRequest(a.cfg.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		2, 0, false, filter, []string{"dn", a.cfg.GroupAttribute}, nil)
+
+	result, err := conn.Search(req)
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+			return nil, fmt.Errorf("user search for %q matched more than one entry", username)
+		}
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("user search failed: %v", err)
+	}
+	if len(result.Entries) == 0 {
+		return nil, ErrUserNotFound
+	}
+	if len(result.Entries) > 1 {
+		return nil, fmt.Errorf("user search for %q matched more than one entry", username)
+	}
+
+	entry := result.Entries[0]
+	groups := entry.GetAttributeValues(a.cfg.GroupAttribute)
+	return &LDAPUser{
+		Username: username,
+		DN:       entry.DN,
+		Groups:   groups,
+		Role:     a.ResolveRole(groups),
+	}, nil
+}
+
+// ResolveRole maps group DNs to the most privileged configured role.
+// Group DNs are compared case-insensitively. The default role applies when no group matches.
+func (a *LDAPAuthenticator) ResolveRole(groups []string) string {
+	mapping := make(map[string]string, len(a.cfg.GroupRoleMapping))
+	for groupDN, role := range a.cfg.GroupRoleMapping {
+		mapping[normalizeDN(groupDN)] = role
+	}
+
+	role := ""
+	for _, group := range groups {
+		mapped, ok := mapping[normalizeDN(group)]
+		if !ok {
+			continue
+		}
+		if _, known := roleRank[mapped]; !known {
+			logger.Warn("LDAP group %s maps to unknown role %q, ignoring", group, mapped)
+			continue
+		}
+		if roleRank[mapped] > roleRank[role] {
+			role = mapped
+		}
+	}
+	if role == "" {
+		role = a.cfg.DefaultRole
+	}
+	return role
+}
+
+func normalizeDN(dn string) string {
+	parts := strings.Split(dn, ",")
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+	return strings.ToLower(strings.Join(parts, ","))
+}
+
+// Login authenticates a directory user and provisions or updates the matching local account.
+func (a *LDAPAuthenticator) Login(db *sqlx.DB, username, password string) (*database.User, error) {
+	ldapUser, err := a.Authenticate(username, password)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := database.GetUserByUsername(db, username)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		user, err = database.CreateExternalUser(db, username, database.AuthSourceLDAP)
+		if err != nil {
+			return nil, fmt.Errorf("failed to provision directory user: %v", err)
+		}
+		logger.Info("Provisioned LDAP user %s with role %s", username, ldapUser.Role)
+	} else if user.AuthSource != database.AuthSourceLDAP {
+		return nil, ErrLocalAccount
+	}
+
+	if user.Disabled {
+		if user.DisabledReason != database.DisabledByDirectory {
+			return nil, ErrAccountDisabled
+		}
+		if err := database.SetUserDisabled(db, user.ID, false, ""); err != nil {
+			return nil, err
+		}
+		user.Disabled = false
+		user.DisabledReason = ""
+	}
+
+	if err := database.SetUserRole(db, user.ID, ldapUser.Role); err != nil {
+		return nil, fmt.Errorf("failed to assign role: %v", err)
+	}
+	return user, nil
+}
+
+// SyncUsers reconciles directory-provisioned users with the directory.
+// Users removed from the directory or without a mapped role are disabled and their tokens revoked.
+func (a *LDAPAuthenticator) SyncUsers(db *sqlx.DB) (*LDAPSyncResult, error) {
+	users, err := database.ListUsersByAuthSource(db, database.AuthSourceLDAP)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list directory users: %v", err)
+	}
+
+	conn, err := a.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	result := &LDAPSyncResult{}
+	for _, user := range users {
+		result.Checked++
+
+		ldapUser, err := a.findUser(conn, user.Username)
+		if err != nil && !errors.Is(err, ErrUserNotFound) {
+			return result, err
+		}
+
+		if ldapUser == nil || ldapUser.Role == "" {
+			if user.Disabled {
+				continue
+			}
+			if err := database.SetUserDisabled(db, user.ID, true, database.DisabledByDirectory); err != nil {
+				return result, err
+			}
+			if err := database.RevokeAllUserTokens(db, user.ID); err != nil {
+				return result, err
+			}
+			result.Disabled++
+			logger.Info("Disabled LDAP user %s: no longer present or mapped in directory", user.Username)
+			continue
+		}
+
+		if user.Disabled {
+			if user.DisabledReason != database.DisabledByDirectory {
+				continue // disabled locally, left alone
+			}
+			if err := database.SetUserDisabled(db, user.ID, false, ""); err != nil {
+				return result, err
+			}
+			result.Enabled++
+		}
+
+		currentRole, err := database.GetUserRole(db, user.ID)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return result, err
+		}
+		if currentRole != ldapUser.Role {
+			if err := database.SetUserRole(db, user.ID, ldapUser.Role); err != nil {
+				return result, err
+			}
+			result.RoleUpdates++
+			logger.Info("Updated role of LDAP user %s from %q to %q", user.Username, currentRole, ldapUser.Role)
+		}
+	}
+
+	return result, nil
+}
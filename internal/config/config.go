@@ -14,7 +14,11 @@ package config
 import (
 	"fmt"
 	"kaf-mirror/pkg/utils"
+	"net/url"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -32,6 +36,10 @@ type Config struct {
 	AI          AIConfig                 `mapstructure:"ai"`
 	Monitoring  MonitoringConfig         `mapstructure:"monitoring"`
 	Compliance  ComplianceConfig         `mapstructure:"compliance"`
+	Auth        AuthConfig               `mapstructure:"auth"`
+	Audit       AuditConfig              `mapstructure:"audit"`
+	Alerting    AlertingConfig           `mapstructure:"alerting"`
+	Webhooks    WebhooksConfig           `mapstructure:"webhooks"`
 }
 
 // ServerConfig defines server settings
@@ -59,19 +67,33 @@ type DatabaseConfig struct {
 
 type LoggingConfig struct {
 	Level      string `mapstructure:"level"`
+	Format     string `mapstructure:"format"` // "text" (default) or "json"
 	File       string `mapstructure:"file"`
 	MaxSize    int    `mapstructure:"max_size"`
 	MaxBackups int    `mapstructure:"max_backups"`
 	MaxAge     int    `mapstructure:"max_age"`
 	Console    bool   `mapstructure:"console"`
+	// JobBufferSize is how many recent log records are kept in memory per job
+	// for the job logs API. Defaults to 1000.
+	JobBufferSize int `mapstructure:"job_buffer_size"`
 }
 
 // ClusterConfig defines Kafka cluster connection details
 type ClusterConfig struct {
-	Provider  string         `mapstructure:"provider"`
-	ClusterID string         `mapstructure:"cluster_id"`
-	Brokers   string         `mapstructure:"brokers"`
-	Security  SecurityConfig `mapstructure:"security"`
+	Provider       string               `mapstructure:"provider"`
+	ClusterID      string               `mapstructure:"cluster_id"`
+	Brokers        string               `mapstructure:"brokers"`
+	Security       SecurityConfig       `mapstructure:"security"`
+	SchemaRegistry SchemaRegistryConfig `mapstructure:"schema_registry"`
+}
+
+// SchemaRegistryConfig points at the Confluent-compatible Schema Registry of a
+// cluster. When both clusters of a job have one, the schemas of mirrored records
+// are copied to the target registry and the schema IDs in the records rewritten.
+type SchemaRegistryConfig struct {
+	URL      string `mapstructure:"url"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
 }
 
 // SecurityConfig defines security settings for Kafka connections
@@ -91,18 +113,348 @@ type SecurityConfig struct {
 
 // ReplicationConfig defines replication parameters
 type ReplicationConfig struct {
-	BatchSize              int    `mapstructure:"batch_size"`
-	Parallelism            int    `mapstructure:"parallelism"`
-	Compression            string `mapstructure:"compression"`
-	JobID                  string `mapstructure:"job_id"`
-	TopicDiscoveryInterval string `mapstructure:"topic_discovery_interval"`
+	BatchSize              int                       `mapstructure:"batch_size"`
+	Parallelism            int                       `mapstructure:"parallelism"`
+	Compression            string                    `mapstructure:"compression"`
+	JobID                  string                    `mapstructure:"job_id"`
+	TopicDiscoveryInterval string                    `mapstructure:"topic_discovery_interval"`
+	TopicConfigSync        TopicConfigSyncConfig     `mapstructure:"topic_config_sync"`
+	SourceTopicDeletion    SourceTopicDeletionConfig `mapstructure:"source_topic_deletion"`
+	ACLSync                ACLSyncConfig             `mapstructure:"acl_sync"`
+	Dedup                  DedupConfig               `mapstructure:"dedup"`
+	ErrorPolicy            ErrorPolicy               `mapstructure:"error_policy"`
+	StartPosition          StartPosition             `mapstructure:"start_position"`
+	// Backfill bounds a backfill job to these ranges. It is set by the job manager
+	// and not read from the configuration file.
+	Backfill []BackfillRange `mapstructure:"-"`
+}
+
+// BackfillRange is a bounded offset range of one source partition. EndOffset is
+// exclusive; copying resumes at NextOffset.
+type BackfillRange struct {
+	Topic       string
+	Partition   int32
+	StartOffset int64
+	EndOffset   int64
+	NextOffset  int64
+}
+
+// ACLSyncConfig controls how client ACLs and quotas are copied from the source to the
+// target cluster of a job. Topic ACLs follow the job's topic mappings; group ACLs are
+// copied for consumer groups matching the Groups glob patterns. Principals are rewritten
+// by the first matching rule and kept as they are otherwise.
+type ACLSyncConfig struct {
+	Groups            []string               `mapstructure:"groups"`
+	PrincipalRewrites []PrincipalRewriteRule `mapstructure:"principal_rewrites"`
+	SyncQuotas        bool                   `mapstructure:"sync_quotas"`
+}
+
+// PrincipalRewriteRule rewrites principals matching the Match regular expression to
+// Replace, which may refer to capture groups as $1.
+type PrincipalRewriteRule struct {
+	Match   string `mapstructure:"match" json:"match"`
+	Replace string `mapstructure:"replace" json:"replace"`
+}
+
+// DedupConfig enables duplicate suppression for clusters where transactions are not
+// available. Every mirrored record carries a provenance header with its source offset;
+// on start the last TailRecords records of each target partition are read and source
+// records already present there are skipped instead of being produced again.
+type DedupConfig struct {
+	Enabled     bool `mapstructure:"enabled" json:"enabled"`
+	TailRecords int  `mapstructure:"tail_records" json:"tail_records"`
+}
+
+// Error policy actions for records the target cluster rejects.
+const (
+	ErrorActionSkip       = "skip"        // log the error and move on
+	ErrorActionRetry      = "retry"       // produce again with backoff, then apply OnExhausted
+	ErrorActionDeadLetter = "dead_letter" // write the record to the dead-letter topic
+	ErrorActionHalt       = "halt"        // fail the job
+)
+
+// Produce error classes an error policy can set actions for.
+const (
+	ErrorClassRecordTooLarge = "record_too_large"
+	ErrorClassAuthorization  = "authorization"
+	ErrorClassUnknownTopic   = "unknown_topic"
+	ErrorClassInvalidRecord  = "invalid_record"
+	ErrorClassTimeout        = "timeout"
+	ErrorClassSchema         = "schema" // schema IDs could not be remapped to the target registry
+	ErrorClassOther          = "other"
+)
+
+// ErrorClasses lists every produce error class.
+var ErrorClasses = []string{
+	ErrorClassRecordTooLarge,
+	ErrorClassAuthorization,
+	ErrorClassUnknownTopic,
+	ErrorClassInvalidRecord,
+	ErrorClassTimeout,
+	ErrorClassSchema,
+	ErrorClassOther,
+}
+
+// ErrorPolicy decides what happens to a record the target cluster rejects. Action
+// applies to every error class without an entry in Classes. Retried records are
+// produced again after RetryBackoff, doubling up to a minute, and OnExhausted
+// applies once MaxRetries retries failed. Later records of the source partition
+// wait until the retried record is settled, so the target keeps the source order;
+// only records already sent when the first attempt failed can land ahead of it.
+// Source offsets are committed only past settled records, so a halted job resumes
+// at the failed record. Without a policy failed records are skipped and the job
+// fails after 100 consecutive errors.
+type ErrorPolicy struct {
+	Action       string            `mapstructure:"action" json:"action,omitempty"`
+	Classes      map[string]string `mapstructure:"classes" json:"classes,omitempty"`
+	MaxRetries   int               `mapstructure:"max_retries" json:"max_retries,omitempty"`
+	RetryBackoff string            `mapstructure:"retry_backoff" json:"retry_backoff,omitempty"`
+	OnExhausted  string            `mapstructure:"on_exhausted" json:"on_exhausted,omitempty"`
+	DeadLetter   DeadLetterConfig  `mapstructure:"dead_letter" json:"dead_letter"`
+}
+
+// DeadLetterConfig says where dead-lettered records are written. Cluster is
+// "source" or "target" and defaults to the target; Topic defaults to
+// kaf-mirror-dlq-<job id>.
+type DeadLetterConfig struct {
+	Cluster string `mapstructure:"cluster" json:"cluster,omitempty"`
+	Topic   string `mapstructure:"topic" json:"topic,omitempty"`
+}
+
+// IsSet reports whether any action was configured.
+func (p ErrorPolicy) IsSet() bool {
+	return p.Action != "" || len(p.Classes) > 0
+}
+
+// ActionFor returns the action for an error class.
+func (p ErrorPolicy) ActionFor(class string) string {
+	if action, ok := p.Classes[class]; ok && action != "" {
+		return action
+	}
+	if p.Action != "" {
+		return p.Action
+	}
+	return ErrorActionSkip
+}
+
+// Retries returns how often a record is retried, 5 unless configured.
+func (p ErrorPolicy) Retries() int {
+	if p.MaxRetries > 0 {
+		return p.MaxRetries
+	}
+	return 5
+}
+
+// Backoff returns the wait before the first retry, one second unless configured.
+func (p ErrorPolicy) Backoff() time.Duration {
+	if backoff, err := time.ParseDuration(p.RetryBackoff); err == nil && backoff > 0 {
+		return backoff
+	}
+	return time.Second
+}
+
+// ExhaustedAction returns the action once retries are used up, halt unless configured.
+func (p ErrorPolicy) ExhaustedAction() string {
+	if p.OnExhausted != "" {
+		return p.OnExhausted
+	}
+	return ErrorActionHalt
+}
+
+// UsesDeadLetter reports whether any error class can end in the dead-letter topic.
+func (p ErrorPolicy) UsesDeadLetter() bool {
+	retries := false
+	for _, class := range ErrorClasses {
+		switch p.ActionFor(class) {
+		case ErrorActionDeadLetter:
+			return true
+		case ErrorActionRetry:
+			retries = true
+		}
+	}
+	return retries && p.ExhaustedAction() == ErrorActionDeadLetter
+}
+
+// DeadLetterCluster returns the cluster of the dead-letter topic, "source" or "target".
+func (p ErrorPolicy) DeadLetterCluster() string {
+	if p.DeadLetter.Cluster == "" {
+		return "target"
+	}
+	return p.DeadLetter.Cluster
+}
+
+// DeadLetterTopic returns the dead-letter topic of a job.
+func (p ErrorPolicy) DeadLetterTopic(jobID string) string {
+	if p.DeadLetter.Topic != "" {
+		return p.DeadLetter.Topic
+	}
+	return "kaf-mirror-dlq-" + jobID
+}
+
+// Validate checks the actions, error classes and retry settings.
+func (p ErrorPolicy) Validate() error {
+	if err := validateErrorAction("action", p.Action); err != nil {
+		return err
+	}
+	for class, action := range p.Classes {
+		if !slices.Contains(ErrorClasses, class) {
+			return fmt.Errorf("error_policy class %q is unknown, use one of %s", class, strings.Join(ErrorClasses, ", "))
+		}
+		if err := validateErrorAction("class "+class, action); err != nil {
+			return err
+		}
+	}
+	if p.OnExhausted == ErrorActionRetry {
+		return fmt.Errorf("error_policy on_exhausted must not be retry")
+	}
+	if err := validateErrorAction("on_exhausted", p.OnExhausted); err != nil {
+		return err
+	}
+	if p.MaxRetries < 0 {
+		return fmt.Errorf("error_policy max_retries must not be negative")
+	}
+	if p.RetryBackoff != "" {
+		if backoff, err := time.ParseDuration(p.RetryBackoff); err != nil || backoff <= 0 {
+			return fmt.Errorf("error_policy retry_backoff must be a positive duration")
+		}
+	}
+	switch p.DeadLetter.Cluster {
+	case "", "source", "target":
+	default:
+		return fmt.Errorf("error_policy dead_letter cluster must be source or target")
+	}
+	return nil
+}
+
+func validateErrorAction(field, action string) error {
+	switch action {
+	case "", ErrorActionSkip, ErrorActionRetry, ErrorActionDeadLetter, ErrorActionHalt:
+		return nil
+	}
+	return fmt.Errorf("error_policy %s must be one of skip, retry, dead_letter or halt", field)
+}
+
+// Source topic deletion policies.
+const (
+	SourceTopicDeletionIgnore       = "ignore"        // keep the mapping and the target topic
+	SourceTopicDeletionStop         = "stop"          // stop mirroring, keep the target topic
+	SourceTopicDeletionDeleteTarget = "delete_target" // stop mirroring, delete the target topic after the grace period
+)
+
+// SourceTopicDeletionConfig decides what topic discovery does when the source topic of a
+// mapping disappears. A source topic that reappears within the grace period is mirrored
+// again and its target topic is kept.
+type SourceTopicDeletionConfig struct {
+	Policy      string `mapstructure:"policy"`
+	GracePeriod string `mapstructure:"grace_period"`
+}
+
+// Start position modes.
+const (
+	StartPositionEarliest  = "earliest"  // oldest record still in the log
+	StartPositionLatest    = "latest"    // only records produced from now on
+	StartPositionTimestamp = "timestamp" // first record at or after Timestamp
+	StartPositionOffsets   = "offsets"   // explicit offsets per partition
+)
+
+// StartPosition decides where mirroring of a partition begins when the job's consumer
+// group has no committed offset for it. Timestamp is an RFC 3339 time or a duration
+// such as "168h" or "7d" counted back from when the position is applied. Offsets are
+// keyed by source topic and partition; partitions without one keep the default.
+type StartPosition struct {
+	Mode      string                     `mapstructure:"mode" json:"mode"`
+	Timestamp string                     `mapstructure:"timestamp" json:"timestamp,omitempty"`
+	Offsets   map[string]map[int32]int64 `mapstructure:"offsets" json:"offsets,omitempty"`
+}
+
+// IsSet reports whether a start position was configured.
+func (p StartPosition) IsSet() bool {
+	return p.Mode != ""
+}
+
+// Validate checks the mode and the fields it needs.
+func (p StartPosition) Validate() error {
+	switch p.Mode {
+	case "", StartPositionEarliest, StartPositionLatest:
+	case StartPositionTimestamp:
+		if _, err := p.Time(time.Now()); err != nil {
+			return err
+		}
+	case StartPositionOffsets:
+		if len(p.Offsets) == 0 {
+			return fmt.Errorf("start position offsets must name at least one partition")
+		}
+		for topic, partitions := range p.Offsets {
+			for partition, offset := range partitions {
+				if partition < 0 || offset < 0 {
+					return fmt.Errorf("start position offset %d for %s partition %d must not be negative", offset, topic, partition)
+				}
+			}
+		}
+	default:
+		return fmt.Errorf("start position mode must be one of earliest, latest, timestamp or offsets")
+	}
+	return nil
+}
+
+// Time resolves the timestamp of a timestamp start position relative to now.
+func (p StartPosition) Time(now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, p.Timestamp); err == nil {
+		return t, nil
+	}
+	value, unit := p.Timestamp, time.Duration(1)
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		value, unit = days+"h", 24
+	}
+	ago, err := time.ParseDuration(value)
+	if err != nil || ago < 0 {
+		return time.Time{}, fmt.Errorf("start position timestamp must be an RFC 3339 time or a duration such as 168h or 7d")
+	}
+	return now.Add(-ago * unit), nil
+}
+
+// Topic config sync modes.
+const (
+	TopicConfigSyncModeSync   = "sync"    // copy at creation and fix drift on existing topics
+	TopicConfigSyncModeDryRun = "dry_run" // copy at creation, only report drift on existing topics
+	TopicConfigSyncModeOff    = "off"     // create target topics with broker defaults
+)
+
+// DefaultTopicConfigSyncInclude are the topic configs copied from source to target
+// when no include list is configured.
+var DefaultTopicConfigSyncInclude = []string{
+	"cleanup.policy",
+	"retention.ms",
+	"retention.bytes",
+	"max.message.bytes",
+	"min.insync.replicas",
+	"message.timestamp.type",
+	"segment.bytes",
+	"segment.ms",
+	"delete.retention.ms",
+	"min.compaction.lag.ms",
+	"max.compaction.lag.ms",
+	"min.cleanable.dirty.ratio",
+}
+
+// TopicConfigSyncConfig controls which topic configs are copied from source to
+// target topics. Include and exclude entries are glob patterns such as "retention.*";
+// exclude wins over include. Overrides are applied on top of the copied values and
+// are merged with the per-job overrides stored in the database.
+type TopicConfigSyncConfig struct {
+	Mode      string            `mapstructure:"mode"`
+	Interval  string            `mapstructure:"interval"` // 0 disables the periodic drift check
+	Include   []string          `mapstructure:"include"`
+	Exclude   []string          `mapstructure:"exclude"`
+	Overrides map[string]string `mapstructure:"overrides"`
 }
 
 // TopicMapping defines a single source-to-target topic mapping
 type TopicMapping struct {
-	Source  string `mapstructure:"source"`
-	Target  string `mapstructure:"target"`
-	Enabled bool   `mapstructure:"enabled"`
+	Source        string        `mapstructure:"source"`
+	Target        string        `mapstructure:"target"`
+	Enabled       bool          `mapstructure:"enabled"`
+	StartPosition StartPosition `mapstructure:"start_position"` // overrides the job's start position
 }
 
 // AIConfig defines AI provider settings
@@ -124,6 +476,22 @@ func (c *Config) Validate() error {
 	if len(c.Clusters) == 0 {
 		return fmt.Errorf("at least one cluster must be defined")
 	}
+	for name, cluster := range c.Clusters {
+		if err := cluster.SchemaRegistry.validate(); err != nil {
+			return fmt.Errorf("cluster %s: %w", name, err)
+		}
+	}
+	switch strings.ToLower(c.Logging.Format) {
+	case "", "text", "json":
+	default:
+		return fmt.Errorf("logging format must be text or json")
+	}
+	if c.Logging.JobBufferSize < 0 {
+		return fmt.Errorf("logging job_buffer_size must not be negative")
+	}
+	if c.Logging.JobBufferSize == 0 {
+		c.Logging.JobBufferSize = 1000
+	}
 	if c.Database.RetentionDays <= 0 {
 		c.Database.RetentionDays = 30
 	}
@@ -143,6 +511,47 @@ func (c *Config) Validate() error {
 	if c.Compliance.Schedule.RunHour < 0 || c.Compliance.Schedule.RunHour > 23 {
 		return fmt.Errorf("compliance schedule run_hour must be between 0 and 23")
 	}
+	if err := c.Auth.LDAP.validate(); err != nil {
+		return err
+	}
+	if err := c.Auth.Lockout.validate(); err != nil {
+		return err
+	}
+	if err := c.Audit.validate(); err != nil {
+		return err
+	}
+	if err := c.Alerting.validate(); err != nil {
+		return err
+	}
+	if err := c.Webhooks.validate(); err != nil {
+		return err
+	}
+	if err := c.Replication.TopicConfigSync.validate(); err != nil {
+		return err
+	}
+	if err := c.Replication.SourceTopicDeletion.validate(); err != nil {
+		return err
+	}
+	if err := c.Replication.ACLSync.validate(); err != nil {
+		return err
+	}
+	if err := c.Replication.ErrorPolicy.Validate(); err != nil {
+		return fmt.Errorf("replication %v", err)
+	}
+	if c.Replication.Dedup.TailRecords < 0 {
+		return fmt.Errorf("replication dedup tail_records must not be negative")
+	}
+	if err := c.Replication.StartPosition.Validate(); err != nil {
+		return fmt.Errorf("replication %v", err)
+	}
+	for _, mapping := range c.Topics {
+		if err := mapping.StartPosition.Validate(); err != nil {
+			return fmt.Errorf("topic mapping %s: %v", mapping.Source, err)
+		}
+	}
+	if err := c.Monitoring.validate(); err != nil {
+		return err
+	}
 	if c.Compliance.Schedule.Enabled {
 		if !c.Compliance.Schedule.Daily && !c.Compliance.Schedule.Weekly && !c.Compliance.Schedule.Monthly {
 			return fmt.Errorf("compliance schedule must enable at least one period")
@@ -167,10 +576,14 @@ type SelfHealing struct {
 // MonitoringConfig defines monitoring and alerting settings
 type MonitoringConfig struct {
 	Enabled    bool             `mapstructure:"enabled"`
-	Platform   string           `mapstructure:"platform"` // "splunk", "loki", "prometheus"
+	Platform   string           `mapstructure:"platform"` // "splunk", "loki", "prometheus", "otlp"
+	Sinks      []string         `mapstructure:"sinks"`    // platforms to send to at once; overrides platform when set
+	Buffer     SinkBufferConfig `mapstructure:"buffer"`
 	Splunk     SplunkConfig     `mapstructure:"splunk"`
 	Loki       LokiConfig       `mapstructure:"loki"`
 	Prometheus PrometheusConfig `mapstructure:"prometheus"`
+	OTLP       OTLPConfig       `mapstructure:"otlp"`
+	Tracing    TracingConfig    `mapstructure:"tracing"`
 }
 
 // ComplianceConfig defines compliance reporting schedule settings
@@ -187,6 +600,132 @@ type ComplianceSchedule struct {
 	Monthly bool `mapstructure:"monthly"`
 }
 
+// AuthConfig defines external authentication settings
+type AuthConfig struct {
+	LDAP    LDAPConfig    `mapstructure:"ldap"`
+	Lockout LockoutConfig `mapstructure:"lockout"`
+}
+
+// LockoutConfig defines temporary account lockout after repeated failed logins
+type LockoutConfig struct {
+	MaxFailedAttempts int    `mapstructure:"max_failed_attempts"` // 0 disables lockout
+	Window            string `mapstructure:"window"`              // failures older than this are forgotten
+	Duration          string `mapstructure:"duration"`            // how long the account stays locked
+}
+
+// LDAPConfig defines LDAP / Active Directory authentication settings
+type LDAPConfig struct {
+	Enabled            bool              `mapstructure:"enabled"`
+	URL                string            `mapstructure:"url"` // ldap://host:389 or ldaps://host:636
+	StartTLS           bool              `mapstructure:"start_tls"`
+	InsecureSkipVerify bool              `mapstructure:"insecure_skip_verify"`
+	BindDN             string            `mapstructure:"bind_dn"`
+	BindPassword       string            `mapstructure:"bind_password"`
+	BaseDN             string            `mapstructure:"base_dn"`
+	UserFilter         string            `mapstructure:"user_filter"` // %s is replaced with the escaped username
+	GroupAttribute     string            `mapstructure:"group_attribute"`
+	GroupRoleMapping   map[string]string `mapstructure:"group_role_mapping"` // group DN -> kaf-mirror role
+	DefaultRole        string            `mapstructure:"default_role"`       // empty denies users without a mapped group
+	Timeout            string            `mapstructure:"timeout"`
+	Sync               LDAPSyncConfig    `mapstructure:"sync"`
+}
+
+// LDAPSyncConfig controls periodic reconciliation of LDAP users
+type LDAPSyncConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	Interval string `mapstructure:"interval"`
+}
+
+// AuditConfig defines the tamper-evident audit trail and its external export
+type AuditConfig struct {
+	RetentionDays int               `mapstructure:"retention_days"` // independent of database.retention_days
+	HMACKey       string            `mapstructure:"hmac_key"`       // optional; signs the hash chain when set
+	Syslog        AuditSyslogConfig `mapstructure:"syslog"`
+	File          AuditFileConfig   `mapstructure:"file"`
+	// SensitiveReads lists API route patterns (e.g. "/api/v1/clusters/:name") whose successful GETs are audited
+	SensitiveReads []string `mapstructure:"sensitive_reads"`
+}
+
+// AuditSyslogConfig defines streaming of audit events to a syslog receiver
+type AuditSyslogConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	Network  string `mapstructure:"network"` // "udp", "tcp" or "unix"
+	Address  string `mapstructure:"address"`
+	Format   string `mapstructure:"format"` // "cef" or "json"
+	Facility int    `mapstructure:"facility"`
+}
+
+// AuditFileConfig defines a JSONL file export of audit events
+type AuditFileConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	Path    string `mapstructure:"path"`
+}
+
+// AlertingConfig holds alert rule evaluation and notification channel settings.
+// Rules themselves are managed through the API and stored in the database.
+type AlertingConfig struct {
+	Enabled            bool                 `mapstructure:"enabled"`
+	EvaluationInterval string               `mapstructure:"evaluation_interval"`
+	RepeatInterval     string               `mapstructure:"repeat_interval"` // re-notify unacknowledged firing alerts
+	SendResolved       bool                 `mapstructure:"send_resolved"`
+	Channels           []AlertChannelConfig `mapstructure:"channels"`
+}
+
+// AlertChannelConfig describes a single notification channel.
+type AlertChannelConfig struct {
+	Name       string            `mapstructure:"name"`
+	Type       string            `mapstructure:"type"` // "webhook", "slack", "email" or "pagerduty"
+	URL        string            `mapstructure:"url"`
+	Headers    map[string]string `mapstructure:"headers"`
+	RoutingKey string            `mapstructure:"routing_key"` // pagerduty integration key
+	Timeout    string            `mapstructure:"timeout"`
+	SMTP       AlertSMTPConfig   `mapstructure:"smtp"`
+}
+
+// WebhooksConfig controls how webhook deliveries are retried. Webhooks themselves are
+// managed through the API and stored in the database.
+type WebhooksConfig struct {
+	MaxAttempts    int    `mapstructure:"max_attempts"`    // attempts before a delivery is marked failed
+	InitialBackoff string `mapstructure:"initial_backoff"` // doubled after every failed attempt
+	MaxBackoff     string `mapstructure:"max_backoff"`
+	Timeout        string `mapstructure:"timeout"`
+}
+
+// AlertSMTPConfig holds the mail server settings of an email channel.
+type AlertSMTPConfig struct {
+	Host     string   `mapstructure:"host"`
+	Port     int      `mapstructure:"port"`
+	Username string   `mapstructure:"username"`
+	Password string   `mapstructure:"password"`
+	From     string   `mapstructure:"from"`
+	To       []string `mapstructure:"to"`
+}
+
+// SinkBufferConfig defines how metrics are queued, batched and retried for each sink.
+// Batches that still fail after the retries are spilled to disk and replayed once
+// the sink recovers.
+type SinkBufferConfig struct {
+	QueueSize      int    `mapstructure:"queue_size"`      // metrics held in memory per sink
+	BatchSize      int    `mapstructure:"batch_size"`      // metrics sent per request
+	FlushInterval  string `mapstructure:"flush_interval"`  // max wait before sending a partial batch
+	MaxRetries     int    `mapstructure:"max_retries"`     // attempts per batch before spilling
+	InitialBackoff string `mapstructure:"initial_backoff"` // doubled after every failed attempt
+	MaxBackoff     string `mapstructure:"max_backoff"`
+	SpillDir       string `mapstructure:"spill_dir"`       // empty disables the disk spill
+	SpillMaxBytes  int64  `mapstructure:"spill_max_bytes"` // per sink; metrics beyond it are dropped
+}
+
+// SinkNames returns the platforms metrics are sent to.
+func (m MonitoringConfig) SinkNames() []string {
+	if len(m.Sinks) > 0 {
+		return m.Sinks
+	}
+	if m.Platform != "" {
+		return []string{m.Platform}
+	}
+	return nil
+}
+
 // SplunkConfig defines Splunk-specific settings
 type SplunkConfig struct {
 	HECEndpoint string `mapstructure:"hec_endpoint"`
@@ -204,6 +743,22 @@ type PrometheusConfig struct {
 	PushGateway string `mapstructure:"push_gateway"`
 }
 
+// OTLPConfig defines the OpenTelemetry collector used for OTLP metrics and traces
+type OTLPConfig struct {
+	Endpoint       string            `mapstructure:"endpoint"` // host:port of the collector
+	Protocol       string            `mapstructure:"protocol"` // "grpc" (default) or "http"
+	Insecure       bool              `mapstructure:"insecure"` // plaintext instead of TLS
+	Headers        map[string]string `mapstructure:"headers"`
+	ServiceName    string            `mapstructure:"service_name"`
+	ExportInterval string            `mapstructure:"export_interval"` // metrics export period
+}
+
+// TracingConfig defines OpenTelemetry tracing settings. Spans are exported to monitoring.otlp.
+type TracingConfig struct {
+	Enabled     bool    `mapstructure:"enabled"`
+	SampleRatio float64 `mapstructure:"sample_ratio"` // share of replicated records traced, 0-1
+}
+
 var AppConfig Config
 
 // LoadConfig loads configuration from standard paths.
@@ -244,6 +799,15 @@ func LoadConfig() (*Config, error) {
 		AppConfig.Replication.TopicDiscoveryInterval = "5m"
 	}
 	applyComplianceDefaults(&AppConfig)
+	applyLDAPDefaults(&AppConfig)
+	applyAuditDefaults(&AppConfig)
+	applyAlertingDefaults(&AppConfig)
+	applyWebhooksDefaults(&AppConfig)
+	applyTopicConfigSyncDefaults(&AppConfig)
+	applySourceTopicDeletionDefaults(&AppConfig)
+	applyACLSyncDefaults(&AppConfig)
+	applyDedupDefaults(&AppConfig)
+	applyMonitoringDefaults(&AppConfig)
 
 	// Dynamically set log file path with date if not already set
 	if !strings.Contains(AppConfig.Logging.File, "20") { // Basic check for a date
@@ -267,3 +831,407 @@ func applyComplianceDefaults(cfg *Config) {
 		cfg.Compliance.Schedule.Daily = true
 	}
 }
+
+func applyLDAPDefaults(cfg *Config) {
+	ldap := &cfg.Auth.LDAP
+	if ldap.UserFilter == "" {
+		ldap.UserFilter = "(&(objectClass=person)(uid=%s))"
+	}
+	if ldap.GroupAttribute == "" {
+		ldap.GroupAttribute = "memberOf"
+	}
+	if ldap.Timeout == "" {
+		ldap.Timeout = "10s"
+	}
+	if ldap.Sync.Interval == "" {
+		ldap.Sync.Interval = "1h"
+	}
+	if cfg.Auth.Lockout.Window == "" {
+		cfg.Auth.Lockout.Window = "15m"
+	}
+	if cfg.Auth.Lockout.Duration == "" {
+		cfg.Auth.Lockout.Duration = "15m"
+	}
+}
+
+func (l *LDAPConfig) validate() error {
+	if !l.Enabled {
+		return nil
+	}
+	if l.URL == "" {
+		return fmt.Errorf("auth ldap url must be set when ldap is enabled")
+	}
+	if l.BaseDN == "" {
+		return fmt.Errorf("auth ldap base_dn must be set when ldap is enabled")
+	}
+	if l.UserFilter != "" && !strings.Contains(l.UserFilter, "%s") {
+		return fmt.Errorf("auth ldap user_filter must contain a %%s placeholder for the username")
+	}
+	if l.Timeout != "" {
+		if _, err := time.ParseDuration(l.Timeout); err != nil {
+			return fmt.Errorf("auth ldap timeout must be a valid duration: %v", err)
+		}
+	}
+	if l.Sync.Enabled && l.Sync.Interval != "" {
+		interval, err := time.ParseDuration(l.Sync.Interval)
+		if err != nil {
+			return fmt.Errorf("auth ldap sync interval must be a valid duration: %v", err)
+		}
+		if interval <= 0 {
+			return fmt.Errorf("auth ldap sync interval must be positive")
+		}
+	}
+	return nil
+}
+
+func applyAuditDefaults(cfg *Config) {
+	if cfg.Audit.RetentionDays <= 0 {
+		cfg.Audit.RetentionDays = 365
+	}
+	if cfg.Audit.Syslog.Network == "" {
+		cfg.Audit.Syslog.Network = "udp"
+	}
+	if cfg.Audit.Syslog.Format == "" {
+		cfg.Audit.Syslog.Format = "cef"
+	}
+	if cfg.Audit.Syslog.Facility == 0 {
+		cfg.Audit.Syslog.Facility = 16 // local0
+	}
+}
+
+func (l *LockoutConfig) validate() error {
+	if l.MaxFailedAttempts < 0 {
+		return fmt.Errorf("auth lockout max_failed_attempts must not be negative")
+	}
+	if l.MaxFailedAttempts == 0 {
+		return nil
+	}
+	for name, value := range map[string]string{"window": l.Window, "duration": l.Duration} {
+		if value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return fmt.Errorf("auth lockout %s must be a positive duration", name)
+		}
+	}
+	return nil
+}
+
+func (a *AuditConfig) validate() error {
+	if a.RetentionDays < 0 {
+		return fmt.Errorf("audit retention_days must be positive")
+	}
+	if a.Syslog.Enabled {
+		if a.Syslog.Address == "" {
+			return fmt.Errorf("audit syslog address must be set when syslog export is enabled")
+		}
+		switch a.Syslog.Network {
+		case "", "udp", "tcp", "unix":
+		default:
+			return fmt.Errorf("audit syslog network must be one of udp, tcp, unix")
+		}
+		switch a.Syslog.Format {
+		case "", "cef", "json":
+		default:
+			return fmt.Errorf("audit syslog format must be cef or json")
+		}
+		if a.Syslog.Facility < 0 || a.Syslog.Facility > 23 {
+			return fmt.Errorf("audit syslog facility must be between 0 and 23")
+		}
+	}
+	if a.File.Enabled && a.File.Path == "" {
+		return fmt.Errorf("audit file path must be set when file export is enabled")
+	}
+	return nil
+}
+
+func applyAlertingDefaults(cfg *Config) {
+	alerting := &cfg.Alerting
+	if alerting.EvaluationInterval == "" {
+		alerting.EvaluationInterval = "15s"
+	}
+	if alerting.RepeatInterval == "" {
+		alerting.RepeatInterval = "4h"
+	}
+	for i := range alerting.Channels {
+		channel := &alerting.Channels[i]
+		if channel.Timeout == "" {
+			channel.Timeout = "10s"
+		}
+		if channel.Type == "pagerduty" && channel.URL == "" {
+			channel.URL = "https://events.pagerduty.com/v2/enqueue"
+		}
+		if channel.Type == "email" && channel.SMTP.Port == 0 {
+			channel.SMTP.Port = 25
+		}
+	}
+}
+
+func applyWebhooksDefaults(cfg *Config) {
+	webhooks := &cfg.Webhooks
+	if webhooks.MaxAttempts == 0 {
+		webhooks.MaxAttempts = 6
+	}
+	if webhooks.InitialBackoff == "" {
+		webhooks.InitialBackoff = "30s"
+	}
+	if webhooks.MaxBackoff == "" {
+		webhooks.MaxBackoff = "30m"
+	}
+	if webhooks.Timeout == "" {
+		webhooks.Timeout = "10s"
+	}
+}
+
+func (w *WebhooksConfig) validate() error {
+	if w.MaxAttempts < 0 {
+		return fmt.Errorf("webhooks max_attempts must not be negative")
+	}
+	for name, value := range map[string]string{"initial_backoff": w.InitialBackoff, "max_backoff": w.MaxBackoff, "timeout": w.Timeout} {
+		if value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return fmt.Errorf("webhooks %s must be a positive duration", name)
+		}
+	}
+	return nil
+}
+
+func applyTopicConfigSyncDefaults(cfg *Config) {
+	sync := &cfg.Replication.TopicConfigSync
+	if sync.Mode == "" {
+		sync.Mode = TopicConfigSyncModeSync
+	}
+	if sync.Interval == "" {
+		sync.Interval = "10m"
+	}
+	if len(sync.Include) == 0 {
+		sync.Include = append([]string(nil), DefaultTopicConfigSyncInclude...)
+	}
+}
+
+func (t *TopicConfigSyncConfig) validate() error {
+	switch t.Mode {
+	case "", TopicConfigSyncModeSync, TopicConfigSyncModeDryRun, TopicConfigSyncModeOff:
+	default:
+		return fmt.Errorf("replication topic_config_sync mode must be one of sync, dry_run or off")
+	}
+	if t.Interval != "" {
+		if d, err := time.ParseDuration(t.Interval); err != nil || d < 0 {
+			return fmt.Errorf("replication topic_config_sync interval must be a non-negative duration")
+		}
+	}
+	for _, pattern := range append(append([]string(nil), t.Include...), t.Exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("replication topic_config_sync pattern %q is invalid: %v", pattern, err)
+		}
+	}
+	for key := range t.Overrides {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("replication topic_config_sync overrides must not have empty keys")
+		}
+	}
+	return nil
+}
+
+func applySourceTopicDeletionDefaults(cfg *Config) {
+	deletion := &cfg.Replication.SourceTopicDeletion
+	if deletion.Policy == "" {
+		deletion.Policy = SourceTopicDeletionStop
+	}
+	if deletion.GracePeriod == "" {
+		deletion.GracePeriod = "24h"
+	}
+}
+
+func (d *SourceTopicDeletionConfig) validate() error {
+	switch d.Policy {
+	case "", SourceTopicDeletionIgnore, SourceTopicDeletionStop, SourceTopicDeletionDeleteTarget:
+	default:
+		return fmt.Errorf("replication source_topic_deletion policy must be one of ignore, stop or delete_target")
+	}
+	if d.GracePeriod != "" {
+		if grace, err := time.ParseDuration(d.GracePeriod); err != nil || grace < 0 {
+			return fmt.Errorf("replication source_topic_deletion grace_period must be a non-negative duration")
+		}
+	}
+	return nil
+}
+
+func (s SchemaRegistryConfig) validate() error {
+	if s.URL == "" {
+		return nil
+	}
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("schema_registry url must be an http or https URL")
+	}
+	return nil
+}
+
+func applyACLSyncDefaults(cfg *Config) {
+	if len(cfg.Replication.ACLSync.Groups) == 0 {
+		cfg.Replication.ACLSync.Groups = []string{"*"}
+	}
+}
+
+func applyDedupDefaults(cfg *Config) {
+	if cfg.Replication.Dedup.TailRecords == 0 {
+		cfg.Replication.Dedup.TailRecords = 1000
+	}
+}
+
+func (a *ACLSyncConfig) validate() error {
+	for _, pattern := range a.Groups {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("replication acl_sync group pattern %q is invalid: %v", pattern, err)
+		}
+	}
+	for _, rule := range a.PrincipalRewrites {
+		if rule.Match == "" {
+			return fmt.Errorf("replication acl_sync principal_rewrites must have a match expression")
+		}
+		if _, err := regexp.Compile(rule.Match); err != nil {
+			return fmt.Errorf("replication acl_sync principal rewrite %q is invalid: %v", rule.Match, err)
+		}
+	}
+	return nil
+}
+
+func (a *AlertingConfig) validate() error {
+	for name, value := range map[string]string{"evaluation_interval": a.EvaluationInterval, "repeat_interval": a.RepeatInterval} {
+		if value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return fmt.Errorf("alerting %s must be a positive duration", name)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, channel := range a.Channels {
+		if channel.Name == "" {
+			return fmt.Errorf("alerting channels must have a name")
+		}
+		if seen[channel.Name] {
+			return fmt.Errorf("alerting channel %q is defined more than once", channel.Name)
+		}
+		seen[channel.Name] = true
+		if channel.Timeout != "" {
+			if _, err := time.ParseDuration(channel.Timeout); err != nil {
+				return fmt.Errorf("alerting channel %q timeout must be a valid duration: %v", channel.Name, err)
+			}
+		}
+		switch channel.Type {
+		case "webhook", "slack":
+			if channel.URL == "" {
+				return fmt.Errorf("alerting channel %q requires a url", channel.Name)
+			}
+		case "pagerduty":
+			if channel.RoutingKey == "" {
+				return fmt.Errorf("alerting channel %q requires a routing_key", channel.Name)
+			}
+		case "email":
+			if channel.SMTP.Host == "" || channel.SMTP.From == "" || len(channel.SMTP.To) == 0 {
+				return fmt.Errorf("alerting channel %q requires smtp host, from and to", channel.Name)
+			}
+		default:
+			return fmt.Errorf("alerting channel %q type must be webhook, slack, email or pagerduty", channel.Name)
+		}
+	}
+	return nil
+}
+
+func applyMonitoringDefaults(cfg *Config) {
+	otlp := &cfg.Monitoring.OTLP
+	if otlp.Protocol == "" {
+		otlp.Protocol = "grpc"
+	}
+	if otlp.ServiceName == "" {
+		otlp.ServiceName = "kaf-mirror"
+	}
+	if otlp.ExportInterval == "" {
+		otlp.ExportInterval = "10s"
+	}
+
+	buf := &cfg.Monitoring.Buffer
+	if buf.QueueSize == 0 {
+		buf.QueueSize = 1000
+	}
+	if buf.BatchSize == 0 {
+		buf.BatchSize = 50
+	}
+	if buf.FlushInterval == "" {
+		buf.FlushInterval = "1s"
+	}
+	if buf.MaxRetries == 0 {
+		buf.MaxRetries = 5
+	}
+	if buf.InitialBackoff == "" {
+		buf.InitialBackoff = "500ms"
+	}
+	if buf.MaxBackoff == "" {
+		buf.MaxBackoff = "30s"
+	}
+	if buf.SpillMaxBytes == 0 {
+		buf.SpillMaxBytes = 100 << 20
+	}
+}
+
+func (b *SinkBufferConfig) validate() error {
+	if b.QueueSize < 0 || b.BatchSize < 0 || b.MaxRetries < 0 || b.SpillMaxBytes < 0 {
+		return fmt.Errorf("monitoring buffer sizes must not be negative")
+	}
+	for name, value := range map[string]string{
+		"flush_interval":  b.FlushInterval,
+		"initial_backoff": b.InitialBackoff,
+		"max_backoff":     b.MaxBackoff,
+	} {
+		if value == "" {
+			continue
+		}
+		if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+			return fmt.Errorf("monitoring buffer %s must be a positive duration", name)
+		}
+	}
+	return nil
+}
+
+func (m *MonitoringConfig) validate() error {
+	switch m.OTLP.Protocol {
+	case "", "grpc", "http":
+	default:
+		return fmt.Errorf("monitoring otlp protocol must be grpc or http")
+	}
+	if m.OTLP.ExportInterval != "" {
+		if d, err := time.ParseDuration(m.OTLP.ExportInterval); err != nil || d <= 0 {
+			return fmt.Errorf("monitoring otlp export_interval must be a positive duration")
+		}
+	}
+	seen := make(map[string]bool)
+	usesOTLP := m.Tracing.Enabled
+	for _, name := range m.SinkNames() {
+		switch name {
+		case "splunk", "loki", "prometheus", "otlp":
+		default:
+			return fmt.Errorf("unsupported monitoring sink %q", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("monitoring sink %q is listed more than once", name)
+		}
+		seen[name] = true
+		if name == "otlp" && m.Enabled {
+			usesOTLP = true
+		}
+	}
+	if err := m.Buffer.validate(); err != nil {
+		return err
+	}
+	if usesOTLP && m.OTLP.Endpoint == "" {
+		return fmt.Errorf("monitoring otlp endpoint must be set when OTLP metrics or tracing is enabled")
+	}
+	if m.Tracing.SampleRatio < 0 || m.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("monitoring tracing sample_ratio must be between 0 and 1")
+	}
+	return nil
+}
@@ -19,17 +19,58 @@ import (
 	"kaf-mirror/internal/analysis"
 	"kaf-mirror/pkg/logger"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 )
 
+var (
+	insightListenersMu  sync.RWMutex
+	insightListeners    = map[int]func(AIInsight){}
+	nextInsightListener int
+)
+
+// AddAIInsightListener registers a callback invoked after each insight is stored.
+// Listeners must not block. The returned function removes the listener.
+func AddAIInsightListener(listener func(AIInsight)) (remove func()) {
+	insightListenersMu.Lock()
+	id := nextInsightListener
+	nextInsightListener++
+	insightListeners[id] = listener
+	insightListenersMu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			insightListenersMu.Lock()
+			delete(insightListeners, id)
+			insightListenersMu.Unlock()
+		})
+	}
+}
+
 // InsertAIInsight stores a new AI insight in the database with response time tracking.
 func InsertAIInsight(db *sqlx.DB, insight *AIInsight) error {
 	query := `INSERT INTO ai_insights (job_id, insight_type, severity_level, ai_model, recommendation, timestamp, resolution_status, response_time_ms)
 			  VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
-	_, err := db.Exec(query, insight.JobID, insight.InsightType, insight.SeverityLevel, insight.AIModel, insight.Recommendation, time.Now(), "new", insight.ResponseTimeMs)
-	return err
+	now := time.Now()
+	result, err := db.Exec(query, insight.JobID, insight.InsightType, insight.SeverityLevel, insight.AIModel, insight.Recommendation, now, "new", insight.ResponseTimeMs)
+	if err != nil {
+		return err
+	}
+	if id, err := result.LastInsertId(); err == nil {
+		insight.ID = int(id)
+	}
+	insight.Timestamp = now
+	insight.ResolutionStatus = "new"
+
+	insightListenersMu.RLock()
+	defer insightListenersMu.RUnlock()
+	for _, listener := range insightListeners {
+		listener(*insight)
+	}
+	return nil
 }
 
 // GetAIMetrics calculates aggregated AI performance metrics.
@@ -89,7 +130,7 @@ func GenerateEnhancedAIInsight(db *sqlx.DB, aiClient interface{}, jobID string,
 	}
 	
 	// Get recent operation logs (last 30 minutes)
-	logEntries, err := analysis.GetLogsForJob(logger.GetProductionLogDir(), jobID, time.Now().Add(-30*time.Minute))
+	logEntries, err := analysis.GetLogsForJob(logger.LogDir(), jobID, time.Now().Add(-30*time.Minute))
 	if err != nil {
 		return fmt.Errorf("failed to get logs for AI analysis: %v", err)
 	}
@@ -310,6 +351,8 @@ func GetAggregatedMetricsForAI(db *sqlx.DB, jobID string, minutes int) (string,
 		TotalErrors        int64   `json:"total_errors"`
 		DataPoints         int     `json:"data_points"`
 		LatestTimestamp    string  `json:"latest_timestamp"`
+		PartitionCount     int     `json:"partition_count,omitempty"`
+		HotPartitions      []PartitionSummary `json:"hot_partitions,omitempty"`
 	}
 	
 	var totalMessages, totalBytes, totalErrors int64
@@ -339,6 +382,12 @@ func GetAggregatedMetricsForAI(db *sqlx.DB, jobID string, minutes int) (string,
 		DataPoints:      len(metrics),
 		LatestTimestamp: metrics[0].Timestamp.Format("2006-01-02 15:04:05"),
 	}
+
+	// A single hot or stuck partition is invisible in the job totals.
+	if partitions, err := GetPartitionSummaries(db, jobID, since); err == nil && len(partitions) > 0 {
+		summary.PartitionCount = len(partitions)
+		summary.HotPartitions = HottestPartitions(partitions, 5)
+	}
 	
 	summaryBytes, err := json.MarshalIndent(summary, "", "  ")
 	if err != nil {
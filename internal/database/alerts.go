@@ -0,0 +1,339 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Alert rule types understood by the alerting engine.
+const (
+	AlertRuleLag         = "lag"
+	AlertRuleLagSeconds  = "lag_seconds"
+	AlertRuleErrorRate   = "error_rate"
+	AlertRuleStalled     = "stalled"
+	AlertRuleJobFailed   = "job_failed"
+	AlertRuleGapDetected = "gap_detected"
+	AlertRuleSLOBurnRate = "slo_burn_rate"
+)
+
+// ErrAlertAlreadyAcknowledged is returned when acknowledging an alert twice.
+var ErrAlertAlreadyAcknowledged = errors.New("alert already acknowledged")
+
+// AlertRuleTypes lists every supported rule type.
+var AlertRuleTypes = []string{
+	AlertRuleLag, AlertRuleLagSeconds, AlertRuleErrorRate,
+	AlertRuleStalled, AlertRuleJobFailed, AlertRuleGapDetected,
+	AlertRuleSLOBurnRate,
+}
+
+// Alert severities and statuses.
+const (
+	AlertSeverityInfo     = "info"
+	AlertSeverityWarning  = "warning"
+	AlertSeverityCritical = "critical"
+
+	AlertStatusFiring   = "firing"
+	AlertStatusResolved = "resolved"
+)
+
+// Validate checks the rule type, severity and for duration.
+func (r *AlertRule) Validate() error {
+	if strings.TrimSpace(r.Name) == "" {
+		return fmt.Errorf("rule name is required")
+	}
+	valid := false
+	for _, t := range AlertRuleTypes {
+		if r.RuleType == t {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("rule type must be one of %s", strings.Join(AlertRuleTypes, ", "))
+	}
+	switch r.Severity {
+	case AlertSeverityInfo, AlertSeverityWarning, AlertSeverityCritical:
+	default:
+		return fmt.Errorf("severity must be info, warning or critical")
+	}
+	if r.Threshold < 0 {
+		return fmt.Errorf("threshold must not be negative")
+	}
+	if _, err := r.For(); err != nil {
+		return fmt.Errorf("for must be a valid duration: %v", err)
+	}
+	return nil
+}
+
+// For returns how long the condition has to hold before the rule fires.
+func (r *AlertRule) For() (time.Duration, error) {
+	if r.ForDuration == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(r.ForDuration)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("duration must not be negative")
+	}
+	return d, nil
+}
+
+// ChannelList returns the channel names the rule notifies; empty means all channels.
+func (r *AlertRule) ChannelList() []string {
+	return splitTokenList(r.Channels)
+}
+
+// AppliesTo reports whether the rule is evaluated for the given job.
+func (r *AlertRule) AppliesTo(jobID string) bool {
+	return r.JobID == "" || r.JobID == jobID
+}
+
+// CreateAlertRule stores a new alert rule.
+func CreateAlertRule(db *sqlx.DB, rule *AlertRule) error {
+	if rule.ForDuration == "" {
+		rule.ForDuration = "0s"
+	}
+	rule.Channels = joinTokenList(splitTokenList(rule.Channels))
+	now := time.Now().UTC()
+	query := `INSERT INTO alert_rules (name, rule_type, job_id, threshold, for_duration, severity, channels, enabled, created_at, updated_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := db.Exec(query, rule.Name, rule.RuleType, rule.JobID, rule.Threshold, rule.ForDuration,
+		rule.Severity, rule.Channels, rule.Enabled, now, now)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	rule.ID = int(id)
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+	return nil
+}
+
+// UpdateAlertRule overwrites an existing alert rule.
+func UpdateAlertRule(db *sqlx.DB, rule *AlertRule) error {
+	if rule.ForDuration == "" {
+		rule.ForDuration = "0s"
+	}
+	rule.Channels = joinTokenList(splitTokenList(rule.Channels))
+	rule.UpdatedAt = time.Now().UTC()
+	query := `UPDATE alert_rules SET name = ?, rule_type = ?, job_id = ?, threshold = ?, for_duration = ?,
+              severity = ?, channels = ?, enabled = ?, updated_at = ? WHERE id = ?`
+	result, err := db.Exec(query, rule.Name, rule.RuleType, rule.JobID, rule.Threshold, rule.ForDuration,
+		rule.Severity, rule.Channels, rule.Enabled, rule.UpdatedAt, rule.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetAlertRule retrieves an alert rule by ID.
+func GetAlertRule(db *sqlx.DB, id int) (*AlertRule, error) {
+	var rule AlertRule
+	if err := db.Get(&rule, "SELECT * FROM alert_rules WHERE id = ?", id); err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// ListAlertRules returns all alert rules ordered by name.
+func ListAlertRules(db *sqlx.DB) ([]AlertRule, error) {
+	rules := []AlertRule{}
+	err := db.Select(&rules, "SELECT * FROM alert_rules ORDER BY name")
+	return rules, err
+}
+
+// DeleteAlertRule removes an alert rule. Alerts it fired are kept for history.
+func DeleteAlertRule(db *sqlx.DB, id int) error {
+	result, err := db.Exec("DELETE FROM alert_rules WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CreateAlert records a newly fired alert.
+func CreateAlert(db *sqlx.DB, alert *Alert) error {
+	if alert.Status == "" {
+		alert.Status = AlertStatusFiring
+	}
+	alert.FiredAt = alert.FiredAt.UTC()
+	query := `INSERT INTO alerts (rule_id, rule_name, rule_type, job_id, severity, status, value, threshold, summary, silenced, fired_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := db.Exec(query, alert.RuleID, alert.RuleName, alert.RuleType, alert.JobID, alert.Severity,
+		alert.Status, alert.Value, alert.Threshold, alert.Summary, alert.Silenced, alert.FiredAt)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	alert.ID = int(id)
+	return nil
+}
+
+// GetAlert retrieves an alert by ID.
+func GetAlert(db *sqlx.DB, id int) (*Alert, error) {
+	var alert Alert
+	if err := db.Get(&alert, "SELECT * FROM alerts WHERE id = ?", id); err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// ListAlerts returns alerts filtered by status ("firing", "resolved" or empty for all)
+// and job ID, newest first.
+func ListAlerts(db *sqlx.DB, status, jobID string, limit int) ([]Alert, error) {
+	query := "SELECT * FROM alerts WHERE 1=1"
+	var args []interface{}
+	if status != "" {
+		query += " AND status = ?"
+		args = append(args, status)
+	}
+	if jobID != "" {
+		query += " AND job_id = ?"
+		args = append(args, jobID)
+	}
+	query += " ORDER BY fired_at DESC, id DESC"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	alerts := []Alert{}
+	err := db.Select(&alerts, query, args...)
+	return alerts, err
+}
+
+// ResolveAlert marks a firing alert as resolved.
+func ResolveAlert(db *sqlx.DB, id int, at time.Time) error {
+	_, err := db.Exec("UPDATE alerts SET status = ?, resolved_at = ? WHERE id = ? AND status = ?",
+		AlertStatusResolved, at.UTC(), id, AlertStatusFiring)
+	return err
+}
+
+// AcknowledgeAlert records who acknowledged an alert. Acknowledged alerts are not re-notified.
+func AcknowledgeAlert(db *sqlx.DB, id int, username string) (*Alert, error) {
+	result, err := db.Exec("UPDATE alerts SET acknowledged_by = ?, acknowledged_at = ? WHERE id = ? AND acknowledged_at IS NULL",
+		username, time.Now().UTC(), id)
+	if err != nil {
+		return nil, err
+	}
+	alert, err := GetAlert(db, id)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return alert, ErrAlertAlreadyAcknowledged
+	}
+	return alert, nil
+}
+
+// MarkAlertNotified records when notifications were last sent for an alert.
+func MarkAlertNotified(db *sqlx.DB, id int, at time.Time) error {
+	_, err := db.Exec("UPDATE alerts SET last_notified_at = ? WHERE id = ?", at.UTC(), id)
+	return err
+}
+
+// SetAlertSilenced updates whether notifications for an alert are suppressed.
+func SetAlertSilenced(db *sqlx.DB, id int, silenced bool) error {
+	_, err := db.Exec("UPDATE alerts SET silenced = ? WHERE id = ?", silenced, id)
+	return err
+}
+
+// CreateAlertSilence stores a new silence.
+func CreateAlertSilence(db *sqlx.DB, silence *AlertSilence) error {
+	if !silence.EndsAt.After(silence.StartsAt) {
+		return fmt.Errorf("silence must end after it starts")
+	}
+	silence.StartsAt = silence.StartsAt.UTC()
+	silence.EndsAt = silence.EndsAt.UTC()
+	silence.CreatedAt = time.Now().UTC()
+	query := `INSERT INTO alert_silences (job_id, rule_id, reason, created_by, starts_at, ends_at, created_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?)`
+	result, err := db.Exec(query, silence.JobID, silence.RuleID, silence.Reason, silence.CreatedBy,
+		silence.StartsAt, silence.EndsAt, silence.CreatedAt)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	silence.ID = int(id)
+	return nil
+}
+
+// ListAlertSilences returns silences; when activeOnly is set, expired silences are omitted.
+func ListAlertSilences(db *sqlx.DB, activeOnly bool) ([]AlertSilence, error) {
+	silences := []AlertSilence{}
+	if activeOnly {
+		err := db.Select(&silences, "SELECT * FROM alert_silences WHERE ends_at > ? ORDER BY ends_at", time.Now().UTC())
+		return silences, err
+	}
+	err := db.Select(&silences, "SELECT * FROM alert_silences ORDER BY ends_at DESC")
+	return silences, err
+}
+
+// DeleteAlertSilence removes a silence, ending it immediately.
+func DeleteAlertSilence(db *sqlx.DB, id int) error {
+	result, err := db.Exec("DELETE FROM alert_silences WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Matches reports whether the silence covers the given rule and job at time t.
+func (s *AlertSilence) Matches(ruleID int, jobID string, t time.Time) bool {
+	if t.Before(s.StartsAt) || !t.Before(s.EndsAt) {
+		return false
+	}
+	if s.JobID != "" && s.JobID != jobID {
+		return false
+	}
+	if s.RuleID != nil && *s.RuleID != ruleID {
+		return false
+	}
+	return true
+}
+
+// IsAlertSilenced reports whether any active silence covers the rule and job.
+func IsAlertSilenced(db *sqlx.DB, ruleID int, jobID string, t time.Time) (bool, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM alert_silences
+              WHERE starts_at <= ? AND ends_at > ?
+                AND (job_id = '' OR job_id = ?)
+                AND (rule_id IS NULL OR rule_id = ?)`
+	err := db.Get(&count, query, t.UTC(), t.UTC(), jobID, ruleID)
+	return count > 0, err
+}
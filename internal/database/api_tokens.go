@@ -18,6 +18,8 @@ import (
 	"database/sql"
 	"encoding/hex"
 	"log"
+	"net"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -25,17 +27,24 @@ import (
 
 // CreateApiToken creates a new API token for a user.
 func CreateApiToken(db *sqlx.DB, userID int, description string, expiresAt time.Time) (string, *ApiToken, error) {
+	return CreateScopedApiToken(db, userID, ApiTokenScope{Description: description, ExpiresAt: expiresAt})
+}
+
+// CreateScopedApiToken creates a named API token restricted to the given scope.
+// Empty permission, job and IP lists leave the respective dimension unrestricted.
+func CreateScopedApiToken(db *sqlx.DB, userID int, scope ApiTokenScope) (string, *ApiToken, error) {
 	tokenBytes := make([]byte, 32)
 	if _, err := rand.Read(tokenBytes); err != nil {
 		return "", nil, err
 	}
 	token := hex.EncodeToString(tokenBytes)
 
-	hash := sha256.Sum256([]byte(token))
-	tokenHash := hex.EncodeToString(hash[:])
+	tokenHash := hashApiToken(token)
 
-	query := `INSERT INTO api_tokens (user_id, token_hash, description, expires_at) VALUES (?, ?, ?, ?)`
-	result, err := db.Exec(query, userID, tokenHash, description, expiresAt)
+	query := `INSERT INTO api_tokens (user_id, name, token_hash, description, permissions, job_ids, allowed_ips, expires_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := db.Exec(query, userID, scope.Name, tokenHash, scope.Description,
+		joinTokenList(scope.Permissions), joinTokenList(scope.JobIDs), joinTokenList(scope.AllowedIPs), scope.ExpiresAt)
 	if err != nil {
 		return "", nil, err
 	}
@@ -45,33 +54,77 @@ func CreateApiToken(db *sqlx.DB, userID int, description string, expiresAt time.
 		return "", nil, err
 	}
 
-	var apiToken ApiToken
-	err = db.Get(&apiToken, "SELECT * FROM api_tokens WHERE id = ?", id)
+	apiToken, err := GetApiTokenByID(db, int(id))
 	if err != nil {
 		return "", nil, err
 	}
 
-	return token, &apiToken, nil
+	return token, apiToken, nil
 }
 
 // ValidateApiToken checks if a token is valid and returns the user ID.
 func ValidateApiToken(db *sqlx.DB, token string) (int, error) {
-	hash := sha256.Sum256([]byte(token))
-	tokenHash := hex.EncodeToString(hash[:])
+	tokenInfo, err := GetApiToken(db, token)
+	if err != nil {
+		return 0, err
+	}
+	return tokenInfo.UserID, nil
+}
 
+// GetApiToken looks up a token by its plaintext value and rejects expired tokens.
+func GetApiToken(db *sqlx.DB, token string) (*ApiToken, error) {
 	var tokenInfo ApiToken
-	err := db.Get(&tokenInfo, "SELECT * FROM api_tokens WHERE token_hash = ?", tokenHash)
+	err := db.Get(&tokenInfo, "SELECT * FROM api_tokens WHERE token_hash = ?", hashApiToken(token))
 	if err != nil {
 		log.Printf("ERROR: DB: Token not found in database: %v", err)
-		return 0, err
+		return nil, err
 	}
 
 	if !tokenInfo.ExpiresAt.IsZero() && tokenInfo.ExpiresAt.Before(time.Now()) {
 		log.Printf("ERROR: DB: Token expired for user %d", tokenInfo.UserID)
-		return 0, sql.ErrNoRows
+		return nil, sql.ErrNoRows
 	}
 
-	return tokenInfo.UserID, nil
+	return &tokenInfo, nil
+}
+
+// GetApiTokenByID retrieves a token by its ID.
+func GetApiTokenByID(db *sqlx.DB, id int) (*ApiToken, error) {
+	var apiToken ApiToken
+	err := db.Get(&apiToken, "SELECT * FROM api_tokens WHERE id = ?", id)
+	return &apiToken, err
+}
+
+// ListApiTokens retrieves all tokens of a user, newest first.
+func ListApiTokens(db *sqlx.DB, userID int) ([]ApiToken, error) {
+	var tokens []ApiToken
+	err := db.Select(&tokens, "SELECT * FROM api_tokens WHERE user_id = ? ORDER BY created_at DESC, id DESC", userID)
+	return tokens, err
+}
+
+// TouchApiToken records the last use of a token. Updates are throttled to one per minute.
+func TouchApiToken(db *sqlx.DB, id int, ip string) error {
+	query := `UPDATE api_tokens SET last_used_at = ?, last_used_ip = ?
+              WHERE id = ? AND (last_used_at IS NULL OR last_used_at < ? OR last_used_ip != ?)`
+	now := time.Now()
+	_, err := db.Exec(query, now, ip, id, now.Add(-time.Minute), ip)
+	return err
+}
+
+// RevokeApiToken deletes a single API token.
+func RevokeApiToken(db *sqlx.DB, id int) error {
+	result, err := db.Exec(`DELETE FROM api_tokens WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
 }
 
 // RevokeAllUserTokens revokes all API tokens for a user.
@@ -80,3 +133,103 @@ func RevokeAllUserTokens(db *sqlx.DB, userID int) error {
 	_, err := db.Exec(query, userID)
 	return err
 }
+
+// IsScoped reports whether the token carries any restriction beyond its user's role.
+func (t *ApiToken) IsScoped() bool {
+	return t.Permissions != "" || t.JobIDs != "" || t.AllowedIPs != ""
+}
+
+// PermissionList returns the token's permission subset; empty means the user's full role.
+func (t *ApiToken) PermissionList() []string {
+	return splitTokenList(t.Permissions)
+}
+
+// JobIDList returns the jobs the token is limited to; empty means all jobs.
+func (t *ApiToken) JobIDList() []string {
+	return splitTokenList(t.JobIDs)
+}
+
+// AllowedIPList returns the token's IP allowlist; empty means any address.
+func (t *ApiToken) AllowedIPList() []string {
+	return splitTokenList(t.AllowedIPs)
+}
+
+// AllowsPermission reports whether the token's scope includes the permission.
+func (t *ApiToken) AllowsPermission(permission string) bool {
+	permissions := t.PermissionList()
+	if len(permissions) == 0 {
+		return true
+	}
+	for _, p := range permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsJob reports whether the token may act on the job.
+func (t *ApiToken) AllowsJob(jobID string) bool {
+	jobIDs := t.JobIDList()
+	if len(jobIDs) == 0 {
+		return true
+	}
+	for _, id := range jobIDs {
+		if id == jobID {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsIP reports whether the client address matches the token's allowlist.
+func (t *ApiToken) AllowsIP(ip string) bool {
+	allowed := t.AllowedIPList()
+	if len(allowed) == 0 {
+		return true
+	}
+	clientIP := net.ParseIP(ip)
+	if clientIP == nil {
+		return false
+	}
+	for _, entry := range allowed {
+		if strings.Contains(entry, "/") {
+			if _, network, err := net.ParseCIDR(entry); err == nil && network.Contains(clientIP) {
+				return true
+			}
+			continue
+		}
+		if allowedIP := net.ParseIP(entry); allowedIP != nil && allowedIP.Equal(clientIP) {
+			return true
+		}
+	}
+	return false
+}
+
+func hashApiToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func joinTokenList(values []string) string {
+	cleaned := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			cleaned = append(cleaned, v)
+		}
+	}
+	return strings.Join(cleaned, ",")
+}
+
+func splitTokenList(value string) []string {
+	if value == "" {
+		return nil
+	}
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
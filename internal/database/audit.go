@@ -0,0 +1,194 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// AuditPrunedEventType is the event appended when old audit events are pruned.
+// It anchors the truncated chain so verification can still detect tampering.
+const AuditPrunedEventType = "audit_pruned"
+
+// AuditIssue describes a single integrity problem found in the audit chain.
+type AuditIssue struct {
+	EventID int    `json:"event_id"`
+	Problem string `json:"problem"`
+}
+
+// AuditVerification is the result of verifying the operational event hash chain.
+type AuditVerification struct {
+	Checked   int          `json:"checked"`
+	Unchained int          `json:"unchained"`
+	FirstID   int          `json:"first_id"`
+	LastID    int          `json:"last_id"`
+	HeadHash  string       `json:"head_hash"`
+	Valid     bool         `json:"valid"`
+	Issues    []AuditIssue `json:"issues,omitempty"`
+}
+
+type auditPrunedDetails struct {
+	PrunedThroughID   int    `json:"pruned_through_id"`
+	PrunedThroughHash string `json:"pruned_through_hash"`
+	Deleted           int64  `json:"deleted"`
+	RetentionDays     int    `json:"retention_days"`
+}
+
+// VerifyAuditChain walks the operational event chain and reports gaps, edits and broken links.
+// Events written before chaining was introduced are reported as unchained and are only
+// accepted before the first chained event. If the chain is HMAC-signed, the key is required.
+func VerifyAuditChain(db *sqlx.DB, hmacKey string) (*AuditVerification, error) {
+	var key []byte
+	if hmacKey != "" {
+		key = []byte(hmacKey)
+	}
+
+	var events []OperationalEvent
+	err := db.Select(&events, "SELECT id, timestamp, event_type, initiator, details, prev_hash, hash FROM operational_events ORDER BY id ASC")
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AuditVerification{}
+	addIssue := func(id int, format string, args ...interface{}) {
+		result.Issues = append(result.Issues, AuditIssue{EventID: id, Problem: fmt.Sprintf(format, args...)})
+	}
+
+	var prev *OperationalEvent
+	chainStarted := false
+	signed := false
+	for i := range events {
+		event := events[i]
+		result.Checked++
+		if result.FirstID == 0 {
+			result.FirstID = event.ID
+		}
+		result.LastID = event.ID
+
+		if event.Hash == "" {
+			if chainStarted {
+				addIssue(event.ID, "event has no hash inside the chain")
+			} else {
+				result.Unchained++
+			}
+			prev = &events[i]
+			continue
+		}
+
+		if prev != nil && event.ID != prev.ID+1 {
+			addIssue(event.ID, "gap in event IDs: expected %d, found %d", prev.ID+1, event.ID)
+		}
+
+		if !chainStarted {
+			chainStarted = true
+			if event.PrevHash != "" && !prunedAnchorMatches(db, event) {
+				addIssue(event.ID, "first chained event links to a missing predecessor")
+			}
+		} else if prev != nil && event.PrevHash != prev.Hash {
+			addIssue(event.ID, "prev_hash does not match hash of event %d", prev.ID)
+		}
+
+		if isHMACHash(event.Hash) {
+			signed = true
+			if key == nil {
+				addIssue(event.ID, "event is HMAC-signed but no key was provided")
+			} else if ComputeEventHash(&event, key) != event.Hash {
+				addIssue(event.ID, "content does not match hash (event was modified or key is wrong)")
+			}
+		} else {
+			if signed {
+				addIssue(event.ID, "unsigned event follows signed events")
+			}
+			if ComputeEventHash(&event, nil) != event.Hash {
+				addIssue(event.ID, "content does not match hash (event was modified)")
+			}
+		}
+
+		result.HeadHash = event.Hash
+		prev = &events[i]
+	}
+	result.Valid = len(result.Issues) == 0
+	return result, nil
+}
+
+// prunedAnchorMatches reports whether a chain that starts with a non-empty prev_hash was
+// truncated by PruneAuditEvents, i.e. a later audit_pruned event records the removed head.
+func prunedAnchorMatches(db *sqlx.DB, first OperationalEvent) bool {
+	var details []string
+	err := db.Select(&details, "SELECT details FROM operational_events WHERE event_type = ? AND id >= ?", AuditPrunedEventType, first.ID)
+	if err != nil {
+		return false
+	}
+	for _, d := range details {
+		var anchor auditPrunedDetails
+		if json.Unmarshal([]byte(d), &anchor) != nil {
+			continue
+		}
+		if anchor.PrunedThroughID == first.ID-1 && anchor.PrunedThroughHash == first.PrevHash {
+			return true
+		}
+	}
+	return false
+}
+
+// PruneAuditEvents deletes operational events older than the retention period.
+// Deletion always removes a contiguous prefix of the chain, and an audit_pruned event
+// recording the last removed event is appended so the remaining chain stays verifiable.
+func PruneAuditEvents(db *sqlx.DB, retentionDays int) (int64, error) {
+	if retentionDays <= 0 {
+		return 0, nil
+	}
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+
+	var last struct {
+		ID   int    `db:"id"`
+		Hash string `db:"hash"`
+	}
+	err := db.Get(&last, `SELECT id, hash FROM operational_events
+                          WHERE id = (SELECT MAX(id) FROM operational_events WHERE timestamp < ?)`, cutoff)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// Append the anchor before deleting so the chain head is never removed.
+	var count int64
+	if err := db.Get(&count, "SELECT COUNT(*) FROM operational_events WHERE id <= ?", last.ID); err != nil {
+		return 0, err
+	}
+	details, _ := json.Marshal(auditPrunedDetails{
+		PrunedThroughID:   last.ID,
+		PrunedThroughHash: last.Hash,
+		Deleted:           count,
+		RetentionDays:     retentionDays,
+	})
+	if err := CreateOperationalEvent(db, &OperationalEvent{
+		EventType: AuditPrunedEventType,
+		Initiator: "system",
+		Details:   string(details),
+	}); err != nil {
+		return 0, err
+	}
+
+	res, err := db.Exec("DELETE FROM operational_events WHERE id <= ?", last.ID)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Backfill range statuses.
+const (
+	BackfillRangePending   = "pending"
+	BackfillRangeCompleted = "completed"
+	BackfillRangeFailed    = "failed"
+)
+
+// BackfillRange is the offset range of one source partition copied by a backfill job.
+type BackfillRange struct {
+	ID          int        `db:"id" json:"id"`
+	JobID       string     `db:"job_id" json:"job_id"`
+	SourceTopic string     `db:"source_topic" json:"source_topic"`
+	PartitionID int32      `db:"partition_id" json:"partition_id"`
+	StartOffset int64      `db:"start_offset" json:"start_offset"`
+	EndOffset   int64      `db:"end_offset" json:"end_offset"` // exclusive
+	NextOffset  int64      `db:"next_offset" json:"next_offset"`
+	GapIDs      GapIDs     `db:"gap_ids" json:"gap_ids"`
+	Status      string     `db:"status" json:"status"`
+	LastError   *string    `db:"last_error" json:"last_error,omitempty"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+	CompletedAt *time.Time `db:"completed_at" json:"completed_at,omitempty"`
+}
+
+// GapIDs are the mirror gaps a backfill range closes, stored as JSON.
+type GapIDs []int
+
+// Scan implements sql.Scanner.
+func (g *GapIDs) Scan(src interface{}) error {
+	var data []byte
+	switch value := src.(type) {
+	case string:
+		data = []byte(value)
+	case []byte:
+		data = value
+	default:
+		return fmt.Errorf("cannot scan %T into gap ids", src)
+	}
+	return json.Unmarshal(data, g)
+}
+
+// Value implements driver.Valuer.
+func (g GapIDs) Value() (driver.Value, error) {
+	if g == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal(g)
+	return string(data), err
+}
+
+// CreateBackfillRanges stores the ranges of a backfill job. Ranges start at their
+// start offset.
+func CreateBackfillRanges(db *sqlx.DB, ranges []BackfillRange) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	query := `INSERT INTO backfill_ranges (job_id, source_topic, partition_id, start_offset, end_offset, next_offset, gap_ids, status, updated_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	for i := range ranges {
+		r := &ranges[i]
+		r.NextOffset = r.StartOffset
+		r.Status = BackfillRangePending
+		r.UpdatedAt = now
+		result, err := tx.Exec(query, r.JobID, r.SourceTopic, r.PartitionID, r.StartOffset, r.EndOffset, r.NextOffset, r.GapIDs, r.Status, now)
+		if err != nil {
+			return fmt.Errorf("failed to create backfill range: %w", err)
+		}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		r.ID = int(id)
+	}
+	return tx.Commit()
+}
+
+// GetBackfillRanges returns the ranges of a backfill job by topic and partition.
+func GetBackfillRanges(db *sqlx.DB, jobID string) ([]BackfillRange, error) {
+	ranges := make([]BackfillRange, 0)
+	err := db.Select(&ranges, "SELECT * FROM backfill_ranges WHERE job_id = ? ORDER BY source_topic, partition_id", jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get backfill ranges: %w", err)
+	}
+	return ranges, nil
+}
+
+// UpdateBackfillRange saves the progress and status of a backfill range.
+func UpdateBackfillRange(db *sqlx.DB, r *BackfillRange) error {
+	r.UpdatedAt = time.Now().UTC()
+	query := `UPDATE backfill_ranges SET next_offset = ?, status = ?, last_error = ?, updated_at = ?, completed_at = ? WHERE id = ?`
+	result, err := db.Exec(query, r.NextOffset, r.Status, r.LastError, r.UpdatedAt, r.CompletedAt, r.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update backfill range: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// SetMirrorGapsInProgress marks mirror gaps as being resolved.
+func SetMirrorGapsInProgress(db *sqlx.DB, gapIDs []int) error {
+	for _, id := range gapIDs {
+		if _, err := db.Exec("UPDATE mirror_gaps SET resolution_status = 'in_progress' WHERE id = ? AND resolution_status = 'unresolved'", id); err != nil {
+			return fmt.Errorf("failed to update mirror gap %d: %w", id, err)
+		}
+	}
+	return nil
+}
@@ -56,9 +56,11 @@ func CreateCluster(db *sqlx.DB, cluster *KafkaCluster) error {
 		}
 	}
 
-	query := `INSERT INTO kafka_clusters (name, provider, cluster_id, brokers, security_config, api_key, api_secret, connection_string)
-              VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
-	_, err = db.Exec(query, cluster.Name, cluster.Provider, cluster.ClusterID, cluster.Brokers, cluster.SecurityConfig, cluster.APIKey, cluster.APISecret, cluster.ConnectionString)
+	query := `INSERT INTO kafka_clusters (name, provider, cluster_id, brokers, security_config, api_key, api_secret, connection_string,
+              schema_registry_url, schema_registry_username, schema_registry_password)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err = db.Exec(query, cluster.Name, cluster.Provider, cluster.ClusterID, cluster.Brokers, cluster.SecurityConfig, cluster.APIKey, cluster.APISecret, cluster.ConnectionString,
+		cluster.SchemaRegistryURL, cluster.SchemaRegistryUsername, cluster.SchemaRegistryPassword)
 	return err
 }
 
@@ -86,9 +88,11 @@ func UpdateCluster(db *sqlx.DB, cluster *KafkaCluster) error {
 	}
 
 	query := `UPDATE kafka_clusters 
-              SET provider = ?, cluster_id = ?, brokers = ?, security_config = ?, api_key = ?, api_secret = ?, connection_string = ?
+              SET provider = ?, cluster_id = ?, brokers = ?, security_config = ?, api_key = ?, api_secret = ?, connection_string = ?,
+                  schema_registry_url = ?, schema_registry_username = ?, schema_registry_password = ?
               WHERE name = ?`
-	_, err = db.Exec(query, cluster.Provider, cluster.ClusterID, cluster.Brokers, cluster.SecurityConfig, cluster.APIKey, cluster.APISecret, cluster.ConnectionString, cluster.Name)
+	_, err = db.Exec(query, cluster.Provider, cluster.ClusterID, cluster.Brokers, cluster.SecurityConfig, cluster.APIKey, cluster.APISecret, cluster.ConnectionString,
+		cluster.SchemaRegistryURL, cluster.SchemaRegistryUsername, cluster.SchemaRegistryPassword, cluster.Name)
 	return err
 }
 
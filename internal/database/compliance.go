@@ -97,7 +97,14 @@ func GenerateComplianceReport(db *sqlx.DB, period string, userID int) (*Complian
 	}
 	reportData["performance_metrics"] = performanceMetrics
 
-	// 7. Compliance Summary
+	// 7. Service Level Objectives
+	serviceLevels, err := getServiceLevels(db, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service levels: %v", err)
+	}
+	reportData["service_levels"] = serviceLevels
+
+	// 8. Compliance Summary
 	complianceSummary := generateComplianceSummary(reportData)
 	reportData["compliance_summary"] = complianceSummary
 
@@ -142,9 +149,9 @@ func getAccessAuditData(db *sqlx.DB, startDate, endDate time.Time) (map[string]i
 	var loginAttempts int
 	err := db.Get(&loginAttempts, `
 		SELECT COUNT(*) FROM operational_events 
-		WHERE event_type = 'auth_login' 
+		WHERE event_type IN ('auth_login', ?, ?)
 		AND timestamp BETWEEN ? AND ?
-	`, startDate, endDate)
+	`, EventLoginSucceeded, EventLoginFailed, startDate, endDate)
 	if err != nil {
 		loginAttempts = 0
 	}
@@ -153,9 +160,9 @@ func getAccessAuditData(db *sqlx.DB, startDate, endDate time.Time) (map[string]i
 	var failedLogins int
 	err = db.Get(&failedLogins, `
 		SELECT COUNT(*) FROM operational_events 
-		WHERE event_type = 'auth_failed' 
+		WHERE event_type = ?
 		AND timestamp BETWEEN ? AND ?
-	`, startDate, endDate)
+	`, EventLoginFailed, startDate, endDate)
 	if err != nil {
 		failedLogins = 0
 	}
@@ -241,39 +248,113 @@ func getDataProcessingSummary(db *sqlx.DB, startDate, endDate time.Time) (map[st
 	return data, nil
 }
 
+// configChangeCondition matches configuration changes, including those made through the API
+// and recorded by the audit middleware as "<METHOD> /api/v1/config...".
+const configChangeCondition = `(event_type = 'config_change' OR (event_type LIKE '% /api/v1/config%' AND event_type NOT LIKE 'GET %' AND event_type NOT LIKE '%/config/export%'))`
+
 // getSecurityEvents retrieves security-related events
 func getSecurityEvents(db *sqlx.DB, startDate, endDate time.Time) (map[string]interface{}, error) {
 	data := make(map[string]interface{})
 
-	// Permission denied events
-	var permissionDenied int
-	err := db.Get(&permissionDenied, `
-		SELECT COUNT(*) FROM operational_events 
-		WHERE event_type = 'permission_denied' 
-		AND timestamp BETWEEN ? AND ?
-	`, startDate, endDate)
-	if err != nil {
-		permissionDenied = 0
+	// Counts per security event type
+	counts := make(map[string]int, len(SecurityEventTypes))
+	for _, eventType := range SecurityEventTypes {
+		var count int
+		err := db.Get(&count, `
+			SELECT COUNT(*) FROM operational_events
+			WHERE event_type = ?
+			AND timestamp BETWEEN ? AND ?
+		`, eventType, startDate, endDate)
+		if err != nil {
+			count = 0
+		}
+		counts[eventType] = count
 	}
 
 	// Configuration changes
 	var configChanges int
-	err = db.Get(&configChanges, `
+	err := db.Get(&configChanges, `
 		SELECT COUNT(*) FROM operational_events 
-		WHERE event_type = 'config_change' 
+		WHERE `+configChangeCondition+`
 		AND timestamp BETWEEN ? AND ?
 	`, startDate, endDate)
 	if err != nil {
 		configChanges = 0
 	}
 
-	data["permission_denied_events"] = permissionDenied
+	data["permission_denied_events"] = counts[EventPermissionDenied]
+	data["login_failures"] = counts[EventLoginFailed]
+	data["successful_logins"] = counts[EventLoginSucceeded]
+	data["account_lockouts"] = counts[EventAccountLocked]
+	data["authentication_failures"] = counts[EventAuthFailed]
+	data["tokens_created"] = counts[EventTokenCreated]
+	data["tokens_revoked"] = counts[EventTokenRevoked]
+	data["sensitive_reads"] = counts[EventSensitiveRead]
 	data["configuration_changes"] = configChanges
-	data["security_incidents"] = permissionDenied // Simple metric
+	data["security_incidents"] = counts[EventPermissionDenied] + counts[EventAccountLocked] + counts[EventAuthFailed]
+
+	// Most frequently denied permissions
+	data["denied_permissions"] = groupEventDetail(db, EventPermissionDenied, "$.permission", startDate, endDate)
+	// Usernames with failed logins
+	data["failed_login_users"] = groupEventDetail(db, EventLoginFailed, "$.username", startDate, endDate)
+
+	// Recent denials, failures and lockouts
+	recent := []map[string]interface{}{}
+	rows, err := db.Query(`
+		SELECT timestamp, event_type, initiator, details
+		FROM operational_events
+		WHERE event_type IN (?, ?, ?, ?)
+		AND timestamp BETWEEN ? AND ?
+		ORDER BY id DESC
+		LIMIT 100
+	`, EventPermissionDenied, EventLoginFailed, EventAccountLocked, EventAuthFailed, startDate, endDate)
+	if err == nil {
+		defer rows.Close()
+		for rows.Next() {
+			var timestamp time.Time
+			var eventType, initiator, details string
+			if err := rows.Scan(&timestamp, &eventType, &initiator, &details); err != nil {
+				continue
+			}
+			recent = append(recent, map[string]interface{}{
+				"timestamp":  timestamp,
+				"event_type": eventType,
+				"initiator":  initiator,
+				"details":    details,
+			})
+		}
+	}
+	data["recent_events"] = recent
 
 	return data, nil
 }
 
+// groupEventDetail counts events of a type grouped by a JSON field of their details.
+func groupEventDetail(db *sqlx.DB, eventType, jsonPath string, startDate, endDate time.Time) map[string]int {
+	result := make(map[string]int)
+	rows, err := db.Query(`
+		SELECT COALESCE(json_extract(details, ?), ''), COUNT(*)
+		FROM operational_events
+		WHERE event_type = ?
+		AND json_valid(details)
+		AND timestamp BETWEEN ? AND ?
+		GROUP BY 1
+	`, jsonPath, eventType, startDate, endDate)
+	if err != nil {
+		return result
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var key string
+		var count int
+		if err := rows.Scan(&key, &count); err != nil {
+			continue
+		}
+		result[key] = count
+	}
+	return result
+}
+
 // getConfigurationChanges retrieves configuration change history
 func getConfigurationChanges(db *sqlx.DB, startDate, endDate time.Time) ([]map[string]interface{}, error) {
 	var changes []map[string]interface{}
@@ -281,7 +362,7 @@ func getConfigurationChanges(db *sqlx.DB, startDate, endDate time.Time) ([]map[s
 	rows, err := db.Query(`
 		SELECT timestamp, initiator, details 
 		FROM operational_events 
-		WHERE event_type = 'config_change' 
+		WHERE `+configChangeCondition+`
 		AND timestamp BETWEEN ? AND ?
 		ORDER BY timestamp DESC
 	`, startDate, endDate)
@@ -391,34 +472,107 @@ func getPerformanceMetrics(db *sqlx.DB, startDate, endDate time.Time) (map[strin
 	return data, nil
 }
 
+// getServiceLevels evaluates every enabled job SLO over the report period
+func getServiceLevels(db *sqlx.DB, startDate, endDate time.Time) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+
+	slos, err := ListJobSLOs(db, "")
+	if err != nil {
+		return nil, err
+	}
+
+	objectives := []map[string]interface{}{}
+	met, breached := 0, 0
+	for i := range slos {
+		slo := &slos[i]
+		if !slo.Enabled {
+			continue
+		}
+		status, err := EvaluateSLOWindow(db, slo, "report", startDate, endDate)
+		if err != nil {
+			return nil, err
+		}
+		if status.Met {
+			met++
+		} else {
+			breached++
+		}
+		objectives = append(objectives, map[string]interface{}{
+			"job_id":                         slo.JobID,
+			"name":                           slo.Name,
+			"type":                           slo.SLOType,
+			"objective":                      slo.Objective,
+			"threshold":                      slo.Threshold,
+			"attainment_percent":             status.AttainmentPercent,
+			"error_budget_remaining_percent": status.ErrorBudgetRemainingPercent,
+			"bad":                            status.Bad,
+			"total_windows":                  status.TotalWindows,
+			"met":                            status.Met,
+			"no_data":                        status.NoData,
+		})
+	}
+
+	data["total_slos"] = met + breached
+	data["slos_met"] = met
+	data["slos_breached"] = breached
+	data["objectives"] = objectives
+
+	return data, nil
+}
+
+// Health score deductions. Each is listed in the summary's health_score_breakdown
+// so the score can be traced back to its inputs.
+const (
+	healthPenaltyPerPermissionDenied = 0.5
+	healthPenaltyPerErrorPercent     = 10
+	healthPenaltyPerBreachedSLO      = 5
+)
+
 // generateComplianceSummary creates an overall compliance summary
 func generateComplianceSummary(reportData map[string]interface{}) map[string]interface{} {
 	summary := make(map[string]interface{})
 
-	// Overall health score (simplified calculation)
-	healthScore := 100.0
+	breakdown := map[string]float64{"base": 100}
 
 	// Deduct points for security issues
 	if securityEvents, ok := reportData["security_events"].(map[string]interface{}); ok {
 		if permissionDenied, ok := securityEvents["permission_denied_events"].(int); ok && permissionDenied > 0 {
-			healthScore -= float64(permissionDenied) * 0.5
+			breakdown["permission_denied_penalty"] = float64(permissionDenied) * healthPenaltyPerPermissionDenied
 		}
 	}
 
 	// Deduct points for errors
 	if perfMetrics, ok := reportData["performance_metrics"].(map[string]interface{}); ok {
-		if errorRate, ok := perfMetrics["error_rate_percentage"].(float64); ok {
-			healthScore -= errorRate * 10 // 10 points per 1% error rate
+		if errorRate, ok := perfMetrics["error_rate_percentage"].(float64); ok && errorRate > 0 {
+			breakdown["error_rate_penalty"] = errorRate * healthPenaltyPerErrorPercent
 		}
 	}
 
+	// Deduct points for missed service level objectives
+	slosBreached := 0
+	if serviceLevels, ok := reportData["service_levels"].(map[string]interface{}); ok {
+		slosBreached, _ = serviceLevels["slos_breached"].(int)
+		summary["slos_met"] = serviceLevels["slos_met"]
+		summary["slos_breached"] = slosBreached
+		if slosBreached > 0 {
+			breakdown["slo_breach_penalty"] = float64(slosBreached) * healthPenaltyPerBreachedSLO
+		}
+	}
+
+	healthScore := breakdown["base"] - breakdown["permission_denied_penalty"] -
+		breakdown["error_rate_penalty"] - breakdown["slo_breach_penalty"]
 	if healthScore < 0 {
 		healthScore = 0
 	}
 
 	summary["overall_health_score"] = healthScore
+	breakdownData := make(map[string]interface{}, len(breakdown))
+	for key, value := range breakdown {
+		breakdownData[key] = value
+	}
+	summary["health_score_breakdown"] = breakdownData
 	summary["compliance_status"] = "COMPLIANT"
-	if healthScore < 90 {
+	if healthScore < 90 || slosBreached > 0 {
 		summary["compliance_status"] = "ATTENTION_REQUIRED"
 	}
 	if healthScore < 70 {
@@ -443,6 +597,9 @@ func generateRecommendations(reportData map[string]interface{}, healthScore floa
 		if permissionDenied, ok := securityEvents["permission_denied_events"].(int); ok && permissionDenied > 10 {
 			recommendations = append(recommendations, "Review user permissions and access controls - high number of permission denied events")
 		}
+		if lockouts, ok := securityEvents["account_lockouts"].(int); ok && lockouts > 0 {
+			recommendations = append(recommendations, "Investigate account lockouts - repeated failed logins may indicate password guessing")
+		}
 	}
 
 	// Check performance
@@ -452,6 +609,13 @@ func generateRecommendations(reportData map[string]interface{}, healthScore floa
 		}
 	}
 
+	// Check service level objectives
+	if serviceLevels, ok := reportData["service_levels"].(map[string]interface{}); ok {
+		if breached, ok := serviceLevels["slos_breached"].(int); ok && breached > 0 {
+			recommendations = append(recommendations, fmt.Sprintf("%d service level objective(s) missed - review the affected jobs' lag and error trends", breached))
+		}
+	}
+
 	if len(recommendations) == 0 {
 		recommendations = append(recommendations, "System is operating within normal parameters")
 	}
@@ -480,6 +644,29 @@ func GenerateComplianceCSV(report *ComplianceReport) ([]byte, error) {
 		writer.Write([]string{""})
 	}
 
+	// Service Levels
+	if t := sloTable(report.ReportData["service_levels"]); len(t.Rows) > 0 {
+		writer.Write([]string{"SERVICE LEVEL OBJECTIVES"})
+		writer.Write(t.Headers)
+		for _, row := range t.Rows {
+			writer.Write(row)
+		}
+		writer.Write([]string{""})
+	}
+
+	// Security Events
+	if security, ok := report.ReportData["security_events"].(map[string]interface{}); ok {
+		writer.Write([]string{"SECURITY EVENTS"})
+		writer.Write([]string{"Metric", "Value"})
+		for _, key := range []string{"successful_logins", "login_failures", "account_lockouts", "authentication_failures",
+			"permission_denied_events", "tokens_created", "tokens_revoked", "sensitive_reads"} {
+			if value, ok := security[key]; ok {
+				writer.Write([]string{key, fmt.Sprintf("%v", value)})
+			}
+		}
+		writer.Write([]string{""})
+	}
+
 	// Audit Trail
 	if events, ok := report.ReportData["configuration_changes"].([]map[string]interface{}); ok {
 		writer.Write([]string{"AUDIT TRAIL (Configuration Changes)"})
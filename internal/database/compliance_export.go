@@ -0,0 +1,630 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/jmoiron/sqlx"
+)
+
+// Compliance report export formats.
+const (
+	ComplianceFormatJSON   = "json"
+	ComplianceFormatCSV    = "csv"
+	ComplianceFormatHTML   = "html"
+	ComplianceFormatPDF    = "pdf"
+	ComplianceFormatBundle = "bundle"
+)
+
+// GetComplianceReport retrieves a stored compliance report by ID.
+func GetComplianceReport(db *sqlx.DB, id int) (*ComplianceReport, error) {
+	var report ComplianceReport
+	query := `
+		SELECT id, period, start_date, end_date, generated_by, generated_at, report_data
+		FROM compliance_reports WHERE id = ?
+	`
+	if err := db.Get(&report, query, id); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(report.ReportDataDB), &report.ReportData); err != nil {
+		return nil, fmt.Errorf("failed to parse report data: %v", err)
+	}
+	return &report, nil
+}
+
+// reportTable is a tabular block of a rendered report section.
+type reportTable struct {
+	Headers []string
+	Rows    [][]string
+}
+
+// reportSection is a format-independent view of one section of a compliance report.
+type reportSection struct {
+	Title  string
+	Fields [][2]string
+	Items  []string
+	Tables []reportTable
+}
+
+// buildReportSections converts report data into the sections shared by the HTML and PDF renderers.
+func buildReportSections(report *ComplianceReport) []reportSection {
+	data := report.ReportData
+	var sections []reportSection
+
+	if summary, ok := data["compliance_summary"].(map[string]interface{}); ok {
+		section := reportSection{Title: "Compliance Summary"}
+		section.Fields = append(section.Fields,
+			[2]string{"Overall health score", fmt.Sprintf("%.1f%%", toFloat(summary["overall_health_score"]))},
+			[2]string{"Compliance status", formatReportValue(summary["compliance_status"])})
+		section.Items = toStringList(summary["recommendations"])
+		if breakdown, ok := summary["health_score_breakdown"].(map[string]interface{}); ok {
+			section.Fields = append(section.Fields, healthBreakdownFields(breakdown)...)
+		}
+		sections = append(sections, section)
+	}
+
+	if serviceLevels, ok := data["service_levels"].(map[string]interface{}); ok {
+		section := reportSection{Title: "Service Level Objectives", Fields: mapFields(serviceLevels)}
+		if t := sloTable(serviceLevels); len(t.Rows) > 0 {
+			section.Tables = append(section.Tables, t)
+		} else {
+			section.Items = []string{"No service level objectives are defined."}
+		}
+		sections = append(sections, section)
+	}
+
+	if access, ok := data["access_audit"].(map[string]interface{}); ok {
+		sections = append(sections, reportSection{Title: "Access Audit", Fields: mapFields(access)})
+	}
+
+	if security, ok := data["security_events"].(map[string]interface{}); ok {
+		section := reportSection{Title: "Security Events"}
+		for key, value := range security {
+			switch value.(type) {
+			case map[string]interface{}, map[string]int, []interface{}, []map[string]interface{}:
+				continue
+			}
+			section.Fields = append(section.Fields, [2]string{humanizeKey(key), formatReportValue(value)})
+		}
+		sortFields(section.Fields)
+		if t := countTable("Permission", security["denied_permissions"]); len(t.Rows) > 0 {
+			section.Tables = append(section.Tables, t)
+		}
+		if t := countTable("Username", security["failed_login_users"]); len(t.Rows) > 0 {
+			section.Tables = append(section.Tables, t)
+		}
+		if t := eventTable(security["recent_events"], true); len(t.Rows) > 0 {
+			section.Tables = append(section.Tables, t)
+		}
+		sections = append(sections, section)
+	}
+
+	changes := reportSection{Title: "Configuration Changes"}
+	if t := eventTable(data["configuration_changes"], false); len(t.Rows) > 0 {
+		changes.Tables = append(changes.Tables, t)
+	} else {
+		changes.Items = []string{"No configuration changes in this period."}
+	}
+	sections = append(sections, changes)
+
+	if processing, ok := data["data_processing"].(map[string]interface{}); ok {
+		sections = append(sections, reportSection{Title: "Data Processing", Fields: mapFields(processing)})
+	}
+
+	if ai, ok := data["ai_usage"].(map[string]interface{}); ok {
+		section := reportSection{Title: "AI Usage", Fields: mapFields(ai)}
+		if t := countTable("Insight type", ai["insights_by_type"]); len(t.Rows) > 0 {
+			section.Tables = append(section.Tables, t)
+		}
+		sections = append(sections, section)
+	}
+
+	if perf, ok := data["performance_metrics"].(map[string]interface{}); ok {
+		sections = append(sections, reportSection{Title: "Performance", Fields: mapFields(perf)})
+	}
+
+	return sections
+}
+
+// healthBreakdownFields lists the base health score and each deduction applied to it.
+func healthBreakdownFields(breakdown map[string]interface{}) [][2]string {
+	fields := [][2]string{{"Health score base", fmt.Sprintf("%.1f", toFloat(breakdown["base"]))}}
+	for _, key := range []string{"permission_denied_penalty", "error_rate_penalty", "slo_breach_penalty"} {
+		if value, ok := breakdown[key]; ok {
+			fields = append(fields, [2]string{humanizeKey(key), fmt.Sprintf("-%.1f", toFloat(value))})
+		}
+	}
+	return fields
+}
+
+// sloTable renders the per-objective attainment of the service levels section.
+func sloTable(value interface{}) reportTable {
+	table := reportTable{Headers: []string{"Job", "SLO", "Type", "Objective", "Threshold", "Attainment", "Budget left", "Status"}}
+	serviceLevels, ok := value.(map[string]interface{})
+	if !ok {
+		return table
+	}
+
+	var objectives []map[string]interface{}
+	switch list := serviceLevels["objectives"].(type) {
+	case []map[string]interface{}:
+		objectives = list
+	case []interface{}:
+		for _, item := range list {
+			if m, ok := item.(map[string]interface{}); ok {
+				objectives = append(objectives, m)
+			}
+		}
+	}
+
+	for _, o := range objectives {
+		status := "MET"
+		if met, _ := o["met"].(bool); !met {
+			status = "BREACHED"
+		}
+		if noData, _ := o["no_data"].(bool); noData {
+			status = "NO DATA"
+		}
+		objective := fmt.Sprintf("%.2f%%", toFloat(o["objective"]))
+		if o["type"] == SLOTypeGaps {
+			objective = "-"
+		}
+		table.Rows = append(table.Rows, []string{
+			formatReportValue(o["job_id"]),
+			formatReportValue(o["name"]),
+			formatReportValue(o["type"]),
+			objective,
+			formatReportValue(o["threshold"]),
+			fmt.Sprintf("%.3f%%", toFloat(o["attainment_percent"])),
+			fmt.Sprintf("%.1f%%", toFloat(o["error_budget_remaining_percent"])),
+			status,
+		})
+	}
+	return table
+}
+
+// mapFields returns the scalar values of a report map as sorted label/value pairs.
+func mapFields(m map[string]interface{}) [][2]string {
+	var fields [][2]string
+	for key, value := range m {
+		switch value.(type) {
+		case map[string]interface{}, map[string]int, []interface{}, []map[string]interface{}:
+			continue
+		}
+		fields = append(fields, [2]string{humanizeKey(key), formatReportValue(value)})
+	}
+	sortFields(fields)
+	return fields
+}
+
+func sortFields(fields [][2]string) {
+	sort.Slice(fields, func(i, j int) bool { return fields[i][0] < fields[j][0] })
+}
+
+// countTable renders a map of counts (as stored fresh or after a JSON round trip) as a table.
+func countTable(label string, value interface{}) reportTable {
+	table := reportTable{Headers: []string{label, "Count"}}
+	switch counts := value.(type) {
+	case map[string]int:
+		for key, count := range counts {
+			table.Rows = append(table.Rows, []string{key, fmt.Sprintf("%d", count)})
+		}
+	case map[string]interface{}:
+		for key, count := range counts {
+			table.Rows = append(table.Rows, []string{key, formatReportValue(count)})
+		}
+	}
+	sort.Slice(table.Rows, func(i, j int) bool { return table.Rows[i][0] < table.Rows[j][0] })
+	return table
+}
+
+// eventTable renders a list of events with timestamp, initiator and details.
+func eventTable(value interface{}, withType bool) reportTable {
+	table := reportTable{Headers: []string{"Timestamp", "Initiator", "Details"}}
+	if withType {
+		table.Headers = []string{"Timestamp", "Event", "Initiator", "Details"}
+	}
+
+	var events []map[string]interface{}
+	switch list := value.(type) {
+	case []map[string]interface{}:
+		events = list
+	case []interface{}:
+		for _, item := range list {
+			if m, ok := item.(map[string]interface{}); ok {
+				events = append(events, m)
+			}
+		}
+	}
+
+	for _, event := range events {
+		row := []string{formatReportValue(event["timestamp"])}
+		if withType {
+			row = append(row, formatReportValue(event["event_type"]))
+		}
+		row = append(row, formatReportValue(event["initiator"]), formatReportValue(event["details"]))
+		table.Rows = append(table.Rows, row)
+	}
+	return table
+}
+
+func humanizeKey(key string) string {
+	words := strings.Split(key, "_")
+	for i, word := range words {
+		switch word {
+		case "ai":
+			words[i] = "AI"
+		case "slo", "slos":
+			words[i] = strings.ToUpper(word[:3]) + word[3:]
+		case "ms":
+			words[i] = "(ms)"
+		default:
+			if i == 0 && word != "" {
+				words[i] = strings.ToUpper(word[:1]) + word[1:]
+			}
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+func formatReportValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		if v == float64(int64(v)) {
+			return fmt.Sprintf("%d", int64(v))
+		}
+		return fmt.Sprintf("%.2f", v)
+	case time.Time:
+		return v.UTC().Format("2006-01-02 15:04:05")
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+func toFloat(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return 0
+}
+
+func toStringList(value interface{}) []string {
+	switch list := value.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		items := make([]string, 0, len(list))
+		for _, item := range list {
+			items = append(items, formatReportValue(item))
+		}
+		return items
+	}
+	return nil
+}
+
+var complianceHTMLTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Kaf-Mirror Compliance Report #{{.Report.ID}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1f2933; }
+h1 { margin-bottom: 0.2em; }
+h2 { border-bottom: 1px solid #cbd2d9; padding-bottom: 0.2em; margin-top: 1.6em; }
+table { border-collapse: collapse; margin: 0.8em 0; width: 100%; }
+th, td { border: 1px solid #cbd2d9; padding: 4px 8px; text-align: left; vertical-align: top; font-size: 0.9em; }
+th { background: #f0f4f8; }
+td { word-break: break-word; }
+.meta td:first-child, .fields td:first-child { width: 30%; font-weight: 600; }
+</style>
+</head>
+<body>
+<h1>Kaf-Mirror Compliance Report</h1>
+<table class="meta">
+<tr><td>Report ID</td><td>{{.Report.ID}}</td></tr>
+<tr><td>Period</td><td>{{.Report.Period}}</td></tr>
+<tr><td>Date range</td><td>{{.Start}} to {{.End}}</td></tr>
+<tr><td>Generated</td><td>{{.Generated}}</td></tr>
+</table>
+{{range .Sections}}
+<h2>{{.Title}}</h2>
+{{if .Fields}}<table class="fields">{{range .Fields}}<tr><td>{{index . 0}}</td><td>{{index . 1}}</td></tr>{{end}}</table>{{end}}
+{{if .Items}}<ul>{{range .Items}}<li>{{.}}</li>{{end}}</ul>{{end}}
+{{range .Tables}}
+<table>
+<tr>{{range .Headers}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>
+{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+// GenerateComplianceHTML renders a compliance report as a standalone HTML document.
+func GenerateComplianceHTML(report *ComplianceReport) ([]byte, error) {
+	var buf bytes.Buffer
+	err := complianceHTMLTemplate.Execute(&buf, map[string]interface{}{
+		"Report":    report,
+		"Start":     report.StartDate.Format("2006-01-02"),
+		"End":       report.EndDate.Format("2006-01-02"),
+		"Generated": report.GeneratedAt.Format("2006-01-02 15:04:05"),
+		"Sections":  buildReportSections(report),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render HTML report: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// GenerateCompliancePDF renders a compliance report as a PDF document.
+func GenerateCompliancePDF(report *ComplianceReport) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(fmt.Sprintf("Kaf-Mirror Compliance Report #%d", report.ID), true)
+	pdf.SetCreator("kaf-mirror", true)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 8, fmt.Sprintf("Report #%d - page %d/{nb}", report.ID, pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pageWidth, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	contentWidth := pageWidth - left - right
+
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(0, 10, "Kaf-Mirror Compliance Report", "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	for _, line := range [][2]string{
+		{"Report ID", fmt.Sprintf("%d", report.ID)},
+		{"Period", report.Period},
+		{"Date range", fmt.Sprintf("%s to %s", report.StartDate.Format("2006-01-02"), report.EndDate.Format("2006-01-02"))},
+		{"Generated", report.GeneratedAt.Format("2006-01-02 15:04:05")},
+	} {
+		pdf.CellFormat(40, 6, line[0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 6, tr(line[1]), "", 1, "L", false, 0, "")
+	}
+
+	for _, section := range buildReportSections(report) {
+		pdf.Ln(4)
+		pdf.SetFont("Helvetica", "B", 13)
+		pdf.CellFormat(0, 8, tr(section.Title), "B", 1, "L", false, 0, "")
+		pdf.Ln(1)
+
+		pdf.SetFont("Helvetica", "", 10)
+		for _, field := range section.Fields {
+			pdf.CellFormat(contentWidth*0.45, 6, tr(field[0]), "", 0, "L", false, 0, "")
+			pdf.CellFormat(0, 6, tr(field[1]), "", 1, "L", false, 0, "")
+		}
+		for _, item := range section.Items {
+			pdf.MultiCell(0, 5, tr("- "+item), "", "L", false)
+		}
+		for _, table := range section.Tables {
+			pdf.Ln(2)
+			writePDFTable(pdf, tr, table, contentWidth)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render PDF report: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writePDFTable writes a table whose last column takes the remaining width. Long cells are truncated.
+func writePDFTable(pdf *fpdf.Fpdf, tr func(string) string, table reportTable, width float64) {
+	columns := len(table.Headers)
+	widths := make([]float64, columns)
+	fixed := 0.0
+	for i := 0; i < columns-1; i++ {
+		widths[i] = 38
+		fixed += widths[i]
+	}
+	widths[columns-1] = width - fixed
+	if columns == 2 {
+		widths[0], widths[1] = width*0.7, width*0.3
+	}
+
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(240, 244, 248)
+	for i, header := range table.Headers {
+		pdf.CellFormat(widths[i], 6, tr(header), "1", 0, "L", true, 0, "")
+	}
+	pdf.Ln(-1)
+
+	pdf.SetFont("Helvetica", "", 8)
+	for _, row := range table.Rows {
+		for i, cell := range row {
+			pdf.CellFormat(widths[i], 5, tr(truncatePDFCell(pdf, cell, widths[i]-2)), "1", 0, "L", false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+}
+
+func truncatePDFCell(pdf *fpdf.Fpdf, text string, width float64) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// EvidenceManifest lists the files of an evidence bundle with their SHA-256 checksums.
+type EvidenceManifest struct {
+	ReportID      int                    `json:"report_id"`
+	Period        string                 `json:"period"`
+	StartDate     time.Time              `json:"start_date"`
+	EndDate       time.Time              `json:"end_date"`
+	CreatedAt     time.Time              `json:"created_at"`
+	AuditHeadHash string                 `json:"audit_head_hash,omitempty"`
+	Files         []EvidenceManifestFile `json:"files"`
+}
+
+// EvidenceManifestFile is a single entry of an evidence manifest.
+type EvidenceManifestFile struct {
+	Path   string `json:"path"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// GenerateEvidenceBundle builds a zip archive with the rendered report, the operational events and
+// inventory snapshots of the report period, the audit chain verification and a manifest with SHA-256
+// checksums. When hmacKey is set, manifest.sig holds an HMAC-SHA256 signature of manifest.json.
+func GenerateEvidenceBundle(db *sqlx.DB, report *ComplianceReport, hmacKey string) ([]byte, error) {
+	type bundleFile struct {
+		path string
+		data []byte
+	}
+	var files []bundleFile
+
+	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode report: %v", err)
+	}
+	files = append(files, bundleFile{"report.json", reportJSON})
+
+	renderers := []struct {
+		path   string
+		render func(*ComplianceReport) ([]byte, error)
+	}{
+		{"report.html", GenerateComplianceHTML},
+		{"report.pdf", GenerateCompliancePDF},
+		{"report.csv", GenerateComplianceCSV},
+	}
+	for _, r := range renderers {
+		data, err := r.render(report)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, bundleFile{r.path, data})
+	}
+
+	var events []OperationalEvent
+	err = db.Select(&events, `
+		SELECT id, timestamp, event_type, initiator, details, prev_hash, hash
+		FROM operational_events
+		WHERE timestamp BETWEEN ? AND ?
+		ORDER BY id ASC
+	`, report.StartDate, report.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read operational events: %v", err)
+	}
+	var eventsJSONL bytes.Buffer
+	for _, event := range events {
+		line, err := json.Marshal(event)
+		if err != nil {
+			return nil, err
+		}
+		eventsJSONL.Write(line)
+		eventsJSONL.WriteByte('\n')
+	}
+	files = append(files, bundleFile{"events.jsonl", eventsJSONL.Bytes()})
+
+	var snapshotIDs []int
+	err = db.Select(&snapshotIDs, `
+		SELECT id FROM job_inventory_snapshots
+		WHERE created_at BETWEEN ? AND ?
+		ORDER BY id ASC
+	`, report.StartDate, report.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read inventory snapshots: %v", err)
+	}
+	for _, id := range snapshotIDs {
+		inventory, err := GetFullInventoryData(db, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read inventory snapshot %d: %v", id, err)
+		}
+		data, err := json.MarshalIndent(inventory, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, bundleFile{fmt.Sprintf("inventory/snapshot-%d.json", id), data})
+	}
+
+	verification, err := VerifyAuditChain(db, hmacKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify audit chain: %v", err)
+	}
+	verificationJSON, err := json.MarshalIndent(verification, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, bundleFile{"audit-verification.json", verificationJSON})
+
+	manifest := EvidenceManifest{
+		ReportID:      report.ID,
+		Period:        report.Period,
+		StartDate:     report.StartDate,
+		EndDate:       report.EndDate,
+		CreatedAt:     time.Now().UTC(),
+		AuditHeadHash: verification.HeadHash,
+	}
+	for _, f := range files {
+		sum := sha256.Sum256(f.data)
+		manifest.Files = append(manifest.Files, EvidenceManifestFile{Path: f.path, Size: len(f.data), SHA256: hex.EncodeToString(sum[:])})
+	}
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, bundleFile{"manifest.json", manifestJSON})
+	if hmacKey != "" {
+		mac := hmac.New(sha256.New, []byte(hmacKey))
+		mac.Write(manifestJSON)
+		files = append(files, bundleFile{"manifest.sig", []byte(hashPrefixHMAC + hex.EncodeToString(mac.Sum(nil)) + "\n")})
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := archive.CreateHeader(&zip.FileHeader{Name: f.path, Method: zip.Deflate, Modified: manifest.CreatedAt})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(f.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -179,6 +179,78 @@ func RunMigrations(db *sqlx.DB) error {
 		return err
 	}
 
+	// Migration 11: Add auth_source and disabled columns to users
+	err = addUserAuthSourceColumns(db)
+	if err != nil {
+		return err
+	}
+
+	// Migration 12: Add scope columns to api_tokens
+	err = addApiTokenScopeColumns(db)
+	if err != nil {
+		return err
+	}
+
+	// Migration 13: Add hash chain columns to operational_events
+	err = addOperationalEventHashColumns(db)
+	if err != nil {
+		return err
+	}
+
+	// Migration 14: Add login lockout columns to users
+	err = addUserLockoutColumns(db)
+	if err != nil {
+		return err
+	}
+
+	// Migration 15: Add alerting permissions to existing roles
+	err = addAlertPermissions(db)
+	if err != nil {
+		return err
+	}
+
+	// Migration 16: Add webhook permissions to existing roles
+	err = addWebhookPermissions(db)
+	if err != nil {
+		return err
+	}
+
+	// Migration 17: Add schema registry columns to kafka_clusters
+	err = addClusterSchemaRegistryColumns(db)
+	if err != nil {
+		return err
+	}
+
+	// Migration 18: Add failover permission to existing roles
+	err = addFailoverPermissions(db)
+	if err != nil {
+		return err
+	}
+
+	// Migration 19: Add start position columns to jobs and topic mappings
+	err = addStartPositionColumns(db)
+	if err != nil {
+		return err
+	}
+
+	// Migration 20: Add the job type column to jobs
+	err = addJobTypeColumn(db)
+	if err != nil {
+		return err
+	}
+
+	// Migration 21: Add the error policy column to jobs
+	err = addErrorPolicyColumn(db)
+	if err != nil {
+		return err
+	}
+
+	// Migration 22: Add the disable reason column to users
+	err = addUserDisabledReasonColumn(db)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -218,6 +290,179 @@ func addMirrorProgressHistoryTable(db *sqlx.DB) error {
 	return nil
 }
 
+// addUserAuthSourceColumns adds the auth_source and disabled columns to the users table
+func addUserAuthSourceColumns(db *sqlx.DB) error {
+	var columnExists int
+	err := db.Get(&columnExists, "SELECT COUNT(*) FROM pragma_table_info('users') WHERE name='auth_source'")
+	if err != nil {
+		return err
+	}
+
+	if columnExists == 0 {
+		_, err = db.Exec("ALTER TABLE users ADD COLUMN auth_source TEXT NOT NULL DEFAULT 'local'")
+		if err != nil {
+			return err
+		}
+	}
+
+	err = db.Get(&columnExists, "SELECT COUNT(*) FROM pragma_table_info('users') WHERE name='disabled'")
+	if err != nil {
+		return err
+	}
+
+	if columnExists == 0 {
+		_, err = db.Exec("ALTER TABLE users ADD COLUMN disabled BOOLEAN NOT NULL DEFAULT FALSE")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addApiTokenScopeColumns adds name, scope and last-used columns to the api_tokens table
+func addApiTokenScopeColumns(db *sqlx.DB) error {
+	columns := []struct {
+		name       string
+		definition string
+	}{
+		{"name", "TEXT NOT NULL DEFAULT ''"},
+		{"permissions", "TEXT NOT NULL DEFAULT ''"},
+		{"job_ids", "TEXT NOT NULL DEFAULT ''"},
+		{"allowed_ips", "TEXT NOT NULL DEFAULT ''"},
+		{"last_used_at", "DATETIME"},
+		{"last_used_ip", "TEXT NOT NULL DEFAULT ''"},
+	}
+
+	for _, column := range columns {
+		var columnExists int
+		err := db.Get(&columnExists, "SELECT COUNT(*) FROM pragma_table_info('api_tokens') WHERE name=?", column.name)
+		if err != nil {
+			return err
+		}
+		if columnExists > 0 {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE api_tokens ADD COLUMN %s %s", column.name, column.definition)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addAlertPermissions grants the alerting permissions to the default roles of existing installations.
+func addAlertPermissions(db *sqlx.DB) error {
+	return grantNewPermissions(db, map[string][]string{
+		"alerts:view":   {"admin", "operator", "monitoring", "compliance"},
+		"alerts:manage": {"admin", "operator"},
+	})
+}
+
+// addWebhookPermissions grants the webhook permissions to existing roles.
+func addWebhookPermissions(db *sqlx.DB) error {
+	return grantNewPermissions(db, map[string][]string{
+		"webhooks:view":   {"admin", "operator", "monitoring"},
+		"webhooks:manage": {"admin", "operator"},
+	})
+}
+
+// addFailoverPermissions grants the failover permission to existing roles.
+func addFailoverPermissions(db *sqlx.DB) error {
+	return grantNewPermissions(db, map[string][]string{
+		"jobs:failover": {"admin", "operator"},
+	})
+}
+
+// grantNewPermissions creates permissions missing from an existing database and
+// grants them to the given roles.
+func grantNewPermissions(db *sqlx.DB, grants map[string][]string) error {
+	for permission, roles := range grants {
+		var permissionExists int
+		if err := db.Get(&permissionExists, "SELECT COUNT(*) FROM permissions WHERE name = ?", permission); err != nil {
+			return err
+		}
+		if permissionExists > 0 {
+			continue
+		}
+
+		// Fresh databases are seeded by SeedDefaultRolesAndPermissions.
+		var roleCount int
+		if err := db.Get(&roleCount, "SELECT COUNT(*) FROM roles"); err != nil {
+			return err
+		}
+		if roleCount == 0 {
+			return nil
+		}
+
+		result, err := db.Exec("INSERT INTO permissions (name) VALUES (?)", permission)
+		if err != nil {
+			return err
+		}
+		permID, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		for _, role := range roles {
+			var roleID int
+			if err := db.Get(&roleID, "SELECT id FROM roles WHERE name = ?", role); err != nil {
+				continue
+			}
+			if _, err := db.Exec("INSERT OR IGNORE INTO role_permissions (role_id, permission_id) VALUES (?, ?)", roleID, permID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addUserLockoutColumns adds the columns used to track failed logins and account lockout.
+func addUserLockoutColumns(db *sqlx.DB) error {
+	columns := []struct {
+		name       string
+		definition string
+	}{
+		{"failed_login_count", "INTEGER NOT NULL DEFAULT 0"},
+		{"last_failed_login_at", "DATETIME"},
+		{"locked_until", "DATETIME"},
+	}
+
+	for _, column := range columns {
+		var columnExists int
+		err := db.Get(&columnExists, "SELECT COUNT(*) FROM pragma_table_info('users') WHERE name=?", column.name)
+		if err != nil {
+			return err
+		}
+		if columnExists > 0 {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE users ADD COLUMN %s %s", column.name, column.definition)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addOperationalEventHashColumns adds the prev_hash and hash columns used by the audit hash chain.
+// Existing events stay unchained; the chain starts with the first event written after the migration.
+func addOperationalEventHashColumns(db *sqlx.DB) error {
+	for _, column := range []string{"prev_hash", "hash"} {
+		var columnExists int
+		err := db.Get(&columnExists, "SELECT COUNT(*) FROM pragma_table_info('operational_events') WHERE name=?", column)
+		if err != nil {
+			return err
+		}
+		if columnExists > 0 {
+			continue
+		}
+		if _, err := db.Exec(fmt.Sprintf("ALTER TABLE operational_events ADD COLUMN %s TEXT NOT NULL DEFAULT ''", column)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func addEventsViewPermission(db *sqlx.DB) error {
 	var permissionExists int
 	err := db.Get(&permissionExists, "SELECT COUNT(*) FROM permissions WHERE name='events:view'")
@@ -283,6 +528,91 @@ func addClusterIdentifierFields(db *sqlx.DB) error {
 	return nil
 }
 
+// addClusterSchemaRegistryColumns adds the schema registry connection columns to kafka_clusters
+func addClusterSchemaRegistryColumns(db *sqlx.DB) error {
+	for _, column := range []string{"schema_registry_url", "schema_registry_username", "schema_registry_password"} {
+		var columnExists int
+		err := db.Get(&columnExists, "SELECT COUNT(*) FROM pragma_table_info('kafka_clusters') WHERE name=?", column)
+		if err != nil {
+			return err
+		}
+		if columnExists == 0 {
+			_, err = db.Exec("ALTER TABLE kafka_clusters ADD COLUMN " + column + " TEXT NOT NULL DEFAULT ''")
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addStartPositionColumns adds the start_position column to replication_jobs and topic_mappings
+func addStartPositionColumns(db *sqlx.DB) error {
+	for _, table := range []string{"replication_jobs", "topic_mappings"} {
+		var columnExists int
+		err := db.Get(&columnExists, "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name='start_position'", table)
+		if err != nil {
+			return err
+		}
+		if columnExists == 0 {
+			_, err = db.Exec("ALTER TABLE " + table + " ADD COLUMN start_position TEXT")
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addJobTypeColumn adds the job_type column to the replication_jobs table
+func addJobTypeColumn(db *sqlx.DB) error {
+	var columnExists int
+	err := db.Get(&columnExists, "SELECT COUNT(*) FROM pragma_table_info('replication_jobs') WHERE name='job_type'")
+	if err != nil {
+		return err
+	}
+	if columnExists == 0 {
+		_, err = db.Exec("ALTER TABLE replication_jobs ADD COLUMN job_type TEXT NOT NULL DEFAULT 'continuous' CHECK(job_type IN ('continuous', 'backfill'))")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addErrorPolicyColumn adds the error_policy column to replication_jobs
+func addErrorPolicyColumn(db *sqlx.DB) error {
+	var columnExists int
+	err := db.Get(&columnExists, "SELECT COUNT(*) FROM pragma_table_info('replication_jobs') WHERE name='error_policy'")
+	if err != nil {
+		return err
+	}
+	if columnExists == 0 {
+		_, err = db.Exec("ALTER TABLE replication_jobs ADD COLUMN error_policy TEXT")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addUserDisabledReasonColumn adds the disabled_reason column to users. Accounts
+// disabled before it existed keep an empty reason and are not re-enabled by LDAP.
+func addUserDisabledReasonColumn(db *sqlx.DB) error {
+	var columnExists int
+	err := db.Get(&columnExists, "SELECT COUNT(*) FROM pragma_table_info('users') WHERE name='disabled_reason'")
+	if err != nil {
+		return err
+	}
+	if columnExists == 0 {
+		_, err = db.Exec("ALTER TABLE users ADD COLUMN disabled_reason TEXT NOT NULL DEFAULT ''")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // addFailedReasonToJobs adds the failed_reason column to the replication_jobs table
 func addFailedReasonToJobs(db *sqlx.DB) error {
 	// Check if the column already exists
@@ -408,6 +738,18 @@ func ensureAggregatedMetricsColumns(db *sqlx.DB) error {
 			return err
 		}
 	}
+	if !existing["duplicates_skipped_delta"] {
+		if _, err := db.Exec("ALTER TABLE aggregated_metrics ADD COLUMN duplicates_skipped_delta INTEGER NOT NULL DEFAULT 0"); err != nil {
+			return err
+		}
+	}
+	for _, column := range []string{"lag_seconds_p50", "lag_seconds_p99", "lag_seconds_max"} {
+		if !existing[column] {
+			if _, err := db.Exec("ALTER TABLE aggregated_metrics ADD COLUMN " + column + " REAL NOT NULL DEFAULT 0"); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
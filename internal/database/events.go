@@ -13,15 +13,143 @@
 package database
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/jmoiron/sqlx"
 )
 
-// CreateOperationalEvent inserts a new operational event into the database.
+const (
+	hashPrefixSHA256 = "sha256:"
+	hashPrefixHMAC   = "hmac-sha256:"
+)
+
+var (
+	// auditChainMu serializes appends so each event links to its immediate predecessor.
+	auditChainMu sync.Mutex
+
+	auditConfigMu  sync.RWMutex
+	auditHMACKey   []byte
+	eventListeners []func(OperationalEvent)
+)
+
+// ConfigureAuditChain sets the optional HMAC key used to sign the operational event hash chain.
+// Without a key, events are chained with plain SHA-256.
+func ConfigureAuditChain(hmacKey string) {
+	auditConfigMu.Lock()
+	defer auditConfigMu.Unlock()
+	if hmacKey == "" {
+		auditHMACKey = nil
+		return
+	}
+	auditHMACKey = []byte(hmacKey)
+}
+
+// AddOperationalEventListener registers a callback invoked after each event is committed.
+// Listeners must not block; they are used to stream events to external sinks.
+func AddOperationalEventListener(listener func(OperationalEvent)) {
+	auditConfigMu.Lock()
+	defer auditConfigMu.Unlock()
+	eventListeners = append(eventListeners, listener)
+}
+
+// ResetOperationalEventListeners removes all registered listeners.
+func ResetOperationalEventListeners() {
+	auditConfigMu.Lock()
+	defer auditConfigMu.Unlock()
+	eventListeners = nil
+}
+
+// ComputeEventHash returns the chain hash of an event. With a key the hash is an HMAC-SHA256,
+// otherwise a plain SHA-256. The hash covers the ID, timestamp, type, initiator, details and prev_hash.
+func ComputeEventHash(event *OperationalEvent, key []byte) string {
+	var h hash.Hash
+	prefix := hashPrefixSHA256
+	if len(key) > 0 {
+		h = hmac.New(sha256.New, key)
+		prefix = hashPrefixHMAC
+	} else {
+		h = sha256.New()
+	}
+	fmt.Fprintf(h, "%d\x1f%s\x1f%s\x1f%s\x1f%s\x1f%s",
+		event.ID,
+		event.Timestamp.UTC().Format(time.RFC3339Nano),
+		event.EventType,
+		event.Initiator,
+		event.Details,
+		event.PrevHash)
+	return prefix + hex.EncodeToString(h.Sum(nil))
+}
+
+// isHMACHash reports whether a stored hash was produced with an HMAC key.
+func isHMACHash(h string) bool {
+	return strings.HasPrefix(h, hashPrefixHMAC)
+}
+
+// CreateOperationalEvent appends a new operational event to the hash chain.
+// The event's ID, timestamp, prev_hash and hash are filled in on success.
 func CreateOperationalEvent(db *sqlx.DB, event *OperationalEvent) error {
-	query := `INSERT INTO operational_events (event_type, initiator, details)
-              VALUES (?, ?, ?)`
-	_, err := db.Exec(query, event.EventType, event.Initiator, event.Details)
-	return err
+	auditConfigMu.RLock()
+	key := auditHMACKey
+	listeners := eventListeners
+	auditConfigMu.RUnlock()
+
+	auditChainMu.Lock()
+	tx, err := db.Beginx()
+	if err != nil {
+		auditChainMu.Unlock()
+		return err
+	}
+
+	var prevHash string
+	err = tx.Get(&prevHash, "SELECT hash FROM operational_events ORDER BY id DESC LIMIT 1")
+	if err != nil && err != sql.ErrNoRows {
+		tx.Rollback()
+		auditChainMu.Unlock()
+		return err
+	}
+
+	// The hash covers the row ID, so the row is inserted first and sealed within the same transaction.
+	event.Timestamp = time.Now().UTC().Truncate(time.Microsecond)
+	event.PrevHash = prevHash
+	res, err := tx.Exec(`INSERT INTO operational_events (event_type, initiator, details, timestamp, prev_hash)
+                         VALUES (?, ?, ?, ?, ?)`, event.EventType, event.Initiator, event.Details, event.Timestamp, event.PrevHash)
+	if err != nil {
+		tx.Rollback()
+		auditChainMu.Unlock()
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		auditChainMu.Unlock()
+		return err
+	}
+	event.ID = int(id)
+	event.Hash = ComputeEventHash(event, key)
+
+	if _, err := tx.Exec("UPDATE operational_events SET hash = ? WHERE id = ?", event.Hash, event.ID); err != nil {
+		tx.Rollback()
+		auditChainMu.Unlock()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		auditChainMu.Unlock()
+		return err
+	}
+	auditChainMu.Unlock()
+
+	for _, listener := range listeners {
+		listener(*event)
+	}
+	return nil
 }
 
 // GetOperationalEvent retrieves a single operational event by its ID.
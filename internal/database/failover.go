@@ -0,0 +1,154 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Failover operation statuses.
+const (
+	FailoverRunning   = "running"
+	FailoverFailed    = "failed"
+	FailoverCompleted = "completed"
+)
+
+// Failover step statuses. Planned is only used in dry-run reports.
+const (
+	FailoverStepPending   = "pending"
+	FailoverStepRunning   = "running"
+	FailoverStepCompleted = "completed"
+	FailoverStepFailed    = "failed"
+	FailoverStepSkipped   = "skipped"
+	FailoverStepPlanned   = "planned"
+)
+
+// CreateFailoverOperation stores a new failover operation.
+func CreateFailoverOperation(db *sqlx.DB, op *FailoverOperation) error {
+	if err := op.encode(); err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	query := `INSERT INTO failover_operations (job_id, status, options, steps, checkpoint_id, reverse_job_id, last_error, initiator, created_at, updated_at, completed_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := db.Exec(query, op.JobID, op.Status, op.OptionsJSON, op.StepsJSON, op.CheckpointID, op.ReverseJobID,
+		op.LastError, op.Initiator, now, now, op.CompletedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create failover operation: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	op.ID = int(id)
+	op.CreatedAt = now
+	op.UpdatedAt = now
+	return nil
+}
+
+// UpdateFailoverOperation saves the status, steps and results of a failover operation.
+func UpdateFailoverOperation(db *sqlx.DB, op *FailoverOperation) error {
+	if err := op.encode(); err != nil {
+		return err
+	}
+	op.UpdatedAt = time.Now().UTC()
+	query := `UPDATE failover_operations SET status = ?, steps = ?, checkpoint_id = ?, reverse_job_id = ?, last_error = ?, updated_at = ?, completed_at = ?
+              WHERE id = ?`
+	result, err := db.Exec(query, op.Status, op.StepsJSON, op.CheckpointID, op.ReverseJobID, op.LastError,
+		op.UpdatedAt, op.CompletedAt, op.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update failover operation: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetFailoverOperation retrieves a failover operation by ID.
+func GetFailoverOperation(db *sqlx.DB, id int) (*FailoverOperation, error) {
+	var op FailoverOperation
+	if err := db.Get(&op, "SELECT * FROM failover_operations WHERE id = ?", id); err != nil {
+		return nil, err
+	}
+	if err := op.decode(); err != nil {
+		return nil, err
+	}
+	return &op, nil
+}
+
+// ListFailoverOperations returns the failover operations of a job, newest first.
+func ListFailoverOperations(db *sqlx.DB, jobID string) ([]FailoverOperation, error) {
+	ops := []FailoverOperation{}
+	if err := db.Select(&ops, "SELECT * FROM failover_operations WHERE job_id = ? ORDER BY created_at DESC, id DESC", jobID); err != nil {
+		return nil, fmt.Errorf("failed to list failover operations: %w", err)
+	}
+	for i := range ops {
+		if err := ops[i].decode(); err != nil {
+			return nil, err
+		}
+	}
+	return ops, nil
+}
+
+// ListRunningFailoverOperations returns the failover operations of every job that are in progress.
+func ListRunningFailoverOperations(db *sqlx.DB) ([]FailoverOperation, error) {
+	ops := []FailoverOperation{}
+	if err := db.Select(&ops, "SELECT * FROM failover_operations WHERE status = ? ORDER BY id", FailoverRunning); err != nil {
+		return nil, fmt.Errorf("failed to list running failover operations: %w", err)
+	}
+	for i := range ops {
+		if err := ops[i].decode(); err != nil {
+			return nil, err
+		}
+	}
+	return ops, nil
+}
+
+// HasRunningFailover reports whether a failover of the job is in progress.
+func HasRunningFailover(db *sqlx.DB, jobID string) (bool, error) {
+	var count int
+	err := db.Get(&count, "SELECT COUNT(*) FROM failover_operations WHERE job_id = ? AND status = ?", jobID, FailoverRunning)
+	return count > 0, err
+}
+
+func (op *FailoverOperation) encode() error {
+	options, err := json.Marshal(op.Options)
+	if err != nil {
+		return err
+	}
+	if op.Steps == nil {
+		op.Steps = []FailoverStep{}
+	}
+	steps, err := json.Marshal(op.Steps)
+	if err != nil {
+		return err
+	}
+	op.OptionsJSON = string(options)
+	op.StepsJSON = string(steps)
+	return nil
+}
+
+func (op *FailoverOperation) decode() error {
+	if err := json.Unmarshal([]byte(op.OptionsJSON), &op.Options); err != nil {
+		return fmt.Errorf("failover operation %d has invalid options: %w", op.ID, err)
+	}
+	if err := json.Unmarshal([]byte(op.StepsJSON), &op.Steps); err != nil {
+		return fmt.Errorf("failover operation %d has invalid steps: %w", op.ID, err)
+	}
+	return nil
+}
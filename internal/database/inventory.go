@@ -147,6 +147,24 @@ func InsertConnectionInventory(db *sqlx.DB, inventory ConnectionInventory) error
 	return nil
 }
 
+func InsertACLInventory(db *sqlx.DB, inventory ACLInventory) error {
+	query := `
+		INSERT INTO acl_inventory 
+		(snapshot_id, cluster_type, principal, host, resource_type, 
+		 resource_name, pattern_type, operation, permission)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	
+	_, err := db.Exec(query,
+		inventory.SnapshotID, inventory.ClusterType, inventory.Principal,
+		inventory.Host, inventory.ResourceType, inventory.ResourceName,
+		inventory.PatternType, inventory.Operation, inventory.Permission)
+	if err != nil {
+		return fmt.Errorf("failed to insert ACL inventory: %w", err)
+	}
+	
+	return nil
+}
+
 func GetInventorySnapshots(db *sqlx.DB, jobID string) ([]JobInventorySnapshot, error) {
 	query := `
 		SELECT id, job_id, snapshot_type, created_at
@@ -184,12 +202,25 @@ func GetFullInventoryData(db *sqlx.DB, snapshotID int) (*InventoryData, error) {
 		return nil, err
 	}
 	
+	acls, err := getACLInventories(db, snapshotID)
+	if err != nil {
+		return nil, err
+	}
+	
 	data := &InventoryData{
 		Snapshot:       *snapshot,
 		ConsumerGroups: consumerGroups,
 		Connections:    connections,
 	}
 	
+	for _, acl := range acls {
+		if acl.ClusterType == "source" {
+			data.SourceACLs = append(data.SourceACLs, acl)
+		} else {
+			data.TargetACLs = append(data.TargetACLs, acl)
+		}
+	}
+	
 	for _, cluster := range clusters {
 		topics, err := getTopicInventories(db, cluster.ID)
 		if err != nil {
@@ -347,6 +378,23 @@ func getConnectionInventories(db *sqlx.DB, snapshotID int) ([]ConnectionInventor
 	return connections, nil
 }
 
+func getACLInventories(db *sqlx.DB, snapshotID int) ([]ACLInventory, error) {
+	query := `
+		SELECT id, snapshot_id, cluster_type, principal, host, resource_type,
+		       resource_name, pattern_type, operation, permission
+		FROM acl_inventory
+		WHERE snapshot_id = ?
+		ORDER BY cluster_type, resource_type, resource_name, principal`
+	
+	var acls []ACLInventory
+	err := db.Select(&acls, query, snapshotID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ACL inventories: %w", err)
+	}
+	
+	return acls, nil
+}
+
 func PruneOldInventorySnapshots(db *sqlx.DB) error {
 	cutoff := time.Now().AddDate(0, 0, -30)
 	
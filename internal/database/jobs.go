@@ -13,12 +13,85 @@
 package database
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"kaf-mirror/internal/config"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 )
 
+// Job types. Continuous jobs mirror until they are stopped; backfill jobs copy
+// bounded partition ranges and stop once they are done.
+const (
+	JobTypeContinuous = "continuous"
+	JobTypeBackfill   = "backfill"
+)
+
+// StartPosition is a job or mapping start position, stored as JSON.
+type StartPosition config.StartPosition
+
+// Scan implements sql.Scanner.
+func (p *StartPosition) Scan(src interface{}) error {
+	var data []byte
+	switch value := src.(type) {
+	case string:
+		data = []byte(value)
+	case []byte:
+		data = value
+	default:
+		return fmt.Errorf("cannot scan %T into a start position", src)
+	}
+	return json.Unmarshal(data, p)
+}
+
+// Value implements driver.Valuer.
+func (p StartPosition) Value() (driver.Value, error) {
+	data, err := json.Marshal(p)
+	return string(data), err
+}
+
+// startPositionValue stores unset start positions as NULL.
+func startPositionValue(p *StartPosition) interface{} {
+	if p == nil || p.Mode == "" {
+		return nil
+	}
+	return p
+}
+
+// ErrorPolicy is the error policy of a job, stored as JSON.
+type ErrorPolicy config.ErrorPolicy
+
+// Scan implements sql.Scanner.
+func (p *ErrorPolicy) Scan(src interface{}) error {
+	var data []byte
+	switch value := src.(type) {
+	case string:
+		data = []byte(value)
+	case []byte:
+		data = value
+	default:
+		return fmt.Errorf("cannot scan %T into an error policy", src)
+	}
+	return json.Unmarshal(data, p)
+}
+
+// Value implements driver.Valuer.
+func (p ErrorPolicy) Value() (driver.Value, error) {
+	data, err := json.Marshal(p)
+	return string(data), err
+}
+
+// errorPolicyValue stores jobs without their own error policy as NULL.
+func errorPolicyValue(p *ErrorPolicy) interface{} {
+	if p == nil {
+		return nil
+	}
+	return p
+}
+
 // ListJobs retrieves all replication jobs from the database.
 func ListJobs(db *sqlx.DB) ([]ReplicationJob, error) {
 	var jobs []ReplicationJob
@@ -45,9 +118,13 @@ func CreateJob(db *sqlx.DB, job *ReplicationJob) error {
 		return errors.New("a job with this name already exists")
 	}
 
-	query := `INSERT INTO replication_jobs (id, name, source_cluster_name, target_cluster_name, status, batch_size, parallelism, compression, preserve_partitions, created_at, updated_at)
-              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-	_, err = db.Exec(query, job.ID, job.Name, job.SourceClusterName, job.TargetClusterName, job.Status, job.BatchSize, job.Parallelism, job.Compression, job.PreservePartitions, time.Now(), time.Now())
+	if job.JobType == "" {
+		job.JobType = JobTypeContinuous
+	}
+
+	query := `INSERT INTO replication_jobs (id, name, source_cluster_name, target_cluster_name, status, batch_size, parallelism, compression, preserve_partitions, start_position, error_policy, job_type, created_at, updated_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err = db.Exec(query, job.ID, job.Name, job.SourceClusterName, job.TargetClusterName, job.Status, job.BatchSize, job.Parallelism, job.Compression, job.PreservePartitions, startPositionValue(job.StartPosition), errorPolicyValue(job.ErrorPolicy), job.JobType, time.Now(), time.Now())
 	return err
 }
 
@@ -64,9 +141,9 @@ func UpdateJob(db *sqlx.DB, job *ReplicationJob) error {
 	}
 
 	query := `UPDATE replication_jobs 
-              SET name = ?, source_cluster_name = ?, target_cluster_name = ?, status = ?, failed_reason = ?, updated_at = ?
+              SET name = ?, source_cluster_name = ?, target_cluster_name = ?, status = ?, failed_reason = ?, start_position = ?, error_policy = ?, updated_at = ?
               WHERE id = ?`
-	_, err = db.Exec(query, job.Name, job.SourceClusterName, job.TargetClusterName, job.Status, job.FailedReason, time.Now(), job.ID)
+	_, err = db.Exec(query, job.Name, job.SourceClusterName, job.TargetClusterName, job.Status, job.FailedReason, startPositionValue(job.StartPosition), errorPolicyValue(job.ErrorPolicy), time.Now(), job.ID)
 	return err
 }
 
@@ -37,9 +37,9 @@ func UpdateMappingsForJob(db *sqlx.DB, jobID string, mappings []TopicMapping) er
 	}
 
 	for _, m := range mappings {
-		query := `INSERT INTO topic_mappings (job_id, source_topic_pattern, target_topic_pattern, enabled)
-				  VALUES (?, ?, ?, ?)`
-		_, err = tx.Exec(query, jobID, m.SourceTopicPattern, m.TargetTopicPattern, m.Enabled)
+		query := `INSERT INTO topic_mappings (job_id, source_topic_pattern, target_topic_pattern, enabled, start_position)
+				  VALUES (?, ?, ?, ?, ?)`
+		_, err = tx.Exec(query, jobID, m.SourceTopicPattern, m.TargetTopicPattern, m.Enabled, startPositionValue(m.StartPosition))
 		if err != nil {
 			tx.Rollback()
 			return err
@@ -31,6 +31,7 @@ func InsertMetrics(db *sqlx.DB, metric *ReplicationMetric) error {
 	consumedMessagesDelta := metric.MessagesConsumed - lastMetric.MessagesConsumed
 	consumedBytesDelta := metric.BytesConsumed - lastMetric.BytesConsumed
 	errorsDelta := metric.ErrorCount - lastMetric.ErrorCount
+	duplicatesDelta := metric.DuplicatesSkipped - lastMetric.DuplicatesSkipped
 
 	if messagesDelta < 0 {
 		messagesDelta = metric.MessagesReplicated
@@ -47,6 +48,9 @@ func InsertMetrics(db *sqlx.DB, metric *ReplicationMetric) error {
 	if errorsDelta < 0 {
 		errorsDelta = metric.ErrorCount
 	}
+	if duplicatesDelta < 0 {
+		duplicatesDelta = metric.DuplicatesSkipped
+	}
 
 	// Insert into the aggregated table
 	query := `INSERT INTO aggregated_metrics (
@@ -57,9 +61,14 @@ func InsertMetrics(db *sqlx.DB, metric *ReplicationMetric) error {
 			  messages_consumed_delta,
 			  bytes_consumed_delta,
 			  avg_lag,
-			  error_count_delta
-			) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
-	_, err = db.Exec(query, metric.JobID, time.Now(), messagesDelta, bytesDelta, consumedMessagesDelta, consumedBytesDelta, metric.CurrentLag, errorsDelta)
+			  error_count_delta,
+			  duplicates_skipped_delta,
+			  lag_seconds_p50,
+			  lag_seconds_p99,
+			  lag_seconds_max
+			) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err = db.Exec(query, metric.JobID, time.Now(), messagesDelta, bytesDelta, consumedMessagesDelta, consumedBytesDelta, metric.CurrentLag, errorsDelta, duplicatesDelta,
+		metric.LagSecondsP50, metric.LagSecondsP99, metric.LagSecondsMax)
 	return err
 }
 
@@ -71,6 +80,7 @@ func GetLatestMetrics(db *sqlx.DB, jobID string) (*ReplicationMetric, error) {
 		MessagesConsumed   int `db:"messages_consumed"`
 		BytesConsumed      int `db:"bytes_consumed"`
 		ErrorCount         int `db:"error_count"`
+		DuplicatesSkipped  int `db:"duplicates_skipped"`
 	}
 	totalsQuery := `
         SELECT
@@ -78,7 +88,8 @@ func GetLatestMetrics(db *sqlx.DB, jobID string) (*ReplicationMetric, error) {
             COALESCE(SUM(bytes_transferred_delta), 0) as bytes_transferred,
             COALESCE(SUM(messages_consumed_delta), 0) as messages_consumed,
             COALESCE(SUM(bytes_consumed_delta), 0) as bytes_consumed,
-            COALESCE(SUM(error_count_delta), 0) as error_count
+            COALESCE(SUM(error_count_delta), 0) as error_count,
+            COALESCE(SUM(duplicates_skipped_delta), 0) as duplicates_skipped
         FROM aggregated_metrics
         WHERE job_id = ?
     `
@@ -88,10 +99,13 @@ func GetLatestMetrics(db *sqlx.DB, jobID string) (*ReplicationMetric, error) {
 	}
 
 	var lastMetric struct {
-		CurrentLag int       `db:"avg_lag"`
-		Timestamp  time.Time `db:"timestamp"`
+		CurrentLag    int       `db:"avg_lag"`
+		LagSecondsP50 float64   `db:"lag_seconds_p50"`
+		LagSecondsP99 float64   `db:"lag_seconds_p99"`
+		LagSecondsMax float64   `db:"lag_seconds_max"`
+		Timestamp     time.Time `db:"timestamp"`
 	}
-	lastMetricQuery := "SELECT avg_lag, timestamp FROM aggregated_metrics WHERE job_id = ? ORDER BY timestamp DESC LIMIT 1"
+	lastMetricQuery := "SELECT avg_lag, lag_seconds_p50, lag_seconds_p99, lag_seconds_max, timestamp FROM aggregated_metrics WHERE job_id = ? ORDER BY timestamp DESC LIMIT 1"
 	err = db.Get(&lastMetric, lastMetricQuery, jobID)
 	// Ignore error if no rows, lag and timestamp will be zero.
 
@@ -102,7 +116,11 @@ func GetLatestMetrics(db *sqlx.DB, jobID string) (*ReplicationMetric, error) {
 		MessagesConsumed:   totals.MessagesConsumed,
 		BytesConsumed:      totals.BytesConsumed,
 		ErrorCount:         totals.ErrorCount,
+		DuplicatesSkipped:  totals.DuplicatesSkipped,
 		CurrentLag:         lastMetric.CurrentLag,
+		LagSecondsP50:      lastMetric.LagSecondsP50,
+		LagSecondsP99:      lastMetric.LagSecondsP99,
+		LagSecondsMax:      lastMetric.LagSecondsMax,
 		Timestamp:          lastMetric.Timestamp,
 	}, nil
 }
@@ -133,7 +151,10 @@ func GetAggregatedHistoricalMetrics(db *sqlx.DB, jobID string, periodDays int, g
             ` + groupBy + ` as period,
             SUM(messages_replicated_delta) as avg_throughput,
             AVG(avg_lag) as avg_lag,
-            SUM(error_count_delta) as total_errors
+            SUM(error_count_delta) as total_errors,
+            AVG(lag_seconds_p50) as lag_seconds_p50,
+            MAX(lag_seconds_p99) as lag_seconds_p99,
+            MAX(lag_seconds_max) as lag_seconds_max
         FROM aggregated_metrics
         WHERE job_id = ? AND timestamp >= datetime('now', '-' || ? || ' days')
         GROUP BY period
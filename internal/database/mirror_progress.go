@@ -126,6 +126,24 @@ func GetLatestMigrationCheckpoint(db *sqlx.DB, jobID string) (*MigrationCheckpoi
 	return &checkpoint, nil
 }
 
+// GetMigrationCheckpoint retrieves a migration checkpoint by ID.
+func GetMigrationCheckpoint(db *sqlx.DB, checkpointID int) (*MigrationCheckpoint, error) {
+	var checkpoint MigrationCheckpoint
+	if err := db.Get(&checkpoint, "SELECT * FROM migration_checkpoints WHERE id = ?", checkpointID); err != nil {
+		return nil, fmt.Errorf("failed to get migration checkpoint %d: %w", checkpointID, err)
+	}
+	return &checkpoint, nil
+}
+
+// UpdateMigrationCheckpointValidation stores the validation results of a checkpoint.
+func UpdateMigrationCheckpointValidation(db *sqlx.DB, checkpointID int, results string) error {
+	_, err := db.Exec("UPDATE migration_checkpoints SET validation_results = ? WHERE id = ?", results, checkpointID)
+	if err != nil {
+		return fmt.Errorf("failed to update migration checkpoint validation: %w", err)
+	}
+	return nil
+}
+
 func CalculateResumePoints(db *sqlx.DB, jobID string, resumePointsData map[string]map[int32]int64, checkpointID *int) error {
 	tx, err := db.Beginx()
 	if err != nil {
@@ -397,6 +415,12 @@ func GetMirrorStateData(db *sqlx.DB, jobID string) (*MirrorStateData, error) {
 		data.LastCheckpoint = checkpoint
 	}
 
+	verification, err := GetLatestContentVerification(db, jobID)
+	if err != nil {
+		return nil, err
+	}
+	data.ContentVerification = verification
+
 	return data, nil
 }
 
@@ -15,59 +15,130 @@ import "time"
 
 // KafkaCluster represents a Kafka cluster's connection details.
 type KafkaCluster struct {
-	Name             string    `db:"name" json:"name"`
-	Provider         string    `db:"provider" json:"provider"`
-	ClusterID        string    `db:"cluster_id" json:"cluster_id"`
-	Brokers          string    `db:"brokers" json:"brokers"`
-	SecurityConfig   string    `db:"security_config" json:"security_config"`
-	APIKey           string    `db:"api_key" json:"api_key"`
-	APISecret        string    `db:"api_secret" json:"api_secret"`
-	ConnectionString *string   `db:"connection_string" json:"connection_string"`
-	Status           string    `db:"status" json:"status"`
-	UpdatedAt        time.Time `db:"updated_at" json:"updated_at"`
+	Name                   string    `db:"name" json:"name"`
+	Provider               string    `db:"provider" json:"provider"`
+	ClusterID              string    `db:"cluster_id" json:"cluster_id"`
+	Brokers                string    `db:"brokers" json:"brokers"`
+	SecurityConfig         string    `db:"security_config" json:"security_config"`
+	APIKey                 string    `db:"api_key" json:"api_key"`
+	APISecret              string    `db:"api_secret" json:"api_secret"`
+	ConnectionString       *string   `db:"connection_string" json:"connection_string"`
+	SchemaRegistryURL      string    `db:"schema_registry_url" json:"schema_registry_url"`
+	SchemaRegistryUsername string    `db:"schema_registry_username" json:"schema_registry_username"`
+	SchemaRegistryPassword string    `db:"schema_registry_password" json:"schema_registry_password"`
+	Status                 string    `db:"status" json:"status"`
+	UpdatedAt              time.Time `db:"updated_at" json:"updated_at"`
 }
 
 // ReplicationJob represents a single replication job stored in the database.
 type ReplicationJob struct {
-	ID                 string    `db:"id" json:"id"`
-	Name               string    `db:"name" json:"name"`
-	SourceClusterName  string    `db:"source_cluster_name" json:"source_cluster_name"`
-	TargetClusterName  string    `db:"target_cluster_name" json:"target_cluster_name"`
-	Status             string    `db:"status" json:"status"`
-	FailedReason       *string   `db:"failed_reason" json:"failed_reason"`
-	BatchSize          int       `db:"batch_size" json:"batch_size"`
-	Parallelism        int       `db:"parallelism" json:"parallelism"`
-	Compression        string    `db:"compression" json:"compression"`
-	PreservePartitions bool      `db:"preserve_partitions" json:"preserve_partitions"`
-	CreatedAt          time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt          time.Time `db:"updated_at" json:"updated_at"`
+	ID                 string         `db:"id" json:"id"`
+	Name               string         `db:"name" json:"name"`
+	SourceClusterName  string         `db:"source_cluster_name" json:"source_cluster_name"`
+	TargetClusterName  string         `db:"target_cluster_name" json:"target_cluster_name"`
+	Status             string         `db:"status" json:"status"`
+	FailedReason       *string        `db:"failed_reason" json:"failed_reason"`
+	BatchSize          int            `db:"batch_size" json:"batch_size"`
+	Parallelism        int            `db:"parallelism" json:"parallelism"`
+	Compression        string         `db:"compression" json:"compression"`
+	PreservePartitions bool           `db:"preserve_partitions" json:"preserve_partitions"`
+	StartPosition      *StartPosition `db:"start_position" json:"start_position,omitempty"`
+	ErrorPolicy        *ErrorPolicy   `db:"error_policy" json:"error_policy,omitempty"`
+	JobType            string         `db:"job_type" json:"job_type"`
+	CreatedAt          time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt          time.Time      `db:"updated_at" json:"updated_at"`
 }
 
 // TopicMapping represents a topic mapping rule within a job.
 type TopicMapping struct {
-	ID                 int    `db:"id" json:"id"`
-	JobID              string `db:"job_id" json:"job_id"`
-	SourceTopicPattern string `db:"source_topic_pattern" json:"source_topic_pattern"`
-	TargetTopicPattern string `db:"target_topic_pattern" json:"target_topic_pattern"`
-	Enabled            bool   `db:"enabled" json:"enabled"`
+	ID                 int            `db:"id" json:"id"`
+	JobID              string         `db:"job_id" json:"job_id"`
+	SourceTopicPattern string         `db:"source_topic_pattern" json:"source_topic_pattern"`
+	TargetTopicPattern string         `db:"target_topic_pattern" json:"target_topic_pattern"`
+	Enabled            bool           `db:"enabled" json:"enabled"`
+	StartPosition      *StartPosition `db:"start_position" json:"start_position,omitempty"`
 }
 
 // ReplicationMetric represents a single data point of replication metrics.
 type ReplicationMetric struct {
-	ID                 int       `db:"id" json:"id"`
+	ID                 int               `db:"id" json:"id"`
+	JobID              string            `db:"job_id" json:"job_id"`
+	MessagesReplicated int               `db:"messages_replicated" json:"messages_replicated"`
+	BytesTransferred   int               `db:"bytes_transferred" json:"bytes_transferred"`
+	MessagesConsumed   int               `db:"messages_consumed" json:"messages_consumed"`
+	BytesConsumed      int               `db:"bytes_consumed" json:"bytes_consumed"`
+	CurrentLag         int               `db:"current_lag" json:"current_lag"`
+	ErrorCount         int               `db:"error_count" json:"error_count"`
+	DuplicatesSkipped  int               `db:"duplicates_skipped" json:"duplicates_skipped"`
+	DeadLettered       int               `db:"-" json:"dead_lettered"`
+	ErrorClasses       map[string]int    `db:"-" json:"error_classes,omitempty"`
+	SourceStalled      bool              `db:"-" json:"source_stalled"`
+	TargetStalled      bool              `db:"-" json:"target_stalled"`
+	CriticalLag        bool              `db:"-" json:"critical_lag"`
+	HighErrorRate      bool              `db:"-" json:"high_error_rate"`
+	ErrorSpike         bool              `db:"-" json:"error_spike"`
+	LagSecondsP50      float64           `db:"lag_seconds_p50" json:"lag_seconds_p50"`
+	LagSecondsP99      float64           `db:"lag_seconds_p99" json:"lag_seconds_p99"`
+	LagSecondsMax      float64           `db:"lag_seconds_max" json:"lag_seconds_max"`
+	TopicLags          []TopicLag        `db:"-" json:"topic_lags,omitempty"`
+	PartitionLags      []PartitionLag    `db:"-" json:"partition_lags,omitempty"`
+	PartitionMetrics   []PartitionMetric `db:"-" json:"-"`
+	Timestamp          time.Time         `db:"timestamp" json:"timestamp"`
+}
+
+// PartitionLag is the replication lag of a single source partition.
+type PartitionLag struct {
+	Topic         string  `json:"topic"`
+	Partition     int32   `json:"partition"`
+	MessageLag    int64   `json:"message_lag"`
+	LagSeconds    float64 `json:"lag_seconds"`
+	LagSecondsP50 float64 `json:"lag_seconds_p50"`
+	LagSecondsP99 float64 `json:"lag_seconds_p99"`
+	Samples       int     `json:"samples"`
+}
+
+// PartitionMetric is one point of the per-partition time series. Counters are
+// deltas over the bucket; lag values are the worst seen in the bucket.
+type PartitionMetric struct {
+	ID                 int       `db:"id" json:"-"`
 	JobID              string    `db:"job_id" json:"job_id"`
-	MessagesReplicated int       `db:"messages_replicated" json:"messages_replicated"`
-	BytesTransferred   int       `db:"bytes_transferred" json:"bytes_transferred"`
-	MessagesConsumed   int       `db:"messages_consumed" json:"messages_consumed"`
-	BytesConsumed      int       `db:"bytes_consumed" json:"bytes_consumed"`
-	CurrentLag         int       `db:"current_lag" json:"current_lag"`
-	ErrorCount         int       `db:"error_count" json:"error_count"`
-	SourceStalled      bool      `db:"-" json:"source_stalled"`
-	TargetStalled      bool      `db:"-" json:"target_stalled"`
-	CriticalLag        bool      `db:"-" json:"critical_lag"`
-	HighErrorRate      bool      `db:"-" json:"high_error_rate"`
-	ErrorSpike         bool      `db:"-" json:"error_spike"`
+	Topic              string    `db:"topic" json:"topic"`
+	Partition          int32     `db:"partition_id" json:"partition"`
 	Timestamp          time.Time `db:"timestamp" json:"timestamp"`
+	Resolution         int       `db:"resolution" json:"resolution_seconds"`
+	MessagesConsumed   int64     `db:"messages_consumed" json:"messages_consumed"`
+	BytesConsumed      int64     `db:"bytes_consumed" json:"bytes_consumed"`
+	MessagesReplicated int64     `db:"messages_replicated" json:"messages_replicated"`
+	BytesTransferred   int64     `db:"bytes_transferred" json:"bytes_transferred"`
+	ErrorCount         int64     `db:"error_count" json:"error_count"`
+	MessageLag         int64     `db:"message_lag" json:"message_lag"`
+	LagSeconds         float64   `db:"lag_seconds" json:"lag_seconds"`
+}
+
+// PartitionSummary totals the partition time series of one partition over a
+// time window. Lag values are taken from the most recent point.
+type PartitionSummary struct {
+	Topic              string    `json:"topic"`
+	Partition          int32     `json:"partition"`
+	MessagesConsumed   int64     `json:"messages_consumed"`
+	BytesConsumed      int64     `json:"bytes_consumed"`
+	MessagesReplicated int64     `json:"messages_replicated"`
+	BytesTransferred   int64     `json:"bytes_transferred"`
+	ErrorCount         int64     `json:"error_count"`
+	MessageLag         int64     `json:"message_lag"`
+	LagSeconds         float64   `json:"lag_seconds"`
+	MaxLagSeconds      float64   `json:"max_lag_seconds"`
+	LastSeen           time.Time `json:"last_seen"`
+}
+
+// TopicLag aggregates the replication lag of all partitions of a source topic.
+type TopicLag struct {
+	Topic         string  `json:"topic"`
+	Partitions    int     `json:"partitions"`
+	MessageLag    int64   `json:"message_lag"`
+	LagSecondsP50 float64 `json:"lag_seconds_p50"`
+	LagSecondsP99 float64 `json:"lag_seconds_p99"`
+	LagSecondsMax float64 `json:"lag_seconds_max"`
 }
 
 // AggregatedMetric represents a summarized view of metrics over a period.
@@ -82,6 +153,10 @@ type AggregatedMetric struct {
 	MessagesConsumedDelta   int       `db:"messages_consumed_delta" json:"messages_consumed_delta"`
 	BytesConsumedDelta      int       `db:"bytes_consumed_delta" json:"bytes_consumed_delta"`
 	ErrorCountDelta         int       `db:"error_count_delta" json:"error_count_delta"`
+	DuplicatesSkippedDelta  int       `db:"duplicates_skipped_delta" json:"duplicates_skipped_delta"`
+	LagSecondsP50           float64   `db:"lag_seconds_p50" json:"lag_seconds_p50"`
+	LagSecondsP99           float64   `db:"lag_seconds_p99" json:"lag_seconds_p99"`
+	LagSecondsMax           float64   `db:"lag_seconds_max" json:"lag_seconds_max"`
 	Timestamp               time.Time `db:"timestamp" json:"timestamp"`
 }
 
@@ -117,25 +192,54 @@ type OperationalEvent struct {
 	Initiator string    `db:"initiator" json:"initiator"`
 	Details   string    `db:"details" json:"details"`
 	Timestamp time.Time `db:"timestamp" json:"timestamp"`
+	PrevHash  string    `db:"prev_hash" json:"prev_hash"`
+	Hash      string    `db:"hash" json:"hash"`
 }
 
 // User represents a user account.
 type User struct {
-	ID           int       `db:"id" json:"id"`
-	Username     string    `db:"username" json:"username"`
-	PasswordHash string    `db:"password_hash" json:"-"`
-	IsInitial    bool      `db:"is_initial" json:"is_initial"`
-	CreatedAt    time.Time `db:"created_at" json:"created_at"`
+	ID                int        `db:"id" json:"id"`
+	Username          string     `db:"username" json:"username"`
+	PasswordHash      string     `db:"password_hash" json:"-"`
+	IsInitial         bool       `db:"is_initial" json:"is_initial"`
+	AuthSource        string     `db:"auth_source" json:"auth_source"`
+	Disabled          bool       `db:"disabled" json:"disabled"`
+	DisabledReason    string     `db:"disabled_reason" json:"disabled_reason,omitempty"`
+	FailedLoginCount  int        `db:"failed_login_count" json:"-"`
+	LastFailedLoginAt *time.Time `db:"last_failed_login_at" json:"-"`
+	LockedUntil       *time.Time `db:"locked_until" json:"locked_until,omitempty"`
+	CreatedAt         time.Time  `db:"created_at" json:"created_at"`
+}
+
+// IsLocked reports whether the account is temporarily locked after failed logins.
+func (u *User) IsLocked() bool {
+	return u.LockedUntil != nil && u.LockedUntil.After(time.Now())
 }
 
 // ApiToken represents an API token for a user.
 type ApiToken struct {
-	ID          int       `db:"id" json:"id"`
-	UserID      int       `db:"user_id" json:"user_id"`
-	TokenHash   string    `db:"token_hash" json:"-"`
-	Description string    `db:"description" json:"description"`
-	ExpiresAt   time.Time `db:"expires_at" json:"expires_at"`
-	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	ID          int        `db:"id" json:"id"`
+	UserID      int        `db:"user_id" json:"user_id"`
+	Name        string     `db:"name" json:"name"`
+	TokenHash   string     `db:"token_hash" json:"-"`
+	Description string     `db:"description" json:"description"`
+	Permissions string     `db:"permissions" json:"permissions"`
+	JobIDs      string     `db:"job_ids" json:"job_ids"`
+	AllowedIPs  string     `db:"allowed_ips" json:"allowed_ips"`
+	ExpiresAt   time.Time  `db:"expires_at" json:"expires_at"`
+	LastUsedAt  *time.Time `db:"last_used_at" json:"last_used_at,omitempty"`
+	LastUsedIP  string     `db:"last_used_ip" json:"last_used_ip,omitempty"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+}
+
+// ApiTokenScope describes the restrictions of a named API token.
+type ApiTokenScope struct {
+	Name        string
+	Description string
+	Permissions []string
+	JobIDs      []string
+	AllowedIPs  []string
+	ExpiresAt   time.Time
 }
 
 type JobInventorySnapshot struct {
@@ -213,6 +317,19 @@ type ConnectionInventory struct {
 	ErrorMessage         string `db:"error_message" json:"error_message,omitempty"`
 }
 
+type ACLInventory struct {
+	ID           int    `db:"id" json:"id"`
+	SnapshotID   int    `db:"snapshot_id" json:"snapshot_id"`
+	ClusterType  string `db:"cluster_type" json:"cluster_type"`
+	Principal    string `db:"principal" json:"principal"`
+	Host         string `db:"host" json:"host"`
+	ResourceType string `db:"resource_type" json:"resource_type"`
+	ResourceName string `db:"resource_name" json:"resource_name"`
+	PatternType  string `db:"pattern_type" json:"pattern_type"`
+	Operation    string `db:"operation" json:"operation"`
+	Permission   string `db:"permission" json:"permission"`
+}
+
 type InventoryData struct {
 	Snapshot         JobInventorySnapshot     `json:"snapshot"`
 	SourceCluster    *ClusterInventory        `json:"source_cluster,omitempty"`
@@ -224,6 +341,8 @@ type InventoryData struct {
 	ConsumerGroups   []ConsumerGroupInventory `json:"consumer_groups,omitempty"`
 	ConsumerOffsets  []ConsumerGroupOffset    `json:"consumer_offsets,omitempty"`
 	Connections      []ConnectionInventory    `json:"connections,omitempty"`
+	SourceACLs       []ACLInventory           `json:"source_acls,omitempty"`
+	TargetACLs       []ACLInventory           `json:"target_acls,omitempty"`
 }
 
 // MirrorProgress tracks replication progress per job and topic partition
@@ -287,6 +406,54 @@ type MirrorStateAnalysis struct {
 	AnalyzerVersion     string    `db:"analyzer_version" json:"analyzer_version"`
 }
 
+// ContentVerification is a run comparing the records of a job's topics on the
+// source and target clusters by content rather than by offsets.
+type ContentVerification struct {
+	ID                int                     `db:"id" json:"id"`
+	JobID             string                  `db:"job_id" json:"job_id"`
+	Status            string                  `db:"status" json:"status"`
+	SampleRate        float64                 `db:"sample_rate" json:"sample_rate"`
+	RecordsCompared   int64                   `db:"records_compared" json:"records_compared"`
+	MismatchedRecords int64                   `db:"mismatched_records" json:"mismatched_records"`
+	MissingRecords    int64                   `db:"missing_records" json:"missing_records"`
+	DuplicateRecords  int64                   `db:"duplicate_records" json:"duplicate_records"`
+	Consistent        bool                    `db:"consistent" json:"consistent"`
+	PartitionsJSON    string                  `db:"partitions" json:"-"`
+	Partitions        []PartitionVerification `db:"-" json:"partitions"`
+	LastError         *string                 `db:"last_error" json:"last_error,omitempty"`
+	Initiator         string                  `db:"initiator" json:"initiator"`
+	StartedAt         time.Time               `db:"started_at" json:"started_at"`
+	CompletedAt       *time.Time              `db:"completed_at" json:"completed_at,omitempty"`
+}
+
+// PartitionVerification is the content comparison of one source partition with
+// its target partition. Offsets are source offsets unless noted otherwise.
+type PartitionVerification struct {
+	SourceTopic string `json:"source_topic"`
+	TargetTopic string `json:"target_topic"`
+	Partition   int32  `json:"partition"`
+	StartOffset int64  `json:"start_offset"`
+	EndOffset   int64  `json:"end_offset"` // exclusive
+	// OffsetDelta is the target offset minus the source offset of a record at
+	// the start of the range.
+	OffsetDelta     int64 `json:"offset_delta"`
+	RecordsCompared int64 `json:"records_compared"`
+	Matched         int64 `json:"matched"`
+	Mismatched      int64 `json:"mismatched"`
+	Missing         int64 `json:"missing"`
+	Duplicates      int64 `json:"duplicates"`
+	// SourceDigest and TargetDigest are rolling hashes over the compared records
+	// of each side; they are equal when both sides hold the same records in order.
+	SourceDigest      string  `json:"source_digest,omitempty"`
+	TargetDigest      string  `json:"target_digest,omitempty"`
+	MismatchedOffsets []int64 `json:"mismatched_offsets,omitempty"`
+	MissingOffsets    []int64 `json:"missing_offsets,omitempty"`
+	// DuplicateOffsets are target offsets.
+	DuplicateOffsets []int64 `json:"duplicate_offsets,omitempty"`
+	Skipped          string  `json:"skipped,omitempty"`
+	Error            string  `json:"error,omitempty"`
+}
+
 // MirrorGap represents detected gaps in replication
 type MirrorGap struct {
 	ID               int        `db:"id" json:"id"`
@@ -312,4 +479,151 @@ type MirrorStateData struct {
 	MirrorGaps     []MirrorGap           `json:"mirror_gaps"`
 	StateAnalysis  []MirrorStateAnalysis `json:"state_analysis"`
 	LastCheckpoint *MigrationCheckpoint  `json:"last_checkpoint,omitempty"`
+	// ContentVerification is the latest completed content verification.
+	ContentVerification *ContentVerification `json:"content_verification,omitempty"`
+}
+
+// AlertRule is a condition evaluated against a job by the alerting engine.
+type AlertRule struct {
+	ID          int       `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	RuleType    string    `db:"rule_type" json:"type"`
+	JobID       string    `db:"job_id" json:"job_id"`
+	Threshold   float64   `db:"threshold" json:"threshold"`
+	ForDuration string    `db:"for_duration" json:"for"`
+	Severity    string    `db:"severity" json:"severity"`
+	Channels    string    `db:"channels" json:"channels"`
+	Enabled     bool      `db:"enabled" json:"enabled"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// JobSLO is a service level objective declared on a replication job.
+type JobSLO struct {
+	ID          int       `db:"id" json:"id"`
+	JobID       string    `db:"job_id" json:"job_id"`
+	Name        string    `db:"name" json:"name"`
+	SLOType     string    `db:"slo_type" json:"type"`
+	Objective   float64   `db:"objective" json:"objective"`
+	Threshold   float64   `db:"threshold" json:"threshold"`
+	Description string    `db:"description" json:"description"`
+	Enabled     bool      `db:"enabled" json:"enabled"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// Alert is a fired instance of an alert rule for a single job.
+type Alert struct {
+	ID             int        `db:"id" json:"id"`
+	RuleID         int        `db:"rule_id" json:"rule_id"`
+	RuleName       string     `db:"rule_name" json:"rule_name"`
+	RuleType       string     `db:"rule_type" json:"rule_type"`
+	JobID          string     `db:"job_id" json:"job_id"`
+	Severity       string     `db:"severity" json:"severity"`
+	Status         string     `db:"status" json:"status"`
+	Value          float64    `db:"value" json:"value"`
+	Threshold      float64    `db:"threshold" json:"threshold"`
+	Summary        string     `db:"summary" json:"summary"`
+	Silenced       bool       `db:"silenced" json:"silenced"`
+	FiredAt        time.Time  `db:"fired_at" json:"fired_at"`
+	ResolvedAt     *time.Time `db:"resolved_at" json:"resolved_at,omitempty"`
+	AcknowledgedBy *string    `db:"acknowledged_by" json:"acknowledged_by,omitempty"`
+	AcknowledgedAt *time.Time `db:"acknowledged_at" json:"acknowledged_at,omitempty"`
+	LastNotifiedAt *time.Time `db:"last_notified_at" json:"last_notified_at,omitempty"`
+}
+
+// AlertSilence suppresses notifications for matching alerts between StartsAt and EndsAt.
+type AlertSilence struct {
+	ID        int       `db:"id" json:"id"`
+	JobID     string    `db:"job_id" json:"job_id"`
+	RuleID    *int      `db:"rule_id" json:"rule_id,omitempty"`
+	Reason    string    `db:"reason" json:"reason"`
+	CreatedBy string    `db:"created_by" json:"created_by"`
+	StartsAt  time.Time `db:"starts_at" json:"starts_at"`
+	EndsAt    time.Time `db:"ends_at" json:"ends_at"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+// Webhook is an external endpoint subscribed to job lifecycle and incident events.
+type Webhook struct {
+	ID         int       `db:"id" json:"id"`
+	Name       string    `db:"name" json:"name"`
+	URL        string    `db:"url" json:"url"`
+	Secret     string    `db:"secret" json:"-"`
+	EventTypes string    `db:"event_types" json:"event_types"`
+	JobID      string    `db:"job_id" json:"job_id"`
+	Enabled    bool      `db:"enabled" json:"enabled"`
+	CreatedBy  string    `db:"created_by" json:"created_by"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// WebhookDelivery is one event sent, or to be sent, to a webhook.
+type WebhookDelivery struct {
+	ID             int        `db:"id" json:"id"`
+	WebhookID      int        `db:"webhook_id" json:"webhook_id"`
+	EventID        string     `db:"event_id" json:"event_id"`
+	EventType      string     `db:"event_type" json:"event_type"`
+	JobID          string     `db:"job_id" json:"job_id"`
+	Payload        string     `db:"payload" json:"payload"`
+	Status         string     `db:"status" json:"status"`
+	Attempts       int        `db:"attempts" json:"attempts"`
+	ResponseStatus *int       `db:"response_status" json:"response_status,omitempty"`
+	LastError      *string    `db:"last_error" json:"last_error,omitempty"`
+	NextAttemptAt  *time.Time `db:"next_attempt_at" json:"next_attempt_at,omitempty"`
+	ReplayOf       *int       `db:"replay_of" json:"replay_of,omitempty"`
+	CreatedAt      time.Time  `db:"created_at" json:"created_at"`
+	DeliveredAt    *time.Time `db:"delivered_at" json:"delivered_at,omitempty"`
+}
+
+// TopicConfigOverride pins a target topic config of a job to a fixed value,
+// e.g. a longer retention.ms on a disaster recovery cluster.
+type TopicConfigOverride struct {
+	JobID     string    `db:"job_id" json:"job_id"`
+	Key       string    `db:"config_key" json:"key"`
+	Value     string    `db:"config_value" json:"value"`
+	UpdatedBy string    `db:"updated_by" json:"updated_by"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
+
+// FailoverOperation is a planned failover of a job, run as a sequence of steps
+// that can be resumed after a failure.
+type FailoverOperation struct {
+	ID           int             `db:"id" json:"id"`
+	JobID        string          `db:"job_id" json:"job_id"`
+	Status       string          `db:"status" json:"status"`
+	OptionsJSON  string          `db:"options" json:"-"`
+	StepsJSON    string          `db:"steps" json:"-"`
+	Options      FailoverOptions `db:"-" json:"options"`
+	Steps        []FailoverStep  `db:"-" json:"steps"`
+	DryRun       bool            `db:"-" json:"dry_run,omitempty"`
+	CheckpointID *int            `db:"checkpoint_id" json:"checkpoint_id,omitempty"`
+	ReverseJobID *string         `db:"reverse_job_id" json:"reverse_job_id,omitempty"`
+	LastError    *string         `db:"last_error" json:"last_error,omitempty"`
+	Initiator    string          `db:"initiator" json:"initiator"`
+	CreatedAt    time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time       `db:"updated_at" json:"updated_at"`
+	CompletedAt  *time.Time      `db:"completed_at" json:"completed_at,omitempty"`
+}
+
+// FailoverOptions control a failover.
+type FailoverOptions struct {
+	// DrainTimeout is how long to wait for zero lag, as a duration; default 5m.
+	DrainTimeout string `json:"drain_timeout,omitempty"`
+	// ConsumerGroups are glob patterns of the source groups whose offsets are
+	// synced; empty syncs every group with offsets on the job's topics.
+	ConsumerGroups []string `json:"consumer_groups,omitempty"`
+	// CreateReverseJob creates a paused target to source job for failback.
+	CreateReverseJob bool   `json:"create_reverse_job"`
+	Reason           string `json:"reason,omitempty"`
+}
+
+// FailoverStep is the state of one step of a failover.
+type FailoverStep struct {
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Details     string     `json:"details,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
 }
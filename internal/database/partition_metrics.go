@@ -0,0 +1,250 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"sort"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PartitionMetricsTier describes one downsampling step: points finer than
+// Resolution that are older than After are merged into Resolution-wide buckets.
+type PartitionMetricsTier struct {
+	After      time.Duration
+	Resolution time.Duration
+}
+
+// PartitionMetricsTiers is the default downsampling schedule: raw points are
+// kept for an hour, minute buckets for a day, hourly buckets until pruned.
+var PartitionMetricsTiers = []PartitionMetricsTier{
+	{After: time.Hour, Resolution: time.Minute},
+	{After: 24 * time.Hour, Resolution: time.Hour},
+}
+
+// InsertPartitionMetrics stores one collection interval of partition metrics.
+func InsertPartitionMetrics(db *sqlx.DB, metrics []PartitionMetric) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertPartitionMetrics(tx, metrics); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertPartitionMetrics(tx *sqlx.Tx, metrics []PartitionMetric) error {
+	stmt, err := tx.Preparex(`INSERT INTO partition_metrics (
+		job_id, topic, partition_id, timestamp, resolution,
+		messages_consumed, bytes_consumed, messages_replicated, bytes_transferred,
+		error_count, message_lag, lag_seconds
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, m := range metrics {
+		ts := m.Timestamp
+		if ts.IsZero() {
+			ts = time.Now()
+		}
+		resolution := m.Resolution
+		if resolution <= 0 {
+			resolution = 10
+		}
+		if _, err := stmt.Exec(m.JobID, m.Topic, m.Partition, ts.UTC(), resolution,
+			m.MessagesConsumed, m.BytesConsumed, m.MessagesReplicated, m.BytesTransferred,
+			m.ErrorCount, m.MessageLag, m.LagSeconds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetPartitionMetrics returns the partition time series of a job between start
+// and end, optionally limited to one source topic, ordered by time.
+func GetPartitionMetrics(db *sqlx.DB, jobID, topic string, start, end time.Time) ([]PartitionMetric, error) {
+	query := `SELECT * FROM partition_metrics WHERE job_id = ? AND timestamp BETWEEN ? AND ?`
+	args := []interface{}{jobID, start.UTC(), end.UTC()}
+	if topic != "" {
+		query += " AND topic = ?"
+		args = append(args, topic)
+	}
+	query += " ORDER BY timestamp, topic, partition_id"
+
+	metrics := []PartitionMetric{}
+	err := db.Select(&metrics, query, args...)
+	return metrics, err
+}
+
+// SummarizePartitionMetrics folds a time series into one summary per
+// partition, sorted by topic and partition.
+func SummarizePartitionMetrics(metrics []PartitionMetric) []PartitionSummary {
+	type key struct {
+		topic     string
+		partition int32
+	}
+	byPartition := make(map[key]*PartitionSummary)
+	for _, m := range metrics {
+		k := key{m.Topic, m.Partition}
+		s, ok := byPartition[k]
+		if !ok {
+			s = &PartitionSummary{Topic: m.Topic, Partition: m.Partition}
+			byPartition[k] = s
+		}
+		s.MessagesConsumed += m.MessagesConsumed
+		s.BytesConsumed += m.BytesConsumed
+		s.MessagesReplicated += m.MessagesReplicated
+		s.BytesTransferred += m.BytesTransferred
+		s.ErrorCount += m.ErrorCount
+		if m.LagSeconds > s.MaxLagSeconds {
+			s.MaxLagSeconds = m.LagSeconds
+		}
+		if !m.Timestamp.Before(s.LastSeen) {
+			s.LastSeen = m.Timestamp
+			s.MessageLag = m.MessageLag
+			s.LagSeconds = m.LagSeconds
+		}
+	}
+
+	summaries := make([]PartitionSummary, 0, len(byPartition))
+	for _, s := range byPartition {
+		summaries = append(summaries, *s)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Topic != summaries[j].Topic {
+			return summaries[i].Topic < summaries[j].Topic
+		}
+		return summaries[i].Partition < summaries[j].Partition
+	})
+	return summaries
+}
+
+// GetPartitionSummaries summarizes the partition metrics of a job since the
+// given time.
+func GetPartitionSummaries(db *sqlx.DB, jobID string, since time.Time) ([]PartitionSummary, error) {
+	metrics, err := GetPartitionMetrics(db, jobID, "", since, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return SummarizePartitionMetrics(metrics), nil
+}
+
+// DownsamplePartitionMetrics merges aged partition metrics into coarser
+// buckets according to tiers. Counters are summed and lag values keep the
+// bucket maximum. Cutoffs are aligned to bucket boundaries so a bucket is
+// always merged in a single pass. It returns the number of points removed.
+func DownsamplePartitionMetrics(db *sqlx.DB, tiers []PartitionMetricsTier, now time.Time) (int, error) {
+	removed := 0
+	for _, tier := range tiers {
+		n, err := downsamplePartitionMetricsTier(db, tier, now)
+		if err != nil {
+			return removed, err
+		}
+		removed += n
+	}
+	return removed, nil
+}
+
+func downsamplePartitionMetricsTier(db *sqlx.DB, tier PartitionMetricsTier, now time.Time) (int, error) {
+	resolution := int(tier.Resolution / time.Second)
+	cutoff := now.Add(-tier.After).UTC().Truncate(tier.Resolution)
+
+	tx, err := db.Beginx()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var points []PartitionMetric
+	if err := tx.Select(&points, `SELECT * FROM partition_metrics WHERE resolution < ? AND timestamp < ?`, resolution, cutoff); err != nil {
+		return 0, err
+	}
+	if len(points) == 0 {
+		return 0, nil
+	}
+
+	type key struct {
+		jobID     string
+		topic     string
+		partition int32
+		bucket    time.Time
+	}
+	buckets := make(map[key]*PartitionMetric)
+	var order []key
+	for _, p := range points {
+		k := key{p.JobID, p.Topic, p.Partition, p.Timestamp.UTC().Truncate(tier.Resolution)}
+		b, ok := buckets[k]
+		if !ok {
+			b = &PartitionMetric{JobID: k.jobID, Topic: k.topic, Partition: k.partition, Timestamp: k.bucket, Resolution: resolution}
+			buckets[k] = b
+			order = append(order, k)
+		}
+		b.MessagesConsumed += p.MessagesConsumed
+		b.BytesConsumed += p.BytesConsumed
+		b.MessagesReplicated += p.MessagesReplicated
+		b.BytesTransferred += p.BytesTransferred
+		b.ErrorCount += p.ErrorCount
+		if p.MessageLag > b.MessageLag {
+			b.MessageLag = p.MessageLag
+		}
+		if p.LagSeconds > b.LagSeconds {
+			b.LagSeconds = p.LagSeconds
+		}
+	}
+
+	if _, err := tx.Exec(`DELETE FROM partition_metrics WHERE resolution < ? AND timestamp < ?`, resolution, cutoff); err != nil {
+		return 0, err
+	}
+	merged := make([]PartitionMetric, 0, len(order))
+	for _, k := range order {
+		merged = append(merged, *buckets[k])
+	}
+	if err := insertPartitionMetrics(tx, merged); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(points) - len(merged), nil
+}
+
+// HottestPartitions returns up to limit summaries ordered by current time lag,
+// then message lag, errors and replicated messages, all descending.
+func HottestPartitions(summaries []PartitionSummary, limit int) []PartitionSummary {
+	ranked := append([]PartitionSummary(nil), summaries...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.LagSeconds != b.LagSeconds {
+			return a.LagSeconds > b.LagSeconds
+		}
+		if a.MessageLag != b.MessageLag {
+			return a.MessageLag > b.MessageLag
+		}
+		if a.ErrorCount != b.ErrorCount {
+			return a.ErrorCount > b.ErrorCount
+		}
+		return a.MessagesReplicated > b.MessagesReplicated
+	})
+	if limit > 0 && len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	return ranked
+}
@@ -29,7 +29,7 @@ func PruneOldData(db *sqlx.DB, retentionDays int) error {
 		return err
 	}
 
-	_, err = db.Exec(`DELETE FROM operational_events WHERE timestamp < ?`, cutoff)
+	_, err = db.Exec(`DELETE FROM partition_metrics WHERE timestamp < ?`, cutoff.UTC())
 	if err != nil {
 		return err
 	}
@@ -56,5 +56,10 @@ func PruneOldData(db *sqlx.DB, retentionDays int) error {
 		return err
 	}
 
+	_, err = db.Exec(`DELETE FROM webhook_deliveries WHERE created_at < ? AND status != 'pending'`, cutoff.UTC())
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -12,6 +12,7 @@
 package database
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/jmoiron/sqlx"
@@ -24,6 +25,28 @@ func AssignRoleToUser(db *sqlx.DB, userID, roleID int) error {
 	return err
 }
 
+// SetUserRole replaces all roles of a user with the named role.
+func SetUserRole(db *sqlx.DB, userID int, roleName string) error {
+	var roleID int
+	if err := db.Get(&roleID, "SELECT id FROM roles WHERE name = ?", roleName); err != nil {
+		return fmt.Errorf("unknown role %q: %v", roleName, err)
+	}
+
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM user_roles WHERE user_id = ?", userID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := tx.Exec("INSERT INTO user_roles (user_id, role_id) VALUES (?, ?)", userID, roleID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
 // GrantPermissionToRole grants a permission to a role.
 func GrantPermissionToRole(db *sqlx.DB, roleID, permissionID int) error {
 	query := `INSERT OR IGNORE INTO role_permissions (role_id, permission_id) VALUES (?, ?)`
@@ -51,6 +74,21 @@ func UserHasPermission(db *sqlx.DB, userID int, permissionName string) (bool, er
 	return hasPermission, nil
 }
 
+// GetUserPermissions retrieves the names of all permissions granted to a user through their roles.
+func GetUserPermissions(db *sqlx.DB, userID int) ([]string, error) {
+	query := `
+        SELECT DISTINCT p.name
+        FROM user_roles ur
+        JOIN role_permissions rp ON ur.role_id = rp.role_id
+        JOIN permissions p ON rp.permission_id = p.id
+        WHERE ur.user_id = ?
+        ORDER BY p.name`
+
+	var permissions []string
+	err := db.Select(&permissions, query, userID)
+	return permissions, err
+}
+
 // GetUserRole retrieves the role of a user.
 func GetUserRole(db *sqlx.DB, userID int) (string, error) {
 	var roleName string
@@ -76,6 +114,9 @@ func SeedDefaultRolesAndPermissions(db *sqlx.DB) error {
 		"roles:manage", "config:view", "config:edit",
 		"compliance:generate", "compliance:view",
 		"inventory:view", "inventory:create",
+		"alerts:view", "alerts:manage",
+		"webhooks:view", "webhooks:manage",
+		"jobs:failover",
 	}
 
 	rolePermissions := map[string][]string{
@@ -84,10 +125,11 @@ func SeedDefaultRolesAndPermissions(db *sqlx.DB) error {
 			"jobs:view", "jobs:start", "jobs:stop", "jobs:pause", "jobs:edit",
 			"clusters:view", "clusters:edit",
 			"metrics:view", "ai:insights:view", "ai:analysis:trigger", "events:view",
-			"inventory:view", "inventory:create",
+			"inventory:view", "inventory:create", "alerts:view", "alerts:manage",
+			"webhooks:view", "webhooks:manage", "jobs:failover",
 		},
-		"monitoring": {"jobs:view", "clusters:view", "metrics:view", "ai:insights:view", "inventory:view", "events:view"},
-		"compliance": {"jobs:view", "clusters:view", "metrics:view", "compliance:generate", "compliance:view", "inventory:view", "events:view"},
+		"monitoring": {"jobs:view", "clusters:view", "metrics:view", "ai:insights:view", "inventory:view", "events:view", "alerts:view", "webhooks:view"},
+		"compliance": {"jobs:view", "clusters:view", "metrics:view", "compliance:generate", "compliance:view", "inventory:view", "events:view", "alerts:view"},
 	}
 
 	tx, err := db.Beginx()
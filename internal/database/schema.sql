@@ -22,6 +22,9 @@ CREATE TABLE IF NOT EXISTS kafka_clusters (
     api_key TEXT,
     api_secret TEXT,
     connection_string TEXT,
+    schema_registry_url TEXT NOT NULL DEFAULT '',
+    schema_registry_username TEXT NOT NULL DEFAULT '',
+    schema_registry_password TEXT NOT NULL DEFAULT '',
     status TEXT DEFAULT 'unknown',
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
@@ -37,6 +40,9 @@ CREATE TABLE IF NOT EXISTS replication_jobs (
     parallelism INTEGER NOT NULL DEFAULT 4,
     compression TEXT NOT NULL DEFAULT 'none',
     preserve_partitions BOOLEAN NOT NULL DEFAULT TRUE,
+    start_position TEXT,
+    error_policy TEXT,
+    job_type TEXT NOT NULL DEFAULT 'continuous' CHECK(job_type IN ('continuous', 'backfill')),
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (source_cluster_name) REFERENCES kafka_clusters(name),
@@ -50,6 +56,7 @@ CREATE TABLE IF NOT EXISTS topic_mappings (
     source_topic_pattern TEXT NOT NULL,
     target_topic_pattern TEXT NOT NULL,
     enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    start_position TEXT,
     FOREIGN KEY (job_id) REFERENCES replication_jobs(id) ON DELETE CASCADE
 );
 
@@ -89,7 +96,9 @@ CREATE TABLE IF NOT EXISTS operational_events (
     event_type TEXT NOT NULL,
     initiator TEXT NOT NULL,
     details TEXT, -- JSON blob
-    timestamp DATETIME DEFAULT CURRENT_TIMESTAMP
+    timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+    prev_hash TEXT NOT NULL DEFAULT '', -- hash of the preceding event; empty for the first chained event
+    hash TEXT NOT NULL DEFAULT ''       -- "sha256:<hex>" or "hmac-sha256:<hex>" over this event and prev_hash
 );
 
 -- Configuration: Stores runtime configuration overrides
@@ -105,6 +114,12 @@ CREATE TABLE IF NOT EXISTS users (
     username TEXT NOT NULL UNIQUE,
     password_hash TEXT NOT NULL,
     is_initial BOOLEAN NOT NULL DEFAULT FALSE,
+    auth_source TEXT NOT NULL DEFAULT 'local' CHECK(auth_source IN ('local', 'ldap')),
+    disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    disabled_reason TEXT NOT NULL DEFAULT '',
+    failed_login_count INTEGER NOT NULL DEFAULT 0,
+    last_failed_login_at DATETIME,
+    locked_until DATETIME,
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 );
 
@@ -112,9 +127,15 @@ CREATE TABLE IF NOT EXISTS users (
 CREATE TABLE IF NOT EXISTS api_tokens (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
     user_id INTEGER NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
     token_hash TEXT NOT NULL UNIQUE,
     description TEXT,
+    permissions TEXT NOT NULL DEFAULT '', -- comma-separated subset of the user's permissions; empty inherits the role
+    job_ids TEXT NOT NULL DEFAULT '',     -- comma-separated job IDs; empty allows all jobs
+    allowed_ips TEXT NOT NULL DEFAULT '', -- comma-separated IPs or CIDRs; empty allows any address
     expires_at DATETIME,
+    last_used_at DATETIME,
+    last_used_ip TEXT NOT NULL DEFAULT '',
     created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 );
@@ -250,6 +271,21 @@ CREATE TABLE IF NOT EXISTS connection_inventory (
     FOREIGN KEY (snapshot_id) REFERENCES job_inventory_snapshots(id) ON DELETE CASCADE
 );
 
+-- ACL Inventory: ACLs of each cluster per snapshot
+CREATE TABLE IF NOT EXISTS acl_inventory (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    snapshot_id INTEGER NOT NULL,
+    cluster_type TEXT NOT NULL CHECK(cluster_type IN ('source', 'target')),
+    principal TEXT NOT NULL,
+    host TEXT NOT NULL,
+    resource_type TEXT NOT NULL,
+    resource_name TEXT NOT NULL,
+    pattern_type TEXT NOT NULL,
+    operation TEXT NOT NULL,
+    permission TEXT NOT NULL,
+    FOREIGN KEY (snapshot_id) REFERENCES job_inventory_snapshots(id) ON DELETE CASCADE
+);
+
 -- Mirror Progress: Tracks replication progress per job and topic partition (current state only)
 CREATE TABLE IF NOT EXISTS mirror_progress (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -336,6 +372,27 @@ CREATE TABLE IF NOT EXISTS mirror_state_analysis (
     FOREIGN KEY (job_id) REFERENCES replication_jobs(id) ON DELETE CASCADE
 );
 
+-- Content Verifications: Record-level comparisons of source and target topics
+CREATE TABLE IF NOT EXISTS content_verifications (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'running' CHECK(status IN ('running', 'failed', 'completed')),
+    sample_rate REAL NOT NULL DEFAULT 1,
+    records_compared INTEGER NOT NULL DEFAULT 0,
+    mismatched_records INTEGER NOT NULL DEFAULT 0,
+    missing_records INTEGER NOT NULL DEFAULT 0,
+    duplicate_records INTEGER NOT NULL DEFAULT 0,
+    consistent BOOLEAN NOT NULL DEFAULT 0,
+    partitions TEXT NOT NULL DEFAULT '[]', -- JSON list of PartitionVerification
+    last_error TEXT,
+    initiator TEXT NOT NULL DEFAULT '',
+    started_at DATETIME NOT NULL,
+    completed_at DATETIME,
+    FOREIGN KEY (job_id) REFERENCES replication_jobs(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_content_verifications_job ON content_verifications(job_id, started_at);
+
 -- Mirror Gaps: Detected gaps in replication
 CREATE TABLE IF NOT EXISTS mirror_gaps (
     id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -353,3 +410,173 @@ CREATE TABLE IF NOT EXISTS mirror_gaps (
     resolved_at DATETIME,
     FOREIGN KEY (job_id) REFERENCES replication_jobs(id) ON DELETE CASCADE
 );
+
+-- Alert Rules: Per-job alerting conditions evaluated by the job manager
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    rule_type TEXT NOT NULL CHECK(rule_type IN ('lag', 'lag_seconds', 'error_rate', 'stalled', 'job_failed', 'gap_detected', 'slo_burn_rate')),
+    job_id TEXT NOT NULL DEFAULT '', -- empty applies the rule to every job
+    threshold REAL NOT NULL DEFAULT 0,
+    for_duration TEXT NOT NULL DEFAULT '0s',
+    severity TEXT NOT NULL DEFAULT 'warning' CHECK(severity IN ('info', 'warning', 'critical')),
+    channels TEXT NOT NULL DEFAULT '', -- comma-separated channel names; empty notifies every channel
+    enabled BOOLEAN NOT NULL DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Alerts: Fired alert instances and their lifecycle
+CREATE TABLE IF NOT EXISTS alerts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    rule_id INTEGER NOT NULL,
+    rule_name TEXT NOT NULL,
+    rule_type TEXT NOT NULL,
+    job_id TEXT NOT NULL,
+    severity TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'firing' CHECK(status IN ('firing', 'resolved')),
+    value REAL NOT NULL DEFAULT 0,
+    threshold REAL NOT NULL DEFAULT 0,
+    summary TEXT NOT NULL,
+    silenced BOOLEAN NOT NULL DEFAULT 0,
+    fired_at DATETIME NOT NULL,
+    resolved_at DATETIME,
+    acknowledged_by TEXT,
+    acknowledged_at DATETIME,
+    last_notified_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_alerts_status ON alerts(status, fired_at);
+
+-- Alert Silences: Time-boxed notification suppression by job and/or rule
+CREATE TABLE IF NOT EXISTS alert_silences (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id TEXT NOT NULL DEFAULT '', -- empty matches every job
+    rule_id INTEGER, -- NULL matches every rule
+    reason TEXT NOT NULL DEFAULT '',
+    created_by TEXT NOT NULL,
+    starts_at DATETIME NOT NULL,
+    ends_at DATETIME NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Partition Metrics: Per (topic, partition) time series, downsampled as it ages
+CREATE TABLE IF NOT EXISTS partition_metrics (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id TEXT NOT NULL,
+    topic TEXT NOT NULL,
+    partition_id INTEGER NOT NULL,
+    timestamp DATETIME NOT NULL,
+    resolution INTEGER NOT NULL DEFAULT 10, -- bucket width in seconds
+    messages_consumed INTEGER NOT NULL DEFAULT 0,
+    bytes_consumed INTEGER NOT NULL DEFAULT 0,
+    messages_replicated INTEGER NOT NULL DEFAULT 0,
+    bytes_transferred INTEGER NOT NULL DEFAULT 0,
+    error_count INTEGER NOT NULL DEFAULT 0,
+    message_lag INTEGER NOT NULL DEFAULT 0,
+    lag_seconds REAL NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_partition_metrics_job_time ON partition_metrics(job_id, timestamp);
+CREATE INDEX IF NOT EXISTS idx_partition_metrics_resolution ON partition_metrics(resolution, timestamp);
+
+-- Job SLOs: Service level objectives evaluated over rolling windows
+CREATE TABLE IF NOT EXISTS job_slos (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    slo_type TEXT NOT NULL CHECK(slo_type IN ('lag_seconds', 'message_lag', 'error_rate', 'gaps')),
+    objective REAL NOT NULL DEFAULT 99.9, -- percent of good 1-minute windows
+    threshold REAL NOT NULL DEFAULT 0,
+    description TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT 1,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    UNIQUE(job_id, name),
+    FOREIGN KEY (job_id) REFERENCES replication_jobs(id) ON DELETE CASCADE
+);
+
+-- Webhooks: Subscriptions of external systems to job lifecycle and incident events
+CREATE TABLE IF NOT EXISTS webhooks (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL, -- HMAC-SHA256 signing key
+    event_types TEXT NOT NULL DEFAULT '', -- comma-separated event types; empty subscribes to every event
+    job_id TEXT NOT NULL DEFAULT '', -- empty matches every job
+    enabled BOOLEAN NOT NULL DEFAULT 1,
+    created_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL
+);
+
+-- Webhook Deliveries: One row per event sent to a webhook, with its retry state
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    webhook_id INTEGER NOT NULL,
+    event_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    job_id TEXT NOT NULL DEFAULT '',
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'delivered', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER,
+    last_error TEXT,
+    next_attempt_at DATETIME,
+    replay_of INTEGER,
+    created_at DATETIME NOT NULL,
+    delivered_at DATETIME,
+    FOREIGN KEY (webhook_id) REFERENCES webhooks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, created_at);
+
+-- Topic Config Overrides: Per-job target topic configs that win over the values copied from the source
+CREATE TABLE IF NOT EXISTS topic_config_overrides (
+    job_id TEXT NOT NULL,
+    config_key TEXT NOT NULL,
+    config_value TEXT NOT NULL,
+    updated_by TEXT NOT NULL DEFAULT '',
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (job_id, config_key),
+    FOREIGN KEY (job_id) REFERENCES replication_jobs(id) ON DELETE CASCADE
+);
+
+-- Failover Operations: Planned failovers of a job, run as resumable steps
+CREATE TABLE IF NOT EXISTS failover_operations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'running' CHECK(status IN ('running', 'failed', 'completed')),
+    options TEXT NOT NULL DEFAULT '{}', -- JSON FailoverOptions
+    steps TEXT NOT NULL DEFAULT '[]', -- JSON list of FailoverStep
+    checkpoint_id INTEGER,
+    reverse_job_id TEXT,
+    last_error TEXT,
+    initiator TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    completed_at DATETIME,
+    FOREIGN KEY (job_id) REFERENCES replication_jobs(id) ON DELETE CASCADE,
+    FOREIGN KEY (checkpoint_id) REFERENCES migration_checkpoints(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_failover_operations_job ON failover_operations(job_id, created_at);
+
+-- Backfill Ranges: Bounded source partition ranges copied by backfill jobs
+CREATE TABLE IF NOT EXISTS backfill_ranges (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    job_id TEXT NOT NULL,
+    source_topic TEXT NOT NULL,
+    partition_id INTEGER NOT NULL,
+    start_offset INTEGER NOT NULL,
+    end_offset INTEGER NOT NULL, -- exclusive
+    next_offset INTEGER NOT NULL,
+    gap_ids TEXT NOT NULL DEFAULT '[]', -- JSON list of mirror_gaps ids resolved on completion
+    status TEXT NOT NULL DEFAULT 'pending' CHECK(status IN ('pending', 'completed', 'failed')),
+    last_error TEXT,
+    updated_at DATETIME NOT NULL,
+    completed_at DATETIME,
+    UNIQUE (job_id, source_topic, partition_id),
+    FOREIGN KEY (job_id) REFERENCES replication_jobs(id) ON DELETE CASCADE
+);
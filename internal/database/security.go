@@ -0,0 +1,58 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"encoding/json"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Security event types recorded in operational_events.
+const (
+	EventLoginSucceeded   = "login_succeeded"
+	EventLoginFailed      = "login_failed"
+	EventAccountLocked    = "account_locked"
+	EventAuthFailed       = "auth_failed"
+	EventPermissionDenied = "permission_denied"
+	EventTokenCreated     = "token_created"
+	EventTokenRevoked     = "token_revoked"
+	EventSensitiveRead    = "sensitive_read"
+)
+
+// SecurityEventTypes lists the event types summarized in the security section of compliance reports.
+var SecurityEventTypes = []string{
+	EventLoginSucceeded,
+	EventLoginFailed,
+	EventAccountLocked,
+	EventAuthFailed,
+	EventPermissionDenied,
+	EventTokenCreated,
+	EventTokenRevoked,
+	EventSensitiveRead,
+}
+
+// RecordSecurityEvent appends a security event with JSON details to the audit trail.
+func RecordSecurityEvent(db *sqlx.DB, eventType, initiator string, details map[string]interface{}) error {
+	if initiator == "" {
+		initiator = "Anonymous"
+	}
+	payload, err := json.Marshal(details)
+	if err != nil {
+		return err
+	}
+	return CreateOperationalEvent(db, &OperationalEvent{
+		EventType: eventType,
+		Initiator: initiator,
+		Details:   string(payload),
+	})
+}
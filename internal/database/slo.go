@@ -0,0 +1,371 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// SLO types. Window-based SLOs count the share of good 1-minute windows;
+// the gaps SLO bounds the number of unresolved replication gaps.
+const (
+	SLOTypeLagSeconds = "lag_seconds"
+	SLOTypeMessageLag = "message_lag"
+	SLOTypeErrorRate  = "error_rate"
+	SLOTypeGaps       = "gaps"
+)
+
+// SLOTypes lists every supported SLO type.
+var SLOTypes = []string{SLOTypeLagSeconds, SLOTypeMessageLag, SLOTypeErrorRate, SLOTypeGaps}
+
+// SLOWindows are the rolling windows SLO attainment is reported over.
+var SLOWindows = []struct {
+	Name     string
+	Duration time.Duration
+}{
+	{"7d", 7 * 24 * time.Hour},
+	{"30d", 30 * 24 * time.Hour},
+}
+
+// Burn-rate windows used for fast-burn alerting.
+const (
+	SLOBurnLongWindow  = time.Hour
+	SLOBurnShortWindow = 5 * time.Minute
+)
+
+// SLOWindowStatus is the attainment of an SLO over one time window.
+type SLOWindowStatus struct {
+	Window string    `json:"window"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	// TotalWindows and GoodWindows count 1-minute windows with metrics.
+	TotalWindows int `json:"total_windows"`
+	GoodWindows  int `json:"good_windows"`
+	// Bad counts bad windows, or unresolved gaps for the gaps SLO.
+	Bad                         int     `json:"bad"`
+	AttainmentPercent           float64 `json:"attainment_percent"`
+	ErrorBudgetRemainingPercent float64 `json:"error_budget_remaining_percent"`
+	Met                         bool    `json:"met"`
+	NoData                      bool    `json:"no_data"`
+}
+
+// SLOStatus is the current state of an SLO over its rolling windows.
+type SLOStatus struct {
+	SLO     JobSLO            `json:"slo"`
+	Windows []SLOWindowStatus `json:"windows"`
+	// Burn rates relate the bad-window ratio of the last hour and the last
+	// five minutes to the error budget; 1 spends the budget exactly on time.
+	BurnRate1h float64 `json:"burn_rate_1h"`
+	BurnRate5m float64 `json:"burn_rate_5m"`
+}
+
+// Validate checks the SLO type, objective and threshold.
+func (s *JobSLO) Validate() error {
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("SLO name is required")
+	}
+	valid := false
+	for _, t := range SLOTypes {
+		if s.SLOType == t {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("SLO type must be one of %s", strings.Join(SLOTypes, ", "))
+	}
+	if s.SLOType != SLOTypeGaps && (s.Objective <= 0 || s.Objective >= 100) {
+		return fmt.Errorf("objective must be a percentage between 0 and 100 (exclusive)")
+	}
+	if s.Threshold < 0 {
+		return fmt.Errorf("threshold must not be negative")
+	}
+	if s.SLOType != SLOTypeGaps && s.Threshold == 0 {
+		return fmt.Errorf("threshold is required for %s SLOs", s.SLOType)
+	}
+	return nil
+}
+
+// errorBudget is the allowed share of bad windows.
+func (s *JobSLO) errorBudget() float64 {
+	return 1 - s.Objective/100
+}
+
+// CreateJobSLO stores a new SLO for a job.
+func CreateJobSLO(db *sqlx.DB, slo *JobSLO) error {
+	if slo.SLOType == SLOTypeGaps {
+		slo.Objective = 100
+	}
+	now := time.Now().UTC()
+	query := `INSERT INTO job_slos (job_id, name, slo_type, objective, threshold, description, enabled, created_at, updated_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := db.Exec(query, slo.JobID, slo.Name, slo.SLOType, slo.Objective, slo.Threshold,
+		slo.Description, slo.Enabled, now, now)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	slo.ID = int(id)
+	slo.CreatedAt = now
+	slo.UpdatedAt = now
+	return nil
+}
+
+// UpdateJobSLO overwrites an existing SLO of a job.
+func UpdateJobSLO(db *sqlx.DB, slo *JobSLO) error {
+	if slo.SLOType == SLOTypeGaps {
+		slo.Objective = 100
+	}
+	slo.UpdatedAt = time.Now().UTC()
+	query := `UPDATE job_slos SET name = ?, slo_type = ?, objective = ?, threshold = ?, description = ?,
+              enabled = ?, updated_at = ? WHERE id = ? AND job_id = ?`
+	result, err := db.Exec(query, slo.Name, slo.SLOType, slo.Objective, slo.Threshold, slo.Description,
+		slo.Enabled, slo.UpdatedAt, slo.ID, slo.JobID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetJobSLO retrieves an SLO of a job by ID.
+func GetJobSLO(db *sqlx.DB, jobID string, id int) (*JobSLO, error) {
+	var slo JobSLO
+	if err := db.Get(&slo, "SELECT * FROM job_slos WHERE id = ? AND job_id = ?", id, jobID); err != nil {
+		return nil, err
+	}
+	return &slo, nil
+}
+
+// ListJobSLOs returns the SLOs of a job, or of every job when jobID is empty.
+func ListJobSLOs(db *sqlx.DB, jobID string) ([]JobSLO, error) {
+	slos := []JobSLO{}
+	if jobID == "" {
+		err := db.Select(&slos, "SELECT * FROM job_slos ORDER BY job_id, name")
+		return slos, err
+	}
+	err := db.Select(&slos, "SELECT * FROM job_slos WHERE job_id = ? ORDER BY name", jobID)
+	return slos, err
+}
+
+// DeleteJobSLO removes an SLO of a job.
+func DeleteJobSLO(db *sqlx.DB, jobID string, id int) error {
+	result, err := db.Exec("DELETE FROM job_slos WHERE id = ? AND job_id = ?", id, jobID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// sloMinute is one 1-minute window of a job's aggregated metrics.
+type sloMinute struct {
+	Minute     string  `db:"minute"`
+	LagSeconds float64 `db:"lag_seconds"`
+	MessageLag float64 `db:"message_lag"`
+	Replicated int64   `db:"replicated"`
+	Errors     int64   `db:"errors"`
+	start      time.Time
+}
+
+// loadSLOMinutes groups a job's aggregated metrics since start into 1-minute
+// windows, keeping the worst lag and the summed counters of each window.
+func loadSLOMinutes(db *sqlx.DB, jobID string, start, end time.Time) ([]sloMinute, error) {
+	var minutes []sloMinute
+	query := `SELECT strftime('%Y-%m-%d %H:%M', timestamp) AS minute,
+                     COALESCE(MAX(lag_seconds_max), 0) AS lag_seconds,
+                     COALESCE(MAX(avg_lag), 0) AS message_lag,
+                     COALESCE(SUM(messages_replicated_delta), 0) AS replicated,
+                     COALESCE(SUM(error_count_delta), 0) AS errors
+              FROM aggregated_metrics
+              WHERE job_id = ? AND timestamp >= ? AND timestamp <= ?
+              GROUP BY minute ORDER BY minute`
+	if err := db.Select(&minutes, query, jobID, start, end); err != nil {
+		return nil, err
+	}
+	for i := range minutes {
+		t, err := time.ParseInLocation("2006-01-02 15:04", minutes[i].Minute, time.UTC)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected metrics timestamp %q: %v", minutes[i].Minute, err)
+		}
+		minutes[i].start = t
+	}
+	return minutes, nil
+}
+
+// good reports whether a 1-minute window meets the SLO threshold.
+func (s *JobSLO) good(m sloMinute) bool {
+	switch s.SLOType {
+	case SLOTypeLagSeconds:
+		return m.LagSeconds < s.Threshold
+	case SLOTypeMessageLag:
+		return m.MessageLag < s.Threshold
+	case SLOTypeErrorRate:
+		total := m.Replicated + m.Errors
+		if total == 0 {
+			return true
+		}
+		return float64(m.Errors)/float64(total)*100 <= s.Threshold
+	}
+	return true
+}
+
+// windowStatus evaluates a window-based SLO over the minutes in [start, end].
+func (s *JobSLO) windowStatus(name string, minutes []sloMinute, start, end time.Time) SLOWindowStatus {
+	status := SLOWindowStatus{Window: name, Start: start, End: end}
+	from := start.UTC().Truncate(time.Minute)
+	for _, m := range minutes {
+		if m.start.Before(from) || m.start.After(end) {
+			continue
+		}
+		status.TotalWindows++
+		if s.good(m) {
+			status.GoodWindows++
+		}
+	}
+	status.Bad = status.TotalWindows - status.GoodWindows
+
+	if status.TotalWindows == 0 {
+		status.NoData = true
+		status.AttainmentPercent = 100
+		status.ErrorBudgetRemainingPercent = 100
+		status.Met = true
+		return status
+	}
+	status.AttainmentPercent = float64(status.GoodWindows) / float64(status.TotalWindows) * 100
+	status.Met = status.AttainmentPercent >= s.Objective
+
+	allowed := s.errorBudget() * float64(status.TotalWindows)
+	remaining := 100.0
+	if allowed > 0 {
+		remaining = (allowed - float64(status.Bad)) / allowed * 100
+	} else if status.Bad > 0 {
+		remaining = 0
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	status.ErrorBudgetRemainingPercent = remaining
+	return status
+}
+
+// burnRate is the bad-window ratio since start divided by the error budget.
+func (s *JobSLO) burnRate(minutes []sloMinute, start time.Time) float64 {
+	from := start.UTC().Truncate(time.Minute)
+	total, bad := 0, 0
+	for _, m := range minutes {
+		if m.start.Before(from) {
+			continue
+		}
+		total++
+		if !s.good(m) {
+			bad++
+		}
+	}
+	if total == 0 || s.errorBudget() <= 0 {
+		return 0
+	}
+	return float64(bad) / float64(total) / s.errorBudget()
+}
+
+// countSLOGaps counts the gaps detected in [start, end] that are not resolved.
+func countSLOGaps(db *sqlx.DB, jobID string, start, end time.Time) (int, error) {
+	var count int
+	err := db.Get(&count, `SELECT COUNT(*) FROM mirror_gaps
+		WHERE job_id = ? AND resolution_status IN ('unresolved', 'in_progress') AND detected_at BETWEEN ? AND ?`,
+		jobID, start.UTC(), end.UTC())
+	return count, err
+}
+
+// gapStatus evaluates a gaps SLO over [start, end].
+func (s *JobSLO) gapStatus(db *sqlx.DB, name string, start, end time.Time) (SLOWindowStatus, error) {
+	status := SLOWindowStatus{Window: name, Start: start, End: end}
+	count, err := countSLOGaps(db, s.JobID, start, end)
+	if err != nil {
+		return status, err
+	}
+	status.Bad = count
+	status.Met = float64(count) <= s.Threshold
+	status.AttainmentPercent = 100
+	status.ErrorBudgetRemainingPercent = 100
+	if !status.Met {
+		status.AttainmentPercent = 0
+		status.ErrorBudgetRemainingPercent = 0
+	} else if s.Threshold > 0 {
+		status.ErrorBudgetRemainingPercent = (s.Threshold - float64(count)) / s.Threshold * 100
+	}
+	return status, nil
+}
+
+// EvaluateSLOWindow computes the attainment of an SLO over [start, end].
+func EvaluateSLOWindow(db *sqlx.DB, slo *JobSLO, name string, start, end time.Time) (SLOWindowStatus, error) {
+	if slo.SLOType == SLOTypeGaps {
+		return slo.gapStatus(db, name, start, end)
+	}
+	minutes, err := loadSLOMinutes(db, slo.JobID, start, end)
+	if err != nil {
+		return SLOWindowStatus{}, err
+	}
+	return slo.windowStatus(name, minutes, start, end), nil
+}
+
+// EvaluateSLOs computes the rolling-window attainment and burn rates of the
+// given SLOs at now. Metrics are loaded once per job.
+func EvaluateSLOs(db *sqlx.DB, slos []JobSLO, now time.Time) ([]SLOStatus, error) {
+	longest := SLOWindows[len(SLOWindows)-1].Duration
+	minutesByJob := make(map[string][]sloMinute)
+
+	statuses := make([]SLOStatus, 0, len(slos))
+	for _, slo := range slos {
+		status := SLOStatus{SLO: slo}
+		if slo.SLOType == SLOTypeGaps {
+			for _, w := range SLOWindows {
+				ws, err := slo.gapStatus(db, w.Name, now.Add(-w.Duration), now)
+				if err != nil {
+					return nil, err
+				}
+				status.Windows = append(status.Windows, ws)
+			}
+			statuses = append(statuses, status)
+			continue
+		}
+
+		minutes, ok := minutesByJob[slo.JobID]
+		if !ok {
+			var err error
+			minutes, err = loadSLOMinutes(db, slo.JobID, now.Add(-longest), now)
+			if err != nil {
+				return nil, err
+			}
+			minutesByJob[slo.JobID] = minutes
+		}
+		for _, w := range SLOWindows {
+			status.Windows = append(status.Windows, slo.windowStatus(w.Name, minutes, now.Add(-w.Duration), now))
+		}
+		status.BurnRate1h = slo.burnRate(minutes, now.Add(-SLOBurnLongWindow))
+		status.BurnRate5m = slo.burnRate(minutes, now.Add(-SLOBurnShortWindow))
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
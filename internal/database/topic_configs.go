@@ -0,0 +1,67 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ListTopicConfigOverrides returns the topic config overrides of a job ordered by key.
+func ListTopicConfigOverrides(db *sqlx.DB, jobID string) ([]TopicConfigOverride, error) {
+	overrides := []TopicConfigOverride{}
+	err := db.Select(&overrides, "SELECT * FROM topic_config_overrides WHERE job_id = ? ORDER BY config_key", jobID)
+	return overrides, err
+}
+
+// TopicConfigOverrideMap returns the topic config overrides of a job keyed by config name.
+func TopicConfigOverrideMap(db *sqlx.DB, jobID string) (map[string]string, error) {
+	overrides, err := ListTopicConfigOverrides(db, jobID)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(overrides))
+	for _, o := range overrides {
+		result[o.Key] = o.Value
+	}
+	return result, nil
+}
+
+// ReplaceTopicConfigOverrides replaces every topic config override of a job.
+func ReplaceTopicConfigOverrides(db *sqlx.DB, jobID string, overrides map[string]string, updatedBy string) error {
+	for key := range overrides {
+		if strings.TrimSpace(key) == "" {
+			return fmt.Errorf("topic config override keys must not be empty")
+		}
+	}
+
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM topic_config_overrides WHERE job_id = ?", jobID); err != nil {
+		return err
+	}
+	now := time.Now()
+	for key, value := range overrides {
+		if _, err := tx.Exec(`INSERT INTO topic_config_overrides (job_id, config_key, config_value, updated_by, updated_at)
+			VALUES (?, ?, ?, ?, ?)`, jobID, strings.TrimSpace(key), value, updatedBy, now); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -12,10 +12,29 @@
 package database
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
 	"github.com/jmoiron/sqlx"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	// AuthSourceLocal marks users authenticated against the local password hash.
+	AuthSourceLocal = "local"
+	// AuthSourceLDAP marks users authenticated against an LDAP / Active Directory server.
+	AuthSourceLDAP = "ldap"
+)
+
+const (
+	// DisabledByAdmin marks accounts disabled by an administrator.
+	DisabledByAdmin = "admin"
+	// DisabledByDirectory marks accounts disabled by directory sync; only these
+	// are enabled again when the user shows up in the directory.
+	DisabledByDirectory = "directory"
+)
+
 // CreateUser creates a new user with a hashed password.
 func CreateUser(db *sqlx.DB, username, password string, isInitial bool) (*User, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -61,10 +80,81 @@ func GetInitialUserID(db *sqlx.DB) (int, error) {
 // ListUsers retrieves all users from the database.
 func ListUsers(db *sqlx.DB) ([]User, error) {
 	var users []User
-	err := db.Select(&users, "SELECT id, username, is_initial, created_at FROM users ORDER BY username")
+	err := db.Select(&users, "SELECT id, username, is_initial, auth_source, disabled, disabled_reason, locked_until, created_at FROM users ORDER BY username")
 	return users, err
 }
 
+// ListUsersByAuthSource retrieves all users provisioned by the given authentication source.
+func ListUsersByAuthSource(db *sqlx.DB, authSource string) ([]User, error) {
+	var users []User
+	err := db.Select(&users, "SELECT id, username, is_initial, auth_source, disabled, disabled_reason, locked_until, created_at FROM users WHERE auth_source = ? ORDER BY username", authSource)
+	return users, err
+}
+
+// CreateExternalUser creates a user whose credentials are owned by an external directory.
+// The stored password hash is random so the account can never log in with a local password.
+func CreateExternalUser(db *sqlx.DB, username, authSource string) (*User, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(hex.EncodeToString(secret)), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO users (username, password_hash, is_initial, auth_source) VALUES (?, ?, FALSE, ?)`
+	result, err := db.Exec(query, username, hashedPassword, authSource)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return GetUser(db, int(id))
+}
+
+// SetUserDisabled disables a user account for reason, or enables it when disabled is false.
+func SetUserDisabled(db *sqlx.DB, userID int, disabled bool, reason string) error {
+	if !disabled {
+		reason = ""
+	}
+	query := `UPDATE users SET disabled = ?, disabled_reason = ? WHERE id = ?`
+	_, err := db.Exec(query, disabled, reason, userID)
+	return err
+}
+
+// RecordFailedLogin increments the user's failed login counter and returns the new count.
+// Failures older than window are forgotten before counting the new one.
+func RecordFailedLogin(db *sqlx.DB, userID int, window time.Duration) (int, error) {
+	now := time.Now()
+	query := `UPDATE users SET
+                failed_login_count = CASE WHEN last_failed_login_at IS NULL OR last_failed_login_at < ? THEN 1 ELSE failed_login_count + 1 END,
+                last_failed_login_at = ?
+              WHERE id = ?`
+	if _, err := db.Exec(query, now.Add(-window), now, userID); err != nil {
+		return 0, err
+	}
+	var count int
+	err := db.Get(&count, "SELECT failed_login_count FROM users WHERE id = ?", userID)
+	return count, err
+}
+
+// LockUser locks the user's account until the given time.
+func LockUser(db *sqlx.DB, userID int, until time.Time) error {
+	_, err := db.Exec("UPDATE users SET locked_until = ? WHERE id = ?", until, userID)
+	return err
+}
+
+// ClearFailedLogins resets the failed login counter and removes any lock.
+func ClearFailedLogins(db *sqlx.DB, userID int) error {
+	_, err := db.Exec("UPDATE users SET failed_login_count = 0, last_failed_login_at = NULL, locked_until = NULL WHERE id = ?", userID)
+	return err
+}
+
 // VerifyPassword checks if the provided password is correct for the user.
 func (u *User) VerifyPassword(password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password))
@@ -0,0 +1,148 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Content verification statuses.
+const (
+	VerificationRunning   = "running"
+	VerificationFailed    = "failed"
+	VerificationCompleted = "completed"
+)
+
+// CreateContentVerification stores a new content verification.
+func CreateContentVerification(db *sqlx.DB, v *ContentVerification) error {
+	if err := v.encode(); err != nil {
+		return err
+	}
+	v.StartedAt = time.Now().UTC()
+	query := `INSERT INTO content_verifications (job_id, status, sample_rate, partitions, initiator, started_at)
+              VALUES (?, ?, ?, ?, ?, ?)`
+	result, err := db.Exec(query, v.JobID, v.Status, v.SampleRate, v.PartitionsJSON, v.Initiator, v.StartedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create content verification: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	v.ID = int(id)
+	return nil
+}
+
+// UpdateContentVerification saves the status and results of a content verification.
+func UpdateContentVerification(db *sqlx.DB, v *ContentVerification) error {
+	if err := v.encode(); err != nil {
+		return err
+	}
+	query := `UPDATE content_verifications SET status = ?, records_compared = ?, mismatched_records = ?, missing_records = ?,
+              duplicate_records = ?, consistent = ?, partitions = ?, last_error = ?, completed_at = ?
+              WHERE id = ?`
+	result, err := db.Exec(query, v.Status, v.RecordsCompared, v.MismatchedRecords, v.MissingRecords,
+		v.DuplicateRecords, v.Consistent, v.PartitionsJSON, v.LastError, v.CompletedAt, v.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update content verification: %w", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetContentVerification retrieves a content verification by ID.
+func GetContentVerification(db *sqlx.DB, id int) (*ContentVerification, error) {
+	var v ContentVerification
+	if err := db.Get(&v, "SELECT * FROM content_verifications WHERE id = ?", id); err != nil {
+		return nil, err
+	}
+	if err := v.decode(); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// ListContentVerifications returns the content verifications of a job, newest first.
+func ListContentVerifications(db *sqlx.DB, jobID string) ([]ContentVerification, error) {
+	verifications := []ContentVerification{}
+	if err := db.Select(&verifications, "SELECT * FROM content_verifications WHERE job_id = ? ORDER BY started_at DESC, id DESC", jobID); err != nil {
+		return nil, fmt.Errorf("failed to list content verifications: %w", err)
+	}
+	for i := range verifications {
+		if err := verifications[i].decode(); err != nil {
+			return nil, err
+		}
+	}
+	return verifications, nil
+}
+
+// GetLatestContentVerification returns the newest completed content verification
+// of a job, or nil when there is none.
+func GetLatestContentVerification(db *sqlx.DB, jobID string) (*ContentVerification, error) {
+	var v ContentVerification
+	err := db.Get(&v, "SELECT * FROM content_verifications WHERE job_id = ? AND status = ? ORDER BY started_at DESC, id DESC LIMIT 1",
+		jobID, VerificationCompleted)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest content verification: %w", err)
+	}
+	if err := v.decode(); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// HasRunningContentVerification reports whether a content verification of the job is in progress.
+func HasRunningContentVerification(db *sqlx.DB, jobID string) (bool, error) {
+	var count int
+	err := db.Get(&count, "SELECT COUNT(*) FROM content_verifications WHERE job_id = ? AND status = ?", jobID, VerificationRunning)
+	return count > 0, err
+}
+
+// FailRunningContentVerifications marks every content verification still in
+// progress as failed with reason, returning how many were changed.
+func FailRunningContentVerifications(db *sqlx.DB, reason string) (int64, error) {
+	result, err := db.Exec("UPDATE content_verifications SET status = ?, last_error = ?, completed_at = ? WHERE status = ?",
+		VerificationFailed, reason, time.Now().UTC(), VerificationRunning)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fail running content verifications: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (v *ContentVerification) encode() error {
+	if v.Partitions == nil {
+		v.Partitions = []PartitionVerification{}
+	}
+	partitions, err := json.Marshal(v.Partitions)
+	if err != nil {
+		return err
+	}
+	v.PartitionsJSON = string(partitions)
+	return nil
+}
+
+func (v *ContentVerification) decode() error {
+	if err := json.Unmarshal([]byte(v.PartitionsJSON), &v.Partitions); err != nil {
+		return fmt.Errorf("content verification %d has invalid partitions: %w", v.ID, err)
+	}
+	return nil
+}
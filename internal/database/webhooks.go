@@ -0,0 +1,269 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Webhook event types.
+const (
+	WebhookEventJobStarted     = "job.started"
+	WebhookEventJobStopped     = "job.stopped"
+	WebhookEventJobFailed      = "job.failed"
+	WebhookEventGapDetected    = "job.gap_detected"
+	WebhookEventInsightCreated = "ai.insight_created"
+	// WebhookEventTest is only sent on request and cannot be subscribed to.
+	WebhookEventTest = "webhook.test"
+)
+
+// WebhookEventTypes lists every event type a webhook can subscribe to.
+var WebhookEventTypes = []string{
+	WebhookEventJobStarted, WebhookEventJobStopped, WebhookEventJobFailed,
+	WebhookEventGapDetected, WebhookEventInsightCreated,
+}
+
+// Webhook delivery statuses.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// Validate checks the name, URL and event types.
+func (w *Webhook) Validate() error {
+	if strings.TrimSpace(w.Name) == "" {
+		return fmt.Errorf("webhook name is required")
+	}
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https URL")
+	}
+	for _, eventType := range w.EventTypeList() {
+		valid := false
+		for _, t := range WebhookEventTypes {
+			if eventType == t {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("unknown event type %q, must be one of %s", eventType, strings.Join(WebhookEventTypes, ", "))
+		}
+	}
+	return nil
+}
+
+// EventTypeList returns the subscribed event types; empty means every event.
+func (w *Webhook) EventTypeList() []string {
+	return splitTokenList(w.EventTypes)
+}
+
+// Matches reports whether the webhook receives an event of eventType for jobID.
+func (w *Webhook) Matches(eventType, jobID string) bool {
+	if !w.Enabled || (w.JobID != "" && w.JobID != jobID) {
+		return false
+	}
+	types := w.EventTypeList()
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateWebhook stores a new webhook.
+func CreateWebhook(db *sqlx.DB, webhook *Webhook) error {
+	webhook.EventTypes = joinTokenList(splitTokenList(webhook.EventTypes))
+	now := time.Now().UTC()
+	query := `INSERT INTO webhooks (name, url, secret, event_types, job_id, enabled, created_by, created_at, updated_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := db.Exec(query, webhook.Name, webhook.URL, webhook.Secret, webhook.EventTypes, webhook.JobID,
+		webhook.Enabled, webhook.CreatedBy, now, now)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	webhook.ID = int(id)
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+	return nil
+}
+
+// UpdateWebhook overwrites an existing webhook.
+func UpdateWebhook(db *sqlx.DB, webhook *Webhook) error {
+	webhook.EventTypes = joinTokenList(splitTokenList(webhook.EventTypes))
+	webhook.UpdatedAt = time.Now().UTC()
+	query := `UPDATE webhooks SET name = ?, url = ?, secret = ?, event_types = ?, job_id = ?, enabled = ?, updated_at = ?
+              WHERE id = ?`
+	result, err := db.Exec(query, webhook.Name, webhook.URL, webhook.Secret, webhook.EventTypes, webhook.JobID,
+		webhook.Enabled, webhook.UpdatedAt, webhook.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetWebhook retrieves a webhook by ID.
+func GetWebhook(db *sqlx.DB, id int) (*Webhook, error) {
+	var webhook Webhook
+	if err := db.Get(&webhook, "SELECT * FROM webhooks WHERE id = ?", id); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// ListWebhooks returns all webhooks ordered by name.
+func ListWebhooks(db *sqlx.DB) ([]Webhook, error) {
+	webhooks := []Webhook{}
+	err := db.Select(&webhooks, "SELECT * FROM webhooks ORDER BY name")
+	return webhooks, err
+}
+
+// DeleteWebhook removes a webhook together with its delivery log.
+func DeleteWebhook(db *sqlx.DB, id int) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("DELETE FROM webhooks WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	if _, err := tx.Exec("DELETE FROM webhook_deliveries WHERE webhook_id = ?", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CreateWebhookDelivery queues a delivery. It is due immediately unless NextAttemptAt is set.
+func CreateWebhookDelivery(db *sqlx.DB, delivery *WebhookDelivery) error {
+	now := time.Now().UTC()
+	delivery.Status = WebhookDeliveryPending
+	delivery.CreatedAt = now
+	if delivery.NextAttemptAt == nil {
+		delivery.NextAttemptAt = &now
+	}
+	query := `INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, job_id, payload, status, attempts, next_attempt_at, replay_of, created_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := db.Exec(query, delivery.WebhookID, delivery.EventID, delivery.EventType, delivery.JobID,
+		delivery.Payload, delivery.Status, delivery.Attempts, delivery.NextAttemptAt.UTC(), delivery.ReplayOf, now)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	delivery.ID = int(id)
+	return nil
+}
+
+// GetWebhookDelivery retrieves a delivery by ID.
+func GetWebhookDelivery(db *sqlx.DB, id int) (*WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	if err := db.Get(&delivery, "SELECT * FROM webhook_deliveries WHERE id = ?", id); err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// ListWebhookDeliveries returns the deliveries of a webhook filtered by status
+// (empty for all), newest first.
+func ListWebhookDeliveries(db *sqlx.DB, webhookID int, status string, limit int) ([]WebhookDelivery, error) {
+	query := "SELECT * FROM webhook_deliveries WHERE webhook_id = ?"
+	args := []interface{}{webhookID}
+	if status != "" {
+		query += " AND status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY created_at DESC, id DESC"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	deliveries := []WebhookDelivery{}
+	err := db.Select(&deliveries, query, args...)
+	return deliveries, err
+}
+
+// UnreplayedFailedWebhookDeliveries returns the failed deliveries of a webhook that
+// have not been replayed yet, oldest first.
+func UnreplayedFailedWebhookDeliveries(db *sqlx.DB, webhookID int) ([]WebhookDelivery, error) {
+	deliveries := []WebhookDelivery{}
+	err := db.Select(&deliveries, `SELECT * FROM webhook_deliveries d WHERE d.webhook_id = ? AND d.status = ?
+                                   AND NOT EXISTS (SELECT 1 FROM webhook_deliveries r WHERE r.replay_of = d.id)
+                                   ORDER BY d.created_at, d.id`,
+		webhookID, WebhookDeliveryFailed)
+	return deliveries, err
+}
+
+// DueWebhookDeliveries returns pending deliveries whose next attempt is due, oldest first.
+func DueWebhookDeliveries(db *sqlx.DB, now time.Time, limit int) ([]WebhookDelivery, error) {
+	deliveries := []WebhookDelivery{}
+	err := db.Select(&deliveries, `SELECT * FROM webhook_deliveries WHERE status = ? AND next_attempt_at <= ?
+                                   ORDER BY next_attempt_at, id LIMIT ?`,
+		WebhookDeliveryPending, now.UTC(), limit)
+	return deliveries, err
+}
+
+// NextWebhookDeliveryAt returns when the earliest pending delivery is due.
+func NextWebhookDeliveryAt(db *sqlx.DB) (*time.Time, error) {
+	var next time.Time
+	err := db.Get(&next, `SELECT next_attempt_at FROM webhook_deliveries WHERE status = ?
+                          ORDER BY next_attempt_at LIMIT 1`, WebhookDeliveryPending)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &next, nil
+}
+
+// UpdateWebhookDeliveryAttempt records the outcome of a delivery attempt.
+func UpdateWebhookDeliveryAttempt(db *sqlx.DB, delivery *WebhookDelivery) error {
+	var nextAttemptAt interface{}
+	if delivery.NextAttemptAt != nil {
+		nextAttemptAt = delivery.NextAttemptAt.UTC()
+	}
+	var deliveredAt interface{}
+	if delivery.DeliveredAt != nil {
+		deliveredAt = delivery.DeliveredAt.UTC()
+	}
+	_, err := db.Exec(`UPDATE webhook_deliveries SET status = ?, attempts = ?, response_status = ?, last_error = ?,
+                       next_attempt_at = ?, delivered_at = ? WHERE id = ?`,
+		delivery.Status, delivery.Attempts, delivery.ResponseStatus, delivery.LastError,
+		nextAttemptAt, deliveredAt, delivery.ID)
+	return err
+}
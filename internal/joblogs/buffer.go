@@ -0,0 +1,201 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package joblogs keeps a bounded in-memory history of log records per job
+// and fans new records out to live subscribers.
+package joblogs
+
+import (
+	"kaf-mirror/pkg/logger"
+	"sync"
+	"time"
+)
+
+// DefaultCapacity is the number of records kept per job when none is configured.
+const DefaultCapacity = 1000
+
+// subscriptionBuffer is the channel size of a live subscription. Records are
+// dropped for a subscriber that falls this far behind.
+const subscriptionBuffer = 256
+
+// Entry is a buffered log record with its position in the global sequence.
+type Entry struct {
+	Seq uint64 `json:"seq"`
+	logger.Record
+}
+
+// Query filters buffered records. Zero values match everything.
+type Query struct {
+	Since    time.Time
+	AfterSeq uint64
+	MinLevel logger.Level
+	Limit    int
+}
+
+// Buffer holds a ring of recent records for every job that has logged.
+type Buffer struct {
+	mu       sync.RWMutex
+	capacity int
+	seq      uint64
+	rings    map[string]*ring
+	subs     map[string]map[*Subscription]struct{}
+}
+
+type ring struct {
+	entries []Entry
+	next    int
+	full    bool
+}
+
+// NewBuffer returns a Buffer keeping up to capacity records per job.
+func NewBuffer(capacity int) *Buffer {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Buffer{
+		capacity: capacity,
+		rings:    make(map[string]*ring),
+		subs:     make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Append stores rec in its job's ring and forwards it to subscribers.
+// Records without a job ID are ignored. It is safe to use as a logger listener.
+func (b *Buffer) Append(rec logger.Record) {
+	if rec.JobID == "" {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.seq++
+	entry := Entry{Seq: b.seq, Record: rec}
+
+	r, ok := b.rings[rec.JobID]
+	if !ok {
+		r = &ring{entries: make([]Entry, b.capacity)}
+		b.rings[rec.JobID] = r
+	}
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+
+	level := levelOf(rec.Level)
+	for sub := range b.subs[rec.JobID] {
+		if level < sub.minLevel {
+			continue
+		}
+		select {
+		case sub.ch <- entry:
+		default:
+			sub.dropped++
+		}
+	}
+}
+
+// Query returns the buffered records for jobID matching q, oldest first.
+// When q.Limit is set only the most recent matches are returned.
+func (b *Buffer) Query(jobID string, q Query) []Entry {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	r, ok := b.rings[jobID]
+	if !ok {
+		return []Entry{}
+	}
+	ordered := r.entries[:r.next]
+	if r.full {
+		ordered = append(append([]Entry{}, r.entries[r.next:]...), r.entries[:r.next]...)
+	}
+
+	result := make([]Entry, 0, len(ordered))
+	for _, e := range ordered {
+		if e.Seq <= q.AfterSeq {
+			continue
+		}
+		if !q.Since.IsZero() && e.Time.Before(q.Since) {
+			continue
+		}
+		if levelOf(e.Level) < q.MinLevel {
+			continue
+		}
+		result = append(result, e)
+	}
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[len(result)-q.Limit:]
+	}
+	return result
+}
+
+// Remove discards the buffered records for jobID.
+func (b *Buffer) Remove(jobID string) {
+	b.mu.Lock()
+	delete(b.rings, jobID)
+	b.mu.Unlock()
+}
+
+// Subscription receives records appended for one job after it was created.
+type Subscription struct {
+	C        <-chan Entry
+	ch       chan Entry
+	buffer   *Buffer
+	jobID    string
+	minLevel logger.Level
+	dropped  uint64
+	once     sync.Once
+}
+
+// Subscribe starts delivering new records for jobID at or above minLevel.
+// Callers must Close the subscription when done.
+func (b *Buffer) Subscribe(jobID string, minLevel logger.Level) *Subscription {
+	ch := make(chan Entry, subscriptionBuffer)
+	sub := &Subscription{C: ch, ch: ch, buffer: b, jobID: jobID, minLevel: minLevel}
+
+	b.mu.Lock()
+	if b.subs[jobID] == nil {
+		b.subs[jobID] = make(map[*Subscription]struct{})
+	}
+	b.subs[jobID][sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+// Dropped reports how many records were skipped because the subscriber was slow.
+func (s *Subscription) Dropped() uint64 {
+	s.buffer.mu.RLock()
+	defer s.buffer.mu.RUnlock()
+	return s.dropped
+}
+
+// Close stops delivery and closes C.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		b := s.buffer
+		b.mu.Lock()
+		delete(b.subs[s.jobID], s)
+		if len(b.subs[s.jobID]) == 0 {
+			delete(b.subs, s.jobID)
+		}
+		close(s.ch)
+		b.mu.Unlock()
+	})
+}
+
+// levelOf returns the level of a record, treating unknown names as DEBUG.
+func levelOf(name string) logger.Level {
+	level, err := logger.ParseLevel(name)
+	if err != nil {
+		return logger.DEBUG
+	}
+	return level
+}
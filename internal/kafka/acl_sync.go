@@ -0,0 +1,335 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ACLEntry is a single ACL. Resource types, pattern types, operations and
+// permissions are lower case Kafka names such as "topic", "prefixed", "read"
+// and "allow".
+type ACLEntry struct {
+	Principal    string `json:"principal"`
+	Host         string `json:"host"`
+	ResourceType string `json:"resource_type"`
+	ResourceName string `json:"resource_name"`
+	PatternType  string `json:"pattern_type"`
+	Operation    string `json:"operation"`
+	Permission   string `json:"permission"`
+}
+
+func (a ACLEntry) String() string {
+	return fmt.Sprintf("%s %s %s on %s %s:%s from %s", a.Permission, a.Principal, a.Operation, a.PatternType, a.ResourceType, a.ResourceName, a.Host)
+}
+
+// ClientQuota is the set of quota values of one client entity. Entity maps the
+// entity type ("user", "client-id") to its name, empty for the default entity.
+type ClientQuota struct {
+	Entity map[string]string  `json:"entity"`
+	Values map[string]float64 `json:"values"`
+}
+
+// entityKey returns a stable key for the quota entity, e.g. "client-id=app,user=alice".
+func (q ClientQuota) entityKey() string {
+	parts := make([]string, 0, len(q.Entity))
+	for entityType, name := range q.Entity {
+		if name == "" {
+			name = "<default>"
+		}
+		parts = append(parts, entityType+"="+name)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// ACLSyncPolicy decides which source ACLs and quotas are copied to the target
+// cluster and how their principals are rewritten.
+type ACLSyncPolicy struct {
+	Groups     []string
+	SyncQuotas bool
+	rewrites   []principalRewrite
+}
+
+type principalRewrite struct {
+	match   *regexp.Regexp
+	replace string
+}
+
+// NewACLSyncPolicy builds a policy from the ACL sync settings. Without group
+// patterns the ACLs of every consumer group are copied.
+func NewACLSyncPolicy(cfg config.ACLSyncConfig) (ACLSyncPolicy, error) {
+	policy := ACLSyncPolicy{Groups: cfg.Groups, SyncQuotas: cfg.SyncQuotas}
+	if len(policy.Groups) == 0 {
+		policy.Groups = []string{"*"}
+	}
+	for _, rule := range cfg.PrincipalRewrites {
+		re, err := regexp.Compile(rule.Match)
+		if err != nil {
+			return ACLSyncPolicy{}, fmt.Errorf("invalid principal rewrite %q: %w", rule.Match, err)
+		}
+		policy.rewrites = append(policy.rewrites, principalRewrite{match: re, replace: rule.Replace})
+	}
+	return policy, nil
+}
+
+// RewritePrincipal applies the first rewrite rule matching the principal.
+func (p ACLSyncPolicy) RewritePrincipal(principal string) string {
+	for _, rule := range p.rewrites {
+		if rule.match.MatchString(principal) {
+			return rule.match.ReplaceAllString(principal, rule.replace)
+		}
+	}
+	return principal
+}
+
+// Translate returns the target cluster equivalent of a source ACL. Topic names
+// are rewritten through topicMap. relevant is false for ACLs on resources the job
+// does not mirror; a non-empty skipReason marks relevant ACLs that cannot be
+// translated.
+func (p ACLSyncPolicy) Translate(acl ACLEntry, topicMap map[string]string) (translated ACLEntry, skipReason string, relevant bool) {
+	translated = acl
+	translated.Principal = p.RewritePrincipal(acl.Principal)
+
+	switch acl.ResourceType {
+	case "topic":
+		if acl.PatternType == "literal" {
+			if acl.ResourceName == "*" {
+				return translated, "", true
+			}
+			targetTopic, ok := topicMap[acl.ResourceName]
+			if !ok {
+				return acl, "", false
+			}
+			translated.ResourceName = targetTopic
+			return translated, "", true
+		}
+
+		covered, renamed := false, false
+		for sourceTopic, targetTopic := range topicMap {
+			if strings.HasPrefix(sourceTopic, acl.ResourceName) {
+				covered = true
+				renamed = renamed || sourceTopic != targetTopic
+			}
+		}
+		if !covered {
+			return acl, "", false
+		}
+		if renamed {
+			return acl, "prefixed ACL covers topics that are renamed on the target", true
+		}
+		return translated, "", true
+	case "group":
+		if !matchesAny(p.Groups, acl.ResourceName) {
+			return acl, "", false
+		}
+		return translated, "", true
+	default:
+		return acl, "", false
+	}
+}
+
+// TranslateQuota rewrites the user of a quota entity like an ACL principal.
+func (p ACLSyncPolicy) TranslateQuota(quota ClientQuota) ClientQuota {
+	translated := ClientQuota{Entity: make(map[string]string, len(quota.Entity)), Values: quota.Values}
+	for entityType, name := range quota.Entity {
+		if entityType == "user" && name != "" {
+			name = strings.TrimPrefix(p.RewritePrincipal("User:"+name), "User:")
+		}
+		translated.Entity[entityType] = name
+	}
+	return translated
+}
+
+// SkippedACL is a source ACL that applies to the job but could not be copied.
+type SkippedACL struct {
+	ACL    ACLEntry `json:"acl"`
+	Reason string   `json:"reason"`
+}
+
+// ClientQuotaDrift is a single quota value whose target value differs from the source.
+type ClientQuotaDrift struct {
+	Entity      map[string]string `json:"entity"`
+	Key         string            `json:"key"`
+	SourceValue float64           `json:"source_value"`
+	TargetValue *float64          `json:"target_value,omitempty"`
+}
+
+// ACLSyncReport is the result of comparing or syncing the ACLs and quotas of a job.
+type ACLSyncReport struct {
+	JobID         string             `json:"job_id"`
+	DryRun        bool               `json:"dry_run"`
+	CheckedAt     time.Time          `json:"checked_at"`
+	SourceACLs    int                `json:"source_acls"`
+	Missing       []ACLEntry         `json:"missing"`
+	Skipped       []SkippedACL       `json:"skipped"`
+	CreatedACLs   int                `json:"created_acls"`
+	QuotasSynced  bool               `json:"quotas_synced"`
+	QuotaDrift    []ClientQuotaDrift `json:"quota_drift,omitempty"`
+	AppliedQuotas int                `json:"applied_quotas"`
+	Errors        []string           `json:"errors,omitempty"`
+}
+
+// SyncACLs copies the ACLs, and optionally the client quotas, that the job's
+// clients need from the source to the target cluster. With dryRun nothing is
+// changed and the report lists what would be created.
+func SyncACLs(ctx context.Context, cfg *config.Config, dryRun bool) (*ACLSyncReport, error) {
+	_, topicMap, _, err := resolveTopicMappings(cfg)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := NewACLSyncPolicy(cfg.Replication.ACLSync)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceAdmin, err := adminClientFactory(cfg.Clusters["source"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create source admin client: %w", err)
+	}
+	defer sourceAdmin.Close()
+
+	targetAdmin, err := adminClientFactory(cfg.Clusters["target"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create target admin client: %w", err)
+	}
+	defer targetAdmin.Close()
+
+	report, err := reconcileACLs(ctx, sourceAdmin, targetAdmin, topicMap, policy, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	report.JobID = cfg.Replication.JobID
+	return report, nil
+}
+
+// reconcileACLs creates the translated source ACLs missing on the target and,
+// when enabled, sets the target quotas to the source values. ACLs and quotas that
+// exist only on the target are left alone. Per-ACL failures are recorded in the
+// report rather than returned.
+func reconcileACLs(ctx context.Context, sourceAdmin, targetAdmin AdminClientAPI, topicMap map[string]string, policy ACLSyncPolicy, dryRun bool) (*ACLSyncReport, error) {
+	report := &ACLSyncReport{
+		DryRun:       dryRun,
+		CheckedAt:    time.Now(),
+		Missing:      []ACLEntry{},
+		Skipped:      []SkippedACL{},
+		QuotasSynced: policy.SyncQuotas,
+	}
+
+	sourceACLs, err := sourceAdmin.DescribeACLs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe source ACLs: %w", err)
+	}
+	targetACLs, err := targetAdmin.DescribeACLs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe target ACLs: %w", err)
+	}
+	existing := make(map[ACLEntry]bool, len(targetACLs))
+	for _, acl := range targetACLs {
+		existing[acl] = true
+	}
+
+	for _, acl := range sourceACLs {
+		translated, skipReason, relevant := policy.Translate(acl, topicMap)
+		if !relevant {
+			continue
+		}
+		report.SourceACLs++
+		if skipReason != "" {
+			report.Skipped = append(report.Skipped, SkippedACL{ACL: acl, Reason: skipReason})
+			continue
+		}
+		if existing[translated] {
+			continue
+		}
+		existing[translated] = true
+		report.Missing = append(report.Missing, translated)
+	}
+	sort.Slice(report.Missing, func(i, j int) bool { return report.Missing[i].String() < report.Missing[j].String() })
+
+	if !dryRun {
+		for _, acl := range report.Missing {
+			if err := targetAdmin.CreateACLs(ctx, []ACLEntry{acl}); err != nil {
+				report.Errors = append(report.Errors, err.Error())
+				continue
+			}
+			report.CreatedACLs++
+		}
+	}
+
+	if policy.SyncQuotas {
+		if err := reconcileClientQuotas(ctx, sourceAdmin, targetAdmin, policy, report); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+func reconcileClientQuotas(ctx context.Context, sourceAdmin, targetAdmin AdminClientAPI, policy ACLSyncPolicy, report *ACLSyncReport) error {
+	sourceQuotas, err := sourceAdmin.DescribeClientQuotas(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to describe source client quotas: %w", err)
+	}
+	targetQuotas, err := targetAdmin.DescribeClientQuotas(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to describe target client quotas: %w", err)
+	}
+	targetByEntity := make(map[string]ClientQuota, len(targetQuotas))
+	for _, quota := range targetQuotas {
+		targetByEntity[quota.entityKey()] = quota
+	}
+
+	var changes []ClientQuota
+	for _, source := range sourceQuotas {
+		desired := policy.TranslateQuota(source)
+		target := targetByEntity[desired.entityKey()]
+
+		change := ClientQuota{Entity: desired.Entity, Values: make(map[string]float64)}
+		for key, value := range desired.Values {
+			drift := ClientQuotaDrift{Entity: desired.Entity, Key: key, SourceValue: value}
+			if current, ok := target.Values[key]; ok {
+				if current == value {
+					continue
+				}
+				drift.TargetValue = &current
+			}
+			report.QuotaDrift = append(report.QuotaDrift, drift)
+			change.Values[key] = value
+		}
+		if len(change.Values) > 0 {
+			changes = append(changes, change)
+		}
+	}
+	sort.Slice(report.QuotaDrift, func(i, j int) bool {
+		left, right := ClientQuota{Entity: report.QuotaDrift[i].Entity}, ClientQuota{Entity: report.QuotaDrift[j].Entity}
+		if left.entityKey() != right.entityKey() {
+			return left.entityKey() < right.entityKey()
+		}
+		return report.QuotaDrift[i].Key < report.QuotaDrift[j].Key
+	})
+
+	if report.DryRun || len(changes) == 0 {
+		return nil
+	}
+	if err := targetAdmin.AlterClientQuotas(ctx, changes, false); err != nil {
+		report.Errors = append(report.Errors, err.Error())
+		return nil
+	}
+	report.AppliedQuotas = len(changes)
+	return nil
+}
@@ -18,11 +18,13 @@ import (
 	"fmt"
 	"kaf-mirror/internal/config"
 	"kaf-mirror/pkg/logger"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/twmb/franz-go/pkg/kadm"
 	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
 	"github.com/twmb/franz-go/pkg/sasl/plain"
 	"github.com/twmb/franz-go/pkg/sasl/scram"
 )
@@ -54,6 +56,7 @@ type OffsetInfo struct {
 	Partition int32
 	Offset    int64
 	HighWaterMark int64
+	LogStartOffset int64
 	Lag       int64
 }
 
@@ -290,30 +293,42 @@ func (a *AdminClient) GetConsumerGroupOffsets(ctx context.Context, groupID strin
 func (a *AdminClient) GetTopicHighWaterMarks(ctx context.Context, topics []string) (map[string][]OffsetInfo, error) {
 	logger.Info("Retrieving high water marks for topics: %v", topics)
 	
+	endOffsets, err := a.client.ListEndOffsets(ctx, topics...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list end offsets: %w", err)
+	}
+	startOffsets, err := a.client.ListStartOffsets(ctx, topics...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list start offsets: %w", err)
+	}
+
 	result := make(map[string][]OffsetInfo)
 	
 	for _, topicName := range topics {
-		topicDetails, err := a.client.ListTopics(ctx, topicName)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get metadata for topic %s: %w", topicName, err)
-		}
-		
-		details, exists := topicDetails[topicName]
-		if !exists {
+		partitions, exists := endOffsets[topicName]
+		if _, unknown := partitions[-1]; !exists || unknown {
 			logger.Warn("Topic %s not found", topicName)
 			continue
 		}
 
-		for _, partition := range details.Partitions {
+		for partition, end := range partitions {
+			if end.Err != nil {
+				return nil, fmt.Errorf("failed to get high water mark for topic %s, partition %d: %w", topicName, partition, end.Err)
+			}
 			offsetInfo := OffsetInfo{
 				Topic:         topicName,
-				Partition:     partition.Partition,
-				Offset:        0,
-				HighWaterMark: 0,
-				Lag:           0,
+				Partition:     partition,
+				Offset:        end.Offset,
+				HighWaterMark: end.Offset,
+			}
+			if start, ok := startOffsets.Lookup(topicName, partition); ok && start.Err == nil {
+				offsetInfo.LogStartOffset = start.Offset
 			}
 			result[topicName] = append(result[topicName], offsetInfo)
 		}
+		sort.Slice(result[topicName], func(i, j int) bool {
+			return result[topicName][i].Partition < result[topicName][j].Partition
+		})
 	}
 
 	return result, nil
@@ -336,7 +351,9 @@ func (a *AdminClient) ValidateTopicCompatibility(ctx context.Context, sourceInfo
 	return nil
 }
 
-func (a *AdminClient) EnsureTopicExists(ctx context.Context, topicName string, partitions int32, replicationFactor int16) error {
+// EnsureTopicExists creates the topic unless it already exists. The given configs are
+// only applied when the topic is created; existing topics are left untouched.
+func (a *AdminClient) EnsureTopicExists(ctx context.Context, topicName string, partitions int32, replicationFactor int16, configs map[string]string) error {
 	logger.Info("Ensuring topic %s exists with %d partitions", topicName, partitions)
 	
 	existing, err := a.client.ListTopics(ctx, topicName)
@@ -349,7 +366,16 @@ func (a *AdminClient) EnsureTopicExists(ctx context.Context, topicName string, p
 		return nil
 	}
 
-	results, err := a.client.CreateTopics(ctx, partitions, replicationFactor, nil, topicName)
+	var topicConfigs map[string]*string
+	if len(configs) > 0 {
+		topicConfigs = make(map[string]*string, len(configs))
+		for key, value := range configs {
+			value := value
+			topicConfigs[key] = &value
+		}
+	}
+
+	results, err := a.client.CreateTopics(ctx, partitions, replicationFactor, topicConfigs, topicName)
 	if err != nil {
 		return fmt.Errorf("failed to create topic %s: %w", topicName, err)
 	}
@@ -364,6 +390,307 @@ func (a *AdminClient) EnsureTopicExists(ctx context.Context, topicName string, p
 	return nil
 }
 
+// CreatePartitions grows a topic to the given total number of partitions.
+func (a *AdminClient) CreatePartitions(ctx context.Context, topicName string, totalPartitions int32) error {
+	responses, err := a.client.UpdatePartitions(ctx, int(totalPartitions), topicName)
+	if err != nil {
+		return fmt.Errorf("failed to add partitions to topic %s: %w", topicName, err)
+	}
+	for _, response := range responses {
+		if response.Err != nil {
+			return fmt.Errorf("failed to add partitions to topic %s: %w", response.Topic, response.Err)
+		}
+	}
+	logger.Info("Topic %s now has %d partitions", topicName, totalPartitions)
+	return nil
+}
+
+// DeleteTopic deletes a topic.
+func (a *AdminClient) DeleteTopic(ctx context.Context, topicName string) error {
+	if _, err := a.client.DeleteTopic(ctx, topicName); err != nil {
+		return fmt.Errorf("failed to delete topic %s: %w", topicName, err)
+	}
+	logger.Info("Deleted topic %s", topicName)
+	return nil
+}
+
+// DescribeTopicConfigs returns the effective configs of the given topics keyed by
+// topic and config name. Sensitive configs are left out.
+func (a *AdminClient) DescribeTopicConfigs(ctx context.Context, topics ...string) (map[string]map[string]string, error) {
+	described, err := a.client.DescribeTopicConfigs(ctx, topics...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe topic configs: %w", err)
+	}
+
+	result := make(map[string]map[string]string, len(described))
+	for _, topic := range described {
+		if topic.Err != nil {
+			return nil, fmt.Errorf("failed to describe configs of topic %s: %w", topic.Name, topic.Err)
+		}
+		configs := make(map[string]string, len(topic.Configs))
+		for _, entry := range topic.Configs {
+			if entry.Sensitive || entry.Value == nil {
+				continue
+			}
+			configs[entry.Key] = *entry.Value
+		}
+		result[topic.Name] = configs
+	}
+	return result, nil
+}
+
+// AlterTopicConfigs sets the given configs on a topic with IncrementalAlterConfigs,
+// leaving every other config of the topic as it is. With validateOnly the broker
+// only checks that the change would be accepted.
+func (a *AdminClient) AlterTopicConfigs(ctx context.Context, topic string, configs map[string]string, validateOnly bool) error {
+	if len(configs) == 0 {
+		return nil
+	}
+
+	alterations := make([]kadm.AlterConfig, 0, len(configs))
+	for key, value := range configs {
+		value := value
+		alterations = append(alterations, kadm.AlterConfig{Op: kadm.SetConfig, Name: key, Value: &value})
+	}
+
+	alter := a.client.AlterTopicConfigs
+	if validateOnly {
+		alter = a.client.ValidateAlterTopicConfigs
+	}
+	responses, err := alter(ctx, alterations, topic)
+	if err != nil {
+		return fmt.Errorf("failed to alter configs of topic %s: %w", topic, err)
+	}
+	for _, response := range responses {
+		if response.Err != nil {
+			if response.ErrMessage != "" {
+				return fmt.Errorf("failed to alter configs of topic %s: %w: %s", response.Name, response.Err, response.ErrMessage)
+			}
+			return fmt.Errorf("failed to alter configs of topic %s: %w", response.Name, response.Err)
+		}
+	}
+	return nil
+}
+
+// DescribeACLs returns every ACL of the cluster. Clusters without an authorizer
+// report a security-disabled error.
+func (a *AdminClient) DescribeACLs(ctx context.Context) ([]ACLEntry, error) {
+	builder := kadm.NewACLs().
+		AnyResource().
+		ResourcePatternType(kadm.ACLPatternAny).
+		Operations(kadm.OpAny).
+		Allow().
+		AllowHosts().
+		Deny().
+		DenyHosts()
+	results, err := a.client.DescribeACLs(ctx, builder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe ACLs: %w", err)
+	}
+
+	var acls []ACLEntry
+	for _, result := range results {
+		if result.Err != nil {
+			return nil, fmt.Errorf("failed to describe ACLs: %w", result.Err)
+		}
+		for _, described := range result.Described {
+			acls = append(acls, ACLEntry{
+				Principal:    described.Principal,
+				Host:         described.Host,
+				ResourceType: strings.ToLower(described.Type.String()),
+				ResourceName: described.Name,
+				PatternType:  strings.ToLower(described.Pattern.String()),
+				Operation:    strings.ToLower(described.Operation.String()),
+				Permission:   strings.ToLower(described.Permission.String()),
+			})
+		}
+	}
+	return acls, nil
+}
+
+// CreateACLs creates the given topic and group ACLs.
+func (a *AdminClient) CreateACLs(ctx context.Context, acls []ACLEntry) error {
+	for _, acl := range acls {
+		builder, err := aclBuilder(acl)
+		if err != nil {
+			return err
+		}
+		results, err := a.client.CreateACLs(ctx, builder)
+		if err != nil {
+			return fmt.Errorf("failed to create ACL %s: %w", acl, err)
+		}
+		for _, result := range results {
+			if result.Err != nil {
+				return fmt.Errorf("failed to create ACL %s: %w", acl, result.Err)
+			}
+		}
+	}
+	return nil
+}
+
+func aclBuilder(acl ACLEntry) (*kadm.ACLBuilder, error) {
+	builder := kadm.NewACLs()
+	switch acl.ResourceType {
+	case "topic":
+		builder.Topics(acl.ResourceName)
+	case "group":
+		builder.Groups(acl.ResourceName)
+	default:
+		return nil, fmt.Errorf("unsupported ACL resource type %q", acl.ResourceType)
+	}
+
+	pattern, err := kmsg.ParseACLResourcePatternType(acl.PatternType)
+	if err != nil {
+		return nil, err
+	}
+	operation, err := kmsg.ParseACLOperation(acl.Operation)
+	if err != nil {
+		return nil, err
+	}
+	builder.ResourcePatternType(pattern).Operations(operation)
+
+	switch acl.Permission {
+	case "allow":
+		builder.Allow(acl.Principal).AllowHosts(acl.Host)
+	case "deny":
+		builder.Deny(acl.Principal).DenyHosts(acl.Host)
+	default:
+		return nil, fmt.Errorf("unsupported ACL permission %q", acl.Permission)
+	}
+	return builder, nil
+}
+
+// DescribeClientQuotas returns the user and client-id quotas of the cluster.
+func (a *AdminClient) DescribeClientQuotas(ctx context.Context) ([]ClientQuota, error) {
+	seen := make(map[string]bool)
+	var quotas []ClientQuota
+	for _, entityType := range []string{"user", "client-id"} {
+		described, err := a.client.DescribeClientQuotas(ctx, false, []kadm.DescribeClientQuotaComponent{{
+			Type:      entityType,
+			MatchType: kmsg.QuotasMatchTypeAny,
+		}})
+		if err != nil {
+			return nil, fmt.Errorf("failed to describe %s quotas: %w", entityType, err)
+		}
+		for _, d := range described {
+			if seen[d.Entity.String()] {
+				continue
+			}
+			seen[d.Entity.String()] = true
+
+			quota := ClientQuota{Entity: make(map[string]string), Values: make(map[string]float64)}
+			for _, component := range d.Entity {
+				name := ""
+				if component.Name != nil {
+					name = *component.Name
+				}
+				quota.Entity[component.Type] = name
+			}
+			for _, value := range d.Values {
+				quota.Values[value.Key] = value.Value
+			}
+			quotas = append(quotas, quota)
+		}
+	}
+	return quotas, nil
+}
+
+// AlterClientQuotas sets the given quota values. Values not listed are left as they
+// are. With validateOnly the broker only checks that the change would be accepted.
+func (a *AdminClient) AlterClientQuotas(ctx context.Context, quotas []ClientQuota, validateOnly bool) error {
+	if len(quotas) == 0 {
+		return nil
+	}
+
+	entries := make([]kadm.AlterClientQuotaEntry, 0, len(quotas))
+	for _, quota := range quotas {
+		var entry kadm.AlterClientQuotaEntry
+		for entityType, name := range quota.Entity {
+			component := kadm.ClientQuotaEntityComponent{Type: entityType}
+			if name != "" {
+				name := name
+				component.Name = &name
+			}
+			entry.Entity = append(entry.Entity, component)
+		}
+		for key, value := range quota.Values {
+			entry.Ops = append(entry.Ops, kadm.AlterClientQuotaOp{Key: key, Value: value})
+		}
+		entries = append(entries, entry)
+	}
+
+	alter := a.client.AlterClientQuotas
+	if validateOnly {
+		alter = a.client.ValidateAlterClientQuotas
+	}
+	results, err := alter(ctx, entries)
+	if err != nil {
+		return fmt.Errorf("failed to alter client quotas: %w", err)
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			return fmt.Errorf("failed to alter client quotas of %s: %w", result.Entity, result.Err)
+		}
+	}
+	return nil
+}
+
+// GetOffsetsForTimestamp returns, per partition of the topics, the offset of the first
+// record with a timestamp at or after millis, or the high water mark if there is none.
+func (a *AdminClient) GetOffsetsForTimestamp(ctx context.Context, topics []string, millis int64) (map[string][]OffsetInfo, error) {
+	listed, err := a.client.ListOffsetsAfterMilli(ctx, millis, topics...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list offsets after %d: %w", millis, err)
+	}
+	result := make(map[string][]OffsetInfo)
+	for _, topicName := range topics {
+		for partition, offset := range listed[topicName] {
+			if partition < 0 {
+				continue // unknown topic
+			}
+			if offset.Err != nil {
+				return nil, fmt.Errorf("failed to get offset for topic %s, partition %d: %w", topicName, partition, offset.Err)
+			}
+			result[topicName] = append(result[topicName], OffsetInfo{Topic: topicName, Partition: partition, Offset: offset.Offset})
+		}
+		sort.Slice(result[topicName], func(i, j int) bool {
+			return result[topicName][i].Partition < result[topicName][j].Partition
+		})
+	}
+	return result, nil
+}
+
+// ListConsumerGroups returns the names of all consumer groups of the cluster.
+func (a *AdminClient) ListConsumerGroups(ctx context.Context) ([]string, error) {
+	groups, err := a.client.ListGroups(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list consumer groups: %w", err)
+	}
+	return groups.Groups(), nil
+}
+
+// CommitConsumerGroupOffsets sets the committed offsets of a consumer group. The
+// group must have no active members.
+func (a *AdminClient) CommitConsumerGroupOffsets(ctx context.Context, groupID string, offsets map[string]map[int32]int64) error {
+	var commits kadm.Offsets
+	for topic, partitions := range offsets {
+		for partition, offset := range partitions {
+			commits.Add(kadm.Offset{Topic: topic, Partition: partition, At: offset, LeaderEpoch: -1})
+		}
+	}
+	if len(commits) == 0 {
+		return nil
+	}
+	responses, err := a.client.CommitOffsets(ctx, groupID, commits)
+	if err != nil {
+		return fmt.Errorf("failed to commit offsets for group %s: %w", groupID, err)
+	}
+	if err := responses.Error(); err != nil {
+		return fmt.Errorf("failed to commit offsets for group %s: %w", groupID, err)
+	}
+	return nil
+}
+
 func (a *AdminClient) GetTopicDetails(ctx context.Context, topicNames ...string) ([]TopicDetails, error) {
 	listedTopics, err := a.client.ListTopics(ctx, topicNames...)
 	if err != nil {
@@ -451,6 +778,12 @@ func (a *AdminClient) GetTopicLag(ctx context.Context, groupID, topic string) (i
 }
 
 func (a *AdminClient) CompareClusterOffsets(ctx context.Context, sourceAdmin *AdminClient, topicMap map[string]string) (*OffsetComparisonResult, error) {
+	return CompareClusterOffsets(ctx, sourceAdmin, a, topicMap)
+}
+
+// CompareClusterOffsets compares the high water marks of every mapped source topic
+// with its target topic.
+func CompareClusterOffsets(ctx context.Context, sourceAdmin, targetAdmin AdminClientAPI, topicMap map[string]string) (*OffsetComparisonResult, error) {
 	logger.Info("Starting cross-cluster offset comparison")
 	
 	result := &OffsetComparisonResult{
@@ -462,7 +795,7 @@ func (a *AdminClient) CompareClusterOffsets(ctx context.Context, sourceAdmin *Ad
 	}
 
 	for sourceTopic, targetTopic := range topicMap {
-		comparison, err := a.compareTopicOffsets(ctx, sourceAdmin, sourceTopic, targetTopic)
+		comparison, err := compareTopicOffsets(ctx, sourceAdmin, targetAdmin, sourceTopic, targetTopic)
 		if err != nil {
 			result.CriticalIssues = append(result.CriticalIssues, 
 				fmt.Sprintf("Failed to compare %s -> %s: %v", sourceTopic, targetTopic, err))
@@ -484,13 +817,13 @@ func (a *AdminClient) CompareClusterOffsets(ctx context.Context, sourceAdmin *Ad
 	return result, nil
 }
 
-func (a *AdminClient) compareTopicOffsets(ctx context.Context, sourceAdmin *AdminClient, sourceTopic, targetTopic string) (*TopicOffsetComparison, error) {
+func compareTopicOffsets(ctx context.Context, sourceAdmin, targetAdmin AdminClientAPI, sourceTopic, targetTopic string) (*TopicOffsetComparison, error) {
 	sourceHWMs, err := sourceAdmin.GetTopicHighWaterMarks(ctx, []string{sourceTopic})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get source high water marks: %w", err)
 	}
 
-	targetHWMs, err := a.GetTopicHighWaterMarks(ctx, []string{targetTopic})
+	targetHWMs, err := targetAdmin.GetTopicHighWaterMarks(ctx, []string{targetTopic})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get target high water marks: %w", err)
 	}
@@ -558,9 +891,15 @@ func (a *AdminClient) compareTopicOffsets(ctx context.Context, sourceAdmin *Admi
 }
 
 func (a *AdminClient) AnalyzeMirrorState(ctx context.Context, sourceAdmin *AdminClient, jobID string, topicMap map[string]string, consumerGroup string) (*MirrorStateAnalysis, error) {
+	return AnalyzeMirrorState(ctx, sourceAdmin, a, jobID, topicMap, consumerGroup)
+}
+
+// AnalyzeMirrorState compares both clusters and the job's consumer group offsets
+// and returns the gaps found, safe resume points and recommendations.
+func AnalyzeMirrorState(ctx context.Context, sourceAdmin, targetAdmin AdminClientAPI, jobID string, topicMap map[string]string, consumerGroup string) (*MirrorStateAnalysis, error) {
 	logger.Info("Starting mirror state analysis for job %s", jobID)
 	
-	offsetComparison, err := a.CompareClusterOffsets(ctx, sourceAdmin, topicMap)
+	offsetComparison, err := CompareClusterOffsets(ctx, sourceAdmin, targetAdmin, topicMap)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compare cluster offsets: %w", err)
 	}
@@ -0,0 +1,247 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"kaf-mirror/internal/config"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BackfillProgress is how far a backfill job has copied the range of one source partition.
+type BackfillProgress struct {
+	Topic     string
+	Partition int32
+	// NextOffset is the first offset of the range the target has not acknowledged yet.
+	NextOffset int64
+	Done       bool
+	// Error is the first produce error of the partition; copying stops at NextOffset.
+	Error string
+}
+
+// BackfillNotifier is implemented by mirrors of backfill jobs. The handler receives
+// the progress of every range each metrics interval, and once more with done set
+// when every range is either copied or failed. It must be set before Start.
+type BackfillNotifier interface {
+	OnBackfillProgress(handler func(jobID string, progress []BackfillProgress, done bool))
+}
+
+// backfillTracker follows the records of bounded partition ranges from the consumer
+// to the target acknowledgement.
+type backfillTracker struct {
+	mu         sync.Mutex
+	partitions map[string]map[int32]*backfillPartition
+	finished   bool
+}
+
+type backfillPartition struct {
+	end      int64
+	consumed int64 // offset after the last record handed to the producer
+	inFlight map[int64]struct{}
+	stopped  bool  // no more records are taken from the partition
+	failedAt int64 // first offset that failed to produce, -1 if none
+	err      string
+}
+
+func newBackfillTracker(ranges []config.BackfillRange) *backfillTracker {
+	t := &backfillTracker{partitions: make(map[string]map[int32]*backfillPartition)}
+	for _, r := range ranges {
+		if t.partitions[r.Topic] == nil {
+			t.partitions[r.Topic] = make(map[int32]*backfillPartition)
+		}
+		t.partitions[r.Topic][r.Partition] = &backfillPartition{
+			end:      r.EndOffset,
+			consumed: r.NextOffset,
+			inFlight: make(map[int64]struct{}),
+			stopped:  r.NextOffset >= r.EndOffset,
+			failedAt: -1,
+		}
+	}
+	return t
+}
+
+// backfillOffsets returns where the consumer starts each range that is not copied yet.
+func backfillOffsets(ranges []config.BackfillRange) map[string]map[int32]int64 {
+	offsets := make(map[string]map[int32]int64)
+	for _, r := range ranges {
+		if r.NextOffset >= r.EndOffset {
+			continue
+		}
+		if offsets[r.Topic] == nil {
+			offsets[r.Topic] = make(map[int32]int64)
+		}
+		offsets[r.Topic][r.Partition] = r.NextOffset
+	}
+	return offsets
+}
+
+// admit reports whether a consumed record lies in its range and is to be copied, and
+// whether the consumer should stop reading the partition because its range is consumed
+// or failed. stop is reported once per partition.
+func (t *backfillTracker) admit(topic string, partition int32, offset int64) (admitted, stop bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p := t.partitions[topic][partition]
+	if p == nil || p.stopped {
+		return false, false
+	}
+	if offset >= p.end || p.failedAt >= 0 {
+		if p.failedAt < 0 {
+			p.consumed = p.end
+		}
+		p.stopped = true
+		return false, true
+	}
+	p.consumed = offset + 1
+	p.inFlight[offset] = struct{}{}
+	if p.consumed >= p.end {
+		p.stopped = true
+		return true, true
+	}
+	return true, false
+}
+
+// acked records that the copy of a record was acknowledged or failed.
+func (t *backfillTracker) acked(topic string, partition int32, offset int64, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p := t.partitions[topic][partition]
+	if p == nil {
+		return
+	}
+	delete(p.inFlight, offset)
+	if err != nil && (p.failedAt < 0 || offset < p.failedAt) {
+		p.failedAt = offset
+		p.err = err.Error()
+	}
+}
+
+// progress returns the progress of every range, by topic and partition.
+func (t *backfillTracker) progress() []BackfillProgress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.progressLocked()
+}
+
+func (t *backfillTracker) progressLocked() []BackfillProgress {
+	var result []BackfillProgress
+	for topic, parts := range t.partitions {
+		for partition, p := range parts {
+			next := p.consumed
+			for offset := range p.inFlight {
+				if offset < next {
+					next = offset
+				}
+			}
+			if p.failedAt >= 0 && p.failedAt < next {
+				next = p.failedAt
+			}
+			result = append(result, BackfillProgress{
+				Topic:      topic,
+				Partition:  partition,
+				NextOffset: next,
+				Done:       p.failedAt < 0 && len(p.inFlight) == 0 && p.consumed >= p.end,
+				Error:      p.err,
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Topic != result[j].Topic {
+			return result[i].Topic < result[j].Topic
+		}
+		return result[i].Partition < result[j].Partition
+	})
+	return result
+}
+
+// finish returns the final progress once every range is copied or failed with no
+// records in flight. It reports true only the first time.
+func (t *backfillTracker) finish() ([]BackfillProgress, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.finished {
+		return nil, false
+	}
+	for _, parts := range t.partitions {
+		for _, p := range parts {
+			if len(p.inFlight) > 0 || (p.failedAt < 0 && p.consumed < p.end) {
+				return nil, false
+			}
+		}
+	}
+	t.finished = true
+	return t.progressLocked(), true
+}
+
+func (t *backfillTracker) isFinished() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.finished
+}
+
+// OnBackfillProgress sets the handler that receives the progress of a backfill job.
+func (r *KafMirrorImpl) OnBackfillProgress(handler func(jobID string, progress []BackfillProgress, done bool)) {
+	r.onBackfill = handler
+}
+
+// admitBackfill reports whether a record is copied. Records outside the backfill
+// ranges are dropped and partitions are no longer read once their range is consumed.
+func (r *KafMirrorImpl) admitBackfill(topic string, partition int32, offset int64) bool {
+	admitted, stop := r.backfill.admit(topic, partition, offset)
+	if stop {
+		r.Consumer.RemovePartitions(map[string][]int32{topic: {partition}})
+	}
+	if !admitted {
+		r.checkBackfillDone()
+	}
+	return admitted
+}
+
+// ackBackfill records the acknowledgement of a copied record.
+func (r *KafMirrorImpl) ackBackfill(topic string, partition int32, offset int64, err error) {
+	r.backfill.acked(topic, partition, offset, err)
+	r.checkBackfillDone()
+}
+
+// checkBackfillDone reports the final progress once every range is settled.
+func (r *KafMirrorImpl) checkBackfillDone() {
+	progress, done := r.backfill.finish()
+	if !done {
+		return
+	}
+	r.log("backfill").Info("Backfill finished")
+	if r.onBackfill != nil {
+		r.onBackfill(r.jobID, progress, true)
+	}
+}
+
+// reportBackfillLoop reports the backfill progress every metrics interval until the
+// backfill is finished.
+func (r *KafMirrorImpl) reportBackfillLoop(ctx context.Context) {
+	ticker := time.NewTicker(metricsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if r.backfill.isFinished() {
+				return
+			}
+			if r.onBackfill != nil {
+				r.onBackfill(r.jobID, r.backfill.progress(), false)
+			}
+		}
+	}
+}
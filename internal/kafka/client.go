@@ -26,6 +26,12 @@ type KgoClient interface {
 	PollFetches(context.Context) kgo.Fetches
 	Produce(context.Context, *kgo.Record, func(*kgo.Record, error))
 	AddConsumeTopics(...string)
+	PurgeTopicsFromConsuming(...string)
+	AddConsumePartitions(map[string]map[int32]kgo.Offset)
+	RemoveConsumePartitions(map[string][]int32)
+	PauseFetchPartitions(map[string][]int32) map[string][]int32
+	ResumeFetchPartitions(map[string][]int32)
+	MarkCommitRecords(...*kgo.Record)
 	Close()
 }
 
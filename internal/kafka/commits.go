@@ -0,0 +1,90 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// commitTracker decides which consumed records may be committed. A record is
+// committed once it and every earlier record of its partition are settled, so a
+// restart resumes at the first record that was not mirrored, dead-lettered or
+// skipped by the error policy.
+type commitTracker struct {
+	mu         sync.Mutex
+	partitions map[string]map[int32]*pendingRecords
+}
+
+// pendingRecords are the consumed records of a partition that are not committed
+// yet, in offset order, and the offsets of those that are settled.
+type pendingRecords struct {
+	records []*kgo.Record
+	settled map[int64]bool
+}
+
+// track adds a consumed record. A record at or before the last one tracked means
+// the partition was assigned again, so its earlier records are forgotten.
+func (t *commitTracker) track(record *kgo.Record) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.partitions == nil {
+		t.partitions = make(map[string]map[int32]*pendingRecords)
+	}
+	if t.partitions[record.Topic] == nil {
+		t.partitions[record.Topic] = make(map[int32]*pendingRecords)
+	}
+	p := t.partitions[record.Topic][record.Partition]
+	if p == nil || (len(p.records) > 0 && record.Offset <= p.records[len(p.records)-1].Offset) {
+		p = &pendingRecords{settled: make(map[int64]bool)}
+		t.partitions[record.Topic][record.Partition] = p
+	}
+	p.records = append(p.records, record)
+}
+
+// settle notes that record needs no more work. It returns the last record of the
+// partition that can now be committed, or nil if an earlier one is unsettled.
+func (t *commitTracker) settle(record *kgo.Record) *kgo.Record {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p := t.partitions[record.Topic][record.Partition]
+	if p == nil {
+		return nil
+	}
+	// A record consumed before the partition was assigned again is not pending.
+	i := sort.Search(len(p.records), func(i int) bool { return p.records[i].Offset >= record.Offset })
+	if i == len(p.records) || p.records[i] != record {
+		return nil
+	}
+	p.settled[record.Offset] = true
+	var last *kgo.Record
+	for len(p.records) > 0 && p.settled[p.records[0].Offset] {
+		last = p.records[0]
+		delete(p.settled, last.Offset)
+		p.records = p.records[1:]
+	}
+	return last
+}
+
+// forget drops the records of partitions the consumer group took away. Their
+// new owner reads them again from the last commit.
+func (t *commitTracker) forget(revoked map[string][]int32) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for topic, partitions := range revoked {
+		for _, partition := range partitions {
+			delete(t.partitions[topic], partition)
+		}
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/twmb/franz-go/pkg/kgo"
 	"github.com/twmb/franz-go/pkg/sasl/kerberos"
@@ -41,24 +42,62 @@ type Consumer struct {
 	mu               sync.RWMutex
 	highWaterMarks   map[string]map[int32]int64
 	lastOffsets      map[string]map[int32]int64
+	onRevoked        func(revoked map[string][]int32)
 }
 
 func NewConsumer(cfg config.ClusterConfig, groupID string, replicationCfg config.ReplicationConfig, jobID string, topics ...string) (*Consumer, error) {
 	logger.Info("Creating new Kafka consumer: provider=%s, brokers=%s, group=%s, topics=%v, job=%s, component=%s",
 		cfg.Provider, cfg.Brokers, groupID, topics, jobID, "consumer")
 
+	var consumer atomic.Pointer[Consumer]
 	opts := []kgo.Opt{
-		kgo.SeedBrokers(strings.Split(cfg.Brokers, ",")...),
 		kgo.ConsumerGroup(groupID),
 		kgo.ConsumeTopics(topics...),
 		kgo.FetchMaxBytes(int32(replicationCfg.BatchSize * 1024)),
+		kgo.ConsumeResetOffset(resetOffset(replicationCfg.StartPosition, time.Now())),
+		// Only records marked with MarkRecords are committed, so records still being
+		// mirrored are read again after a restart.
+		kgo.AutoCommitMarks(),
 		kgo.OnPartitionsAssigned(func(ctx context.Context, c *kgo.Client, assigned map[string][]int32) {
 			logger.Info("Consumer partitions assigned: %v, job=%s, component=%s", assigned, jobID, "consumer")
 		}),
 		kgo.OnPartitionsRevoked(func(ctx context.Context, c *kgo.Client, revoked map[string][]int32) {
 			logger.Info("Consumer partitions revoked: %v, job=%s, component=%s", revoked, jobID, "consumer")
+			consumer.Load().partitionsRevoked(revoked)
 		}),
 	}
+	c, err := newConsumer(cfg, replicationCfg, jobID, opts)
+	if err != nil {
+		return nil, err
+	}
+	consumer.Store(c)
+	return c, nil
+}
+
+// NewPartitionConsumer creates a consumer without a consumer group that reads the
+// given source partitions from the given offsets, by topic and partition.
+func NewPartitionConsumer(cfg config.ClusterConfig, replicationCfg config.ReplicationConfig, jobID string, offsets map[string]map[int32]int64) (*Consumer, error) {
+	logger.Info("Creating new Kafka partition consumer: provider=%s, brokers=%s, offsets=%v, job=%s, component=%s",
+		cfg.Provider, cfg.Brokers, offsets, jobID, "consumer")
+
+	partitions := make(map[string]map[int32]kgo.Offset, len(offsets))
+	for topic, parts := range offsets {
+		partitions[topic] = make(map[int32]kgo.Offset, len(parts))
+		for partition, offset := range parts {
+			partitions[topic][partition] = kgo.NewOffset().At(offset)
+		}
+	}
+	opts := []kgo.Opt{
+		kgo.ConsumePartitions(partitions),
+		kgo.FetchMaxBytes(int32(replicationCfg.BatchSize * 1024)),
+	}
+	return newConsumer(cfg, replicationCfg, jobID, opts)
+}
+
+// newConsumer adds the connection and security options of the cluster to opts and
+// creates the consumer client.
+func newConsumer(cfg config.ClusterConfig, replicationCfg config.ReplicationConfig, jobID string, opts []kgo.Opt) (*Consumer, error) {
+	opts = append(opts, kgo.SeedBrokers(strings.Split(cfg.Brokers, ",")...))
 
 	logger.Debug("Consumer configuration: batch_size=%dKB, parallelism=%d, job=%s, component=%s",
 		replicationCfg.BatchSize, replicationCfg.Parallelism, jobID, "consumer")
@@ -223,9 +262,29 @@ func (c *Consumer) Consume(ctx context.Context, handler func(*kgo.Record)) {
 
 func (c *Consumer) GetMetrics() ConsumerMetrics {
 	var calculatedLag int64
+	for _, parts := range c.PartitionLags() {
+		for _, lag := range parts {
+			calculatedLag += lag
+		}
+	}
+
+	return ConsumerMetrics{
+		RecordsProcessed: atomic.LoadInt64(&c.recordsProcessed),
+		BytesProcessed:   atomic.LoadInt64(&c.bytesProcessed),
+		ConsumerLag:      calculatedLag,
+	}
+}
 
+// PartitionLags returns the offset lag of every assigned partition, keyed by
+// topic and partition. Partitions that have not been consumed yet report the
+// full high watermark.
+func (c *Consumer) PartitionLags() map[string]map[int32]int64 {
 	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	lags := make(map[string]map[int32]int64, len(c.highWaterMarks))
 	for topic, parts := range c.highWaterMarks {
+		lags[topic] = make(map[int32]int64, len(parts))
 		for p, high := range parts {
 			lastOffset := int64(-1)
 			if c.lastOffsets != nil {
@@ -239,16 +298,10 @@ func (c *Consumer) GetMetrics() ConsumerMetrics {
 			if lag < 0 {
 				lag = 0
 			}
-			calculatedLag += lag
+			lags[topic][p] = lag
 		}
 	}
-	c.mu.RUnlock()
-
-	return ConsumerMetrics{
-		RecordsProcessed: atomic.LoadInt64(&c.recordsProcessed),
-		BytesProcessed:   atomic.LoadInt64(&c.bytesProcessed),
-		ConsumerLag:      calculatedLag,
-	}
+	return lags
 }
 
 func (c *Consumer) Close() {
@@ -264,6 +317,59 @@ func (c *Consumer) AddTopics(topics ...string) {
 	c.Client.AddConsumeTopics(topics...)
 }
 
+// RemoveTopics stops consuming from the given topics.
+func (c *Consumer) RemoveTopics(topics ...string) {
+	if len(topics) == 0 {
+		return
+	}
+	c.Client.PurgeTopicsFromConsuming(topics...)
+}
+
+// RemovePartitions stops consuming the given partitions, by topic.
+func (c *Consumer) RemovePartitions(partitions map[string][]int32) {
+	if len(partitions) == 0 {
+		return
+	}
+	c.Client.RemoveConsumePartitions(partitions)
+}
+
+// PausePartition stops fetching a partition until ResumePartition is called.
+// Records already fetched are still returned.
+func (c *Consumer) PausePartition(topic string, partition int32) {
+	c.Client.PauseFetchPartitions(map[string][]int32{topic: {partition}})
+}
+
+// ResumePartition fetches a paused partition again.
+func (c *Consumer) ResumePartition(topic string, partition int32) {
+	c.Client.ResumeFetchPartitions(map[string][]int32{topic: {partition}})
+}
+
+// MarkRecords marks records for the next autocommit of the consumer group. A
+// consumer without a group ignores it.
+func (c *Consumer) MarkRecords(records ...*kgo.Record) {
+	c.Client.MarkCommitRecords(records...)
+}
+
+// OnPartitionsRevoked sets a func called with the partitions the consumer group
+// takes away, or that are lost.
+func (c *Consumer) OnPartitionsRevoked(fn func(revoked map[string][]int32)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onRevoked = fn
+}
+
+func (c *Consumer) partitionsRevoked(revoked map[string][]int32) {
+	if c == nil {
+		return
+	}
+	c.mu.RLock()
+	fn := c.onRevoked
+	c.mu.RUnlock()
+	if fn != nil {
+		fn(revoked)
+	}
+}
+
 // NewConsumerForTest creates a Consumer with preset offsets for unit tests.
 func NewConsumerForTest(highWaterMarks map[string]map[int32]int64, lastOffsets map[string]map[int32]int64) *Consumer {
 	return &Consumer{
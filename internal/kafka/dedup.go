@@ -0,0 +1,196 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// ProvenanceHeader is set on every mirrored record when dedup is enabled. Its value
+// is the source topic, partition and offset of the record as "topic/partition/offset".
+const ProvenanceHeader = "kaf-mirror-source"
+
+// defaultDedupTailRecords is how many records of each target partition are read
+// when the configuration does not say.
+const defaultDedupTailRecords = 1000
+
+// dedupFilter skips source records the target already holds, as found in the tail
+// of the target partitions when the job started. A restarted consumer re-reads a
+// contiguous run of records from its last committed offset, so a partition is no
+// longer checked after its first record that is not a duplicate. skip is called
+// from the consumer goroutine and from the drains of partitions held for a retry.
+type dedupFilter struct {
+	hasher recordHasher
+	mu     sync.Mutex
+	// copied are the source offsets named by provenance headers, by source topic
+	// and partition.
+	copied map[string]map[int32]map[int64]bool
+	// hashes count the target records without provenance by target topic and
+	// content, for records written before dedup was enabled.
+	hashes  map[string]map[uint64]int
+	done    map[string]map[int32]bool
+	skipped atomic.Int64
+}
+
+func newDedupFilter(cfg *config.Config) *dedupFilter {
+	return &dedupFilter{
+		hasher: newRecordHasher(cfg),
+		copied: make(map[string]map[int32]map[int64]bool),
+		hashes: make(map[string]map[uint64]int),
+		done:   make(map[string]map[int32]bool),
+	}
+}
+
+// loadDedupFilter reads the last records of every partition of the target topics
+// and indexes what they show was already copied.
+func loadDedupFilter(ctx context.Context, cfg *config.Config, targetTopics []string) (*dedupFilter, error) {
+	tail := int64(cfg.Replication.Dedup.TailRecords)
+	if tail <= 0 {
+		tail = defaultDedupTailRecords
+	}
+	filter := newDedupFilter(cfg)
+	if len(targetTopics) == 0 {
+		return filter, nil
+	}
+
+	admin, err := adminClientFactory(cfg.Clusters["target"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create target admin client: %w", err)
+	}
+	defer admin.Close()
+	offsets, err := admin.GetTopicHighWaterMarks(ctx, targetTopics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target offsets: %w", err)
+	}
+	reader, err := recordReaderFactory(cfg.Clusters["target"], cfg.Replication, cfg.Replication.JobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create target reader: %w", err)
+	}
+	defer reader.Close()
+
+	for _, topic := range targetTopics {
+		for _, partition := range offsets[topic] {
+			start := max(partition.LogStartOffset, partition.HighWaterMark-tail)
+			records, err := reader.ReadRange(ctx, topic, partition.Partition, start, partition.HighWaterMark)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the tail of %s partition %d: %w", topic, partition.Partition, err)
+			}
+			for _, record := range records {
+				filter.index(topic, record)
+			}
+		}
+	}
+	return filter, nil
+}
+
+// index records a target record as copied.
+func (f *dedupFilter) index(targetTopic string, record *kgo.Record) {
+	for _, header := range record.Headers {
+		if header.Key != ProvenanceHeader {
+			continue
+		}
+		topic, partition, offset, ok := parseProvenance(string(header.Value))
+		if !ok {
+			break
+		}
+		if f.copied[topic] == nil {
+			f.copied[topic] = make(map[int32]map[int64]bool)
+		}
+		if f.copied[topic][partition] == nil {
+			f.copied[topic][partition] = make(map[int64]bool)
+		}
+		f.copied[topic][partition][offset] = true
+		return
+	}
+	if f.hashes[targetTopic] == nil {
+		f.hashes[targetTopic] = make(map[uint64]int)
+	}
+	f.hashes[targetTopic][f.hasher.hash(record)]++
+}
+
+// skip reports whether record is already on the target and counts it if so.
+func (f *dedupFilter) skip(record *kgo.Record, targetTopic string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.done[record.Topic][record.Partition] {
+		return false
+	}
+	if f.copied[record.Topic][record.Partition][record.Offset] {
+		f.skipped.Add(1)
+		return true
+	}
+	if counts := f.hashes[targetTopic]; len(counts) > 0 {
+		hash := f.hasher.hash(record)
+		if counts[hash] > 0 {
+			counts[hash]--
+			f.skipped.Add(1)
+			return true
+		}
+	}
+
+	if f.done[record.Topic] == nil {
+		f.done[record.Topic] = make(map[int32]bool)
+	}
+	f.done[record.Topic][record.Partition] = true
+	delete(f.copied[record.Topic], record.Partition)
+	return false
+}
+
+// withProvenance returns the headers of record with the provenance header set.
+// A provenance header of an upstream mirror is replaced.
+func withProvenance(record *kgo.Record) []kgo.RecordHeader {
+	headers := make([]kgo.RecordHeader, 0, len(record.Headers)+1)
+	for _, header := range record.Headers {
+		if header.Key != ProvenanceHeader {
+			headers = append(headers, header)
+		}
+	}
+	value := record.Topic + "/" + strconv.FormatInt(int64(record.Partition), 10) + "/" + strconv.FormatInt(record.Offset, 10)
+	return append(headers, kgo.RecordHeader{Key: ProvenanceHeader, Value: []byte(value)})
+}
+
+// parseProvenance splits a provenance header value. Topic names cannot contain a
+// slash, so the value splits into exactly three parts.
+func parseProvenance(value string) (string, int32, int64, bool) {
+	parts := strings.Split(value, "/")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", 0, 0, false
+	}
+	partition, err := strconv.ParseInt(parts[1], 10, 32)
+	if err != nil {
+		return "", 0, 0, false
+	}
+	offset, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return "", 0, 0, false
+	}
+	return parts[0], int32(partition), offset, true
+}
+
+// dedupTargetTopics returns the target topics the job writes to, sorted.
+func dedupTargetTopics(targetPartitions map[string]int32) []string {
+	topics := make([]string, 0, len(targetPartitions))
+	for topic := range targetPartitions {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
@@ -0,0 +1,248 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/pkg/logger"
+	"time"
+)
+
+// Topic change types reported by topic discovery.
+const (
+	TopicChangeDiscovered              = "topic_discovered"
+	TopicChangePartitionsIncreased     = "topic_partitions_increased"
+	TopicChangeSourceDeleted           = "topic_source_deleted"
+	TopicChangeMirroringStopped        = "topic_mirroring_stopped"
+	TopicChangeMirroringResumed        = "topic_mirroring_resumed"
+	TopicChangeTargetDeletionScheduled = "topic_target_deletion_scheduled"
+	TopicChangeTargetDeletionCancelled = "topic_target_deletion_cancelled"
+	TopicChangeTargetDeleted           = "topic_target_deleted"
+)
+
+// TopicChange is a change to a mapped topic made or detected by topic discovery.
+type TopicChange struct {
+	Type        string
+	SourceTopic string
+	TargetTopic string
+	Details     string
+}
+
+// TopicChangeNotifier is implemented by mirrors that report the topic changes
+// found by discovery. The handler must be set before Start.
+type TopicChangeNotifier interface {
+	OnTopicChange(handler func(jobID string, change TopicChange))
+}
+
+// maxBufferedTopicChanges bounds the changes kept until a handler is set.
+const maxBufferedTopicChanges = 100
+
+// pendingTopicDeletion is a target topic whose source topic was deleted.
+type pendingTopicDeletion struct {
+	sourceTopic string
+	deleteAt    time.Time
+}
+
+// OnTopicChange sets the handler that receives the topic changes of this job.
+// Changes found while the mirror was created are passed to it right away.
+func (r *KafMirrorImpl) OnTopicChange(handler func(jobID string, change TopicChange)) {
+	r.onTopicChange = handler
+	buffered := r.bufferedChanges
+	r.bufferedChanges = nil
+	for _, change := range buffered {
+		handler(r.jobID, change)
+	}
+}
+
+func (r *KafMirrorImpl) notifyTopicChange(changeType, sourceTopic, targetTopic, format string, args ...interface{}) {
+	change := TopicChange{
+		Type:        changeType,
+		SourceTopic: sourceTopic,
+		TargetTopic: targetTopic,
+		Details:     fmt.Sprintf(format, args...),
+	}
+	r.log("discovery", logger.Topic(sourceTopic)).Info("%s", change.Details)
+	if r.onTopicChange != nil {
+		r.onTopicChange(r.jobID, change)
+	} else if len(r.bufferedChanges) < maxBufferedTopicChanges {
+		r.bufferedChanges = append(r.bufferedChanges, change)
+	}
+}
+
+// deletionPolicy returns the configured source topic deletion policy.
+func deletionPolicy(cfg *config.Config) (string, time.Duration) {
+	policy := cfg.Replication.SourceTopicDeletion.Policy
+	if policy == "" {
+		policy = config.SourceTopicDeletionStop
+	}
+	grace, err := time.ParseDuration(cfg.Replication.SourceTopicDeletion.GracePeriod)
+	if err != nil || grace < 0 {
+		grace = 24 * time.Hour
+	}
+	return policy, grace
+}
+
+func (r *KafMirrorImpl) snapshotTopicMap() map[string]string {
+	r.mapMu.RLock()
+	defer r.mapMu.RUnlock()
+	return r.snapshotTopicMapLocked()
+}
+
+func (r *KafMirrorImpl) snapshotTopicMapLocked() map[string]string {
+	topicMap := make(map[string]string, len(r.topicMap))
+	for sourceTopic, targetTopic := range r.topicMap {
+		topicMap[sourceTopic] = targetTopic
+	}
+	return topicMap
+}
+
+// resumeExactMappings mirrors exact mappings again whose source topic was deleted
+// and has been recreated since.
+func (r *KafMirrorImpl) resumeExactMappings(ctx context.Context, sourceAdmin, targetAdmin AdminClientAPI, sourceInfo, targetInfo *ClusterInfo) {
+	topicMap := r.snapshotTopicMap()
+	for _, m := range r.mappings {
+		if !m.Enabled || isRegex(m.Source) {
+			continue
+		}
+		if _, mapped := topicMap[m.Source]; mapped {
+			continue
+		}
+		sourceTopicInfo, exists := sourceInfo.Topics[m.Source]
+		if !exists {
+			continue
+		}
+		targetTopic := m.Target
+		if targetTopic == "" {
+			targetTopic = m.Source
+		}
+
+		replicationFactor := clampReplicationFactor(sourceTopicInfo.ReplicationFactor, targetInfo.BrokerCount, m.Source)
+		createConfigs := topicCreateConfigs(ctx, sourceAdmin, m.Source, r.topicConfigs, replicationFactor)
+		if err := ensureTopicWithRetry(ctx, targetAdmin, targetTopic, sourceTopicInfo.Partitions, replicationFactor, createConfigs); err != nil {
+			r.log("discovery", logger.Topic(m.Source)).Error("Failed to ensure target topic %s for recreated source topic %s: %v", targetTopic, m.Source, err)
+			continue
+		}
+
+		r.mapMu.Lock()
+		r.topicMap[m.Source] = targetTopic
+		if r.targetPartitions == nil {
+			r.targetPartitions = make(map[string]int32)
+		}
+		r.targetPartitions[targetTopic] = sourceTopicInfo.Partitions
+		r.mapMu.Unlock()
+		delete(r.deletedSources, m.Source)
+
+		r.Consumer.AddTopics(m.Source)
+		r.notifyTopicChange(TopicChangeMirroringResumed, m.Source, targetTopic,
+			"Source topic %s was recreated, mirroring to %s resumed", m.Source, targetTopic)
+	}
+}
+
+// followSourceDeletions applies the deletion policy to mappings whose source topic
+// no longer exists.
+func (r *KafMirrorImpl) followSourceDeletions(sourceInfo *ClusterInfo) {
+	for sourceTopic, targetTopic := range r.snapshotTopicMap() {
+		if _, exists := sourceInfo.Topics[sourceTopic]; exists {
+			delete(r.deletedSources, sourceTopic)
+			continue
+		}
+		if r.deletedSources[sourceTopic] {
+			continue
+		}
+		r.deletedSources[sourceTopic] = true
+
+		if r.deletionPolicy == config.SourceTopicDeletionIgnore {
+			r.notifyTopicChange(TopicChangeSourceDeleted, sourceTopic, targetTopic,
+				"Source topic %s was deleted, keeping mapping to %s (policy ignore)", sourceTopic, targetTopic)
+			continue
+		}
+		r.stopMirroring(sourceTopic, targetTopic)
+	}
+}
+
+// stopMirroring removes a mapping whose source topic was deleted and, with the
+// delete_target policy, schedules the deletion of its target topic.
+func (r *KafMirrorImpl) stopMirroring(sourceTopic, targetTopic string) {
+	r.mapMu.Lock()
+	delete(r.topicMap, sourceTopic)
+	delete(r.targetPartitions, targetTopic)
+	r.mapMu.Unlock()
+
+	if r.Consumer != nil {
+		r.Consumer.RemoveTopics(sourceTopic)
+	}
+	r.notifyTopicChange(TopicChangeMirroringStopped, sourceTopic, targetTopic,
+		"Source topic %s was deleted, stopped mirroring to %s", sourceTopic, targetTopic)
+
+	if r.deletionPolicy == config.SourceTopicDeletionDeleteTarget {
+		deleteAt := time.Now().Add(r.deletionGrace)
+		r.pendingDeletions[targetTopic] = pendingTopicDeletion{sourceTopic: sourceTopic, deleteAt: deleteAt}
+		r.notifyTopicChange(TopicChangeTargetDeletionScheduled, sourceTopic, targetTopic,
+			"Target topic %s will be deleted at %s unless source topic %s is recreated", targetTopic, deleteAt.UTC().Format(time.RFC3339), sourceTopic)
+	}
+}
+
+// followPartitionIncreases adds partitions to target topics whose source topic grew
+// and keeps the partition counts used for record routing current.
+func (r *KafMirrorImpl) followPartitionIncreases(ctx context.Context, targetAdmin AdminClientAPI, sourceInfo, targetInfo *ClusterInfo) {
+	for sourceTopic, targetTopic := range r.snapshotTopicMap() {
+		sourceTopicInfo, sourceExists := sourceInfo.Topics[sourceTopic]
+		targetTopicInfo, targetExists := targetInfo.Topics[targetTopic]
+		if !sourceExists || !targetExists {
+			continue
+		}
+
+		partitions := targetTopicInfo.Partitions
+		if sourceTopicInfo.Partitions > targetTopicInfo.Partitions {
+			if err := targetAdmin.CreatePartitions(ctx, targetTopic, sourceTopicInfo.Partitions); err != nil {
+				r.log("discovery", logger.Topic(targetTopic)).Error("Failed to grow target topic %s to %d partitions: %v", targetTopic, sourceTopicInfo.Partitions, err)
+			} else {
+				partitions = sourceTopicInfo.Partitions
+				r.notifyTopicChange(TopicChangePartitionsIncreased, sourceTopic, targetTopic,
+					"Source topic %s grew to %d partitions, target topic %s grown from %d", sourceTopic, sourceTopicInfo.Partitions, targetTopic, targetTopicInfo.Partitions)
+			}
+		}
+
+		r.mapMu.Lock()
+		if r.targetPartitions == nil {
+			r.targetPartitions = make(map[string]int32)
+		}
+		r.targetPartitions[targetTopic] = partitions
+		r.mapMu.Unlock()
+	}
+}
+
+// deleteExpiredTargets deletes target topics whose grace period has passed and
+// cancels pending deletions whose source topic was recreated.
+func (r *KafMirrorImpl) deleteExpiredTargets(ctx context.Context, targetAdmin AdminClientAPI, sourceInfo *ClusterInfo) {
+	now := time.Now()
+	for targetTopic, pending := range r.pendingDeletions {
+		if _, exists := sourceInfo.Topics[pending.sourceTopic]; exists {
+			delete(r.pendingDeletions, targetTopic)
+			r.notifyTopicChange(TopicChangeTargetDeletionCancelled, pending.sourceTopic, targetTopic,
+				"Source topic %s was recreated, target topic %s is kept", pending.sourceTopic, targetTopic)
+			continue
+		}
+		if now.Before(pending.deleteAt) {
+			continue
+		}
+		if err := targetAdmin.DeleteTopic(ctx, targetTopic); err != nil {
+			r.log("discovery", logger.Topic(targetTopic)).Error("Failed to delete target topic %s: %v", targetTopic, err)
+			continue
+		}
+		delete(r.pendingDeletions, targetTopic)
+		r.notifyTopicChange(TopicChangeTargetDeleted, pending.sourceTopic, targetTopic,
+			"Deleted target topic %s after its source topic %s was deleted", targetTopic, pending.sourceTopic)
+	}
+}
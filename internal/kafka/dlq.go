@@ -0,0 +1,345 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Headers a dead-lettered record carries next to the headers of the record itself.
+const (
+	deadLetterHeaderPrefix          = "kaf-mirror-dlq-"
+	DeadLetterHeaderJobID           = deadLetterHeaderPrefix + "job-id"
+	DeadLetterHeaderSourceTopic     = deadLetterHeaderPrefix + "source-topic"
+	DeadLetterHeaderSourcePartition = deadLetterHeaderPrefix + "source-partition"
+	DeadLetterHeaderSourceOffset    = deadLetterHeaderPrefix + "source-offset"
+	DeadLetterHeaderTargetTopic     = deadLetterHeaderPrefix + "target-topic"
+	DeadLetterHeaderErrorClass      = deadLetterHeaderPrefix + "error-class"
+	DeadLetterHeaderError           = deadLetterHeaderPrefix + "error"
+	DeadLetterHeaderAttempts        = deadLetterHeaderPrefix + "attempts"
+	DeadLetterHeaderFailedAt        = deadLetterHeaderPrefix + "failed-at"
+)
+
+const (
+	// defaultDeadLetterListLimit is how many records a listing returns unless asked.
+	defaultDeadLetterListLimit = 100
+	// defaultDeadLetterReplayLimit is how many records a replay re-drives unless asked.
+	defaultDeadLetterReplayLimit = 1000
+)
+
+// DeadLetterReplayGroup returns the consumer group that tracks how far the
+// dead-letter topic of a job was replayed.
+func DeadLetterReplayGroup(jobID string) string {
+	return "kaf-mirror-dlq-replay-" + jobID
+}
+
+// DeadLetterRecord is a record of a dead-letter topic with its error metadata.
+type DeadLetterRecord struct {
+	Partition       int32     `json:"partition"`
+	Offset          int64     `json:"offset"`
+	SourceTopic     string    `json:"source_topic"`
+	SourcePartition int32     `json:"source_partition"`
+	SourceOffset    int64     `json:"source_offset"`
+	TargetTopic     string    `json:"target_topic"`
+	ErrorClass      string    `json:"error_class"`
+	Error           string    `json:"error"`
+	Attempts        int       `json:"attempts"`
+	FailedAt        time.Time `json:"failed_at"`
+	Timestamp       time.Time `json:"timestamp"`
+	Key             []byte    `json:"key"`
+	Value           []byte    `json:"value"`
+	// Replayed is set once a replay re-drove the record.
+	Replayed bool `json:"replayed"`
+
+	headers []kgo.RecordHeader
+}
+
+// DeadLetterList is a page of the newest records of a job's dead-letter topic.
+type DeadLetterList struct {
+	Cluster string             `json:"cluster"`
+	Topic   string             `json:"topic"`
+	Total   int64              `json:"total"`
+	Pending int64              `json:"pending"`
+	Records []DeadLetterRecord `json:"records"`
+}
+
+// DeadLetterReplay is the result of re-driving dead-lettered records to their target topics.
+type DeadLetterReplay struct {
+	Topic    string   `json:"topic"`
+	Replayed int      `json:"replayed"`
+	Failed   int      `json:"failed"`
+	Pending  int64    `json:"pending"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// newDeadLetterRecord wraps the record the target rejected with the error metadata.
+// Key, value, headers and timestamp are kept as they were sent to the target.
+func newDeadLetterRecord(jobID, topic string, source, rec *kgo.Record, class string, cause error, attempts int, failedAt time.Time) *kgo.Record {
+	headers := make([]kgo.RecordHeader, 0, len(rec.Headers)+9)
+	headers = append(headers, rec.Headers...)
+	headers = append(headers,
+		kgo.RecordHeader{Key: DeadLetterHeaderJobID, Value: []byte(jobID)},
+		kgo.RecordHeader{Key: DeadLetterHeaderSourceTopic, Value: []byte(source.Topic)},
+		kgo.RecordHeader{Key: DeadLetterHeaderSourcePartition, Value: []byte(strconv.FormatInt(int64(source.Partition), 10))},
+		kgo.RecordHeader{Key: DeadLetterHeaderSourceOffset, Value: []byte(strconv.FormatInt(source.Offset, 10))},
+		kgo.RecordHeader{Key: DeadLetterHeaderTargetTopic, Value: []byte(rec.Topic)},
+		kgo.RecordHeader{Key: DeadLetterHeaderErrorClass, Value: []byte(class)},
+		kgo.RecordHeader{Key: DeadLetterHeaderError, Value: []byte(cause.Error())},
+		kgo.RecordHeader{Key: DeadLetterHeaderAttempts, Value: []byte(strconv.Itoa(attempts))},
+		kgo.RecordHeader{Key: DeadLetterHeaderFailedAt, Value: []byte(failedAt.UTC().Format(time.RFC3339Nano))},
+	)
+	return &kgo.Record{
+		Topic:     topic,
+		Key:       rec.Key,
+		Value:     rec.Value,
+		Headers:   headers,
+		Timestamp: rec.Timestamp,
+	}
+}
+
+// parseDeadLetter splits a dead-letter topic record into its metadata and the
+// record that was rejected.
+func parseDeadLetter(record *kgo.Record) DeadLetterRecord {
+	d := DeadLetterRecord{
+		Partition: record.Partition,
+		Offset:    record.Offset,
+		Timestamp: record.Timestamp,
+		Key:       record.Key,
+		Value:     record.Value,
+	}
+	for _, header := range record.Headers {
+		value := string(header.Value)
+		switch header.Key {
+		case DeadLetterHeaderSourceTopic:
+			d.SourceTopic = value
+		case DeadLetterHeaderSourcePartition:
+			partition, _ := strconv.ParseInt(value, 10, 32)
+			d.SourcePartition = int32(partition)
+		case DeadLetterHeaderSourceOffset:
+			d.SourceOffset, _ = strconv.ParseInt(value, 10, 64)
+		case DeadLetterHeaderTargetTopic:
+			d.TargetTopic = value
+		case DeadLetterHeaderErrorClass:
+			d.ErrorClass = value
+		case DeadLetterHeaderError:
+			d.Error = value
+		case DeadLetterHeaderAttempts:
+			d.Attempts, _ = strconv.Atoi(value)
+		case DeadLetterHeaderFailedAt:
+			d.FailedAt, _ = time.Parse(time.RFC3339Nano, value)
+		default:
+			if !strings.HasPrefix(header.Key, deadLetterHeaderPrefix) {
+				d.headers = append(d.headers, header)
+			}
+		}
+	}
+	return d
+}
+
+// deadLetterTopic opens an admin client for the cluster of the job's dead-letter
+// topic and returns it with the topic and the replay offsets by partition.
+func deadLetterTopic(ctx context.Context, cfg *config.Config) (AdminClientAPI, string, []OffsetInfo, map[int32]int64, error) {
+	policy := cfg.Replication.ErrorPolicy
+	topic := policy.DeadLetterTopic(cfg.Replication.JobID)
+	admin, err := adminClientFactory(cfg.Clusters[policy.DeadLetterCluster()])
+	if err != nil {
+		return nil, "", nil, nil, fmt.Errorf("failed to create %s admin client: %w", policy.DeadLetterCluster(), err)
+	}
+	offsets, err := admin.GetTopicHighWaterMarks(ctx, []string{topic})
+	if err != nil {
+		admin.Close()
+		return nil, "", nil, nil, fmt.Errorf("failed to get offsets of dead-letter topic %s: %w", topic, err)
+	}
+	committed, err := admin.GetConsumerGroupOffsets(ctx, DeadLetterReplayGroup(cfg.Replication.JobID), []string{topic})
+	if err != nil {
+		admin.Close()
+		return nil, "", nil, nil, fmt.Errorf("failed to get replay offsets: %w", err)
+	}
+	replayed := make(map[int32]int64)
+	for _, offset := range committed[topic] {
+		if offset.Offset >= 0 {
+			replayed[offset.Partition] = offset.Offset
+		}
+	}
+	return admin, topic, offsets[topic], replayed, nil
+}
+
+// ReadDeadLetters returns the newest records of the job's dead-letter topic,
+// newest failure first, at most limit of them.
+func ReadDeadLetters(ctx context.Context, cfg *config.Config, limit int) (*DeadLetterList, error) {
+	if limit <= 0 {
+		limit = defaultDeadLetterListLimit
+	}
+	admin, topic, partitions, replayed, err := deadLetterTopic(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer admin.Close()
+
+	cluster := cfg.Replication.ErrorPolicy.DeadLetterCluster()
+	list := &DeadLetterList{Cluster: cluster, Topic: topic, Records: []DeadLetterRecord{}}
+	if len(partitions) == 0 {
+		return list, nil
+	}
+	reader, err := recordReaderFactory(cfg.Clusters[cluster], cfg.Replication, cfg.Replication.JobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dead-letter reader: %w", err)
+	}
+	defer reader.Close()
+
+	for _, partition := range partitions {
+		list.Total += partition.HighWaterMark - partition.LogStartOffset
+		list.Pending += partition.HighWaterMark - max(partition.LogStartOffset, replayed[partition.Partition])
+		start := max(partition.LogStartOffset, partition.HighWaterMark-int64(limit))
+		records, err := reader.ReadRange(ctx, topic, partition.Partition, start, partition.HighWaterMark)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dead-letter topic %s partition %d: %w", topic, partition.Partition, err)
+		}
+		for _, record := range records {
+			d := parseDeadLetter(record)
+			d.Replayed = record.Offset < replayed[record.Partition]
+			list.Records = append(list.Records, d)
+		}
+	}
+	sort.Slice(list.Records, func(i, j int) bool {
+		a, b := list.Records[i], list.Records[j]
+		if !a.FailedAt.Equal(b.FailedAt) {
+			return a.FailedAt.After(b.FailedAt)
+		}
+		if a.Partition != b.Partition {
+			return a.Partition < b.Partition
+		}
+		return a.Offset > b.Offset
+	})
+	if len(list.Records) > limit {
+		list.Records = list.Records[:limit]
+	}
+	return list, nil
+}
+
+// ReplayDeadLetters produces records of the job's dead-letter topic to the target
+// topics they were rejected by, without the error metadata. Each replay picks up
+// where the last one stopped, as tracked by the replay consumer group, and re-drives
+// at most limit records. A partition stops at its first record that fails again.
+func ReplayDeadLetters(ctx context.Context, cfg *config.Config, limit int) (*DeadLetterReplay, error) {
+	if limit <= 0 {
+		limit = defaultDeadLetterReplayLimit
+	}
+	admin, topic, partitions, replayed, err := deadLetterTopic(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer admin.Close()
+
+	result := &DeadLetterReplay{Topic: topic}
+	if len(partitions) == 0 {
+		return result, nil
+	}
+	cluster := cfg.Replication.ErrorPolicy.DeadLetterCluster()
+	reader, err := recordReaderFactory(cfg.Clusters[cluster], cfg.Replication, cfg.Replication.JobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dead-letter reader: %w", err)
+	}
+	defer reader.Close()
+	producer, err := producerFactory(cfg.Clusters["target"], cfg.Replication, cfg.Replication.JobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create target producer: %w", err)
+	}
+	defer producer.Close()
+	// Records dead-lettered for a schema error still carry source schema IDs.
+	schemas := NewSchemaReplicator(cfg.Clusters["source"].SchemaRegistry, cfg.Clusters["target"].SchemaRegistry)
+
+	commits := make(map[int32]int64)
+	for _, partition := range partitions {
+		start := max(partition.LogStartOffset, replayed[partition.Partition])
+		end := min(partition.HighWaterMark, start+int64(limit-result.Replayed))
+		if start >= end {
+			result.Pending += max(0, partition.HighWaterMark-start)
+			continue
+		}
+		records, err := reader.ReadRange(ctx, topic, partition.Partition, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dead-letter topic %s partition %d: %w", topic, partition.Partition, err)
+		}
+
+		next := end
+		for _, record := range records {
+			d := parseDeadLetter(record)
+			if d.TargetTopic == "" {
+				continue // not written by the mirror
+			}
+			replay := &kgo.Record{Topic: d.TargetTopic, Key: d.Key, Value: d.Value, Headers: d.headers, Timestamp: d.Timestamp}
+			err := remapDeadLetter(ctx, schemas, d, replay)
+			if err == nil {
+				err = produceAndWait(ctx, producer, replay)
+			}
+			if err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Sprintf("partition %d offset %d: %v", record.Partition, record.Offset, err))
+				next = record.Offset
+				break
+			}
+			result.Replayed++
+		}
+		if next > start {
+			commits[partition.Partition] = next
+		}
+		result.Pending += partition.HighWaterMark - next
+	}
+
+	if len(commits) > 0 {
+		if err := admin.CommitConsumerGroupOffsets(ctx, DeadLetterReplayGroup(cfg.Replication.JobID), map[string]map[int32]int64{topic: commits}); err != nil {
+			return result, fmt.Errorf("failed to commit replay offsets: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// remapDeadLetter remaps the schema IDs of a record dead-lettered because they
+// could not be remapped when it was mirrored.
+func remapDeadLetter(ctx context.Context, schemas *SchemaReplicator, d DeadLetterRecord, replay *kgo.Record) error {
+	if schemas == nil || d.ErrorClass != config.ErrorClassSchema {
+		return nil
+	}
+	key, err := schemas.Rewrite(ctx, d.SourceTopic, d.TargetTopic, true, d.Key)
+	if err != nil {
+		return fmt.Errorf("%w for key: %w", errSchemaRewrite, err)
+	}
+	value, err := schemas.Rewrite(ctx, d.SourceTopic, d.TargetTopic, false, d.Value)
+	if err != nil {
+		return fmt.Errorf("%w for value: %w", errSchemaRewrite, err)
+	}
+	replay.Key, replay.Value = key, value
+	return nil
+}
+
+// produceAndWait produces a record and waits for its acknowledgement.
+func produceAndWait(ctx context.Context, producer *Producer, record *kgo.Record) error {
+	result := make(chan error, 1)
+	producer.Produce(ctx, record, func(_ *kgo.Record, err error) {
+		result <- err
+	})
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
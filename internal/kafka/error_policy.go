@@ -0,0 +1,320 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/pkg/logger"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// maxRetryBackoff caps the doubling wait between retries of a record.
+const maxRetryBackoff = time.Minute
+
+var producerFactory = NewProducer
+
+// SetProducerFactoryForTest replaces the producer factory and returns a restore func.
+func SetProducerFactoryForTest(factory func(config.ClusterConfig, config.ReplicationConfig, string) (*Producer, error)) func() {
+	previous := producerFactory
+	producerFactory = factory
+	return func() {
+		producerFactory = previous
+	}
+}
+
+// ClassifyProduceError returns the error class of a produce error.
+func ClassifyProduceError(err error) string {
+	switch {
+	case errors.Is(err, errSchemaRewrite):
+		return config.ErrorClassSchema
+	case errors.Is(err, kerr.MessageTooLarge), errors.Is(err, kerr.RecordListTooLarge):
+		return config.ErrorClassRecordTooLarge
+	case errors.Is(err, kerr.TopicAuthorizationFailed), errors.Is(err, kerr.ClusterAuthorizationFailed),
+		errors.Is(err, kerr.SaslAuthenticationFailed):
+		return config.ErrorClassAuthorization
+	case errors.Is(err, kerr.UnknownTopicOrPartition), errors.Is(err, kerr.InvalidTopicException):
+		return config.ErrorClassUnknownTopic
+	case errors.Is(err, kerr.InvalidRecord), errors.Is(err, kerr.CorruptMessage), errors.Is(err, kerr.InvalidTimestamp):
+		return config.ErrorClassInvalidRecord
+	case errors.Is(err, kgo.ErrRecordTimeout), errors.Is(err, kerr.RequestTimedOut), errors.Is(err, context.DeadlineExceeded):
+		return config.ErrorClassTimeout
+	}
+	return config.ErrorClassOther
+}
+
+// produceOutcome is what finally happened to a mirrored record.
+type produceOutcome int
+
+const (
+	// outcomeProduced records reached the target.
+	outcomeProduced produceOutcome = iota
+	// outcomeDeadLettered records went to the dead-letter topic instead.
+	outcomeDeadLettered
+	// outcomeSkipped records were dropped by the error policy.
+	outcomeSkipped
+	// outcomeFailed records were not mirrored because the job halts or stops.
+	// They are not committed, so a restart reads them again.
+	outcomeFailed
+)
+
+// produceDone receives the final outcome of a mirrored record. err is the last
+// produce error.
+type produceDone func(rec *kgo.Record, err error, outcome produceOutcome)
+
+// produce writes out to the target and applies the job's error policy when the
+// target rejects it, or when the schema IDs of the record cannot be remapped.
+// attempt counts the earlier tries of the record.
+func (r *KafMirrorImpl) produce(source, out *kgo.Record, attempt int, done produceDone) {
+	key, value, err := r.rewriteSchemaIDs(source, out.Topic)
+	if err != nil {
+		r.handleProduceError(source, out, err, attempt, done)
+		return
+	}
+	out.Key, out.Value = key, value
+	r.Producer.Produce(context.Background(), out, func(rec *kgo.Record, err error) {
+		if err != nil {
+			r.handleProduceError(source, rec, err, attempt, done)
+			return
+		}
+		done(rec, nil, outcomeProduced)
+	})
+}
+
+func (r *KafMirrorImpl) handleProduceError(source, rec *kgo.Record, err error, attempt int, done produceDone) {
+	class := ClassifyProduceError(err)
+	r.countError(class)
+	if r.stopping() {
+		done(rec, err, outcomeFailed)
+		return
+	}
+	log := r.log("producer", logger.Topic(source.Topic), logger.Partition(source.Partition))
+
+	action := r.errorPolicy.ActionFor(class)
+	if action == config.ErrorActionRetry {
+		if attempt < r.errorPolicy.Retries() {
+			if attempt == 0 {
+				// Later records of the source partition wait for the outcome.
+				r.holdPartition(source.Topic, source.Partition)
+				settle := done
+				done = func(rec *kgo.Record, err error, outcome produceOutcome) {
+					settle(rec, err, outcome)
+					r.releasePartition(source.Topic, source.Partition)
+				}
+			}
+			backoff := min(r.errorPolicy.Backoff()<<attempt, maxRetryBackoff)
+			log.Warn("Retrying record at offset %d in %s after %s error: %v", source.Offset, backoff, class, err)
+			retry := &kgo.Record{
+				Topic:     rec.Topic,
+				Partition: rec.Partition,
+				Key:       rec.Key,
+				Value:     rec.Value,
+				Headers:   rec.Headers,
+				Timestamp: rec.Timestamp,
+			}
+			time.AfterFunc(backoff, func() { r.produce(source, retry, attempt+1, done) })
+			return
+		}
+		action = r.errorPolicy.ExhaustedAction()
+	}
+
+	switch action {
+	case config.ErrorActionDeadLetter:
+		r.deadLetter(source, rec, class, err, attempt+1, done)
+	case config.ErrorActionHalt:
+		done(rec, err, outcomeFailed)
+		r.halt(fmt.Sprintf("record at offset %d of %s partition %d could not be produced to %s (%s): %v",
+			source.Offset, source.Topic, source.Partition, rec.Topic, class, err))
+	default:
+		done(rec, err, outcomeSkipped)
+	}
+}
+
+// deadLetter writes a rejected record to the dead-letter topic. The job halts if
+// that fails too, as the record would be lost otherwise.
+func (r *KafMirrorImpl) deadLetter(source, rec *kgo.Record, class string, cause error, attempts int, done produceDone) {
+	if r.deadLetters == nil {
+		done(rec, cause, outcomeSkipped)
+		return
+	}
+	record := newDeadLetterRecord(r.jobID, r.deadLetterTopic, source, rec, class, cause, attempts, time.Now())
+	r.deadLetters.Produce(context.Background(), record, func(_ *kgo.Record, err error) {
+		if err != nil {
+			done(rec, cause, outcomeFailed)
+			r.halt(fmt.Sprintf("record at offset %d of %s partition %d could not be written to dead-letter topic %s: %v",
+				source.Offset, source.Topic, source.Partition, r.deadLetterTopic, err))
+			return
+		}
+		r.deadLettered.Add(1)
+		r.log("producer", logger.Topic(source.Topic), logger.Partition(source.Partition)).Warn("Wrote record at offset %d to dead-letter topic %s after %s error: %v",
+			source.Offset, r.deadLetterTopic, class, cause)
+		done(rec, cause, outcomeDeadLettered)
+	})
+}
+
+// halt fails the job once and stops consuming, so nothing is mirrored past the
+// failed record. The job manager is told asynchronously as it may be stopping
+// the job and waiting for this record's callback.
+func (r *KafMirrorImpl) halt(reason string) {
+	if r.stopping() || !r.halted.CompareAndSwap(false, true) {
+		return
+	}
+	r.log("producer").Error("Halting job: %s", reason)
+	if r.cancelFunc != nil {
+		r.cancelFunc()
+	}
+	if r.onPanic != nil {
+		go r.onPanic(r.jobID, reason)
+	}
+}
+
+// heldPartition is a source partition whose records wait while earlier records
+// of it are retried, so they reach the target in order.
+type heldPartition struct {
+	retrying int
+	records  []*kgo.Record
+	draining bool
+}
+
+// holdRecord queues record if its partition is held and reports whether it did.
+func (r *KafMirrorImpl) holdRecord(record *kgo.Record) bool {
+	r.heldMu.Lock()
+	defer r.heldMu.Unlock()
+	p := r.held[record.Topic][record.Partition]
+	if p == nil {
+		return false
+	}
+	p.records = append(p.records, record)
+	return true
+}
+
+// holdPartition holds a source partition until releasePartition was called once
+// for every call. Fetching it is paused meanwhile, so only records already
+// fetched are queued.
+func (r *KafMirrorImpl) holdPartition(topic string, partition int32) {
+	r.heldMu.Lock()
+	defer r.heldMu.Unlock()
+	if r.held == nil {
+		r.held = make(map[string]map[int32]*heldPartition)
+	}
+	if r.held[topic] == nil {
+		r.held[topic] = make(map[int32]*heldPartition)
+	}
+	p := r.held[topic][partition]
+	if p == nil {
+		p = &heldPartition{}
+		r.held[topic][partition] = p
+		if r.Consumer != nil {
+			r.Consumer.PausePartition(topic, partition)
+		}
+	}
+	p.retrying++
+}
+
+// releasePartition ends one hold of a source partition. The queued records are
+// mirrored in order once no record of the partition is retried any more.
+func (r *KafMirrorImpl) releasePartition(topic string, partition int32) {
+	r.heldMu.Lock()
+	defer r.heldMu.Unlock()
+	p := r.held[topic][partition]
+	if p == nil {
+		return
+	}
+	p.retrying--
+	if p.retrying > 0 || p.draining {
+		return
+	}
+	p.draining = true
+	// Not mirrored from the producer callback, which must not produce.
+	go r.drainPartition(topic, partition)
+}
+
+// drainPartition mirrors the queued records of a held partition. It stops when
+// one of them is retried in turn; the partition is resumed once it is empty.
+func (r *KafMirrorImpl) drainPartition(topic string, partition int32) {
+	for {
+		r.heldMu.Lock()
+		p := r.held[topic][partition]
+		if p.retrying > 0 {
+			p.draining = false
+			r.heldMu.Unlock()
+			return
+		}
+		if len(p.records) == 0 || (r.ctx != nil && r.ctx.Err() != nil) {
+			delete(r.held[topic], partition)
+			r.heldMu.Unlock()
+			if r.Consumer != nil {
+				r.Consumer.ResumePartition(topic, partition)
+			}
+			return
+		}
+		record := p.records[0]
+		p.records = p.records[1:]
+		r.heldMu.Unlock()
+		r.mirrorRecord(record)
+	}
+}
+
+// stopping reports whether the job is shutting down.
+func (r *KafMirrorImpl) stopping() bool {
+	return r.ctx != nil && r.ctx.Err() != nil && !r.halted.Load()
+}
+
+func (r *KafMirrorImpl) countError(class string) {
+	r.errorMu.Lock()
+	defer r.errorMu.Unlock()
+	if r.errorClasses == nil {
+		r.errorClasses = make(map[string]int)
+	}
+	r.errorClasses[class]++
+}
+
+// errorClassCounts returns a copy of the produce errors counted per class.
+func (r *KafMirrorImpl) errorClassCounts() map[string]int {
+	r.errorMu.Lock()
+	defer r.errorMu.Unlock()
+	if len(r.errorClasses) == 0 {
+		return nil
+	}
+	counts := make(map[string]int, len(r.errorClasses))
+	for class, count := range r.errorClasses {
+		counts[class] = count
+	}
+	return counts
+}
+
+// openDeadLetterProducer creates the dead-letter topic of the job unless it exists
+// and returns a producer for its cluster.
+func openDeadLetterProducer(ctx context.Context, cfg *config.Config) (*Producer, string, error) {
+	policy := cfg.Replication.ErrorPolicy
+	cluster := cfg.Clusters[policy.DeadLetterCluster()]
+	topic := policy.DeadLetterTopic(cfg.Replication.JobID)
+
+	admin, err := adminClientFactory(cluster)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create %s admin client: %w", policy.DeadLetterCluster(), err)
+	}
+	defer admin.Close()
+	if err := admin.EnsureTopicExists(ctx, topic, 1, -1, nil); err != nil {
+		return nil, "", fmt.Errorf("failed to create dead-letter topic %s: %w", topic, err)
+	}
+	producer, err := producerFactory(cluster, cfg.Replication, cfg.Replication.JobID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create dead-letter producer: %w", err)
+	}
+	return producer, topic, nil
+}
@@ -0,0 +1,269 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"path"
+	"sort"
+	"strings"
+)
+
+// jobConsumerGroupPrefix names the consumer groups of kaf-mirror jobs, which are
+// never copied between clusters.
+const jobConsumerGroupPrefix = "kaf-mirror-job-"
+
+// JobConsumerGroup returns the consumer group a job reads its source topics with.
+func JobConsumerGroup(jobID string) string {
+	return jobConsumerGroupPrefix + jobID
+}
+
+// JobClusters holds admin clients for both clusters of a job together with its
+// resolved topic mappings, for operations that look at both sides at once.
+type JobClusters struct {
+	JobID    string
+	Source   AdminClientAPI
+	Target   AdminClientAPI
+	TopicMap map[string]string
+}
+
+// GroupOffsetSync is the result of copying the offsets of one consumer group.
+type GroupOffsetSync struct {
+	Group string `json:"group"`
+	// Offsets are the translated target offsets by target topic and partition.
+	Offsets map[string]map[int32]int64 `json:"offsets"`
+	Error   string                     `json:"error,omitempty"`
+}
+
+// OpenJobClusters connects to both clusters of a job. Regex mappings are resolved
+// against the current source topics. Callers must Close the result.
+func OpenJobClusters(cfg *config.Config) (*JobClusters, error) {
+	_, topicMap, _, err := resolveTopicMappings(cfg)
+	if err != nil {
+		return nil, err
+	}
+	sourceAdmin, err := adminClientFactory(cfg.Clusters["source"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create source admin client: %w", err)
+	}
+	targetAdmin, err := adminClientFactory(cfg.Clusters["target"])
+	if err != nil {
+		sourceAdmin.Close()
+		return nil, fmt.Errorf("failed to create target admin client: %w", err)
+	}
+	return &JobClusters{JobID: cfg.Replication.JobID, Source: sourceAdmin, Target: targetAdmin, TopicMap: topicMap}, nil
+}
+
+// Close closes both admin clients.
+func (c *JobClusters) Close() {
+	c.Source.Close()
+	c.Target.Close()
+}
+
+// OutstandingRecords returns how many records of the mapped source topics have
+// not reached the target yet, summed over partitions. Partitions missing on the
+// target count with their full source high water mark.
+func (c *JobClusters) OutstandingRecords(ctx context.Context) (int64, error) {
+	comparison, err := CompareClusterOffsets(ctx, c.Source, c.Target, c.TopicMap)
+	if err != nil {
+		return 0, err
+	}
+	if len(comparison.CriticalIssues) > 0 {
+		return 0, fmt.Errorf("failed to compare offsets: %s", strings.Join(comparison.CriticalIssues, "; "))
+	}
+	var outstanding int64
+	for _, topic := range comparison.TopicComparisons {
+		for _, partition := range topic.PartitionComparisons {
+			if partition.Gap > 0 {
+				outstanding += partition.Gap
+			}
+		}
+	}
+	return outstanding, nil
+}
+
+// AnalyzeMirrorState runs the cross-cluster mirror state analysis for the job.
+func (c *JobClusters) AnalyzeMirrorState(ctx context.Context) (*MirrorStateAnalysis, error) {
+	return AnalyzeMirrorState(ctx, c.Source, c.Target, c.JobID, c.TopicMap, JobConsumerGroup(c.JobID))
+}
+
+// TargetHighWaterMarks returns the high water marks of the mapped target topics.
+func (c *JobClusters) TargetHighWaterMarks(ctx context.Context) (map[string]map[int32]int64, error) {
+	return highWaterMarks(ctx, c.Target, c.targetTopics())
+}
+
+// SourceGroupOffsets returns the committed offsets on the mapped source topics
+// of the source consumer groups matching patterns, by group, topic and partition.
+// An empty pattern list matches every group. Groups of kaf-mirror jobs and
+// groups without offsets on the mapped topics are left out.
+func (c *JobClusters) SourceGroupOffsets(ctx context.Context, patterns []string) (map[string]map[string]map[int32]int64, error) {
+	groups, err := c.Source.ListConsumerGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(groups)
+
+	result := make(map[string]map[string]map[int32]int64)
+	for _, group := range groups {
+		if strings.HasPrefix(group, jobConsumerGroupPrefix) || !matchesAnyGroup(group, patterns) {
+			continue
+		}
+		committed, err := c.Source.GetConsumerGroupOffsets(ctx, group, c.sourceTopics())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get offsets of group %s: %w", group, err)
+		}
+		offsets := make(map[string]map[int32]int64)
+		for topic, partitions := range committed {
+			for _, partition := range partitions {
+				if partition.Offset < 0 {
+					continue
+				}
+				if offsets[topic] == nil {
+					offsets[topic] = make(map[int32]int64)
+				}
+				offsets[topic][partition.Partition] = partition.Offset
+			}
+		}
+		if len(offsets) > 0 {
+			result[group] = offsets
+		}
+	}
+	return result, nil
+}
+
+// SyncConsumerGroupOffsets commits the translated source offsets of the matching
+// consumer groups on the target cluster, so consumers moving to the target resume
+// where they stopped. With dryRun only the translation is returned. A failing
+// group does not stop the others; its error is part of its result.
+func (c *JobClusters) SyncConsumerGroupOffsets(ctx context.Context, patterns []string, dryRun bool) ([]GroupOffsetSync, error) {
+	groupOffsets, err := c.SourceGroupOffsets(ctx, patterns)
+	if err != nil {
+		return nil, err
+	}
+	if len(groupOffsets) == 0 {
+		return []GroupOffsetSync{}, nil
+	}
+	sourceHWMs, err := highWaterMarks(ctx, c.Source, c.sourceTopics())
+	if err != nil {
+		return nil, err
+	}
+	targetHWMs, err := c.TargetHighWaterMarks(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make([]string, 0, len(groupOffsets))
+	for group := range groupOffsets {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	results := make([]GroupOffsetSync, 0, len(groups))
+	for _, group := range groups {
+		groupSync := GroupOffsetSync{
+			Group:   group,
+			Offsets: TranslateGroupOffsets(groupOffsets[group], sourceHWMs, targetHWMs, c.TopicMap),
+		}
+		if !dryRun {
+			if err := c.Target.CommitConsumerGroupOffsets(ctx, group, groupSync.Offsets); err != nil {
+				groupSync.Error = err.Error()
+			}
+		}
+		results = append(results, groupSync)
+	}
+	return results, nil
+}
+
+// TranslateGroupOffsets maps committed source offsets to target offsets by keeping
+// each consumer's distance from the end of the partition. That is exact once the
+// target has caught up with the source, which a failover waits for, even when the
+// target topic holds less history than the source. Offsets are keyed by target
+// topic; partitions unknown on either side are left out.
+func TranslateGroupOffsets(committed, sourceHWMs, targetHWMs map[string]map[int32]int64, topicMap map[string]string) map[string]map[int32]int64 {
+	translated := make(map[string]map[int32]int64)
+	for sourceTopic, partitions := range committed {
+		targetTopic, mapped := topicMap[sourceTopic]
+		if !mapped {
+			continue
+		}
+		for partition, offset := range partitions {
+			sourceHWM, ok := sourceHWMs[sourceTopic][partition]
+			if !ok {
+				continue
+			}
+			targetHWM, ok := targetHWMs[targetTopic][partition]
+			if !ok {
+				continue
+			}
+			remaining := sourceHWM - offset
+			if remaining < 0 {
+				remaining = 0
+			}
+			targetOffset := targetHWM - remaining
+			if targetOffset < 0 {
+				targetOffset = 0
+			}
+			if translated[targetTopic] == nil {
+				translated[targetTopic] = make(map[int32]int64)
+			}
+			translated[targetTopic][partition] = targetOffset
+		}
+	}
+	return translated
+}
+
+func (c *JobClusters) sourceTopics() []string {
+	topics := make([]string, 0, len(c.TopicMap))
+	for sourceTopic := range c.TopicMap {
+		topics = append(topics, sourceTopic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+func (c *JobClusters) targetTopics() []string {
+	topics := make([]string, 0, len(c.TopicMap))
+	for _, targetTopic := range c.TopicMap {
+		topics = append(topics, targetTopic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+func highWaterMarks(ctx context.Context, admin AdminClientAPI, topics []string) (map[string]map[int32]int64, error) {
+	offsets, err := admin.GetTopicHighWaterMarks(ctx, topics)
+	if err != nil {
+		return nil, err
+	}
+	hwms := make(map[string]map[int32]int64, len(offsets))
+	for topic, partitions := range offsets {
+		hwms[topic] = make(map[int32]int64, len(partitions))
+		for _, partition := range partitions {
+			hwms[topic][partition.Partition] = partition.HighWaterMark
+		}
+	}
+	return hwms, nil
+}
+
+func matchesAnyGroup(group string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, group); ok {
+			return true
+		}
+	}
+	return false
+}
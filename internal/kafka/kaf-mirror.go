@@ -21,6 +21,7 @@ import (
 	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -51,12 +52,52 @@ type KafMirrorImpl struct {
 	jobID             string
 	onPanic           func(jobID string, reason string)
 	discoveryInterval time.Duration
+	latency           *LatencyTracker
+	traceSampleRatio  float64
+
+	topicConfigs        TopicConfigPolicy
+	topicConfigInterval time.Duration
+
+	// Copies schemas to the target registry, nil without registries
+	schemas *SchemaReplicator
+
+	// Source topic deletion handling, only touched by discovery
+	deletionPolicy   string
+	deletionGrace    time.Duration
+	deletedSources   map[string]bool
+	pendingDeletions map[string]pendingTopicDeletion
+	onTopicChange    func(jobID string, change TopicChange)
+	bufferedChanges  []TopicChange
+
+	// Bounded ranges of a backfill job, nil for continuous jobs
+	backfill   *backfillTracker
+	onBackfill func(jobID string, progress []BackfillProgress, done bool)
+
+	// Duplicate suppression after restarts, nil unless dedup is enabled
+	dedup      *dedupFilter
+	provenance bool
+
+	// Error policy for records the target rejects
+	errorPolicy     config.ErrorPolicy
+	deadLetters     *Producer // nil unless the policy dead-letters records
+	deadLetterTopic string
+	deadLettered    atomic.Int64
+	errorClasses    map[string]int
+	errorMu         sync.Mutex
+	halted          atomic.Bool
+	ctx             context.Context
+	held            map[string]map[int32]*heldPartition // source partitions waiting for a retry
+	heldMu          sync.Mutex
+	commits         commitTracker // consumed records not committed yet
 
 	// Incident tracking to prevent spam logging
 	incidentStates map[string]bool
 	incidentMutex  sync.RWMutex
 }
 
+// metricsInterval is how often a running job reports its metrics.
+const metricsInterval = 10 * time.Second
+
 type regexMapping struct {
 	regex  *regexp.Regexp
 	target string
@@ -70,14 +111,38 @@ func NewKafMirror(cfg *config.Config) (KafMirror, error) {
 	}
 
 	// Validate cluster compatibility and sync state before starting
-	targetPartitions, err := validateAndSyncClusters(cfg, topics, topicMap)
+	targetPartitions, missingSources, err := validateAndSyncClusters(cfg, topics, topicMap)
 	if err != nil {
 		return nil, fmt.Errorf("cluster validation failed: %w", err)
 	}
+	backfill := len(cfg.Replication.Backfill) > 0
+	if backfill {
+		for sourceTopic := range missingSources {
+			return nil, fmt.Errorf("source topic %s of the backfill does not exist", sourceTopic)
+		}
+	}
+	if len(missingSources) > 0 {
+		existing := topics[:0]
+		for _, topic := range topics {
+			if _, missing := missingSources[topic]; !missing {
+				existing = append(existing, topic)
+			}
+		}
+		topics = existing
+	}
 
-	// Use job-specific consumer group to avoid conflicts between jobs
-	consumerGroup := fmt.Sprintf("kaf-mirror-job-%s", cfg.Replication.JobID)
-	consumer, err := NewConsumer(cfg.Clusters["source"], consumerGroup, cfg.Replication, cfg.Replication.JobID, topics...)
+	var consumer *Consumer
+	if backfill {
+		// Backfills read their ranges directly and leave the job's consumer group alone
+		consumer, err = NewPartitionConsumer(cfg.Clusters["source"], cfg.Replication, cfg.Replication.JobID, backfillOffsets(cfg.Replication.Backfill))
+	} else {
+		// Use job-specific consumer group to avoid conflicts between jobs
+		consumerGroup := fmt.Sprintf("kaf-mirror-job-%s", cfg.Replication.JobID)
+		if err := applyStartPositions(cfg, consumerGroup, topics); err != nil {
+			return nil, fmt.Errorf("failed to apply start positions: %w", err)
+		}
+		consumer, err = NewConsumer(cfg.Clusters["source"], consumerGroup, cfg.Replication, cfg.Replication.JobID, topics...)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +153,8 @@ func NewKafMirror(cfg *config.Config) (KafMirror, error) {
 		return nil, err
 	}
 
-	return &KafMirrorImpl{
+	policy, grace := deletionPolicy(cfg)
+	r := &KafMirrorImpl{
 		Consumer:          consumer,
 		Producer:          producer,
 		sourceCfg:         cfg.Clusters["source"],
@@ -98,18 +164,67 @@ func NewKafMirror(cfg *config.Config) (KafMirror, error) {
 		regexMaps:         regexMaps,
 		targetPartitions:  targetPartitions,
 		discoveryInterval: discoveryInterval(cfg),
+		latency:           NewLatencyTracker(),
+		traceSampleRatio:  traceSampleRatio(cfg),
 		incidentStates:    make(map[string]bool),
-	}, nil
+
+		topicConfigs:        NewTopicConfigPolicy(cfg.Replication.TopicConfigSync),
+		topicConfigInterval: topicConfigSyncInterval(cfg),
+
+		jobID:            cfg.Replication.JobID,
+		deletionPolicy:   policy,
+		deletionGrace:    grace,
+		deletedSources:   make(map[string]bool),
+		pendingDeletions: make(map[string]pendingTopicDeletion),
+		schemas:          NewSchemaReplicator(cfg.Clusters["source"].SchemaRegistry, cfg.Clusters["target"].SchemaRegistry),
+		errorPolicy:      cfg.Replication.ErrorPolicy,
+	}
+	consumer.OnPartitionsRevoked(r.commits.forget)
+	if backfill {
+		// The ranges are fixed, so new topics are not picked up
+		r.backfill = newBackfillTracker(cfg.Replication.Backfill)
+		r.discoveryInterval = 0
+	}
+	if r.errorPolicy.UsesDeadLetter() {
+		r.deadLetters, r.deadLetterTopic, err = openDeadLetterProducer(context.Background(), cfg)
+		if err != nil {
+			consumer.Close()
+			producer.Close()
+			return nil, err
+		}
+	}
+	if cfg.Replication.Dedup.Enabled {
+		r.provenance = true
+		// Backfills resume from their acknowledged ranges and need no filter
+		if !backfill {
+			filter, err := loadDedupFilter(context.Background(), cfg, dedupTargetTopics(targetPartitions))
+			if err != nil {
+				r.Stop()
+				return nil, fmt.Errorf("failed to read target partitions for dedup: %w", err)
+			}
+			r.dedup = filter
+		}
+	}
+	r.syncSchemas(context.Background(), topicMap)
+
+	// Exact mappings whose source topic is already gone are handled like a deletion
+	// seen by discovery, so they resume once the topic is recreated.
+	for sourceTopic, targetTopic := range missingSources {
+		r.deletedSources[sourceTopic] = true
+		r.stopMirroring(sourceTopic, targetTopic)
+	}
+	return r, nil
 }
 
 // Start begins the replication process.
 func (r *KafMirrorImpl) Start(jobID string, metricsCallback func(database.ReplicationMetric), onPanic func(jobID string, reason string)) {
-	logger.Info("[Job %s] Starting replication process", jobID)
-	logger.Info("[Job %s] Topic mappings: %v", jobID, r.topicMap)
-
 	ctx, cancel := context.WithCancel(context.Background())
+	r.ctx = ctx
 	r.cancelFunc = cancel
 	r.jobID = jobID
+	log := r.log("replication")
+	log.Info("Starting replication process")
+	log.Info("Topic mappings: %v", r.topicMap)
 	r.onPanic = onPanic
 	r.wg.Add(2)
 
@@ -118,16 +233,19 @@ func (r *KafMirrorImpl) Start(jobID string, metricsCallback func(database.Replic
 		defer func() {
 			if rec := recover(); rec != nil {
 				reason := fmt.Sprintf("consumer panic: %v", rec)
-				logger.Error("[Job %s] %s", jobID, reason)
+				r.log("consumer").Error("%s", reason)
 				onPanic(jobID, reason)
 			}
 		}()
-		logger.Info("[Job %s] Starting consumer goroutine", jobID)
+		consumerLog := r.log("consumer")
+		consumerLog.Info("Starting consumer goroutine")
 		r.Consumer.Consume(ctx, func(record *kgo.Record) {
-			logger.Debug("[Job %s] Received record from topic %s, partition %d, offset %d", jobID, record.Topic, record.Partition, record.Offset)
+			if logger.Enabled(logger.DEBUG) {
+				consumerLog.With(logger.Topic(record.Topic), logger.Partition(record.Partition)).Debug("Received record at offset %d", record.Offset)
+			}
 			r.handleRecord(record)
 		})
-		logger.Info("[Job %s] Consumer goroutine ended", jobID)
+		consumerLog.Info("Consumer goroutine ended")
 	}()
 
 	go func() {
@@ -135,16 +253,16 @@ func (r *KafMirrorImpl) Start(jobID string, metricsCallback func(database.Replic
 		defer func() {
 			if rec := recover(); rec != nil {
 				reason := fmt.Sprintf("metrics panic: %v", rec)
-				logger.Error("[Job %s] %s", jobID, reason)
+				r.log("metrics").Error("%s", reason)
 				onPanic(jobID, reason)
 			}
 		}()
-		logger.Info("[Job %s] Starting metrics collection goroutine", jobID)
+		r.log("metrics").Info("Starting metrics collection goroutine")
 		r.collectMetrics(ctx, jobID, metricsCallback, onPanic)
-		logger.Info("[Job %s] Metrics collection goroutine ended", jobID)
+		r.log("metrics").Info("Metrics collection goroutine ended")
 	}()
 
-	if len(r.regexMaps) > 0 && r.discoveryInterval > 0 {
+	if r.discoveryInterval > 0 {
 		r.wg.Add(1)
 		go func() {
 			defer r.wg.Done()
@@ -152,7 +270,25 @@ func (r *KafMirrorImpl) Start(jobID string, metricsCallback func(database.Replic
 		}()
 	}
 
-	logger.Info("[Job %s] Both goroutines started successfully", jobID)
+	if r.topicConfigs.Enabled() && r.topicConfigInterval > 0 {
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.syncTopicConfigsLoop(ctx)
+		}()
+	}
+
+	if r.backfill != nil {
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.reportBackfillLoop(ctx)
+		}()
+		// Ranges may all be empty or copied by an earlier run
+		r.checkBackfillDone()
+	}
+
+	log.Info("Both goroutines started successfully")
 }
 
 // Stop gracefully shuts down the kaf-mirror.
@@ -163,6 +299,9 @@ func (r *KafMirrorImpl) Stop() {
 	r.wg.Wait()
 	r.Consumer.Close()
 	r.Producer.Close()
+	if r.deadLetters != nil {
+		r.deadLetters.Close()
+	}
 }
 
 // GetConsumer returns the underlying consumer.
@@ -181,6 +320,30 @@ func (r *KafMirrorImpl) HandleRecordForTest(record *kgo.Record) {
 }
 
 func (r *KafMirrorImpl) handleRecord(record *kgo.Record) {
+	r.commits.track(record)
+	if r.holdRecord(record) {
+		return
+	}
+	r.mirrorRecord(record)
+}
+
+// settleRecord marks record for commit once every earlier record of its source
+// partition is settled too.
+func (r *KafMirrorImpl) settleRecord(record *kgo.Record) {
+	if last := r.commits.settle(record); last != nil && r.Consumer != nil {
+		r.Consumer.MarkRecords(last)
+	}
+}
+
+func (r *KafMirrorImpl) mirrorRecord(record *kgo.Record) {
+	if r.halted.Load() {
+		return
+	}
+	if r.backfill != nil && !r.admitBackfill(record.Topic, record.Partition, record.Offset) {
+		r.settleRecord(record)
+		return
+	}
+
 	r.mapMu.RLock()
 	targetTopic, ok := r.topicMap[string(record.Topic)]
 	r.mapMu.RUnlock()
@@ -197,7 +360,19 @@ func (r *KafMirrorImpl) handleRecord(record *kgo.Record) {
 	}
 
 	if targetTopic == "" {
-		logger.Warn("No mapping found for topic: %s", record.Topic)
+		r.log("consumer", logger.Topic(record.Topic)).Warn("No mapping found for topic: %s", record.Topic)
+		if r.backfill != nil {
+			r.ackBackfill(record.Topic, record.Partition, record.Offset, fmt.Errorf("no mapping found for topic %s", record.Topic))
+		}
+		r.settleRecord(record)
+		return
+	}
+
+	if r.dedup != nil && r.dedup.skip(record, targetTopic) {
+		if logger.Enabled(logger.DEBUG) {
+			r.log("consumer", logger.Topic(record.Topic), logger.Partition(record.Partition)).Debug("Skipped record at offset %d already on the target", record.Offset)
+		}
+		r.settleRecord(record)
 		return
 	}
 
@@ -210,29 +385,37 @@ func (r *KafMirrorImpl) handleRecord(record *kgo.Record) {
 	if totalSize > 0 {
 		// Sample every 100th message or messages > 2KB for compression analysis
 		if totalSize > 2048 || record.Offset%100 == 0 {
-			logger.InfoAI("message", "size", string(record.Topic),
+			r.log("consumer", logger.Topic(record.Topic), logger.Partition(record.Partition)).AI(logger.INFO, "message", "size",
 				"Message analysis: topic=%s, partition=%d, total_size=%d bytes, value_size=%d bytes, key_size=%d bytes - potential compression candidate",
 				record.Topic, record.Partition, totalSize, valueSize, keySize)
 		}
 
 		// AI recommendation triggers
 		if totalSize > 5120 { // > 5KB
-			logger.InfoAI("compression", "recommendation", string(record.Topic),
+			r.log("consumer", logger.Topic(record.Topic)).AI(logger.INFO, "compression", "recommendation",
 				"Large message detected: %d bytes on topic %s - consider enabling lz4 or snappy compression for better performance",
 				totalSize, record.Topic)
 		} else if totalSize > 2048 { // > 2KB
-			logger.InfoAI("compression", "candidate", string(record.Topic),
+			r.log("consumer", logger.Topic(record.Topic)).AI(logger.INFO, "compression", "candidate",
 				"Medium message detected: %d bytes on topic %s - snappy compression could reduce storage overhead",
 				totalSize, record.Topic)
 		}
 	}
 
-	// Create a new record for the target topic
+	headers := record.Headers
+	if r.provenance {
+		headers = withProvenance(record)
+	}
+
+	// Create a new record for the target topic, keeping the source timestamp
+	// so end-to-end latency stays measurable on the target side. Schema IDs
+	// in key and value are remapped when it is produced.
 	outRecord := &kgo.Record{
-		Topic:   targetTopic,
-		Value:   record.Value,
-		Key:     record.Key,
-		Headers: record.Headers,
+		Topic:     targetTopic,
+		Value:     record.Value,
+		Key:       record.Key,
+		Headers:   headers,
+		Timestamp: record.Timestamp,
 	}
 	r.mapMu.RLock()
 	partitionCount, hasPartitions := r.targetPartitions[targetTopic]
@@ -241,24 +424,53 @@ func (r *KafMirrorImpl) handleRecord(record *kgo.Record) {
 		if record.Partition < partitionCount {
 			outRecord.Partition = record.Partition
 		} else {
-			logger.Warn("Source partition %d exceeds target partitions %d for topic %s", record.Partition, partitionCount, targetTopic)
+			r.log("producer", logger.Topic(targetTopic), logger.Partition(record.Partition)).Warn("Source partition %d exceeds target partitions %d for topic %s", record.Partition, partitionCount, targetTopic)
 		}
 	}
 
-	r.Producer.Produce(context.Background(), outRecord, func(rec *kgo.Record, err error) {
+	endTrace := r.traceRecord(record, outRecord)
+
+	sourceTopic, sourcePartition, sourceOffset, sourceTs := record.Topic, record.Partition, record.Offset, record.Timestamp
+	r.latency.ObserveConsumed(sourceTopic, sourcePartition, sourceTs, totalSize)
+	r.produce(record, outRecord, 0, func(rec *kgo.Record, err error, outcome produceOutcome) {
+		r.latency.ObserveAck(sourceTopic, sourcePartition, sourceTs, time.Now(), totalSize, err)
+		if outcome != outcomeFailed {
+			r.settleRecord(record)
+		}
+		if r.backfill != nil {
+			if outcome == outcomeDeadLettered {
+				// Kept in the dead-letter topic, so the range is not failed
+				r.ackBackfill(sourceTopic, sourcePartition, sourceOffset, nil)
+			} else {
+				r.ackBackfill(sourceTopic, sourcePartition, sourceOffset, err)
+			}
+		}
+		if endTrace != nil {
+			endTrace(rec, err)
+		}
 		if err != nil {
-			logger.Error("Failed to produce record to topic %s: %v", rec.Topic, err)
-		} else {
-			logger.Debug("Replicated record to topic %s, partition %d, offset %d", rec.Topic, rec.Partition, rec.Offset)
+			if outcome != outcomeDeadLettered {
+				r.log("producer", logger.Topic(rec.Topic)).Error("Failed to produce record to topic %s: %v", rec.Topic, err)
+			}
+		} else if logger.Enabled(logger.DEBUG) {
+			r.log("producer", logger.Topic(rec.Topic), logger.Partition(rec.Partition)).Debug("Replicated record at offset %d", rec.Offset)
 		}
 	})
 }
 
+// duplicatesSkipped returns how many source records dedup skipped since the start.
+func (r *KafMirrorImpl) duplicatesSkipped() int64 {
+	if r.dedup == nil {
+		return 0
+	}
+	return r.dedup.skipped.Load()
+}
+
 func (r *KafMirrorImpl) collectMetrics(ctx context.Context, jobID string, callback func(database.ReplicationMetric), onPanic func(jobID string, reason string)) {
-	ticker := time.NewTicker(10 * time.Second)
+	ticker := time.NewTicker(metricsInterval)
 	defer ticker.Stop()
 
-	logger.Info("[Job %s] Metrics collection loop started", jobID)
+	r.log("metrics").Info("Metrics collection loop started")
 
 	// Counter for periodic logging (every 5th tick = ~50 seconds)
 	logCounter := 0
@@ -294,10 +506,10 @@ func (r *KafMirrorImpl) collectMetrics(ctx context.Context, jobID string, callba
 			isFirstConsumed := totalConsumed == 1 && logCounter <= 2
 			isSignificantConsumed := totalConsumed > 0 && totalConsumed%100 == 0
 
-			// Check for producer failure threshold
-			if producerMetrics.ConsecutiveErrors > 100 {
+			// Check for producer failure threshold, unless an error policy decides
+			if !r.errorPolicy.IsSet() && producerMetrics.ConsecutiveErrors > 100 {
 				reason := fmt.Sprintf("producer has failed %d consecutive times", producerMetrics.ConsecutiveErrors)
-				logger.Error("[Job %s] %s", jobID, reason)
+				r.log("producer").Error("%s", reason)
 				onPanic(jobID, reason) // Use onPanic to trigger a shutdown
 				return
 			}
@@ -377,6 +589,12 @@ func (r *KafMirrorImpl) collectMetrics(ctx context.Context, jobID string, callba
 					totalMessages, totalBytes, currentLag)
 			}
 
+			lagSnapshot := r.latency.Snapshot(r.Consumer.PartitionLags(), time.Now())
+			for i := range lagSnapshot.Metrics {
+				lagSnapshot.Metrics[i].JobID = jobID
+				lagSnapshot.Metrics[i].Resolution = int(metricsInterval / time.Second)
+			}
+
 			metric := database.ReplicationMetric{
 				JobID:              jobID,
 				MessagesReplicated: int(totalMessages),      // Total messages replicated (acked)
@@ -385,17 +603,26 @@ func (r *KafMirrorImpl) collectMetrics(ctx context.Context, jobID string, callba
 				BytesConsumed:      int(totalConsumedBytes), // Total bytes consumed
 				CurrentLag:         int(currentLag),         // Current consumer lag
 				ErrorCount:         int(totalErrors),        // Total errors
+				DuplicatesSkipped:  int(r.duplicatesSkipped()),
+				DeadLettered:       int(r.deadLettered.Load()),
+				ErrorClasses:       r.errorClassCounts(),
 				SourceStalled:      sourceStalled,
 				TargetStalled:      targetStalled,
 				CriticalLag:        criticalLag,
 				HighErrorRate:      highErrorRate,
 				ErrorSpike:         errorSpike,
+				LagSecondsP50:      lagSnapshot.P50,
+				LagSecondsP99:      lagSnapshot.P99,
+				LagSecondsMax:      lagSnapshot.Max,
+				TopicLags:          lagSnapshot.Topics,
+				PartitionLags:      lagSnapshot.Partitions,
+				PartitionMetrics:   lagSnapshot.Metrics,
 				Timestamp:          time.Now(),
 			}
 
 			callback(metric)
 		case <-ctx.Done():
-			logger.Info("[Job %s] Metrics collection cancelled", jobID)
+			r.log("metrics").Info("Metrics collection cancelled")
 			return
 		}
 	}
@@ -489,8 +716,10 @@ func resolveTopicMappings(cfg *config.Config) ([]string, map[string]string, []re
 	return topics, topicMap, regexMaps, nil
 }
 
-// validateAndSyncClusters validates cluster compatibility and syncs state before replication
-func validateAndSyncClusters(cfg *config.Config, topics []string, topicMap map[string]string) (map[string]int32, error) {
+// validateAndSyncClusters validates cluster compatibility and syncs state before replication.
+// Unless the source topic deletion policy is ignore, mappings whose source topic does not
+// exist are removed from topicMap and returned instead of failing the job.
+func validateAndSyncClusters(cfg *config.Config, topics []string, topicMap map[string]string) (map[string]int32, map[string]string, error) {
 	logger.Info("Starting cluster validation and state synchronization")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
@@ -499,63 +728,72 @@ func validateAndSyncClusters(cfg *config.Config, topics []string, topicMap map[s
 	// Create admin clients for both clusters
 	sourceAdmin, err := adminClientFactory(cfg.Clusters["source"])
 	if err != nil {
-		return nil, fmt.Errorf("failed to create source admin client: %w", err)
+		return nil, nil, fmt.Errorf("failed to create source admin client: %w", err)
 	}
 	defer sourceAdmin.Close()
 
 	targetAdmin, err := adminClientFactory(cfg.Clusters["target"])
 	if err != nil {
-		return nil, fmt.Errorf("failed to create target admin client: %w", err)
+		return nil, nil, fmt.Errorf("failed to create target admin client: %w", err)
 	}
 	defer targetAdmin.Close()
 
 	// Get cluster information
 	sourceInfo, err := sourceAdmin.GetClusterInfo(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get source cluster info: %w", err)
+		return nil, nil, fmt.Errorf("failed to get source cluster info: %w", err)
 	}
 
 	targetInfo, err := targetAdmin.GetClusterInfo(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get target cluster info: %w", err)
+		return nil, nil, fmt.Errorf("failed to get target cluster info: %w", err)
 	}
 
 	logger.InfoAI("cluster", "validation", "", "Source cluster: %d brokers, %d topics", sourceInfo.BrokerCount, len(sourceInfo.Topics))
 	logger.InfoAI("cluster", "validation", "", "Target cluster: %d brokers, %d topics", targetInfo.BrokerCount, len(targetInfo.Topics))
 
 	// Validate and sync each topic mapping
+	topicConfigs := NewTopicConfigPolicy(cfg.Replication.TopicConfigSync)
 	targetPartitions := make(map[string]int32)
+	missingSources := make(map[string]string)
 	for sourceTopic, targetTopic := range topicMap {
 		logger.InfoAI("topic", "validation", "", "Validating topic mapping: %s -> %s", sourceTopic, targetTopic)
 
 		// Check if source topic exists
 		sourceTopicInfo, sourceExists := sourceInfo.Topics[sourceTopic]
 		if !sourceExists {
-			return nil, fmt.Errorf("source topic %s does not exist", sourceTopic)
+			if policy, _ := deletionPolicy(cfg); policy == config.SourceTopicDeletionIgnore {
+				return nil, nil, fmt.Errorf("source topic %s does not exist", sourceTopic)
+			}
+			logger.Warn("Source topic %s does not exist, not mirroring it to %s until it is created", sourceTopic, targetTopic)
+			missingSources[sourceTopic] = targetTopic
+			delete(topicMap, sourceTopic)
+			continue
 		}
 
 		// Ensure target topic exists with correct partitions
 		replicationFactor := clampReplicationFactor(sourceTopicInfo.ReplicationFactor, targetInfo.BrokerCount, sourceTopic)
-		err = ensureTopicWithRetry(ctx, targetAdmin, targetTopic, sourceTopicInfo.Partitions, replicationFactor)
+		createConfigs := topicCreateConfigs(ctx, sourceAdmin, sourceTopic, topicConfigs, replicationFactor)
+		err = ensureTopicWithRetry(ctx, targetAdmin, targetTopic, sourceTopicInfo.Partitions, replicationFactor, createConfigs)
 		if err != nil {
-			return nil, fmt.Errorf("failed to ensure target topic %s exists: %w", targetTopic, err)
+			return nil, nil, fmt.Errorf("failed to ensure target topic %s exists: %w", targetTopic, err)
 		}
 
 		// Re-fetch target info after potential topic creation
 		targetInfo, err = targetAdmin.GetClusterInfo(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("failed to refresh target cluster info: %w", err)
+			return nil, nil, fmt.Errorf("failed to refresh target cluster info: %w", err)
 		}
 
 		// Validate topic compatibility
 		targetTopicInfo, targetExists := targetInfo.Topics[targetTopic]
 		if !targetExists {
-			return nil, fmt.Errorf("target topic %s does not exist after creation attempt", targetTopic)
+			return nil, nil, fmt.Errorf("target topic %s does not exist after creation attempt", targetTopic)
 		}
 
 		err = targetAdmin.ValidateTopicCompatibility(ctx, sourceTopicInfo, targetTopicInfo)
 		if err != nil {
-			return nil, fmt.Errorf("topic compatibility validation failed: %w", err)
+			return nil, nil, fmt.Errorf("topic compatibility validation failed: %w", err)
 		}
 		targetPartitions[targetTopic] = targetTopicInfo.Partitions
 
@@ -593,6 +831,20 @@ func validateAndSyncClusters(cfg *config.Config, topics []string, topicMap map[s
 		}
 	}
 
+	// Bring the configs of target topics that already existed in line with their sources
+	if topicConfigs.Enabled() {
+		action := topicConfigApply
+		if topicConfigs.Mode == config.TopicConfigSyncModeDryRun {
+			action = topicConfigReport
+		}
+		report, err := reconcileTopicConfigs(ctx, sourceAdmin, targetAdmin, topicMap, topicConfigs, action)
+		if err != nil {
+			logger.Warn("Failed to sync topic configs: %v", err)
+		} else {
+			logTopicConfigReport(report, logger.With(logger.JobID(cfg.Replication.JobID), logger.Component("topic-config")))
+		}
+	}
+
 	// Check current consumer group offsets using job-specific group
 	groupID := fmt.Sprintf("kaf-mirror-job-%s", cfg.Replication.JobID)
 	logger.Info("Checking consumer group offsets for group: %s", groupID)
@@ -649,7 +901,7 @@ func validateAndSyncClusters(cfg *config.Config, topics []string, topicMap map[s
 	}
 
 	logger.Info("Cluster validation and state synchronization completed successfully")
-	return targetPartitions, nil
+	return targetPartitions, missingSources, nil
 }
 
 func (r *KafMirrorImpl) discoverTopicsLoop(ctx context.Context) {
@@ -660,7 +912,7 @@ func (r *KafMirrorImpl) discoverTopicsLoop(ctx context.Context) {
 		select {
 		case <-ticker.C:
 			if err := r.discoverAndSyncTopics(ctx); err != nil {
-				logger.Error("[Job %s] Topic discovery failed: %v", r.jobID, err)
+				r.log("discovery").Error("Topic discovery failed: %v", err)
 				if r.onPanic != nil {
 					r.onPanic(r.jobID, err.Error())
 				}
@@ -723,7 +975,8 @@ func (r *KafMirrorImpl) discoverAndSyncTopics(ctx context.Context) error {
 			r.mapMu.RUnlock()
 
 			replicationFactor := clampReplicationFactor(sourceTopicInfo.ReplicationFactor, targetInfo.BrokerCount, sourceTopic)
-			if err := ensureTopicWithRetry(ctx, targetAdmin, targetTopic, sourceTopicInfo.Partitions, replicationFactor); err != nil {
+			createConfigs := topicCreateConfigs(ctx, sourceAdmin, sourceTopic, r.topicConfigs, replicationFactor)
+			if err := ensureTopicWithRetry(ctx, targetAdmin, targetTopic, sourceTopicInfo.Partitions, replicationFactor, createConfigs); err != nil {
 				return fmt.Errorf("failed to ensure target topic %s exists after retries: %w", targetTopic, err)
 			}
 
@@ -735,21 +988,27 @@ func (r *KafMirrorImpl) discoverAndSyncTopics(ctx context.Context) error {
 			r.targetPartitions[targetTopic] = sourceTopicInfo.Partitions
 			r.mapMu.Unlock()
 
+			r.syncSchemas(ctx, map[string]string{sourceTopic: targetTopic})
 			r.Consumer.AddTopics(sourceTopic)
-			logger.Info("[Job %s] Discovered new topic mapping %s -> %s", r.jobID, sourceTopic, targetTopic)
+			r.notifyTopicChange(TopicChangeDiscovered, sourceTopic, targetTopic, "Discovered new topic mapping %s -> %s", sourceTopic, targetTopic)
 		}
 	}
 
+	r.resumeExactMappings(ctx, sourceAdmin, targetAdmin, sourceInfo, targetInfo)
+	r.followSourceDeletions(sourceInfo)
+	r.followPartitionIncreases(ctx, targetAdmin, sourceInfo, targetInfo)
+	r.deleteExpiredTargets(ctx, targetAdmin, sourceInfo)
+
 	return nil
 }
 
-func ensureTopicWithRetry(ctx context.Context, admin AdminClientAPI, topicName string, partitions int32, replicationFactor int16) error {
+func ensureTopicWithRetry(ctx context.Context, admin AdminClientAPI, topicName string, partitions int32, replicationFactor int16, configs map[string]string) error {
 	var lastErr error
 	for attempt := 1; attempt <= 5; attempt++ {
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
-		if err := admin.EnsureTopicExists(ctx, topicName, partitions, replicationFactor); err != nil {
+		if err := admin.EnsureTopicExists(ctx, topicName, partitions, replicationFactor, configs); err != nil {
 			lastErr = err
 			logger.Warn("Failed to create target topic %s (attempt %d/5): %v", topicName, attempt, err)
 			time.Sleep(time.Duration(attempt) * time.Second)
@@ -774,6 +1033,19 @@ func clampReplicationFactor(sourceRF int16, targetBrokerCount int, sourceTopic s
 	return sourceRF
 }
 
+// log returns a logger tagged with the job and the given component.
+func (r *KafMirrorImpl) log(component string, fields ...logger.Field) *logger.Entry {
+	return logger.With(logger.JobID(r.jobID), logger.Component(component)).With(fields...)
+}
+
+// traceSampleRatio is the share of records traced, 0 when tracing is disabled.
+func traceSampleRatio(cfg *config.Config) float64 {
+	if !cfg.Monitoring.Tracing.Enabled {
+		return 0
+	}
+	return cfg.Monitoring.Tracing.SampleRatio
+}
+
 func discoveryInterval(cfg *config.Config) time.Duration {
 	interval, err := time.ParseDuration(cfg.Replication.TopicDiscoveryInterval)
 	if err != nil {
@@ -1075,8 +1347,19 @@ func isRegex(s string) bool {
 type AdminClientAPI interface {
 	GetClusterInfo(ctx context.Context) (*ClusterInfo, error)
 	GetConsumerGroupOffsets(ctx context.Context, groupID string, topics []string) (map[string][]OffsetInfo, error)
+	ListConsumerGroups(ctx context.Context) ([]string, error)
+	CommitConsumerGroupOffsets(ctx context.Context, groupID string, offsets map[string]map[int32]int64) error
 	GetTopicHighWaterMarks(ctx context.Context, topics []string) (map[string][]OffsetInfo, error)
-	EnsureTopicExists(ctx context.Context, topicName string, partitions int32, replicationFactor int16) error
+	GetOffsetsForTimestamp(ctx context.Context, topics []string, millis int64) (map[string][]OffsetInfo, error)
+	EnsureTopicExists(ctx context.Context, topicName string, partitions int32, replicationFactor int16, configs map[string]string) error
+	DescribeTopicConfigs(ctx context.Context, topics ...string) (map[string]map[string]string, error)
+	AlterTopicConfigs(ctx context.Context, topic string, configs map[string]string, validateOnly bool) error
+	CreatePartitions(ctx context.Context, topicName string, totalPartitions int32) error
+	DeleteTopic(ctx context.Context, topicName string) error
+	DescribeACLs(ctx context.Context) ([]ACLEntry, error)
+	CreateACLs(ctx context.Context, acls []ACLEntry) error
+	DescribeClientQuotas(ctx context.Context) ([]ClientQuota, error)
+	AlterClientQuotas(ctx context.Context, quotas []ClientQuota, validateOnly bool) error
 	ValidateTopicCompatibility(ctx context.Context, sourceInfo, targetInfo TopicInfo) error
 	Close()
 }
@@ -1106,11 +1389,118 @@ func NewKafMirrorImplForTest(producer *Producer, topicMap map[string]string, tar
 		Producer:         producer,
 		topicMap:         topicMap,
 		targetPartitions: targetPartitions,
+		latency:          NewLatencyTracker(),
 		incidentStates:   make(map[string]bool),
 	}
 }
 
+// SetBackfillForTest makes the mirror copy only the given ranges, reading from consumer.
+func (r *KafMirrorImpl) SetBackfillForTest(consumer *Consumer, ranges []config.BackfillRange) {
+	r.Consumer = consumer
+	r.backfill = newBackfillTracker(ranges)
+}
+
+// BackfillProgressForTest returns the current progress of the backfill ranges.
+func (r *KafMirrorImpl) BackfillProgressForTest() []BackfillProgress {
+	return r.backfill.progress()
+}
+
+// EnableDedupForTest turns on dedup for cfg, reading the target partitions through
+// the admin client and record reader factories.
+func (r *KafMirrorImpl) EnableDedupForTest(cfg *config.Config) error {
+	filter, err := loadDedupFilter(context.Background(), cfg, dedupTargetTopics(r.targetPartitions))
+	if err != nil {
+		return err
+	}
+	r.dedup = filter
+	r.provenance = true
+	return nil
+}
+
+// SetErrorPolicyForTest applies an error policy, writing dead letters with the given
+// producer, and reports halts to onHalt.
+func (r *KafMirrorImpl) SetErrorPolicyForTest(policy config.ErrorPolicy, deadLetters *Producer, onHalt func(jobID string, reason string)) {
+	r.errorPolicy = policy
+	r.deadLetters = deadLetters
+	r.deadLetterTopic = policy.DeadLetterTopic(r.jobID)
+	r.onPanic = onHalt
+}
+
+// ErrorClassCountsForTest returns the produce errors counted per class.
+func (r *KafMirrorImpl) ErrorClassCountsForTest() map[string]int {
+	return r.errorClassCounts()
+}
+
+// DeadLetteredForTest returns how many records went to the dead-letter topic.
+func (r *KafMirrorImpl) DeadLetteredForTest() int64 {
+	return r.deadLettered.Load()
+}
+
+// DuplicatesSkippedForTest returns how many records dedup skipped.
+func (r *KafMirrorImpl) DuplicatesSkippedForTest() int64 {
+	return r.duplicatesSkipped()
+}
+
+// SetTraceSampleRatioForTest sets the share of records traced.
+func (r *KafMirrorImpl) SetTraceSampleRatioForTest(ratio float64) {
+	r.traceSampleRatio = ratio
+}
+
+// LagSnapshotForTest exposes the time-based lag snapshot for tests.
+func (r *KafMirrorImpl) LagSnapshotForTest(messageLag map[string]map[int32]int64, now time.Time) LagSnapshot {
+	return r.latency.Snapshot(messageLag, now)
+}
+
 // ValidateAndSyncClustersForTest exposes cluster validation for unit tests.
 func ValidateAndSyncClustersForTest(cfg *config.Config, topics []string, topicMap map[string]string) (map[string]int32, error) {
-	return validateAndSyncClusters(cfg, topics, topicMap)
+	targetPartitions, _, err := validateAndSyncClusters(cfg, topics, topicMap)
+	return targetPartitions, err
+}
+
+// NewDiscoveryMirrorForTest builds a KafMirrorImpl that runs topic discovery for cfg
+// against the admin client factory without connecting to Kafka.
+func NewDiscoveryMirrorForTest(cfg *config.Config, consumer *Consumer, topicMap map[string]string, targetPartitions map[string]int32) (*KafMirrorImpl, error) {
+	_, _, regexMaps, err := resolveTopicMappings(cfg)
+	if err != nil {
+		return nil, err
+	}
+	policy, grace := deletionPolicy(cfg)
+	return &KafMirrorImpl{
+		Consumer:         consumer,
+		sourceCfg:        cfg.Clusters["source"],
+		targetCfg:        cfg.Clusters["target"],
+		mappings:         cfg.Topics,
+		topicMap:         topicMap,
+		regexMaps:        regexMaps,
+		targetPartitions: targetPartitions,
+		jobID:            cfg.Replication.JobID,
+		latency:          NewLatencyTracker(),
+		incidentStates:   make(map[string]bool),
+		topicConfigs:     NewTopicConfigPolicy(cfg.Replication.TopicConfigSync),
+		deletionPolicy:   policy,
+		deletionGrace:    grace,
+		deletedSources:   make(map[string]bool),
+		pendingDeletions: make(map[string]pendingTopicDeletion),
+	}, nil
+}
+
+// DiscoverTopicsForTest runs a single topic discovery pass.
+func (r *KafMirrorImpl) DiscoverTopicsForTest(ctx context.Context) error {
+	return r.discoverAndSyncTopics(ctx)
+}
+
+// TopicStateForTest returns a copy of the topic mappings and target partition counts.
+func (r *KafMirrorImpl) TopicStateForTest() (map[string]string, map[string]int32) {
+	r.mapMu.RLock()
+	defer r.mapMu.RUnlock()
+	partitions := make(map[string]int32, len(r.targetPartitions))
+	for topic, count := range r.targetPartitions {
+		partitions[topic] = count
+	}
+	return r.snapshotTopicMapLocked(), partitions
+}
+
+// ApplyStartPositionsForTest exposes the start position step of NewKafMirror for tests.
+func ApplyStartPositionsForTest(cfg *config.Config, group string, topics []string) error {
+	return applyStartPositions(cfg, group, topics)
 }
@@ -0,0 +1,258 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"kaf-mirror/internal/database"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxLatencySamples bounds the number of end-to-end latency samples kept per
+// partition between two snapshots. Once full, new samples overwrite the oldest.
+const maxLatencySamples = 2048
+
+// LatencyTracker measures time-based replication lag and throughput per source
+// partition. End-to-end latency is the difference between a source record's
+// timestamp and the moment the target cluster acknowledged its copy.
+type LatencyTracker struct {
+	mu         sync.Mutex
+	partitions map[string]map[int32]*partitionLatency
+}
+
+type partitionLatency struct {
+	samples        []float64
+	next           int
+	inFlight       int64
+	newestSource   time.Time
+	lastReplicated time.Time
+
+	// Counters since the previous snapshot.
+	consumed        int64
+	consumedBytes   int64
+	replicated      int64
+	replicatedBytes int64
+	errors          int64
+}
+
+// LagSnapshot is the time-based lag of a job at one point in time.
+type LagSnapshot struct {
+	P50        float64
+	P99        float64
+	Max        float64
+	Topics     []database.TopicLag
+	Partitions []database.PartitionLag
+	// Metrics holds one time series point per partition for the interval
+	// since the previous snapshot; JobID is left for the caller to set.
+	Metrics []database.PartitionMetric
+}
+
+// NewLatencyTracker creates an empty tracker.
+func NewLatencyTracker() *LatencyTracker {
+	return &LatencyTracker{partitions: make(map[string]map[int32]*partitionLatency)}
+}
+
+func (t *LatencyTracker) partition(topic string, partition int32) *partitionLatency {
+	parts, ok := t.partitions[topic]
+	if !ok {
+		parts = make(map[int32]*partitionLatency)
+		t.partitions[topic] = parts
+	}
+	p, ok := parts[partition]
+	if !ok {
+		p = &partitionLatency{}
+		parts[partition] = p
+	}
+	return p
+}
+
+// ObserveConsumed records a source record of size bytes handed to the producer.
+func (t *LatencyTracker) ObserveConsumed(topic string, partition int32, sourceTs time.Time, size int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p := t.partition(topic, partition)
+	p.inFlight++
+	p.consumed++
+	p.consumedBytes += int64(size)
+	if sourceTs.After(p.newestSource) {
+		p.newestSource = sourceTs
+	}
+}
+
+// ObserveAck records the target acknowledgement of a replicated record.
+// Failed sends only release the in-flight slot.
+func (t *LatencyTracker) ObserveAck(topic string, partition int32, sourceTs, ackedAt time.Time, size int, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p := t.partition(topic, partition)
+	if p.inFlight > 0 {
+		p.inFlight--
+	}
+	if err != nil {
+		p.errors++
+		return
+	}
+	p.replicated++
+	p.replicatedBytes += int64(size)
+	if sourceTs.IsZero() {
+		return
+	}
+
+	latency := ackedAt.Sub(sourceTs).Seconds()
+	if latency < 0 {
+		// Producer clocks ahead of ours; treat as caught up.
+		latency = 0
+	}
+	if len(p.samples) < maxLatencySamples {
+		p.samples = append(p.samples, latency)
+	} else {
+		p.samples[p.next] = latency
+		p.next = (p.next + 1) % maxLatencySamples
+	}
+	if sourceTs.After(p.lastReplicated) {
+		p.lastReplicated = sourceTs
+	}
+}
+
+// Snapshot computes p50/p99 per partition, topic and job from the samples
+// collected since the previous snapshot, then resets them along with the
+// throughput counters.
+//
+// Partitions without samples in the interval (idle, or stuck) contribute a
+// single estimate: zero when nothing is pending, otherwise the age of the last
+// replicated source timestamp, or of the newest source timestamp seen when
+// nothing has been replicated yet. messageLag carries the consumer's
+// per-partition offset lag and is used to tell caught-up partitions apart.
+func (t *LatencyTracker) Snapshot(messageLag map[string]map[int32]int64, now time.Time) LagSnapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for topic, parts := range messageLag {
+		for partition := range parts {
+			t.partition(topic, partition)
+		}
+	}
+
+	var snap LagSnapshot
+	var all []float64
+	topicSamples := make(map[string][]float64)
+	topicLags := make(map[string]*database.TopicLag)
+
+	for topic, parts := range t.partitions {
+		for partition, p := range parts {
+			lag := messageLag[topic][partition]
+			current := p.estimate(lag, now)
+
+			samples := p.samples
+			if len(samples) == 0 {
+				samples = []float64{current}
+			}
+			sorted := append([]float64(nil), samples...)
+			sort.Float64s(sorted)
+
+			pl := database.PartitionLag{
+				Topic:         topic,
+				Partition:     partition,
+				MessageLag:    lag,
+				LagSeconds:    current,
+				LagSecondsP50: percentile(sorted, 0.50),
+				LagSecondsP99: percentile(sorted, 0.99),
+				Samples:       len(p.samples),
+			}
+			snap.Partitions = append(snap.Partitions, pl)
+			snap.Metrics = append(snap.Metrics, database.PartitionMetric{
+				Topic:              topic,
+				Partition:          partition,
+				Timestamp:          now,
+				MessagesConsumed:   p.consumed,
+				BytesConsumed:      p.consumedBytes,
+				MessagesReplicated: p.replicated,
+				BytesTransferred:   p.replicatedBytes,
+				ErrorCount:         p.errors,
+				MessageLag:         lag,
+				LagSeconds:         current,
+			})
+
+			tl, ok := topicLags[topic]
+			if !ok {
+				tl = &database.TopicLag{Topic: topic}
+				topicLags[topic] = tl
+			}
+			tl.MessageLag += lag
+			tl.LagSecondsMax = math.Max(tl.LagSecondsMax, current)
+			tl.Partitions++
+			snap.Max = math.Max(snap.Max, current)
+
+			topicSamples[topic] = append(topicSamples[topic], sorted...)
+			all = append(all, sorted...)
+
+			p.samples = p.samples[:0]
+			p.next = 0
+			p.consumed, p.consumedBytes, p.replicated, p.replicatedBytes, p.errors = 0, 0, 0, 0, 0
+		}
+	}
+
+	for topic, tl := range topicLags {
+		samples := topicSamples[topic]
+		sort.Float64s(samples)
+		tl.LagSecondsP50 = percentile(samples, 0.50)
+		tl.LagSecondsP99 = percentile(samples, 0.99)
+		snap.Topics = append(snap.Topics, *tl)
+	}
+	sort.Float64s(all)
+	snap.P50 = percentile(all, 0.50)
+	snap.P99 = percentile(all, 0.99)
+
+	sort.Slice(snap.Topics, func(i, j int) bool { return snap.Topics[i].Topic < snap.Topics[j].Topic })
+	sort.Slice(snap.Partitions, func(i, j int) bool {
+		if snap.Partitions[i].Topic != snap.Partitions[j].Topic {
+			return snap.Partitions[i].Topic < snap.Partitions[j].Topic
+		}
+		return snap.Partitions[i].Partition < snap.Partitions[j].Partition
+	})
+	sort.Slice(snap.Metrics, func(i, j int) bool {
+		if snap.Metrics[i].Topic != snap.Metrics[j].Topic {
+			return snap.Metrics[i].Topic < snap.Metrics[j].Topic
+		}
+		return snap.Metrics[i].Partition < snap.Metrics[j].Partition
+	})
+	return snap
+}
+
+// estimate returns how many seconds the partition currently is behind source.
+func (p *partitionLatency) estimate(messageLag int64, now time.Time) float64 {
+	if messageLag <= 0 && p.inFlight == 0 {
+		return 0
+	}
+	since := p.lastReplicated
+	if since.IsZero() {
+		since = p.newestSource
+	}
+	if since.IsZero() {
+		return 0
+	}
+	return math.Max(now.Sub(since).Seconds(), 0)
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(math.Ceil(q*float64(len(sorted)))) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	return sorted[idx]
+}
@@ -0,0 +1,472 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"kaf-mirror/internal/config"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Confluent wire format: a zero magic byte followed by the big-endian schema ID.
+const (
+	wireFormatMagic     = 0
+	wireFormatHeaderLen = 5
+)
+
+const schemaRegistryContentType = "application/vnd.schemaregistry.v1+json"
+
+// errSchemaNotFound is returned for subjects, versions and schema IDs the
+// registry does not know.
+var errSchemaNotFound = errors.New("schema not found")
+
+// SchemaReference points at a schema another schema depends on.
+type SchemaReference struct {
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// RegisteredSchema is a schema as returned by the registry.
+type RegisteredSchema struct {
+	Subject    string            `json:"subject,omitempty"`
+	Version    int               `json:"version,omitempty"`
+	ID         int               `json:"id,omitempty"`
+	Schema     string            `json:"schema"`
+	SchemaType string            `json:"schemaType,omitempty"`
+	References []SchemaReference `json:"references,omitempty"`
+}
+
+// SubjectVersion is one subject and version a schema ID is registered under.
+type SubjectVersion struct {
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// SchemaRegistryClient talks to a Confluent-compatible Schema Registry.
+type SchemaRegistryClient struct {
+	baseURL  string
+	username string
+	password string
+	http     *http.Client
+}
+
+// NewSchemaRegistryClient creates a client for the registry in cfg.
+func NewSchemaRegistryClient(cfg config.SchemaRegistryConfig) *SchemaRegistryClient {
+	return &SchemaRegistryClient{
+		baseURL:  strings.TrimRight(cfg.URL, "/"),
+		username: cfg.Username,
+		password: cfg.Password,
+		http:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SchemaByID returns the schema registered under a global ID.
+func (c *SchemaRegistryClient) SchemaByID(ctx context.Context, id int) (*RegisteredSchema, error) {
+	var schema RegisteredSchema
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/schemas/ids/%d", id), nil, &schema); err != nil {
+		return nil, err
+	}
+	schema.ID = id
+	return &schema, nil
+}
+
+// SubjectVersionsForID lists the subjects and versions a schema ID is registered under.
+func (c *SchemaRegistryClient) SubjectVersionsForID(ctx context.Context, id int) ([]SubjectVersion, error) {
+	var versions []SubjectVersion
+	err := c.do(ctx, http.MethodGet, fmt.Sprintf("/schemas/ids/%d/versions", id), nil, &versions)
+	return versions, err
+}
+
+// SubjectVersions lists the versions of a subject in ascending order.
+func (c *SchemaRegistryClient) SubjectVersions(ctx context.Context, subject string) ([]int, error) {
+	var versions []int
+	if err := c.do(ctx, http.MethodGet, "/subjects/"+url.PathEscape(subject)+"/versions", nil, &versions); err != nil {
+		return nil, err
+	}
+	sort.Ints(versions)
+	return versions, nil
+}
+
+// SubjectVersion returns one version of a subject.
+func (c *SchemaRegistryClient) SubjectVersion(ctx context.Context, subject string, version int) (*RegisteredSchema, error) {
+	var schema RegisteredSchema
+	err := c.do(ctx, http.MethodGet, "/subjects/"+url.PathEscape(subject)+"/versions/"+strconv.Itoa(version), nil, &schema)
+	if err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// Register registers a schema under a subject and returns its ID. Registering a
+// schema the subject already has returns the existing ID.
+func (c *SchemaRegistryClient) Register(ctx context.Context, subject string, schema RegisteredSchema) (int, error) {
+	body := RegisteredSchema{Schema: schema.Schema, SchemaType: schema.SchemaType, References: schema.References}
+	var resp struct {
+		ID int `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/subjects/"+url.PathEscape(subject)+"/versions", body, &resp); err != nil {
+		return 0, err
+	}
+	return resp.ID, nil
+}
+
+// Lookup returns the subject version a schema is registered as.
+func (c *SchemaRegistryClient) Lookup(ctx context.Context, subject string, schema RegisteredSchema) (*RegisteredSchema, error) {
+	body := RegisteredSchema{Schema: schema.Schema, SchemaType: schema.SchemaType, References: schema.References}
+	var found RegisteredSchema
+	if err := c.do(ctx, http.MethodPost, "/subjects/"+url.PathEscape(subject), body, &found); err != nil {
+		return nil, err
+	}
+	return &found, nil
+}
+
+func (c *SchemaRegistryClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", schemaRegistryContentType)
+	if body != nil {
+		req.Header.Set("Content-Type", schemaRegistryContentType)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("schema registry %s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("schema registry %s %s: %w", method, path, errSchemaNotFound)
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			ErrorCode int    `json:"error_code"`
+			Message   string `json:"message"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("schema registry %s %s: %s (%d)", method, path, apiErr.Message, apiErr.ErrorCode)
+		}
+		return fmt.Errorf("schema registry %s %s: status %d", method, path, resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// SchemaReplicator copies the schemas of mirrored records to the target registry
+// and rewrites the schema IDs in Confluent wire format records. Subjects follow
+// the topic mappings: "orders-value" becomes "orders-dr-value" when orders is
+// mirrored to orders-dr. Subjects not named after a topic keep their name.
+type SchemaReplicator struct {
+	source *SchemaRegistryClient
+	target *SchemaRegistryClient
+
+	mu      sync.RWMutex
+	ids     map[schemaSubject]int // source schema ID and target subject -> target schema ID
+	unknown map[int]bool          // IDs the source registry does not know
+}
+
+// schemaSubject is a source schema ID as copied to one target subject. Topics
+// sharing a schema each get it registered under their own renamed subject.
+type schemaSubject struct {
+	sourceID int
+	subject  string
+}
+
+// errSchemaRewrite marks records whose schema IDs could not be remapped.
+var errSchemaRewrite = errors.New("schema ID remap failed")
+
+// NewSchemaReplicator returns a replicator between the registries of the source
+// and target cluster, or nil when one of them has no registry or both share one.
+func NewSchemaReplicator(source, target config.SchemaRegistryConfig) *SchemaReplicator {
+	if source.URL == "" || target.URL == "" {
+		return nil
+	}
+	if strings.TrimRight(source.URL, "/") == strings.TrimRight(target.URL, "/") {
+		return nil
+	}
+	return &SchemaReplicator{
+		source:  NewSchemaRegistryClient(source),
+		target:  NewSchemaRegistryClient(target),
+		ids:     make(map[schemaSubject]int),
+		unknown: make(map[int]bool),
+	}
+}
+
+// IDMap returns a copy of the source to target schema ID map.
+func (s *SchemaReplicator) IDMap() map[int]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids := make(map[int]int, len(s.ids))
+	for key, targetID := range s.ids {
+		ids[key.sourceID] = targetID
+	}
+	return ids
+}
+
+// SyncSubjects copies every version of the key and value subjects of the mapped
+// topics to the target registry, oldest first. Topics without subjects are
+// skipped. It returns the number of versions copied; a failing subject does not
+// stop the others.
+func (s *SchemaReplicator) SyncSubjects(ctx context.Context, topicMap map[string]string) (int, error) {
+	sourceTopics := make([]string, 0, len(topicMap))
+	for sourceTopic := range topicMap {
+		sourceTopics = append(sourceTopics, sourceTopic)
+	}
+	sort.Strings(sourceTopics)
+
+	copied := 0
+	var errs []string
+	for _, sourceTopic := range sourceTopics {
+		for _, suffix := range []string{"-key", "-value"} {
+			subject := sourceTopic + suffix
+			versions, err := s.source.SubjectVersions(ctx, subject)
+			if errors.Is(err, errSchemaNotFound) {
+				continue
+			}
+			if err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			for _, version := range versions {
+				schema, err := s.source.SubjectVersion(ctx, subject, version)
+				if err != nil {
+					errs = append(errs, err.Error())
+					break
+				}
+				if _, err := s.copySchema(ctx, schema, topicMap); err != nil {
+					errs = append(errs, fmt.Sprintf("subject %s version %d: %v", subject, version, err))
+					break
+				}
+				copied++
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return copied, fmt.Errorf("failed to copy schemas: %s", strings.Join(errs, "; "))
+	}
+	return copied, nil
+}
+
+// Rewrite replaces the schema ID of a Confluent wire format key or value with
+// the ID of the same schema in the target registry, copying the schema first if
+// needed. Data that is not in wire format, or whose ID the source registry does
+// not know, is returned unchanged. The input slice is never modified.
+func (s *SchemaReplicator) Rewrite(ctx context.Context, sourceTopic, targetTopic string, isKey bool, data []byte) ([]byte, error) {
+	if len(data) < wireFormatHeaderLen || data[0] != wireFormatMagic {
+		return data, nil
+	}
+	sourceID := int(binary.BigEndian.Uint32(data[1:wireFormatHeaderLen]))
+	key := schemaSubject{sourceID: sourceID, subject: recordSubject(targetTopic, isKey)}
+
+	s.mu.RLock()
+	targetID, known := s.ids[key]
+	unknown := s.unknown[sourceID]
+	s.mu.RUnlock()
+	if unknown {
+		return data, nil
+	}
+	if !known {
+		var err error
+		targetID, err = s.copySchemaID(ctx, sourceID, sourceTopic, targetTopic, isKey)
+		if errors.Is(err, errSchemaNotFound) {
+			s.mu.Lock()
+			s.unknown[sourceID] = true
+			s.mu.Unlock()
+			return data, nil
+		}
+		if err != nil {
+			return data, err
+		}
+		s.mu.Lock()
+		s.ids[key] = targetID
+		s.mu.Unlock()
+	}
+	if targetID == sourceID {
+		return data, nil
+	}
+
+	out := make([]byte, len(data))
+	copy(out, data)
+	binary.BigEndian.PutUint32(out[1:wireFormatHeaderLen], uint32(targetID))
+	return out, nil
+}
+
+// copySchemaID copies a schema seen in a record. It is registered under the
+// renamed subject of the record's topic when the source registry has it there,
+// otherwise under the renamed first subject it is registered under.
+func (s *SchemaReplicator) copySchemaID(ctx context.Context, sourceID int, sourceTopic, targetTopic string, isKey bool) (int, error) {
+	versions, err := s.source.SubjectVersionsForID(ctx, sourceID)
+	if err != nil {
+		return 0, err
+	}
+	if len(versions) == 0 {
+		return 0, errSchemaNotFound
+	}
+	chosen := versions[0]
+	for _, v := range versions {
+		if v.Subject == recordSubject(sourceTopic, isKey) {
+			chosen = v
+			break
+		}
+	}
+
+	schema, err := s.source.SubjectVersion(ctx, chosen.Subject, chosen.Version)
+	if err != nil {
+		return 0, err
+	}
+	return s.copySchema(ctx, schema, map[string]string{sourceTopic: targetTopic})
+}
+
+// copySchema registers a source subject version, and the schemas it references,
+// in the target registry and records the ID mapping.
+func (s *SchemaReplicator) copySchema(ctx context.Context, schema *RegisteredSchema, topicMap map[string]string) (int, error) {
+	targetSubject := renameSubject(schema.Subject, topicMap)
+	key := schemaSubject{sourceID: schema.ID, subject: targetSubject}
+	s.mu.RLock()
+	targetID, known := s.ids[key]
+	s.mu.RUnlock()
+	if known {
+		return targetID, nil
+	}
+
+	references := make([]SchemaReference, 0, len(schema.References))
+	for _, ref := range schema.References {
+		referenced, err := s.source.SubjectVersion(ctx, ref.Subject, ref.Version)
+		if err != nil {
+			return 0, fmt.Errorf("referenced schema %s version %d: %w", ref.Subject, ref.Version, err)
+		}
+		if _, err := s.copySchema(ctx, referenced, topicMap); err != nil {
+			return 0, err
+		}
+		targetSubject := renameSubject(ref.Subject, topicMap)
+		registered, err := s.target.Lookup(ctx, targetSubject, *referenced)
+		if err != nil {
+			return 0, fmt.Errorf("referenced schema %s: %w", targetSubject, err)
+		}
+		references = append(references, SchemaReference{Name: ref.Name, Subject: targetSubject, Version: registered.Version})
+	}
+
+	targetSchema := *schema
+	targetSchema.References = references
+	targetID, err := s.target.Register(ctx, targetSubject, targetSchema)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	s.ids[key] = targetID
+	s.mu.Unlock()
+	return targetID, nil
+}
+
+// recordSubject returns the subject of a topic's keys or values under the
+// default topic name strategy.
+func recordSubject(topic string, isKey bool) string {
+	if isKey {
+		return topic + "-key"
+	}
+	return topic + "-value"
+}
+
+// renameSubject maps a subject named after a source topic ("<topic>-key",
+// "<topic>-value" or "<topic>-<record name>") to the target topic. The longest
+// matching source topic wins; other subjects are returned unchanged.
+func renameSubject(subject string, topicMap map[string]string) string {
+	bestSource := ""
+	for sourceTopic := range topicMap {
+		if strings.HasPrefix(subject, sourceTopic+"-") && len(sourceTopic) > len(bestSource) {
+			bestSource = sourceTopic
+		}
+	}
+	if bestSource == "" {
+		return subject
+	}
+	return topicMap[bestSource] + subject[len(bestSource):]
+}
+
+// RenameSubjectForTest exposes subject renaming for tests.
+func RenameSubjectForTest(subject string, topicMap map[string]string) string {
+	return renameSubject(subject, topicMap)
+}
+
+// syncSchemas copies the subjects of the mapped topics to the target registry.
+// Failures are logged; schemas are still copied on demand as records arrive.
+func (r *KafMirrorImpl) syncSchemas(ctx context.Context, topicMap map[string]string) {
+	if r.schemas == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	copied, err := r.schemas.SyncSubjects(ctx, topicMap)
+	if err != nil {
+		r.log("schemas").Warn("Schema sync incomplete: %v", err)
+	}
+	if copied > 0 {
+		r.log("schemas").Info("Copied %d schema versions to the target registry", copied)
+	}
+}
+
+// rewriteSchemaIDs returns the key and value of record with their schema IDs
+// remapped to the target registry. The record must not be produced when this
+// fails, as its bytes would point at the wrong schema on the target.
+func (r *KafMirrorImpl) rewriteSchemaIDs(record *kgo.Record, targetTopic string) ([]byte, []byte, error) {
+	if r.schemas == nil {
+		return record.Key, record.Value, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	key, err := r.schemas.Rewrite(ctx, record.Topic, targetTopic, true, record.Key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w for key: %w", errSchemaRewrite, err)
+	}
+	value, err := r.schemas.Rewrite(ctx, record.Topic, targetTopic, false, record.Value)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w for value: %w", errSchemaRewrite, err)
+	}
+	return key, value, nil
+}
+
+// SetSchemaReplicatorForTest sets the schema replicator used by handleRecord.
+func (r *KafMirrorImpl) SetSchemaReplicatorForTest(schemas *SchemaReplicator) {
+	r.schemas = schemas
+}
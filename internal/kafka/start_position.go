@@ -0,0 +1,228 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/pkg/logger"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// PartitionStartOffset is where a start position puts one source partition.
+type PartitionStartOffset struct {
+	Topic           string `json:"topic"`
+	Partition       int32  `json:"partition"`
+	CommittedOffset int64  `json:"committed_offset"` // -1 when the group has none
+	Offset          int64  `json:"offset"`
+	LogStartOffset  int64  `json:"log_start_offset"`
+	HighWaterMark   int64  `json:"high_water_mark"`
+	Lag             int64  `json:"lag"` // records left to mirror from Offset
+}
+
+// ResolveStartOffsets returns the offset position starts each partition of topics at,
+// checked against the partitions' log start offsets and high water marks. With the
+// offsets mode only the partitions listed for topics are returned and offsets outside
+// the available range are an error. CommittedOffset is left at -1.
+func ResolveStartOffsets(ctx context.Context, admin AdminClientAPI, position config.StartPosition, topics []string, now time.Time) ([]PartitionStartOffset, error) {
+	if !position.IsSet() {
+		return nil, fmt.Errorf("start position mode is required")
+	}
+	if err := position.Validate(); err != nil {
+		return nil, err
+	}
+	hwms, err := admin.GetTopicHighWaterMarks(ctx, topics)
+	if err != nil {
+		return nil, err
+	}
+	var atTime map[string][]OffsetInfo
+	if position.Mode == config.StartPositionTimestamp {
+		t, _ := position.Time(now)
+		if atTime, err = admin.GetOffsetsForTimestamp(ctx, topics, t.UnixMilli()); err != nil {
+			return nil, err
+		}
+	}
+
+	sorted := append([]string(nil), topics...)
+	sort.Strings(sorted)
+	var result []PartitionStartOffset
+	for _, topic := range sorted {
+		partitions, ok := hwms[topic]
+		if !ok {
+			return nil, fmt.Errorf("topic %s does not exist on the source cluster", topic)
+		}
+		known := make(map[int32]bool, len(partitions))
+		for _, partition := range partitions {
+			known[partition.Partition] = true
+			entry := PartitionStartOffset{
+				Topic:           topic,
+				Partition:       partition.Partition,
+				CommittedOffset: -1,
+				LogStartOffset:  partition.LogStartOffset,
+				HighWaterMark:   partition.HighWaterMark,
+			}
+			switch position.Mode {
+			case config.StartPositionEarliest:
+				entry.Offset = partition.LogStartOffset
+			case config.StartPositionLatest:
+				entry.Offset = partition.HighWaterMark
+			case config.StartPositionTimestamp:
+				entry.Offset = partition.HighWaterMark
+				for _, found := range atTime[topic] {
+					if found.Partition == partition.Partition && found.Offset >= 0 {
+						entry.Offset = found.Offset
+					}
+				}
+			case config.StartPositionOffsets:
+				offset, ok := position.Offsets[topic][partition.Partition]
+				if !ok {
+					continue
+				}
+				if offset < partition.LogStartOffset || offset > partition.HighWaterMark {
+					return nil, fmt.Errorf("offset %d for %s partition %d is outside the available range %d-%d",
+						offset, topic, partition.Partition, partition.LogStartOffset, partition.HighWaterMark)
+				}
+				entry.Offset = offset
+			}
+			entry.Lag = entry.HighWaterMark - entry.Offset
+			result = append(result, entry)
+		}
+		for partition := range position.Offsets[topic] {
+			if !known[partition] {
+				return nil, fmt.Errorf("%s has no partition %d", topic, partition)
+			}
+		}
+	}
+	return result, nil
+}
+
+// CommittedOffsets returns the offsets a consumer group has committed on topics, by
+// topic and partition. Partitions without a commit are left out.
+func CommittedOffsets(ctx context.Context, admin AdminClientAPI, group string, topics []string) (map[string]map[int32]int64, error) {
+	offsets, err := admin.GetConsumerGroupOffsets(ctx, group, topics)
+	if err != nil {
+		return nil, err
+	}
+	committed := make(map[string]map[int32]int64)
+	for topic, partitions := range offsets {
+		for _, partition := range partitions {
+			if partition.Offset < 0 {
+				continue
+			}
+			if committed[topic] == nil {
+				committed[topic] = make(map[int32]int64)
+			}
+			committed[topic][partition.Partition] = partition.Offset
+		}
+	}
+	return committed, nil
+}
+
+// startPositionFor returns the start position of a source topic: the one of the first
+// enabled mapping of the topic that sets one, otherwise the job's.
+func startPositionFor(cfg *config.Config, topic string) config.StartPosition {
+	for _, m := range cfg.Topics {
+		if !m.Enabled || !m.StartPosition.IsSet() {
+			continue
+		}
+		if m.Source == topic {
+			return m.StartPosition
+		}
+		if isRegex(m.Source) {
+			if matched, err := regexp.MatchString(m.Source, topic); err == nil && matched {
+				return m.StartPosition
+			}
+		}
+	}
+	return cfg.Replication.StartPosition
+}
+
+func hasStartPositions(cfg *config.Config) bool {
+	if cfg.Replication.StartPosition.IsSet() {
+		return true
+	}
+	for _, m := range cfg.Topics {
+		if m.Enabled && m.StartPosition.IsSet() {
+			return true
+		}
+	}
+	return false
+}
+
+// applyStartPositions commits the configured start offsets for the partitions of topics
+// the job's consumer group has no committed offset for, so the consumer starts there.
+// Partitions the group has consumed before are left alone.
+func applyStartPositions(cfg *config.Config, group string, topics []string) error {
+	if !hasStartPositions(cfg) || len(topics) == 0 {
+		return nil
+	}
+	admin, err := adminClientFactory(cfg.Clusters["source"])
+	if err != nil {
+		return fmt.Errorf("failed to create source admin client: %w", err)
+	}
+	defer admin.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	committed, err := CommittedOffsets(ctx, admin, group, topics)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	commits := make(map[string]map[int32]int64)
+	for _, topic := range topics {
+		position := startPositionFor(cfg, topic)
+		if !position.IsSet() {
+			continue
+		}
+		offsets, err := ResolveStartOffsets(ctx, admin, position, []string{topic}, now)
+		if err != nil {
+			return fmt.Errorf("start position of %s: %w", topic, err)
+		}
+		for _, offset := range offsets {
+			if _, ok := committed[topic][offset.Partition]; ok {
+				continue
+			}
+			if commits[topic] == nil {
+				commits[topic] = make(map[int32]int64)
+			}
+			commits[topic][offset.Partition] = offset.Offset
+		}
+		if len(commits[topic]) > 0 {
+			logger.Info("Starting %d partitions of %s from the %s start position, group=%s", len(commits[topic]), topic, position.Mode, group)
+		}
+	}
+	if len(commits) == 0 {
+		return nil
+	}
+	return admin.CommitConsumerGroupOffsets(ctx, group, commits)
+}
+
+// resetOffset is where the consumer starts partitions that have no committed offset
+// when it reaches them, such as topics discovered while the job runs.
+func resetOffset(position config.StartPosition, now time.Time) kgo.Offset {
+	switch position.Mode {
+	case config.StartPositionLatest:
+		return kgo.NewOffset().AtEnd()
+	case config.StartPositionTimestamp:
+		if t, err := position.Time(now); err == nil {
+			return kgo.NewOffset().AfterMilli(t.UnixMilli())
+		}
+	}
+	return kgo.NewOffset().AtStart()
+}
@@ -0,0 +1,353 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/pkg/logger"
+	"path"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// TopicConfigPolicy decides which source topic configs a target topic should carry.
+type TopicConfigPolicy struct {
+	Mode      string
+	Include   []string
+	Exclude   []string
+	Overrides map[string]string
+}
+
+// NewTopicConfigPolicy builds a policy from the topic config sync settings,
+// falling back to sync mode and the default include list.
+func NewTopicConfigPolicy(cfg config.TopicConfigSyncConfig) TopicConfigPolicy {
+	policy := TopicConfigPolicy{
+		Mode:      cfg.Mode,
+		Include:   cfg.Include,
+		Exclude:   cfg.Exclude,
+		Overrides: cfg.Overrides,
+	}
+	if policy.Mode == "" {
+		policy.Mode = config.TopicConfigSyncModeSync
+	}
+	if len(policy.Include) == 0 {
+		policy.Include = config.DefaultTopicConfigSyncInclude
+	}
+	return policy
+}
+
+// Enabled reports whether configs are copied to target topics at all.
+func (p TopicConfigPolicy) Enabled() bool {
+	return p.Mode != config.TopicConfigSyncModeOff
+}
+
+// Allowed reports whether a config key passes the include and exclude lists.
+func (p TopicConfigPolicy) Allowed(key string) bool {
+	return !matchesAny(p.Exclude, key) && matchesAny(p.Include, key)
+}
+
+// Desired returns the configs a target topic should carry: the allowed source
+// configs with the overrides applied on top. min.insync.replicas is capped at the
+// target replication factor so acks=all writes keep working on smaller clusters.
+func (p TopicConfigPolicy) Desired(source map[string]string, targetReplicationFactor int16) map[string]string {
+	desired := make(map[string]string)
+	for key, value := range source {
+		if p.Allowed(key) {
+			desired[key] = value
+		}
+	}
+	for key, value := range p.Overrides {
+		desired[key] = value
+	}
+	if value, ok := desired["min.insync.replicas"]; ok && targetReplicationFactor > 0 {
+		if minISR, err := strconv.Atoi(value); err == nil && minISR > int(targetReplicationFactor) {
+			desired["min.insync.replicas"] = strconv.Itoa(int(targetReplicationFactor))
+		}
+	}
+	return desired
+}
+
+// Diff lists the configs of a target topic that differ from the desired state.
+func (p TopicConfigPolicy) Diff(source, target map[string]string, targetReplicationFactor int16) []TopicConfigDrift {
+	desired := p.Desired(source, targetReplicationFactor)
+	drift := make([]TopicConfigDrift, 0)
+	for key, value := range desired {
+		if current, ok := target[key]; ok && current == value {
+			continue
+		}
+		_, override := p.Overrides[key]
+		drift = append(drift, TopicConfigDrift{
+			Key:          key,
+			SourceValue:  source[key],
+			TargetValue:  target[key],
+			DesiredValue: value,
+			Override:     override,
+		})
+	}
+	sort.Slice(drift, func(i, j int) bool { return drift[i].Key < drift[j].Key })
+	return drift
+}
+
+func matchesAny(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// TopicConfigDrift is a single config whose target value differs from the desired one.
+type TopicConfigDrift struct {
+	Key          string `json:"key"`
+	SourceValue  string `json:"source_value"`
+	TargetValue  string `json:"target_value"`
+	DesiredValue string `json:"desired_value"`
+	Override     bool   `json:"override"` // desired value comes from an override
+}
+
+// TopicConfigStatus is the config drift of one mapped topic pair.
+type TopicConfigStatus struct {
+	SourceTopic string             `json:"source_topic"`
+	TargetTopic string             `json:"target_topic"`
+	Drift       []TopicConfigDrift `json:"drift"`
+	Applied     bool               `json:"applied"`
+	Error       string             `json:"error,omitempty"`
+}
+
+// TopicConfigReport is the result of comparing or syncing the topic configs of a job.
+type TopicConfigReport struct {
+	JobID          string              `json:"job_id"`
+	Mode           string              `json:"mode"`
+	DryRun         bool                `json:"dry_run"`
+	CheckedAt      time.Time           `json:"checked_at"`
+	DriftedTopics  int                 `json:"drifted_topics"`
+	DriftedConfigs int                 `json:"drifted_configs"`
+	AppliedTopics  int                 `json:"applied_topics"`
+	Topics         []TopicConfigStatus `json:"topics"`
+}
+
+// topicConfigAction is what reconcileTopicConfigs does with the drift it finds.
+type topicConfigAction int
+
+const (
+	topicConfigReport   topicConfigAction = iota // only report drift
+	topicConfigValidate                          // let the target broker validate the fix
+	topicConfigApply                             // fix the drift
+)
+
+// CheckTopicConfigDrift compares the configs of every mapped target topic of a job
+// with its source topic without changing anything.
+func CheckTopicConfigDrift(ctx context.Context, cfg *config.Config) (*TopicConfigReport, error) {
+	return runTopicConfigSync(ctx, cfg, topicConfigReport)
+}
+
+// SyncTopicConfigs brings the configs of every mapped target topic of a job in line
+// with its source topic. With dryRun the target broker only validates the changes.
+func SyncTopicConfigs(ctx context.Context, cfg *config.Config, dryRun bool) (*TopicConfigReport, error) {
+	if dryRun {
+		return runTopicConfigSync(ctx, cfg, topicConfigValidate)
+	}
+	return runTopicConfigSync(ctx, cfg, topicConfigApply)
+}
+
+func runTopicConfigSync(ctx context.Context, cfg *config.Config, action topicConfigAction) (*TopicConfigReport, error) {
+	_, topicMap, _, err := resolveTopicMappings(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceAdmin, err := adminClientFactory(cfg.Clusters["source"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create source admin client: %w", err)
+	}
+	defer sourceAdmin.Close()
+
+	targetAdmin, err := adminClientFactory(cfg.Clusters["target"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create target admin client: %w", err)
+	}
+	defer targetAdmin.Close()
+
+	policy := NewTopicConfigPolicy(cfg.Replication.TopicConfigSync)
+	report, err := reconcileTopicConfigs(ctx, sourceAdmin, targetAdmin, topicMap, policy, action)
+	if err != nil {
+		return nil, err
+	}
+	report.JobID = cfg.Replication.JobID
+	return report, nil
+}
+
+// reconcileTopicConfigs compares the configs of every topic pair in topicMap and,
+// depending on action, validates or applies the desired values on the target.
+// Per-topic failures are recorded in the report rather than returned.
+func reconcileTopicConfigs(ctx context.Context, sourceAdmin, targetAdmin AdminClientAPI, topicMap map[string]string, policy TopicConfigPolicy, action topicConfigAction) (*TopicConfigReport, error) {
+	report := &TopicConfigReport{
+		Mode:      policy.Mode,
+		DryRun:    action != topicConfigApply,
+		CheckedAt: time.Now(),
+		Topics:    make([]TopicConfigStatus, 0, len(topicMap)),
+	}
+	if len(topicMap) == 0 {
+		return report, nil
+	}
+
+	sourceTopics := make([]string, 0, len(topicMap))
+	targetTopics := make([]string, 0, len(topicMap))
+	for sourceTopic, targetTopic := range topicMap {
+		sourceTopics = append(sourceTopics, sourceTopic)
+		targetTopics = append(targetTopics, targetTopic)
+	}
+	sort.Strings(sourceTopics)
+
+	sourceConfigs, err := sourceAdmin.DescribeTopicConfigs(ctx, sourceTopics...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe source topic configs: %w", err)
+	}
+	targetConfigs, err := targetAdmin.DescribeTopicConfigs(ctx, targetTopics...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe target topic configs: %w", err)
+	}
+	targetInfo, err := targetAdmin.GetClusterInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target cluster info: %w", err)
+	}
+
+	for _, sourceTopic := range sourceTopics {
+		targetTopic := topicMap[sourceTopic]
+		status := TopicConfigStatus{SourceTopic: sourceTopic, TargetTopic: targetTopic, Drift: []TopicConfigDrift{}}
+
+		source, sourceOK := sourceConfigs[sourceTopic]
+		target, targetOK := targetConfigs[targetTopic]
+		switch {
+		case !sourceOK:
+			status.Error = fmt.Sprintf("source topic %s not found", sourceTopic)
+		case !targetOK:
+			status.Error = fmt.Sprintf("target topic %s not found", targetTopic)
+		default:
+			status.Drift = policy.Diff(source, target, targetInfo.Topics[targetTopic].ReplicationFactor)
+		}
+
+		if len(status.Drift) > 0 {
+			report.DriftedTopics++
+			report.DriftedConfigs += len(status.Drift)
+
+			if action != topicConfigReport {
+				changes := make(map[string]string, len(status.Drift))
+				for _, d := range status.Drift {
+					changes[d.Key] = d.DesiredValue
+				}
+				if err := targetAdmin.AlterTopicConfigs(ctx, targetTopic, changes, action == topicConfigValidate); err != nil {
+					status.Error = err.Error()
+				} else if action == topicConfigApply {
+					status.Applied = true
+					report.AppliedTopics++
+				}
+			}
+		}
+		report.Topics = append(report.Topics, status)
+	}
+	return report, nil
+}
+
+// topicCreateConfigs returns the configs a new target topic should be created with.
+// Failing to read the source configs is not fatal; the topic is then created with
+// broker defaults and the periodic sync catches up later.
+func topicCreateConfigs(ctx context.Context, sourceAdmin AdminClientAPI, sourceTopic string, policy TopicConfigPolicy, replicationFactor int16) map[string]string {
+	if !policy.Enabled() {
+		return nil
+	}
+	configs, err := sourceAdmin.DescribeTopicConfigs(ctx, sourceTopic)
+	if err != nil {
+		logger.Warn("Failed to read configs of source topic %s, creating target with broker defaults: %v", sourceTopic, err)
+		return nil
+	}
+	return policy.Desired(configs[sourceTopic], replicationFactor)
+}
+
+// topicConfigSyncInterval is how often a running job checks its topic configs for
+// drift, 0 when the periodic check is disabled.
+func topicConfigSyncInterval(cfg *config.Config) time.Duration {
+	if cfg.Replication.TopicConfigSync.Interval == "" {
+		return 10 * time.Minute
+	}
+	interval, err := time.ParseDuration(cfg.Replication.TopicConfigSync.Interval)
+	if err != nil || interval < 0 {
+		return 10 * time.Minute
+	}
+	return interval
+}
+
+// syncTopicConfigsLoop periodically reconciles the configs of the job's topics.
+// In dry_run mode drift is only logged.
+func (r *KafMirrorImpl) syncTopicConfigsLoop(ctx context.Context) {
+	ticker := time.NewTicker(r.topicConfigInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.syncTopicConfigs(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *KafMirrorImpl) syncTopicConfigs(ctx context.Context) {
+	log := r.log("topic-config")
+
+	sourceAdmin, err := adminClientFactory(r.sourceCfg)
+	if err != nil {
+		log.Error("Failed to create source admin client for topic config sync: %v", err)
+		return
+	}
+	defer sourceAdmin.Close()
+
+	targetAdmin, err := adminClientFactory(r.targetCfg)
+	if err != nil {
+		log.Error("Failed to create target admin client for topic config sync: %v", err)
+		return
+	}
+	defer targetAdmin.Close()
+
+	topicMap := r.snapshotTopicMap()
+	action := topicConfigApply
+	if r.topicConfigs.Mode == config.TopicConfigSyncModeDryRun {
+		action = topicConfigReport
+	}
+	report, err := reconcileTopicConfigs(ctx, sourceAdmin, targetAdmin, topicMap, r.topicConfigs, action)
+	if err != nil {
+		log.Error("Topic config sync failed: %v", err)
+		return
+	}
+	logTopicConfigReport(report, log)
+}
+
+func logTopicConfigReport(report *TopicConfigReport, log *logger.Entry) {
+	for _, status := range report.Topics {
+		topicLog := log.With(logger.Topic(status.TargetTopic))
+		if status.Error != "" {
+			topicLog.Warn("Topic config sync of %s -> %s failed: %s", status.SourceTopic, status.TargetTopic, status.Error)
+		}
+		for _, d := range status.Drift {
+			if status.Applied {
+				topicLog.Info("Set %s on target topic %s from %q to %q", d.Key, status.TargetTopic, d.TargetValue, d.DesiredValue)
+			} else {
+				topicLog.Warn("Target topic %s drifted: %s is %q, expected %q", status.TargetTopic, d.Key, d.TargetValue, d.DesiredValue)
+			}
+		}
+	}
+}
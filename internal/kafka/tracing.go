@@ -0,0 +1,127 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"math/rand/v2"
+	"strconv"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var replicationTracer = otel.Tracer("kaf-mirror/replication")
+
+// RecordHeaderCarrier adapts the headers of a Kafka record to OpenTelemetry
+// propagation, so W3C traceparent and tracestate travel with the record.
+type RecordHeaderCarrier struct {
+	Record *kgo.Record
+}
+
+// Get returns the value of the first header with the given key.
+func (c RecordHeaderCarrier) Get(key string) string {
+	for _, h := range c.Record.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set replaces the header with the given key, or appends it.
+func (c RecordHeaderCarrier) Set(key, value string) {
+	for i, h := range c.Record.Headers {
+		if h.Key == key {
+			c.Record.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	c.Record.Headers = append(c.Record.Headers, kgo.RecordHeader{Key: key, Value: []byte(value)})
+}
+
+// Keys lists the header keys of the record.
+func (c RecordHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.Record.Headers))
+	for _, h := range c.Record.Headers {
+		keys = append(keys, h.Key)
+	}
+	return keys
+}
+
+// sampleRecord decides whether a record is traced.
+func (r *KafMirrorImpl) sampleRecord() bool {
+	switch {
+	case r.traceSampleRatio <= 0:
+		return false
+	case r.traceSampleRatio >= 1:
+		return true
+	default:
+		return rand.Float64() < r.traceSampleRatio
+	}
+}
+
+// traceRecord starts a consumer span for a sampled source record and a producer
+// span for its copy, then writes the producer span's traceparent into out's headers.
+// The consumer span continues the trace of the source record when it carries one,
+// so an upstream producer, the mirror and downstream consumers share a trace.
+// The returned function ends both spans once the target acknowledged the copy;
+// it is nil when the record is not sampled.
+func (r *KafMirrorImpl) traceRecord(in, out *kgo.Record) func(produced *kgo.Record, err error) {
+	if !r.sampleRecord() {
+		return nil
+	}
+	propagator := otel.GetTextMapPropagator()
+	parent := propagator.Extract(context.Background(), RecordHeaderCarrier{Record: in})
+
+	ctx, consumeSpan := replicationTracer.Start(parent, in.Topic+" receive",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.operation.type", "receive"),
+			attribute.String("messaging.destination.name", in.Topic),
+			attribute.String("messaging.destination.partition.id", strconv.Itoa(int(in.Partition))),
+			attribute.Int64("messaging.kafka.offset", in.Offset),
+			attribute.String("kaf_mirror.job_id", r.jobID),
+		))
+	ctx, produceSpan := replicationTracer.Start(ctx, out.Topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.operation.type", "publish"),
+			attribute.String("messaging.destination.name", out.Topic),
+			attribute.String("kaf_mirror.job_id", r.jobID),
+		))
+
+	// The copy gets its own headers so the source record is left untouched;
+	// they start from out's, which may already carry the provenance header.
+	out.Headers = append([]kgo.RecordHeader(nil), out.Headers...)
+	propagator.Inject(ctx, RecordHeaderCarrier{Record: out})
+
+	return func(produced *kgo.Record, err error) {
+		if err != nil {
+			produceSpan.RecordError(err)
+			produceSpan.SetStatus(codes.Error, err.Error())
+			consumeSpan.SetStatus(codes.Error, "replication failed")
+		} else if produced != nil {
+			produceSpan.SetAttributes(
+				attribute.String("messaging.destination.partition.id", strconv.Itoa(int(produced.Partition))),
+				attribute.Int64("messaging.kafka.offset", produced.Offset),
+			)
+		}
+		produceSpan.End()
+		consumeSpan.End()
+	}
+}
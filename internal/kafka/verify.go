@@ -0,0 +1,472 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	// verifyWindowSize is the number of source offsets compared at once. Sampling
+	// picks whole windows, so sampled records stay contiguous.
+	verifyWindowSize = 1000
+	// verifyWindowSlack is how many target offsets are read on either side of a
+	// window, so records shifted by duplicates or losses are still found.
+	verifyWindowSlack = 100
+	// verifyOffsetSamples caps the offsets listed per partition and problem.
+	verifyOffsetSamples = 10
+	// verifyReadIdleTimeout ends a range read that stops returning records, as
+	// happens when the range ends in compacted offsets or transaction markers.
+	verifyReadIdleTimeout = 5 * time.Second
+)
+
+// RecordReader reads bounded offset ranges of single partitions.
+type RecordReader interface {
+	ReadRange(ctx context.Context, topic string, partition int32, start, end int64) ([]*kgo.Record, error)
+	Close()
+}
+
+var recordReaderFactory = func(cfg config.ClusterConfig, replicationCfg config.ReplicationConfig, jobID string) (RecordReader, error) {
+	return NewPartitionConsumer(cfg, replicationCfg, jobID, nil)
+}
+
+// SetRecordReaderFactoryForTest replaces the record reader factory and returns a restore func.
+func SetRecordReaderFactoryForTest(factory func(config.ClusterConfig, config.ReplicationConfig, string) (RecordReader, error)) func() {
+	previous := recordReaderFactory
+	recordReaderFactory = factory
+	return func() {
+		recordReaderFactory = previous
+	}
+}
+
+// ReadRange returns the records of a partition from start up to end, exclusive.
+// The consumer must not use a consumer group. Offsets without records, such as
+// compacted ones, are skipped.
+func (c *Consumer) ReadRange(ctx context.Context, topic string, partition int32, start, end int64) ([]*kgo.Record, error) {
+	if start >= end {
+		return nil, nil
+	}
+	c.Client.AddConsumePartitions(map[string]map[int32]kgo.Offset{topic: {partition: kgo.NewOffset().At(start)}})
+	defer c.Client.RemoveConsumePartitions(map[string][]int32{topic: {partition}})
+
+	var records []*kgo.Record
+	next := start
+	for next < end {
+		pollCtx, cancel := context.WithTimeout(ctx, verifyReadIdleTimeout)
+		fetches := c.Client.PollFetches(pollCtx)
+		cancel()
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if fetches.IsClientClosed() {
+			return nil, kgo.ErrClientClosed
+		}
+
+		var fetchErr error
+		idle := false
+		fetches.EachError(func(t string, p int32, err error) {
+			if errors.Is(err, context.DeadlineExceeded) {
+				idle = true
+			} else if fetchErr == nil {
+				fetchErr = fmt.Errorf("failed to read %s partition %d: %w", t, p, err)
+			}
+		})
+		if fetchErr != nil {
+			return nil, fetchErr
+		}
+		caughtUp := false
+		fetches.EachPartition(func(p kgo.FetchTopicPartition) {
+			for _, record := range p.Records {
+				if record.Topic != topic || record.Partition != partition || record.Offset < next {
+					continue
+				}
+				if record.Offset < end {
+					records = append(records, record)
+				}
+				next = record.Offset + 1
+			}
+			if p.Topic == topic && p.Partition == partition && p.HighWatermark <= next {
+				caughtUp = true
+			}
+		})
+		if idle || caughtUp {
+			break
+		}
+	}
+	return records, nil
+}
+
+// VerifyContent compares the records of the job's mapped topics on both clusters
+// by content. Every source partition is compared with the target partition of the
+// same number over the offset range both clusters hold, translated by the job's
+// committed source offsets, or by the distance from the end of the partition when
+// the job has none. sampleRate is the share of each range compared, in windows of
+// contiguous records; 1 compares everything.
+func VerifyContent(ctx context.Context, cfg *config.Config, sampleRate float64) ([]database.PartitionVerification, error) {
+	if sampleRate <= 0 || sampleRate > 1 {
+		return nil, fmt.Errorf("sample rate must be greater than 0 and at most 1, got %g", sampleRate)
+	}
+	clusters, err := OpenJobClusters(cfg)
+	if err != nil {
+		return nil, err
+	}
+	defer clusters.Close()
+
+	source, err := recordReaderFactory(cfg.Clusters["source"], cfg.Replication, cfg.Replication.JobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create source reader: %w", err)
+	}
+	defer source.Close()
+	target, err := recordReaderFactory(cfg.Clusters["target"], cfg.Replication, cfg.Replication.JobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create target reader: %w", err)
+	}
+	defer target.Close()
+
+	verifier := &contentVerifier{
+		clusters:   clusters,
+		source:     source,
+		target:     target,
+		sampleRate: sampleRate,
+		hasher:     newRecordHasher(cfg),
+	}
+	return verifier.verify(ctx)
+}
+
+type contentVerifier struct {
+	clusters       *JobClusters
+	source, target RecordReader
+	sampleRate     float64
+	hasher         recordHasher
+}
+
+func (v *contentVerifier) verify(ctx context.Context) ([]database.PartitionVerification, error) {
+	sourceTopics := v.clusters.sourceTopics()
+	sourceOffsets, err := v.clusters.Source.GetTopicHighWaterMarks(ctx, sourceTopics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source offsets: %w", err)
+	}
+	targetOffsets, err := v.clusters.Target.GetTopicHighWaterMarks(ctx, v.clusters.targetTopics())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target offsets: %w", err)
+	}
+	committed, err := v.clusters.Source.GetConsumerGroupOffsets(ctx, JobConsumerGroup(v.clusters.JobID), sourceTopics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get committed offsets of the job: %w", err)
+	}
+
+	results := make([]database.PartitionVerification, 0)
+	for _, sourceTopic := range sourceTopics {
+		targetTopic := v.clusters.TopicMap[sourceTopic]
+		targetPartitions := make(map[int32]OffsetInfo)
+		for _, offset := range targetOffsets[targetTopic] {
+			targetPartitions[offset.Partition] = offset
+		}
+		committedOffsets := make(map[int32]int64)
+		for _, offset := range committed[sourceTopic] {
+			if offset.Offset >= 0 {
+				committedOffsets[offset.Partition] = offset.Offset
+			}
+		}
+
+		partitions := append([]OffsetInfo(nil), sourceOffsets[sourceTopic]...)
+		sort.Slice(partitions, func(i, j int) bool { return partitions[i].Partition < partitions[j].Partition })
+		for _, src := range partitions {
+			result := database.PartitionVerification{SourceTopic: sourceTopic, TargetTopic: targetTopic, Partition: src.Partition}
+			tgt, ok := targetPartitions[src.Partition]
+			if !ok {
+				// Records of partitions the target lacks are spread by the partitioner.
+				result.Skipped = "target topic has no partition with this number"
+				results = append(results, result)
+				continue
+			}
+			delta := tgt.HighWaterMark - src.HighWaterMark
+			if offset, ok := committedOffsets[src.Partition]; ok {
+				delta = tgt.HighWaterMark - offset
+			}
+			if err := v.verifyPartition(ctx, &result, src, tgt, delta); err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				result.Error = err.Error()
+			}
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// verifyPartition compares the sampled windows of a source partition with the
+// target. delta translates source to target offsets and is re-anchored on the
+// last matched record of every window.
+func (v *contentVerifier) verifyPartition(ctx context.Context, result *database.PartitionVerification, src, tgt OffsetInfo, delta int64) error {
+	start := max(src.LogStartOffset, tgt.LogStartOffset-delta)
+	end := min(src.HighWaterMark, tgt.HighWaterMark-delta)
+	result.OffsetDelta = delta
+	if start >= end {
+		result.Skipped = "no records held by both clusters"
+		return nil
+	}
+	result.StartOffset, result.EndOffset = start, end
+
+	cmp := &partitionComparison{result: result, claimed: make(map[int64]bool), sourceDigest: newDigest(), targetDigest: newDigest()}
+	windows := sampleWindows(start, end, v.sampleRate)
+	for i, window := range windows {
+		sourceRecords, err := v.source.ReadRange(ctx, result.SourceTopic, result.Partition, window.start, window.end)
+		if err != nil {
+			return err
+		}
+		targetStart := max(tgt.LogStartOffset, window.start+delta-verifyWindowSlack)
+		targetEnd := min(tgt.HighWaterMark, window.end+delta+verifyWindowSlack)
+		targetRecords, err := v.target.ReadRange(ctx, result.TargetTopic, result.Partition, targetStart, targetEnd)
+		if err != nil {
+			return err
+		}
+		delta = cmp.compareWindow(v.hasher, sourceRecords, targetRecords, window, delta, i == len(windows)-1)
+	}
+	result.SourceDigest = cmp.sourceDigest.String()
+	result.TargetDigest = cmp.targetDigest.String()
+	return nil
+}
+
+type offsetRange struct {
+	start, end int64
+}
+
+// sampleWindows splits [start, end) into windows of verifyWindowSize offsets and
+// keeps every window for a sample rate of 1, or evenly spaced windows otherwise.
+// The newest window is always kept.
+func sampleWindows(start, end int64, sampleRate float64) []offsetRange {
+	count := (end - start + verifyWindowSize - 1) / verifyWindowSize
+	stride := int64(1)
+	if sampleRate < 1 {
+		stride = max(1, int64(math.Round(1/sampleRate)))
+	}
+	var windows []offsetRange
+	for i := int64(0); i < count; i++ {
+		if (count-1-i)%stride != 0 {
+			continue
+		}
+		windowStart := start + i*verifyWindowSize
+		windows = append(windows, offsetRange{start: windowStart, end: min(windowStart+verifyWindowSize, end)})
+	}
+	return windows
+}
+
+type hashedRecord struct {
+	offset int64
+	hash   uint64
+}
+
+// partitionComparison accumulates the results of the windows of one partition.
+type partitionComparison struct {
+	result       *database.PartitionVerification
+	claimed      map[int64]bool // target offsets already matched or counted
+	sourceDigest *rollingDigest
+	targetDigest *rollingDigest
+}
+
+// compareWindow matches source records to target records by content, in order.
+// Unmatched target copies of matched records are duplicates; an unmatched source
+// record is mismatched when the target holds a different record at its
+// translated offset, and missing otherwise. Target records without a source
+// counterpart count as mismatched. It returns the translation after the window.
+func (p *partitionComparison) compareWindow(hasher recordHasher, sourceRecords, targetRecords []*kgo.Record, window offsetRange, delta int64, final bool) int64 {
+	result := p.result
+	coreStart, coreEnd := window.start+delta, window.end+delta
+
+	targets := make([]hashedRecord, 0, len(targetRecords))
+	byOffset := make(map[int64]hashedRecord, len(targetRecords))
+	byHash := make(map[uint64][]hashedRecord)
+	for _, record := range targetRecords {
+		if p.claimed[record.Offset] {
+			continue
+		}
+		target := hashedRecord{offset: record.Offset, hash: hasher.hash(record)}
+		targets = append(targets, target)
+		byOffset[target.offset] = target
+		byHash[target.hash] = append(byHash[target.hash], target)
+		if target.offset >= coreStart && target.offset < coreEnd {
+			p.targetDigest.add(target.hash)
+		}
+	}
+
+	type unmatchedRecord struct {
+		offset, delta int64
+	}
+	var unmatched []unmatchedRecord
+	matchedHashes := make(map[uint64]bool)
+	running := delta
+	lastClaimed := int64(-1)
+	for _, record := range sourceRecords {
+		source := hashedRecord{offset: record.Offset, hash: hasher.hash(record)}
+		result.RecordsCompared++
+		p.sourceDigest.add(source.hash)
+
+		matched := false
+		candidates := byHash[source.hash]
+		for len(candidates) > 0 {
+			target := candidates[0]
+			candidates = candidates[1:]
+			if p.claimed[target.offset] {
+				continue
+			}
+			p.claimed[target.offset] = true
+			lastClaimed = max(lastClaimed, target.offset)
+			running = target.offset - source.offset
+			matched = true
+			break
+		}
+		byHash[source.hash] = candidates
+		if matched {
+			result.Matched++
+			matchedHashes[source.hash] = true
+		} else {
+			unmatched = append(unmatched, unmatchedRecord{offset: source.offset, delta: running})
+		}
+	}
+
+	for _, target := range targets {
+		if !p.claimed[target.offset] && matchedHashes[target.hash] {
+			p.claimed[target.offset] = true
+			result.Duplicates++
+			result.DuplicateOffsets = appendOffsetSample(result.DuplicateOffsets, target.offset)
+		}
+	}
+	for _, record := range unmatched {
+		target, ok := byOffset[record.offset+record.delta]
+		if ok && !p.claimed[target.offset] {
+			p.claimed[target.offset] = true
+			lastClaimed = max(lastClaimed, target.offset)
+			result.Mismatched++
+			result.MismatchedOffsets = appendOffsetSample(result.MismatchedOffsets, record.offset)
+			continue
+		}
+		result.Missing++
+		result.MissingOffsets = appendOffsetSample(result.MissingOffsets, record.offset)
+	}
+	// Unclaimed records after the last claimed one may belong to the next window.
+	for _, target := range targets {
+		if p.claimed[target.offset] || target.offset < coreStart || target.offset >= coreEnd {
+			continue
+		}
+		if !final && target.offset > lastClaimed {
+			continue
+		}
+		p.claimed[target.offset] = true
+		result.Mismatched++
+		result.MismatchedOffsets = appendOffsetSample(result.MismatchedOffsets, target.offset-running)
+	}
+
+	for offset := range p.claimed {
+		if offset < window.end+running-verifyWindowSlack {
+			delete(p.claimed, offset)
+		}
+	}
+	return running
+}
+
+func appendOffsetSample(offsets []int64, offset int64) []int64 {
+	if len(offsets) >= verifyOffsetSamples {
+		return offsets
+	}
+	return append(offsets, offset)
+}
+
+// recordHasher hashes the parts of a record that the mirror copies unchanged:
+// key, value, headers and timestamp.
+type recordHasher struct {
+	// skipHeaders are the trace context and provenance headers the mirror sets
+	// on the copies.
+	skipHeaders map[string]bool
+	// ignoreSchemaIDs leaves out the schema ID of Schema Registry framed payloads,
+	// which the mirror rewrites when it replicates schemas.
+	ignoreSchemaIDs bool
+}
+
+func newRecordHasher(cfg *config.Config) recordHasher {
+	h := recordHasher{skipHeaders: make(map[string]bool)}
+	for _, field := range otel.GetTextMapPropagator().Fields() {
+		h.skipHeaders[field] = true
+	}
+	h.skipHeaders[ProvenanceHeader] = true
+	h.ignoreSchemaIDs = NewSchemaReplicator(cfg.Clusters["source"].SchemaRegistry, cfg.Clusters["target"].SchemaRegistry) != nil
+	return h
+}
+
+func (h recordHasher) hash(record *kgo.Record) uint64 {
+	d := fnv.New64a()
+	h.writePayload(d, record.Key)
+	h.writePayload(d, record.Value)
+	for _, header := range record.Headers {
+		if h.skipHeaders[header.Key] {
+			continue
+		}
+		writeHashBytes(d, []byte(header.Key))
+		writeHashBytes(d, header.Value)
+	}
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(record.Timestamp.UnixMilli()))
+	d.Write(ts[:])
+	return d.Sum64()
+}
+
+func (h recordHasher) writePayload(d hash.Hash64, data []byte) {
+	if h.ignoreSchemaIDs && len(data) >= wireFormatHeaderLen && data[0] == wireFormatMagic {
+		framed := make([]byte, 0, len(data)-wireFormatHeaderLen+1)
+		framed = append(framed, wireFormatMagic)
+		data = append(framed, data[wireFormatHeaderLen:]...)
+	}
+	writeHashBytes(d, data)
+}
+
+// writeHashBytes writes data with its length, keeping null apart from empty.
+func writeHashBytes(d hash.Hash64, data []byte) {
+	var length [8]byte
+	if data == nil {
+		binary.BigEndian.PutUint64(length[:], math.MaxUint64)
+	} else {
+		binary.BigEndian.PutUint64(length[:], uint64(len(data)))
+	}
+	d.Write(length[:])
+	d.Write(data)
+}
+
+// rollingDigest folds record hashes in order into one value.
+type rollingDigest struct {
+	sum uint64
+}
+
+func newDigest() *rollingDigest {
+	return &rollingDigest{sum: 14695981039346656037} // FNV-1a offset basis
+}
+
+func (r *rollingDigest) add(h uint64) {
+	r.sum = (r.sum ^ h) * 1099511628211 // FNV-1a prime
+}
+
+func (r *rollingDigest) String() string {
+	return fmt.Sprintf("%016x", r.sum)
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+)
+
+// SyncJobACLs copies the ACLs, and optionally the client quotas, the job's clients
+// need from its source to its target cluster. With dryRun nothing is changed. An
+// applied sync that changed the target is recorded as an operational event and in
+// a new inventory snapshot.
+func (jm *JobManager) SyncJobACLs(jobID string, dryRun bool, initiator string) (*kafka.ACLSyncReport, error) {
+	jobConfig, err := jm.loadJobConfig(jobID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), topicConfigTimeout)
+	defer cancel()
+	report, err := kafka.SyncACLs(ctx, jobConfig, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun || (report.CreatedACLs == 0 && report.AppliedQuotas == 0) {
+		return report, nil
+	}
+
+	event := &database.OperationalEvent{
+		EventType: "acl_sync",
+		Initiator: initiator,
+		Details: fmt.Sprintf("Job %s: created %d ACLs and updated %d client quotas on target cluster, %d skipped, %d failed",
+			jobID, report.CreatedACLs, report.AppliedQuotas, len(report.Skipped), len(report.Errors)),
+	}
+	if err := database.CreateOperationalEvent(jm.Db, event); err != nil {
+		jobLog(jobID).Error("Failed to record ACL sync for job %s: %v", jobID, err)
+	}
+	go jm.CreateInventorySnapshot(jobID, "manual")
+	return report, nil
+}
@@ -0,0 +1,554 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Backfill statuses reported by BackfillStatus.
+const (
+	BackfillPaused    = "paused"
+	BackfillRunning   = "running"
+	BackfillCompleted = "completed"
+	BackfillFailed    = "failed"
+)
+
+// BackfillRequest copies the records between two positions of the topics of a job
+// again, in a new backfill job.
+type BackfillRequest struct {
+	Name string `json:"name,omitempty"`
+	// Topics limits the backfill to these source topics. Empty means every mapped
+	// topic, or the topics named by explicit offsets.
+	Topics []string             `json:"topics,omitempty"`
+	From   config.StartPosition `json:"from"`
+	// To is the exclusive end of the ranges, the current high water marks when unset.
+	To config.StartPosition `json:"to"`
+}
+
+// BackfillFromGapsRequest copies the offsets of a job's mirror gaps again, in a new
+// backfill job.
+type BackfillFromGapsRequest struct {
+	Name string `json:"name,omitempty"`
+	// GapIDs are the gaps to close. Empty means every unresolved gap of the job.
+	GapIDs []int `json:"gap_ids,omitempty"`
+}
+
+// BackfillStatus is the progress of a backfill job.
+type BackfillStatus struct {
+	JobID           string                   `json:"job_id"`
+	Name            string                   `json:"name"`
+	Status          string                   `json:"status"`
+	ProgressPercent float64                  `json:"progress_percent"`
+	CopiedOffsets   int64                    `json:"copied_offsets"`
+	TotalOffsets    int64                    `json:"total_offsets"`
+	Ranges          []database.BackfillRange `json:"ranges"`
+}
+
+// ErrNotBackfillJob is returned when the backfill status of a continuous job is requested.
+var ErrNotBackfillJob = errors.New("job is not a backfill job")
+
+// CreateBackfillJob creates a paused backfill job with the clusters, settings and
+// mappings of a job that copies the records between req.From and req.To. Both positions
+// are resolved against the source partitions now; partitions with nothing between them
+// are left out.
+func (jm *JobManager) CreateBackfillJob(jobID string, req BackfillRequest, initiator string) (*BackfillStatus, error) {
+	if !req.From.IsSet() {
+		return nil, fmt.Errorf("backfill start position is required")
+	}
+	if req.From.Mode == config.StartPositionLatest {
+		return nil, fmt.Errorf("a backfill cannot start at the latest offsets")
+	}
+	to := req.To
+	if !to.IsSet() {
+		to = config.StartPosition{Mode: config.StartPositionLatest}
+	}
+	if to.Mode == config.StartPositionEarliest {
+		return nil, fmt.Errorf("a backfill cannot end at the earliest offsets")
+	}
+
+	job, err := database.GetJob(jm.Db, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("job %s not found: %v", jobID, err)
+	}
+	if job.JobType == database.JobTypeBackfill {
+		return nil, fmt.Errorf("job %s is a backfill job", jobID)
+	}
+	jobConfig, err := jm.loadJobConfig(jobID)
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := kafka.OpenJobClusters(jobConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer clusters.Close()
+
+	topics, err := offsetResetTopics(OffsetResetRequest{Position: req.From, Topics: req.Topics}, clusters.TopicMap)
+	if err != nil {
+		return nil, err
+	}
+	for topic := range to.Offsets {
+		if _, ok := clusters.TopicMap[topic]; !ok {
+			return nil, fmt.Errorf("topic %s is not mirrored by the job", topic)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	now := time.Now()
+	starts, err := kafka.ResolveStartOffsets(ctx, clusters.Source, req.From, topics, now)
+	if err != nil {
+		return nil, fmt.Errorf("backfill start: %w", err)
+	}
+	ends, err := kafka.ResolveStartOffsets(ctx, clusters.Source, to, topics, now)
+	if err != nil {
+		return nil, fmt.Errorf("backfill end: %w", err)
+	}
+	endOffsets := make(map[string]map[int32]int64)
+	for _, end := range ends {
+		if endOffsets[end.Topic] == nil {
+			endOffsets[end.Topic] = make(map[int32]int64)
+		}
+		endOffsets[end.Topic][end.Partition] = end.Offset
+	}
+
+	var ranges []database.BackfillRange
+	for _, start := range starts {
+		end, ok := endOffsets[start.Topic][start.Partition]
+		if !ok {
+			return nil, fmt.Errorf("no end offset for %s partition %d", start.Topic, start.Partition)
+		}
+		if end <= start.Offset {
+			continue
+		}
+		ranges = append(ranges, database.BackfillRange{
+			SourceTopic: start.Topic,
+			PartitionID: start.Partition,
+			StartOffset: start.Offset,
+			EndOffset:   end,
+		})
+	}
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("there are no records between the backfill start and end")
+	}
+
+	details := fmt.Sprintf("from the %s to the %s position of %v", req.From.Mode, to.Mode, topics)
+	return jm.createBackfillJob(job, clusters.TopicMap, ranges, req.Name, initiator, details)
+}
+
+// CreateBackfillFromGaps creates a paused backfill job that copies the source offsets of
+// mirror gaps of a job again. Gaps of the same partition are merged into one range;
+// offsets no longer on the source are skipped. The gaps are marked in progress and
+// resolved once their range is copied.
+func (jm *JobManager) CreateBackfillFromGaps(jobID string, req BackfillFromGapsRequest, initiator string) (*BackfillStatus, error) {
+	job, err := database.GetJob(jm.Db, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("job %s not found: %v", jobID, err)
+	}
+	if job.JobType == database.JobTypeBackfill {
+		return nil, fmt.Errorf("job %s is a backfill job", jobID)
+	}
+	gaps, err := jm.backfillGaps(jobID, req.GapIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	jobConfig, err := jm.loadJobConfig(jobID)
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := kafka.OpenJobClusters(jobConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer clusters.Close()
+
+	var topics []string
+	for _, gap := range gaps {
+		if _, ok := clusters.TopicMap[gap.SourceTopic]; !ok {
+			return nil, fmt.Errorf("topic %s of gap %d is not mirrored by the job", gap.SourceTopic, gap.ID)
+		}
+		topics = append(topics, gap.SourceTopic)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	hwms, err := clusters.Source.GetTopicHighWaterMarks(ctx, topics)
+	if err != nil {
+		return nil, err
+	}
+
+	ranges := mergeGapRanges(gaps, hwms)
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("the offsets of the gaps are no longer available on the source cluster")
+	}
+	status, err := jm.createBackfillJob(job, clusters.TopicMap, ranges, req.Name, initiator, fmt.Sprintf("for %d mirror gaps", len(gaps)))
+	if err != nil {
+		return nil, err
+	}
+	var gapIDs []int
+	for _, r := range status.Ranges {
+		gapIDs = append(gapIDs, r.GapIDs...)
+	}
+	if err := database.SetMirrorGapsInProgress(jm.Db, gapIDs); err != nil {
+		jobLog(jobID).Error("Failed to mark mirror gaps in progress for job %s: %v", jobID, err)
+	}
+	return status, nil
+}
+
+// backfillGaps returns the unresolved gaps of a job with the given ids, or all of them.
+func (jm *JobManager) backfillGaps(jobID string, gapIDs []int) ([]database.MirrorGap, error) {
+	gaps, err := database.GetMirrorGaps(jm.Db, jobID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int]database.MirrorGap, len(gaps))
+	for _, gap := range gaps {
+		byID[gap.ID] = gap
+	}
+
+	var selected []database.MirrorGap
+	if len(gapIDs) == 0 {
+		for _, gap := range gaps {
+			if gap.ResolutionStatus == "unresolved" {
+				selected = append(selected, gap)
+			}
+		}
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("job %s has no unresolved mirror gaps", jobID)
+		}
+		return selected, nil
+	}
+	for _, id := range gapIDs {
+		gap, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("mirror gap %d not found for job %s", id, jobID)
+		}
+		if gap.ResolutionStatus != "unresolved" {
+			return nil, fmt.Errorf("mirror gap %d is %s", id, gap.ResolutionStatus)
+		}
+		selected = append(selected, gap)
+	}
+	return selected, nil
+}
+
+// mergeGapRanges merges the gaps of each partition into one range, limited to the
+// offsets the source partition still has.
+func mergeGapRanges(gaps []database.MirrorGap, hwms map[string][]kafka.OffsetInfo) []database.BackfillRange {
+	type key struct {
+		topic     string
+		partition int32
+	}
+	merged := make(map[key]*database.BackfillRange)
+	var keys []key
+	for _, gap := range gaps {
+		k := key{gap.SourceTopic, int32(gap.PartitionID)}
+		r, ok := merged[k]
+		if !ok {
+			r = &database.BackfillRange{
+				SourceTopic: gap.SourceTopic,
+				PartitionID: int32(gap.PartitionID),
+				StartOffset: gap.GapStartOffset,
+				EndOffset:   gap.GapEndOffset,
+			}
+			merged[k] = r
+			keys = append(keys, k)
+		}
+		if gap.GapStartOffset < r.StartOffset {
+			r.StartOffset = gap.GapStartOffset
+		}
+		if gap.GapEndOffset > r.EndOffset {
+			r.EndOffset = gap.GapEndOffset
+		}
+		r.GapIDs = append(r.GapIDs, gap.ID)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].topic != keys[j].topic {
+			return keys[i].topic < keys[j].topic
+		}
+		return keys[i].partition < keys[j].partition
+	})
+
+	var ranges []database.BackfillRange
+	for _, k := range keys {
+		r := merged[k]
+		for _, partition := range hwms[k.topic] {
+			if partition.Partition != k.partition {
+				continue
+			}
+			if r.StartOffset < partition.LogStartOffset {
+				r.StartOffset = partition.LogStartOffset
+			}
+			if r.EndOffset > partition.HighWaterMark {
+				r.EndOffset = partition.HighWaterMark
+			}
+			if r.EndOffset > r.StartOffset {
+				sort.Ints(r.GapIDs)
+				ranges = append(ranges, *r)
+			}
+		}
+	}
+	return ranges
+}
+
+// createBackfillJob stores a paused backfill job for ranges of the topics of job.
+func (jm *JobManager) createBackfillJob(job *database.ReplicationJob, topicMap map[string]string, ranges []database.BackfillRange, name, initiator, details string) (*BackfillStatus, error) {
+	if name == "" {
+		name = fmt.Sprintf("%s-backfill-%s", job.Name, time.Now().UTC().Format("20060102-150405"))
+	}
+	backfill := &database.ReplicationJob{
+		ID:                 uuid.NewString(),
+		Name:               name,
+		SourceClusterName:  job.SourceClusterName,
+		TargetClusterName:  job.TargetClusterName,
+		Status:             "paused",
+		BatchSize:          job.BatchSize,
+		Parallelism:        job.Parallelism,
+		Compression:        job.Compression,
+		PreservePartitions: job.PreservePartitions,
+		JobType:            database.JobTypeBackfill,
+	}
+	if err := database.CreateJob(jm.Db, backfill); err != nil {
+		return nil, fmt.Errorf("failed to create backfill job: %w", err)
+	}
+
+	var mappings []database.TopicMapping
+	mapped := make(map[string]bool)
+	for i := range ranges {
+		ranges[i].JobID = backfill.ID
+		topic := ranges[i].SourceTopic
+		if mapped[topic] {
+			continue
+		}
+		mapped[topic] = true
+		mappings = append(mappings, database.TopicMapping{
+			JobID:              backfill.ID,
+			SourceTopicPattern: topic,
+			TargetTopicPattern: topicMap[topic],
+			Enabled:            true,
+		})
+	}
+	if err := database.UpdateMappingsForJob(jm.Db, backfill.ID, mappings); err != nil {
+		database.DeleteJob(jm.Db, backfill.ID)
+		return nil, fmt.Errorf("failed to create mappings of backfill job: %w", err)
+	}
+	if err := database.CreateBackfillRanges(jm.Db, ranges); err != nil {
+		database.DeleteJob(jm.Db, backfill.ID)
+		return nil, err
+	}
+
+	status := backfillStatus(backfill, ranges)
+	event := &database.OperationalEvent{
+		EventType: "backfill_created",
+		Initiator: initiator,
+		Details: fmt.Sprintf("Job %s: backfill job %s copies %d offsets of %d partitions %s", job.ID, backfill.ID,
+			status.TotalOffsets, len(ranges), details),
+	}
+	if err := database.CreateOperationalEvent(jm.Db, event); err != nil {
+		jobLog(job.ID).Error("Failed to record backfill event for job %s: %v", job.ID, err)
+	}
+	return status, nil
+}
+
+// BackfillStatus returns the progress of a backfill job.
+func (jm *JobManager) BackfillStatus(jobID string) (*BackfillStatus, error) {
+	job, err := database.GetJob(jm.Db, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.JobType != database.JobTypeBackfill {
+		return nil, ErrNotBackfillJob
+	}
+	ranges, err := database.GetBackfillRanges(jm.Db, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return backfillStatus(job, ranges), nil
+}
+
+func backfillStatus(job *database.ReplicationJob, ranges []database.BackfillRange) *BackfillStatus {
+	status := &BackfillStatus{JobID: job.ID, Name: job.Name, Ranges: ranges}
+	completed, failed := 0, 0
+	for _, r := range ranges {
+		status.TotalOffsets += r.EndOffset - r.StartOffset
+		status.CopiedOffsets += r.NextOffset - r.StartOffset
+		switch r.Status {
+		case database.BackfillRangeCompleted:
+			completed++
+		case database.BackfillRangeFailed:
+			failed++
+		}
+	}
+	status.ProgressPercent = 100
+	if status.TotalOffsets > 0 {
+		status.ProgressPercent = math.Round(float64(status.CopiedOffsets)/float64(status.TotalOffsets)*1000) / 10
+	}
+
+	switch {
+	case completed == len(ranges):
+		status.Status = BackfillCompleted
+	case job.Status == "active":
+		status.Status = BackfillRunning
+	case job.Status == "failed" || failed > 0:
+		status.Status = BackfillFailed
+	default:
+		status.Status = BackfillPaused
+	}
+	return status
+}
+
+// backfillConfig returns the ranges of a backfill job for its mirror.
+func backfillConfig(ranges []database.BackfillRange) []config.BackfillRange {
+	result := make([]config.BackfillRange, 0, len(ranges))
+	for _, r := range ranges {
+		result = append(result, config.BackfillRange{
+			Topic:       r.SourceTopic,
+			Partition:   r.PartitionID,
+			StartOffset: r.StartOffset,
+			EndOffset:   r.EndOffset,
+			NextOffset:  r.NextOffset,
+		})
+	}
+	return result
+}
+
+// backfillCopied reports whether every range of a backfill is copied.
+func backfillCopied(ranges []config.BackfillRange) bool {
+	for _, r := range ranges {
+		if r.NextOffset < r.EndOffset {
+			return false
+		}
+	}
+	return true
+}
+
+// recordBackfillProgress saves the progress a backfill job reports. Once every range is
+// settled the job is stopped and completed in the background, since the report may come
+// from the job's own goroutines.
+func (jm *JobManager) recordBackfillProgress(jobID string, progress []kafka.BackfillProgress, done bool) {
+	if done {
+		go jm.completeBackfill(jobID, progress)
+		return
+	}
+	jm.saveBackfillProgress(jobID, progress, false)
+}
+
+func (jm *JobManager) saveBackfillProgress(jobID string, progress []kafka.BackfillProgress, done bool) {
+	ranges, err := database.GetBackfillRanges(jm.Db, jobID)
+	if err != nil {
+		jobLog(jobID).Error("Failed to get backfill ranges of job %s: %v", jobID, err)
+		return
+	}
+	byPartition := make(map[string]map[int32]*database.BackfillRange)
+	for i := range ranges {
+		r := &ranges[i]
+		if byPartition[r.SourceTopic] == nil {
+			byPartition[r.SourceTopic] = make(map[int32]*database.BackfillRange)
+		}
+		byPartition[r.SourceTopic][r.PartitionID] = r
+	}
+
+	now := time.Now().UTC()
+	for _, p := range progress {
+		r := byPartition[p.Topic][p.Partition]
+		if r == nil || r.Status == database.BackfillRangeCompleted {
+			continue
+		}
+		r.NextOffset = p.NextOffset
+		r.LastError = nil
+		r.Status = database.BackfillRangePending
+		switch {
+		case p.Done:
+			r.Status = database.BackfillRangeCompleted
+			r.CompletedAt = &now
+		case p.Error != "":
+			message := p.Error
+			r.LastError = &message
+			if done {
+				r.Status = database.BackfillRangeFailed
+			}
+		}
+		if err := database.UpdateBackfillRange(jm.Db, r); err != nil {
+			jobLog(jobID).Error("Failed to save backfill progress of job %s: %v", jobID, err)
+		}
+	}
+}
+
+// completeBackfill stops a finished backfill job, saves its final progress and resolves
+// the mirror gaps of the copied ranges. A job with failed ranges is marked failed and
+// copies them again when it is restarted.
+func (jm *JobManager) completeBackfill(jobID string, progress []kafka.BackfillProgress) {
+	if err := jm.StopJob(jobID); err != nil {
+		jobLog(jobID).Error("Failed to stop finished backfill job %s: %v", jobID, err)
+	}
+	jm.saveBackfillProgress(jobID, progress, true)
+
+	ranges, err := database.GetBackfillRanges(jm.Db, jobID)
+	if err != nil {
+		jobLog(jobID).Error("Failed to get backfill ranges of job %s: %v", jobID, err)
+		return
+	}
+	var failed []string
+	resolved := 0
+	for _, r := range ranges {
+		switch r.Status {
+		case database.BackfillRangeCompleted:
+			for _, gapID := range r.GapIDs {
+				if err := database.ResolveMirrorGap(jm.Db, gapID, "backfill"); err != nil {
+					jobLog(jobID).Error("Failed to resolve mirror gap %d: %v", gapID, err)
+					continue
+				}
+				resolved++
+			}
+		case database.BackfillRangeFailed:
+			reason := ""
+			if r.LastError != nil {
+				reason = *r.LastError
+			}
+			failed = append(failed, fmt.Sprintf("%s partition %d at offset %d: %s", r.SourceTopic, r.PartitionID, r.NextOffset, reason))
+		}
+	}
+
+	event := &database.OperationalEvent{
+		EventType: "backfill_completed",
+		Initiator: "system",
+		Details:   fmt.Sprintf("Job %s: backfill of %d partitions completed, %d mirror gaps resolved", jobID, len(ranges), resolved),
+	}
+	if len(failed) > 0 {
+		reason := "backfill failed for " + strings.Join(failed, "; ")
+		event.EventType = "backfill_failed"
+		event.Details = fmt.Sprintf("Job %s: %s", jobID, reason)
+		if job, err := database.GetJob(jm.Db, jobID); err == nil {
+			job.Status = "failed"
+			job.FailedReason = &reason
+			if err := database.UpdateJob(jm.Db, job); err != nil {
+				jobLog(jobID).Error("Failed to mark backfill job %s failed: %v", jobID, err)
+			}
+		}
+	}
+	if err := database.CreateOperationalEvent(jm.Db, event); err != nil {
+		jobLog(jobID).Error("Failed to record backfill event for job %s: %v", jobID, err)
+	}
+}
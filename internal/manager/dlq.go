@@ -0,0 +1,61 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"time"
+)
+
+// deadLetterTimeout bounds reading and replaying a dead-letter topic.
+const deadLetterTimeout = 2 * time.Minute
+
+// ListDeadLetters returns the newest records of the job's dead-letter topic.
+func (jm *JobManager) ListDeadLetters(jobID string, limit int) (*kafka.DeadLetterList, error) {
+	jobConfig, err := jm.loadJobConfig(jobID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), deadLetterTimeout)
+	defer cancel()
+	return kafka.ReadDeadLetters(ctx, jobConfig, limit)
+}
+
+// ReplayDeadLetters re-drives up to limit records of the job's dead-letter topic
+// to their target topics, continuing after the last replay.
+func (jm *JobManager) ReplayDeadLetters(jobID string, limit int, initiator string) (*kafka.DeadLetterReplay, error) {
+	jobConfig, err := jm.loadJobConfig(jobID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), deadLetterTimeout)
+	defer cancel()
+	result, err := kafka.ReplayDeadLetters(ctx, jobConfig, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	details := fmt.Sprintf("Job %s: replayed %d records of dead-letter topic %s, %d failed, %d pending",
+		jobID, result.Replayed, result.Topic, result.Failed, result.Pending)
+	event := &database.OperationalEvent{
+		EventType: "dlq_replay",
+		Initiator: initiator,
+		Details:   details,
+	}
+	if err := database.CreateOperationalEvent(jm.Db, event); err != nil {
+		jobLog(jobID).Error("Failed to record dead-letter replay event for job %s: %v", jobID, err)
+	}
+	return result, nil
+}
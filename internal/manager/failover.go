@@ -0,0 +1,654 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/pkg/logger"
+	"path"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Failover steps, in the order they run.
+const (
+	FailoverStepDrain       = "drain"
+	FailoverStepCheckpoint  = "checkpoint"
+	FailoverStepValidate    = "validate"
+	FailoverStepSyncOffsets = "sync_offsets"
+	FailoverStepStopJob     = "stop_job"
+	FailoverStepReverseJob  = "reverse_job"
+)
+
+var failoverSteps = []string{
+	FailoverStepDrain, FailoverStepCheckpoint, FailoverStepValidate,
+	FailoverStepSyncOffsets, FailoverStepStopJob, FailoverStepReverseJob,
+}
+
+// FailoverPlanned is the status of a dry-run failover report without blockers.
+const FailoverPlanned = "planned"
+
+const (
+	defaultFailoverDrainTimeout = 5 * time.Minute
+	failoverStepTimeout         = 2 * time.Minute
+)
+
+// failoverPollInterval is how often the drain step measures the lag.
+var failoverPollInterval = 5 * time.Second
+
+// ErrFailoverInProgress is returned when a job already has a running failover.
+var ErrFailoverInProgress = errors.New("a failover of this job is already in progress")
+
+// SetFailoverPollIntervalForTest changes how often the drain step measures the
+// lag and returns a restore func.
+func SetFailoverPollIntervalForTest(interval time.Duration) func() {
+	previous := failoverPollInterval
+	failoverPollInterval = interval
+	return func() { failoverPollInterval = previous }
+}
+
+// ValidateFailoverOptions checks the drain timeout and consumer group patterns.
+func ValidateFailoverOptions(opts database.FailoverOptions) error {
+	if _, err := failoverDrainTimeout(opts); err != nil {
+		return err
+	}
+	for _, pattern := range opts.ConsumerGroups {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("consumer group pattern %q is invalid: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+func failoverDrainTimeout(opts database.FailoverOptions) (time.Duration, error) {
+	if opts.DrainTimeout == "" {
+		return defaultFailoverDrainTimeout, nil
+	}
+	timeout, err := time.ParseDuration(opts.DrainTimeout)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("drain_timeout must be a positive duration")
+	}
+	return timeout, nil
+}
+
+// StartFailover starts a planned failover of a job from its source to its target
+// cluster. The steps run in the background: wait for zero lag, record a migration
+// checkpoint, validate the mirror state, copy consumer group offsets to the target,
+// stop the job and, if requested, create a paused reverse job for failback. Every
+// step is recorded as an operational event; a failed failover can be resumed with
+// ResumeFailover.
+func (jm *JobManager) StartFailover(jobID string, opts database.FailoverOptions, initiator string) (*database.FailoverOperation, error) {
+	if err := ValidateFailoverOptions(opts); err != nil {
+		return nil, err
+	}
+	if _, err := database.GetJob(jm.Db, jobID); err != nil {
+		return nil, fmt.Errorf("job %s not found: %v", jobID, err)
+	}
+	running, err := database.HasRunningFailover(jm.Db, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if running {
+		return nil, ErrFailoverInProgress
+	}
+
+	op := &database.FailoverOperation{
+		JobID:     jobID,
+		Status:    database.FailoverRunning,
+		Options:   opts,
+		Initiator: initiator,
+	}
+	for _, name := range failoverSteps {
+		status := database.FailoverStepPending
+		if name == FailoverStepReverseJob && !opts.CreateReverseJob {
+			status = database.FailoverStepSkipped
+		}
+		op.Steps = append(op.Steps, database.FailoverStep{Name: name, Status: status})
+	}
+	if err := database.CreateFailoverOperation(jm.Db, op); err != nil {
+		return nil, err
+	}
+	jm.recordFailoverEvent(op, "Failover #%d started by %s", op.ID, initiator)
+	return jm.launchFailover(op), nil
+}
+
+// ResumeFailover reruns a failed failover from the step that failed.
+func (jm *JobManager) ResumeFailover(jobID string, operationID int, initiator string) (*database.FailoverOperation, error) {
+	op, err := jm.GetFailover(jobID, operationID)
+	if err != nil {
+		return nil, err
+	}
+	if op.Status != database.FailoverFailed {
+		return nil, fmt.Errorf("failover #%d is %s, only failed failovers can be resumed", op.ID, op.Status)
+	}
+	running, err := database.HasRunningFailover(jm.Db, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if running {
+		return nil, ErrFailoverInProgress
+	}
+
+	op.Status = database.FailoverRunning
+	op.LastError = nil
+	if err := database.UpdateFailoverOperation(jm.Db, op); err != nil {
+		return nil, err
+	}
+	jm.recordFailoverEvent(op, "Failover #%d resumed by %s", op.ID, initiator)
+	return jm.launchFailover(op), nil
+}
+
+// GetFailover returns a failover operation of a job.
+func (jm *JobManager) GetFailover(jobID string, operationID int) (*database.FailoverOperation, error) {
+	op, err := database.GetFailoverOperation(jm.Db, operationID)
+	if err != nil || op.JobID != jobID {
+		return nil, fmt.Errorf("failover #%d of job %s not found", operationID, jobID)
+	}
+	return op, nil
+}
+
+// ListFailovers returns the failover operations of a job, newest first.
+func (jm *JobManager) ListFailovers(jobID string) ([]database.FailoverOperation, error) {
+	return database.ListFailoverOperations(jm.Db, jobID)
+}
+
+// launchFailover runs op in the background and returns a snapshot of it.
+func (jm *JobManager) launchFailover(op *database.FailoverOperation) *database.FailoverOperation {
+	snapshot := *op
+	snapshot.Steps = append([]database.FailoverStep(nil), op.Steps...)
+	jm.wg.Add(1)
+	go func() {
+		defer jm.wg.Done()
+		jm.runFailover(op)
+	}()
+	return &snapshot
+}
+
+// failInterruptedFailovers marks failovers left running by a previous process as
+// failed, so they can be resumed.
+func (jm *JobManager) failInterruptedFailovers() {
+	ops, err := database.ListRunningFailoverOperations(jm.Db)
+	if err != nil {
+		logger.Error("Failed to check for interrupted failovers: %v", err)
+		return
+	}
+	for i := range ops {
+		op := &ops[i]
+		for j := range op.Steps {
+			if op.Steps[j].Status == database.FailoverStepRunning {
+				op.Steps[j].Status = database.FailoverStepFailed
+				op.Steps[j].Error = "interrupted by a restart"
+			}
+		}
+		jm.failFailover(op, errors.New("interrupted by a restart"))
+	}
+}
+
+func (jm *JobManager) runFailover(op *database.FailoverOperation) {
+	jobConfig, err := jm.loadJobConfig(op.JobID)
+	if err != nil {
+		jm.failFailover(op, err)
+		return
+	}
+	clusters, err := kafka.OpenJobClusters(jobConfig)
+	if err != nil {
+		jm.failFailover(op, err)
+		return
+	}
+	defer clusters.Close()
+
+	for i := range op.Steps {
+		step := &op.Steps[i]
+		if step.Status == database.FailoverStepCompleted || step.Status == database.FailoverStepSkipped {
+			continue
+		}
+		if atomic.LoadInt32(&jm.closing) == 1 {
+			jm.failFailover(op, errors.New("interrupted by shutdown"))
+			return
+		}
+
+		started := time.Now().UTC()
+		step.Status = database.FailoverStepRunning
+		step.StartedAt = &started
+		step.CompletedAt = nil
+		step.Details = ""
+		step.Error = ""
+		jm.saveFailover(op)
+
+		details, err := jm.runFailoverStep(op, step.Name, clusters)
+		completed := time.Now().UTC()
+		step.CompletedAt = &completed
+		step.Details = details
+		if err != nil {
+			step.Status = database.FailoverStepFailed
+			step.Error = err.Error()
+			jm.failFailover(op, fmt.Errorf("step %s failed: %w", step.Name, err))
+			return
+		}
+		step.Status = database.FailoverStepCompleted
+		jm.saveFailover(op)
+		jm.recordFailoverEvent(op, "Failover #%d step %s completed: %s", op.ID, step.Name, details)
+	}
+
+	completed := time.Now().UTC()
+	op.Status = database.FailoverCompleted
+	op.CompletedAt = &completed
+	jm.saveFailover(op)
+	jm.recordFailoverEvent(op, "Failover #%d completed", op.ID)
+}
+
+func (jm *JobManager) runFailoverStep(op *database.FailoverOperation, name string, clusters *kafka.JobClusters) (string, error) {
+	if name == FailoverStepDrain {
+		return jm.drainForFailover(op, clusters)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), failoverStepTimeout)
+	defer cancel()
+	switch name {
+	case FailoverStepCheckpoint:
+		return jm.checkpointForFailover(ctx, op, clusters)
+	case FailoverStepValidate:
+		return jm.validateForFailover(ctx, op, clusters)
+	case FailoverStepSyncOffsets:
+		return syncOffsetsForFailover(ctx, op, clusters)
+	case FailoverStepStopJob:
+		return jm.stopJobForFailover(op)
+	case FailoverStepReverseJob:
+		return jm.createReverseJob(ctx, op, clusters)
+	}
+	return "", fmt.Errorf("unknown failover step %s", name)
+}
+
+// drainForFailover waits until every source record has reached the target.
+func (jm *JobManager) drainForFailover(op *database.FailoverOperation, clusters *kafka.JobClusters) (string, error) {
+	timeout, err := failoverDrainTimeout(op.Options)
+	if err != nil {
+		return "", err
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), failoverStepTimeout)
+		outstanding, err := clusters.OutstandingRecords(ctx)
+		cancel()
+		if err != nil {
+			return "", err
+		}
+		if outstanding == 0 {
+			return "replication lag is zero", nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Sprintf("%d records outstanding", outstanding),
+				fmt.Errorf("lag did not reach zero within %s, %d records outstanding", timeout, outstanding)
+		}
+		wait := failoverPollInterval
+		if wait > remaining {
+			wait = remaining
+		}
+		select {
+		case <-time.After(wait):
+		case <-jm.close:
+			return "", errors.New("interrupted by shutdown")
+		}
+	}
+}
+
+// failoverCheckpointState is what a failover checkpoint records.
+type failoverCheckpointState struct {
+	GroupOffsets map[string]map[string]map[int32]int64
+	TargetHWMs   map[string]map[int32]int64
+}
+
+func captureFailoverState(ctx context.Context, op *database.FailoverOperation, clusters *kafka.JobClusters) (*failoverCheckpointState, error) {
+	groupOffsets, err := clusters.SourceGroupOffsets(ctx, op.Options.ConsumerGroups)
+	if err != nil {
+		return nil, err
+	}
+	targetHWMs, err := clusters.TargetHighWaterMarks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &failoverCheckpointState{GroupOffsets: groupOffsets, TargetHWMs: targetHWMs}, nil
+}
+
+// checkpointForFailover records the source consumer group offsets and the target
+// high water marks as a migration checkpoint. The reverse job starts from them.
+func (jm *JobManager) checkpointForFailover(ctx context.Context, op *database.FailoverOperation, clusters *kafka.JobClusters) (string, error) {
+	state, err := captureFailoverState(ctx, op, clusters)
+	if err != nil {
+		return "", err
+	}
+	groupOffsets, err := json.Marshal(state.GroupOffsets)
+	if err != nil {
+		return "", err
+	}
+	targetHWMs, err := json.Marshal(state.TargetHWMs)
+	if err != nil {
+		return "", err
+	}
+	reason := op.Options.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("Failover #%d", op.ID)
+	}
+	checkpointID, err := database.CreateMigrationCheckpoint(jm.Db, database.MigrationCheckpoint{
+		JobID:                      op.JobID,
+		CheckpointType:             "pre_migration",
+		SourceConsumerGroupOffsets: string(groupOffsets),
+		TargetHighWaterMarks:       string(targetHWMs),
+		CreatedAt:                  time.Now(),
+		CreatedBy:                  op.Initiator,
+		MigrationReason:            &reason,
+	})
+	if err != nil {
+		return "", err
+	}
+	op.CheckpointID = &checkpointID
+	return fmt.Sprintf("checkpoint %d records offsets of %d consumer groups and high water marks of %d target partitions",
+		checkpointID, len(state.GroupOffsets), countPartitions(state.TargetHWMs)), nil
+}
+
+// validateForFailover fails on replication gaps or critical issues and stores the
+// analysis with the checkpoint.
+func (jm *JobManager) validateForFailover(ctx context.Context, op *database.FailoverOperation, clusters *kafka.JobClusters) (string, error) {
+	analysis, err := clusters.AnalyzeMirrorState(ctx)
+	if err != nil {
+		return "", err
+	}
+	if op.CheckpointID != nil {
+		if results, err := json.Marshal(analysis); err == nil {
+			if err := database.UpdateMigrationCheckpointValidation(jm.Db, *op.CheckpointID, string(results)); err != nil {
+				jobLog(op.JobID).Warn("Failed to store failover validation for job %s: %v", op.JobID, err)
+			}
+		}
+	}
+	gaps, critical, warnings := mirrorStateIssues(analysis)
+	details := fmt.Sprintf("%d gaps, %d critical issues, %d warnings", gaps, len(critical), warnings)
+	if len(critical) > 0 {
+		return details, fmt.Errorf("critical mirror state issues: %s", strings.Join(critical, "; "))
+	}
+	if gaps > 0 {
+		return details, fmt.Errorf("%d partitions have replication gaps", gaps)
+	}
+	return details, nil
+}
+
+func mirrorStateIssues(analysis *kafka.MirrorStateAnalysis) (gaps int, critical []string, warnings int) {
+	if analysis.OffsetComparison == nil {
+		return 0, nil, analysis.WarningIssuesCount
+	}
+	return analysis.OffsetComparison.TotalGapsDetected, analysis.OffsetComparison.CriticalIssues, analysis.WarningIssuesCount
+}
+
+// syncOffsetsForFailover copies the consumer group offsets to the target cluster.
+func syncOffsetsForFailover(ctx context.Context, op *database.FailoverOperation, clusters *kafka.JobClusters) (string, error) {
+	results, err := clusters.SyncConsumerGroupOffsets(ctx, op.Options.ConsumerGroups, false)
+	if err != nil {
+		return "", err
+	}
+	var failed []string
+	for _, result := range results {
+		if result.Error != "" {
+			failed = append(failed, fmt.Sprintf("%s: %s", result.Group, result.Error))
+		}
+	}
+	details := fmt.Sprintf("synced offsets of %d consumer groups", len(results)-len(failed))
+	if len(failed) > 0 {
+		return details, fmt.Errorf("failed to sync consumer group offsets: %s", strings.Join(failed, "; "))
+	}
+	return details, nil
+}
+
+// stopJobForFailover stops the job; a job that is not running is left as it is.
+func (jm *JobManager) stopJobForFailover(op *database.FailoverOperation) (string, error) {
+	jm.Mu.Lock()
+	_, running := jm.KafMirrors[op.JobID]
+	jm.Mu.Unlock()
+	if !running {
+		return "job was not running", nil
+	}
+	if err := jm.StopJob(op.JobID); err != nil {
+		return "", err
+	}
+	return "job stopped", nil
+}
+
+// createReverseJob creates a paused job mirroring the target topics back to the
+// source and points its consumer group at the target high water marks of the
+// checkpoint, so failback copies exactly what was written after the failover.
+func (jm *JobManager) createReverseJob(ctx context.Context, op *database.FailoverOperation, clusters *kafka.JobClusters) (string, error) {
+	if op.CheckpointID == nil {
+		return "", errors.New("failover has no checkpoint")
+	}
+	checkpoint, err := database.GetMigrationCheckpoint(jm.Db, *op.CheckpointID)
+	if err != nil {
+		return "", err
+	}
+	var targetHWMs map[string]map[int32]int64
+	if err := json.Unmarshal([]byte(checkpoint.TargetHighWaterMarks), &targetHWMs); err != nil {
+		return "", fmt.Errorf("checkpoint %d has invalid target high water marks: %w", checkpoint.ID, err)
+	}
+
+	reverseJobID := ""
+	if op.ReverseJobID != nil {
+		reverseJobID = *op.ReverseJobID
+	} else {
+		job, err := database.GetJob(jm.Db, op.JobID)
+		if err != nil {
+			return "", err
+		}
+		reverse, err := jm.createReverseJobRecord(job, clusters.TopicMap, op.ID)
+		if err != nil {
+			return "", err
+		}
+		reverseJobID = reverse.ID
+		op.ReverseJobID = &reverseJobID
+		jm.saveFailover(op)
+		go jm.CreateInventorySnapshot(reverseJobID, "manual")
+	}
+
+	if err := clusters.Target.CommitConsumerGroupOffsets(ctx, kafka.JobConsumerGroup(reverseJobID), targetHWMs); err != nil {
+		return "", fmt.Errorf("failed to set start offsets of reverse job %s: %w", reverseJobID, err)
+	}
+	return fmt.Sprintf("created paused job %s starting at checkpoint %d", reverseJobID, checkpoint.ID), nil
+}
+
+func (jm *JobManager) createReverseJobRecord(job *database.ReplicationJob, topicMap map[string]string, operationID int) (*database.ReplicationJob, error) {
+	reverse := &database.ReplicationJob{
+		ID:                 uuid.NewString(),
+		Name:               job.Name + "-failback",
+		SourceClusterName:  job.TargetClusterName,
+		TargetClusterName:  job.SourceClusterName,
+		Status:             "paused",
+		BatchSize:          job.BatchSize,
+		Parallelism:        job.Parallelism,
+		Compression:        job.Compression,
+		PreservePartitions: job.PreservePartitions,
+	}
+	if err := database.CreateJob(jm.Db, reverse); err != nil {
+		reverse.Name = fmt.Sprintf("%s-failback-%d", job.Name, operationID)
+		if err := database.CreateJob(jm.Db, reverse); err != nil {
+			return nil, fmt.Errorf("failed to create reverse job: %w", err)
+		}
+	}
+
+	sourceTopics := make([]string, 0, len(topicMap))
+	for sourceTopic := range topicMap {
+		sourceTopics = append(sourceTopics, sourceTopic)
+	}
+	sort.Strings(sourceTopics)
+	mappings := make([]database.TopicMapping, 0, len(sourceTopics))
+	for _, sourceTopic := range sourceTopics {
+		mappings = append(mappings, database.TopicMapping{
+			JobID:              reverse.ID,
+			SourceTopicPattern: topicMap[sourceTopic],
+			TargetTopicPattern: sourceTopic,
+			Enabled:            true,
+		})
+	}
+	if err := database.UpdateMappingsForJob(jm.Db, reverse.ID, mappings); err != nil {
+		database.DeleteJob(jm.Db, reverse.ID)
+		return nil, fmt.Errorf("failed to create mappings of reverse job: %w", err)
+	}
+	return reverse, nil
+}
+
+// PlanFailover returns a dry-run report of a failover: the current lag, what the
+// checkpoint would record, the mirror state, the offsets that would be synced and
+// the reverse job that would be created. Nothing is changed. The report's status
+// is failed when a step is already known to fail.
+func (jm *JobManager) PlanFailover(jobID string, opts database.FailoverOptions, initiator string) (*database.FailoverOperation, error) {
+	if err := ValidateFailoverOptions(opts); err != nil {
+		return nil, err
+	}
+	job, err := database.GetJob(jm.Db, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("job %s not found: %v", jobID, err)
+	}
+	jobConfig, err := jm.loadJobConfig(jobID)
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := kafka.OpenJobClusters(jobConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer clusters.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), failoverStepTimeout)
+	defer cancel()
+
+	op := &database.FailoverOperation{
+		JobID:     jobID,
+		Status:    FailoverPlanned,
+		Options:   opts,
+		DryRun:    true,
+		Initiator: initiator,
+		CreatedAt: time.Now().UTC(),
+	}
+	plan := func(name, details string, err error) {
+		step := database.FailoverStep{Name: name, Status: database.FailoverStepPlanned, Details: details}
+		if err != nil {
+			step.Status = database.FailoverStepFailed
+			step.Error = err.Error()
+			if op.LastError == nil {
+				message := fmt.Sprintf("step %s would fail: %v", name, err)
+				op.LastError = &message
+				op.Status = database.FailoverFailed
+			}
+		}
+		op.Steps = append(op.Steps, step)
+	}
+
+	timeout, _ := failoverDrainTimeout(opts)
+	outstanding, err := clusters.OutstandingRecords(ctx)
+	if err != nil {
+		plan(FailoverStepDrain, "", err)
+	} else if outstanding == 0 {
+		plan(FailoverStepDrain, "replication lag is zero", nil)
+	} else {
+		plan(FailoverStepDrain, fmt.Sprintf("%d records outstanding, would wait up to %s for zero lag", outstanding, timeout), nil)
+	}
+
+	state, err := captureFailoverState(ctx, op, clusters)
+	if err != nil {
+		plan(FailoverStepCheckpoint, "", err)
+	} else {
+		plan(FailoverStepCheckpoint, fmt.Sprintf("would record offsets of %d consumer groups and high water marks of %d target partitions",
+			len(state.GroupOffsets), countPartitions(state.TargetHWMs)), nil)
+	}
+
+	analysis, err := clusters.AnalyzeMirrorState(ctx)
+	if err != nil {
+		plan(FailoverStepValidate, "", err)
+	} else {
+		gaps, critical, warnings := mirrorStateIssues(analysis)
+		details := fmt.Sprintf("%d gaps, %d critical issues, %d warnings", gaps, len(critical), warnings)
+		switch {
+		case len(critical) > 0:
+			plan(FailoverStepValidate, details, fmt.Errorf("critical mirror state issues: %s", strings.Join(critical, "; ")))
+		case gaps > 0 && outstanding == 0:
+			plan(FailoverStepValidate, details, fmt.Errorf("%d partitions have replication gaps", gaps))
+		case gaps > 0:
+			plan(FailoverStepValidate, details+", gaps are expected to close while draining", nil)
+		default:
+			plan(FailoverStepValidate, details, nil)
+		}
+	}
+
+	syncs, err := clusters.SyncConsumerGroupOffsets(ctx, opts.ConsumerGroups, true)
+	if err != nil {
+		plan(FailoverStepSyncOffsets, "", err)
+	} else {
+		groups := make([]string, 0, len(syncs))
+		for _, result := range syncs {
+			groups = append(groups, result.Group)
+		}
+		details := fmt.Sprintf("would commit offsets of %d consumer groups on the target", len(groups))
+		if len(groups) > 0 {
+			details += ": " + strings.Join(groups, ", ")
+		}
+		plan(FailoverStepSyncOffsets, details, nil)
+	}
+
+	plan(FailoverStepStopJob, fmt.Sprintf("would stop the job (currently %s)", job.Status), nil)
+
+	if opts.CreateReverseJob {
+		plan(FailoverStepReverseJob, fmt.Sprintf("would create paused job %s-failback from %s to %s with %d mappings, starting at the checkpoint",
+			job.Name, job.TargetClusterName, job.SourceClusterName, len(clusters.TopicMap)), nil)
+	} else {
+		op.Steps = append(op.Steps, database.FailoverStep{Name: FailoverStepReverseJob, Status: database.FailoverStepSkipped})
+	}
+	return op, nil
+}
+
+func (jm *JobManager) failFailover(op *database.FailoverOperation, err error) {
+	message := err.Error()
+	op.Status = database.FailoverFailed
+	op.LastError = &message
+	jm.saveFailover(op)
+	jm.recordFailoverEvent(op, "Failover #%d failed: %s", op.ID, message)
+}
+
+func (jm *JobManager) saveFailover(op *database.FailoverOperation) {
+	if err := database.UpdateFailoverOperation(jm.Db, op); err != nil {
+		jobLog(op.JobID).Error("Failed to save failover #%d of job %s: %v", op.ID, op.JobID, err)
+	}
+}
+
+// recordFailoverEvent records a failover state change as an operational event.
+func (jm *JobManager) recordFailoverEvent(op *database.FailoverOperation, format string, args ...interface{}) {
+	event := &database.OperationalEvent{
+		EventType: "failover",
+		Initiator: op.Initiator,
+		Details:   fmt.Sprintf("Job %s: %s", op.JobID, fmt.Sprintf(format, args...)),
+	}
+	if err := database.CreateOperationalEvent(jm.Db, event); err != nil {
+		jobLog(op.JobID).Error("Failed to record failover event for job %s: %v", op.JobID, err)
+	}
+}
+
+func countPartitions(offsets map[string]map[int32]int64) int {
+	count := 0
+	for _, partitions := range offsets {
+		count += len(partitions)
+	}
+	return count
+}
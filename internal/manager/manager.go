@@ -15,12 +15,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"kaf-mirror/internal/ai"
+	"kaf-mirror/internal/alerting"
+	"kaf-mirror/internal/auth"
 	"kaf-mirror/internal/config"
 	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/joblogs"
 	"kaf-mirror/internal/kafka"
 	"kaf-mirror/internal/metrics"
+	"kaf-mirror/internal/webhooks"
 	"kaf-mirror/pkg/logger"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -34,9 +40,14 @@ type KafMirrorFactory func(cfg *config.Config) (kafka.KafMirror, error)
 
 // Hub interface to avoid circular dependency
 type Hub interface {
-	BroadcastJSON(v interface{})
+	// Publish sends data to the WebSocket clients subscribed to channel,
+	// filtered by jobID for job scoped data.
+	Publish(channel, jobID string, data interface{})
 }
 
+// jobMetricsChannel is the WebSocket channel live job metrics are published on.
+const jobMetricsChannel = "job_metrics"
+
 // JobManager manages the lifecycle of replication jobs.
 type JobManager struct {
 	Db                   *sqlx.DB
@@ -47,12 +58,19 @@ type JobManager struct {
 	KafMirrorFactory     KafMirrorFactory
 	metricsSink          metrics.Sink
 	AIClient             *ai.Client
+	Alerts               *alerting.Engine
+	Logs                 *joblogs.Buffer
+	Webhooks             *webhooks.Dispatcher
+	removeLogListener    func()
+	removeInsightHook    func()
 	close                chan struct{}
 	aiAnalysisTicker     *time.Ticker
 	wg                   sync.WaitGroup
 	dbOpsWg              sync.WaitGroup
 	lastAIAnalysis       map[string]time.Time
 	lastAIMetric         map[string]database.ReplicationMetric
+	latestMetrics        map[string]database.ReplicationMetric
+	metricsMu            sync.RWMutex
 	lastComplianceReport map[string]time.Time
 	closing              int32
 }
@@ -81,9 +99,33 @@ func New(db *sqlx.DB, cfg *config.Config, hub Hub) *JobManager {
 		close:                make(chan struct{}),
 		lastAIAnalysis:       make(map[string]time.Time),
 		lastAIMetric:         make(map[string]database.ReplicationMetric),
+		latestMetrics:        make(map[string]database.ReplicationMetric),
 		lastComplianceReport: make(map[string]time.Time),
+		Logs:                 joblogs.NewBuffer(cfg.Logging.JobBufferSize),
+		Webhooks:             webhooks.NewDispatcher(db, cfg.Webhooks),
 	}
 
+	jm.removeLogListener = logger.AddListener(jm.Logs.Append)
+	jm.removeInsightHook = database.AddAIInsightListener(func(insight database.AIInsight) {
+		jobID := ""
+		if insight.JobID != nil {
+			jobID = *insight.JobID
+		}
+		jm.Webhooks.Emit(database.WebhookEventInsightCreated, jobID, insight)
+	})
+
+	if cfg.Alerting.Enabled {
+		engine, err := alerting.NewEngine(db, cfg.Alerting)
+		if err != nil {
+			logger.Warn("Failed to create alerting engine: %v", err)
+		} else {
+			jm.Alerts = engine
+		}
+	}
+
+	jm.failInterruptedFailovers()
+	jm.failInterruptedVerifications()
+
 	jm.wg.Add(1)
 	go func() {
 		defer jm.wg.Done()
@@ -111,13 +153,16 @@ func New(db *sqlx.DB, cfg *config.Config, hub Hub) *JobManager {
 		}
 	}()
 
-	jm.wg.Add(6)
+	jm.wg.Add(9)
 	go jm.startPruning()
 	go jm.startAIAnalysis()
 	go jm.startHistoricalAnalysis()
 	go jm.startTopicHealthChecks()
 	go jm.startMirrorStateUpdates()
 	go jm.startComplianceScheduler()
+	go jm.startLDAPSync()
+	go jm.startAlertEvaluation()
+	go jm.startPartitionMetricsDownsampling()
 	return jm
 }
 
@@ -136,10 +181,10 @@ func (jm *JobManager) reconcileAndStartJobs() error {
 		if job.Status == "active" {
 			logger.Info("Job '%s' (%s) is marked as active, ensuring it is started.", job.Name, job.ID)
 			if err := jm.StartJob(job.ID); err != nil {
-				logger.Error("Failed to automatically start active job %s: %v", job.ID, err)
+				jobLog(job.ID).Error("Failed to automatically start active job %s: %v", job.ID, err)
 				job.Status = "paused"
 				if updateErr := database.UpdateJob(jm.Db, &job); updateErr != nil {
-					logger.Error("Failed to update status for job %s after start failure: %v", job.ID, updateErr)
+					jobLog(job.ID).Error("Failed to update status for job %s after start failure: %v", job.ID, updateErr)
 				}
 			}
 		}
@@ -152,6 +197,14 @@ func (jm *JobManager) Close() {
 	close(jm.close)
 	jm.wg.Wait()
 	jm.dbOpsWg.Wait()
+	jm.removeLogListener()
+	jm.removeInsightHook()
+	jm.Webhooks.Close()
+	if closer, ok := jm.metricsSink.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			logger.Warn("Failed to close metrics sink: %v", err)
+		}
+	}
 }
 
 func (jm *JobManager) startPruning() {
@@ -166,6 +219,12 @@ func (jm *JobManager) startPruning() {
 			if err != nil {
 				logger.Error("Failed to prune old data: %v", err)
 			}
+			deleted, err := database.PruneAuditEvents(jm.Db, jm.Config.Audit.RetentionDays)
+			if err != nil {
+				logger.Error("Failed to prune audit events: %v", err)
+			} else if deleted > 0 {
+				logger.Info("Pruned %d audit events older than %d days", deleted, jm.Config.Audit.RetentionDays)
+			}
 			err = database.ArchiveInactiveClusters(jm.Db, 72*time.Hour)
 			if err != nil {
 				logger.Error("Failed to archive inactive clusters: %v", err)
@@ -176,6 +235,96 @@ func (jm *JobManager) startPruning() {
 	}
 }
 
+func (jm *JobManager) startLDAPSync() {
+	defer jm.wg.Done()
+	ldapCfg := jm.Config.Auth.LDAP
+	if !ldapCfg.Enabled || !ldapCfg.Sync.Enabled {
+		return
+	}
+
+	interval, err := time.ParseDuration(ldapCfg.Sync.Interval)
+	if err != nil || interval <= 0 {
+		interval = time.Hour
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	authenticator := auth.NewLDAPAuthenticator(ldapCfg)
+	for {
+		select {
+		case <-ticker.C:
+			result, err := authenticator.SyncUsers(jm.Db)
+			if err != nil {
+				logger.Error("LDAP user sync failed: %v", err)
+				continue
+			}
+			if result.Disabled > 0 || result.Enabled > 0 || result.RoleUpdates > 0 {
+				details := fmt.Sprintf("LDAP sync checked %d users: %d disabled, %d re-enabled, %d role updates",
+					result.Checked, result.Disabled, result.Enabled, result.RoleUpdates)
+				event := &database.OperationalEvent{
+					EventType: "ldap_sync",
+					Initiator: "system",
+					Details:   details,
+				}
+				if err := database.CreateOperationalEvent(jm.Db, event); err != nil {
+					logger.Warn("Failed to record LDAP sync event: %v", err)
+				}
+				logger.Info("%s", details)
+			}
+		case <-jm.close:
+			return
+		}
+	}
+}
+
+// startAlertEvaluation periodically evaluates the alert rules against all jobs.
+func (jm *JobManager) startAlertEvaluation() {
+	defer jm.wg.Done()
+	if jm.Alerts == nil {
+		return
+	}
+
+	interval, err := time.ParseDuration(jm.Config.Alerting.EvaluationInterval)
+	if err != nil || interval <= 0 {
+		interval = 15 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := jm.Alerts.Evaluate(time.Now()); err != nil {
+				logger.Error("Alert evaluation failed: %v", err)
+			}
+		case <-jm.close:
+			return
+		}
+	}
+}
+
+// startPartitionMetricsDownsampling periodically merges aged per-partition
+// metrics into coarser buckets to bound the size of the time series.
+func (jm *JobManager) startPartitionMetricsDownsampling() {
+	defer jm.wg.Done()
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			removed, err := database.DownsamplePartitionMetrics(jm.Db, database.PartitionMetricsTiers, time.Now())
+			if err != nil {
+				logger.Error("Failed to downsample partition metrics: %v", err)
+			} else if removed > 0 {
+				logger.Debug("Downsampled partition metrics, %d points merged", removed)
+			}
+		case <-jm.close:
+			return
+		}
+	}
+}
+
 func (jm *JobManager) startComplianceScheduler() {
 	defer jm.wg.Done()
 	if !jm.Config.Compliance.Schedule.Enabled {
@@ -285,7 +434,7 @@ func (jm *JobManager) StartAllJobs() error {
 	for _, job := range jobs {
 		if job.Status != "active" {
 			if err := jm.StartJob(job.ID); err != nil {
-				logger.Error("Failed to start job %s: %v", job.ID, err)
+				jobLog(job.ID).Error("Failed to start job %s: %v", job.ID, err)
 			}
 		}
 	}
@@ -354,9 +503,9 @@ func (jm *JobManager) RestartAllJobs() error {
 	jm.Mu.Unlock()
 
 	for _, jobID := range runningJobs {
-		logger.Info("Stopping job %s for restart", jobID)
+		jobLog(jobID).Info("Stopping job %s for restart", jobID)
 		if err := jm.StopJob(jobID); err != nil {
-			logger.Error("Failed to stop job %s during restart all: %v", jobID, err)
+			jobLog(jobID).Error("Failed to stop job %s during restart all: %v", jobID, err)
 		}
 	}
 
@@ -365,7 +514,7 @@ func (jm *JobManager) RestartAllJobs() error {
 		if job.Status == "active" || job.Status == "paused" {
 			logger.Info("Starting job '%s' (%s)", job.Name, job.ID)
 			if err := jm.StartJob(job.ID); err != nil {
-				logger.Error("Failed to start job %s during restart all: %v", job.ID, err)
+				jobLog(job.ID).Error("Failed to start job %s during restart all: %v", job.ID, err)
 			} else {
 				restartedCount++
 			}
@@ -376,7 +525,9 @@ func (jm *JobManager) RestartAllJobs() error {
 	return nil
 }
 
-func (jm *JobManager) RestartJob(jobID string) error {
+func (jm *JobManager) RestartJob(jobID string) (err error) {
+	defer traceJobOperation("RestartJob", jobID)(&err)
+
 	job, err := database.GetJob(jm.Db, jobID)
 	if err != nil {
 		return fmt.Errorf("failed to get job %s: %v", jobID, err)
@@ -402,10 +553,12 @@ func (jm *JobManager) RestartJob(jobID string) error {
 	return nil
 }
 
-func (jm *JobManager) ForceRestartJob(jobID string) error {
+func (jm *JobManager) ForceRestartJob(jobID string) (err error) {
+	defer traceJobOperation("ForceRestartJob", jobID)(&err)
+
 	jm.Mu.Lock()
 	if kafMirror, ok := jm.KafMirrors[jobID]; ok {
-		logger.Info("Forcefully stopping existing instance of job %s", jobID)
+		jobLog(jobID).Info("Forcefully stopping existing instance of job %s", jobID)
 		kafMirror.Stop()
 		delete(jm.KafMirrors, jobID)
 	}
@@ -421,7 +574,7 @@ func (jm *JobManager) ForceRestartJob(jobID string) error {
 		return fmt.Errorf("failed to reset job state before force-restart: %v", err)
 	}
 
-	logger.Info("Performing pre-flight health checks for job %s", jobID)
+	jobLog(jobID).Info("Performing pre-flight health checks for job %s", jobID)
 	sourceCluster, err := database.GetCluster(jm.Db, job.SourceClusterName)
 	if err != nil {
 		return fmt.Errorf("failed to get source cluster: %v", err)
@@ -443,7 +596,7 @@ func (jm *JobManager) ForceRestartJob(jobID string) error {
 		return fmt.Errorf("mirror state validation failed: %v", err)
 	}
 
-	logger.Info("Pre-flight checks passed. Starting job %s", jobID)
+	jobLog(jobID).Info("Pre-flight checks passed. Starting job %s", jobID)
 	return jm.StartJob(jobID)
 }
 
@@ -462,20 +615,22 @@ func (jm *JobManager) StopAllJobs() error {
 
 		job, err := database.GetJob(jm.Db, jobID)
 		if err != nil {
-			logger.Error("Failed to get job %s for status update: %v", jobID, err)
+			jobLog(jobID).Error("Failed to get job %s for status update: %v", jobID, err)
 			continue
 		}
 
 		job.Status = "paused"
 		if err := database.UpdateJob(jm.Db, job); err != nil {
-			logger.Error("Failed to update status for job %s: %v", jobID, err)
+			jobLog(jobID).Error("Failed to update status for job %s: %v", jobID, err)
 		}
 	}
 
 	return nil
 }
 
-func (jm *JobManager) StartJob(jobID string) error {
+func (jm *JobManager) StartJob(jobID string) (err error) {
+	defer traceJobOperation("StartJob", jobID)(&err)
+
 	jm.Mu.Lock()
 	defer jm.Mu.Unlock()
 
@@ -486,7 +641,7 @@ func (jm *JobManager) StartJob(jobID string) error {
 
 	job, err := database.GetJob(jm.Db, jobID)
 	if err != nil {
-		logger.Error("Failed to get job %s from database: %v", jobID, err)
+		jobLog(jobID).Error("Failed to get job %s from database: %v", jobID, err)
 		return err
 	}
 
@@ -508,23 +663,28 @@ func (jm *JobManager) StartJob(jobID string) error {
 
 	mappings, err := database.GetMappingsForJob(jm.Db, jobID)
 	if err != nil {
-		logger.Error("Failed to get mappings for job %s: %v", jobID, err)
+		jobLog(jobID).Error("Failed to get mappings for job %s: %v", jobID, err)
 		return err
 	}
 
 	jobConfig, err := jm.buildJobConfig(sourceCluster, targetCluster, mappings, job)
 	if err != nil {
-		logger.Error("Failed to build job config for job %s: %v", jobID, err)
+		jobLog(jobID).Error("Failed to build job config for job %s: %v", jobID, err)
 		return err
 	}
+	if job.JobType == database.JobTypeBackfill && backfillCopied(jobConfig.Replication.Backfill) {
+		return fmt.Errorf("backfill job %s has already completed", jobID)
+	}
 
 	jobConfig.Replication.JobID = jobID
 	kafMirror, err := jm.KafMirrorFactory(jobConfig)
 	if err != nil {
-		logger.Error("Failed to create KafMirror for job %s: %v", jobID, err)
+		jobLog(jobID).Error("Failed to create KafMirror for job %s: %v", jobID, err)
 		return err
 	}
 
+	jm.watchMirror(kafMirror)
+
 	logger.Info("Starting job '%s' (%s)", job.Name, jobID)
 	kafMirror.Start(jobID, jm.ProcessMetrics, jm.handleJobPanic)
 	jm.KafMirrors[jobID] = kafMirror
@@ -533,7 +693,7 @@ func (jm *JobManager) StartJob(jobID string) error {
 	job.FailedReason = nil
 	err = database.UpdateJob(jm.Db, job)
 	if err != nil {
-		logger.Error("Failed to update job status for job %s: %v", jobID, err)
+		jobLog(jobID).Error("Failed to update job status for job %s: %v", jobID, err)
 		// Rollback by stopping the kaf-mirror instance
 		kafMirror.Stop()
 		delete(jm.KafMirrors, jobID)
@@ -541,6 +701,7 @@ func (jm *JobManager) StartJob(jobID string) error {
 	}
 
 	logger.Info("Successfully started job '%s' (%s)", job.Name, jobID)
+	jm.Webhooks.Emit(database.WebhookEventJobStarted, jobID, jobEventData(job))
 
 	// Trigger an inventory snapshot when the job starts
 	go jm.CreateInventorySnapshot(jobID, "manual")
@@ -556,6 +717,13 @@ func (jm *JobManager) StartJob(jobID string) error {
 
 // ProcessMetrics is the callback function for the kaf-mirror to send metrics.
 func (jm *JobManager) ProcessMetrics(metric database.ReplicationMetric) {
+	jm.metricsMu.Lock()
+	if jm.latestMetrics == nil {
+		jm.latestMetrics = make(map[string]database.ReplicationMetric)
+	}
+	jm.latestMetrics[metric.JobID] = metric
+	jm.metricsMu.Unlock()
+
 	if jm.metricsSink != nil {
 		if err := jm.metricsSink.Send(metric); err != nil {
 			logger.Error("Failed to send metric to sink: %v", err)
@@ -565,10 +733,46 @@ func (jm *JobManager) ProcessMetrics(metric database.ReplicationMetric) {
 	if err := database.InsertMetrics(jm.Db, &metric); err != nil {
 		logger.Error("Failed to insert metric into database: %v", err)
 	}
+
+	if err := database.InsertPartitionMetrics(jm.Db, metric.PartitionMetrics); err != nil {
+		logger.Error("Failed to insert partition metrics into database: %v", err)
+	}
+
+	if jm.Alerts != nil {
+		jm.Alerts.ObserveMetric(metric)
+	}
+
+	if jm.Hub != nil {
+		jm.Hub.Publish(jobMetricsChannel, metric.JobID, metric)
+	}
+}
+
+// jobLog returns a logger tagged with the job.
+func jobLog(jobID string) *logger.Entry {
+	return logger.With(logger.JobID(jobID), logger.Component("manager"))
+}
+
+// MetricsSinkHealth returns the delivery state of the configured metric sinks.
+func (jm *JobManager) MetricsSinkHealth() []metrics.SinkHealth {
+	if reporter, ok := jm.metricsSink.(interface{ Health() []metrics.SinkHealth }); ok {
+		return reporter.Health()
+	}
+	return nil
+}
+
+// LatestMetric returns the most recent in-memory metric reported by a job,
+// including its per-topic and per-partition lag breakdown.
+func (jm *JobManager) LatestMetric(jobID string) (database.ReplicationMetric, bool) {
+	jm.metricsMu.RLock()
+	defer jm.metricsMu.RUnlock()
+	metric, ok := jm.latestMetrics[jobID]
+	return metric, ok
 }
 
 // StopJob stops a replication job by its ID.
-func (jm *JobManager) StopJob(jobID string) error {
+func (jm *JobManager) StopJob(jobID string) (err error) {
+	defer traceJobOperation("StopJob", jobID)(&err)
+
 	jm.Mu.Lock()
 	defer jm.Mu.Unlock()
 
@@ -595,7 +799,7 @@ func (jm *JobManager) StopJob(jobID string) error {
 	// Update job status in database synchronously to avoid race conditions
 	job, err := database.GetJob(jm.Db, jobID)
 	if err != nil {
-		logger.Error("Failed to get job %s from database for status update: %v", jobID, err)
+		jobLog(jobID).Error("Failed to get job %s from database for status update: %v", jobID, err)
 		return nil // Don't return error - job was stopped successfully in memory
 	}
 
@@ -603,11 +807,12 @@ func (jm *JobManager) StopJob(jobID string) error {
 	job.FailedReason = nil
 	err = database.UpdateJob(jm.Db, job)
 	if err != nil {
-		logger.Error("Failed to update job status for job %s: %v", jobID, err)
+		jobLog(jobID).Error("Failed to update job status for job %s: %v", jobID, err)
 		// Don't return error - job was stopped successfully in memory
 	}
 
 	logger.Info("Successfully stopped job '%s'", jobID)
+	jm.Webhooks.Emit(database.WebhookEventJobStopped, jobID, jobEventData(job))
 	return nil
 }
 
@@ -624,15 +829,31 @@ func (jm *JobManager) handleJobPanic(jobID string, reason string) {
 
 	job, err := database.GetJob(jm.Db, jobID)
 	if err != nil {
-		logger.Error("Failed to get job %s from database for panic update: %v", jobID, err)
+		jobLog(jobID).Error("Failed to get job %s from database for panic update: %v", jobID, err)
 		return
 	}
 
 	job.Status = "failed"
 	job.FailedReason = &reason
 	if err := database.UpdateJob(jm.Db, job); err != nil {
-		logger.Error("Failed to update job status to failed for job %s: %v", jobID, err)
+		jobLog(jobID).Error("Failed to update job status to failed for job %s: %v", jobID, err)
 	}
+	jm.Webhooks.Emit(database.WebhookEventJobFailed, jobID, jobEventData(job))
+}
+
+// jobEventData is the webhook payload data of job lifecycle events.
+func jobEventData(job *database.ReplicationJob) map[string]interface{} {
+	data := map[string]interface{}{
+		"job_id":         job.ID,
+		"name":           job.Name,
+		"status":         job.Status,
+		"source_cluster": job.SourceClusterName,
+		"target_cluster": job.TargetClusterName,
+	}
+	if job.FailedReason != nil {
+		data["failed_reason"] = *job.FailedReason
+	}
+	return data
 }
 
 // startAIAnalysis runs intelligent anomaly-based AI analysis every 5 minutes with cost-effective triggering
@@ -994,10 +1215,12 @@ func (jm *JobManager) buildTimeSeriesPrompt(jobID, jobName string, metrics []dat
 - **Lag Range**: %d - %d messages
 - **Total Errors**: %d errors
 
+## Partition Breakdown (last 10 minutes)
+%s
 ## Time Series Pattern Analysis
 Please analyze this Kafka replication job's performance patterns and identify:
 
-1. **Performance Anomalies**: Any unusual spikes, drops, or patterns in throughput or lag
+1. **Performance Anomalies**: Any unusual spikes, drops, or patterns in throughput or lag, including single hot or stuck partitions
 2. **Trend Analysis**: Whether performance is improving, degrading, or stable
 3. **Error Correlation**: Any correlation between errors and performance degradation
 4. **Optimization Recommendations**: Specific suggestions for improving performance
@@ -1010,7 +1233,50 @@ Please analyze this Kafka replication job's performance patterns and identify:
 
 Based on this time-series data, what are your key findings and recommendations?`,
 		jobID, jobName, len(metrics), avgThroughput, minThroughput, maxThroughput,
-		throughputTrend, avgLag, minLag, maxLag, totalErrors)
+		throughputTrend, avgLag, minLag, maxLag, totalErrors,
+		jm.partitionBreakdownForPrompt(jobID, time.Now().Add(-10*time.Minute)))
+}
+
+// partitionBreakdownForPrompt describes the most lagging, failing and busiest
+// partitions of a job since the given time, flagging skew and stuck partitions.
+func (jm *JobManager) partitionBreakdownForPrompt(jobID string, since time.Time) string {
+	summaries, err := database.GetPartitionSummaries(jm.Db, jobID, since)
+	if err != nil || len(summaries) == 0 {
+		return "No per-partition data recorded.\n"
+	}
+
+	var total int64
+	for _, p := range summaries {
+		total += p.MessagesReplicated
+	}
+	fairShare := 1.0 / float64(len(summaries))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "- **Partitions**: %d\n", len(summaries))
+	for _, p := range database.HottestPartitions(summaries, 10) {
+		share := 0.0
+		if total > 0 {
+			share = float64(p.MessagesReplicated) / float64(total)
+		}
+		var flags []string
+		if p.MessageLag > 0 && p.MessagesReplicated == 0 {
+			flags = append(flags, "STUCK")
+		}
+		if len(summaries) > 1 && share > 2*fairShare {
+			flags = append(flags, "HOT")
+		}
+		if p.ErrorCount > 0 {
+			flags = append(flags, "ERRORS")
+		}
+		flagText := ""
+		if len(flags) > 0 {
+			flagText = " [" + strings.Join(flags, ", ") + "]"
+		}
+		fmt.Fprintf(&b, "- **%s[%d]**%s: consumed %d, replicated %d (%.0f%% of job), errors %d, lag %d messages / %.1fs (max %.1fs)\n",
+			p.Topic, p.Partition, flagText, p.MessagesConsumed, p.MessagesReplicated, share*100,
+			p.ErrorCount, p.MessageLag, p.LagSeconds, p.MaxLagSeconds)
+	}
+	return b.String()
 }
 
 // startHistoricalAnalysis runs long-term historical analysis every 24 hours
@@ -1052,7 +1318,7 @@ func (jm *JobManager) performHistoricalAnalysis() {
 func (jm *JobManager) AnalyzeJobHistory(jobID, jobName string, periodDays int, granularity string) {
 	metrics, err := database.GetAggregatedHistoricalMetrics(jm.Db, jobID, periodDays, granularity)
 	if err != nil {
-		logger.Error("Failed to fetch historical metrics for AI analysis (job %s): %v", jobID, err)
+		jobLog(jobID).Error("Failed to fetch historical metrics for AI analysis (job %s): %v", jobID, err)
 		return
 	}
 
@@ -1064,7 +1330,7 @@ func (jm *JobManager) AnalyzeJobHistory(jobID, jobName string, periodDays int, g
 
 	insight, err := jm.AIClient.GetAnomalyDetection(context.Background(), analysisPrompt)
 	if err != nil {
-		logger.Error("Failed to get AI historical analysis for job %s: %v", jobID, err)
+		jobLog(jobID).Error("Failed to get AI historical analysis for job %s: %v", jobID, err)
 		return
 	}
 
@@ -1078,7 +1344,7 @@ func (jm *JobManager) AnalyzeJobHistory(jobID, jobName string, periodDays int, g
 	}
 
 	if err := database.InsertAIInsight(jm.Db, aiInsight); err != nil {
-		logger.Error("Failed to insert AI historical insight for job %s: %v", jobID, err)
+		jobLog(jobID).Error("Failed to insert AI historical insight for job %s: %v", jobID, err)
 	}
 }
 
@@ -1099,17 +1365,20 @@ func (jm *JobManager) buildHistoricalAnalysisPrompt(jobID, jobName string, metri
 
 ## Historical Performance Summary (Daily Averages)
 %s
+## Partition Breakdown (whole period)
+%s
 ## Long-Term Trend Analysis Request
 Based on the historical data provided, please analyze the long-term performance trends for this Kafka replication job. Your analysis should focus on:
 
-1.  **Performance Trends**: Is there a gradual increase or decrease in throughput or lag over time?
+1.  **Performance Trends**: Is there a gradual increase or decrease in throughput or lag over time? Note partitions that persistently lag or carry a disproportionate share of traffic.
 2.  **Recurring Patterns**: Are there any weekly or cyclical patterns? (e.g., performance dips on weekends, or high traffic at the start of the week).
 3.  **Stability Assessment**: How stable is the job's performance over this period?
 4.  **Capacity Planning Insights**: Based on the trends, are there any early indicators that capacity (CPU, network, broker performance) might become an issue in the future?
 5.  **Actionable Recommendations**: Suggest any proactive tuning or configuration changes to address negative trends or improve long-term stability.
 
 Provide a concise summary of your findings, focusing on strategic insights rather than immediate alerts.`,
-		jobID, jobName, periodDays, metricsSummary)
+		jobID, jobName, periodDays, metricsSummary,
+		jm.partitionBreakdownForPrompt(jobID, time.Now().AddDate(0, 0, -periodDays)))
 }
 
 // determineSeverity analyzes metrics to determine insight severity level
@@ -1160,7 +1429,7 @@ func (jm *JobManager) CreateInventorySnapshot(jobID, snapshotType string) {
 	if atomic.LoadInt32(&jm.closing) == 1 {
 		return
 	}
-	logger.Info("Creating inventory snapshot for job %s (type: %s)", jobID, snapshotType)
+	jobLog(jobID).Info("Creating inventory snapshot for job %s (type: %s)", jobID, snapshotType)
 
 	// Check if database and required tables are available
 	var tablesExist int
@@ -1170,14 +1439,14 @@ func (jm *JobManager) CreateInventorySnapshot(jobID, snapshotType string) {
 		return
 	}
 	if tablesExist < 3 {
-		logger.Warn("Required database tables not available, skipping inventory snapshot for job %s", jobID)
+		jobLog(jobID).Warn("Required database tables not available, skipping inventory snapshot for job %s", jobID)
 		return
 	}
 
 	// Create the snapshot record in the database
 	snapshotID, err := database.CreateInventorySnapshot(jm.Db, jobID, snapshotType)
 	if err != nil {
-		logger.Error("Failed to create inventory snapshot for job %s: %v", jobID, err)
+		jobLog(jobID).Error("Failed to create inventory snapshot for job %s: %v", jobID, err)
 		return
 	}
 
@@ -1231,7 +1500,7 @@ func (jm *JobManager) CreateInventorySnapshot(jobID, snapshotType string) {
 		jm.captureClusterInventory(snapshotID, job.TargetClusterName, "target")
 	}()
 
-	logger.Info("Inventory snapshot capture initiated for job %s", jobID)
+	jobLog(jobID).Info("Inventory snapshot capture initiated for job %s", jobID)
 }
 
 func (jm *JobManager) captureClusterInventory(snapshotID int, clusterName, clusterType string) {
@@ -1373,6 +1642,28 @@ func (jm *JobManager) captureClusterInventory(snapshotID int, clusterName, clust
 		}
 	}
 
+	// Record ACLs; clusters without an authorizer have none to describe
+	acls, err := adminClient.DescribeACLs(context.Background())
+	if err != nil {
+		logger.Warn("Skipping ACL inventory for cluster %s: %v", clusterName, err)
+	}
+	for _, acl := range acls {
+		aclInventory := database.ACLInventory{
+			SnapshotID:   snapshotID,
+			ClusterType:  clusterType,
+			Principal:    acl.Principal,
+			Host:         acl.Host,
+			ResourceType: acl.ResourceType,
+			ResourceName: acl.ResourceName,
+			PatternType:  acl.PatternType,
+			Operation:    acl.Operation,
+			Permission:   acl.Permission,
+		}
+		if err := database.InsertACLInventory(jm.Db, aclInventory); err != nil {
+			logger.Error("Failed to insert ACL inventory for %s: %v", clusterName, err)
+		}
+	}
+
 	logger.Info("Completed cluster inventory capture for %s (%s)", clusterName, clusterType)
 }
 
@@ -1426,6 +1717,11 @@ func (jm *JobManager) buildJobConfig(sourceCluster, targetCluster *database.Kafk
 			APIKey:    sourceCluster.APIKey,
 			APISecret: sourceCluster.APISecret,
 		},
+		SchemaRegistry: config.SchemaRegistryConfig{
+			URL:      sourceCluster.SchemaRegistryURL,
+			Username: sourceCluster.SchemaRegistryUsername,
+			Password: sourceCluster.SchemaRegistryPassword,
+		},
 	}
 
 	targetConfig := config.ClusterConfig{
@@ -1435,6 +1731,11 @@ func (jm *JobManager) buildJobConfig(sourceCluster, targetCluster *database.Kafk
 			APIKey:    targetCluster.APIKey,
 			APISecret: targetCluster.APISecret,
 		},
+		SchemaRegistry: config.SchemaRegistryConfig{
+			URL:      targetCluster.SchemaRegistryURL,
+			Username: targetCluster.SchemaRegistryUsername,
+			Password: targetCluster.SchemaRegistryPassword,
+		},
 	}
 
 	jobConfig := &config.Config{
@@ -1449,6 +1750,41 @@ func (jm *JobManager) buildJobConfig(sourceCluster, targetCluster *database.Kafk
 		},
 		Topics: make([]config.TopicMapping, len(mappings)),
 	}
+	if job.StartPosition != nil {
+		jobConfig.Replication.StartPosition = config.StartPosition(*job.StartPosition)
+	}
+	if jm.Config != nil {
+		jobConfig.Monitoring.Tracing = jm.Config.Monitoring.Tracing
+		jobConfig.Replication.TopicConfigSync = jm.Config.Replication.TopicConfigSync
+		jobConfig.Replication.SourceTopicDeletion = jm.Config.Replication.SourceTopicDeletion
+		jobConfig.Replication.ACLSync = jm.Config.Replication.ACLSync
+		jobConfig.Replication.Dedup = jm.Config.Replication.Dedup
+		jobConfig.Replication.ErrorPolicy = jm.Config.Replication.ErrorPolicy
+	}
+	if job.ErrorPolicy != nil {
+		jobConfig.Replication.ErrorPolicy = config.ErrorPolicy(*job.ErrorPolicy)
+	}
+
+	overrides, err := database.TopicConfigOverrideMap(jm.Db, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get topic config overrides: %w", err)
+	}
+	merged := make(map[string]string, len(jobConfig.Replication.TopicConfigSync.Overrides)+len(overrides))
+	for key, value := range jobConfig.Replication.TopicConfigSync.Overrides {
+		merged[key] = value
+	}
+	for key, value := range overrides {
+		merged[key] = value
+	}
+	jobConfig.Replication.TopicConfigSync.Overrides = merged
+
+	if job.JobType == database.JobTypeBackfill {
+		ranges, err := database.GetBackfillRanges(jm.Db, job.ID)
+		if err != nil {
+			return nil, err
+		}
+		jobConfig.Replication.Backfill = backfillConfig(ranges)
+	}
 
 	for i, m := range mappings {
 		jobConfig.Topics[i] = config.TopicMapping{
@@ -1456,6 +1792,9 @@ func (jm *JobManager) buildJobConfig(sourceCluster, targetCluster *database.Kafk
 			Target:  m.TargetTopicPattern,
 			Enabled: m.Enabled,
 		}
+		if m.StartPosition != nil {
+			jobConfig.Topics[i].StartPosition = config.StartPosition(*m.StartPosition)
+		}
 	}
 
 	return jobConfig, nil
@@ -1493,13 +1832,13 @@ func (jm *JobManager) performTopicHealthChecks() {
 func (jm *JobManager) checkJobTopicHealth(job *database.ReplicationJob) {
 	sourceCluster, err := database.GetCluster(jm.Db, job.SourceClusterName)
 	if err != nil {
-		logger.Error("Failed to get source cluster for health check (job %s): %v", job.ID, err)
+		jobLog(job.ID).Error("Failed to get source cluster for health check (job %s): %v", job.ID, err)
 		return
 	}
 
 	mappings, err := database.GetMappingsForJob(jm.Db, job.ID)
 	if err != nil {
-		logger.Error("Failed to get mappings for health check (job %s): %v", job.ID, err)
+		jobLog(job.ID).Error("Failed to get mappings for health check (job %s): %v", job.ID, err)
 		return
 	}
 
@@ -1525,14 +1864,14 @@ func (jm *JobManager) checkJobTopicHealth(job *database.ReplicationJob) {
 
 	adminClient, err := kafka.NewAdminClient(sourceConfig)
 	if err != nil {
-		logger.Error("Failed to create admin client for health check (job %s): %v", job.ID, err)
+		jobLog(job.ID).Error("Failed to create admin client for health check (job %s): %v", job.ID, err)
 		return
 	}
 	defer adminClient.Close()
 
 	health, err := adminClient.CheckTopicHealth(context.Background(), topics)
 	if err != nil {
-		logger.Error("Failed to check topic health for job %s: %v", job.ID, err)
+		jobLog(job.ID).Error("Failed to check topic health for job %s: %v", job.ID, err)
 		return
 	}
 
@@ -1814,6 +2153,7 @@ func (jm *JobManager) startJobWithNewConfig(jobID string, job *database.Replicat
 	if err != nil {
 		return fmt.Errorf("failed to create KafMirror: %v", err)
 	}
+	jm.watchMirror(kafMirror)
 
 	kafMirror.Start(jobID, jm.ProcessMetrics, jm.handleJobPanic)
 	jm.KafMirrors[jobID] = kafMirror
@@ -1869,7 +2209,9 @@ func (jm *JobManager) healthCheckCluster(cluster *database.KafkaCluster, cluster
 	return nil
 }
 
-func (jm *JobManager) validateMirrorState(jobID string, sourceCluster, targetCluster *database.KafkaCluster) error {
+func (jm *JobManager) validateMirrorState(jobID string, sourceCluster, targetCluster *database.KafkaCluster) (err error) {
+	defer traceJobOperation("ValidateMirrorState", jobID)(&err)
+
 	mappings, err := database.GetMappingsForJob(jm.Db, jobID)
 	if err != nil {
 		return fmt.Errorf("failed to get mappings: %v", err)
@@ -1939,7 +2281,7 @@ func (jm *JobManager) validateMirrorState(jobID string, sourceCluster, targetClu
 
 	existingProgress, err := database.GetMirrorProgress(jm.Db, jobID)
 	if err == nil && len(existingProgress) > 0 {
-		logger.Info("Found existing mirror progress for job %s - validating for safe restart", jobID)
+		jobLog(jobID).Info("Found existing mirror progress for job %s - validating for safe restart", jobID)
 
 		consumerGroup := fmt.Sprintf("kaf-mirror-job-%s", jobID)
 		mirrorAnalysis, err := targetAdmin.AnalyzeMirrorState(ctx, sourceAdmin, jobID, topicMap, consumerGroup)
@@ -1950,11 +2292,34 @@ func (jm *JobManager) validateMirrorState(jobID string, sourceCluster, targetClu
 		}
 	}
 
-	logger.Info("Mirror state validation passed for job %s: %d topics validated", jobID, len(enabledTopics))
+	jobLog(jobID).Info("Mirror state validation passed for job %s: %d topics validated", jobID, len(enabledTopics))
 	return nil
 }
 
 // GetJobTopicHealth retrieves the health of all topics for a given job.
+// recordTopicChange records a topic change found by the discovery of a running job
+// as an operational event.
+// watchMirror connects the optional notifications of a mirror to the job manager.
+func (jm *JobManager) watchMirror(kafMirror kafka.KafMirror) {
+	if notifier, ok := kafMirror.(kafka.TopicChangeNotifier); ok {
+		notifier.OnTopicChange(jm.recordTopicChange)
+	}
+	if notifier, ok := kafMirror.(kafka.BackfillNotifier); ok {
+		notifier.OnBackfillProgress(jm.recordBackfillProgress)
+	}
+}
+
+func (jm *JobManager) recordTopicChange(jobID string, change kafka.TopicChange) {
+	event := &database.OperationalEvent{
+		EventType: change.Type,
+		Initiator: "system",
+		Details:   fmt.Sprintf("Job %s: %s", jobID, change.Details),
+	}
+	if err := database.CreateOperationalEvent(jm.Db, event); err != nil {
+		jobLog(jobID).Error("Failed to record topic change for job %s: %v", jobID, err)
+	}
+}
+
 func (jm *JobManager) GetJobTopicHealth(jobID string) ([]kafka.TopicHealth, error) {
 	job, err := database.GetJob(jm.Db, jobID)
 	if err != nil {
@@ -2035,11 +2400,15 @@ func (jm *JobManager) updateAllMirrorStates() {
 }
 
 func (jm *JobManager) updateMirrorState(jobID string) {
-	logger.Info("Updating comprehensive mirror state for job %s", jobID)
+	jobLog(jobID).Info("Updating comprehensive mirror state for job %s", jobID)
 
 	job, err := database.GetJob(jm.Db, jobID)
 	if err != nil {
-		logger.Error("Failed to get job %s for mirror state update: %v", jobID, err)
+		jobLog(jobID).Error("Failed to get job %s for mirror state update: %v", jobID, err)
+		return
+	}
+	if job.JobType == database.JobTypeBackfill {
+		// Backfills copy old ranges, so their target offsets never line up with the source
 		return
 	}
 
@@ -2057,7 +2426,7 @@ func (jm *JobManager) updateMirrorState(jobID string) {
 
 	mappings, err := database.GetMappingsForJob(jm.Db, jobID)
 	if err != nil {
-		logger.Error("Failed to get mappings for job %s for mirror state update: %v", jobID, err)
+		jobLog(jobID).Error("Failed to get mappings for job %s for mirror state update: %v", jobID, err)
 		return
 	}
 
@@ -2069,7 +2438,7 @@ func (jm *JobManager) updateMirrorState(jobID string) {
 	}
 
 	if len(topicMap) == 0 {
-		logger.Warn("No enabled topics found for job %s, skipping mirror state update", jobID)
+		jobLog(jobID).Warn("No enabled topics found for job %s, skipping mirror state update", jobID)
 		return
 	}
 
@@ -2111,7 +2480,7 @@ func (jm *JobManager) updateMirrorState(jobID string) {
 	// Perform comprehensive cross-cluster analysis similar to validate-mirror
 	offsetComparison, err := targetAdmin.CompareClusterOffsets(ctx, sourceAdmin, topicMap)
 	if err != nil {
-		logger.Error("Failed to compare cluster offsets for job %s: %v", jobID, err)
+		jobLog(jobID).Error("Failed to compare cluster offsets for job %s: %v", jobID, err)
 		return
 	}
 
@@ -2119,7 +2488,7 @@ func (jm *JobManager) updateMirrorState(jobID string) {
 	consumerGroup := fmt.Sprintf("kaf-mirror-job-%s", jobID)
 	mirrorAnalysis, err := targetAdmin.AnalyzeMirrorState(ctx, sourceAdmin, jobID, topicMap, consumerGroup)
 	if err != nil {
-		logger.Error("Failed to analyze mirror state for job %s: %v", jobID, err)
+		jobLog(jobID).Error("Failed to analyze mirror state for job %s: %v", jobID, err)
 		return
 	}
 
@@ -2165,7 +2534,7 @@ func (jm *JobManager) updateMirrorState(jobID string) {
 		}
 
 		if err := database.CalculateResumePoints(jm.Db, jobID, resumePointsMap, nil); err != nil {
-			logger.Error("Failed to store resume points for job %s: %v", jobID, err)
+			jobLog(jobID).Error("Failed to store resume points for job %s: %v", jobID, err)
 		}
 	}
 
@@ -2193,7 +2562,18 @@ func (jm *JobManager) updateMirrorState(jobID string) {
 
 	if len(gaps) > 0 {
 		if err := database.DetectMirrorGaps(jm.Db, jobID, gaps); err != nil {
-			logger.Error("Failed to detect mirror gaps for job %s: %v", jobID, err)
+			jobLog(jobID).Error("Failed to detect mirror gaps for job %s: %v", jobID, err)
+		} else {
+			var totalGap int64
+			for _, gap := range gaps {
+				totalGap += gap.GapSize
+			}
+			jm.Webhooks.Emit(database.WebhookEventGapDetected, jobID, map[string]interface{}{
+				"job_id":         jobID,
+				"gap_count":      len(gaps),
+				"total_gap_size": totalGap,
+				"gaps":           gaps,
+			})
 		}
 	}
 
@@ -2226,7 +2606,7 @@ func (jm *JobManager) updateMirrorState(jobID string) {
 	}
 
 	if _, err := database.StoreMirrorStateAnalysis(jm.Db, *dbMirrorAnalysis); err != nil {
-		logger.Error("Failed to store mirror state analysis for job %s: %v", jobID, err)
+		jobLog(jobID).Error("Failed to store mirror state analysis for job %s: %v", jobID, err)
 	}
 
 	logger.Info("Successfully updated comprehensive mirror state for job %s: %d topics, %d gaps, %d critical issues",
@@ -0,0 +1,161 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"sort"
+	"time"
+)
+
+// OffsetResetRequest moves the consumer group of a job to a start position.
+type OffsetResetRequest struct {
+	Position config.StartPosition `json:"position"`
+	// Topics limits the reset to these source topics. Empty means every mapped topic,
+	// or the topics named by explicit offsets.
+	Topics []string `json:"topics,omitempty"`
+}
+
+// OffsetReset is the preview or the result of an offset reset.
+type OffsetReset struct {
+	JobID         string                       `json:"job_id"`
+	ConsumerGroup string                       `json:"consumer_group"`
+	Position      config.StartPosition         `json:"position"`
+	DryRun        bool                         `json:"dry_run"`
+	Partitions    []kafka.PartitionStartOffset `json:"partitions"`
+}
+
+// ErrResetRunningJob is returned when the offsets of a running job would be reset.
+var ErrResetRunningJob = errors.New("stop the job before resetting its offsets")
+
+// ResetJobOffsets resolves a start position against the source high water marks and
+// commits it as the job's consumer group offsets, so the job continues from there when
+// it is started again. With dryRun only the preview is returned; it also works while
+// the job is running.
+func (jm *JobManager) ResetJobOffsets(jobID string, req OffsetResetRequest, dryRun bool, initiator string) (*OffsetReset, error) {
+	if !req.Position.IsSet() {
+		return nil, fmt.Errorf("start position mode is required")
+	}
+	if err := req.Position.Validate(); err != nil {
+		return nil, err
+	}
+	if !dryRun {
+		jm.Mu.Lock()
+		_, running := jm.KafMirrors[jobID]
+		jm.Mu.Unlock()
+		if running {
+			return nil, ErrResetRunningJob
+		}
+	}
+
+	jobConfig, err := jm.loadJobConfig(jobID)
+	if err != nil {
+		return nil, err
+	}
+	clusters, err := kafka.OpenJobClusters(jobConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer clusters.Close()
+
+	topics, err := offsetResetTopics(req, clusters.TopicMap)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	group := kafka.JobConsumerGroup(jobID)
+	committed, err := kafka.CommittedOffsets(ctx, clusters.Source, group, topics)
+	if err != nil {
+		return nil, err
+	}
+	partitions, err := kafka.ResolveStartOffsets(ctx, clusters.Source, req.Position, topics, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	for i := range partitions {
+		if offset, ok := committed[partitions[i].Topic][partitions[i].Partition]; ok {
+			partitions[i].CommittedOffset = offset
+		}
+	}
+
+	reset := &OffsetReset{
+		JobID:         jobID,
+		ConsumerGroup: group,
+		Position:      req.Position,
+		DryRun:        dryRun,
+		Partitions:    partitions,
+	}
+	if dryRun {
+		return reset, nil
+	}
+
+	commits := make(map[string]map[int32]int64)
+	for _, partition := range partitions {
+		if commits[partition.Topic] == nil {
+			commits[partition.Topic] = make(map[int32]int64)
+		}
+		commits[partition.Topic][partition.Partition] = partition.Offset
+	}
+	if err := clusters.Source.CommitConsumerGroupOffsets(ctx, group, commits); err != nil {
+		return nil, err
+	}
+
+	event := &database.OperationalEvent{
+		EventType: "offset_reset",
+		Initiator: initiator,
+		Details:   fmt.Sprintf("Job %s: reset %d partitions of %v to the %s start position", jobID, len(partitions), topics, req.Position.Mode),
+	}
+	if err := database.CreateOperationalEvent(jm.Db, event); err != nil {
+		jobLog(jobID).Error("Failed to record offset reset event for job %s: %v", jobID, err)
+	}
+	return reset, nil
+}
+
+// offsetResetTopics returns the sorted source topics a reset applies to. Every topic
+// must be mirrored by the job.
+func offsetResetTopics(req OffsetResetRequest, topicMap map[string]string) ([]string, error) {
+	topics := req.Topics
+	if len(topics) == 0 && req.Position.Mode == config.StartPositionOffsets {
+		for topic := range req.Position.Offsets {
+			topics = append(topics, topic)
+		}
+	}
+	if len(topics) == 0 {
+		for topic := range topicMap {
+			topics = append(topics, topic)
+		}
+	}
+	for _, topic := range topics {
+		if _, ok := topicMap[topic]; !ok {
+			return nil, fmt.Errorf("topic %s is not mirrored by the job", topic)
+		}
+	}
+	for topic := range req.Position.Offsets {
+		if _, ok := topicMap[topic]; !ok {
+			return nil, fmt.Errorf("topic %s is not mirrored by the job", topic)
+		}
+	}
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("the job has no topics to reset")
+	}
+	sorted := append([]string(nil), topics...)
+	sort.Strings(sorted)
+	return sorted, nil
+}
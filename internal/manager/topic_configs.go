@@ -0,0 +1,76 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"time"
+)
+
+// topicConfigTimeout bounds an on-demand topic config check or sync.
+const topicConfigTimeout = 60 * time.Second
+
+// GetJobTopicConfigDrift compares the configs of the job's target topics with
+// their source topics without changing anything.
+func (jm *JobManager) GetJobTopicConfigDrift(jobID string) (*kafka.TopicConfigReport, error) {
+	jobConfig, err := jm.loadJobConfig(jobID)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), topicConfigTimeout)
+	defer cancel()
+	return kafka.CheckTopicConfigDrift(ctx, jobConfig)
+}
+
+// SyncJobTopicConfigs fixes the config drift of the job's target topics. With
+// dryRun the target cluster only validates the changes.
+func (jm *JobManager) SyncJobTopicConfigs(jobID string, dryRun bool) (*kafka.TopicConfigReport, error) {
+	jobConfig, err := jm.loadJobConfig(jobID)
+	if err != nil {
+		return nil, err
+	}
+	if jobConfig.Replication.TopicConfigSync.Mode == config.TopicConfigSyncModeOff {
+		return nil, fmt.Errorf("topic config sync is disabled")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), topicConfigTimeout)
+	defer cancel()
+	return kafka.SyncTopicConfigs(ctx, jobConfig, dryRun)
+}
+
+func (jm *JobManager) loadJobConfig(jobID string) (*config.Config, error) {
+	job, err := database.GetJob(jm.Db, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %s: %v", jobID, err)
+	}
+	sourceCluster, err := database.GetCluster(jm.Db, job.SourceClusterName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get source cluster %s: %v", job.SourceClusterName, err)
+	}
+	targetCluster, err := database.GetCluster(jm.Db, job.TargetClusterName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get target cluster %s: %v", job.TargetClusterName, err)
+	}
+	mappings, err := database.GetMappingsForJob(jm.Db, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mappings for job %s: %v", jobID, err)
+	}
+	jobConfig, err := jm.buildJobConfig(sourceCluster, targetCluster, mappings, job)
+	if err != nil {
+		return nil, err
+	}
+	jobConfig.Replication.JobID = jobID
+	return jobConfig, nil
+}
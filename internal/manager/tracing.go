@@ -0,0 +1,40 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("kaf-mirror/manager")
+
+// traceJobOperation starts a span for a job lifecycle operation. The returned
+// function ends it, recording the error the operation returned, if any:
+//
+//	func (jm *JobManager) StopJob(jobID string) (err error) {
+//		defer traceJobOperation("StopJob", jobID)(&err)
+func traceJobOperation(operation, jobID string) func(*error) {
+	_, span := tracer.Start(context.Background(), "JobManager."+operation,
+		trace.WithAttributes(attribute.String("kaf_mirror.job_id", jobID)))
+	return func(errp *error) {
+		if errp != nil && *errp != nil {
+			span.RecordError(*errp)
+			span.SetStatus(codes.Error, (*errp).Error())
+		}
+		span.End()
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/pkg/logger"
+	"time"
+)
+
+// ErrVerificationInProgress is returned when a job already has a running content verification.
+var ErrVerificationInProgress = errors.New("a content verification of this job is already running")
+
+// StartContentVerification compares the records of the job's topics on both
+// clusters in the background and returns the running verification. sampleRate
+// is the share of each partition compared; 0 compares everything.
+func (jm *JobManager) StartContentVerification(jobID string, sampleRate float64, initiator string) (*database.ContentVerification, error) {
+	if sampleRate == 0 {
+		sampleRate = 1
+	}
+	if sampleRate < 0 || sampleRate > 1 {
+		return nil, fmt.Errorf("sample rate must be greater than 0 and at most 1, got %g", sampleRate)
+	}
+	running, err := database.HasRunningContentVerification(jm.Db, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if running {
+		return nil, ErrVerificationInProgress
+	}
+	jobConfig, err := jm.loadJobConfig(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &database.ContentVerification{
+		JobID:      jobID,
+		Status:     database.VerificationRunning,
+		SampleRate: sampleRate,
+		Initiator:  initiator,
+	}
+	if err := database.CreateContentVerification(jm.Db, v); err != nil {
+		return nil, err
+	}
+	snapshot := *v
+
+	jm.wg.Add(1)
+	go func() {
+		defer jm.wg.Done()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			select {
+			case <-jm.close:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		partitions, err := kafka.VerifyContent(ctx, jobConfig, sampleRate)
+		jm.finishContentVerification(v, partitions, err)
+	}()
+	return &snapshot, nil
+}
+
+// GetContentVerification returns a content verification of a job.
+func (jm *JobManager) GetContentVerification(jobID string, id int) (*database.ContentVerification, error) {
+	v, err := database.GetContentVerification(jm.Db, id)
+	if err != nil || v.JobID != jobID {
+		return nil, fmt.Errorf("content verification #%d of job %s not found", id, jobID)
+	}
+	return v, nil
+}
+
+// ListContentVerifications returns the content verifications of a job, newest first.
+func (jm *JobManager) ListContentVerifications(jobID string) ([]database.ContentVerification, error) {
+	return database.ListContentVerifications(jm.Db, jobID)
+}
+
+// failInterruptedVerifications marks verifications left running by a previous
+// process as failed.
+func (jm *JobManager) failInterruptedVerifications() {
+	if _, err := database.FailRunningContentVerifications(jm.Db, "interrupted by a restart"); err != nil {
+		logger.Error("Failed to check for interrupted content verifications: %v", err)
+	}
+}
+
+func (jm *JobManager) finishContentVerification(v *database.ContentVerification, partitions []database.PartitionVerification, err error) {
+	completed := time.Now().UTC()
+	v.CompletedAt = &completed
+	if err != nil {
+		message := err.Error()
+		v.Status = database.VerificationFailed
+		v.LastError = &message
+	} else {
+		v.Status = database.VerificationCompleted
+		v.Partitions = partitions
+		v.Consistent = true
+		for _, p := range partitions {
+			v.RecordsCompared += p.RecordsCompared
+			v.MismatchedRecords += p.Mismatched
+			v.MissingRecords += p.Missing
+			v.DuplicateRecords += p.Duplicates
+			if p.Error != "" {
+				v.Consistent = false
+			}
+		}
+		if v.MismatchedRecords > 0 || v.MissingRecords > 0 || v.DuplicateRecords > 0 {
+			v.Consistent = false
+		}
+	}
+	if err := database.UpdateContentVerification(jm.Db, v); err != nil {
+		jobLog(v.JobID).Error("Failed to save content verification #%d of job %s: %v", v.ID, v.JobID, err)
+	}
+
+	var details string
+	switch {
+	case v.Status == database.VerificationFailed:
+		details = fmt.Sprintf("Job %s: content verification #%d failed: %s", v.JobID, v.ID, *v.LastError)
+	case v.Consistent:
+		details = fmt.Sprintf("Job %s: content verification #%d compared %d records, source and target match",
+			v.JobID, v.ID, v.RecordsCompared)
+	default:
+		details = fmt.Sprintf("Job %s: content verification #%d compared %d records: %d mismatched, %d missing, %d duplicates",
+			v.JobID, v.ID, v.RecordsCompared, v.MismatchedRecords, v.MissingRecords, v.DuplicateRecords)
+	}
+	event := &database.OperationalEvent{
+		EventType: "content_verification",
+		Initiator: v.Initiator,
+		Details:   details,
+	}
+	if err := database.CreateOperationalEvent(jm.Db, event); err != nil {
+		jobLog(v.JobID).Error("Failed to record content verification event for job %s: %v", v.JobID, err)
+	}
+}
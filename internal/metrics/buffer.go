@@ -0,0 +1,421 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/pkg/logger"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BatchSink is implemented by sinks that can deliver several metrics in one request.
+type BatchSink interface {
+	SendBatch(metrics []database.ReplicationMetric) error
+}
+
+// SinkHealth reports the delivery state of one sink.
+type SinkHealth struct {
+	Name                string     `json:"name"`
+	Healthy             bool       `json:"healthy"`
+	Queued              int        `json:"queued"`
+	QueueCapacity       int        `json:"queue_capacity"`
+	Sent                uint64     `json:"sent"`
+	FailedAttempts      uint64     `json:"failed_attempts"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	Dropped             uint64     `json:"dropped"`
+	Spilled             uint64     `json:"spilled"`
+	SpillBytes          int64      `json:"spill_bytes"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastErrorAt         *time.Time `json:"last_error_at,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+}
+
+// BufferedSink decouples a sink from metric processing. Send only enqueues; a
+// worker sends batches and retries them with exponential backoff. Batches that
+// still fail, and metrics arriving while the queue is full, are appended to a
+// spill file that is replayed after the next successful send. Without a spill
+// directory those metrics are dropped and counted.
+type BufferedSink struct {
+	name  string
+	sink  Sink
+	queue chan database.ReplicationMetric
+
+	batchSize      int
+	flushInterval  time.Duration
+	maxRetries     int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	spill          *spillFile
+
+	sent           atomic.Uint64
+	failedAttempts atomic.Uint64
+	dropped        atomic.Uint64
+	spilled        atomic.Uint64
+
+	mu                  sync.Mutex
+	consecutiveFailures int
+	lastError           string
+	lastErrorAt         time.Time
+	lastSuccessAt       time.Time
+
+	closeOnce sync.Once
+	done      chan struct{}
+	stopped   chan struct{}
+}
+
+// NewBufferedSink wraps sink with a queue and starts its delivery worker.
+func NewBufferedSink(name string, sink Sink, cfg config.SinkBufferConfig) (*BufferedSink, error) {
+	b := &BufferedSink{
+		name:           name,
+		sink:           sink,
+		queue:          make(chan database.ReplicationMetric, positiveOr(cfg.QueueSize, 1000)),
+		batchSize:      positiveOr(cfg.BatchSize, 50),
+		maxRetries:     positiveOr(cfg.MaxRetries, 5),
+		flushInterval:  time.Second,
+		initialBackoff: 500 * time.Millisecond,
+		maxBackoff:     30 * time.Second,
+		done:           make(chan struct{}),
+		stopped:        make(chan struct{}),
+	}
+	for _, d := range []struct {
+		value  string
+		target *time.Duration
+	}{
+		{cfg.FlushInterval, &b.flushInterval},
+		{cfg.InitialBackoff, &b.initialBackoff},
+		{cfg.MaxBackoff, &b.maxBackoff},
+	} {
+		if d.value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(d.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid buffer duration %q: %w", d.value, err)
+		}
+		*d.target = parsed
+	}
+	if cfg.SpillDir != "" {
+		spill, err := openSpillFile(filepath.Join(cfg.SpillDir, name+".jsonl"), cfg.SpillMaxBytes)
+		if err != nil {
+			return nil, err
+		}
+		b.spill = spill
+	}
+
+	go b.run()
+	return b, nil
+}
+
+func positiveOr(value, fallback int) int {
+	if value > 0 {
+		return value
+	}
+	return fallback
+}
+
+// Name returns the platform name of the wrapped sink.
+func (b *BufferedSink) Name() string {
+	return b.name
+}
+
+// Send enqueues a metric without blocking.
+func (b *BufferedSink) Send(metric database.ReplicationMetric) error {
+	select {
+	case <-b.done:
+		return fmt.Errorf("metrics sink %s is closed", b.name)
+	default:
+	}
+	select {
+	case b.queue <- metric:
+	default:
+		b.overflow([]database.ReplicationMetric{metric})
+	}
+	return nil
+}
+
+// Health returns the current delivery state.
+func (b *BufferedSink) Health() SinkHealth {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	h := SinkHealth{
+		Name:                b.name,
+		Healthy:             b.consecutiveFailures == 0,
+		Queued:              len(b.queue),
+		QueueCapacity:       cap(b.queue),
+		Sent:                b.sent.Load(),
+		FailedAttempts:      b.failedAttempts.Load(),
+		ConsecutiveFailures: b.consecutiveFailures,
+		Dropped:             b.dropped.Load(),
+		Spilled:             b.spilled.Load(),
+		LastError:           b.lastError,
+	}
+	if b.spill != nil {
+		h.SpillBytes = b.spill.size()
+	}
+	if !b.lastErrorAt.IsZero() {
+		t := b.lastErrorAt
+		h.LastErrorAt = &t
+	}
+	if !b.lastSuccessAt.IsZero() {
+		t := b.lastSuccessAt
+		h.LastSuccessAt = &t
+	}
+	return h
+}
+
+// Close stops accepting metrics, makes one attempt to deliver the queued ones,
+// spills what could not be sent and closes the wrapped sink.
+func (b *BufferedSink) Close() error {
+	b.closeOnce.Do(func() { close(b.done) })
+	<-b.stopped
+	if b.spill != nil {
+		b.spill.close()
+	}
+	if closer, ok := b.sink.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (b *BufferedSink) run() {
+	defer close(b.stopped)
+	ticker := time.NewTicker(b.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]database.ReplicationMetric, 0, b.batchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			b.deliver(batch)
+			batch = make([]database.ReplicationMetric, 0, b.batchSize)
+		}
+	}
+	for {
+		select {
+		case m := <-b.queue:
+			batch = append(batch, m)
+			if len(batch) >= b.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-b.done:
+			for {
+				select {
+				case m := <-b.queue:
+					batch = append(batch, m)
+					if len(batch) >= b.batchSize {
+						b.drain(batch)
+						batch = make([]database.ReplicationMetric, 0, b.batchSize)
+					}
+				default:
+					if len(batch) > 0 {
+						b.drain(batch)
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// deliver sends a batch, retrying with exponential backoff. A batch that still
+// fails is spilled; after a success the spill file is replayed.
+func (b *BufferedSink) deliver(batch []database.ReplicationMetric) {
+	backoff := b.initialBackoff
+	for attempt := 1; ; attempt++ {
+		err := b.send(batch)
+		if err == nil {
+			b.replaySpill()
+			return
+		}
+		if attempt >= b.maxRetries {
+			break
+		}
+		select {
+		case <-time.After(backoff):
+		case <-b.done:
+			b.overflow(batch)
+			return
+		}
+		backoff *= 2
+		if backoff > b.maxBackoff {
+			backoff = b.maxBackoff
+		}
+	}
+	logger.Warn("Metrics sink %s unavailable, spilling %d metrics", b.name, len(batch))
+	b.overflow(batch)
+}
+
+// drain makes a single attempt during shutdown.
+func (b *BufferedSink) drain(batch []database.ReplicationMetric) {
+	if err := b.send(batch); err != nil {
+		b.overflow(batch)
+	}
+}
+
+func (b *BufferedSink) send(batch []database.ReplicationMetric) error {
+	var err error
+	if bs, ok := b.sink.(BatchSink); ok {
+		err = bs.SendBatch(batch)
+	} else {
+		for _, m := range batch {
+			if err = b.sink.Send(m); err != nil {
+				break
+			}
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err != nil {
+		b.failedAttempts.Add(1)
+		b.consecutiveFailures++
+		b.lastError = err.Error()
+		b.lastErrorAt = time.Now()
+		return err
+	}
+	b.sent.Add(uint64(len(batch)))
+	b.consecutiveFailures = 0
+	b.lastSuccessAt = time.Now()
+	return nil
+}
+
+// overflow spills metrics that cannot be delivered now, or drops them.
+func (b *BufferedSink) overflow(metrics []database.ReplicationMetric) {
+	if b.spill != nil {
+		written, err := b.spill.append(metrics)
+		b.spilled.Add(uint64(written))
+		if err != nil {
+			logger.Warn("Failed to spill metrics for sink %s: %v", b.name, err)
+		}
+		metrics = metrics[written:]
+	}
+	if len(metrics) > 0 {
+		b.dropped.Add(uint64(len(metrics)))
+	}
+}
+
+// replaySpill resends spilled metrics in batches until the file is empty or a
+// send fails; unsent metrics stay spilled.
+func (b *BufferedSink) replaySpill() {
+	if b.spill == nil || b.spill.size() == 0 {
+		return
+	}
+	pending, err := b.spill.takeAll()
+	if err != nil {
+		logger.Warn("Failed to read spilled metrics for sink %s: %v", b.name, err)
+		return
+	}
+	for len(pending) > 0 {
+		n := min(b.batchSize, len(pending))
+		if err := b.send(pending[:n]); err != nil {
+			b.overflow(pending)
+			return
+		}
+		pending = pending[n:]
+	}
+}
+
+// spillFile is an append-only JSON lines file of metrics awaiting delivery.
+type spillFile struct {
+	mu       sync.Mutex
+	path     string
+	maxBytes int64
+	file     *os.File
+	bytes    int64
+}
+
+func openSpillFile(path string, maxBytes int64) (*spillFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create spill directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open spill file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &spillFile{path: path, maxBytes: maxBytes, file: file, bytes: info.Size()}, nil
+}
+
+func (s *spillFile) size() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.bytes
+}
+
+// append writes metrics until maxBytes is reached and returns how many were written.
+func (s *spillFile) append(metrics []database.ReplicationMetric) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, m := range metrics {
+		line, err := json.Marshal(m)
+		if err != nil {
+			return i, err
+		}
+		line = append(line, '\n')
+		if s.maxBytes > 0 && s.bytes+int64(len(line)) > s.maxBytes {
+			return i, nil
+		}
+		n, err := s.file.Write(line)
+		s.bytes += int64(n)
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(metrics), nil
+}
+
+// takeAll reads and truncates the spill file.
+func (s *spillFile) takeAll() ([]database.ReplicationMetric, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	var metrics []database.ReplicationMetric
+	scanner := bufio.NewScanner(s.file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16<<20)
+	for scanner.Scan() {
+		var m database.ReplicationMetric
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+			continue
+		}
+		metrics = append(metrics, m)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := s.file.Truncate(0); err != nil {
+		return nil, err
+	}
+	s.bytes = 0
+	return metrics, nil
+}
+
+func (s *spillFile) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.file.Close()
+}
@@ -30,40 +30,24 @@ type LokiSink struct {
 // NewLokiSink creates a new Loki sink.
 func NewLokiSink(cfg config.LokiConfig) (*LokiSink, error) {
 	return &LokiSink{
-		client: &http.Client{},
+		client: &http.Client{Timeout: sinkRequestTimeout},
 		cfg:    cfg,
 	}, nil
 }
 
 // Send sends a metric to Loki.
 func (s *LokiSink) Send(metric database.ReplicationMetric) error {
-	logEntry := map[string]interface{}{
-		"streams": []map[string]interface{}{
-			{
-				"stream": map[string]string{
-					"job_id": metric.JobID,
-				},
-				"values": [][]string{
-					{
-						fmt.Sprintf("%d", time.Now().UnixNano()),
-						fmt.Sprintf("messages_replicated=%d bytes_transferred=%d messages_consumed=%d bytes_consumed=%d current_lag=%d error_count=%d source_stalled=%t target_stalled=%t critical_lag=%t high_error_rate=%t error_spike=%t",
-							metric.MessagesReplicated,
-							metric.BytesTransferred,
-							metric.MessagesConsumed,
-							metric.BytesConsumed,
-							metric.CurrentLag,
-							metric.ErrorCount,
-							metric.SourceStalled,
-							metric.TargetStalled,
-							metric.CriticalLag,
-							metric.HighErrorRate,
-							metric.ErrorSpike,
-						),
-					},
-				},
-			},
-		},
+	return s.SendBatch([]database.ReplicationMetric{metric})
+}
+
+// SendBatch pushes several metrics in one request. Entries carry the metric's
+// own timestamp so replayed metrics land at the time they were recorded.
+func (s *LokiSink) SendBatch(metrics []database.ReplicationMetric) error {
+	var streams []map[string]interface{}
+	for _, metric := range metrics {
+		streams = append(streams, lokiStreams(metric)...)
 	}
+	logEntry := map[string]interface{}{"streams": streams}
 
 	body, err := json.Marshal(logEntry)
 	if err != nil {
@@ -89,3 +73,58 @@ func (s *LokiSink) Send(metric database.ReplicationMetric) error {
 
 	return nil
 }
+
+func lokiStreams(metric database.ReplicationMetric) []map[string]interface{} {
+	ts := metric.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	stamp := fmt.Sprintf("%d", ts.UnixNano())
+	streams := []map[string]interface{}{
+		{
+			"stream": map[string]string{
+				"job_id": metric.JobID,
+			},
+			"values": [][]string{
+				{
+					stamp,
+					fmt.Sprintf("messages_replicated=%d bytes_transferred=%d messages_consumed=%d bytes_consumed=%d current_lag=%d error_count=%d duplicates_skipped=%d dead_lettered=%d source_stalled=%t target_stalled=%t critical_lag=%t high_error_rate=%t error_spike=%t lag_seconds_p50=%.3f lag_seconds_p99=%.3f lag_seconds_max=%.3f",
+						metric.MessagesReplicated,
+						metric.BytesTransferred,
+						metric.MessagesConsumed,
+						metric.BytesConsumed,
+						metric.CurrentLag,
+						metric.ErrorCount,
+						metric.DuplicatesSkipped,
+						metric.DeadLettered,
+						metric.SourceStalled,
+						metric.TargetStalled,
+						metric.CriticalLag,
+						metric.HighErrorRate,
+						metric.ErrorSpike,
+						metric.LagSecondsP50,
+						metric.LagSecondsP99,
+						metric.LagSecondsMax,
+					),
+				},
+			},
+		},
+	}
+	for _, p := range metric.PartitionLags {
+		streams = append(streams, map[string]interface{}{
+			"stream": map[string]string{
+				"job_id":    metric.JobID,
+				"topic":     p.Topic,
+				"partition": fmt.Sprintf("%d", p.Partition),
+			},
+			"values": [][]string{
+				{
+					stamp,
+					fmt.Sprintf("message_lag=%d lag_seconds=%.3f lag_seconds_p50=%.3f lag_seconds_p99=%.3f samples=%d",
+						p.MessageLag, p.LagSeconds, p.LagSecondsP50, p.LagSecondsP99, p.Samples),
+				},
+			},
+		})
+	}
+	return streams
+}
@@ -13,29 +13,101 @@
 package metrics
 
 import (
+	"errors"
+	"fmt"
 	"kaf-mirror/internal/config"
 	"kaf-mirror/internal/database"
+	"time"
 )
 
+// sinkRequestTimeout bounds a single HTTP request to a monitoring platform.
+const sinkRequestTimeout = 10 * time.Second
+
 // Sink is an interface for sending metrics to a monitoring platform.
 type Sink interface {
 	Send(metric database.ReplicationMetric) error
 }
 
-// NewSink creates a new metrics sink based on the provided configuration.
+// NewSink creates the metrics sinks configured in monitoring.sinks (or
+// monitoring.platform) and fans metrics out to all of them. Every sink gets its
+// own buffer, so a slow or unavailable platform does not hold up the others.
 func NewSink(cfg config.MonitoringConfig) (Sink, error) {
 	if !cfg.Enabled {
 		return nil, nil
 	}
 
-	switch cfg.Platform {
+	var sinks []*BufferedSink
+	for _, name := range cfg.SinkNames() {
+		sink, err := NewPlatformSink(name, cfg)
+		if err != nil {
+			closeAll(sinks)
+			return nil, fmt.Errorf("failed to create %s sink: %w", name, err)
+		}
+		buffered, err := NewBufferedSink(name, sink, cfg.Buffer)
+		if err != nil {
+			closeAll(sinks)
+			return nil, fmt.Errorf("failed to create %s sink: %w", name, err)
+		}
+		sinks = append(sinks, buffered)
+	}
+	if len(sinks) == 0 {
+		return nil, nil
+	}
+	return &FanOut{sinks: sinks}, nil
+}
+
+// NewPlatformSink creates the unbuffered sink for a single platform.
+func NewPlatformSink(name string, cfg config.MonitoringConfig) (Sink, error) {
+	switch name {
 	case "splunk":
 		return NewSplunkSink(cfg.Splunk)
 	case "loki":
 		return NewLokiSink(cfg.Loki)
 	case "prometheus":
 		return NewPrometheusSink(cfg.Prometheus)
+	case "otlp":
+		return NewOTLPSink(cfg.OTLP)
 	default:
-		return nil, nil
+		return nil, fmt.Errorf("unsupported monitoring platform %q", name)
+	}
+}
+
+// FanOut sends every metric to several buffered sinks.
+type FanOut struct {
+	sinks []*BufferedSink
+}
+
+// Send enqueues the metric on every sink.
+func (f *FanOut) Send(metric database.ReplicationMetric) error {
+	var errs []error
+	for _, s := range f.sinks {
+		if err := s.Send(metric); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Health returns the delivery state of every sink.
+func (f *FanOut) Health() []SinkHealth {
+	health := make([]SinkHealth, 0, len(f.sinks))
+	for _, s := range f.sinks {
+		health = append(health, s.Health())
+	}
+	return health
+}
+
+// Close flushes and closes every sink.
+func (f *FanOut) Close() error {
+	return closeAll(f.sinks)
+}
+
+func closeAll(sinks []*BufferedSink) error {
+	var errs []error
+	for _, s := range sinks {
+		if err := s.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", s.Name(), err))
+		}
 	}
+	return errors.Join(errs...)
 }
@@ -0,0 +1,190 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/telemetry"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// OTLPSink exports metrics to an OpenTelemetry collector over OTLP gRPC or HTTP.
+// Send keeps the latest metric of each job; the gauges report them whenever the
+// periodic reader collects, so every job is exported once per export interval.
+type OTLPSink struct {
+	provider *sdkmetric.MeterProvider
+
+	mu     sync.Mutex
+	latest map[string]database.ReplicationMetric
+}
+
+// NewOTLPSink creates a new OTLP sink.
+func NewOTLPSink(cfg config.OTLPConfig) (*OTLPSink, error) {
+	interval := 10 * time.Second
+	if cfg.ExportInterval != "" {
+		d, err := time.ParseDuration(cfg.ExportInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OTLP export interval: %w", err)
+		}
+		interval = d
+	}
+
+	exporter, err := newOTLPMetricExporter(context.Background(), cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP metric exporter: %w", err)
+	}
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(interval))),
+		sdkmetric.WithResource(telemetry.Resource(cfg, "")),
+	)
+
+	s := &OTLPSink{provider: provider, latest: make(map[string]database.ReplicationMetric)}
+	if err := s.registerInstruments(provider.Meter("kaf-mirror")); err != nil {
+		provider.Shutdown(context.Background())
+		return nil, err
+	}
+	return s, nil
+}
+
+func newOTLPMetricExporter(ctx context.Context, cfg config.OTLPConfig) (sdkmetric.Exporter, error) {
+	switch cfg.Protocol {
+	case "http":
+		opts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlpmetrichttp.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlpmetrichttp.WithHeaders(cfg.Headers))
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	case "", "grpc":
+		opts := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(cfg.Endpoint)}
+		if cfg.Insecure {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+		if len(cfg.Headers) > 0 {
+			opts = append(opts, otlpmetricgrpc.WithHeaders(cfg.Headers))
+		}
+		return otlpmetricgrpc.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q", cfg.Protocol)
+	}
+}
+
+// otlpGauge maps a job-level gauge to its value in a metric.
+type otlpGauge struct {
+	name  string
+	desc  string
+	unit  string
+	value func(m database.ReplicationMetric) float64
+}
+
+var otlpJobGauges = []otlpGauge{
+	{"kaf_mirror_messages_replicated", "Number of messages replicated.", "{message}", func(m database.ReplicationMetric) float64 { return float64(m.MessagesReplicated) }},
+	{"kaf_mirror_bytes_transferred", "Number of bytes transferred.", "By", func(m database.ReplicationMetric) float64 { return float64(m.BytesTransferred) }},
+	{"kaf_mirror_messages_consumed", "Number of messages consumed.", "{message}", func(m database.ReplicationMetric) float64 { return float64(m.MessagesConsumed) }},
+	{"kaf_mirror_bytes_consumed", "Number of bytes consumed.", "By", func(m database.ReplicationMetric) float64 { return float64(m.BytesConsumed) }},
+	{"kaf_mirror_current_lag", "Current consumer lag.", "{message}", func(m database.ReplicationMetric) float64 { return float64(m.CurrentLag) }},
+	{"kaf_mirror_error_count", "Number of errors.", "{error}", func(m database.ReplicationMetric) float64 { return float64(m.ErrorCount) }},
+	{"kaf_mirror_duplicates_skipped", "Number of source records skipped because the target already held them.", "{message}", func(m database.ReplicationMetric) float64 { return float64(m.DuplicatesSkipped) }},
+	{"kaf_mirror_dead_lettered", "Number of records written to the dead-letter topic.", "{message}", func(m database.ReplicationMetric) float64 { return float64(m.DeadLettered) }},
+	{"kaf_mirror_incident_source_stalled", "Source consumption stalled (1=true).", "", func(m database.ReplicationMetric) float64 { return boolToFloat(m.SourceStalled) }},
+	{"kaf_mirror_incident_target_stalled", "Target production stalled (1=true).", "", func(m database.ReplicationMetric) float64 { return boolToFloat(m.TargetStalled) }},
+	{"kaf_mirror_incident_critical_lag", "Critical lag detected (1=true).", "", func(m database.ReplicationMetric) float64 { return boolToFloat(m.CriticalLag) }},
+	{"kaf_mirror_incident_high_error_rate", "High error rate detected (1=true).", "", func(m database.ReplicationMetric) float64 { return boolToFloat(m.HighErrorRate) }},
+	{"kaf_mirror_incident_error_spike", "Error spike detected (1=true).", "", func(m database.ReplicationMetric) float64 { return boolToFloat(m.ErrorSpike) }},
+	{"kaf_mirror_lag_seconds_p50", "Median end-to-end replication latency in seconds behind source.", "s", func(m database.ReplicationMetric) float64 { return m.LagSecondsP50 }},
+	{"kaf_mirror_lag_seconds_p99", "99th percentile end-to-end replication latency in seconds behind source.", "s", func(m database.ReplicationMetric) float64 { return m.LagSecondsP99 }},
+	{"kaf_mirror_lag_seconds_max", "Largest current time lag of any partition in seconds behind source.", "s", func(m database.ReplicationMetric) float64 { return m.LagSecondsMax }},
+}
+
+func (s *OTLPSink) registerInstruments(meter metric.Meter) error {
+	var observables []metric.Observable
+	jobGauges := make([]metric.Float64ObservableGauge, len(otlpJobGauges))
+	for i, g := range otlpJobGauges {
+		gauge, err := meter.Float64ObservableGauge(g.name, metric.WithDescription(g.desc), metric.WithUnit(g.unit))
+		if err != nil {
+			return err
+		}
+		jobGauges[i] = gauge
+		observables = append(observables, gauge)
+	}
+	partitionLag, err := meter.Float64ObservableGauge("kaf_mirror_partition_lag_seconds",
+		metric.WithDescription("Current time lag per source partition in seconds behind source."), metric.WithUnit("s"))
+	if err != nil {
+		return err
+	}
+	partitionMessageLag, err := meter.Int64ObservableGauge("kaf_mirror_partition_message_lag",
+		metric.WithDescription("Consumer lag per source partition."), metric.WithUnit("{message}"))
+	if err != nil {
+		return err
+	}
+	errorsByClass, err := meter.Int64ObservableGauge("kaf_mirror_errors_by_class",
+		metric.WithDescription("Number of produce errors per error class."), metric.WithUnit("{error}"))
+	if err != nil {
+		return err
+	}
+	observables = append(observables, partitionLag, partitionMessageLag, errorsByClass)
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for jobID, m := range s.latest {
+			job := metric.WithAttributes(attribute.String("job_id", jobID))
+			for i, g := range otlpJobGauges {
+				o.ObserveFloat64(jobGauges[i], g.value(m), job)
+			}
+			for _, p := range m.PartitionLags {
+				attrs := metric.WithAttributes(
+					attribute.String("job_id", jobID),
+					attribute.String("topic", p.Topic),
+					attribute.Int("partition", int(p.Partition)),
+				)
+				o.ObserveFloat64(partitionLag, p.LagSeconds, attrs)
+				o.ObserveInt64(partitionMessageLag, p.MessageLag, attrs)
+			}
+			for class, count := range m.ErrorClasses {
+				o.ObserveInt64(errorsByClass, int64(count), metric.WithAttributes(
+					attribute.String("job_id", jobID),
+					attribute.String("class", class),
+				))
+			}
+		}
+		return nil
+	}, observables...)
+	return err
+}
+
+// Send records the metric for the next export.
+func (s *OTLPSink) Send(metric database.ReplicationMetric) error {
+	s.mu.Lock()
+	s.latest[metric.JobID] = metric
+	s.mu.Unlock()
+	return nil
+}
+
+// Close exports pending metrics and shuts the exporter down.
+func (s *OTLPSink) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return s.provider.Shutdown(ctx)
+}
@@ -14,6 +14,7 @@ package metrics
 import (
 	"kaf-mirror/internal/config"
 	"kaf-mirror/internal/database"
+	"strconv"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/push"
@@ -29,11 +30,23 @@ type PrometheusSink struct {
 	bytesConsumed      prometheus.Gauge
 	currentLag         prometheus.Gauge
 	errorCount         prometheus.Gauge
+	duplicatesSkipped  prometheus.Gauge
+	deadLettered       prometheus.Gauge
 	sourceStalled      prometheus.Gauge
 	targetStalled      prometheus.Gauge
 	criticalLag        prometheus.Gauge
 	highErrorRate      prometheus.Gauge
 	errorSpike         prometheus.Gauge
+	lagSecondsP50      prometheus.Gauge
+	lagSecondsP99      prometheus.Gauge
+	lagSecondsMax      prometheus.Gauge
+
+	topicLagSecondsP50     *prometheus.GaugeVec
+	topicLagSecondsP99     *prometheus.GaugeVec
+	partitionLagSeconds    *prometheus.GaugeVec
+	partitionLagSecondsP50 *prometheus.GaugeVec
+	partitionLagSecondsP99 *prometheus.GaugeVec
+	errorsByClass          *prometheus.GaugeVec
 }
 
 // NewPrometheusSink creates a new Prometheus sink.
@@ -62,6 +75,14 @@ func NewPrometheusSink(cfg config.PrometheusConfig) (*PrometheusSink, error) {
 		Name: "kaf_mirror_error_count",
 		Help: "Number of errors.",
 	})
+	duplicatesSkipped := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "kaf_mirror_duplicates_skipped",
+		Help: "Number of source records skipped because the target already held them.",
+	})
+	deadLettered := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "kaf_mirror_dead_lettered",
+		Help: "Number of records written to the dead-letter topic.",
+	})
 	sourceStalled := prometheus.NewGauge(prometheus.GaugeOpts{
 		Name: "kaf_mirror_incident_source_stalled",
 		Help: "Source consumption stalled (1=true).",
@@ -83,6 +104,43 @@ func NewPrometheusSink(cfg config.PrometheusConfig) (*PrometheusSink, error) {
 		Help: "Error spike detected (1=true).",
 	})
 
+	lagSecondsP50 := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "kaf_mirror_lag_seconds_p50",
+		Help: "Median end-to-end replication latency in seconds behind source.",
+	})
+	lagSecondsP99 := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "kaf_mirror_lag_seconds_p99",
+		Help: "99th percentile end-to-end replication latency in seconds behind source.",
+	})
+	lagSecondsMax := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "kaf_mirror_lag_seconds_max",
+		Help: "Largest current time lag of any partition in seconds behind source.",
+	})
+	topicLagSecondsP50 := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kaf_mirror_topic_lag_seconds_p50",
+		Help: "Median replication latency per source topic in seconds.",
+	}, []string{"job_id", "topic"})
+	topicLagSecondsP99 := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kaf_mirror_topic_lag_seconds_p99",
+		Help: "99th percentile replication latency per source topic in seconds.",
+	}, []string{"job_id", "topic"})
+	partitionLagSeconds := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kaf_mirror_partition_lag_seconds",
+		Help: "Current time lag per source partition in seconds behind source.",
+	}, []string{"job_id", "topic", "partition"})
+	partitionLagSecondsP50 := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kaf_mirror_partition_lag_seconds_p50",
+		Help: "Median replication latency per source partition in seconds.",
+	}, []string{"job_id", "topic", "partition"})
+	partitionLagSecondsP99 := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kaf_mirror_partition_lag_seconds_p99",
+		Help: "99th percentile replication latency per source partition in seconds.",
+	}, []string{"job_id", "topic", "partition"})
+	errorsByClass := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kaf_mirror_errors_by_class",
+		Help: "Number of produce errors per error class.",
+	}, []string{"job_id", "class"})
+
 	registry := prometheus.NewRegistry()
 	registry.MustRegister(
 		messagesReplicated,
@@ -91,11 +149,22 @@ func NewPrometheusSink(cfg config.PrometheusConfig) (*PrometheusSink, error) {
 		bytesConsumed,
 		currentLag,
 		errorCount,
+		duplicatesSkipped,
+		deadLettered,
 		sourceStalled,
 		targetStalled,
 		criticalLag,
 		highErrorRate,
 		errorSpike,
+		lagSecondsP50,
+		lagSecondsP99,
+		lagSecondsMax,
+		topicLagSecondsP50,
+		topicLagSecondsP99,
+		partitionLagSeconds,
+		partitionLagSecondsP50,
+		partitionLagSecondsP99,
+		errorsByClass,
 	)
 
 	pusher := push.New(cfg.PushGateway, "kaf-mirror").Gatherer(registry)
@@ -108,11 +177,23 @@ func NewPrometheusSink(cfg config.PrometheusConfig) (*PrometheusSink, error) {
 		bytesConsumed:      bytesConsumed,
 		currentLag:         currentLag,
 		errorCount:         errorCount,
+		duplicatesSkipped:  duplicatesSkipped,
+		deadLettered:       deadLettered,
 		sourceStalled:      sourceStalled,
 		targetStalled:      targetStalled,
 		criticalLag:        criticalLag,
 		highErrorRate:      highErrorRate,
 		errorSpike:         errorSpike,
+		lagSecondsP50:      lagSecondsP50,
+		lagSecondsP99:      lagSecondsP99,
+		lagSecondsMax:      lagSecondsMax,
+
+		topicLagSecondsP50:     topicLagSecondsP50,
+		topicLagSecondsP99:     topicLagSecondsP99,
+		partitionLagSeconds:    partitionLagSeconds,
+		partitionLagSecondsP50: partitionLagSecondsP50,
+		partitionLagSecondsP99: partitionLagSecondsP99,
+		errorsByClass:          errorsByClass,
 	}, nil
 }
 
@@ -124,11 +205,35 @@ func (s *PrometheusSink) Send(metric database.ReplicationMetric) error {
 	s.bytesConsumed.Set(float64(metric.BytesConsumed))
 	s.currentLag.Set(float64(metric.CurrentLag))
 	s.errorCount.Set(float64(metric.ErrorCount))
+	s.duplicatesSkipped.Set(float64(metric.DuplicatesSkipped))
+	s.deadLettered.Set(float64(metric.DeadLettered))
 	s.sourceStalled.Set(boolToFloat(metric.SourceStalled))
 	s.targetStalled.Set(boolToFloat(metric.TargetStalled))
 	s.criticalLag.Set(boolToFloat(metric.CriticalLag))
 	s.highErrorRate.Set(boolToFloat(metric.HighErrorRate))
 	s.errorSpike.Set(boolToFloat(metric.ErrorSpike))
+	s.lagSecondsP50.Set(metric.LagSecondsP50)
+	s.lagSecondsP99.Set(metric.LagSecondsP99)
+	s.lagSecondsMax.Set(metric.LagSecondsMax)
+
+	// Drop series of partitions the job no longer reports before refreshing.
+	job := prometheus.Labels{"job_id": metric.JobID}
+	for _, vec := range []*prometheus.GaugeVec{s.topicLagSecondsP50, s.topicLagSecondsP99, s.partitionLagSeconds, s.partitionLagSecondsP50, s.partitionLagSecondsP99} {
+		vec.DeletePartialMatch(job)
+	}
+	for _, t := range metric.TopicLags {
+		s.topicLagSecondsP50.WithLabelValues(metric.JobID, t.Topic).Set(t.LagSecondsP50)
+		s.topicLagSecondsP99.WithLabelValues(metric.JobID, t.Topic).Set(t.LagSecondsP99)
+	}
+	for _, p := range metric.PartitionLags {
+		partition := strconv.Itoa(int(p.Partition))
+		s.partitionLagSeconds.WithLabelValues(metric.JobID, p.Topic, partition).Set(p.LagSeconds)
+		s.partitionLagSecondsP50.WithLabelValues(metric.JobID, p.Topic, partition).Set(p.LagSecondsP50)
+		s.partitionLagSecondsP99.WithLabelValues(metric.JobID, p.Topic, partition).Set(p.LagSecondsP99)
+	}
+	for class, count := range metric.ErrorClasses {
+		s.errorsByClass.WithLabelValues(metric.JobID, class).Set(float64(count))
+	}
 
 	return s.pusher.Push()
 }
@@ -30,26 +30,36 @@ type SplunkSink struct {
 // NewSplunkSink creates a new Splunk sink.
 func NewSplunkSink(cfg config.SplunkConfig) (*SplunkSink, error) {
 	return &SplunkSink{
-		client: &http.Client{},
+		client: &http.Client{Timeout: sinkRequestTimeout},
 		cfg:    cfg,
 	}, nil
 }
 
 // Send sends a metric to Splunk.
 func (s *SplunkSink) Send(metric database.ReplicationMetric) error {
-	payload := map[string]interface{}{
-		"event":       metric,
-		"source":      "kaf-mirror",
-		"sourcetype":  "_json",
-		"index":       s.cfg.Index,
-	}
+	return s.SendBatch([]database.ReplicationMetric{metric})
+}
 
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return err
+// SendBatch sends several metrics in one HEC request; HEC accepts concatenated events.
+func (s *SplunkSink) SendBatch(metrics []database.ReplicationMetric) error {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, metric := range metrics {
+		payload := map[string]interface{}{
+			"event":      metric,
+			"source":     "kaf-mirror",
+			"sourcetype": "_json",
+			"index":      s.cfg.Index,
+		}
+		if !metric.Timestamp.IsZero() {
+			payload["time"] = float64(metric.Timestamp.UnixMilli()) / 1000
+		}
+		if err := enc.Encode(payload); err != nil {
+			return err
+		}
 	}
 
-	req, err := http.NewRequest("POST", s.cfg.HECEndpoint, bytes.NewBuffer(body))
+	req, err := http.NewRequest("POST", s.cfg.HECEndpoint, &body)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,465 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"kaf-mirror/internal/alerting"
+	"kaf-mirror/internal/database"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type alertRuleRequest struct {
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	JobID     string   `json:"job_id"`
+	Threshold *float64 `json:"threshold"`
+	For       string   `json:"for"` // Go duration the condition must hold before firing
+	Severity  string   `json:"severity"`
+	Channels  []string `json:"channels"` // empty notifies every configured channel
+	Enabled   *bool    `json:"enabled"`
+}
+
+type alertSilenceRequest struct {
+	JobID    string `json:"job_id"`
+	RuleID   *int   `json:"rule_id"`
+	Reason   string `json:"reason"`
+	Duration string `json:"duration"` // Go duration, e.g. "2h"
+}
+
+type alertChannelResponse struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// alertRuleResponse is the API representation of an alert rule.
+type alertRuleResponse struct {
+	database.AlertRule
+	Channels []string `json:"channels"`
+}
+
+func newAlertRuleResponse(rule *database.AlertRule) alertRuleResponse {
+	channels := rule.ChannelList()
+	if channels == nil {
+		channels = []string{}
+	}
+	return alertRuleResponse{AlertRule: *rule, Channels: channels}
+}
+
+func (s *Server) alertEngine() *alerting.Engine {
+	if s.manager == nil {
+		return nil
+	}
+	return s.manager.Alerts
+}
+
+func alertIDParam(c *fiber.Ctx) (int, error) {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "Invalid ID")
+	}
+	return id, nil
+}
+
+// handleListAlerts godoc
+// @Summary List alerts
+// @Description List fired alerts, newest first
+// @Tags alerts
+// @Produce json
+// @Param status query string false "firing, resolved or all (default all)"
+// @Param job_id query string false "Job ID"
+// @Param limit query int false "Maximum number of alerts (default 100)"
+// @Success 200 {array} database.Alert
+// @Router /alerts [get]
+// @Security ApiKeyAuth
+func (s *Server) handleListAlerts(c *fiber.Ctx) error {
+	status := c.Query("status")
+	switch status {
+	case "all":
+		status = ""
+	case "", database.AlertStatusFiring, database.AlertStatusResolved:
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, "status must be firing, resolved or all")
+	}
+	limit := c.QueryInt("limit", 100)
+
+	alerts, err := database.ListAlerts(s.Db, status, c.Query("job_id"), limit)
+	if err != nil {
+		log.Printf("Error listing alerts: %v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list alerts")
+	}
+	return c.JSON(alerts)
+}
+
+// handleGetAlert godoc
+// @Summary Get an alert
+// @Tags alerts
+// @Produce json
+// @Param id path int true "Alert ID"
+// @Success 200 {object} database.Alert
+// @Router /alerts/{id} [get]
+// @Security ApiKeyAuth
+func (s *Server) handleGetAlert(c *fiber.Ctx) error {
+	id, err := alertIDParam(c)
+	if err != nil {
+		return err
+	}
+	alert, err := database.GetAlert(s.Db, id)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Alert not found")
+	}
+	return c.JSON(alert)
+}
+
+// handleAcknowledgeAlert godoc
+// @Summary Acknowledge an alert
+// @Description Acknowledge an alert. Acknowledged alerts are not re-notified and PagerDuty incidents are acknowledged.
+// @Tags alerts
+// @Produce json
+// @Param id path int true "Alert ID"
+// @Success 200 {object} database.Alert
+// @Router /alerts/{id}/ack [post]
+// @Security ApiKeyAuth
+func (s *Server) handleAcknowledgeAlert(c *fiber.Ctx) error {
+	id, err := alertIDParam(c)
+	if err != nil {
+		return err
+	}
+	user := c.Locals("user").(*database.User)
+
+	var alert *database.Alert
+	if engine := s.alertEngine(); engine != nil {
+		alert, err = engine.Acknowledge(id, user.Username)
+	} else {
+		alert, err = database.AcknowledgeAlert(s.Db, id, user.Username)
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return fiber.NewError(fiber.StatusNotFound, "Alert not found")
+	}
+	if errors.Is(err, database.ErrAlertAlreadyAcknowledged) {
+		return fiber.NewError(fiber.StatusConflict, "Alert is already acknowledged")
+	}
+	if err != nil {
+		log.Printf("Error acknowledging alert %d: %v", id, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to acknowledge alert")
+	}
+	return c.JSON(alert)
+}
+
+// handleListAlertRules godoc
+// @Summary List alert rules
+// @Tags alerts
+// @Produce json
+// @Success 200 {array} server.alertRuleResponse
+// @Router /alerts/rules [get]
+// @Security ApiKeyAuth
+func (s *Server) handleListAlertRules(c *fiber.Ctx) error {
+	rules, err := database.ListAlertRules(s.Db)
+	if err != nil {
+		log.Printf("Error listing alert rules: %v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list alert rules")
+	}
+	response := make([]alertRuleResponse, 0, len(rules))
+	for i := range rules {
+		response = append(response, newAlertRuleResponse(&rules[i]))
+	}
+	return c.JSON(response)
+}
+
+// handleCreateAlertRule godoc
+// @Summary Create an alert rule
+// @Description Create a rule of type lag, lag_seconds, error_rate, stalled, job_failed, gap_detected or slo_burn_rate. Leave job_id empty to apply the rule to every job.
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param rule body server.alertRuleRequest true "Alert rule"
+// @Success 201 {object} server.alertRuleResponse
+// @Router /alerts/rules [post]
+// @Security ApiKeyAuth
+func (s *Server) handleCreateAlertRule(c *fiber.Ctx) error {
+	var req alertRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	rule := database.AlertRule{Enabled: true}
+	if err := s.applyAlertRuleRequest(&rule, &req); err != nil {
+		return err
+	}
+	if err := database.CreateAlertRule(s.Db, &rule); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("An alert rule named '%s' already exists", rule.Name))
+		}
+		log.Printf("Error creating alert rule: %v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to create alert rule")
+	}
+	return c.Status(fiber.StatusCreated).JSON(newAlertRuleResponse(&rule))
+}
+
+// handleUpdateAlertRule godoc
+// @Summary Update an alert rule
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Param rule body server.alertRuleRequest true "Alert rule"
+// @Success 200 {object} server.alertRuleResponse
+// @Router /alerts/rules/{id} [put]
+// @Security ApiKeyAuth
+func (s *Server) handleUpdateAlertRule(c *fiber.Ctx) error {
+	id, err := alertIDParam(c)
+	if err != nil {
+		return err
+	}
+	rule, err := database.GetAlertRule(s.Db, id)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Alert rule not found")
+	}
+	var req alertRuleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if err := s.applyAlertRuleRequest(rule, &req); err != nil {
+		return err
+	}
+	if err := database.UpdateAlertRule(s.Db, rule); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("An alert rule named '%s' already exists", rule.Name))
+		}
+		log.Printf("Error updating alert rule %d: %v", id, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to update alert rule")
+	}
+	return c.JSON(newAlertRuleResponse(rule))
+}
+
+// applyAlertRuleRequest merges the request into rule and validates the result.
+// Fields left empty in the request keep their current value.
+func (s *Server) applyAlertRuleRequest(rule *database.AlertRule, req *alertRuleRequest) error {
+	if name := strings.TrimSpace(req.Name); name != "" {
+		rule.Name = name
+	}
+	if req.Type != "" {
+		rule.RuleType = req.Type
+	}
+	if req.JobID != "" || rule.ID == 0 {
+		rule.JobID = strings.TrimSpace(req.JobID)
+	}
+	if req.Threshold != nil {
+		rule.Threshold = *req.Threshold
+	}
+	if req.For != "" {
+		rule.ForDuration = req.For
+	}
+	if req.Severity != "" {
+		rule.Severity = req.Severity
+	} else if rule.Severity == "" {
+		rule.Severity = database.AlertSeverityWarning
+	}
+	if req.Channels != nil {
+		rule.Channels = strings.Join(req.Channels, ",")
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+
+	if err := rule.Validate(); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if rule.JobID != "" {
+		if _, err := database.GetJob(s.Db, rule.JobID); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Job '%s' not found", rule.JobID))
+		}
+	}
+	configured := make(map[string]bool)
+	if s.cfg != nil {
+		for _, channel := range s.cfg.Alerting.Channels {
+			configured[channel.Name] = true
+		}
+	}
+	for _, name := range rule.ChannelList() {
+		if !configured[name] {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Alert channel '%s' is not configured", name))
+		}
+	}
+	return nil
+}
+
+// handleDeleteAlertRule godoc
+// @Summary Delete an alert rule
+// @Description Delete an alert rule. Alerts it fired are resolved on the next evaluation and kept for history.
+// @Tags alerts
+// @Param id path int true "Rule ID"
+// @Success 204
+// @Router /alerts/rules/{id} [delete]
+// @Security ApiKeyAuth
+func (s *Server) handleDeleteAlertRule(c *fiber.Ctx) error {
+	id, err := alertIDParam(c)
+	if err != nil {
+		return err
+	}
+	if err := database.DeleteAlertRule(s.Db, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fiber.NewError(fiber.StatusNotFound, "Alert rule not found")
+		}
+		log.Printf("Error deleting alert rule %d: %v", id, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete alert rule")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// handleListAlertSilences godoc
+// @Summary List alert silences
+// @Tags alerts
+// @Produce json
+// @Param all query bool false "Include expired silences"
+// @Success 200 {array} database.AlertSilence
+// @Router /alerts/silences [get]
+// @Security ApiKeyAuth
+func (s *Server) handleListAlertSilences(c *fiber.Ctx) error {
+	silences, err := database.ListAlertSilences(s.Db, !c.QueryBool("all", false))
+	if err != nil {
+		log.Printf("Error listing alert silences: %v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list alert silences")
+	}
+	return c.JSON(silences)
+}
+
+// handleCreateAlertSilence godoc
+// @Summary Silence alerts
+// @Description Suppress notifications for a job, a rule, or both, for the given duration. Alerts are still recorded while silenced.
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param silence body server.alertSilenceRequest true "Silence"
+// @Success 201 {object} database.AlertSilence
+// @Router /alerts/silences [post]
+// @Security ApiKeyAuth
+func (s *Server) handleCreateAlertSilence(c *fiber.Ctx) error {
+	var req alertSilenceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	duration, err := time.ParseDuration(req.Duration)
+	if err != nil || duration <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "duration must be a positive duration, e.g. 2h")
+	}
+	req.JobID = strings.TrimSpace(req.JobID)
+	if req.JobID == "" && req.RuleID == nil {
+		return fiber.NewError(fiber.StatusBadRequest, "A silence needs a job_id, a rule_id or both")
+	}
+	if req.JobID != "" {
+		if _, err := database.GetJob(s.Db, req.JobID); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Job '%s' not found", req.JobID))
+		}
+	}
+	if req.RuleID != nil {
+		if _, err := database.GetAlertRule(s.Db, *req.RuleID); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Alert rule %d not found", *req.RuleID))
+		}
+	}
+
+	user := c.Locals("user").(*database.User)
+	now := time.Now()
+	silence := database.AlertSilence{
+		JobID:     req.JobID,
+		RuleID:    req.RuleID,
+		Reason:    req.Reason,
+		CreatedBy: user.Username,
+		StartsAt:  now,
+		EndsAt:    now.Add(duration),
+	}
+	if err := database.CreateAlertSilence(s.Db, &silence); err != nil {
+		log.Printf("Error creating alert silence: %v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to create alert silence")
+	}
+	return c.Status(fiber.StatusCreated).JSON(silence)
+}
+
+// handleDeleteAlertSilence godoc
+// @Summary Expire an alert silence
+// @Tags alerts
+// @Param id path int true "Silence ID"
+// @Success 204
+// @Router /alerts/silences/{id} [delete]
+// @Security ApiKeyAuth
+func (s *Server) handleDeleteAlertSilence(c *fiber.Ctx) error {
+	id, err := alertIDParam(c)
+	if err != nil {
+		return err
+	}
+	if err := database.DeleteAlertSilence(s.Db, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fiber.NewError(fiber.StatusNotFound, "Alert silence not found")
+		}
+		log.Printf("Error deleting alert silence %d: %v", id, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete alert silence")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// handleListAlertChannels godoc
+// @Summary List notification channels
+// @Description List the configured notification channels. Credentials are never returned.
+// @Tags alerts
+// @Produce json
+// @Success 200 {array} server.alertChannelResponse
+// @Router /alerts/channels [get]
+// @Security ApiKeyAuth
+func (s *Server) handleListAlertChannels(c *fiber.Ctx) error {
+	response := []alertChannelResponse{}
+	if s.cfg != nil {
+		for _, channel := range s.cfg.Alerting.Channels {
+			response = append(response, alertChannelResponse{Name: channel.Name, Type: channel.Type})
+		}
+	}
+	return c.JSON(response)
+}
+
+// handleTestAlertChannel godoc
+// @Summary Send a test notification
+// @Tags alerts
+// @Produce json
+// @Param name path string true "Channel name"
+// @Success 200 {object} map[string]interface{}
+// @Router /alerts/channels/{name}/test [post]
+// @Security ApiKeyAuth
+func (s *Server) handleTestAlertChannel(c *fiber.Ctx) error {
+	engine := s.alertEngine()
+	if engine == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Alerting is disabled")
+	}
+	name := c.Params("name")
+	found := false
+	for _, channel := range engine.Channels() {
+		if channel.Name() == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Alert channel '%s' is not configured", name))
+	}
+
+	ctx, cancel := context.WithTimeout(c.Context(), 30*time.Second)
+	defer cancel()
+	if err := engine.TestChannel(ctx, name); err != nil {
+		return fiber.NewError(fiber.StatusBadGateway, fmt.Sprintf("Test notification failed: %v", err))
+	}
+	return c.JSON(fiber.Map{"status": "sent", "channel": name})
+}
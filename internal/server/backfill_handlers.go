@@ -0,0 +1,122 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/manager"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// handleCreateBackfill godoc
+// @Summary Create a backfill job for a time or offset range
+// @Description Create a paused backfill job with the clusters, settings and mappings of a job that copies the records between two positions again: earliest, a timestamp or explicit partition offsets to a timestamp, explicit offsets or the latest offsets (the default). The end is exclusive. Backfill jobs read their ranges without a consumer group and stop once every range is copied. Set start to run it right away.
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param start query bool false "Start the backfill job after creating it"
+// @Param request body manager.BackfillRequest true "Backfill range"
+// @Success 201 {object} manager.BackfillStatus
+// @Router /jobs/{id}/backfills [post]
+// @Security ApiKeyAuth
+func (s *Server) handleCreateBackfill(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	if _, err := database.GetJob(s.Db, jobID); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Job not found")
+	}
+	var req manager.BackfillRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if err := req.From.Validate(); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "from: "+err.Error())
+	}
+	if err := req.To.Validate(); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "to: "+err.Error())
+	}
+
+	user := c.Locals("user").(*database.User)
+	status, err := s.manager.CreateBackfillJob(jobID, req, user.Username)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return s.startBackfill(c, status)
+}
+
+// handleCreateBackfillFromGaps godoc
+// @Summary Create a backfill job for mirror gaps
+// @Description Create a paused backfill job that copies the source offsets of unresolved mirror gaps of a job again. Gaps of the same partition are merged into one range. The gaps are marked in progress and resolved when their range is copied. Without gap_ids every unresolved gap is included.
+// @Tags mirror
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param start query bool false "Start the backfill job after creating it"
+// @Param request body manager.BackfillFromGapsRequest false "Gaps to close"
+// @Success 201 {object} manager.BackfillStatus
+// @Router /jobs/{id}/mirror/gaps/backfill [post]
+// @Security ApiKeyAuth
+func (s *Server) handleCreateBackfillFromGaps(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	if _, err := database.GetJob(s.Db, jobID); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Job not found")
+	}
+	var req manager.BackfillFromGapsRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+
+	user := c.Locals("user").(*database.User)
+	status, err := s.manager.CreateBackfillFromGaps(jobID, req, user.Username)
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return s.startBackfill(c, status)
+}
+
+// startBackfill starts a new backfill job when requested and returns its status.
+func (s *Server) startBackfill(c *fiber.Ctx, status *manager.BackfillStatus) error {
+	if c.QueryBool("start", false) {
+		if err := s.manager.StartJob(status.JobID); err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("Backfill job %s was created but failed to start: %v", status.JobID, err))
+		}
+		if started, err := s.manager.BackfillStatus(status.JobID); err == nil {
+			status = started
+		}
+	}
+	return c.Status(fiber.StatusCreated).JSON(status)
+}
+
+// handleGetBackfill godoc
+// @Summary Get the progress of a backfill job
+// @Description Get the status, progress percentage and per-partition ranges of a backfill job.
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Backfill job ID"
+// @Success 200 {object} manager.BackfillStatus
+// @Router /jobs/{id}/backfill [get]
+// @Security ApiKeyAuth
+func (s *Server) handleGetBackfill(c *fiber.Ctx) error {
+	status, err := s.manager.BackfillStatus(c.Params("id"))
+	if errors.Is(err, manager.ErrNotBackfillJob) {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Job not found")
+	}
+	return c.JSON(status)
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// validateErrorPolicy checks the error policy of a job.
+func validateErrorPolicy(policy *database.ErrorPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	return config.ErrorPolicy(*policy).Validate()
+}
+
+// replayDeadLettersRequest is the body of a dead-letter replay request.
+type replayDeadLettersRequest struct {
+	// Limit caps the records re-driven; omitted replays up to 1000.
+	Limit int `json:"limit"`
+}
+
+// handleListDeadLetters godoc
+// @Summary List the dead-letter records of a job
+// @Description List the newest records of the job's dead-letter topic with the error that sent them there, the source record they came from and whether a replay already re-drove them.
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param limit query int false "Maximum number of records" default(100)
+// @Success 200 {object} kafka.DeadLetterList
+// @Failure 404 {object} map[string]interface{}
+// @Router /jobs/{id}/dlq [get]
+// @Security ApiKeyAuth
+func (s *Server) handleListDeadLetters(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	if _, err := database.GetJob(s.Db, jobID); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Job not found")
+	}
+	limit := c.QueryInt("limit", 0)
+	if limit < 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "limit must not be negative")
+	}
+	list, err := s.manager.ListDeadLetters(jobID, limit)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(list)
+}
+
+// handleReplayDeadLetters godoc
+// @Summary Replay the dead-letter records of a job
+// @Description Produce records of the job's dead-letter topic to the target topics that rejected them, without the error metadata. Each replay continues after the last one; a partition stops at its first record that fails again.
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body replayDeadLettersRequest false "Replay limit"
+// @Success 200 {object} kafka.DeadLetterReplay
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /jobs/{id}/dlq/replay [post]
+// @Security ApiKeyAuth
+func (s *Server) handleReplayDeadLetters(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	if _, err := database.GetJob(s.Db, jobID); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Job not found")
+	}
+	var req replayDeadLettersRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+	if req.Limit < 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "limit must not be negative")
+	}
+
+	user := c.Locals("user").(*database.User)
+	result, err := s.manager.ReplayDeadLetters(jobID, req.Limit, user.Username)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(result)
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"errors"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/manager"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// handleStartFailover godoc
+// @Summary Fail a job over to its target cluster
+// @Description Start a planned failover: wait for zero lag, record a migration checkpoint, validate the mirror state, copy consumer group offsets to the target, stop the job and optionally create a paused reverse job for failback. The steps run in the background; poll the returned operation. With dry_run nothing is changed and a report of every step is returned.
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param dry_run query bool false "Only report what the failover would do"
+// @Param options body database.FailoverOptions false "Failover options"
+// @Success 200 {object} database.FailoverOperation "Dry-run report"
+// @Success 202 {object} database.FailoverOperation
+// @Failure 409 {object} map[string]interface{}
+// @Router /jobs/{id}/failover [post]
+// @Security ApiKeyAuth
+func (s *Server) handleStartFailover(c *fiber.Ctx) error {
+	// The failover runs after the request returns, when Fiber reuses the param buffer.
+	jobID := utils.CopyString(c.Params("id"))
+	if _, err := database.GetJob(s.Db, jobID); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Job not found")
+	}
+	var opts database.FailoverOptions
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&opts); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+	if err := manager.ValidateFailoverOptions(opts); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	user := c.Locals("user").(*database.User)
+	if c.QueryBool("dry_run", false) {
+		report, err := s.manager.PlanFailover(jobID, opts, user.Username)
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		return c.JSON(report)
+	}
+
+	op, err := s.manager.StartFailover(jobID, opts, user.Username)
+	if errors.Is(err, manager.ErrFailoverInProgress) {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	return c.Status(fiber.StatusAccepted).JSON(op)
+}
+
+// handleListFailovers godoc
+// @Summary List the failovers of a job
+// @Description List the failover operations of a job with their steps, newest first.
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {array} database.FailoverOperation
+// @Router /jobs/{id}/failovers [get]
+// @Security ApiKeyAuth
+func (s *Server) handleListFailovers(c *fiber.Ctx) error {
+	ops, err := s.manager.ListFailovers(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list failovers")
+	}
+	return c.JSON(ops)
+}
+
+// handleGetFailover godoc
+// @Summary Get a failover of a job
+// @Description Get a failover operation with the status and details of every step.
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param operation_id path int true "Failover operation ID"
+// @Success 200 {object} database.FailoverOperation
+// @Router /jobs/{id}/failovers/{operation_id} [get]
+// @Security ApiKeyAuth
+func (s *Server) handleGetFailover(c *fiber.Ctx) error {
+	operationID, err := c.ParamsInt("operation_id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid failover ID")
+	}
+	op, err := s.manager.GetFailover(c.Params("id"), operationID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Failover not found")
+	}
+	return c.JSON(op)
+}
+
+// handleResumeFailover godoc
+// @Summary Resume a failed failover
+// @Description Rerun a failed failover from the step that failed. Completed steps are not repeated.
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param operation_id path int true "Failover operation ID"
+// @Success 202 {object} database.FailoverOperation
+// @Failure 409 {object} map[string]interface{}
+// @Router /jobs/{id}/failovers/{operation_id}/resume [post]
+// @Security ApiKeyAuth
+func (s *Server) handleResumeFailover(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	operationID, err := c.ParamsInt("operation_id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid failover ID")
+	}
+	if _, err := s.manager.GetFailover(jobID, operationID); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Failover not found")
+	}
+	user := c.Locals("user").(*database.User)
+	op, err := s.manager.ResumeFailover(jobID, operationID, user.Username)
+	if err != nil {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	return c.Status(fiber.StatusAccepted).JSON(op)
+}
@@ -15,15 +15,17 @@ package server
 import (
 	"context"
 	crypto_rand "crypto/rand"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"kaf-mirror/internal/auth"
 	"kaf-mirror/internal/config"
 	"kaf-mirror/internal/database"
 	"kaf-mirror/internal/kafka"
 	"log"
 	"strconv"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/gofiber/contrib/websocket"
@@ -42,11 +44,22 @@ import (
 // @Router /health [get]
 func (s *Server) handleHealthCheck(c *fiber.Ctx) error {
 	uptime := time.Since(s.startTime).Seconds()
-	return c.JSON(fiber.Map{
+	response := fiber.Map{
 		"status":    "ok",
 		"uptime":    int(uptime),
 		"timestamp": time.Now().Unix(),
-	})
+	}
+	if s.manager != nil {
+		if sinks := s.manager.MetricsSinkHealth(); len(sinks) > 0 {
+			response["metrics_sinks"] = sinks
+			for _, sink := range sinks {
+				if !sink.Healthy {
+					response["status"] = "degraded"
+				}
+			}
+		}
+	}
+	return c.JSON(response)
 }
 
 // handleGetVersion godoc
@@ -483,6 +496,15 @@ func (s *Server) handleListJobs(c *fiber.Ctx) error {
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list jobs")
 	}
+	if apiToken, ok := c.Locals("token").(*database.ApiToken); ok && len(apiToken.JobIDList()) > 0 {
+		scoped := make([]database.ReplicationJob, 0, len(jobs))
+		for _, job := range jobs {
+			if apiToken.AllowsJob(job.ID) {
+				scoped = append(scoped, job)
+			}
+		}
+		jobs = scoped
+	}
 	return c.JSON(jobs)
 }
 
@@ -495,11 +517,13 @@ type CreateJobRequest struct {
 	Parallelism        int                      `json:"parallelism"`
 	Compression        string                   `json:"compression"`
 	PreservePartitions bool                     `json:"preserve_partitions"`
+	StartPosition      *database.StartPosition  `json:"start_position,omitempty"`
+	ErrorPolicy        *database.ErrorPolicy    `json:"error_policy,omitempty"`
 }
 
 // handleCreateJob godoc
 // @Summary Create a new replication job
-// @Description Create a new replication job.
+// @Description Create a new replication job. The optional error_policy decides what happens to records the target rejects; while a record is retried, later records of its source partition wait for it, apart from those already sent when it failed.
 // @Tags jobs
 // @Accept json
 // @Produce json
@@ -512,6 +536,12 @@ func (s *Server) handleCreateJob(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
+	if err := validateStartPositions(req.StartPosition, req.TopicMappings); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if err := validateErrorPolicy(req.ErrorPolicy); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
 
 	job := &database.ReplicationJob{
 		ID:                 uuid.NewString(),
@@ -523,6 +553,8 @@ func (s *Server) handleCreateJob(c *fiber.Ctx) error {
 		Parallelism:        req.Parallelism,
 		Compression:        req.Compression,
 		PreservePartitions: req.PreservePartitions,
+		StartPosition:      req.StartPosition,
+		ErrorPolicy:        req.ErrorPolicy,
 	}
 
 	if err := database.CreateJob(s.Db, job); err != nil {
@@ -587,8 +619,16 @@ func (s *Server) handleUpdateJob(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
+	if err := validateStartPositions(req.StartPosition, req.TopicMappings); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if err := validateErrorPolicy(req.ErrorPolicy); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
 
 	job.Name = req.Name
+	job.StartPosition = req.StartPosition
+	job.ErrorPolicy = req.ErrorPolicy
 	// Status is updated via start/stop/pause endpoints, not here.
 
 	if err := database.UpdateJob(s.Db, job); err != nil {
@@ -617,6 +657,9 @@ func (s *Server) handleDeleteJob(c *fiber.Ctx) error {
 	if err := database.DeleteJob(s.Db, c.Params("id")); err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete job")
 	}
+	if s.manager.Logs != nil {
+		s.manager.Logs.Remove(c.Params("id"))
+	}
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
@@ -852,6 +895,9 @@ func (s *Server) handleUpdateMappings(c *fiber.Ctx) error {
 	if err := c.BodyParser(&mappings); err != nil {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
 	}
+	if err := validateStartPositions(nil, mappings); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
 
 	if err := database.UpdateMappingsForJob(s.Db, jobID, mappings); err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "Failed to update mappings")
@@ -894,27 +940,80 @@ func (s *Server) handleGetHistoricalMetrics(c *fiber.Ctx) error {
 	jobID := c.Params("id")
 	timeRange := c.Query("range", "2h") // Default to 2h
 
-	var start time.Time
 	end := time.Now()
+	start := metricsRangeStart(timeRange, end)
 
+	metrics, err := database.GetHistoricalMetrics(s.Db, jobID, start, end)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get historical metrics")
+	}
+	return c.JSON(metrics)
+}
+
+// metricsRangeStart resolves a metrics range query value (2h, 24h, 7d, 30d)
+// to the start of the window ending at end. Unknown values mean 2h.
+func metricsRangeStart(timeRange string, end time.Time) time.Time {
 	switch timeRange {
 	case "24h":
-		start = end.Add(-24 * time.Hour)
+		return end.Add(-24 * time.Hour)
 	case "7d":
-		start = end.Add(-7 * 24 * time.Hour)
+		return end.Add(-7 * 24 * time.Hour)
 	case "30d":
-		start = end.Add(-30 * 24 * time.Hour)
-	case "2h":
-		fallthrough
+		return end.Add(-30 * 24 * time.Hour)
 	default:
-		start = end.Add(-2 * time.Hour)
+		return end.Add(-2 * time.Hour)
 	}
+}
 
-	metrics, err := database.GetHistoricalMetrics(s.Db, jobID, start, end)
+// partitionMetricsResponse is the per-partition view of a job's metrics.
+type partitionMetricsResponse struct {
+	JobID      string                      `json:"job_id"`
+	Range      string                      `json:"range"`
+	Start      time.Time                   `json:"start"`
+	End        time.Time                   `json:"end"`
+	Partitions []database.PartitionSummary `json:"partitions"`
+	Series     []database.PartitionMetric  `json:"series,omitempty"`
+}
+
+// handleGetPartitionMetrics godoc
+// @Summary Get per-partition metrics for a job
+// @Description Get consumed/produced counts, bytes, errors and lag per source topic and partition. Partitions are summarized over the range; the raw time series (downsampled to 1m after an hour and 1h after a day) is included unless series=false.
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param range query string false "Time range (2h, 24h, 7d, 30d)" default(2h)
+// @Param topic query string false "Only this source topic"
+// @Param series query bool false "Include the time series" default(true)
+// @Success 200 {object} partitionMetricsResponse
+// @Failure 404 {object} map[string]string
+// @Router /jobs/{id}/metrics/partitions [get]
+// @Security ApiKeyAuth
+func (s *Server) handleGetPartitionMetrics(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	if _, err := database.GetJob(s.Db, jobID); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Job not found")
+	}
+
+	timeRange := c.Query("range", "2h")
+	end := time.Now()
+	start := metricsRangeStart(timeRange, end)
+
+	series, err := database.GetPartitionMetrics(s.Db, jobID, c.Query("topic"), start, end)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get historical metrics")
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get partition metrics")
 	}
-	return c.JSON(metrics)
+
+	resp := partitionMetricsResponse{
+		JobID:      jobID,
+		Range:      timeRange,
+		Start:      start,
+		End:        end,
+		Partitions: database.SummarizePartitionMetrics(series),
+	}
+	if c.QueryBool("series", true) {
+		resp.Series = series
+	}
+	return c.JSON(resp)
 }
 
 // handleGetOperationalEvents godoc
@@ -933,67 +1032,71 @@ func (s *Server) handleGetOperationalEvents(c *fiber.Ctx) error {
 	return c.JSON(events)
 }
 
+// jobLagResponse is the replication lag of a job, in messages and in seconds
+// behind source.
+type jobLagResponse struct {
+	JobID         string                  `json:"job_id"`
+	Running       bool                    `json:"running"`
+	MessageLag    int                     `json:"message_lag"`
+	LagSecondsP50 float64                 `json:"lag_seconds_p50"`
+	LagSecondsP99 float64                 `json:"lag_seconds_p99"`
+	LagSecondsMax float64                 `json:"lag_seconds_max"`
+	Topics        []database.TopicLag     `json:"topics"`
+	Partitions    []database.PartitionLag `json:"partitions"`
+	Timestamp     time.Time               `json:"timestamp"`
+}
+
 // handleGetLag godoc
-// @Summary Get consumer lag for a job
-// @Description Get the current consumer lag for a replication job.
+// @Summary Get replication lag for a job
+// @Description Get the message lag and the time-based lag (seconds behind source, p50/p99/max) of a replication job, broken down per topic and partition. Jobs that are not running report the last persisted job-level values only.
 // @Tags jobs
 // @Produce json
 // @Param id path string true "Job ID"
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} jobLagResponse
+// @Failure 404 {object} map[string]string
 // @Router /jobs/{id}/lag [get]
 // @Security ApiKeyAuth
 func (s *Server) handleGetLag(c *fiber.Ctx) error {
 	jobID := c.Params("id")
-	job, err := database.GetJob(s.Db, jobID)
-	if err != nil {
+	if _, err := database.GetJob(s.Db, jobID); err != nil {
 		return fiber.NewError(fiber.StatusNotFound, "Job not found")
 	}
 
-	mappings, err := database.GetMappingsForJob(s.Db, jobID)
-	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, "Could not get mappings for job")
+	resp := jobLagResponse{
+		JobID:      jobID,
+		Topics:     []database.TopicLag{},
+		Partitions: []database.PartitionLag{},
 	}
 
-	if len(mappings) == 0 {
-		return c.JSON(fiber.Map{})
-	}
+	s.manager.Mu.Lock()
+	_, resp.Running = s.manager.KafMirrors[jobID]
+	s.manager.Mu.Unlock()
 
-	sourceCluster, ok := s.cfg.Clusters[job.SourceClusterName]
-	if !ok {
-		return fiber.NewError(fiber.StatusNotFound, "Source cluster for job not configured")
+	if metric, ok := s.manager.LatestMetric(jobID); ok && resp.Running {
+		resp.MessageLag = metric.CurrentLag
+		resp.LagSecondsP50 = metric.LagSecondsP50
+		resp.LagSecondsP99 = metric.LagSecondsP99
+		resp.LagSecondsMax = metric.LagSecondsMax
+		resp.Timestamp = metric.Timestamp
+		if metric.TopicLags != nil {
+			resp.Topics = metric.TopicLags
+		}
+		if metric.PartitionLags != nil {
+			resp.Partitions = metric.PartitionLags
+		}
+		return c.JSON(resp)
 	}
 
-	adminClient, err := kafka.NewAdminClient(sourceCluster)
+	metric, err := database.GetLatestMetrics(s.Db, jobID)
 	if err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to create source admin client")
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get job metrics")
 	}
-	defer adminClient.Close()
-
-	groupID := "kaf-mirror-group-" + job.ID // Use job ID for unique group ID
-	lagResults := make(map[string]interface{})
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-
-	for _, mapping := range mappings {
-		if !mapping.Enabled {
-			continue
-		}
-		wg.Add(1)
-		go func(m database.TopicMapping) {
-			defer wg.Done()
-			lag, err := adminClient.GetTopicLag(context.Background(), m.SourceTopicPattern, groupID)
-			mu.Lock()
-			defer mu.Unlock()
-			if err != nil {
-				lagResults[m.SourceTopicPattern] = fmt.Sprintf("error: %v", err)
-			} else {
-				lagResults[m.SourceTopicPattern] = lag
-			}
-		}(mapping)
-	}
-	wg.Wait()
-
-	return c.JSON(lagResults)
+	resp.MessageLag = metric.CurrentLag
+	resp.LagSecondsP50 = metric.LagSecondsP50
+	resp.LagSecondsP99 = metric.LagSecondsP99
+	resp.LagSecondsMax = metric.LagSecondsMax
+	resp.Timestamp = metric.Timestamp
+	return c.JSON(resp)
 }
 
 // --- AI Handlers ---
@@ -1277,23 +1380,114 @@ func (s *Server) handleGenerateToken(c *fiber.Ctx) error {
 	}
 
 	user, err := database.GetUserByUsername(s.Db, creds.Username)
-	if err != nil {
-		return fiber.NewError(fiber.StatusUnauthorized, "Invalid credentials")
+	known := err == nil
+	if known && user.IsLocked() {
+		log.Printf("Login attempt for locked user: %s", creds.Username)
+		s.recordLoginFailure(c, creds.Username, nil, "account locked")
+		return fiber.NewError(fiber.StatusUnauthorized, "Account is temporarily locked due to failed login attempts")
+	}
+
+	if !known || user.AuthSource == database.AuthSourceLDAP {
+		// Unknown or directory-owned users are authenticated against LDAP when it is enabled.
+		if s.cfg == nil || !s.cfg.Auth.LDAP.Enabled {
+			s.recordLoginFailure(c, creds.Username, nil, "unknown user")
+			return fiber.NewError(fiber.StatusUnauthorized, "Invalid credentials")
+		}
+		ldapLogin, err := auth.NewLDAPAuthenticator(s.cfg.Auth.LDAP).Login(s.Db, creds.Username, creds.Password)
+		if err != nil {
+			log.Printf("LDAP authentication failed for user %s: %v", creds.Username, err)
+			var counted *database.User
+			if known && errors.Is(err, auth.ErrInvalidCredentials) {
+				counted = user
+			}
+			s.recordLoginFailure(c, creds.Username, counted, "directory authentication failed: "+err.Error())
+			return fiber.NewError(fiber.StatusUnauthorized, "Invalid credentials")
+		}
+		user = ldapLogin
+	} else {
+		if user.Disabled {
+			log.Printf("Login attempt for disabled user: %s", creds.Username)
+			s.recordLoginFailure(c, creds.Username, nil, "user disabled")
+			return fiber.NewError(fiber.StatusUnauthorized, "Invalid credentials")
+		}
+		if !user.VerifyPassword(creds.Password) {
+			log.Printf("Invalid password for user: %s", creds.Username)
+			s.recordLoginFailure(c, creds.Username, user, "invalid password")
+			return fiber.NewError(fiber.StatusUnauthorized, "Invalid credentials")
+		}
 	}
 
-	if !user.VerifyPassword(creds.Password) {
-		log.Printf("Invalid password for user: %s", creds.Username)
-		return fiber.NewError(fiber.StatusUnauthorized, "Invalid credentials")
+	if user.FailedLoginCount > 0 || user.LockedUntil != nil {
+		if err := database.ClearFailedLogins(s.Db, user.ID); err != nil {
+			log.Printf("Failed to reset failed logins for user %s: %v", user.Username, err)
+		}
 	}
 
-	token, _, err := database.CreateApiToken(s.Db, user.ID, "User-generated token", time.Now().Add(24*time.Hour))
+	token, apiToken, err := database.CreateApiToken(s.Db, user.ID, "User-generated token", time.Now().Add(24*time.Hour))
 	if err != nil {
 		return fiber.NewError(fiber.StatusInternalServerError, "Failed to generate token")
 	}
 
+	s.recordSecurityEvent(database.EventLoginSucceeded, user.Username, fiber.Map{
+		"ip":       c.IP(),
+		"token_id": apiToken.ID,
+	})
+
 	return c.JSON(fiber.Map{"token": token})
 }
 
+// recordLoginFailure records a failed login. When user is set, the failure counts towards the
+// account lockout; attempts against unknown, locked or disabled accounts are not counted.
+func (s *Server) recordLoginFailure(c *fiber.Ctx, username string, user *database.User, reason string) {
+	s.recordSecurityEvent(database.EventLoginFailed, username, fiber.Map{
+		"username": username,
+		"reason":   reason,
+		"ip":       c.IP(),
+	})
+
+	if user == nil || s.cfg == nil || s.cfg.Auth.Lockout.MaxFailedAttempts <= 0 {
+		return
+	}
+	lockout := s.cfg.Auth.Lockout
+	window, err := time.ParseDuration(lockout.Window)
+	if err != nil || window <= 0 {
+		window = 15 * time.Minute
+	}
+	duration, err := time.ParseDuration(lockout.Duration)
+	if err != nil || duration <= 0 {
+		duration = 15 * time.Minute
+	}
+
+	count, err := database.RecordFailedLogin(s.Db, user.ID, window)
+	if err != nil {
+		log.Printf("Failed to record failed login for user %s: %v", username, err)
+		return
+	}
+	if count < lockout.MaxFailedAttempts {
+		return
+	}
+
+	until := time.Now().Add(duration)
+	if err := database.LockUser(s.Db, user.ID, until); err != nil {
+		log.Printf("Failed to lock user %s: %v", username, err)
+		return
+	}
+	log.Printf("Locked user %s until %s after %d failed logins", username, until.Format(time.RFC3339), count)
+	s.recordSecurityEvent(database.EventAccountLocked, username, fiber.Map{
+		"username":        username,
+		"failed_attempts": count,
+		"locked_until":    until,
+		"ip":              c.IP(),
+	})
+}
+
+// recordSecurityEvent writes a security event to the audit trail, logging but not failing on errors.
+func (s *Server) recordSecurityEvent(eventType, initiator string, details fiber.Map) {
+	if err := database.RecordSecurityEvent(s.Db, eventType, initiator, details); err != nil {
+		log.Printf("Failed to record %s event: %v", eventType, err)
+	}
+}
+
 // handleListUsers godoc
 // @Summary List all users
 // @Description Get a list of all users.
@@ -1479,6 +1673,10 @@ func (s *Server) handleChangePassword(c *fiber.Ctx) error {
 
 	user := c.Locals("user").(*database.User)
 
+	if user.AuthSource == database.AuthSourceLDAP {
+		return fiber.NewError(fiber.StatusBadRequest, "Password is managed by the LDAP directory")
+	}
+
 	if !user.VerifyPassword(req.OldPassword) {
 		return fiber.NewError(fiber.StatusUnauthorized, "Invalid credentials")
 	}
@@ -1641,22 +1839,75 @@ func (s *Server) handleGetComplianceReport(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "Invalid report ID")
 	}
 
-	var report database.ComplianceReport
-	query := `
-		SELECT id, period, start_date, end_date, generated_by, generated_at, report_data
-		FROM compliance_reports WHERE id = ?
-	`
-	err = s.Db.Get(&report, query, reportID)
+	report, err := database.GetComplianceReport(s.Db, reportID)
 	if err != nil {
-		return fiber.NewError(fiber.StatusNotFound, "Compliance report not found")
+		if errors.Is(err, sql.ErrNoRows) {
+			return fiber.NewError(fiber.StatusNotFound, "Compliance report not found")
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to load compliance report")
 	}
 
-	// Parse JSON data
-	if err := json.Unmarshal([]byte(report.ReportDataDB), &report.ReportData); err != nil {
-		return fiber.NewError(fiber.StatusInternalServerError, "Failed to parse report data")
+	return c.JSON(report)
+}
+
+// handleExportComplianceReport godoc
+// @Summary Export a compliance report
+// @Description Export a compliance report as JSON, CSV, HTML, PDF or as a zip evidence bundle. The bundle contains the rendered report, the operational events and inventory snapshots of the period, the audit chain verification and a manifest with SHA-256 checksums (signed with the audit HMAC key when configured).
+// @Tags compliance
+// @Param id path int true "Report ID"
+// @Param format query string false "Export format (json, csv, html, pdf, bundle)" default(bundle)
+// @Success 200 {file} file
+// @Router /compliance/report/{id}/export [get]
+// @Security ApiKeyAuth
+func (s *Server) handleExportComplianceReport(c *fiber.Ctx) error {
+	reportID, err := c.ParamsInt("id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid report ID")
 	}
 
-	return c.JSON(report)
+	report, err := database.GetComplianceReport(s.Db, reportID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fiber.NewError(fiber.StatusNotFound, "Compliance report not found")
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to load compliance report")
+	}
+
+	format := strings.ToLower(c.Query("format", database.ComplianceFormatBundle))
+	var data []byte
+	var contentType, extension string
+	switch format {
+	case database.ComplianceFormatJSON:
+		data, err = json.MarshalIndent(report, "", "  ")
+		contentType, extension = "application/json", "json"
+	case database.ComplianceFormatCSV:
+		data, err = database.GenerateComplianceCSV(report)
+		contentType, extension = "text/csv", "csv"
+	case database.ComplianceFormatHTML:
+		data, err = database.GenerateComplianceHTML(report)
+		contentType, extension = "text/html; charset=utf-8", "html"
+	case database.ComplianceFormatPDF:
+		data, err = database.GenerateCompliancePDF(report)
+		contentType, extension = "application/pdf", "pdf"
+	case database.ComplianceFormatBundle:
+		hmacKey := ""
+		if s.cfg != nil {
+			hmacKey = s.cfg.Audit.HMACKey
+		}
+		data, err = database.GenerateEvidenceBundle(s.Db, report, hmacKey)
+		contentType, extension = "application/zip", "zip"
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid format. Use json, csv, html, pdf or bundle")
+	}
+	if err != nil {
+		log.Printf("Error exporting compliance report %d as %s: %v", reportID, format, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to export compliance report")
+	}
+
+	c.Set("Content-Type", contentType)
+	c.Set("Content-Disposition", fmt.Sprintf("attachment; filename=compliance-report-%d-%s-%s.%s",
+		report.ID, report.Period, report.GeneratedAt.Format("2006-01-02"), extension))
+	return c.Send(data)
 }
 
 // handleDashboard serves the main dashboard with role-based access
@@ -1685,9 +1936,19 @@ func (s *Server) handleWebSocketAuth(c *fiber.Ctx) error {
 		log.Printf("WebSocket connection failed token validation from %s: %v", c.IP(), err)
 		return c.Status(401).JSON(fiber.Map{"error": "Invalid authentication token"})
 	}
+	apiToken, err := database.GetApiToken(s.Db, token)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{"error": "Invalid authentication token"})
+	}
+	if !apiToken.AllowsIP(c.IP()) {
+		log.Printf("WebSocket connection with token %d from disallowed address %s", apiToken.ID, c.IP())
+		return c.Status(403).JSON(fiber.Map{"error": "Token is not allowed from this address"})
+	}
 
-	// Store user in context for WebSocket handler
+	// Store user and token in context for WebSocket handler; channel
+	// subscriptions are checked against both.
 	c.Locals("user", user)
+	c.Locals("token", apiToken)
 
 	// Upgrade to WebSocket
 	if websocket.IsWebSocketUpgrade(c) {
@@ -1709,6 +1970,9 @@ func (s *Server) validateTokenDetailed(tokenString string) (*database.User, erro
 		log.Printf("User lookup failed for userID %d: %v", userID, err)
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
+	if user.Disabled {
+		return nil, fmt.Errorf("user %s is disabled", user.Username)
+	}
 
 	return user, nil
 }
@@ -2412,23 +2676,19 @@ func (s *Server) handleCreateMigrationCheckpoint(c *fiber.Ctx) error {
 // --- WebSocket Handler ---
 
 func (s *Server) handleWebSocket(c *websocket.Conn) {
-	s.hub.register <- c
-	defer func() {
-		s.hub.unregister <- c
-	}()
-
-	for {
-		// Read message from client
-		mt, msg, err := c.ReadMessage()
-		if err != nil {
-			// Handle error (e.g., client disconnected)
-			break
-		}
-
-		// Echo the message back
-		if err := c.WriteMessage(mt, msg); err != nil {
-			// Handle error
-			break
-		}
-	}
+	user, _ := c.Locals("user").(*database.User)
+	token, _ := c.Locals("token").(*database.ApiToken)
+	client := newClient(s.hub, c, user, token)
+	session := newWSSession(s, client)
+
+	s.hub.add(client)
+	go client.writePump()
+	session.readLoop()
+
+	// The connection is recycled once this handler returns, so wait for the
+	// writer before leaving.
+	client.close(websocket.CloseNormalClosure, "")
+	<-client.writerDone
+	s.hub.remove(client)
+	session.close()
 }
@@ -9,68 +9,300 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-
 package server
 
 import (
 	"encoding/json"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/pkg/logger"
 	"sync"
+	"time"
 
 	"github.com/gofiber/contrib/websocket"
 )
 
-// Hub maintains the set of active clients and broadcasts messages to them.
+// EventSchemaVersion is the version of the WebSocket event envelope. It is
+// bumped when a field is removed or changes meaning; new event types and
+// fields may be added within a version.
+const EventSchemaVersion = 1
+
+// WebSocket channels clients can subscribe to.
+const (
+	ChannelJobMetrics = "job_metrics"
+	ChannelJobLogs    = "job_logs"
+	ChannelEvents     = "events"
+	ChannelAlerts     = "alerts"
+	ChannelInsights   = "insights"
+)
+
+// channelPermissions is the permission required to subscribe to each channel.
+var channelPermissions = map[string]string{
+	ChannelJobMetrics: "metrics:view",
+	ChannelJobLogs:    "jobs:view",
+	ChannelEvents:     "events:view",
+	ChannelAlerts:     "alerts:view",
+	ChannelInsights:   "ai:insights:view",
+}
+
+// channelEventTypes is the event type of the messages published on each channel.
+var channelEventTypes = map[string]string{
+	ChannelJobMetrics: "job_metrics",
+	ChannelJobLogs:    "job_log",
+	ChannelEvents:     "event",
+	ChannelAlerts:     "alert",
+	ChannelInsights:   "insight",
+}
+
+const (
+	defaultPingInterval  = 30 * time.Second
+	defaultSendQueueSize = 256
+	writeWait            = 10 * time.Second
+	maxClientMessageSize = 64 * 1024
+)
+
+// Event is the envelope of every message the server sends over the WebSocket.
+//
+// Data messages carry the channel they were published on and, for job
+// scoped data, the job ID. Control messages (welcome, subscribed,
+// unsubscribed, pong, error) answer client requests.
+type Event struct {
+	Version int         `json:"v"`
+	Type    string      `json:"type"`
+	Channel string      `json:"channel,omitempty"`
+	JobID   string      `json:"job_id,omitempty"`
+	Time    time.Time   `json:"time"`
+	Data    interface{} `json:"data,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// Hub tracks connected WebSocket clients and routes published events to the
+// clients subscribed to them. Every client has its own bounded send queue and
+// writer, so a slow client never delays the others; a client whose queue
+// overflows is disconnected.
 type Hub struct {
-	clients    map[*websocket.Conn]bool
-	broadcast  chan []byte
-	register   chan *websocket.Conn
-	unregister chan *websocket.Conn
-	mu         sync.Mutex
+	mu      sync.RWMutex
+	clients map[*Client]struct{}
+
+	// PingInterval is how often clients are pinged. A client that sends
+	// nothing, not even a pong, for two intervals is disconnected.
+	PingInterval time.Duration
+	// SendQueueSize is the number of events queued per client.
+	SendQueueSize int
 }
 
 // NewHub creates a new Hub.
 func NewHub() *Hub {
 	return &Hub{
-		clients:    make(map[*websocket.Conn]bool),
-		broadcast:  make(chan []byte),
-		register:   make(chan *websocket.Conn),
-		unregister: make(chan *websocket.Conn),
+		clients:       make(map[*Client]struct{}),
+		PingInterval:  defaultPingInterval,
+		SendQueueSize: defaultSendQueueSize,
+	}
+}
+
+// Publish sends data to every client subscribed to channel. jobID is set for
+// job scoped data and is matched against the client's job filter.
+func (h *Hub) Publish(channel, jobID string, data interface{}) {
+	msg, err := json.Marshal(Event{
+		Version: EventSchemaVersion,
+		Type:    channelEventTypes[channel],
+		Channel: channel,
+		JobID:   jobID,
+		Time:    time.Now().UTC(),
+		Data:    data,
+	})
+	if err != nil {
+		logger.Warn("Failed to encode %s event: %v", channel, err)
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for client := range h.clients {
+		if client.subscribed(channel, jobID) {
+			client.enqueue(msg)
+		}
+	}
+}
+
+// Clients returns the number of connected clients.
+func (h *Hub) Clients() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients)
+}
+
+func (h *Hub) add(client *Client) {
+	h.mu.Lock()
+	h.clients[client] = struct{}{}
+	h.mu.Unlock()
+}
+
+func (h *Hub) remove(client *Client) {
+	h.mu.Lock()
+	delete(h.clients, client)
+	h.mu.Unlock()
+}
+
+// Client is a connected WebSocket client and its subscriptions.
+type Client struct {
+	hub   *Hub
+	conn  *websocket.Conn
+	user  *database.User
+	token *database.ApiToken
+
+	send       chan []byte
+	closed     chan struct{}
+	closeOnce  sync.Once
+	closeCode  int
+	closeText  string
+	writerDone chan struct{}
+
+	mu   sync.Mutex
+	subs map[string]map[string]struct{} // channel -> job IDs; empty means every job
+}
+
+func newClient(h *Hub, conn *websocket.Conn, user *database.User, token *database.ApiToken) *Client {
+	queueSize := h.SendQueueSize
+	if queueSize <= 0 {
+		queueSize = defaultSendQueueSize
+	}
+	return &Client{
+		hub:        h,
+		conn:       conn,
+		user:       user,
+		token:      token,
+		send:       make(chan []byte, queueSize),
+		closed:     make(chan struct{}),
+		writerDone: make(chan struct{}),
+		subs:       make(map[string]map[string]struct{}),
+	}
+}
+
+// subscribe adds channel to the client's subscriptions, limited to jobIDs
+// when any are given. Subscribing again replaces the job filter.
+func (c *Client) subscribe(channel string, jobIDs []string) {
+	jobs := make(map[string]struct{}, len(jobIDs))
+	for _, id := range jobIDs {
+		jobs[id] = struct{}{}
 	}
+	c.mu.Lock()
+	c.subs[channel] = jobs
+	c.mu.Unlock()
 }
 
-// Run starts the hub's event loop.
-func (h *Hub) Run() {
+func (c *Client) unsubscribe(channel string) {
+	c.mu.Lock()
+	delete(c.subs, channel)
+	c.mu.Unlock()
+}
+
+// subscribed reports whether a publication should reach this client. Job-restricted
+// tokens only receive publications for their jobs, never ones that span all jobs.
+func (c *Client) subscribed(channel, jobID string) bool {
+	c.mu.Lock()
+	jobs, ok := c.subs[channel]
+	c.mu.Unlock()
+	if !ok {
+		return false
+	}
+	if len(jobs) > 0 {
+		if _, ok := jobs[jobID]; !ok {
+			return false
+		}
+	}
+	return c.token == nil || c.token.AllowsJob(jobID)
+}
+
+// sendEvent queues a control or data event for this client only.
+func (c *Client) sendEvent(ev Event) {
+	ev.Version = EventSchemaVersion
+	if ev.Time.IsZero() {
+		ev.Time = time.Now().UTC()
+	}
+	msg, err := json.Marshal(ev)
+	if err != nil {
+		logger.Warn("Failed to encode %s event: %v", ev.Type, err)
+		return
+	}
+	c.enqueue(msg)
+}
+
+// enqueue queues msg without blocking. A client whose queue is full is
+// disconnected rather than allowed to hold up publishers.
+func (c *Client) enqueue(msg []byte) {
+	select {
+	case <-c.closed:
+		return
+	default:
+	}
+	select {
+	case c.send <- msg:
+	default:
+		logger.Warn("Disconnecting WebSocket client %s: send queue full", c.username())
+		c.close(websocket.ClosePolicyViolation, "send queue full")
+	}
+}
+
+func (c *Client) username() string {
+	if c.user == nil {
+		return "unknown"
+	}
+	return c.user.Username
+}
+
+// close asks the writer to send a close frame and shut the connection down.
+func (c *Client) close(code int, text string) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		c.closeText = text
+		close(c.closed)
+	})
+}
+
+// writePump is the only goroutine writing to the connection. It drains the
+// send queue, pings the client and closes the connection when the client is
+// closed or a write fails, which also ends the reader.
+func (c *Client) writePump() {
+	interval := c.hub.PingInterval
+	if interval <= 0 {
+		interval = defaultPingInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+		close(c.writerDone)
+	}()
+
 	for {
 		select {
-		case conn := <-h.register:
-			h.mu.Lock()
-			h.clients[conn] = true
-			h.mu.Unlock()
-		case conn := <-h.unregister:
-			h.mu.Lock()
-			if _, ok := h.clients[conn]; ok {
-				delete(h.clients, conn)
-				conn.Close()
+		case msg := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.TextMessage, msg); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
 			}
-			h.mu.Unlock()
-		case message := <-h.broadcast:
-			h.mu.Lock()
-			for conn := range h.clients {
-				if err := conn.WriteMessage(websocket.TextMessage, message); err != nil {
-					// Handle error, e.g., remove the client
-				}
+		case <-c.closed:
+			if c.closeCode != websocket.CloseAbnormalClosure {
+				c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+				c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(c.closeCode, c.closeText))
 			}
-			h.mu.Unlock()
+			return
 		}
 	}
 }
 
-// BroadcastJSON sends a JSON-encoded message to all clients.
-func (h *Hub) BroadcastJSON(v interface{}) {
-	msg, err := json.Marshal(v)
-	if err != nil {
-		// Handle error
-		return
+// closeAll disconnects every client, e.g. on server shutdown.
+func (h *Hub) closeAll(code int, text string) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for client := range h.clients {
+		client.close(code, text)
 	}
-	h.broadcast <- msg
 }
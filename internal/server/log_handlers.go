@@ -0,0 +1,155 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/joblogs"
+	"kaf-mirror/pkg/logger"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	defaultJobLogLimit = 200
+	// logStreamHeartbeat is how often an empty line is written to an idle
+	// follow stream so proxies keep it open and dead clients are noticed.
+	logStreamHeartbeat = 15 * time.Second
+)
+
+type jobLogsResponse struct {
+	JobID string          `json:"job_id"`
+	Logs  []joblogs.Entry `json:"logs"`
+}
+
+// parseJobLogQuery reads the since, level, limit and after query parameters.
+func parseJobLogQuery(c *fiber.Ctx) (joblogs.Query, error) {
+	q := joblogs.Query{Limit: defaultJobLogLimit}
+
+	if since := c.Query("since"); since != "" {
+		if ts, err := time.Parse(time.RFC3339, since); err == nil {
+			q.Since = ts
+		} else if d, err := time.ParseDuration(since); err == nil && d > 0 {
+			q.Since = time.Now().Add(-d)
+		} else {
+			return q, fiber.NewError(fiber.StatusBadRequest, "since must be an RFC3339 timestamp or a duration such as 15m")
+		}
+	}
+	if level := c.Query("level"); level != "" {
+		parsed, err := logger.ParseLevel(level)
+		if err != nil {
+			return q, fiber.NewError(fiber.StatusBadRequest, "level must be one of debug, info, warn, error")
+		}
+		q.MinLevel = parsed
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			return q, fiber.NewError(fiber.StatusBadRequest, "limit must be a non-negative integer")
+		}
+		q.Limit = n
+	}
+	if after := c.Query("after"); after != "" {
+		n, err := strconv.ParseUint(after, 10, 64)
+		if err != nil {
+			return q, fiber.NewError(fiber.StatusBadRequest, "after must be a sequence number")
+		}
+		q.AfterSeq = n
+	}
+	return q, nil
+}
+
+// handleGetJobLogs godoc
+// @Summary Get recent logs for a job
+// @Description Returns the job's buffered log records, oldest first. With follow=true the response is newline-delimited JSON that stays open and streams new records until the client disconnects.
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param since query string false "RFC3339 timestamp or duration such as 15m"
+// @Param level query string false "Minimum level: debug, info, warn or error"
+// @Param limit query int false "Maximum number of buffered records to return (default 200, 0 for all)"
+// @Param after query int false "Only return records with a higher sequence number"
+// @Param follow query bool false "Keep the connection open and stream new records"
+// @Success 200 {object} server.jobLogsResponse
+// @Router /jobs/{id}/logs [get]
+// @Security ApiKeyAuth
+func (s *Server) handleGetJobLogs(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	if _, err := database.GetJob(s.Db, jobID); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Job not found")
+	}
+	if s.manager.Logs == nil {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "Job logs are not available")
+	}
+	q, err := parseJobLogQuery(c)
+	if err != nil {
+		return err
+	}
+
+	if !c.QueryBool("follow") {
+		return c.JSON(jobLogsResponse{JobID: jobID, Logs: s.manager.Logs.Query(jobID, q)})
+	}
+
+	// Subscribe before reading the backlog so nothing logged in between is
+	// lost; duplicates are skipped by sequence number.
+	sub := s.manager.Logs.Subscribe(jobID, q.MinLevel)
+	backlog := s.manager.Logs.Query(jobID, q)
+
+	c.Set(fiber.HeaderContentType, "application/x-ndjson")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer sub.Close()
+
+		enc := json.NewEncoder(w)
+		var last uint64
+		for _, entry := range backlog {
+			if err := enc.Encode(entry); err != nil {
+				return
+			}
+			last = entry.Seq
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		heartbeat := time.NewTicker(logStreamHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case entry, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				if entry.Seq <= last {
+					continue
+				}
+				if err := enc.Encode(entry); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				if _, err := w.WriteString("\n"); err != nil {
+					return
+				}
+			case <-s.done:
+				return
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+	return nil
+}
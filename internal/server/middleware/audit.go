@@ -24,9 +24,30 @@ import (
 )
 
 // AuditLog is a middleware that logs state-modifying actions.
-func AuditLog(db *sqlx.DB) fiber.Handler {
+// Successful GETs of the routes listed in sensitiveReads are recorded as sensitive_read events.
+func AuditLog(db *sqlx.DB, sensitiveReads []string) fiber.Handler {
+	sensitive := make(map[string]bool, len(sensitiveReads))
+	for _, route := range sensitiveReads {
+		sensitive[normalizeRoute(route)] = true
+	}
+
 	return func(c *fiber.Ctx) error {
-		if c.Method() == "GET" || c.Method() == "HEAD" || c.Method() == "OPTIONS" {
+		if c.Method() == "GET" {
+			err := c.Next()
+			if err == nil && c.Response().StatusCode() < 300 && sensitive[normalizeRoute(c.Route().Path)] {
+				initiator := "Anonymous"
+				if u, ok := c.Locals("user").(*database.User); ok && u != nil {
+					initiator = u.Username
+				}
+				details := fiber.Map{"route": c.Route().Path}
+				if apiToken, ok := c.Locals("token").(*database.ApiToken); ok && apiToken != nil {
+					details["token_id"] = apiToken.ID
+				}
+				recordSecurityEvent(db, c, database.EventSensitiveRead, initiator, details)
+			}
+			return err
+		}
+		if c.Method() == "HEAD" || c.Method() == "OPTIONS" {
 			return c.Next()
 		}
 
@@ -69,6 +90,13 @@ func AuditLog(db *sqlx.DB) fiber.Handler {
 	}
 }
 
+func normalizeRoute(route string) string {
+	if route != "/" {
+		route = strings.TrimSuffix(route, "/")
+	}
+	return route
+}
+
 // formatAuditDetails creates human-readable audit details while masking sensitive data
 func formatAuditDetails(c *fiber.Ctx) string {
 	path := c.Path()
@@ -41,15 +41,28 @@ func AuthRequired(db *sqlx.DB) fiber.Handler {
 		}
 
 		token := parts[1]
-		userID, err := database.ValidateApiToken(db, token)
+		apiToken, err := database.GetApiToken(db, token)
 		if err != nil {
 			log.Printf("ERROR: Auth: Invalid or expired JWT: %v", err)
+			recordSecurityEvent(db, c, database.EventAuthFailed, "", fiber.Map{"reason": "invalid or expired token"})
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"error": "Invalid or expired JWT",
 			})
 		}
 
-		user, err := database.GetUser(db, userID)
+		if !apiToken.AllowsIP(c.IP()) {
+			log.Printf("ERROR: Auth: Token %d used from disallowed address %s", apiToken.ID, c.IP())
+			recordSecurityEvent(db, c, database.EventAuthFailed, "", fiber.Map{"reason": "address not allowed", "token_id": apiToken.ID})
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Token is not allowed from this address",
+			})
+		}
+
+		if err := database.TouchApiToken(db, apiToken.ID, c.IP()); err != nil {
+			log.Printf("WARN: Auth: Failed to record token usage: %v", err)
+		}
+
+		user, err := database.GetUser(db, apiToken.UserID)
 		if err != nil {
 			log.Printf("ERROR: Auth: Invalid user: %v", err)
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -57,9 +70,28 @@ func AuthRequired(db *sqlx.DB) fiber.Handler {
 			})
 		}
 
-		// Store user object in context for use in handlers
+		if user.Disabled {
+			log.Printf("ERROR: Auth: Disabled user %s", user.Username)
+			recordSecurityEvent(db, c, database.EventAuthFailed, user.Username, fiber.Map{"reason": "user disabled", "token_id": apiToken.ID})
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "User account is disabled",
+			})
+		}
+
+		// Store user and token in context for use in handlers
 		c.Locals("user", user)
+		c.Locals("token", apiToken)
 
 		return c.Next()
 	}
 }
+
+// recordSecurityEvent adds the request context to a security event and writes it to the audit trail.
+func recordSecurityEvent(db *sqlx.DB, c *fiber.Ctx, eventType, initiator string, details fiber.Map) {
+	details["method"] = c.Method()
+	details["path"] = c.Path()
+	details["ip"] = c.IP()
+	if err := database.RecordSecurityEvent(db, eventType, initiator, details); err != nil {
+		log.Printf("WARN: Audit: Failed to record %s event: %v", eventType, err)
+	}
+}
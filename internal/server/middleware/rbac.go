@@ -14,6 +14,7 @@ package middleware
 
 import (
 	"kaf-mirror/internal/database"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/jmoiron/sqlx"
@@ -37,11 +38,73 @@ func PermissionRequired(db *sqlx.DB, permission string) fiber.Handler {
 		}
 
 		if !hasPerm {
+			recordSecurityEvent(db, c, database.EventPermissionDenied, user.Username, fiber.Map{
+				"permission": permission,
+				"reason":     "role does not grant permission",
+			})
 			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
 				"error": "Forbidden",
 			})
 		}
 
+		// The effective permissions of a token are the intersection of its scope and the user's role.
+		if apiToken, ok := c.Locals("token").(*database.ApiToken); ok {
+			if !apiToken.AllowsPermission(permission) {
+				recordSecurityEvent(db, c, database.EventPermissionDenied, user.Username, fiber.Map{
+					"permission": permission,
+					"reason":     "token scope does not include permission",
+					"token_id":   apiToken.ID,
+				})
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error": "Token scope does not include " + permission,
+				})
+			}
+			if !tokenAllowsJobRoute(c, apiToken) {
+				recordSecurityEvent(db, c, database.EventPermissionDenied, user.Username, fiber.Map{
+					"permission": permission,
+					"reason":     "token is not scoped to this job",
+					"token_id":   apiToken.ID,
+				})
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error": "Token is not scoped to this job",
+				})
+			}
+		}
+
 		return c.Next()
 	}
 }
+
+// crossJobRoutes are route prefixes whose responses span every job. A job
+// restriction cannot be applied to them, so job-restricted tokens are refused.
+var crossJobRoutes = []string{
+	"/api/v1/events",
+	"/api/v1/slo",
+	"/api/v1/ai/",
+	"/api/v1/alerts",
+	"/api/v1/inventory/",
+	"/api/v1/webhooks",
+	"/api/v1/compliance/",
+}
+
+// tokenAllowsJobRoute enforces a token's job restriction on /jobs routes.
+// Job-restricted tokens may only act on their jobs, may not run bulk or create
+// operations, and may not read the cross-job endpoints in crossJobRoutes.
+func tokenAllowsJobRoute(c *fiber.Ctx, apiToken *database.ApiToken) bool {
+	if len(apiToken.JobIDList()) == 0 {
+		return true
+	}
+	path := c.Route().Path
+	for _, prefix := range crossJobRoutes {
+		if strings.HasPrefix(path, prefix) {
+			return false
+		}
+	}
+	if strings.Contains(path, "/jobs/:id") {
+		return apiToken.AllowsJob(c.Params("id"))
+	}
+	if strings.Contains(path, "/jobs") && c.Method() != fiber.MethodGet {
+		return false
+	}
+	return true
+}
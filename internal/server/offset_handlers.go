@@ -0,0 +1,74 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/manager"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// validateStartPositions checks the start position of a job and of its mappings.
+func validateStartPositions(job *database.StartPosition, mappings []database.TopicMapping) error {
+	if job != nil {
+		if err := config.StartPosition(*job).Validate(); err != nil {
+			return err
+		}
+	}
+	for _, m := range mappings {
+		if m.StartPosition == nil {
+			continue
+		}
+		if err := config.StartPosition(*m.StartPosition).Validate(); err != nil {
+			return fmt.Errorf("topic mapping %s: %v", m.SourceTopicPattern, err)
+		}
+	}
+	return nil
+}
+
+// handleResetJobOffsets godoc
+// @Summary Reset the consumer group offsets of a job
+// @Description Move the job's source consumer group to a start position: earliest, latest, a timestamp or explicit partition offsets. The offsets are checked against the source high water marks and returned per partition next to the committed offset. The job must be stopped unless dry_run is set, which only returns the preview.
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param dry_run query bool false "Only preview the new offsets"
+// @Param request body manager.OffsetResetRequest true "Start position and topics"
+// @Success 200 {object} manager.OffsetReset
+// @Failure 409 {object} map[string]interface{}
+// @Router /jobs/{id}/reset-offsets [post]
+// @Security ApiKeyAuth
+func (s *Server) handleResetJobOffsets(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	if _, err := database.GetJob(s.Db, jobID); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Job not found")
+	}
+	var req manager.OffsetResetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	user := c.Locals("user").(*database.User)
+	reset, err := s.manager.ResetJobOffsets(jobID, req, c.QueryBool("dry_run", false), user.Username)
+	if errors.Is(err, manager.ErrResetRunningJob) {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return c.JSON(reset)
+}
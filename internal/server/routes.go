@@ -26,7 +26,7 @@ func (s *Server) setupRoutes() {
 	authGroup.Post("/token", s.handleGenerateToken)
 	authGroup.Get("/me", middleware.AuthRequired(s.Db), s.handleGetMe)
 
-	api := s.App.Group("/api/v1", middleware.AuthRequired(s.Db), middleware.AuditLog(s.Db))
+	api := s.App.Group("/api/v1", middleware.AuthRequired(s.Db), middleware.AuditLog(s.Db, s.cfg.Audit.SensitiveReads))
 
 	configGroup := api.Group("/config", middleware.AuthRequired(s.Db))
 	configGroup.Get("/", middleware.PermissionRequired(s.Db, "config:view"), s.handleGetConfig)
@@ -67,11 +67,33 @@ func (s *Server) setupRoutes() {
 
 	jobsGroup.Get("/:id/metrics/current", middleware.PermissionRequired(s.Db, "metrics:view"), s.handleGetCurrentMetrics)
 	jobsGroup.Get("/:id/metrics/history", middleware.PermissionRequired(s.Db, "metrics:view"), s.handleGetHistoricalMetrics)
+	jobsGroup.Get("/:id/metrics/partitions", middleware.PermissionRequired(s.Db, "metrics:view"), s.handleGetPartitionMetrics)
 	jobsGroup.Get("/:id/lag", middleware.PermissionRequired(s.Db, "metrics:view"), s.handleGetLag)
+	jobsGroup.Get("/:id/slo", middleware.PermissionRequired(s.Db, "metrics:view"), s.handleListJobSLOs)
+	jobsGroup.Post("/:id/slo", middleware.PermissionRequired(s.Db, "jobs:edit"), s.handleCreateJobSLO)
+	jobsGroup.Put("/:id/slo/:sloId", middleware.PermissionRequired(s.Db, "jobs:edit"), s.handleUpdateJobSLO)
+	jobsGroup.Delete("/:id/slo/:sloId", middleware.PermissionRequired(s.Db, "jobs:edit"), s.handleDeleteJobSLO)
 	jobsGroup.Get("/:id/topic-health", middleware.PermissionRequired(s.Db, "jobs:view"), s.handleGetJobTopicHealth)
+	jobsGroup.Get("/:id/topic-configs/drift", middleware.PermissionRequired(s.Db, "jobs:view"), s.handleGetJobTopicConfigDrift)
+	jobsGroup.Post("/:id/topic-configs/sync", middleware.PermissionRequired(s.Db, "jobs:edit"), s.handleSyncJobTopicConfigs)
+	jobsGroup.Get("/:id/topic-configs/overrides", middleware.PermissionRequired(s.Db, "jobs:view"), s.handleGetJobTopicConfigOverrides)
+	jobsGroup.Put("/:id/topic-configs/overrides", middleware.PermissionRequired(s.Db, "jobs:edit"), s.handleUpdateJobTopicConfigOverrides)
+	jobsGroup.Get("/:id/acl-sync", middleware.PermissionRequired(s.Db, "jobs:view"), s.handleGetJobACLSync)
+	jobsGroup.Post("/:id/acl-sync", middleware.PermissionRequired(s.Db, "jobs:edit"), s.handleSyncJobACLs)
+	jobsGroup.Post("/:id/reset-offsets", middleware.PermissionRequired(s.Db, "jobs:edit"), s.handleResetJobOffsets)
+	jobsGroup.Post("/:id/backfills", middleware.PermissionRequired(s.Db, "jobs:create"), s.handleCreateBackfill)
+	jobsGroup.Get("/:id/backfill", middleware.PermissionRequired(s.Db, "jobs:view"), s.handleGetBackfill)
+	jobsGroup.Post("/:id/failover", middleware.PermissionRequired(s.Db, "jobs:failover"), s.handleStartFailover)
+	jobsGroup.Get("/:id/failovers", middleware.PermissionRequired(s.Db, "jobs:view"), s.handleListFailovers)
+	jobsGroup.Get("/:id/failovers/:operation_id", middleware.PermissionRequired(s.Db, "jobs:view"), s.handleGetFailover)
+	jobsGroup.Post("/:id/failovers/:operation_id/resume", middleware.PermissionRequired(s.Db, "jobs:failover"), s.handleResumeFailover)
+	jobsGroup.Get("/:id/dlq", middleware.PermissionRequired(s.Db, "jobs:view"), s.handleListDeadLetters)
+	jobsGroup.Post("/:id/dlq/replay", middleware.PermissionRequired(s.Db, "jobs:edit"), s.handleReplayDeadLetters)
+	jobsGroup.Get("/:id/logs", middleware.PermissionRequired(s.Db, "jobs:view"), s.handleGetJobLogs)
 
 	api.Get("/topics/source", middleware.PermissionRequired(s.Db, "clusters:view"), s.handleListSourceTopics)
 	api.Get("/topics/target", middleware.PermissionRequired(s.Db, "clusters:view"), s.handleListTargetTopics)
+	api.Get("/slo", middleware.PermissionRequired(s.Db, "metrics:view"), s.handleGetSLOOverview)
 
 	aiGroup := api.Group("/ai")
 	aiGroup.Get("/insights", middleware.PermissionRequired(s.Db, "ai:insights:view"), s.handleGetAIInsights)
@@ -104,8 +126,12 @@ func (s *Server) setupRoutes() {
 		mirrorGroup.Get("/resume-points", s.handleGetResumePoints)
 		mirrorGroup.Post("/resume-points", middleware.PermissionRequired(s.Db, "jobs:edit"), s.handleCalculateResumePoints)
 		mirrorGroup.Get("/gaps", s.handleGetMirrorGaps)
+		mirrorGroup.Post("/gaps/backfill", middleware.PermissionRequired(s.Db, "jobs:create"), s.handleCreateBackfillFromGaps)
 		mirrorGroup.Post("/validate-mirror", middleware.PermissionRequired(s.Db, "jobs:edit"), s.handleValidateMigration)
 		mirrorGroup.Post("/checkpoint", middleware.PermissionRequired(s.Db, "jobs:edit"), s.handleCreateMigrationCheckpoint)
+		mirrorGroup.Post("/verify", middleware.PermissionRequired(s.Db, "jobs:edit"), s.handleVerifyContent)
+		mirrorGroup.Get("/verifications", s.handleListVerifications)
+		mirrorGroup.Get("/verifications/:verification_id", s.handleGetVerification)
 	}
 
 	api.Get("/events", middleware.PermissionRequired(s.Db, "events:view"), s.handleGetOperationalEvents)
@@ -118,10 +144,42 @@ func (s *Server) setupRoutes() {
 	usersGroup.Put("/change-password", s.handleChangePassword)
 	usersGroup.Post("/:username/reset-password", middleware.PermissionRequired(s.Db, "users:create"), s.handleResetUserPassword)
 
+	tokensGroup := api.Group("/tokens")
+	tokensGroup.Get("/", s.handleListTokens)
+	tokensGroup.Post("/", s.handleCreateToken)
+	tokensGroup.Delete("/:id", s.handleRevokeToken)
+
+	alertsGroup := api.Group("/alerts")
+	alertsGroup.Get("/", middleware.PermissionRequired(s.Db, "alerts:view"), s.handleListAlerts)
+	alertsGroup.Get("/rules", middleware.PermissionRequired(s.Db, "alerts:view"), s.handleListAlertRules)
+	alertsGroup.Post("/rules", middleware.PermissionRequired(s.Db, "alerts:manage"), s.handleCreateAlertRule)
+	alertsGroup.Put("/rules/:id", middleware.PermissionRequired(s.Db, "alerts:manage"), s.handleUpdateAlertRule)
+	alertsGroup.Delete("/rules/:id", middleware.PermissionRequired(s.Db, "alerts:manage"), s.handleDeleteAlertRule)
+	alertsGroup.Get("/silences", middleware.PermissionRequired(s.Db, "alerts:view"), s.handleListAlertSilences)
+	alertsGroup.Post("/silences", middleware.PermissionRequired(s.Db, "alerts:manage"), s.handleCreateAlertSilence)
+	alertsGroup.Delete("/silences/:id", middleware.PermissionRequired(s.Db, "alerts:manage"), s.handleDeleteAlertSilence)
+	alertsGroup.Get("/channels", middleware.PermissionRequired(s.Db, "alerts:view"), s.handleListAlertChannels)
+	alertsGroup.Post("/channels/:name/test", middleware.PermissionRequired(s.Db, "alerts:manage"), s.handleTestAlertChannel)
+	alertsGroup.Get("/:id", middleware.PermissionRequired(s.Db, "alerts:view"), s.handleGetAlert)
+	alertsGroup.Post("/:id/ack", middleware.PermissionRequired(s.Db, "alerts:manage"), s.handleAcknowledgeAlert)
+
+	webhooksGroup := api.Group("/webhooks")
+	webhooksGroup.Get("/", middleware.PermissionRequired(s.Db, "webhooks:view"), s.handleListWebhooks)
+	webhooksGroup.Post("/", middleware.PermissionRequired(s.Db, "webhooks:manage"), s.handleCreateWebhook)
+	webhooksGroup.Get("/events", middleware.PermissionRequired(s.Db, "webhooks:view"), s.handleListWebhookEventTypes)
+	webhooksGroup.Post("/deliveries/:deliveryId/replay", middleware.PermissionRequired(s.Db, "webhooks:manage"), s.handleReplayWebhookDelivery)
+	webhooksGroup.Get("/:id", middleware.PermissionRequired(s.Db, "webhooks:view"), s.handleGetWebhook)
+	webhooksGroup.Put("/:id", middleware.PermissionRequired(s.Db, "webhooks:manage"), s.handleUpdateWebhook)
+	webhooksGroup.Delete("/:id", middleware.PermissionRequired(s.Db, "webhooks:manage"), s.handleDeleteWebhook)
+	webhooksGroup.Post("/:id/test", middleware.PermissionRequired(s.Db, "webhooks:manage"), s.handleTestWebhook)
+	webhooksGroup.Get("/:id/deliveries", middleware.PermissionRequired(s.Db, "webhooks:view"), s.handleListWebhookDeliveries)
+	webhooksGroup.Post("/:id/replay", middleware.PermissionRequired(s.Db, "webhooks:manage"), s.handleReplayWebhookDeliveries)
+
 	complianceGroup := api.Group("/compliance")
 	complianceGroup.Post("/report/:period", middleware.PermissionRequired(s.Db, "compliance:generate"), s.handleGenerateComplianceReport)
 	complianceGroup.Get("/reports", middleware.PermissionRequired(s.Db, "compliance:view"), s.handleListComplianceReports)
 	complianceGroup.Get("/report/:id", middleware.PermissionRequired(s.Db, "compliance:view"), s.handleGetComplianceReport)
+	complianceGroup.Get("/report/:id/export", middleware.PermissionRequired(s.Db, "compliance:view"), s.handleExportComplianceReport)
 
 	// Dashboard routes - HTML files served without auth (JS handles redirect)
 	s.App.Get("/", s.handleDashboard)
@@ -16,13 +16,16 @@ import (
 	"fmt"
 	"log"
 	"kaf-mirror/internal/ai"
+	"kaf-mirror/internal/alerting"
 	"kaf-mirror/internal/config"
 	"kaf-mirror/internal/database"
 	"kaf-mirror/internal/manager"
 	"kaf-mirror/internal/server/middleware"
 	"kaf-mirror/pkg/logger"
+	"sync"
 	"time"
 
+	"github.com/gofiber/contrib/websocket"
 	"github.com/gofiber/fiber/v2"
 	"github.com/jmoiron/sqlx"
 
@@ -39,6 +42,8 @@ type Server struct {
 	hub       *Hub
 	startTime time.Time
 	Version   string
+	done      chan struct{}
+	closeOnce sync.Once
 }
 
 // New creates a new server instance.
@@ -46,6 +51,9 @@ func New(cfg *config.Config, db *sqlx.DB, manager *manager.JobManager, hub *Hub,
 	app := fiber.New()
 
 	app.Use(middleware.Cors(cfg.Server.CORS.AllowedOrigins))
+	if cfg.Monitoring.Tracing.Enabled {
+		app.Use(middleware.Tracing())
+	}
 
 	var aiConfig config.AIConfig
 	if dbConfig, err := database.LoadConfig(db); err == nil {
@@ -63,9 +71,10 @@ func New(cfg *config.Config, db *sqlx.DB, manager *manager.JobManager, hub *Hub,
 		hub:       hub,
 		startTime: time.Now(),
 		Version:   version,
+		done:      make(chan struct{}),
 	}
 
-	go hub.Run()
+	s.publishEvents()
 	s.setupRoutes()
 
 	go func() {
@@ -109,5 +118,28 @@ func (s *Server) getWebPath() string {
 
 // Shutdown gracefully shuts down the server.
 func (s *Server) Shutdown() error {
+	// End long-lived streams first; the app waits for open requests.
+	s.closeOnce.Do(func() { close(s.done) })
+	s.hub.closeAll(websocket.CloseGoingAway, "server shutting down")
 	return s.App.Shutdown()
 }
+
+// publishEvents forwards operational events, alerts and AI insights to the
+// WebSocket hub. Job metrics are published by the job manager.
+func (s *Server) publishEvents() {
+	database.AddOperationalEventListener(func(event database.OperationalEvent) {
+		s.hub.Publish(ChannelEvents, "", event)
+	})
+	database.AddAIInsightListener(func(insight database.AIInsight) {
+		jobID := ""
+		if insight.JobID != nil {
+			jobID = *insight.JobID
+		}
+		s.hub.Publish(ChannelInsights, jobID, insight)
+	})
+	if s.manager.Alerts != nil {
+		s.manager.Alerts.AddListener(func(n alerting.Notification) {
+			s.hub.Publish(ChannelAlerts, n.Alert.JobID, n)
+		})
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kaf-mirror/internal/database"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type sloRequest struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Objective   *float64 `json:"objective"` // percentage of good 1-minute windows, e.g. 99.9
+	Threshold   *float64 `json:"threshold"` // seconds, messages, error percent or allowed gaps
+	Description *string  `json:"description"`
+	Enabled     *bool    `json:"enabled"`
+}
+
+// sloOverviewResponse summarizes the SLOs of every job.
+type sloOverviewResponse struct {
+	Total    int                  `json:"total"`
+	Met      int                  `json:"met"`
+	Breached int                  `json:"breached"`
+	SLOs     []database.SLOStatus `json:"slos"`
+}
+
+func sloIDParam(c *fiber.Ctx) (int, error) {
+	id, err := strconv.Atoi(c.Params("sloId"))
+	if err != nil || id <= 0 {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "Invalid SLO ID")
+	}
+	return id, nil
+}
+
+// handleListJobSLOs godoc
+// @Summary List the SLOs of a job
+// @Description List the SLOs of a job with their attainment and remaining error budget over rolling 7 and 30 day windows, and their current burn rates
+// @Tags slo
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {array} database.SLOStatus
+// @Router /jobs/{id}/slo [get]
+// @Security ApiKeyAuth
+func (s *Server) handleListJobSLOs(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	if _, err := database.GetJob(s.Db, jobID); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Job not found")
+	}
+	slos, err := database.ListJobSLOs(s.Db, jobID)
+	if err != nil {
+		log.Printf("Error listing SLOs for job %s: %v", jobID, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list SLOs")
+	}
+	statuses, err := database.EvaluateSLOs(s.Db, slos, time.Now())
+	if err != nil {
+		log.Printf("Error evaluating SLOs for job %s: %v", jobID, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to evaluate SLOs")
+	}
+	return c.JSON(statuses)
+}
+
+// handleCreateJobSLO godoc
+// @Summary Create an SLO for a job
+// @Description Create an SLO of type lag_seconds, message_lag or error_rate (objective is the percentage of 1-minute windows below threshold), or gaps (at most threshold unresolved gaps)
+// @Tags slo
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param slo body server.sloRequest true "SLO"
+// @Success 201 {object} database.JobSLO
+// @Router /jobs/{id}/slo [post]
+// @Security ApiKeyAuth
+func (s *Server) handleCreateJobSLO(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	if _, err := database.GetJob(s.Db, jobID); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Job not found")
+	}
+	var req sloRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	slo := database.JobSLO{JobID: jobID, Objective: 99.9, Enabled: true}
+	if err := applySLORequest(&slo, &req); err != nil {
+		return err
+	}
+	if err := database.CreateJobSLO(s.Db, &slo); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("An SLO named '%s' already exists for this job", slo.Name))
+		}
+		log.Printf("Error creating SLO for job %s: %v", jobID, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to create SLO")
+	}
+	return c.Status(fiber.StatusCreated).JSON(slo)
+}
+
+// handleUpdateJobSLO godoc
+// @Summary Update an SLO of a job
+// @Tags slo
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param sloId path int true "SLO ID"
+// @Param slo body server.sloRequest true "SLO"
+// @Success 200 {object} database.JobSLO
+// @Router /jobs/{id}/slo/{sloId} [put]
+// @Security ApiKeyAuth
+func (s *Server) handleUpdateJobSLO(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	id, err := sloIDParam(c)
+	if err != nil {
+		return err
+	}
+	slo, err := database.GetJobSLO(s.Db, jobID, id)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "SLO not found")
+	}
+	var req sloRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	if err := applySLORequest(slo, &req); err != nil {
+		return err
+	}
+	if err := database.UpdateJobSLO(s.Db, slo); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("An SLO named '%s' already exists for this job", slo.Name))
+		}
+		log.Printf("Error updating SLO %d of job %s: %v", id, jobID, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to update SLO")
+	}
+	return c.JSON(slo)
+}
+
+// handleDeleteJobSLO godoc
+// @Summary Delete an SLO of a job
+// @Tags slo
+// @Param id path string true "Job ID"
+// @Param sloId path int true "SLO ID"
+// @Success 204
+// @Router /jobs/{id}/slo/{sloId} [delete]
+// @Security ApiKeyAuth
+func (s *Server) handleDeleteJobSLO(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	id, err := sloIDParam(c)
+	if err != nil {
+		return err
+	}
+	if err := database.DeleteJobSLO(s.Db, jobID, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fiber.NewError(fiber.StatusNotFound, "SLO not found")
+		}
+		log.Printf("Error deleting SLO %d of job %s: %v", id, jobID, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete SLO")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// handleGetSLOOverview godoc
+// @Summary SLO overview
+// @Description Attainment of the SLOs of every job. An SLO counts as breached when it misses its objective over the 30 day window.
+// @Tags slo
+// @Produce json
+// @Success 200 {object} server.sloOverviewResponse
+// @Router /slo [get]
+// @Security ApiKeyAuth
+func (s *Server) handleGetSLOOverview(c *fiber.Ctx) error {
+	slos, err := database.ListJobSLOs(s.Db, "")
+	if err != nil {
+		log.Printf("Error listing SLOs: %v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list SLOs")
+	}
+	statuses, err := database.EvaluateSLOs(s.Db, slos, time.Now())
+	if err != nil {
+		log.Printf("Error evaluating SLOs: %v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to evaluate SLOs")
+	}
+	resp := sloOverviewResponse{SLOs: statuses}
+	for _, status := range statuses {
+		if !status.SLO.Enabled {
+			continue
+		}
+		resp.Total++
+		if status.Windows[len(status.Windows)-1].Met {
+			resp.Met++
+		} else {
+			resp.Breached++
+		}
+	}
+	return c.JSON(resp)
+}
+
+// applySLORequest merges the request into slo and validates the result.
+// Fields left empty in the request keep their current value.
+func applySLORequest(slo *database.JobSLO, req *sloRequest) error {
+	if name := strings.TrimSpace(req.Name); name != "" {
+		slo.Name = name
+	}
+	if req.Type != "" {
+		slo.SLOType = req.Type
+	}
+	if req.Objective != nil {
+		slo.Objective = *req.Objective
+	}
+	if req.Threshold != nil {
+		slo.Threshold = *req.Threshold
+	}
+	if req.Description != nil {
+		slo.Description = *req.Description
+	}
+	if req.Enabled != nil {
+		slo.Enabled = *req.Enabled
+	}
+	if err := slo.Validate(); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"kaf-mirror/internal/database"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const defaultNamedTokenLifetime = 90 * 24 * time.Hour
+
+type createTokenRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+	JobIDs      []string `json:"job_ids"`
+	AllowedIPs  []string `json:"allowed_ips"`
+	ExpiresIn   string   `json:"expires_in"` // Go duration, e.g. "720h"; defaults to 90 days
+}
+
+// tokenResponse is the API representation of an API token. The secret is never included.
+type tokenResponse struct {
+	ID          int        `json:"id"`
+	UserID      int        `json:"user_id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Permissions []string   `json:"permissions"`
+	JobIDs      []string   `json:"job_ids"`
+	AllowedIPs  []string   `json:"allowed_ips"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP  string     `json:"last_used_ip,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+func newTokenResponse(t *database.ApiToken) tokenResponse {
+	return tokenResponse{
+		ID:          t.ID,
+		UserID:      t.UserID,
+		Name:        t.Name,
+		Description: t.Description,
+		Permissions: t.PermissionList(),
+		JobIDs:      t.JobIDList(),
+		AllowedIPs:  t.AllowedIPList(),
+		ExpiresAt:   t.ExpiresAt,
+		LastUsedAt:  t.LastUsedAt,
+		LastUsedIP:  t.LastUsedIP,
+		CreatedAt:   t.CreatedAt,
+	}
+}
+
+// requireUnscopedToken prevents scoped automation tokens from managing tokens,
+// which would otherwise let them mint credentials broader than their own scope.
+func requireUnscopedToken(c *fiber.Ctx) error {
+	if apiToken, ok := c.Locals("token").(*database.ApiToken); ok && apiToken.IsScoped() {
+		return fiber.NewError(fiber.StatusForbidden, "Scoped tokens cannot manage API tokens")
+	}
+	return nil
+}
+
+// handleListTokens godoc
+// @Summary List API tokens
+// @Description List the current user's API tokens. Users with users:list may pass a username to list another user's tokens.
+// @Tags tokens
+// @Produce json
+// @Param username query string false "Username"
+// @Success 200 {array} server.tokenResponse
+// @Router /tokens [get]
+// @Security ApiKeyAuth
+func (s *Server) handleListTokens(c *fiber.Ctx) error {
+	if err := requireUnscopedToken(c); err != nil {
+		return err
+	}
+	user := c.Locals("user").(*database.User)
+
+	targetID := user.ID
+	if username := c.Query("username"); username != "" && username != user.Username {
+		allowed, err := database.UserHasPermission(s.Db, user.ID, "users:list")
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to check permissions")
+		}
+		if !allowed {
+			return fiber.NewError(fiber.StatusForbidden, "Forbidden")
+		}
+		target, err := database.GetUserByUsername(s.Db, username)
+		if err != nil {
+			return fiber.NewError(fiber.StatusNotFound, "User not found")
+		}
+		targetID = target.ID
+	}
+
+	tokens, err := database.ListApiTokens(s.Db, targetID)
+	if err != nil {
+		log.Printf("Error listing tokens: %v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list tokens")
+	}
+
+	response := make([]tokenResponse, 0, len(tokens))
+	for i := range tokens {
+		response = append(response, newTokenResponse(&tokens[i]))
+	}
+	return c.JSON(response)
+}
+
+// handleCreateToken godoc
+// @Summary Create a named API token
+// @Description Create a named API token limited to a subset of the current user's permissions, specific jobs and an IP allowlist. The token value is only returned once.
+// @Tags tokens
+// @Accept json
+// @Produce json
+// @Param token body server.createTokenRequest true "Token scope"
+// @Success 201 {object} map[string]interface{}
+// @Router /tokens [post]
+// @Security ApiKeyAuth
+func (s *Server) handleCreateToken(c *fiber.Ctx) error {
+	if err := requireUnscopedToken(c); err != nil {
+		return err
+	}
+	user := c.Locals("user").(*database.User)
+
+	var req createTokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "Token name is required")
+	}
+
+	existing, err := database.ListApiTokens(s.Db, user.ID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list tokens")
+	}
+	for _, t := range existing {
+		if t.Name == req.Name {
+			return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("A token named '%s' already exists", req.Name))
+		}
+	}
+
+	userPermissions, err := database.GetUserPermissions(s.Db, user.ID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get user permissions")
+	}
+	granted := make(map[string]bool, len(userPermissions))
+	for _, p := range userPermissions {
+		granted[p] = true
+	}
+	var missing []string
+	for _, p := range req.Permissions {
+		if !granted[strings.TrimSpace(p)] {
+			missing = append(missing, p)
+		}
+	}
+	if len(missing) > 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "Permissions not granted to your role: "+strings.Join(missing, ", "))
+	}
+
+	for _, jobID := range req.JobIDs {
+		if _, err := database.GetJob(s.Db, strings.TrimSpace(jobID)); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Job '%s' not found", jobID))
+		}
+	}
+
+	for _, entry := range req.AllowedIPs {
+		entry = strings.TrimSpace(entry)
+		if _, _, err := net.ParseCIDR(entry); err == nil {
+			continue
+		}
+		if net.ParseIP(entry) == nil {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Invalid IP address or CIDR '%s'", entry))
+		}
+	}
+
+	lifetime := defaultNamedTokenLifetime
+	if req.ExpiresIn != "" {
+		lifetime, err = time.ParseDuration(req.ExpiresIn)
+		if err != nil || lifetime <= 0 {
+			return fiber.NewError(fiber.StatusBadRequest, "expires_in must be a positive duration, e.g. 720h")
+		}
+	}
+
+	token, apiToken, err := database.CreateScopedApiToken(s.Db, user.ID, database.ApiTokenScope{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: req.Permissions,
+		JobIDs:      req.JobIDs,
+		AllowedIPs:  req.AllowedIPs,
+		ExpiresAt:   time.Now().Add(lifetime),
+	})
+	if err != nil {
+		log.Printf("Error creating token: %v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to create token")
+	}
+
+	s.recordSecurityEvent(database.EventTokenCreated, user.Username, fiber.Map{
+		"token_id":    apiToken.ID,
+		"name":        apiToken.Name,
+		"permissions": apiToken.PermissionList(),
+		"job_ids":     apiToken.JobIDList(),
+		"allowed_ips": apiToken.AllowedIPList(),
+		"expires_at":  apiToken.ExpiresAt,
+	})
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"token":     token,
+		"api_token": newTokenResponse(apiToken),
+	})
+}
+
+// handleRevokeToken godoc
+// @Summary Revoke an API token
+// @Description Revoke a single API token. Users may revoke their own tokens; users with users:delete may revoke any token.
+// @Tags tokens
+// @Param id path int true "Token ID"
+// @Success 204
+// @Router /tokens/{id} [delete]
+// @Security ApiKeyAuth
+func (s *Server) handleRevokeToken(c *fiber.Ctx) error {
+	if err := requireUnscopedToken(c); err != nil {
+		return err
+	}
+	user := c.Locals("user").(*database.User)
+
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid token ID")
+	}
+
+	apiToken, err := database.GetApiTokenByID(s.Db, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fiber.NewError(fiber.StatusNotFound, "Token not found")
+		}
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to get token")
+	}
+
+	if apiToken.UserID != user.ID {
+		allowed, err := database.UserHasPermission(s.Db, user.ID, "users:delete")
+		if err != nil {
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to check permissions")
+		}
+		if !allowed {
+			// Do not reveal the existence of other users' tokens.
+			return fiber.NewError(fiber.StatusNotFound, "Token not found")
+		}
+	}
+
+	if err := database.RevokeApiToken(s.Db, id); err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to revoke token")
+	}
+
+	s.recordSecurityEvent(database.EventTokenRevoked, user.Username, fiber.Map{
+		"token_id": apiToken.ID,
+		"name":     apiToken.Name,
+		"owner_id": apiToken.UserID,
+	})
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
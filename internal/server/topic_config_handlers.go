@@ -0,0 +1,125 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"kaf-mirror/internal/database"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type topicConfigOverridesRequest struct {
+	Overrides map[string]string `json:"overrides"`
+}
+
+// handleGetJobTopicConfigDrift godoc
+// @Summary Report topic config drift of a job
+// @Description Compare the configs of every target topic of a job with its source topic, after include/exclude filtering and overrides, without changing anything
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} kafka.TopicConfigReport
+// @Router /jobs/{id}/topic-configs/drift [get]
+// @Security ApiKeyAuth
+func (s *Server) handleGetJobTopicConfigDrift(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	if _, err := database.GetJob(s.Db, jobID); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Job not found")
+	}
+	report, err := s.manager.GetJobTopicConfigDrift(jobID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(report)
+}
+
+// handleSyncJobTopicConfigs godoc
+// @Summary Sync topic configs of a job
+// @Description Set the drifted configs of every target topic of a job to the desired values. With dry_run the target cluster only validates the changes.
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param dry_run query bool false "Validate the changes without applying them"
+// @Success 200 {object} kafka.TopicConfigReport
+// @Router /jobs/{id}/topic-configs/sync [post]
+// @Security ApiKeyAuth
+func (s *Server) handleSyncJobTopicConfigs(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	if _, err := database.GetJob(s.Db, jobID); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Job not found")
+	}
+	report, err := s.manager.SyncJobTopicConfigs(jobID, c.QueryBool("dry_run", false))
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	return c.JSON(report)
+}
+
+// handleGetJobTopicConfigOverrides godoc
+// @Summary List topic config overrides of a job
+// @Tags jobs
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {array} database.TopicConfigOverride
+// @Router /jobs/{id}/topic-configs/overrides [get]
+// @Security ApiKeyAuth
+func (s *Server) handleGetJobTopicConfigOverrides(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	if _, err := database.GetJob(s.Db, jobID); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Job not found")
+	}
+	overrides, err := database.ListTopicConfigOverrides(s.Db, jobID)
+	if err != nil {
+		log.Printf("Error listing topic config overrides for job %s: %v", jobID, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list topic config overrides")
+	}
+	return c.JSON(overrides)
+}
+
+// handleUpdateJobTopicConfigOverrides godoc
+// @Summary Replace topic config overrides of a job
+// @Description Replace the topic config overrides of a job. Overrides win over the values copied from the source topic and over the overrides in the configuration file. They are picked up on the next job start or sync.
+// @Tags jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param overrides body server.topicConfigOverridesRequest true "Overrides"
+// @Success 200 {array} database.TopicConfigOverride
+// @Router /jobs/{id}/topic-configs/overrides [put]
+// @Security ApiKeyAuth
+func (s *Server) handleUpdateJobTopicConfigOverrides(c *fiber.Ctx) error {
+	jobID := c.Params("id")
+	if _, err := database.GetJob(s.Db, jobID); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Job not found")
+	}
+	var req topicConfigOverridesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	for key := range req.Overrides {
+		if strings.TrimSpace(key) == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "Override keys must not be empty")
+		}
+	}
+	user := c.Locals("user").(*database.User)
+	if err := database.ReplaceTopicConfigOverrides(s.Db, jobID, req.Overrides, user.Username); err != nil {
+		log.Printf("Error updating topic config overrides for job %s: %v", jobID, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to update topic config overrides")
+	}
+	overrides, err := database.ListTopicConfigOverrides(s.Db, jobID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list topic config overrides")
+	}
+	return c.JSON(overrides)
+}
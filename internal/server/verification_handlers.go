@@ -0,0 +1,108 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"errors"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/manager"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// verifyRequest is the body of a content verification request.
+type verifyRequest struct {
+	// SampleRate is the share of each partition compared, above 0 and at most 1;
+	// omitted compares every record.
+	SampleRate float64 `json:"sample_rate"`
+}
+
+// handleVerifyContent godoc
+// @Summary Verify the mirrored records of a job
+// @Description Compare the records of the job's topics on the source and target clusters by hashing key, value, headers and timestamp, and report mismatched, missing and duplicate records per partition. Offsets are translated between the clusters. The comparison runs in the background; poll the returned verification.
+// @Tags mirror-state
+// @Accept json
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param request body verifyRequest false "Sample rate"
+// @Success 202 {object} database.ContentVerification
+// @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /jobs/{id}/mirror/verify [post]
+// @Security ApiKeyAuth
+func (s *Server) handleVerifyContent(c *fiber.Ctx) error {
+	// The verification runs after the request returns, when Fiber reuses the param buffer.
+	jobID := utils.CopyString(c.Params("id"))
+	if _, err := database.GetJob(s.Db, jobID); err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Job not found")
+	}
+	var req verifyRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+		}
+	}
+	if req.SampleRate < 0 || req.SampleRate > 1 {
+		return fiber.NewError(fiber.StatusBadRequest, "sample_rate must be greater than 0 and at most 1")
+	}
+
+	user := c.Locals("user").(*database.User)
+	v, err := s.manager.StartContentVerification(jobID, req.SampleRate, user.Username)
+	if errors.Is(err, manager.ErrVerificationInProgress) {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	return c.Status(fiber.StatusAccepted).JSON(v)
+}
+
+// handleListVerifications godoc
+// @Summary List the content verifications of a job
+// @Description List the content verifications of a job with their per-partition results, newest first.
+// @Tags mirror-state
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {array} database.ContentVerification
+// @Router /jobs/{id}/mirror/verifications [get]
+// @Security ApiKeyAuth
+func (s *Server) handleListVerifications(c *fiber.Ctx) error {
+	verifications, err := s.manager.ListContentVerifications(c.Params("id"))
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list content verifications")
+	}
+	return c.JSON(verifications)
+}
+
+// handleGetVerification godoc
+// @Summary Get a content verification of a job
+// @Description Get a content verification with its per-partition results.
+// @Tags mirror-state
+// @Produce json
+// @Param id path string true "Job ID"
+// @Param verification_id path int true "Verification ID"
+// @Success 200 {object} database.ContentVerification
+// @Failure 404 {object} map[string]interface{}
+// @Router /jobs/{id}/mirror/verifications/{verification_id} [get]
+// @Security ApiKeyAuth
+func (s *Server) handleGetVerification(c *fiber.Ctx) error {
+	verificationID, err := c.ParamsInt("verification_id")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid verification ID")
+	}
+	v, err := s.manager.GetContentVerification(c.Params("id"), verificationID)
+	if err != nil {
+		return fiber.NewError(fiber.StatusNotFound, "Content verification not found")
+	}
+	return c.JSON(v)
+}
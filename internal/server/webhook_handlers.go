@@ -0,0 +1,376 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/webhooks"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type webhookRequest struct {
+	Name         string   `json:"name"`
+	URL          string   `json:"url"`
+	Secret       string   `json:"secret"`        // generated when empty on create
+	RotateSecret bool     `json:"rotate_secret"` // generate a new secret on update
+	EventTypes   []string `json:"event_types"`   // empty subscribes to every event
+	JobID        *string  `json:"job_id"`        // empty matches every job
+	Enabled      *bool    `json:"enabled"`
+}
+
+// webhookResponse is the API representation of a webhook. The secret is only
+// returned when it is created or rotated.
+type webhookResponse struct {
+	database.Webhook
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret,omitempty"`
+}
+
+func newWebhookResponse(webhook *database.Webhook) webhookResponse {
+	eventTypes := webhook.EventTypeList()
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	return webhookResponse{Webhook: *webhook, EventTypes: eventTypes}
+}
+
+type webhookReplayResponse struct {
+	Replayed   int                        `json:"replayed"`
+	Deliveries []database.WebhookDelivery `json:"deliveries"`
+}
+
+func (s *Server) webhookDispatcher() (*webhooks.Dispatcher, error) {
+	if s.manager == nil || s.manager.Webhooks == nil {
+		return nil, fiber.NewError(fiber.StatusServiceUnavailable, "Webhook delivery is not available")
+	}
+	return s.manager.Webhooks, nil
+}
+
+func (s *Server) webhookParam(c *fiber.Ctx) (*database.Webhook, error) {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid ID")
+	}
+	webhook, err := database.GetWebhook(s.Db, id)
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Webhook not found")
+	}
+	return webhook, nil
+}
+
+// handleListWebhooks godoc
+// @Summary List webhooks
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} server.webhookResponse
+// @Router /webhooks [get]
+// @Security ApiKeyAuth
+func (s *Server) handleListWebhooks(c *fiber.Ctx) error {
+	list, err := database.ListWebhooks(s.Db)
+	if err != nil {
+		log.Printf("Error listing webhooks: %v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list webhooks")
+	}
+	response := make([]webhookResponse, 0, len(list))
+	for i := range list {
+		response = append(response, newWebhookResponse(&list[i]))
+	}
+	return c.JSON(response)
+}
+
+// handleListWebhookEventTypes godoc
+// @Summary List webhook event types
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} string
+// @Router /webhooks/events [get]
+// @Security ApiKeyAuth
+func (s *Server) handleListWebhookEventTypes(c *fiber.Ctx) error {
+	return c.JSON(database.WebhookEventTypes)
+}
+
+// handleGetWebhook godoc
+// @Summary Get a webhook
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} server.webhookResponse
+// @Router /webhooks/{id} [get]
+// @Security ApiKeyAuth
+func (s *Server) handleGetWebhook(c *fiber.Ctx) error {
+	webhook, err := s.webhookParam(c)
+	if err != nil {
+		return err
+	}
+	return c.JSON(newWebhookResponse(webhook))
+}
+
+// handleCreateWebhook godoc
+// @Summary Create a webhook
+// @Description Subscribe a URL to job.started, job.stopped, job.failed, job.gap_detected and ai.insight_created events. Payloads are signed with HMAC-SHA256; the secret is returned only in this response.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body server.webhookRequest true "Webhook"
+// @Success 201 {object} server.webhookResponse
+// @Router /webhooks [post]
+// @Security ApiKeyAuth
+func (s *Server) handleCreateWebhook(c *fiber.Ctx) error {
+	var req webhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	user := c.Locals("user").(*database.User)
+	webhook := database.Webhook{Enabled: true, CreatedBy: user.Username}
+	if req.Secret == "" {
+		req.RotateSecret = true
+	}
+	if err := s.applyWebhookRequest(&webhook, &req); err != nil {
+		return err
+	}
+	if err := database.CreateWebhook(s.Db, &webhook); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("A webhook named '%s' already exists", webhook.Name))
+		}
+		log.Printf("Error creating webhook: %v", err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to create webhook")
+	}
+	response := newWebhookResponse(&webhook)
+	response.Secret = webhook.Secret
+	return c.Status(fiber.StatusCreated).JSON(response)
+}
+
+// handleUpdateWebhook godoc
+// @Summary Update a webhook
+// @Description Fields left empty keep their value. Set rotate_secret to generate a new signing secret, which is returned once.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param webhook body server.webhookRequest true "Webhook"
+// @Success 200 {object} server.webhookResponse
+// @Router /webhooks/{id} [put]
+// @Security ApiKeyAuth
+func (s *Server) handleUpdateWebhook(c *fiber.Ctx) error {
+	webhook, err := s.webhookParam(c)
+	if err != nil {
+		return err
+	}
+	var req webhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+	oldSecret := webhook.Secret
+	if err := s.applyWebhookRequest(webhook, &req); err != nil {
+		return err
+	}
+	if err := database.UpdateWebhook(s.Db, webhook); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE") {
+			return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("A webhook named '%s' already exists", webhook.Name))
+		}
+		log.Printf("Error updating webhook %d: %v", webhook.ID, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to update webhook")
+	}
+	response := newWebhookResponse(webhook)
+	if webhook.Secret != oldSecret {
+		response.Secret = webhook.Secret
+	}
+	return c.JSON(response)
+}
+
+// applyWebhookRequest merges the request into webhook and validates the result.
+func (s *Server) applyWebhookRequest(webhook *database.Webhook, req *webhookRequest) error {
+	if name := strings.TrimSpace(req.Name); name != "" {
+		webhook.Name = name
+	}
+	if u := strings.TrimSpace(req.URL); u != "" {
+		webhook.URL = u
+	}
+	if req.EventTypes != nil {
+		webhook.EventTypes = strings.Join(req.EventTypes, ",")
+	}
+	if req.JobID != nil {
+		webhook.JobID = strings.TrimSpace(*req.JobID)
+	}
+	if req.Enabled != nil {
+		webhook.Enabled = *req.Enabled
+	}
+	switch {
+	case req.Secret != "":
+		webhook.Secret = req.Secret
+	case req.RotateSecret:
+		secret, err := webhooks.GenerateSecret()
+		if err != nil {
+			log.Printf("Error generating webhook secret: %v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, "Failed to generate webhook secret")
+		}
+		webhook.Secret = secret
+	}
+
+	if err := webhook.Validate(); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+	if webhook.JobID != "" {
+		if _, err := database.GetJob(s.Db, webhook.JobID); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Job '%s' not found", webhook.JobID))
+		}
+	}
+	return nil
+}
+
+// handleDeleteWebhook godoc
+// @Summary Delete a webhook
+// @Description Delete a webhook together with its delivery log.
+// @Tags webhooks
+// @Param id path int true "Webhook ID"
+// @Success 204
+// @Router /webhooks/{id} [delete]
+// @Security ApiKeyAuth
+func (s *Server) handleDeleteWebhook(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil || id <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid ID")
+	}
+	if err := database.DeleteWebhook(s.Db, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fiber.NewError(fiber.StatusNotFound, "Webhook not found")
+		}
+		log.Printf("Error deleting webhook %d: %v", id, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to delete webhook")
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// handleTestWebhook godoc
+// @Summary Send a test event
+// @Description Send a webhook.test event and return the delivery with the outcome of the first attempt.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} database.WebhookDelivery
+// @Router /webhooks/{id}/test [post]
+// @Security ApiKeyAuth
+func (s *Server) handleTestWebhook(c *fiber.Ctx) error {
+	webhook, err := s.webhookParam(c)
+	if err != nil {
+		return err
+	}
+	dispatcher, err := s.webhookDispatcher()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(c.UserContext(), 30*time.Second)
+	defer cancel()
+	delivery, err := dispatcher.Test(ctx, webhook.ID)
+	if err != nil {
+		log.Printf("Error testing webhook %d: %v", webhook.ID, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to send test event")
+	}
+	return c.JSON(delivery)
+}
+
+// handleListWebhookDeliveries godoc
+// @Summary List webhook deliveries
+// @Description The delivery log of a webhook, newest first.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param status query string false "pending, delivered or failed"
+// @Param limit query int false "Maximum number of deliveries (default 100)"
+// @Success 200 {array} database.WebhookDelivery
+// @Router /webhooks/{id}/deliveries [get]
+// @Security ApiKeyAuth
+func (s *Server) handleListWebhookDeliveries(c *fiber.Ctx) error {
+	webhook, err := s.webhookParam(c)
+	if err != nil {
+		return err
+	}
+	status := c.Query("status")
+	switch status {
+	case "", database.WebhookDeliveryPending, database.WebhookDeliveryDelivered, database.WebhookDeliveryFailed:
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, "status must be pending, delivered or failed")
+	}
+	deliveries, err := database.ListWebhookDeliveries(s.Db, webhook.ID, status, c.QueryInt("limit", 100))
+	if err != nil {
+		log.Printf("Error listing deliveries of webhook %d: %v", webhook.ID, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to list webhook deliveries")
+	}
+	return c.JSON(deliveries)
+}
+
+// handleReplayWebhookDeliveries godoc
+// @Summary Replay failed deliveries
+// @Description Queue a new delivery for every failed delivery of the webhook that was not replayed yet.
+// @Tags webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} server.webhookReplayResponse
+// @Router /webhooks/{id}/replay [post]
+// @Security ApiKeyAuth
+func (s *Server) handleReplayWebhookDeliveries(c *fiber.Ctx) error {
+	webhook, err := s.webhookParam(c)
+	if err != nil {
+		return err
+	}
+	dispatcher, err := s.webhookDispatcher()
+	if err != nil {
+		return err
+	}
+	replays, err := dispatcher.ReplayFailed(webhook.ID)
+	if err != nil {
+		log.Printf("Error replaying deliveries of webhook %d: %v", webhook.ID, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to replay webhook deliveries")
+	}
+	return c.JSON(webhookReplayResponse{Replayed: len(replays), Deliveries: replays})
+}
+
+// handleReplayWebhookDelivery godoc
+// @Summary Replay a delivery
+// @Description Queue a new delivery with the payload of a delivered or failed delivery.
+// @Tags webhooks
+// @Produce json
+// @Param deliveryId path int true "Delivery ID"
+// @Success 202 {object} database.WebhookDelivery
+// @Router /webhooks/deliveries/{deliveryId}/replay [post]
+// @Security ApiKeyAuth
+func (s *Server) handleReplayWebhookDelivery(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("deliveryId"))
+	if err != nil || id <= 0 {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid delivery ID")
+	}
+	dispatcher, err := s.webhookDispatcher()
+	if err != nil {
+		return err
+	}
+	replay, err := dispatcher.Replay(id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fiber.NewError(fiber.StatusNotFound, "Delivery not found")
+	}
+	if errors.Is(err, webhooks.ErrDeliveryPending) {
+		return fiber.NewError(fiber.StatusConflict, "Delivery is still pending")
+	}
+	if err != nil {
+		log.Printf("Error replaying webhook delivery %d: %v", id, err)
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to replay webhook delivery")
+	}
+	return c.Status(fiber.StatusAccepted).JSON(replay)
+}
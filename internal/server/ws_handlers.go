@@ -0,0 +1,260 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/joblogs"
+	"kaf-mirror/pkg/logger"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// wsRequest is a message sent by WebSocket clients:
+//
+//	{"action":"subscribe","channel":"job_metrics","job_ids":["orders"]}
+//	{"action":"subscribe","channel":"job_logs","job_id":"orders","level":"warn"}
+//	{"action":"unsubscribe","channel":"events"}
+//	{"action":"ping"}
+type wsRequest struct {
+	Action  string   `json:"action"`
+	Channel string   `json:"channel"`
+	JobID   string   `json:"job_id"`
+	JobIDs  []string `json:"job_ids"`
+	Level   string   `json:"level"` // minimum level, job_logs only
+}
+
+func (r wsRequest) jobIDs() []string {
+	ids := append([]string{}, r.JobIDs...)
+	if r.JobID != "" {
+		ids = append(ids, r.JobID)
+	}
+	return ids
+}
+
+// wsSession handles the requests of one client. Job log subscriptions are fed
+// from the manager's log buffer; every other channel is routed by the hub.
+type wsSession struct {
+	s      *Server
+	client *Client
+	logs   map[string]*joblogs.Subscription
+	wg     sync.WaitGroup
+}
+
+func newWSSession(s *Server, client *Client) *wsSession {
+	return &wsSession{s: s, client: client, logs: make(map[string]*joblogs.Subscription)}
+}
+
+// readLoop reads client requests until the connection fails or the client
+// stops answering pings.
+func (ws *wsSession) readLoop() {
+	conn := ws.client.conn
+	interval := ws.s.hub.PingInterval
+	if interval <= 0 {
+		interval = defaultPingInterval
+	}
+	readWait := 2 * interval
+	conn.SetReadLimit(maxClientMessageSize)
+	conn.SetReadDeadline(time.Now().Add(readWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(readWait))
+	})
+
+	ws.client.sendEvent(Event{Type: "welcome", Data: fiber.Map{
+		"schema_version": EventSchemaVersion,
+		"channels":       ws.allowedChannels(),
+	}})
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(readWait))
+		ws.handle(msg)
+	}
+}
+
+func (ws *wsSession) handle(msg []byte) {
+	var req wsRequest
+	if err := json.Unmarshal(msg, &req); err != nil {
+		ws.fail(req, "invalid message: expected a JSON object")
+		return
+	}
+	switch strings.ToLower(req.Action) {
+	case "ping":
+		ws.client.sendEvent(Event{Type: "pong"})
+	case "subscribe":
+		ws.subscribe(req)
+	case "unsubscribe":
+		ws.unsubscribe(req)
+	default:
+		ws.fail(req, "unknown action")
+	}
+}
+
+func (ws *wsSession) subscribe(req wsRequest) {
+	permission, ok := channelPermissions[req.Channel]
+	if !ok {
+		ws.fail(req, "unknown channel")
+		return
+	}
+	if err := ws.authorize(req.Channel, permission); err != nil {
+		ws.fail(req, err.Error())
+		return
+	}
+	jobIDs := req.jobIDs()
+	if token := ws.client.token; token != nil {
+		for _, id := range jobIDs {
+			if !token.AllowsJob(id) {
+				ws.fail(req, "token is not scoped to job "+id)
+				return
+			}
+		}
+	}
+
+	if req.Channel == ChannelJobLogs {
+		if len(jobIDs) == 0 {
+			ws.fail(req, "job_id is required")
+			return
+		}
+		if ws.s.manager.Logs == nil {
+			ws.fail(req, "job logs are not available")
+			return
+		}
+		minLevel := logger.DEBUG
+		if req.Level != "" {
+			level, err := logger.ParseLevel(req.Level)
+			if err != nil {
+				ws.fail(req, "invalid level")
+				return
+			}
+			minLevel = level
+		}
+		for _, id := range jobIDs {
+			ws.followJobLogs(id, minLevel)
+		}
+	} else {
+		ws.client.subscribe(req.Channel, jobIDs)
+	}
+	ws.client.sendEvent(Event{Type: "subscribed", Channel: req.Channel, Data: fiber.Map{"job_ids": jobIDs}})
+}
+
+func (ws *wsSession) unsubscribe(req wsRequest) {
+	if _, ok := channelPermissions[req.Channel]; !ok {
+		ws.fail(req, "unknown channel")
+		return
+	}
+	jobIDs := req.jobIDs()
+	if req.Channel == ChannelJobLogs {
+		if len(jobIDs) == 0 {
+			for id := range ws.logs {
+				jobIDs = append(jobIDs, id)
+			}
+		}
+		for _, id := range jobIDs {
+			if sub, ok := ws.logs[id]; ok {
+				sub.Close()
+				delete(ws.logs, id)
+			}
+		}
+	} else {
+		ws.client.unsubscribe(req.Channel)
+	}
+	ws.client.sendEvent(Event{Type: "unsubscribed", Channel: req.Channel, Data: fiber.Map{"job_ids": jobIDs}})
+}
+
+// followJobLogs forwards the job's new log records to the client, replacing
+// an earlier subscription to the same job.
+func (ws *wsSession) followJobLogs(jobID string, minLevel logger.Level) {
+	if old, ok := ws.logs[jobID]; ok {
+		old.Close()
+	}
+	sub := ws.s.manager.Logs.Subscribe(jobID, minLevel)
+	ws.logs[jobID] = sub
+
+	ws.wg.Add(1)
+	go func() {
+		defer ws.wg.Done()
+		for entry := range sub.C {
+			ws.client.sendEvent(Event{Type: channelEventTypes[ChannelJobLogs], Channel: ChannelJobLogs, JobID: jobID, Time: entry.Time.UTC(), Data: entry})
+		}
+	}()
+}
+
+// authorize checks the user's role and, for scoped API tokens, the token's
+// permissions. Denials are recorded like denied API requests.
+func (ws *wsSession) authorize(channel, permission string) error {
+	user := ws.client.user
+	if user == nil {
+		return fmt.Errorf("not authenticated")
+	}
+	allowed, err := database.UserHasPermission(ws.s.Db, user.ID, permission)
+	if err != nil {
+		return fmt.Errorf("failed to check permissions")
+	}
+	reason := ""
+	details := fiber.Map{"permission": permission, "channel": channel, "transport": "websocket"}
+	if !allowed {
+		reason = "role does not grant permission"
+	} else if token := ws.client.token; token != nil && !token.AllowsPermission(permission) {
+		reason = "token scope does not include permission"
+		details["token_id"] = token.ID
+	}
+	if reason == "" {
+		return nil
+	}
+	details["reason"] = reason
+	if err := database.RecordSecurityEvent(ws.s.Db, database.EventPermissionDenied, user.Username, details); err != nil {
+		logger.Warn("Failed to record permission denial: %v", err)
+	}
+	return fmt.Errorf("permission denied: %s requires %s", channel, permission)
+}
+
+// allowedChannels lists the channels the client may subscribe to.
+func (ws *wsSession) allowedChannels() []string {
+	channels := []string{}
+	user := ws.client.user
+	if user == nil {
+		return channels
+	}
+	for channel, permission := range channelPermissions {
+		allowed, err := database.UserHasPermission(ws.s.Db, user.ID, permission)
+		if err != nil || !allowed {
+			continue
+		}
+		if token := ws.client.token; token != nil && !token.AllowsPermission(permission) {
+			continue
+		}
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+func (ws *wsSession) fail(req wsRequest, message string) {
+	ws.client.sendEvent(Event{Type: "error", Channel: req.Channel, JobID: req.JobID, Error: message})
+}
+
+// close ends the job log subscriptions and waits for their forwarders.
+func (ws *wsSession) close() {
+	for _, sub := range ws.logs {
+		sub.Close()
+	}
+	ws.wg.Wait()
+}
@@ -0,0 +1,457 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webhooks delivers job lifecycle and incident events to the webhooks
+// registered through the API.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/pkg/logger"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Headers set on every delivery. The signature is an HMAC-SHA256 over
+// "<timestamp>.<body>" keyed with the webhook secret.
+const (
+	HeaderEvent     = "X-Kaf-Mirror-Event"
+	HeaderDelivery  = "X-Kaf-Mirror-Delivery"
+	HeaderTimestamp = "X-Kaf-Mirror-Timestamp"
+	HeaderSignature = "X-Kaf-Mirror-Signature"
+)
+
+const (
+	queueSize = 1024
+	// batchSize bounds how many due deliveries are loaded at once.
+	batchSize = 50
+	// idlePoll is the longest the dispatcher sleeps without checking for due deliveries.
+	idlePoll = time.Minute
+)
+
+// ErrDeliveryPending is returned when replaying a delivery that is still being retried.
+var ErrDeliveryPending = errors.New("delivery is still pending")
+
+// Payload is the JSON body posted to a webhook. Replays resend the same payload,
+// so receivers can deduplicate on EventID.
+type Payload struct {
+	EventID    string      `json:"event_id"`
+	Event      string      `json:"event"`
+	JobID      string      `json:"job_id,omitempty"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// Sign returns the signature header value for a delivery body.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// GenerateSecret returns a random signing secret for a new webhook.
+func GenerateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Dispatcher records every emitted event as a delivery for each matching webhook
+// and sends the deliveries, retrying failures with exponential backoff. Deliveries
+// are stored in the database, so retries survive restarts and failed deliveries
+// can be replayed.
+type Dispatcher struct {
+	db             *sqlx.DB
+	client         *http.Client
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+
+	queue   chan Payload
+	wake    chan struct{}
+	dropped atomic.Int64
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// NewDispatcher starts a dispatcher using the retry settings in cfg.
+func NewDispatcher(db *sqlx.DB, cfg config.WebhooksConfig) *Dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Dispatcher{
+		db:             db,
+		client:         &http.Client{Timeout: parseDuration(cfg.Timeout, 10*time.Second)},
+		maxAttempts:    cfg.MaxAttempts,
+		initialBackoff: parseDuration(cfg.InitialBackoff, 30*time.Second),
+		maxBackoff:     parseDuration(cfg.MaxBackoff, 30*time.Minute),
+		queue:          make(chan Payload, queueSize),
+		wake:           make(chan struct{}, 1),
+		ctx:            ctx,
+		cancel:         cancel,
+	}
+	if d.maxAttempts <= 0 {
+		d.maxAttempts = 6
+	}
+	if d.maxBackoff < d.initialBackoff {
+		d.maxBackoff = d.initialBackoff
+	}
+	d.wg.Add(1)
+	go d.run()
+	return d
+}
+
+func parseDuration(value string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+// Emit queues an event for every webhook subscribed to eventType without blocking.
+// Events emitted while the queue is full or after Close are dropped.
+func (d *Dispatcher) Emit(eventType, jobID string, data interface{}) {
+	if d == nil || d.ctx.Err() != nil {
+		return
+	}
+	payload := Payload{
+		EventID:    newEventID(),
+		Event:      eventType,
+		JobID:      jobID,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+	select {
+	case d.queue <- payload:
+	default:
+		if d.dropped.Add(1)%100 == 1 {
+			logger.Warn("Webhook event queue full, dropped %d events so far", d.dropped.Load())
+		}
+	}
+}
+
+// Dropped returns the number of events dropped because the queue was full.
+func (d *Dispatcher) Dropped() int64 {
+	return d.dropped.Load()
+}
+
+// Close stops the dispatcher. Queued events are recorded as pending deliveries
+// and sent after the next start.
+func (d *Dispatcher) Close() {
+	d.closeOnce.Do(func() {
+		d.cancel()
+		d.wg.Wait()
+	})
+}
+
+func (d *Dispatcher) run() {
+	defer d.wg.Done()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case payload := <-d.queue:
+			d.record(payload)
+		case <-d.wake:
+		case <-timer.C:
+		case <-d.ctx.Done():
+			for {
+				select {
+				case payload := <-d.queue:
+					d.record(payload)
+				default:
+					return
+				}
+			}
+		}
+
+		d.deliverDue()
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		timer.Reset(d.untilNextDue())
+	}
+}
+
+// signal wakes the dispatcher to look for due deliveries.
+func (d *Dispatcher) signal() {
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (d *Dispatcher) untilNextDue() time.Duration {
+	next, err := database.NextWebhookDeliveryAt(d.db)
+	if err != nil {
+		logger.Error("Failed to load next webhook delivery: %v", err)
+		return idlePoll
+	}
+	if next == nil {
+		return idlePoll
+	}
+	wait := time.Until(*next)
+	if wait < 0 {
+		return 0
+	}
+	if wait > idlePoll {
+		return idlePoll
+	}
+	return wait
+}
+
+// record stores a pending delivery of the payload for every matching webhook.
+func (d *Dispatcher) record(payload Payload) {
+	webhooks, err := database.ListWebhooks(d.db)
+	if err != nil {
+		logger.Error("Failed to load webhooks for %s event: %v", payload.Event, err)
+		return
+	}
+	var body []byte
+	for _, webhook := range webhooks {
+		if !webhook.Matches(payload.Event, payload.JobID) {
+			continue
+		}
+		if body == nil {
+			if body, err = json.Marshal(payload); err != nil {
+				logger.Error("Failed to encode %s webhook payload: %v", payload.Event, err)
+				return
+			}
+		}
+		delivery := &database.WebhookDelivery{
+			WebhookID: webhook.ID,
+			EventID:   payload.EventID,
+			EventType: payload.Event,
+			JobID:     payload.JobID,
+			Payload:   string(body),
+		}
+		if err := database.CreateWebhookDelivery(d.db, delivery); err != nil {
+			logger.Error("Failed to record %s delivery for webhook %s: %v", payload.Event, webhook.Name, err)
+		}
+	}
+}
+
+func (d *Dispatcher) deliverDue() {
+	for d.ctx.Err() == nil {
+		deliveries, err := database.DueWebhookDeliveries(d.db, time.Now(), batchSize)
+		if err != nil {
+			logger.Error("Failed to load due webhook deliveries: %v", err)
+			return
+		}
+		for i := range deliveries {
+			if d.ctx.Err() != nil {
+				return
+			}
+			d.attempt(d.ctx, &deliveries[i])
+		}
+		if len(deliveries) < batchSize {
+			return
+		}
+	}
+}
+
+// attempt sends a delivery once and records the outcome. Failed attempts are
+// rescheduled until the attempt limit is reached.
+func (d *Dispatcher) attempt(ctx context.Context, delivery *database.WebhookDelivery) {
+	webhook, err := database.GetWebhook(d.db, delivery.WebhookID)
+	var status int
+	switch {
+	case err != nil:
+		err = fmt.Errorf("webhook %d not found", delivery.WebhookID)
+	case !webhook.Enabled:
+		err = fmt.Errorf("webhook is disabled")
+	default:
+		status, err = d.send(ctx, webhook, delivery)
+		if ctx.Err() != nil {
+			// Shutting down; the delivery stays pending and is retried on the next start.
+			return
+		}
+	}
+
+	now := time.Now().UTC()
+	delivery.Attempts++
+	delivery.ResponseStatus = nil
+	if status != 0 {
+		delivery.ResponseStatus = &status
+	}
+	if err == nil {
+		delivery.Status = database.WebhookDeliveryDelivered
+		delivery.LastError = nil
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+	} else {
+		msg := err.Error()
+		delivery.LastError = &msg
+		if delivery.Attempts >= d.maxAttempts || webhook == nil || !webhook.Enabled {
+			delivery.Status = database.WebhookDeliveryFailed
+			delivery.NextAttemptAt = nil
+		} else {
+			next := now.Add(d.backoff(delivery.Attempts))
+			delivery.NextAttemptAt = &next
+		}
+	}
+	if err := database.UpdateWebhookDeliveryAttempt(d.db, delivery); err != nil {
+		logger.Error("Failed to record webhook delivery %d: %v", delivery.ID, err)
+	}
+	if delivery.Status == database.WebhookDeliveryFailed {
+		logger.Warn("Webhook delivery %d (%s) failed after %d attempts: %s", delivery.ID, delivery.EventType, delivery.Attempts, *delivery.LastError)
+	}
+}
+
+// backoff returns the wait after the given number of failed attempts.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	wait := d.initialBackoff
+	for i := 1; i < attempts && wait < d.maxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > d.maxBackoff {
+		wait = d.maxBackoff
+	}
+	return wait
+}
+
+func (d *Dispatcher) send(ctx context.Context, webhook *database.Webhook, delivery *database.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "kaf-mirror-webhooks")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, strconv.Itoa(delivery.ID))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return resp.StatusCode, fmt.Errorf("receiver returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	return resp.StatusCode, nil
+}
+
+// Test sends a webhook.test event to a webhook and waits for the first attempt.
+// A failed test delivery is retried like any other delivery.
+func (d *Dispatcher) Test(ctx context.Context, webhookID int) (*database.WebhookDelivery, error) {
+	webhook, err := database.GetWebhook(d.db, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	payload := Payload{
+		EventID:    newEventID(),
+		Event:      database.WebhookEventTest,
+		OccurredAt: time.Now().UTC(),
+		Data:       map[string]string{"webhook": webhook.Name, "message": "Test event from kaf-mirror"},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	// Schedule the retry up front so the background worker does not send it concurrently.
+	retryAt := time.Now().Add(d.initialBackoff)
+	delivery := &database.WebhookDelivery{
+		WebhookID:     webhook.ID,
+		EventID:       payload.EventID,
+		EventType:     payload.Event,
+		Payload:       string(body),
+		NextAttemptAt: &retryAt,
+	}
+	if err := database.CreateWebhookDelivery(d.db, delivery); err != nil {
+		return nil, err
+	}
+	d.attempt(ctx, delivery)
+	return database.GetWebhookDelivery(d.db, delivery.ID)
+}
+
+// Replay queues a new delivery with the payload of an earlier one.
+func (d *Dispatcher) Replay(deliveryID int) (*database.WebhookDelivery, error) {
+	original, err := database.GetWebhookDelivery(d.db, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if original.Status == database.WebhookDeliveryPending {
+		return nil, ErrDeliveryPending
+	}
+	replay := &database.WebhookDelivery{
+		WebhookID: original.WebhookID,
+		EventID:   original.EventID,
+		EventType: original.EventType,
+		JobID:     original.JobID,
+		Payload:   original.Payload,
+		ReplayOf:  &original.ID,
+	}
+	if err := database.CreateWebhookDelivery(d.db, replay); err != nil {
+		return nil, err
+	}
+	d.signal()
+	return replay, nil
+}
+
+// ReplayFailed replays every failed delivery of a webhook that was not replayed
+// before and returns the new deliveries.
+func (d *Dispatcher) ReplayFailed(webhookID int) ([]database.WebhookDelivery, error) {
+	failed, err := database.UnreplayedFailedWebhookDeliveries(d.db, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	replays := make([]database.WebhookDelivery, 0, len(failed))
+	for _, delivery := range failed {
+		replay, err := d.Replay(delivery.ID)
+		if err != nil {
+			return replays, err
+		}
+		replays = append(replays, *replay)
+	}
+	return replays, nil
+}
+
+func newEventID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Record is a written log record as passed to listeners.
+type Record struct {
+	Time          time.Time `json:"time"`
+	Level         string    `json:"level"`
+	Message       string    `json:"msg"`
+	Caller        string    `json:"caller"`
+	JobID         string    `json:"job_id,omitempty"`
+	Component     string    `json:"component,omitempty"`
+	Cluster       string    `json:"cluster,omitempty"`
+	Topic         string    `json:"topic,omitempty"`
+	Partition     *int32    `json:"partition,omitempty"`
+	AICategory    string    `json:"ai_category,omitempty"`
+	AISubcategory string    `json:"ai_subcategory,omitempty"`
+}
+
+var (
+	listenersMu   sync.RWMutex
+	listeners     = map[int]func(Record){}
+	nextListener  int
+	listenerCount atomic.Int32
+)
+
+// AddListener registers fn to receive every record that passes the level filter.
+// fn runs on the logging goroutine and must not block or log. The returned
+// function removes the listener.
+func AddListener(fn func(Record)) (remove func()) {
+	listenersMu.Lock()
+	id := nextListener
+	nextListener++
+	listeners[id] = fn
+	listenerCount.Store(int32(len(listeners)))
+	listenersMu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			listenersMu.Lock()
+			delete(listeners, id)
+			listenerCount.Store(int32(len(listeners)))
+			listenersMu.Unlock()
+		})
+	}
+}
+
+func hasListeners() bool {
+	return listenerCount.Load() > 0
+}
+
+func notifyListeners(rec Record) {
+	listenersMu.RLock()
+	defer listenersMu.RUnlock()
+	for _, fn := range listeners {
+		fn(rec)
+	}
+}
+
+func newRecord(at time.Time, level Level, caller, message string, fields []Field) Record {
+	rec := Record{Time: at, Level: levelNames[level], Message: message, Caller: caller}
+	for _, f := range fields {
+		switch f.Key {
+		case "job_id":
+			rec.JobID = f.Value.String()
+		case "component":
+			rec.Component = f.Value.String()
+		case "cluster":
+			rec.Cluster = f.Value.String()
+		case "topic":
+			rec.Topic = f.Value.String()
+		case "partition":
+			p := int32(f.Value.Int64())
+			rec.Partition = &p
+		case "ai_category":
+			rec.AICategory = f.Value.String()
+		case "ai_subcategory":
+			rec.AISubcategory = f.Value.String()
+		}
+	}
+	return rec
+}
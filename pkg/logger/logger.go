@@ -13,9 +13,11 @@
 package logger
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -42,6 +44,12 @@ var levelNames = map[Level]string{
 	FATAL: "FATAL",
 }
 
+// Log formats. Text is the default; JSON writes one object per line for log shippers.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
 type Logger struct {
 	mu          sync.Mutex
 	debugLogger *log.Logger
@@ -50,6 +58,7 @@ type Logger struct {
 	errorLogger *log.Logger
 	fatalLogger *log.Logger
 	level       Level
+	format      string
 	file        *os.File
 }
 
@@ -61,6 +70,7 @@ func init() {
 	// The main application will re-initialize this with the proper configuration.
 	defaultLogger = &Logger{
 		level:       INFO,
+		format:      FormatText,
 		debugLogger: log.New(io.Discard, "", 0),
 		infoLogger:  log.New(os.Stdout, "", 0),
 		warnLogger:  log.New(os.Stdout, "", 0),
@@ -69,103 +79,240 @@ func init() {
 	}
 }
 
-func (l *Logger) log(level Level, format string, args ...interface{}) {
+// Field is a structured attribute attached to a log record, e.g. JobID("job-1").
+type Field = slog.Attr
+
+// JobID tags a record with the replication job it belongs to.
+func JobID(id string) Field { return slog.String("job_id", id) }
+
+// Component tags a record with the subsystem that wrote it, e.g. "consumer".
+func Component(name string) Field { return slog.String("component", name) }
+
+// Cluster tags a record with a Kafka cluster name.
+func Cluster(name string) Field { return slog.String("cluster", name) }
+
+// Topic tags a record with a Kafka topic.
+func Topic(name string) Field { return slog.String("topic", name) }
+
+// Partition tags a record with a Kafka partition.
+func Partition(partition int32) Field { return slog.Int("partition", int(partition)) }
+
+// textTags maps field keys to the tags of the text format. The order is the
+// order tags appear in a text line.
+var textTags = []struct{ key, tag string }{
+	{"job_id", "job"},
+	{"component", "component"},
+	{"cluster", "cluster"},
+	{"topic", "topic"},
+	{"partition", "partition"},
+}
+
+var slogLevels = map[Level]slog.Level{
+	DEBUG: slog.LevelDebug,
+	INFO:  slog.LevelInfo,
+	WARN:  slog.LevelWarn,
+	ERROR: slog.LevelError,
+	FATAL: slog.LevelError + 4,
+}
+
+// jsonOptions renders records as {"time","level","caller","msg",...fields}.
+var jsonOptions = &slog.HandlerOptions{
+	AddSource: true,
+	Level:     slog.LevelDebug,
+	ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+		if len(groups) > 0 {
+			return a
+		}
+		switch a.Key {
+		case slog.LevelKey:
+			level := a.Value.Any().(slog.Level)
+			for l, sl := range slogLevels {
+				if sl == level {
+					return slog.String(slog.LevelKey, levelNames[l])
+				}
+			}
+		case slog.SourceKey:
+			if src, ok := a.Value.Any().(*slog.Source); ok {
+				return slog.String("caller", fmt.Sprintf("%s:%d", filepath.Base(src.File), src.Line))
+			}
+		}
+		return a
+	},
+}
+
+// emit writes one record. depth is the number of frames between the caller
+// that should be reported and emit.
+func (l *Logger) emit(depth int, level Level, fields []Field, format string, args ...interface{}) {
 	if level < l.level {
 		return
 	}
-
-	// Get caller info
-	_, file, line, ok := runtime.Caller(2)
-	var caller string
-	if ok {
-		caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	now := time.Now()
+	message := fmt.Sprintf(format, args...)
+	out := l.output(level)
+
+	if l.format == FormatJSON {
+		var pcs [1]uintptr
+		runtime.Callers(depth+2, pcs[:])
+		record := slog.NewRecord(now, slogLevels[level], message, pcs[0])
+		record.AddAttrs(fields...)
+		slog.NewJSONHandler(out.Writer(), jsonOptions).Handle(context.Background(), record)
+		if hasListeners() {
+			notifyListeners(newRecord(now, level, callerOf(depth+1), message, fields))
+		}
 	} else {
-		caller = "unknown"
+		caller := callerOf(depth + 1)
+		timestamp := now.Format("2006-01-02 15:04:05.000")
+		out.Println(fmt.Sprintf("[%s] %-5s [%s]%s %s", timestamp, levelNames[level], caller, textFields(fields), message))
+		if hasListeners() {
+			notifyListeners(newRecord(now, level, caller, message, fields))
+		}
 	}
 
-	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
-	levelName := levelNames[level]
-	message := fmt.Sprintf(format, args...)
-	
-	logLine := fmt.Sprintf("[%s] %-5s [%s] %s", timestamp, levelName, caller, message)
-
-	switch level {
-	case DEBUG:
-		l.debugLogger.Println(logLine)
-	case INFO:
-		l.infoLogger.Println(logLine)
-	case WARN:
-		l.warnLogger.Println(logLine)
-	case ERROR:
-		l.errorLogger.Println(logLine)
-	case FATAL:
-		l.fatalLogger.Println(logLine)
+	if level == FATAL {
 		os.Exit(1)
 	}
 }
 
-// logWithTag logs a message with AI analysis tags
-func (l *Logger) logWithTag(level Level, category, subcategory, jobID string, format string, args ...interface{}) {
-	if level < l.level {
-		return
+// callerOf returns file:line of the frame skip levels above its caller.
+func callerOf(skip int) string {
+	_, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return "unknown"
 	}
+	return fmt.Sprintf("%s:%d", filepath.Base(file), line)
+}
 
-	// Get caller info
-	_, file, line, ok := runtime.Caller(2)
-	var caller string
-	if ok {
-		caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
-	} else {
-		caller = "unknown"
+// textFields renders fields as tags: [AI:category:subcategory] [job:id] [component:name] ...
+func textFields(fields []Field) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	values := make(map[string]string, len(fields))
+	var extra []Field
+	for _, f := range fields {
+		switch f.Key {
+		case "ai_category", "ai_subcategory", "job_id", "component", "cluster", "topic", "partition":
+			values[f.Key] = f.Value.String()
+		default:
+			extra = append(extra, f)
+		}
 	}
 
-	timestamp := time.Now().Format("2006-01-02 15:04:05.000")
-	levelName := levelNames[level]
-	message := fmt.Sprintf(format, args...)
-	
-	// Format: [timestamp] LEVEL [caller] [AI:category:subcategory] [job:jobID] message
-	var logLine string
-	if jobID != "" {
-		logLine = fmt.Sprintf("[%s] %-5s [%s] [AI:%s:%s] [job:%s] %s", 
-			timestamp, levelName, caller, category, subcategory, jobID, message)
-	} else {
-		logLine = fmt.Sprintf("[%s] %-5s [%s] [AI:%s:%s] %s", 
-			timestamp, levelName, caller, category, subcategory, message)
+	var b strings.Builder
+	if category, ok := values["ai_category"]; ok {
+		fmt.Fprintf(&b, " [AI:%s:%s]", category, values["ai_subcategory"])
+	}
+	for _, t := range textTags {
+		if v, ok := values[t.key]; ok && v != "" {
+			fmt.Fprintf(&b, " [%s:%s]", t.tag, v)
+		}
+	}
+	for _, f := range extra {
+		fmt.Fprintf(&b, " [%s:%s]", f.Key, f.Value.String())
 	}
+	return b.String()
+}
 
+func (l *Logger) output(level Level) *log.Logger {
 	switch level {
 	case DEBUG:
-		l.debugLogger.Println(logLine)
+		return l.debugLogger
 	case INFO:
-		l.infoLogger.Println(logLine)
+		return l.infoLogger
 	case WARN:
-		l.warnLogger.Println(logLine)
+		return l.warnLogger
 	case ERROR:
-		l.errorLogger.Println(logLine)
-	case FATAL:
-		l.fatalLogger.Println(logLine)
-		os.Exit(1)
+		return l.errorLogger
+	default:
+		return l.fatalLogger
+	}
+}
+
+// aiFields tags a record for AI analysis. AI-tagged records historically carry
+// the job ID as a positional argument; an empty one is omitted.
+func aiFields(category, subcategory, jobID string) []Field {
+	fields := []Field{slog.String("ai_category", category), slog.String("ai_subcategory", subcategory)}
+	if jobID != "" {
+		fields = append(fields, JobID(jobID))
+	}
+	return fields
+}
+
+// Entry is a logger bound to contextual fields.
+type Entry struct {
+	logger *Logger
+	fields []Field
+}
+
+// With returns an entry whose records carry the given fields.
+func (l *Logger) With(fields ...Field) *Entry {
+	return &Entry{logger: l, fields: fields}
+}
+
+// With returns an entry with additional fields.
+func (e *Entry) With(fields ...Field) *Entry {
+	merged := make([]Field, 0, len(e.fields)+len(fields))
+	merged = append(merged, e.fields...)
+	merged = append(merged, fields...)
+	return &Entry{logger: e.logger, fields: merged}
+}
+
+func (e *Entry) Debug(format string, args ...interface{}) {
+	e.logger.emit(1, DEBUG, e.fields, format, args...)
+}
+
+func (e *Entry) Info(format string, args ...interface{}) {
+	e.logger.emit(1, INFO, e.fields, format, args...)
+}
+
+func (e *Entry) Warn(format string, args ...interface{}) {
+	e.logger.emit(1, WARN, e.fields, format, args...)
+}
+
+func (e *Entry) Error(format string, args ...interface{}) {
+	e.logger.emit(1, ERROR, e.fields, format, args...)
+}
+
+// AI writes a record tagged for AI analysis with the entry's fields.
+func (e *Entry) AI(level Level, category, subcategory string, format string, args ...interface{}) {
+	e.logger.emit(1, level, append(aiFields(category, subcategory, ""), e.fields...), format, args...)
+}
+
+// New creates a logger writing every level to w in the given format.
+func New(w io.Writer, level Level, format string) (*Logger, error) {
+	format, err := ParseFormat(format)
+	if err != nil {
+		return nil, err
 	}
+	return &Logger{
+		level:       level,
+		format:      format,
+		debugLogger: log.New(w, "", 0),
+		infoLogger:  log.New(w, "", 0),
+		warnLogger:  log.New(w, "", 0),
+		errorLogger: log.New(w, "", 0),
+		fatalLogger: log.New(w, "", 0),
+	}, nil
 }
 
 func (l *Logger) Debug(format string, args ...interface{}) {
-	l.log(DEBUG, format, args...)
+	l.emit(1, DEBUG, nil, format, args...)
 }
 
 func (l *Logger) Info(format string, args ...interface{}) {
-	l.log(INFO, format, args...)
+	l.emit(1, INFO, nil, format, args...)
 }
 
 func (l *Logger) Warn(format string, args ...interface{}) {
-	l.log(WARN, format, args...)
+	l.emit(1, WARN, nil, format, args...)
 }
 
 func (l *Logger) Error(format string, args ...interface{}) {
-	l.log(ERROR, format, args...)
+	l.emit(1, ERROR, nil, format, args...)
 }
 
 func (l *Logger) Fatal(format string, args ...interface{}) {
-	l.log(FATAL, format, args...)
+	l.emit(1, FATAL, nil, format, args...)
 }
 
 func (l *Logger) Close() error {
@@ -177,44 +324,55 @@ func (l *Logger) Close() error {
 
 // Package-level functions using default logger
 func Debug(format string, args ...interface{}) {
-	defaultLogger.Debug(format, args...)
+	defaultLogger.emit(1, DEBUG, nil, format, args...)
 }
 
 func Info(format string, args ...interface{}) {
-	defaultLogger.Info(format, args...)
+	defaultLogger.emit(1, INFO, nil, format, args...)
 }
 
 func Warn(format string, args ...interface{}) {
-	defaultLogger.Warn(format, args...)
+	defaultLogger.emit(1, WARN, nil, format, args...)
 }
 
 func Error(format string, args ...interface{}) {
-	defaultLogger.Error(format, args...)
+	defaultLogger.emit(1, ERROR, nil, format, args...)
 }
 
 func Fatal(format string, args ...interface{}) {
-	defaultLogger.Fatal(format, args...)
+	defaultLogger.emit(1, FATAL, nil, format, args...)
 }
 
 func Close() error {
 	return defaultLogger.Close()
 }
 
+// With returns an entry of the default logger whose records carry the given fields.
+func With(fields ...Field) *Entry {
+	return defaultLogger.With(fields...)
+}
+
 // AI-tagged logging functions for operational intelligence
 func InfoAI(category, subcategory, jobID, format string, args ...interface{}) {
-	defaultLogger.logWithTag(INFO, category, subcategory, jobID, format, args...)
+	defaultLogger.emit(1, INFO, aiFields(category, subcategory, jobID), format, args...)
 }
 
 func WarnAI(category, subcategory, jobID, format string, args ...interface{}) {
-	defaultLogger.logWithTag(WARN, category, subcategory, jobID, format, args...)
+	defaultLogger.emit(1, WARN, aiFields(category, subcategory, jobID), format, args...)
 }
 
 func ErrorAI(category, subcategory, jobID, format string, args ...interface{}) {
-	defaultLogger.logWithTag(ERROR, category, subcategory, jobID, format, args...)
+	defaultLogger.emit(1, ERROR, aiFields(category, subcategory, jobID), format, args...)
 }
 
 func DebugAI(category, subcategory, jobID, format string, args ...interface{}) {
-	defaultLogger.logWithTag(DEBUG, category, subcategory, jobID, format, args...)
+	defaultLogger.emit(1, DEBUG, aiFields(category, subcategory, jobID), format, args...)
+}
+
+// Enabled reports whether the default logger writes records of the given level,
+// so hot paths can skip building fields for records that would be discarded.
+func Enabled(level Level) bool {
+	return level >= defaultLogger.level
 }
 
 // SetLevel sets the logging level for the default logger
@@ -242,6 +400,29 @@ func GetProductionLogDir() string {
 	return "logs"
 }
 
+// ParseFormat validates a log format; empty means text.
+func ParseFormat(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return FormatText, fmt.Errorf("unknown log format: %s", format)
+	}
+}
+
+// LogDir returns the directory of the configured log file, or the production
+// log directory when logging goes to the console only.
+func LogDir() string {
+	defaultLogger.mu.Lock()
+	defer defaultLogger.mu.Unlock()
+	if defaultLogger.file != nil {
+		return filepath.Dir(defaultLogger.file.Name())
+	}
+	return GetProductionLogDir()
+}
+
 // ParseLevel converts a string to a log level
 func ParseLevel(levelStr string) (Level, error) {
 	switch strings.ToUpper(levelStr) {
@@ -262,7 +443,7 @@ func ParseLevel(levelStr string) (Level, error) {
 
 // InitializeFromConfig initializes the default logger from configuration.
 // This function is designed to be called only once from main.
-func InitializeFromConfig(logFile string, levelStr string, enableConsole bool) error {
+func InitializeFromConfig(logFile string, levelStr string, enableConsole bool, formatStr string) error {
 	var finalErr error
 	once.Do(func() {
 		level, err := ParseLevel(levelStr)
@@ -270,6 +451,11 @@ func InitializeFromConfig(logFile string, levelStr string, enableConsole bool) e
 			finalErr = fmt.Errorf("invalid log level: %v", err)
 			return
 		}
+		format, err := ParseFormat(formatStr)
+		if err != nil {
+			finalErr = fmt.Errorf("invalid log format: %v", err)
+			return
+		}
 
 		// Ensure the log directory exists
 		logDir := filepath.Dir(logFile)
@@ -295,6 +481,7 @@ func InitializeFromConfig(logFile string, levelStr string, enableConsole bool) e
 		defer defaultLogger.mu.Unlock()
 
 		defaultLogger.level = level
+		defaultLogger.format = format
 		defaultLogger.file = file
 		defaultLogger.debugLogger = log.New(writer, "", 0)
 		defaultLogger.infoLogger = log.New(writer, "", 0)
@@ -0,0 +1,71 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dashboard_test
+
+import (
+	"errors"
+	"kaf-mirror/cmd/mirror-cli/dashboard/core"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLiveFeed_UpdatesCachedMetrics(t *testing.T) {
+	subscribed := make(chan string, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.URL.Query().Get("token"))
+		upgrader := websocket.Upgrader{}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.WriteJSON(map[string]interface{}{"v": 1, "type": "welcome", "data": map[string]interface{}{"channels": []string{"events", "job_metrics"}}})
+		for i := 0; i < 2; i++ {
+			var req map[string]string
+			if err := conn.ReadJSON(&req); err != nil {
+				return
+			}
+			subscribed <- req["channel"]
+		}
+		conn.WriteJSON(map[string]interface{}{"v": 2, "type": "job_metrics", "job_id": "job-a", "data": map[string]interface{}{"current_lag": 1}})
+		conn.WriteJSON(map[string]interface{}{"v": 1, "type": "job_metrics", "job_id": "job-a", "data": map[string]interface{}{"current_lag": 7}})
+		conn.ReadMessage()
+	}))
+	defer srv.Close()
+
+	dm := core.NewDataManager("secret", core.DataFetchers{
+		FetchMetrics: func(jobID string) (map[string]interface{}, error) {
+			return nil, errors.New("polling should not be needed")
+		},
+	})
+	feed := core.NewLiveFeed(srv.URL, "secret", dm)
+	go feed.Run()
+	defer feed.Close()
+
+	select {
+	case <-feed.Updates:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no live update received")
+	}
+	assert.ElementsMatch(t, []string{"events", "job_metrics"}, []string{<-subscribed, <-subscribed})
+
+	metrics, err := dm.GetJobMetrics("job-a")
+	require.NoError(t, err)
+	assert.Equal(t, 7.0, metrics["current_lag"])
+}
@@ -0,0 +1,391 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alerting_test
+
+import (
+	"kaf-mirror/internal/alerting"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupEngine(t *testing.T) (*sqlx.DB, *alerting.Engine, *captureServer) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	job := &database.ReplicationJob{ID: "job-a", Name: "orders", SourceClusterName: "src", TargetClusterName: "tgt", Status: "active"}
+	require.NoError(t, database.CreateJob(db, job))
+
+	hook := newCaptureServer(t, http.StatusOK)
+	engine, err := alerting.NewEngine(db, config.AlertingConfig{
+		Enabled:        true,
+		RepeatInterval: "1h",
+		SendResolved:   true,
+		Channels:       []config.AlertChannelConfig{{Name: "hook", Type: "webhook", URL: hook.URL}},
+	})
+	require.NoError(t, err)
+	return db, engine, hook
+}
+
+func createRule(t *testing.T, db *sqlx.DB, rule database.AlertRule) *database.AlertRule {
+	rule.Enabled = true
+	if rule.Severity == "" {
+		rule.Severity = database.AlertSeverityWarning
+	}
+	require.NoError(t, rule.Validate())
+	require.NoError(t, database.CreateAlertRule(db, &rule))
+	return &rule
+}
+
+func metricAt(ts time.Time, consumed, replicated, errors, lag int) database.ReplicationMetric {
+	return database.ReplicationMetric{
+		JobID:              "job-a",
+		MessagesConsumed:   consumed,
+		MessagesReplicated: replicated,
+		ErrorCount:         errors,
+		CurrentLag:         lag,
+		Timestamp:          ts,
+	}
+}
+
+func firingAlerts(t *testing.T, db *sqlx.DB) []database.Alert {
+	alerts, err := database.ListAlerts(db, database.AlertStatusFiring, "", 0)
+	require.NoError(t, err)
+	return alerts
+}
+
+func TestEngineLagRuleHonoursForDuration(t *testing.T) {
+	db, engine, hook := setupEngine(t)
+	createRule(t, db, database.AlertRule{Name: "orders-lag", RuleType: database.AlertRuleLag, JobID: "job-a",
+		Threshold: 100, ForDuration: "1m", Severity: database.AlertSeverityCritical})
+
+	t0 := time.Now()
+	engine.ObserveMetric(metricAt(t0, 10, 10, 0, 500))
+	require.NoError(t, engine.Evaluate(t0))
+	engine.ObserveMetric(metricAt(t0.Add(30*time.Second), 20, 20, 0, 500))
+	require.NoError(t, engine.Evaluate(t0.Add(30*time.Second)))
+	assert.Empty(t, firingAlerts(t, db), "condition has not held for the full duration yet")
+	assert.Equal(t, 0, hook.pending())
+
+	engine.ObserveMetric(metricAt(t0.Add(60*time.Second), 30, 30, 0, 500))
+	require.NoError(t, engine.Evaluate(t0.Add(60*time.Second)))
+	alerts := firingAlerts(t, db)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "job-a", alerts[0].JobID)
+	assert.Equal(t, float64(500), alerts[0].Value)
+	assert.Equal(t, "critical", alerts[0].Severity)
+	assert.Equal(t, "firing", hook.next(t)["event"])
+
+	// Still firing: no duplicate alert or notification within the repeat interval.
+	engine.ObserveMetric(metricAt(t0.Add(70*time.Second), 40, 40, 0, 600))
+	require.NoError(t, engine.Evaluate(t0.Add(70*time.Second)))
+	assert.Len(t, firingAlerts(t, db), 1)
+	assert.Equal(t, 0, hook.pending())
+
+	engine.ObserveMetric(metricAt(t0.Add(80*time.Second), 200, 200, 0, 5))
+	require.NoError(t, engine.Evaluate(t0.Add(80*time.Second)))
+	assert.Empty(t, firingAlerts(t, db))
+	resolved := hook.next(t)
+	assert.Equal(t, "resolved", resolved["event"])
+
+	stored, err := database.GetAlert(db, alerts[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, database.AlertStatusResolved, stored.Status)
+	assert.NotNil(t, stored.ResolvedAt)
+}
+
+func TestEngineRuleTypes(t *testing.T) {
+	t.Run("ErrorRate", func(t *testing.T) {
+		db, engine, hook := setupEngine(t)
+		createRule(t, db, database.AlertRule{Name: "errors", RuleType: database.AlertRuleErrorRate, Threshold: 10})
+		t0 := time.Now()
+		engine.ObserveMetric(metricAt(t0, 100, 100, 0, 0))
+		engine.ObserveMetric(metricAt(t0.Add(10*time.Second), 200, 180, 20, 0))
+		require.NoError(t, engine.Evaluate(t0.Add(10*time.Second)))
+
+		alerts := firingAlerts(t, db)
+		require.Len(t, alerts, 1)
+		assert.InDelta(t, 20.0, alerts[0].Value, 0.01)
+		hook.next(t)
+	})
+
+	t.Run("LagSeconds", func(t *testing.T) {
+		db, engine, hook := setupEngine(t)
+		createRule(t, db, database.AlertRule{Name: "behind", RuleType: database.AlertRuleLagSeconds, Threshold: 60})
+		t0 := time.Now()
+		// 100 msg/s consumption with 12000 messages of lag is two minutes behind.
+		engine.ObserveMetric(metricAt(t0, 1000, 1000, 0, 12000))
+		engine.ObserveMetric(metricAt(t0.Add(10*time.Second), 2000, 2000, 0, 12000))
+		require.NoError(t, engine.Evaluate(t0.Add(10*time.Second)))
+
+		alerts := firingAlerts(t, db)
+		require.Len(t, alerts, 1)
+		assert.InDelta(t, 120.0, alerts[0].Value, 0.01)
+		hook.next(t)
+	})
+
+	t.Run("Stalled", func(t *testing.T) {
+		db, engine, hook := setupEngine(t)
+		createRule(t, db, database.AlertRule{Name: "stalled", RuleType: database.AlertRuleStalled})
+		t0 := time.Now()
+		engine.ObserveMetric(metricAt(t0, 50, 50, 0, 300))
+		engine.ObserveMetric(metricAt(t0.Add(10*time.Second), 50, 50, 0, 300))
+		require.NoError(t, engine.Evaluate(t0.Add(10*time.Second)))
+		require.Len(t, firingAlerts(t, db), 1)
+		hook.next(t)
+	})
+
+	t.Run("StaleMetricsCountAsStalled", func(t *testing.T) {
+		db, engine, hook := setupEngine(t)
+		createRule(t, db, database.AlertRule{Name: "stalled", RuleType: database.AlertRuleStalled})
+		require.NoError(t, engine.Evaluate(time.Now()))
+		alerts := firingAlerts(t, db)
+		require.Len(t, alerts, 1)
+		assert.Contains(t, alerts[0].Summary, "has not reported metrics")
+		hook.next(t)
+	})
+
+	t.Run("JobFailed", func(t *testing.T) {
+		db, engine, hook := setupEngine(t)
+		createRule(t, db, database.AlertRule{Name: "failed", RuleType: database.AlertRuleJobFailed, Severity: database.AlertSeverityCritical})
+		job, err := database.GetJob(db, "job-a")
+		require.NoError(t, err)
+		reason := "producer has failed 101 consecutive times"
+		job.Status = "failed"
+		job.FailedReason = &reason
+		require.NoError(t, database.UpdateJob(db, job))
+
+		require.NoError(t, engine.Evaluate(time.Now()))
+		alerts := firingAlerts(t, db)
+		require.Len(t, alerts, 1)
+		assert.Contains(t, alerts[0].Summary, reason)
+		hook.next(t)
+	})
+
+	t.Run("GapDetected", func(t *testing.T) {
+		db, engine, hook := setupEngine(t)
+		createRule(t, db, database.AlertRule{Name: "gaps", RuleType: database.AlertRuleGapDetected})
+		require.NoError(t, database.DetectMirrorGaps(db, "job-a", []database.MirrorGap{{
+			JobID: "job-a", SourceTopic: "orders", TargetTopic: "orders", PartitionID: 0,
+			GapStartOffset: 10, GapEndOffset: 20, GapSize: 10, GapType: "missing_messages", ResolutionStatus: "unresolved",
+		}}))
+
+		require.NoError(t, engine.Evaluate(time.Now()))
+		alerts := firingAlerts(t, db)
+		require.Len(t, alerts, 1)
+		assert.Equal(t, float64(1), alerts[0].Value)
+		hook.next(t)
+	})
+
+	t.Run("SLOBurnRate", func(t *testing.T) {
+		db, engine, hook := setupEngine(t)
+		createRule(t, db, database.AlertRule{Name: "burn", RuleType: database.AlertRuleSLOBurnRate, Threshold: 14.4})
+		require.NoError(t, database.CreateJobSLO(db, &database.JobSLO{JobID: "job-a", Name: "time-lag",
+			SLOType: database.SLOTypeLagSeconds, Objective: 99, Threshold: 60, Enabled: true}))
+
+		now := time.Now()
+		// Ten good minutes, then two minutes far behind the source.
+		for i := 12; i > 0; i-- {
+			lag := 1.0
+			if i <= 2 {
+				lag = 300
+			}
+			_, err := db.Exec(`INSERT INTO aggregated_metrics (job_id, timestamp, messages_replicated_delta, bytes_transferred_delta, avg_lag, error_count_delta, lag_seconds_max)
+				VALUES (?, ?, 100, 100, 0, 0, ?)`, "job-a", now.Add(-time.Duration(i)*time.Minute), lag)
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, engine.Evaluate(now))
+		alerts := firingAlerts(t, db)
+		require.Len(t, alerts, 1)
+		// 2 of 12 minutes in the last hour spend a 1% budget 16.7x too fast.
+		assert.InDelta(t, 2.0/12/0.01, alerts[0].Value, 0.01)
+		assert.Contains(t, alerts[0].Summary, "time-lag")
+		hook.next(t)
+	})
+}
+
+func TestEngineSilenceSuppressesNotifications(t *testing.T) {
+	db, engine, hook := setupEngine(t)
+	rule := createRule(t, db, database.AlertRule{Name: "lag", RuleType: database.AlertRuleLag, Threshold: 100})
+	now := time.Now()
+	require.NoError(t, database.CreateAlertSilence(db, &database.AlertSilence{
+		JobID: "job-a", RuleID: &rule.ID, CreatedBy: "testuser",
+		StartsAt: now.Add(-time.Minute), EndsAt: now.Add(time.Hour),
+	}))
+
+	engine.ObserveMetric(metricAt(now, 10, 10, 0, 500))
+	require.NoError(t, engine.Evaluate(now))
+
+	alerts := firingAlerts(t, db)
+	require.Len(t, alerts, 1)
+	assert.True(t, alerts[0].Silenced, "alert is recorded but marked silenced")
+
+	engine.ObserveMetric(metricAt(now.Add(10*time.Second), 500, 500, 0, 0))
+	require.NoError(t, engine.Evaluate(now.Add(10*time.Second)))
+	assert.Empty(t, firingAlerts(t, db))
+	assert.Equal(t, 0, hook.pending(), "silenced alerts notify neither firing nor resolution")
+}
+
+func TestEngineAcknowledgementStopsRepeats(t *testing.T) {
+	db, engine, hook := setupEngine(t)
+	createRule(t, db, database.AlertRule{Name: "failed", RuleType: database.AlertRuleJobFailed})
+	job, err := database.GetJob(db, "job-a")
+	require.NoError(t, err)
+	job.Status = "failed"
+	require.NoError(t, database.UpdateJob(db, job))
+
+	t0 := time.Now()
+	require.NoError(t, engine.Evaluate(t0))
+	hook.next(t)
+
+	// Unacknowledged alerts are re-notified after the repeat interval.
+	require.NoError(t, engine.Evaluate(t0.Add(61*time.Minute)))
+	assert.Equal(t, "firing", hook.next(t)["event"])
+
+	alerts := firingAlerts(t, db)
+	require.Len(t, alerts, 1)
+	acked, err := engine.Acknowledge(alerts[0].ID, "oncall")
+	require.NoError(t, err)
+	require.NotNil(t, acked.AcknowledgedBy)
+	assert.Equal(t, "oncall", *acked.AcknowledgedBy)
+	assert.Equal(t, "acknowledged", hook.next(t)["event"])
+
+	_, err = engine.Acknowledge(alerts[0].ID, "oncall")
+	assert.ErrorIs(t, err, database.ErrAlertAlreadyAcknowledged)
+
+	require.NoError(t, engine.Evaluate(t0.Add(3*time.Hour)))
+	assert.Equal(t, 0, hook.pending())
+}
+
+func TestEngineResolvesAlertsOfDeletedRules(t *testing.T) {
+	db, engine, hook := setupEngine(t)
+	rule := createRule(t, db, database.AlertRule{Name: "lag", RuleType: database.AlertRuleLag, Threshold: 100})
+	now := time.Now()
+	engine.ObserveMetric(metricAt(now, 10, 10, 0, 500))
+	require.NoError(t, engine.Evaluate(now))
+	require.Len(t, firingAlerts(t, db), 1)
+	hook.next(t)
+
+	require.NoError(t, database.DeleteAlertRule(db, rule.ID))
+	require.NoError(t, engine.Evaluate(now.Add(10*time.Second)))
+	assert.Empty(t, firingAlerts(t, db))
+}
+
+func TestEngineRestoresFiringAlerts(t *testing.T) {
+	db, engine, hook := setupEngine(t)
+	createRule(t, db, database.AlertRule{Name: "lag", RuleType: database.AlertRuleLag, Threshold: 100})
+	now := time.Now()
+	engine.ObserveMetric(metricAt(now, 10, 10, 0, 500))
+	require.NoError(t, engine.Evaluate(now))
+	hook.next(t)
+
+	// A restarted engine picks up the firing alert instead of firing a duplicate.
+	restarted, err := alerting.NewEngine(db, config.AlertingConfig{
+		RepeatInterval: "1h",
+		SendResolved:   true,
+		Channels:       []config.AlertChannelConfig{{Name: "hook", Type: "webhook", URL: hook.URL}},
+	})
+	require.NoError(t, err)
+	restarted.ObserveMetric(metricAt(now.Add(10*time.Second), 20, 20, 0, 500))
+	require.NoError(t, restarted.Evaluate(now.Add(10*time.Second)))
+	assert.Len(t, firingAlerts(t, db), 1)
+	assert.Equal(t, 0, hook.pending())
+
+	restarted.ObserveMetric(metricAt(now.Add(20*time.Second), 600, 600, 0, 0))
+	require.NoError(t, restarted.Evaluate(now.Add(20*time.Second)))
+	assert.Empty(t, firingAlerts(t, db))
+	assert.Equal(t, "resolved", hook.next(t)["event"])
+}
+
+func TestEngineListenersSeeSilencedAlerts(t *testing.T) {
+	db, engine, hook := setupEngine(t)
+	rule := createRule(t, db, database.AlertRule{Name: "lag", RuleType: database.AlertRuleLag, Threshold: 100})
+	now := time.Now()
+	require.NoError(t, database.CreateAlertSilence(db, &database.AlertSilence{
+		JobID: "job-a", RuleID: &rule.ID, CreatedBy: "testuser",
+		StartsAt: now.Add(-time.Minute), EndsAt: now.Add(time.Hour),
+	}))
+
+	var events []string
+	engine.AddListener(func(n alerting.Notification) {
+		events = append(events, n.Event+":"+n.Alert.JobID)
+	})
+
+	engine.ObserveMetric(metricAt(now, 10, 10, 0, 500))
+	require.NoError(t, engine.Evaluate(now))
+	alerts := firingAlerts(t, db)
+	require.Len(t, alerts, 1)
+	_, err := engine.Acknowledge(alerts[0].ID, "testuser")
+	require.NoError(t, err)
+	engine.ObserveMetric(metricAt(now.Add(10*time.Second), 500, 500, 0, 0))
+	require.NoError(t, engine.Evaluate(now.Add(10*time.Second)))
+
+	assert.Equal(t, []string{"firing:job-a", "acknowledged:job-a", "resolved:job-a"}, events)
+	assert.Equal(t, 0, hook.pending())
+}
+
+func TestEngineSlowChannelDoesNotBlockMetrics(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, database.CreateJob(db, &database.ReplicationJob{ID: "job-a", Name: "orders", SourceClusterName: "src", TargetClusterName: "tgt", Status: "active"}))
+
+	received := make(chan struct{}, 1)
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		<-release
+	}))
+	t.Cleanup(slow.Close)
+	unblock := sync.OnceFunc(func() { close(release) })
+	t.Cleanup(unblock) // runs before slow.Close, which waits for the handler
+	engine, err := alerting.NewEngine(db, config.AlertingConfig{
+		Enabled:  true,
+		Channels: []config.AlertChannelConfig{{Name: "slow", Type: "webhook", URL: slow.URL}},
+	})
+	require.NoError(t, err)
+	createRule(t, db, database.AlertRule{Name: "lag", RuleType: database.AlertRuleLag, Threshold: 100})
+
+	now := time.Now()
+	engine.ObserveMetric(metricAt(now, 10, 10, 0, 500))
+	evaluated := make(chan error, 1)
+	go func() { evaluated <- engine.Evaluate(now) }()
+
+	select {
+	case <-received:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no notification sent")
+	}
+	observed := make(chan struct{})
+	go func() {
+		engine.ObserveMetric(metricAt(now.Add(10*time.Second), 20, 20, 0, 500))
+		close(observed)
+	}()
+	select {
+	case <-observed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("ObserveMetric blocked on a notification in flight")
+	}
+
+	unblock()
+	require.NoError(t, <-evaluated)
+}
@@ -0,0 +1,267 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package alerting_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"kaf-mirror/internal/alerting"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// captureServer is a local HTTP stand-in that records every JSON body it receives.
+type captureServer struct {
+	*httptest.Server
+	requests chan map[string]interface{}
+	headers  chan http.Header
+}
+
+func newCaptureServer(t *testing.T, status int) *captureServer {
+	cs := &captureServer{
+		requests: make(chan map[string]interface{}, 32),
+		headers:  make(chan http.Header, 32),
+	}
+	cs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload map[string]interface{}
+		json.Unmarshal(body, &payload)
+		cs.requests <- payload
+		cs.headers <- r.Header.Clone()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(cs.Close)
+	return cs
+}
+
+func (cs *captureServer) next(t *testing.T) map[string]interface{} {
+	t.Helper()
+	select {
+	case payload := <-cs.requests:
+		<-cs.headers
+		return payload
+	case <-time.After(2 * time.Second):
+		t.Fatal("no request received")
+		return nil
+	}
+}
+
+func (cs *captureServer) pending() int {
+	return len(cs.requests)
+}
+
+// smtpStandIn is a minimal SMTP server that accepts one message per connection.
+type smtpStandIn struct {
+	addr     string
+	messages chan string
+	rcpts    chan []string
+}
+
+func newSMTPStandIn(t *testing.T) *smtpStandIn {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	s := &smtpStandIn{addr: ln.Addr().String(), messages: make(chan string, 8), rcpts: make(chan []string, 8)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+func (s *smtpStandIn) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	write := func(line string) { io.WriteString(conn, line+"\r\n") }
+	write("220 localhost ESMTP stand-in")
+
+	var rcpts []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			write("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM"):
+			write("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO"):
+			rcpts = append(rcpts, strings.TrimSpace(line[len("RCPT TO:"):]))
+			write("250 OK")
+		case cmd == "DATA":
+			write("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.messages <- data.String()
+			s.rcpts <- rcpts
+			write("250 OK queued")
+		case cmd == "QUIT":
+			write("221 Bye")
+			return
+		default:
+			write("250 OK")
+		}
+	}
+}
+
+func sampleAlert() database.Alert {
+	return database.Alert{
+		ID:        42,
+		RuleName:  "orders-lag",
+		RuleType:  database.AlertRuleLag,
+		JobID:     "job-a",
+		Severity:  database.AlertSeverityCritical,
+		Status:    database.AlertStatusFiring,
+		Value:     5000,
+		Threshold: 1000,
+		Summary:   "Consumer lag for job orders (job-a) is 5000 messages (threshold 1000)",
+		FiredAt:   time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC),
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	srv := newCaptureServer(t, http.StatusOK)
+	notifier, err := alerting.NewNotifier(config.AlertChannelConfig{
+		Name: "hook", Type: "webhook", URL: srv.URL,
+		Headers: map[string]string{"Authorization": "Bearer secret"},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, notifier.Notify(context.Background(), alerting.Notification{Event: alerting.EventFiring, Alert: sampleAlert()}))
+	var payload map[string]interface{}
+	select {
+	case payload = <-srv.requests:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no request received")
+	}
+	headers := <-srv.headers
+	assert.Equal(t, "Bearer secret", headers.Get("Authorization"))
+	assert.Equal(t, "firing", payload["event"])
+	alert := payload["alert"].(map[string]interface{})
+	assert.Equal(t, "orders-lag", alert["rule_name"])
+	assert.Equal(t, "job-a", alert["job_id"])
+}
+
+func TestWebhookNotifierReportsHTTPErrors(t *testing.T) {
+	srv := newCaptureServer(t, http.StatusInternalServerError)
+	notifier, err := alerting.NewNotifier(config.AlertChannelConfig{Name: "hook", Type: "webhook", URL: srv.URL})
+	require.NoError(t, err)
+
+	err = notifier.Notify(context.Background(), alerting.Notification{Event: alerting.EventFiring, Alert: sampleAlert()})
+	assert.ErrorContains(t, err, "500")
+}
+
+func TestSlackNotifier(t *testing.T) {
+	srv := newCaptureServer(t, http.StatusOK)
+	notifier, err := alerting.NewNotifier(config.AlertChannelConfig{Name: "slack", Type: "slack", URL: srv.URL})
+	require.NoError(t, err)
+
+	require.NoError(t, notifier.Notify(context.Background(), alerting.Notification{Event: alerting.EventFiring, Alert: sampleAlert()}))
+	payload := srv.next(t)
+	assert.Equal(t, "[FIRING] critical: orders-lag", payload["text"])
+	attachments := payload["attachments"].([]interface{})
+	require.Len(t, attachments, 1)
+	attachment := attachments[0].(map[string]interface{})
+	assert.Equal(t, "#d00000", attachment["color"])
+	assert.Contains(t, attachment["text"], "5000 messages")
+}
+
+func TestPagerDutyNotifierFollowsAlertLifecycle(t *testing.T) {
+	srv := newCaptureServer(t, http.StatusAccepted)
+	notifier, err := alerting.NewNotifier(config.AlertChannelConfig{Name: "pd", Type: "pagerduty", URL: srv.URL, RoutingKey: "R0UT1NG"})
+	require.NoError(t, err)
+
+	alert := sampleAlert()
+	for _, event := range []string{alerting.EventFiring, alerting.EventAcknowledged, alerting.EventResolved} {
+		require.NoError(t, notifier.Notify(context.Background(), alerting.Notification{Event: event, Alert: alert}))
+	}
+
+	trigger := srv.next(t)
+	assert.Equal(t, "R0UT1NG", trigger["routing_key"])
+	assert.Equal(t, "trigger", trigger["event_action"])
+	assert.Equal(t, "kaf-mirror-alert-42", trigger["dedup_key"])
+	details := trigger["payload"].(map[string]interface{})
+	assert.Equal(t, "critical", details["severity"])
+	assert.Equal(t, "job-a", details["component"])
+	assert.Equal(t, "kaf-mirror", details["source"])
+
+	ack := srv.next(t)
+	assert.Equal(t, "acknowledge", ack["event_action"])
+	assert.Equal(t, "kaf-mirror-alert-42", ack["dedup_key"])
+	assert.NotContains(t, ack, "payload")
+
+	resolve := srv.next(t)
+	assert.Equal(t, "resolve", resolve["event_action"])
+	assert.Equal(t, "kaf-mirror-alert-42", resolve["dedup_key"])
+}
+
+func TestEmailNotifier(t *testing.T) {
+	smtpServer := newSMTPStandIn(t)
+	host, portStr, _ := net.SplitHostPort(smtpServer.addr)
+	port, _ := strconv.Atoi(portStr)
+
+	notifier, err := alerting.NewNotifier(config.AlertChannelConfig{
+		Name: "mail", Type: "email",
+		SMTP: config.AlertSMTPConfig{
+			Host: host, Port: port,
+			From: "kaf-mirror@example.com",
+			To:   []string{"oncall@example.com", "team@example.com"},
+		},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, notifier.Notify(context.Background(), alerting.Notification{Event: alerting.EventFiring, Alert: sampleAlert()}))
+
+	select {
+	case msg := <-smtpServer.messages:
+		assert.Contains(t, msg, "Subject: kaf-mirror [FIRING] critical: orders-lag")
+		assert.Contains(t, msg, "To: oncall@example.com, team@example.com")
+		assert.Contains(t, msg, "Job:       job-a")
+		assert.Equal(t, []string{"<oncall@example.com>", "<team@example.com>"}, <-smtpServer.rcpts)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no mail received")
+	}
+}
+
+func TestNewNotifierRejectsUnknownType(t *testing.T) {
+	_, err := alerting.NewNotifier(config.AlertChannelConfig{Name: "x", Type: "carrier-pigeon"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analysis_test
+
+import (
+	"bytes"
+	"kaf-mirror/internal/analysis"
+	"kaf-mirror/pkg/logger"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeLog(t *testing.T, dir, name string, modTime time.Time, lines ...string) {
+	t.Helper()
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.WriteString(line + "\n")
+	}
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestGetLogsForJob_ReadsStructuredAndTextAcrossDays(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	yesterday := now.Add(-20 * time.Hour)
+
+	var jsonLines bytes.Buffer
+	l, err := logger.New(&jsonLines, logger.DEBUG, "json")
+	require.NoError(t, err)
+	l.With(logger.JobID("job-1"), logger.Component("producer"), logger.Topic("orders"), logger.Partition(2)).Error("Failed to produce record")
+	l.With(logger.JobID("job-2")).Info("other job")
+	l.With(logger.Topic("orders")).AI(logger.WARN, "incident", "start", "Source stalled for job-1")
+	writeLog(t, dir, "kaf-mirror-"+now.Format("2006-01-02")+".log", now, strings.TrimSpace(jsonLines.String()))
+
+	ts := func(at time.Time) string { return at.Format("2006-01-02 15:04:05.000") }
+	writeLog(t, dir, "kaf-mirror-"+yesterday.Format("2006-01-02")+".log", yesterday,
+		"["+ts(yesterday)+"] INFO  [manager.go:10] [job:job-1] [component:manager] Starting job job-1",
+		"["+ts(yesterday)+"] INFO  [manager.go:11] [job:job-2] Starting job job-2",
+		"["+ts(yesterday)+"] WARN  [kaf-mirror.go:20] [AI:incident:start] [job:job-1] Source stalled",
+	)
+	writeLog(t, dir, "kaf-mirror-2000-01-01.log", now.Add(-72*time.Hour),
+		"["+ts(now.Add(-72*time.Hour))+"] INFO  [manager.go:10] [job:job-1] too old",
+	)
+	writeLog(t, dir, "kaf-mirror.log.gz", now, "compressed")
+
+	entries, err := analysis.GetLogsForJob(dir, "job-1", now.Add(-24*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+
+	assert.Equal(t, "manager", entries[0].Component)
+	assert.Equal(t, "Starting job job-1", entries[0].Message)
+	assert.Equal(t, "job-1", entries[0].JobID)
+	assert.WithinDuration(t, yesterday, entries[0].Timestamp, time.Second)
+
+	assert.Equal(t, "incident", entries[1].AICategory)
+	assert.Equal(t, "start", entries[1].AISubcategory)
+	assert.Equal(t, "WARN", entries[1].Level)
+
+	assert.Equal(t, "producer", entries[2].Component)
+	assert.Equal(t, "ERROR", entries[2].Level)
+	assert.Equal(t, "orders", entries[2].Topic)
+	require.NotNil(t, entries[2].Partition)
+	assert.Equal(t, int32(2), *entries[2].Partition)
+
+	assert.Equal(t, "incident", entries[3].AICategory, "AI records mentioning the job are included")
+	assert.Empty(t, entries[3].JobID)
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"kaf-mirror/internal/audit"
+	"kaf-mirror/internal/database"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleEvent() database.OperationalEvent {
+	return database.OperationalEvent{
+		ID:        7,
+		Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		EventType: "user_deleted",
+		Initiator: "admin",
+		Details:   `{"user":"a=b|c"}`,
+		PrevHash:  "sha256:aa",
+		Hash:      "sha256:bb",
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.jsonl")
+	sink, err := audit.NewFileSink(path)
+	require.NoError(t, err)
+
+	exporter := audit.NewExporterWithSinks(sink)
+	exporter.Enqueue(sampleEvent())
+	exporter.Enqueue(sampleEvent())
+	exporter.Close()
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event database.OperationalEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		assert.Equal(t, "sha256:bb", event.Hash)
+		lines++
+	}
+	assert.Equal(t, 2, lines)
+}
+
+func TestSyslogSink(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	read := func() string {
+		buf := make([]byte, 4096)
+		listener.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, _, err := listener.ReadFrom(buf)
+		require.NoError(t, err)
+		return string(buf[:n])
+	}
+
+	t.Run("CEF", func(t *testing.T) {
+		sink, err := audit.NewSyslogSink("udp", listener.LocalAddr().String(), audit.FormatCEF, 16)
+		require.NoError(t, err)
+		defer sink.Close()
+
+		require.NoError(t, sink.Write(sampleEvent()))
+		msg := read()
+		// local0 (16) * 8 + notice (5)
+		assert.True(t, strings.HasPrefix(msg, "<133>1 2025-01-02T03:04:05Z "), msg)
+		assert.Contains(t, msg, "CEF:0|Scalytics|kaf-mirror|1|user_deleted|user_deleted|5|")
+		assert.Contains(t, msg, `msg={"user":"a\=b|c"}`)
+		assert.Contains(t, msg, "cs1=sha256:bb")
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		sink, err := audit.NewSyslogSink("udp", listener.LocalAddr().String(), audit.FormatJSON, 16)
+		require.NoError(t, err)
+		defer sink.Close()
+
+		require.NoError(t, sink.Write(sampleEvent()))
+		msg := read()
+		body := msg[strings.Index(msg, "{"):]
+		var event database.OperationalEvent
+		require.NoError(t, json.Unmarshal([]byte(body), &event))
+		assert.Equal(t, 7, event.ID)
+	})
+}
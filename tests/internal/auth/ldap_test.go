@@ -0,0 +1,276 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth_test
+
+import (
+	"errors"
+	"kaf-mirror/internal/auth"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	serviceDN       = "cn=svc,ou=service,dc=example,dc=com"
+	servicePassword = "svc-secret"
+	adminsGroup     = "cn=kafka-admins,ou=groups,dc=example,dc=com"
+	operatorsGroup  = "cn=kafka-operators,ou=groups,dc=example,dc=com"
+)
+
+type directoryEntry struct {
+	dn       string
+	password string
+	groups   []string
+}
+
+// fakeDirectory is an in-process LDAP stand-in keyed by uid.
+type fakeDirectory struct {
+	mu      sync.Mutex
+	entries map[string]directoryEntry
+}
+
+func newFakeDirectory() *fakeDirectory {
+	return &fakeDirectory{entries: make(map[string]directoryEntry)}
+}
+
+func (d *fakeDirectory) add(uid, password string, groups ...string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries[uid] = directoryEntry{
+		dn:       "uid=" + uid + ",ou=people,dc=example,dc=com",
+		password: password,
+		groups:   groups,
+	}
+}
+
+func (d *fakeDirectory) remove(uid string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.entries, uid)
+}
+
+func (d *fakeDirectory) dial(cfg config.LDAPConfig) (auth.LDAPConn, error) {
+	return &fakeConn{dir: d}, nil
+}
+
+type fakeConn struct {
+	dir   *fakeDirectory
+	bound bool
+}
+
+func (c *fakeConn) Bind(username, password string) error {
+	c.dir.mu.Lock()
+	defer c.dir.mu.Unlock()
+	if username == serviceDN && password == servicePassword {
+		c.bound = true
+		return nil
+	}
+	for _, entry := range c.dir.entries {
+		if entry.dn == username && entry.password == password {
+			c.bound = true
+			return nil
+		}
+	}
+	return ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+}
+
+func (c *fakeConn) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	if !c.bound {
+		return nil, ldap.NewError(ldap.LDAPResultInsufficientAccessRights, errors.New("not bound"))
+	}
+	result := &ldap.SearchResult{}
+	if req.Scope == ldap.ScopeBaseObject {
+		result.Entries = append(result.Entries, ldap.NewEntry(req.BaseDN, nil))
+		return result, nil
+	}
+
+	start := strings.Index(req.Filter, "(uid=")
+	if start < 0 {
+		return result, nil
+	}
+	uid := req.Filter[start+len("(uid="):]
+	uid = uid[:strings.Index(uid, ")")]
+
+	c.dir.mu.Lock()
+	defer c.dir.mu.Unlock()
+	if entry, ok := c.dir.entries[uid]; ok {
+		result.Entries = append(result.Entries, ldap.NewEntry(entry.dn, map[string][]string{
+			"memberOf": entry.groups,
+		}))
+	}
+	return result, nil
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func setupLDAP(t *testing.T) (*fakeDirectory, config.LDAPConfig) {
+	dir := newFakeDirectory()
+	auth.SetLDAPDialerForTest(dir.dial)
+	t.Cleanup(func() { auth.SetLDAPDialerForTest(nil) })
+
+	cfg := config.LDAPConfig{
+		Enabled:      true,
+		URL:          "ldap://in-process",
+		BindDN:       serviceDN,
+		BindPassword: servicePassword,
+		BaseDN:       "dc=example,dc=com",
+		GroupRoleMapping: map[string]string{
+			adminsGroup:    "admin",
+			operatorsGroup: "operator",
+		},
+	}
+	return dir, cfg
+}
+
+func TestLDAPAuthenticator_Authenticate(t *testing.T) {
+	dir, cfg := setupLDAP(t)
+	dir.add("alice", "alice-pw", operatorsGroup, "CN=Kafka-Admins,OU=Groups,DC=example,DC=com")
+	dir.add("bob", "bob-pw", "cn=unrelated,ou=groups,dc=example,dc=com")
+
+	authenticator := auth.NewLDAPAuthenticator(cfg)
+
+	user, err := authenticator.Authenticate("alice", "alice-pw")
+	require.NoError(t, err)
+	assert.Equal(t, "uid=alice,ou=people,dc=example,dc=com", user.DN)
+	assert.Equal(t, "admin", user.Role, "most privileged mapped group should win, compared case-insensitively")
+
+	_, err = authenticator.Authenticate("alice", "wrong")
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+
+	_, err = authenticator.Authenticate("alice", "")
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+
+	_, err = authenticator.Authenticate("mallory", "pw")
+	assert.ErrorIs(t, err, auth.ErrUserNotFound)
+
+	_, err = authenticator.Authenticate("bob", "bob-pw")
+	assert.ErrorIs(t, err, auth.ErrNoMappedRole)
+
+	cfg.DefaultRole = "monitoring"
+	user, err = auth.NewLDAPAuthenticator(cfg).Authenticate("bob", "bob-pw")
+	require.NoError(t, err)
+	assert.Equal(t, "monitoring", user.Role)
+}
+
+func TestLDAPAuthenticator_ServiceBindFailure(t *testing.T) {
+	_, cfg := setupLDAP(t)
+	cfg.BindPassword = "wrong"
+
+	err := auth.NewLDAPAuthenticator(cfg).TestConnection()
+	assert.Error(t, err)
+
+	cfg.BindPassword = servicePassword
+	assert.NoError(t, auth.NewLDAPAuthenticator(cfg).TestConnection())
+}
+
+func TestLDAPAuthenticator_LoginAndSync(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, database.SeedDefaultRolesAndPermissions(db))
+
+	dir, cfg := setupLDAP(t)
+	dir.add("alice", "alice-pw", operatorsGroup)
+	dir.add("carol", "carol-pw", adminsGroup)
+	authenticator := auth.NewLDAPAuthenticator(cfg)
+
+	_, err = database.CreateUser(db, "dave", "local-pw", false)
+	require.NoError(t, err)
+	dir.add("dave", "dave-pw", adminsGroup)
+
+	t.Run("ProvisionsDirectoryUser", func(t *testing.T) {
+		user, err := authenticator.Login(db, "alice", "alice-pw")
+		require.NoError(t, err)
+		assert.Equal(t, database.AuthSourceLDAP, user.AuthSource)
+		assert.False(t, user.VerifyPassword("alice-pw"), "directory users must not have a usable local password")
+
+		role, err := database.GetUserRole(db, user.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "operator", role)
+
+		_, err = authenticator.Login(db, "carol", "carol-pw")
+		require.NoError(t, err)
+	})
+
+	t.Run("RejectsLocalAccountTakeover", func(t *testing.T) {
+		_, err := authenticator.Login(db, "dave", "dave-pw")
+		assert.ErrorIs(t, err, auth.ErrLocalAccount)
+	})
+
+	t.Run("SyncDisablesRemovedUsersAndUpdatesRoles", func(t *testing.T) {
+		carol, err := database.GetUserByUsername(db, "carol")
+		require.NoError(t, err)
+		_, _, err = database.CreateApiToken(db, carol.ID, "test", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		dir.remove("carol")
+		dir.add("alice", "alice-pw", adminsGroup)
+
+		result, err := authenticator.SyncUsers(db)
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Checked)
+		assert.Equal(t, 1, result.Disabled)
+		assert.Equal(t, 1, result.RoleUpdates)
+
+		carol, err = database.GetUserByUsername(db, "carol")
+		require.NoError(t, err)
+		assert.True(t, carol.Disabled)
+
+		var tokens int
+		require.NoError(t, db.Get(&tokens, "SELECT COUNT(*) FROM api_tokens WHERE user_id = ?", carol.ID))
+		assert.Zero(t, tokens)
+
+		alice, err := database.GetUserByUsername(db, "alice")
+		require.NoError(t, err)
+		role, err := database.GetUserRole(db, alice.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "admin", role)
+
+		dave, err := database.GetUserByUsername(db, "dave")
+		require.NoError(t, err)
+		assert.False(t, dave.Disabled, "local users are not touched by directory sync")
+	})
+
+	t.Run("LoginReenablesUserBackInDirectory", func(t *testing.T) {
+		dir.add("carol", "carol-pw", operatorsGroup)
+		user, err := authenticator.Login(db, "carol", "carol-pw")
+		require.NoError(t, err)
+		assert.False(t, user.Disabled)
+	})
+
+	t.Run("LocallyDisabledUserStaysDisabled", func(t *testing.T) {
+		alice, err := database.GetUserByUsername(db, "alice")
+		require.NoError(t, err)
+		require.NoError(t, database.SetUserDisabled(db, alice.ID, true, database.DisabledByAdmin))
+
+		_, err = authenticator.Login(db, "alice", "alice-pw")
+		assert.ErrorIs(t, err, auth.ErrAccountDisabled)
+
+		result, err := authenticator.SyncUsers(db)
+		require.NoError(t, err)
+		assert.Zero(t, result.Enabled)
+
+		alice, err = database.GetUserByUsername(db, "alice")
+		require.NoError(t, err)
+		assert.True(t, alice.Disabled)
+		assert.Equal(t, database.DisabledByAdmin, alice.DisabledReason)
+	})
+}
@@ -14,6 +14,7 @@ package config_test
 import (
 	"kaf-mirror/internal/config"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -72,3 +73,249 @@ func TestConfigValidate_RetentionBounds(t *testing.T) {
 	err = cfg.Validate()
 	assert.Error(t, err)
 }
+
+func TestConfigValidate_AlertingChannels(t *testing.T) {
+	cfg := &config.Config{
+		Server: config.ServerConfig{Port: 8080},
+		Clusters: map[string]config.ClusterConfig{
+			"source": {Brokers: "localhost:9092"},
+		},
+	}
+	cfg.Alerting.Channels = []config.AlertChannelConfig{
+		{Name: "hook", Type: "webhook", URL: "http://localhost:9000/hook"},
+		{Name: "pd", Type: "pagerduty", RoutingKey: "key"},
+		{Name: "mail", Type: "email", SMTP: config.AlertSMTPConfig{Host: "localhost", From: "a@example.com", To: []string{"b@example.com"}}},
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Alerting.Channels = append(cfg.Alerting.Channels, config.AlertChannelConfig{Name: "hook", Type: "slack", URL: "http://x"})
+	assert.Error(t, cfg.Validate(), "duplicate channel names")
+
+	cfg.Alerting.Channels = []config.AlertChannelConfig{{Name: "mail", Type: "email"}}
+	assert.Error(t, cfg.Validate(), "email without smtp settings")
+
+	cfg.Alerting.Channels = []config.AlertChannelConfig{{Name: "sms", Type: "sms"}}
+	assert.Error(t, cfg.Validate(), "unsupported channel type")
+
+	cfg.Alerting.Channels = nil
+	cfg.Alerting.RepeatInterval = "0s"
+	assert.Error(t, cfg.Validate())
+}
+
+func TestConfigValidate_MonitoringOTLP(t *testing.T) {
+	cfg := &config.Config{
+		Server: config.ServerConfig{Port: 8080},
+		Clusters: map[string]config.ClusterConfig{
+			"source": {Brokers: "localhost:9092"},
+		},
+	}
+	cfg.Monitoring.Enabled = true
+	cfg.Monitoring.Platform = "otlp"
+	assert.Error(t, cfg.Validate(), "otlp platform without endpoint")
+
+	cfg.Monitoring.OTLP.Endpoint = "localhost:4317"
+	cfg.Monitoring.Tracing = config.TracingConfig{Enabled: true, SampleRatio: 0.1}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Monitoring.Tracing.SampleRatio = 1.5
+	assert.Error(t, cfg.Validate(), "sample ratio above 1")
+
+	cfg.Monitoring.Tracing.SampleRatio = 1
+	cfg.Monitoring.OTLP.Protocol = "thrift"
+	assert.Error(t, cfg.Validate(), "unsupported protocol")
+
+	cfg.Monitoring.OTLP.Protocol = "http"
+	cfg.Monitoring.OTLP.ExportInterval = "0s"
+	assert.Error(t, cfg.Validate())
+}
+
+func TestConfigValidate_MonitoringSinks(t *testing.T) {
+	cfg := &config.Config{
+		Server: config.ServerConfig{Port: 8080},
+		Clusters: map[string]config.ClusterConfig{
+			"source": {Brokers: "localhost:9092"},
+		},
+	}
+	cfg.Monitoring.Enabled = true
+	cfg.Monitoring.Platform = "loki"
+	assert.Equal(t, []string{"loki"}, cfg.Monitoring.SinkNames())
+
+	cfg.Monitoring.Sinks = []string{"loki", "splunk", "prometheus"}
+	assert.Equal(t, []string{"loki", "splunk", "prometheus"}, cfg.Monitoring.SinkNames(), "sinks override platform")
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Monitoring.Sinks = []string{"loki", "loki"}
+	assert.Error(t, cfg.Validate(), "duplicate sink")
+
+	cfg.Monitoring.Sinks = []string{"datadog"}
+	assert.Error(t, cfg.Validate(), "unsupported sink")
+
+	cfg.Monitoring.Sinks = []string{"loki", "otlp"}
+	assert.Error(t, cfg.Validate(), "otlp sink without endpoint")
+
+	cfg.Monitoring.Sinks = []string{"loki"}
+	cfg.Monitoring.Buffer.MaxBackoff = "soon"
+	assert.Error(t, cfg.Validate())
+}
+
+func TestConfigValidate_LoggingJobBufferSize(t *testing.T) {
+	cfg := &config.Config{
+		Server: config.ServerConfig{Port: 8080},
+		Clusters: map[string]config.ClusterConfig{
+			"source": {Brokers: "localhost:9092"},
+		},
+	}
+
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, 1000, cfg.Logging.JobBufferSize)
+
+	cfg.Logging.JobBufferSize = 50
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, 50, cfg.Logging.JobBufferSize)
+
+	cfg.Logging.JobBufferSize = -1
+	assert.Error(t, cfg.Validate())
+}
+
+func TestConfigValidate_Webhooks(t *testing.T) {
+	cfg := &config.Config{
+		Server: config.ServerConfig{Port: 8080},
+		Clusters: map[string]config.ClusterConfig{
+			"source": {Brokers: "localhost:9092"},
+		},
+		Webhooks: config.WebhooksConfig{MaxAttempts: 3, InitialBackoff: "5s", MaxBackoff: "1m", Timeout: "2s"},
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Webhooks.InitialBackoff = "soon"
+	assert.Error(t, cfg.Validate())
+
+	cfg.Webhooks.InitialBackoff = "5s"
+	cfg.Webhooks.MaxAttempts = -1
+	assert.Error(t, cfg.Validate())
+}
+
+func TestConfigValidate_TopicConfigSync(t *testing.T) {
+	cfg := &config.Config{
+		Server: config.ServerConfig{Port: 8080},
+		Clusters: map[string]config.ClusterConfig{
+			"source": {Brokers: "localhost:9092"},
+		},
+		Replication: config.ReplicationConfig{TopicConfigSync: config.TopicConfigSyncConfig{
+			Mode:     config.TopicConfigSyncModeDryRun,
+			Interval: "0",
+			Include:  []string{"retention.*", "cleanup.policy"},
+		}},
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Replication.TopicConfigSync.Mode = "enforce"
+	assert.Error(t, cfg.Validate())
+
+	cfg.Replication.TopicConfigSync.Mode = config.TopicConfigSyncModeSync
+	cfg.Replication.TopicConfigSync.Exclude = []string{"retention.["}
+	assert.Error(t, cfg.Validate())
+}
+
+func TestConfigValidate_SourceTopicDeletion(t *testing.T) {
+	cfg := &config.Config{
+		Server: config.ServerConfig{Port: 8080},
+		Clusters: map[string]config.ClusterConfig{
+			"source": {Brokers: "localhost:9092"},
+		},
+		Replication: config.ReplicationConfig{SourceTopicDeletion: config.SourceTopicDeletionConfig{
+			Policy:      config.SourceTopicDeletionDeleteTarget,
+			GracePeriod: "72h",
+		}},
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Replication.SourceTopicDeletion.GracePeriod = "-1h"
+	assert.Error(t, cfg.Validate())
+
+	cfg.Replication.SourceTopicDeletion.GracePeriod = "1h"
+	cfg.Replication.SourceTopicDeletion.Policy = "purge"
+	assert.Error(t, cfg.Validate())
+}
+
+func TestConfigValidate_ACLSync(t *testing.T) {
+	cfg := &config.Config{
+		Server: config.ServerConfig{Port: 8080},
+		Clusters: map[string]config.ClusterConfig{
+			"source": {Brokers: "localhost:9092"},
+		},
+		Replication: config.ReplicationConfig{ACLSync: config.ACLSyncConfig{
+			Groups:            []string{"billing-*"},
+			PrincipalRewrites: []config.PrincipalRewriteRule{{Match: "^User:prod-(.*)$", Replace: "User:dr-$1"}},
+		}},
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Replication.ACLSync.PrincipalRewrites = []config.PrincipalRewriteRule{{Match: "User:(", Replace: "x"}}
+	assert.Error(t, cfg.Validate())
+
+	cfg.Replication.ACLSync.PrincipalRewrites = []config.PrincipalRewriteRule{{Replace: "x"}}
+	assert.Error(t, cfg.Validate())
+
+	cfg.Replication.ACLSync.PrincipalRewrites = nil
+	cfg.Replication.ACLSync.Groups = []string{"[billing"}
+	assert.Error(t, cfg.Validate())
+}
+
+func TestConfigValidate_SchemaRegistry(t *testing.T) {
+	cfg := &config.Config{
+		Server: config.ServerConfig{Port: 8080},
+		Clusters: map[string]config.ClusterConfig{
+			"source": {Brokers: "localhost:9092", SchemaRegistry: config.SchemaRegistryConfig{URL: "http://registry:8081"}},
+			"target": {Brokers: "localhost:9093"},
+		},
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Clusters["target"] = config.ClusterConfig{Brokers: "localhost:9093", SchemaRegistry: config.SchemaRegistryConfig{URL: "registry:8081"}}
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cluster target")
+}
+
+func TestConfigValidate_StartPosition(t *testing.T) {
+	cfg := &config.Config{
+		Server:   config.ServerConfig{Port: 8080},
+		Clusters: map[string]config.ClusterConfig{"source": {Brokers: "localhost:9092"}},
+		Replication: config.ReplicationConfig{
+			StartPosition: config.StartPosition{Mode: config.StartPositionTimestamp, Timestamp: "7d"},
+		},
+		Topics: []config.TopicMapping{
+			{Source: "orders", Enabled: true, StartPosition: config.StartPosition{Mode: config.StartPositionOffsets, Offsets: map[string]map[int32]int64{"orders": {0: 10}}}},
+		},
+	}
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Replication.StartPosition = config.StartPosition{Mode: "newest"}
+	assert.Error(t, cfg.Validate())
+
+	cfg.Replication.StartPosition = config.StartPosition{Mode: config.StartPositionTimestamp, Timestamp: "last week"}
+	assert.Error(t, cfg.Validate())
+
+	cfg.Replication.StartPosition = config.StartPosition{}
+	cfg.Topics[0].StartPosition = config.StartPosition{Mode: config.StartPositionOffsets}
+	err := cfg.Validate()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "topic mapping orders")
+}
+
+func TestStartPosition_Time(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	cases := map[string]time.Time{
+		"7d":                   time.Date(2025, 3, 3, 12, 0, 0, 0, time.UTC),
+		"12h":                  time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+		"2025-03-01T00:00:00Z": time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for timestamp, expected := range cases {
+		got, err := config.StartPosition{Mode: config.StartPositionTimestamp, Timestamp: timestamp}.Time(now)
+		assert.NoError(t, err, timestamp)
+		assert.True(t, expected.Equal(got), "%s: got %s", timestamp, got)
+	}
+	_, err := config.StartPosition{Mode: config.StartPositionTimestamp, Timestamp: "-1h"}.Time(now)
+	assert.Error(t, err)
+}
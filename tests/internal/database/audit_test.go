@@ -0,0 +1,148 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database_test
+
+import (
+	"kaf-mirror/internal/database"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func appendEvents(t *testing.T, db *sqlx.DB, n int) []database.OperationalEvent {
+	t.Helper()
+	events := make([]database.OperationalEvent, 0, n)
+	for i := 0; i < n; i++ {
+		event := database.OperationalEvent{EventType: "job_created", Initiator: "admin", Details: `{"job":"a"}`}
+		require.NoError(t, database.CreateOperationalEvent(db, &event))
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestAuditChain(t *testing.T) {
+	t.Run("ChainsEvents", func(t *testing.T) {
+		db, err := database.InitDB(":memory:")
+		require.NoError(t, err)
+		defer db.Close()
+
+		events := appendEvents(t, db, 3)
+		assert.Equal(t, "", events[0].PrevHash)
+		assert.Equal(t, events[0].Hash, events[1].PrevHash)
+		assert.Equal(t, events[1].Hash, events[2].PrevHash)
+
+		result, err := database.VerifyAuditChain(db, "")
+		require.NoError(t, err)
+		assert.True(t, result.Valid, "issues: %v", result.Issues)
+		assert.Equal(t, 3, result.Checked)
+		assert.Equal(t, events[2].Hash, result.HeadHash)
+	})
+
+	t.Run("DetectsEdit", func(t *testing.T) {
+		db, err := database.InitDB(":memory:")
+		require.NoError(t, err)
+		defer db.Close()
+
+		events := appendEvents(t, db, 3)
+		_, err = db.Exec("UPDATE operational_events SET initiator = 'mallory' WHERE id = ?", events[1].ID)
+		require.NoError(t, err)
+
+		result, err := database.VerifyAuditChain(db, "")
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		require.Len(t, result.Issues, 1)
+		assert.Equal(t, events[1].ID, result.Issues[0].EventID)
+	})
+
+	t.Run("DetectsGap", func(t *testing.T) {
+		db, err := database.InitDB(":memory:")
+		require.NoError(t, err)
+		defer db.Close()
+
+		events := appendEvents(t, db, 3)
+		_, err = db.Exec("DELETE FROM operational_events WHERE id = ?", events[1].ID)
+		require.NoError(t, err)
+
+		result, err := database.VerifyAuditChain(db, "")
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.NotEmpty(t, result.Issues)
+		assert.Equal(t, events[2].ID, result.Issues[0].EventID)
+	})
+
+	t.Run("HMACRequiresKey", func(t *testing.T) {
+		db, err := database.InitDB(":memory:")
+		require.NoError(t, err)
+		defer db.Close()
+
+		database.ConfigureAuditChain("secret")
+		defer database.ConfigureAuditChain("")
+		appendEvents(t, db, 2)
+
+		result, err := database.VerifyAuditChain(db, "secret")
+		require.NoError(t, err)
+		assert.True(t, result.Valid, "issues: %v", result.Issues)
+
+		result, err = database.VerifyAuditChain(db, "wrong")
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+
+		result, err = database.VerifyAuditChain(db, "")
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+	})
+
+	t.Run("LegacyEventsBeforeChain", func(t *testing.T) {
+		db, err := database.InitDB(":memory:")
+		require.NoError(t, err)
+		defer db.Close()
+
+		require.NoError(t, insertOperationalEvent(db, "legacy", "user", time.Now()))
+		appendEvents(t, db, 2)
+
+		result, err := database.VerifyAuditChain(db, "")
+		require.NoError(t, err)
+		assert.True(t, result.Valid, "issues: %v", result.Issues)
+		assert.Equal(t, 1, result.Unchained)
+	})
+}
+
+func TestPruneAuditEvents(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	events := appendEvents(t, db, 4)
+	old := time.Now().AddDate(0, 0, -100)
+	_, err = db.Exec("UPDATE operational_events SET timestamp = ? WHERE id <= ?", old, events[1].ID)
+	require.NoError(t, err)
+
+	deleted, err := database.PruneAuditEvents(db, 90)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+	// Two remaining events plus the audit_pruned anchor.
+	assert.Equal(t, 3, countRows(t, db, "operational_events"))
+
+	result, err := database.VerifyAuditChain(db, "")
+	require.NoError(t, err)
+	assert.True(t, result.Valid, "issues: %v", result.Issues)
+
+	// Removing the anchor leaves the truncated chain unexplained.
+	_, err = db.Exec("DELETE FROM operational_events WHERE event_type = ?", database.AuditPrunedEventType)
+	require.NoError(t, err)
+	result, err = database.VerifyAuditChain(db, "")
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+}
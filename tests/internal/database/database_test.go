@@ -69,6 +69,102 @@ func TestDatabase(t *testing.T) {
 		assert.Equal(t, "a", fetchedMappings[0].SourceTopicPattern)
 	})
 
+	t.Run("StartPositions", func(t *testing.T) {
+		jobID := uuid.NewString()
+		job := &database.ReplicationJob{ID: jobID, Name: "start-job", SourceClusterName: "a", TargetClusterName: "b", Status: "paused",
+			StartPosition: &database.StartPosition{Mode: "timestamp", Timestamp: "7d"}}
+		assert.NoError(t, database.CreateJob(db, job))
+
+		mappings := []database.TopicMapping{
+			{SourceTopicPattern: "a", TargetTopicPattern: "b", Enabled: true,
+				StartPosition: &database.StartPosition{Mode: "offsets", Offsets: map[string]map[int32]int64{"a": {0: 42}}}},
+			{SourceTopicPattern: "c", TargetTopicPattern: "d", Enabled: true},
+		}
+		assert.NoError(t, database.UpdateMappingsForJob(db, jobID, mappings))
+
+		fetchedJob, err := database.GetJob(db, jobID)
+		assert.NoError(t, err)
+		assert.Equal(t, &database.StartPosition{Mode: "timestamp", Timestamp: "7d"}, fetchedJob.StartPosition)
+
+		fetchedMappings, err := database.GetMappingsForJob(db, jobID)
+		assert.NoError(t, err)
+		assert.Len(t, fetchedMappings, 2)
+		assert.Equal(t, int64(42), fetchedMappings[0].StartPosition.Offsets["a"][0])
+		assert.Nil(t, fetchedMappings[1].StartPosition)
+
+		fetchedJob.StartPosition = nil
+		assert.NoError(t, database.UpdateJob(db, fetchedJob))
+		fetchedJob, err = database.GetJob(db, jobID)
+		assert.NoError(t, err)
+		assert.Nil(t, fetchedJob.StartPosition)
+	})
+
+	t.Run("BackfillRanges", func(t *testing.T) {
+		jobID := uuid.NewString()
+		assert.NoError(t, database.CreateJob(db, &database.ReplicationJob{ID: jobID, Name: "backfill-job", SourceClusterName: "a", TargetClusterName: "b", Status: "paused", JobType: database.JobTypeBackfill}))
+		assert.NoError(t, database.CreateBackfillRanges(db, []database.BackfillRange{
+			{JobID: jobID, SourceTopic: "b", PartitionID: 0, StartOffset: 10, EndOffset: 20, GapIDs: database.GapIDs{3, 4}},
+			{JobID: jobID, SourceTopic: "a", PartitionID: 1, StartOffset: 0, EndOffset: 5},
+		}))
+
+		ranges, err := database.GetBackfillRanges(db, jobID)
+		assert.NoError(t, err)
+		assert.Len(t, ranges, 2)
+		assert.Equal(t, "a", ranges[0].SourceTopic)
+		assert.Equal(t, database.GapIDs{}, ranges[0].GapIDs)
+		assert.Equal(t, database.GapIDs{3, 4}, ranges[1].GapIDs)
+		assert.Equal(t, int64(10), ranges[1].NextOffset)
+		assert.Equal(t, database.BackfillRangePending, ranges[1].Status)
+
+		ranges[1].NextOffset = 20
+		ranges[1].Status = database.BackfillRangeCompleted
+		assert.NoError(t, database.UpdateBackfillRange(db, &ranges[1]))
+		ranges, err = database.GetBackfillRanges(db, jobID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(20), ranges[1].NextOffset)
+		assert.Equal(t, database.BackfillRangeCompleted, ranges[1].Status)
+
+		job, err := database.GetJob(db, jobID)
+		assert.NoError(t, err)
+		assert.Equal(t, database.JobTypeBackfill, job.JobType)
+	})
+
+	t.Run("ContentVerifications", func(t *testing.T) {
+		jobID := uuid.NewString()
+		assert.NoError(t, database.CreateJob(db, &database.ReplicationJob{ID: jobID, Name: "verified-job", SourceClusterName: "a", TargetClusterName: "b", Status: "paused"}))
+
+		latest, err := database.GetLatestContentVerification(db, jobID)
+		assert.NoError(t, err)
+		assert.Nil(t, latest)
+
+		v := &database.ContentVerification{JobID: jobID, Status: database.VerificationRunning, SampleRate: 0.5}
+		assert.NoError(t, database.CreateContentVerification(db, v))
+		running, err := database.HasRunningContentVerification(db, jobID)
+		assert.NoError(t, err)
+		assert.True(t, running)
+
+		v.Status = database.VerificationCompleted
+		v.RecordsCompared = 10
+		v.MissingRecords = 1
+		v.Partitions = []database.PartitionVerification{{SourceTopic: "orders", Missing: 1, MissingOffsets: []int64{7}}}
+		assert.NoError(t, database.UpdateContentVerification(db, v))
+		latest, err = database.GetLatestContentVerification(db, jobID)
+		assert.NoError(t, err)
+		assert.Equal(t, v.ID, latest.ID)
+		assert.Equal(t, []int64{7}, latest.Partitions[0].MissingOffsets)
+
+		interrupted := &database.ContentVerification{JobID: jobID, Status: database.VerificationRunning, SampleRate: 1}
+		assert.NoError(t, database.CreateContentVerification(db, interrupted))
+		failed, err := database.FailRunningContentVerifications(db, "interrupted by a restart")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), failed)
+		list, err := database.ListContentVerifications(db, jobID)
+		assert.NoError(t, err)
+		assert.Len(t, list, 2)
+		assert.Equal(t, database.VerificationFailed, list[0].Status)
+		assert.Equal(t, "interrupted by a restart", *list[0].LastError)
+	})
+
 	t.Run("Metrics", func(t *testing.T) {
 		jobID := uuid.NewString()
 		job := &database.ReplicationJob{ID: jobID, Name: "metrics-job", SourceClusterName: "a", TargetClusterName: "b", Status: "paused"}
@@ -95,6 +191,7 @@ func TestDatabase(t *testing.T) {
 			BytesConsumed:      2000,
 			CurrentLag:         12,
 			ErrorCount:         1,
+			DuplicatesSkipped:  7,
 			Timestamp:          time.Now(),
 		}
 		err = database.InsertMetrics(db, metric2)
@@ -108,6 +205,11 @@ func TestDatabase(t *testing.T) {
 		assert.Equal(t, 30, metrics[1].MessagesConsumedDelta)
 		assert.Equal(t, 600, metrics[1].BytesConsumedDelta)
 		assert.Equal(t, 1, metrics[1].ErrorCountDelta)
+		assert.Equal(t, 7, metrics[1].DuplicatesSkippedDelta)
+
+		latest, err := database.GetLatestMetrics(db, jobID)
+		assert.NoError(t, err)
+		assert.Equal(t, 7, latest.DuplicatesSkipped)
 	})
 
 	t.Run("TestConfluentClusterUniqueness", func(t *testing.T) {
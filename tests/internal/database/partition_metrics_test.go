@@ -0,0 +1,119 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database_test
+
+import (
+	"kaf-mirror/internal/database"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func partitionPoint(jobID, topic string, partition int32, ts time.Time, replicated, lag int64, lagSeconds float64) database.PartitionMetric {
+	return database.PartitionMetric{
+		JobID:              jobID,
+		Topic:              topic,
+		Partition:          partition,
+		Timestamp:          ts,
+		Resolution:         10,
+		MessagesConsumed:   replicated,
+		BytesConsumed:      replicated * 100,
+		MessagesReplicated: replicated,
+		BytesTransferred:   replicated * 100,
+		MessageLag:         lag,
+		LagSeconds:         lagSeconds,
+	}
+}
+
+func TestPartitionMetrics_InsertQueryAndSummarize(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	require.NoError(t, database.InsertPartitionMetrics(db, []database.PartitionMetric{
+		partitionPoint("job-a", "orders", 0, now.Add(-20*time.Second), 100, 5, 0.5),
+		partitionPoint("job-a", "orders", 1, now.Add(-20*time.Second), 0, 900, 45),
+		partitionPoint("job-a", "payments", 0, now.Add(-20*time.Second), 10, 0, 0),
+		partitionPoint("job-b", "orders", 0, now.Add(-20*time.Second), 1, 0, 0),
+	}))
+	require.NoError(t, database.InsertPartitionMetrics(db, []database.PartitionMetric{
+		partitionPoint("job-a", "orders", 0, now.Add(-10*time.Second), 50, 0, 0.2),
+		partitionPoint("job-a", "orders", 1, now.Add(-10*time.Second), 0, 950, 55),
+	}))
+
+	series, err := database.GetPartitionMetrics(db, "job-a", "", now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	assert.Len(t, series, 5)
+
+	series, err = database.GetPartitionMetrics(db, "job-a", "orders", now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	assert.Len(t, series, 4)
+
+	summaries := database.SummarizePartitionMetrics(series)
+	require.Len(t, summaries, 2)
+	assert.Equal(t, int64(150), summaries[0].MessagesReplicated)
+	assert.Equal(t, int64(0), summaries[0].MessageLag, "lag comes from the latest point")
+	assert.Equal(t, int64(950), summaries[1].MessageLag)
+	assert.Equal(t, 55.0, summaries[1].LagSeconds)
+	assert.Equal(t, 55.0, summaries[1].MaxLagSeconds)
+
+	hottest := database.HottestPartitions(summaries, 1)
+	require.Len(t, hottest, 1)
+	assert.Equal(t, int32(1), hottest[0].Partition)
+}
+
+func TestDownsamplePartitionMetrics(t *testing.T) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	now := time.Date(2026, 3, 2, 12, 0, 30, 0, time.UTC)
+	old := time.Date(2026, 3, 2, 10, 15, 0, 0, time.UTC) // older than 1h
+	var points []database.PartitionMetric
+	for i := 0; i < 6; i++ {
+		points = append(points, partitionPoint("job-a", "orders", 0, old.Add(time.Duration(i)*10*time.Second), 10, int64(i), float64(i)))
+	}
+	points = append(points, partitionPoint("job-a", "orders", 0, now.Add(-10*time.Minute), 10, 0, 0))
+	points = append(points, partitionPoint("job-a", "orders", 0, now.Add(-3*24*time.Hour), 7, 1, 1))
+	points = append(points, partitionPoint("job-a", "orders", 0, now.Add(-3*24*time.Hour+10*time.Minute), 3, 2, 4))
+	require.NoError(t, database.InsertPartitionMetrics(db, points))
+
+	removed, err := database.DownsamplePartitionMetrics(db, database.PartitionMetricsTiers, now)
+	require.NoError(t, err)
+	assert.Equal(t, 6, removed)
+
+	series, err := database.GetPartitionMetrics(db, "job-a", "", now.Add(-7*24*time.Hour), now)
+	require.NoError(t, err)
+	require.Len(t, series, 3)
+
+	hourly := series[0]
+	assert.Equal(t, 3600, hourly.Resolution)
+	assert.Equal(t, int64(10), hourly.MessagesReplicated)
+	assert.Equal(t, int64(2), hourly.MessageLag)
+	assert.Equal(t, 4.0, hourly.LagSeconds)
+
+	minute := series[1]
+	assert.Equal(t, 60, minute.Resolution)
+	assert.True(t, minute.Timestamp.Equal(old))
+	assert.Equal(t, int64(60), minute.MessagesReplicated)
+	assert.Equal(t, int64(5), minute.MessageLag)
+	assert.Equal(t, 5.0, minute.LagSeconds)
+
+	assert.Equal(t, 10, series[2].Resolution, "recent points stay raw")
+
+	removed, err = database.DownsamplePartitionMetrics(db, database.PartitionMetricsTiers, now)
+	require.NoError(t, err)
+	assert.Equal(t, 0, removed)
+}
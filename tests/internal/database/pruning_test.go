@@ -42,7 +42,8 @@ func TestPruneOldData_UsesRetentionDays(t *testing.T) {
 		assert.NoError(t, err)
 
 		assert.Equal(t, 1, countRows(t, db, "aggregated_metrics"))
-		assert.Equal(t, 1, countRows(t, db, "operational_events"))
+		// Operational events follow the separate audit retention (see PruneAuditEvents).
+		assert.Equal(t, 2, countRows(t, db, "operational_events"))
 	})
 
 	t.Run("ClampToDefault", func(t *testing.T) {
@@ -66,7 +67,8 @@ func TestPruneOldData_UsesRetentionDays(t *testing.T) {
 		assert.NoError(t, err)
 
 		assert.Equal(t, 1, countRows(t, db, "aggregated_metrics"))
-		assert.Equal(t, 1, countRows(t, db, "operational_events"))
+		// Operational events follow the separate audit retention (see PruneAuditEvents).
+		assert.Equal(t, 2, countRows(t, db, "operational_events"))
 	})
 }
 
@@ -0,0 +1,192 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package database_test
+
+import (
+	"kaf-mirror/internal/database"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func insertSLOMinute(t *testing.T, db *sqlx.DB, jobID string, ts time.Time, lagSeconds float64, replicated, errors int) {
+	t.Helper()
+	_, err := db.Exec(`INSERT INTO aggregated_metrics (job_id, timestamp, messages_replicated_delta, bytes_transferred_delta, avg_lag, error_count_delta, lag_seconds_max)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`, jobID, ts, replicated, replicated, 0, errors, lagSeconds)
+	require.NoError(t, err)
+}
+
+func setupSLOJob(t *testing.T) *sqlx.DB {
+	t.Helper()
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	require.NoError(t, database.CreateJob(db, &database.ReplicationJob{
+		ID: "job-a", Name: "orders", SourceClusterName: "src", TargetClusterName: "tgt", Status: "active",
+	}))
+	return db
+}
+
+func TestJobSLO_Validate(t *testing.T) {
+	valid := database.JobSLO{Name: "lag", SLOType: database.SLOTypeLagSeconds, Objective: 99.9, Threshold: 60}
+	assert.NoError(t, valid.Validate())
+
+	gaps := database.JobSLO{Name: "no-gaps", SLOType: database.SLOTypeGaps}
+	assert.NoError(t, gaps.Validate())
+
+	for name, slo := range map[string]database.JobSLO{
+		"missing name":      {SLOType: database.SLOTypeLagSeconds, Objective: 99, Threshold: 1},
+		"unknown type":      {Name: "x", SLOType: "latency", Objective: 99, Threshold: 1},
+		"objective of 100":  {Name: "x", SLOType: database.SLOTypeLagSeconds, Objective: 100, Threshold: 1},
+		"missing threshold": {Name: "x", SLOType: database.SLOTypeErrorRate, Objective: 99},
+		"negative gaps":     {Name: "x", SLOType: database.SLOTypeGaps, Threshold: -1},
+	} {
+		assert.Error(t, slo.Validate(), name)
+	}
+}
+
+func TestJobSLO_CRUD(t *testing.T) {
+	db := setupSLOJob(t)
+	defer db.Close()
+
+	slo := database.JobSLO{JobID: "job-a", Name: "lag", SLOType: database.SLOTypeLagSeconds, Objective: 99.9, Threshold: 60, Enabled: true}
+	require.NoError(t, database.CreateJobSLO(db, &slo))
+	assert.NotZero(t, slo.ID)
+	assert.Error(t, database.CreateJobSLO(db, &database.JobSLO{JobID: "job-a", Name: "lag", SLOType: database.SLOTypeGaps}))
+
+	slo.Threshold = 30
+	require.NoError(t, database.UpdateJobSLO(db, &slo))
+	fetched, err := database.GetJobSLO(db, "job-a", slo.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 30.0, fetched.Threshold)
+
+	_, err = database.GetJobSLO(db, "job-b", slo.ID)
+	assert.Error(t, err, "SLOs are scoped to their job")
+
+	all, err := database.ListJobSLOs(db, "")
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+
+	require.NoError(t, database.DeleteJobSLO(db, "job-a", slo.ID))
+	assert.Error(t, database.DeleteJobSLO(db, "job-a", slo.ID))
+}
+
+func TestEvaluateSLOs_AttainmentAndBudget(t *testing.T) {
+	db := setupSLOJob(t)
+	defer db.Close()
+
+	now := time.Date(2026, 3, 10, 12, 0, 30, 0, time.UTC)
+	// 1000 minutes over the last two weeks, 2 of them 8 days ago and 1 in the last hour above 60s.
+	for i := 0; i < 1000; i++ {
+		ts := now.Add(-time.Duration(i*20) * time.Minute)
+		lag := 5.0
+		if i == 1 || i == 580 || i == 590 {
+			lag = 120
+		}
+		insertSLOMinute(t, db, "job-a", ts, lag, 100, 0)
+	}
+
+	slos := []database.JobSLO{
+		{JobID: "job-a", Name: "lag", SLOType: database.SLOTypeLagSeconds, Objective: 99.5, Threshold: 60, Enabled: true},
+		{JobID: "job-a", Name: "strict", SLOType: database.SLOTypeLagSeconds, Objective: 99.9, Threshold: 60, Enabled: true},
+	}
+	statuses, err := database.EvaluateSLOs(db, slos, now)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+
+	week := statuses[0].Windows[0]
+	assert.Equal(t, "7d", week.Window)
+	assert.Equal(t, 505, week.TotalWindows)
+	assert.Equal(t, 1, week.Bad)
+	assert.True(t, week.Met)
+	assert.InDelta(t, 100*504.0/505, week.AttainmentPercent, 0.0001)
+	// 0.5% of 505 minutes allows 2.525 bad minutes; one is spent.
+	assert.InDelta(t, (2.525-1)/2.525*100, week.ErrorBudgetRemainingPercent, 0.0001)
+
+	month := statuses[0].Windows[1]
+	assert.Equal(t, "30d", month.Window)
+	assert.Equal(t, 1000, month.TotalWindows)
+	assert.Equal(t, 3, month.Bad)
+	assert.True(t, month.Met)
+
+	strict := statuses[1].Windows[1]
+	assert.False(t, strict.Met)
+	assert.Equal(t, 0.0, strict.ErrorBudgetRemainingPercent, "an exhausted budget does not go negative")
+
+	// The last hour has 4 minutes, one of them bad.
+	assert.InDelta(t, (1.0/4)/0.005, statuses[0].BurnRate1h, 0.0001)
+	assert.Equal(t, 0.0, statuses[0].BurnRate5m)
+}
+
+func TestEvaluateSLOs_ErrorRateAndNoData(t *testing.T) {
+	db := setupSLOJob(t)
+	defer db.Close()
+
+	now := time.Date(2026, 3, 10, 12, 0, 30, 0, time.UTC)
+	insertSLOMinute(t, db, "job-a", now.Add(-3*time.Minute), 0, 990, 10)
+	insertSLOMinute(t, db, "job-a", now.Add(-2*time.Minute), 0, 950, 50)
+	insertSLOMinute(t, db, "job-a", now.Add(-2*time.Minute+10*time.Second), 0, 0, 0)
+	insertSLOMinute(t, db, "job-a", now.Add(-1*time.Minute), 0, 0, 0)
+
+	statuses, err := database.EvaluateSLOs(db, []database.JobSLO{
+		{JobID: "job-a", Name: "errors", SLOType: database.SLOTypeErrorRate, Objective: 50, Threshold: 1, Enabled: true},
+		{JobID: "job-b", Name: "idle", SLOType: database.SLOTypeMessageLag, Objective: 99, Threshold: 100, Enabled: true},
+	}, now)
+	require.NoError(t, err)
+
+	errorsWindow := statuses[0].Windows[0]
+	assert.Equal(t, 3, errorsWindow.TotalWindows, "samples in the same minute form one window")
+	assert.Equal(t, 1, errorsWindow.Bad)
+	assert.True(t, errorsWindow.Met)
+
+	idle := statuses[1].Windows[0]
+	assert.True(t, idle.NoData)
+	assert.True(t, idle.Met)
+	assert.Equal(t, 100.0, idle.ErrorBudgetRemainingPercent)
+}
+
+func TestEvaluateSLOs_Gaps(t *testing.T) {
+	db := setupSLOJob(t)
+	defer db.Close()
+
+	now := time.Now().UTC()
+	gap := func(detected time.Time, status string) database.MirrorGap {
+		return database.MirrorGap{
+			JobID: "job-a", SourceTopic: "orders", TargetTopic: "orders", PartitionID: 0,
+			GapStartOffset: 10, GapEndOffset: 20, GapSize: 10, DetectedAt: detected,
+			GapType: "missing_messages", ResolutionStatus: status,
+		}
+	}
+	require.NoError(t, database.DetectMirrorGaps(db, "job-a", []database.MirrorGap{
+		gap(now.Add(-24*time.Hour), "unresolved"),
+		gap(now.Add(-10*24*time.Hour), "in_progress"),
+		gap(now.Add(-2*time.Hour), "resolved"),
+	}))
+
+	slo := database.JobSLO{JobID: "job-a", Name: "no-gaps", SLOType: database.SLOTypeGaps, Threshold: 0, Enabled: true}
+	statuses, err := database.EvaluateSLOs(db, []database.JobSLO{slo}, now)
+	require.NoError(t, err)
+
+	week, month := statuses[0].Windows[0], statuses[0].Windows[1]
+	assert.Equal(t, 1, week.Bad)
+	assert.False(t, week.Met)
+	assert.Equal(t, 2, month.Bad)
+	assert.False(t, month.Met)
+
+	slo.Threshold = 2
+	status, err := database.EvaluateSLOWindow(db, &slo, "report", now.Add(-30*24*time.Hour), now)
+	require.NoError(t, err)
+	assert.True(t, status.Met)
+	assert.Equal(t, 0.0, status.ErrorBudgetRemainingPercent)
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package joblogs_test
+
+import (
+	"fmt"
+	"kaf-mirror/internal/joblogs"
+	"kaf-mirror/pkg/logger"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func record(jobID, level, msg string, at time.Time) logger.Record {
+	return logger.Record{Time: at, Level: level, Message: msg, JobID: jobID}
+}
+
+func messages(entries []joblogs.Entry) []string {
+	out := make([]string, len(entries))
+	for i, e := range entries {
+		out[i] = e.Message
+	}
+	return out
+}
+
+func TestBuffer_KeepsMostRecentRecordsPerJob(t *testing.T) {
+	b := joblogs.NewBuffer(3)
+	now := time.Now()
+	for i := 1; i <= 5; i++ {
+		b.Append(record("a", "INFO", fmt.Sprintf("a%d", i), now))
+	}
+	b.Append(record("b", "INFO", "b1", now))
+	b.Append(record("", "INFO", "global", now))
+
+	assert.Equal(t, []string{"a3", "a4", "a5"}, messages(b.Query("a", joblogs.Query{})))
+	assert.Equal(t, []string{"b1"}, messages(b.Query("b", joblogs.Query{})))
+	assert.Empty(t, b.Query("missing", joblogs.Query{}))
+
+	b.Remove("a")
+	assert.Empty(t, b.Query("a", joblogs.Query{}))
+}
+
+func TestBuffer_QueryFilters(t *testing.T) {
+	b := joblogs.NewBuffer(10)
+	start := time.Now().Add(-time.Hour)
+	b.Append(record("a", "DEBUG", "old debug", start))
+	b.Append(record("a", "WARN", "old warn", start))
+	b.Append(record("a", "INFO", "new info", start.Add(50*time.Minute)))
+	b.Append(record("a", "ERROR", "new error", start.Add(55*time.Minute)))
+
+	all := b.Query("a", joblogs.Query{})
+	require.Len(t, all, 4)
+	assert.Less(t, all[0].Seq, all[3].Seq)
+
+	assert.Equal(t, []string{"old warn", "new error"}, messages(b.Query("a", joblogs.Query{MinLevel: logger.WARN})))
+	assert.Equal(t, []string{"new info", "new error"}, messages(b.Query("a", joblogs.Query{Since: start.Add(30 * time.Minute)})))
+	assert.Equal(t, []string{"new error"}, messages(b.Query("a", joblogs.Query{Limit: 1})))
+	assert.Equal(t, []string{"new info", "new error"}, messages(b.Query("a", joblogs.Query{AfterSeq: all[1].Seq})))
+}
+
+func TestBuffer_SubscribeDeliversNewRecordsAtLevel(t *testing.T) {
+	b := joblogs.NewBuffer(10)
+	b.Append(record("a", "ERROR", "before", time.Now()))
+
+	sub := b.Subscribe("a", logger.WARN)
+	b.Append(record("a", "INFO", "info", time.Now()))
+	b.Append(record("b", "ERROR", "other job", time.Now()))
+	b.Append(record("a", "WARN", "warn", time.Now()))
+
+	select {
+	case e := <-sub.C:
+		assert.Equal(t, "warn", e.Message)
+	case <-time.After(time.Second):
+		t.Fatal("expected a record")
+	}
+	select {
+	case e := <-sub.C:
+		t.Fatalf("unexpected record %q", e.Message)
+	default:
+	}
+
+	sub.Close()
+	sub.Close()
+	_, ok := <-sub.C
+	assert.False(t, ok)
+	b.Append(record("a", "ERROR", "after close", time.Now()))
+}
+
+func TestBuffer_SlowSubscriberDropsInsteadOfBlocking(t *testing.T) {
+	b := joblogs.NewBuffer(1000)
+	sub := b.Subscribe("a", logger.DEBUG)
+	defer sub.Close()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 1000; i++ {
+			b.Append(record("a", "INFO", "x", time.Now()))
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Append blocked on a slow subscriber")
+	}
+	assert.Greater(t, sub.Dropped(), uint64(0))
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka_test
+
+import (
+	"context"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/kafka"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func topicACL(principal, name, pattern, operation string) kafka.ACLEntry {
+	return kafka.ACLEntry{
+		Principal:    principal,
+		Host:         "*",
+		ResourceType: "topic",
+		ResourceName: name,
+		PatternType:  pattern,
+		Operation:    operation,
+		Permission:   "allow",
+	}
+}
+
+func TestACLSyncPolicy_Translate(t *testing.T) {
+	policy, err := kafka.NewACLSyncPolicy(config.ACLSyncConfig{
+		Groups: []string{"billing-*"},
+		PrincipalRewrites: []config.PrincipalRewriteRule{
+			{Match: "^User:prod-(.*)$", Replace: "User:dr-$1"},
+		},
+	})
+	require.NoError(t, err)
+	topicMap := map[string]string{"orders": "orders-dr", "events.a": "events.a", "events.b": "events.b"}
+
+	translated, reason, relevant := policy.Translate(topicACL("User:prod-app", "orders", "literal", "read"), topicMap)
+	assert.True(t, relevant)
+	assert.Empty(t, reason)
+	assert.Equal(t, "User:dr-app", translated.Principal)
+	assert.Equal(t, "orders-dr", translated.ResourceName)
+
+	_, _, relevant = policy.Translate(topicACL("User:app", "payments", "literal", "read"), topicMap)
+	assert.False(t, relevant, "topics the job does not mirror are ignored")
+
+	translated, reason, relevant = policy.Translate(topicACL("User:app", "*", "literal", "describe"), topicMap)
+	assert.True(t, relevant)
+	assert.Empty(t, reason)
+	assert.Equal(t, "*", translated.ResourceName)
+
+	translated, reason, relevant = policy.Translate(topicACL("User:app", "events.", "prefixed", "read"), topicMap)
+	assert.True(t, relevant)
+	assert.Empty(t, reason)
+	assert.Equal(t, "events.", translated.ResourceName)
+
+	_, reason, relevant = policy.Translate(topicACL("User:app", "ord", "prefixed", "read"), topicMap)
+	assert.True(t, relevant)
+	assert.Contains(t, reason, "renamed")
+
+	group := kafka.ACLEntry{Principal: "User:app", Host: "*", ResourceType: "group", ResourceName: "billing-eu", PatternType: "literal", Operation: "read", Permission: "allow"}
+	_, _, relevant = policy.Translate(group, topicMap)
+	assert.True(t, relevant)
+	group.ResourceName = "analytics"
+	_, _, relevant = policy.Translate(group, topicMap)
+	assert.False(t, relevant)
+
+	cluster := kafka.ACLEntry{Principal: "User:app", Host: "*", ResourceType: "cluster", ResourceName: "kafka-cluster", PatternType: "literal", Operation: "alter", Permission: "allow"}
+	_, _, relevant = policy.Translate(cluster, topicMap)
+	assert.False(t, relevant)
+
+	quota := policy.TranslateQuota(kafka.ClientQuota{Entity: map[string]string{"user": "prod-app"}, Values: map[string]float64{"producer_byte_rate": 1024}})
+	assert.Equal(t, map[string]string{"user": "dr-app"}, quota.Entity)
+}
+
+func TestSyncACLs_DryRunThenApply(t *testing.T) {
+	source := &fakeAdmin{
+		info: &kafka.ClusterInfo{},
+		acls: []kafka.ACLEntry{
+			topicACL("User:prod-app", "orders", "literal", "read"),
+			topicACL("User:prod-app", "orders", "literal", "describe"),
+			topicACL("User:other", "payments", "literal", "read"),
+			topicACL("User:app", "ord", "prefixed", "write"),
+		},
+		quotas: []kafka.ClientQuota{
+			{Entity: map[string]string{"user": "prod-app"}, Values: map[string]float64{"producer_byte_rate": 2048, "consumer_byte_rate": 4096}},
+		},
+	}
+	target := &fakeAdmin{
+		info: &kafka.ClusterInfo{},
+		acls: []kafka.ACLEntry{topicACL("User:dr-app", "orders-dr", "literal", "describe")},
+		quotas: []kafka.ClientQuota{
+			{Entity: map[string]string{"user": "dr-app"}, Values: map[string]float64{"producer_byte_rate": 2048, "consumer_byte_rate": 1024}},
+		},
+	}
+	restore := kafka.SetAdminClientFactoryForTest(func(cfg config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		if cfg.Brokers == "source:9092" {
+			return source, nil
+		}
+		return target, nil
+	})
+	t.Cleanup(restore)
+
+	cfg := &config.Config{
+		Clusters: map[string]config.ClusterConfig{
+			"source": {Brokers: "source:9092"},
+			"target": {Brokers: "target:9092"},
+		},
+		Topics: []config.TopicMapping{{Source: "orders", Target: "orders-dr", Enabled: true}},
+		Replication: config.ReplicationConfig{
+			JobID: "test-job",
+			ACLSync: config.ACLSyncConfig{
+				PrincipalRewrites: []config.PrincipalRewriteRule{{Match: "^User:prod-(.*)$", Replace: "User:dr-$1"}},
+				SyncQuotas:        true,
+			},
+		},
+	}
+
+	report, err := kafka.SyncACLs(context.Background(), cfg, true)
+	require.NoError(t, err)
+	assert.Equal(t, "test-job", report.JobID)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 3, report.SourceACLs)
+	assert.Equal(t, []kafka.ACLEntry{topicACL("User:dr-app", "orders-dr", "literal", "read")}, report.Missing)
+	require.Len(t, report.Skipped, 1)
+	require.Len(t, report.QuotaDrift, 1)
+	assert.Equal(t, "consumer_byte_rate", report.QuotaDrift[0].Key)
+	assert.Equal(t, 1024.0, *report.QuotaDrift[0].TargetValue)
+	assert.Len(t, target.acls, 1, "dry run must not create ACLs")
+	assert.Empty(t, target.altQuotas)
+
+	report, err = kafka.SyncACLs(context.Background(), cfg, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.CreatedACLs)
+	assert.Equal(t, 1, report.AppliedQuotas)
+	assert.Contains(t, target.acls, topicACL("User:dr-app", "orders-dr", "literal", "read"))
+	require.Len(t, target.altQuotas, 1)
+	assert.Equal(t, map[string]float64{"consumer_byte_rate": 4096}, target.altQuotas[0].Values)
+
+	report, err = kafka.SyncACLs(context.Background(), cfg, true)
+	require.NoError(t, err)
+	assert.Empty(t, report.Missing)
+}
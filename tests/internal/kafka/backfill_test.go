@@ -0,0 +1,119 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/tests/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// backfillFixture is a backfill mirror whose produce acknowledgements are held back.
+type backfillFixture struct {
+	mirror   *kafka.KafMirrorImpl
+	acks     map[int64]func(*kgo.Record, error)
+	records  map[int64]*kgo.Record
+	removed  []map[string][]int32
+	reports  [][]kafka.BackfillProgress // final reports
+	produced []int64
+}
+
+func newBackfillFixture(ranges ...config.BackfillRange) *backfillFixture {
+	f := &backfillFixture{acks: map[int64]func(*kgo.Record, error){}, records: map[int64]*kgo.Record{}}
+	producer := &kafka.Producer{Client: &mocks.MockKgoClient{
+		ProduceFunc: func(ctx context.Context, r *kgo.Record, cb func(*kgo.Record, error)) {
+			offset := int64(r.Headers[0].Value[0])
+			f.produced = append(f.produced, offset)
+			f.records[offset] = r
+			f.acks[offset] = cb
+		},
+	}}
+	consumer := &kafka.Consumer{Client: &mocks.MockKgoClient{
+		RemoveConsumePartitionsFunc: func(partitions map[string][]int32) { f.removed = append(f.removed, partitions) },
+	}}
+	f.mirror = kafka.NewKafMirrorImplForTest(producer, map[string]string{"orders": "dr.orders"}, map[string]int32{"dr.orders": 2})
+	f.mirror.SetBackfillForTest(consumer, ranges)
+	f.mirror.OnBackfillProgress(func(jobID string, progress []kafka.BackfillProgress, done bool) {
+		if done {
+			f.reports = append(f.reports, progress)
+		}
+	})
+	return f
+}
+
+func (f *backfillFixture) consume(partition int32, offset int64) {
+	// The source offset travels in a header so the producer mock can find it.
+	f.mirror.HandleRecordForTest(&kgo.Record{
+		Topic:     "orders",
+		Partition: partition,
+		Offset:    offset,
+		Value:     []byte("payload"),
+		Headers:   []kgo.RecordHeader{{Key: "offset", Value: []byte{byte(offset)}}},
+	})
+}
+
+func (f *backfillFixture) ack(offset int64, err error) {
+	f.acks[offset](f.records[offset], err)
+}
+
+func TestBackfill_StopsAtRangeEndAfterAcknowledgement(t *testing.T) {
+	f := newBackfillFixture(
+		config.BackfillRange{Topic: "orders", Partition: 0, StartOffset: 2, EndOffset: 5, NextOffset: 2},
+		config.BackfillRange{Topic: "orders", Partition: 1, StartOffset: 0, EndOffset: 4, NextOffset: 4},
+	)
+
+	f.consume(0, 2)
+	f.consume(0, 3)
+	assert.Empty(t, f.removed)
+	f.consume(0, 4)
+	assert.Equal(t, []map[string][]int32{{"orders": {0}}}, f.removed, "the partition is no longer read once its range is consumed")
+	f.consume(0, 5)
+	assert.Equal(t, []int64{2, 3, 4}, f.produced, "records after the range are not copied")
+
+	f.ack(4, nil)
+	f.ack(3, nil)
+	progress := f.mirror.BackfillProgressForTest()
+	assert.Equal(t, int64(2), progress[0].NextOffset, "progress waits for the oldest record in flight")
+	assert.False(t, progress[0].Done)
+	assert.True(t, progress[1].Done, "a range copied by an earlier run is done")
+	assert.Empty(t, f.reports)
+
+	f.ack(2, nil)
+	require.Len(t, f.reports, 1, "completion is reported once")
+	assert.Equal(t, kafka.BackfillProgress{Topic: "orders", Partition: 0, NextOffset: 5, Done: true}, f.reports[0][0])
+}
+
+func TestBackfill_ProduceErrorStopsPartition(t *testing.T) {
+	f := newBackfillFixture(config.BackfillRange{Topic: "orders", Partition: 0, StartOffset: 0, EndOffset: 10, NextOffset: 0})
+
+	f.consume(0, 0)
+	f.consume(0, 1)
+	f.ack(0, errors.New("record too large"))
+	assert.Empty(t, f.reports, "a record is still in flight")
+	f.ack(1, nil)
+	require.Len(t, f.reports, 1, "the backfill finishes once the failed partition is drained")
+
+	f.consume(0, 2)
+	assert.Equal(t, []int64{0, 1}, f.produced, "records after a failure are not copied")
+	assert.Equal(t, []map[string][]int32{{"orders": {0}}}, f.removed)
+	require.Len(t, f.reports, 1)
+	assert.Equal(t, int64(0), f.reports[0][0].NextOffset, "copying resumes at the failed record")
+	assert.False(t, f.reports[0][0].Done)
+	assert.Equal(t, "record too large", f.reports[0][0].Error)
+}
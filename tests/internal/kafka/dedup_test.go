@@ -0,0 +1,145 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka_test
+
+import (
+	"context"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/tests/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// newDedupMirror returns a mirror of orders to dr.orders whose target partition
+// holds targetRecords, and the records it produces.
+func newDedupMirror(t *testing.T, targetRecords []*kgo.Record) (*kafka.KafMirrorImpl, *[]*kgo.Record) {
+	target := &fakeAdmin{hwms: map[string][]kafka.OffsetInfo{"dr.orders": offsets("dr.orders", int64(len(targetRecords)))}}
+	t.Cleanup(kafka.SetAdminClientFactoryForTest(func(config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		return target, nil
+	}))
+	t.Cleanup(kafka.SetRecordReaderFactoryForTest(func(config.ClusterConfig, config.ReplicationConfig, string) (kafka.RecordReader, error) {
+		return &fakeReader{records: targetRecords}, nil
+	}))
+
+	var produced []*kgo.Record
+	producer := &kafka.Producer{Client: &mocks.MockKgoClient{
+		ProduceFunc: func(ctx context.Context, r *kgo.Record, cb func(*kgo.Record, error)) {
+			produced = append(produced, r)
+		},
+	}}
+	mirror := kafka.NewKafMirrorImplForTest(producer, map[string]string{"orders": "dr.orders"}, map[string]int32{"dr.orders": 1})
+	require.NoError(t, mirror.EnableDedupForTest(&config.Config{
+		Clusters: map[string]config.ClusterConfig{
+			"source": {Brokers: "source:9092"},
+			"target": {Brokers: "target:9092"},
+		},
+		Replication: config.ReplicationConfig{JobID: "job-1", Dedup: config.DedupConfig{Enabled: true, TailRecords: 100}},
+	}))
+	return mirror, &produced
+}
+
+func provenanceOf(record *kgo.Record) string {
+	for _, header := range record.Headers {
+		if header.Key == kafka.ProvenanceHeader {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+func TestDedup_SkipsRecordsNamedByProvenanceHeaders(t *testing.T) {
+	// Source offsets 5 to 9 reached the target before the crash.
+	var targetRecords []*kgo.Record
+	for i := int64(0); i < 5; i++ {
+		record := verifyRecord("dr.orders", i, fmt.Sprintf("v%d", i+5))
+		record.Headers = append(record.Headers, kgo.RecordHeader{Key: kafka.ProvenanceHeader, Value: []byte(fmt.Sprintf("orders/0/%d", i+5))})
+		targetRecords = append(targetRecords, record)
+	}
+	mirror, produced := newDedupMirror(t, targetRecords)
+
+	// The consumer resumes from its last committed offset, 5.
+	for _, record := range partitionRecords("orders", 5, recordValues(5, 13)...) {
+		mirror.HandleRecordForTest(record)
+	}
+
+	require.Len(t, *produced, 3)
+	assert.Equal(t, "orders/0/10", provenanceOf((*produced)[0]))
+	assert.Equal(t, "orders/0/12", provenanceOf((*produced)[2]))
+	assert.Equal(t, int64(5), mirror.DuplicatesSkippedForTest())
+}
+
+func TestDedup_MatchesRecordsWithoutProvenanceByContent(t *testing.T) {
+	// Written before dedup was enabled, so only the content identifies them.
+	mirror, produced := newDedupMirror(t, partitionRecords("dr.orders", 0, "v0", "v1", "v2"))
+
+	for _, record := range partitionRecords("orders", 1, recordValues(1, 5)...) {
+		mirror.HandleRecordForTest(record)
+	}
+
+	require.Len(t, *produced, 2)
+	assert.Equal(t, []byte("v3"), (*produced)[0].Value)
+	assert.Equal(t, []byte("v4"), (*produced)[1].Value)
+	assert.Equal(t, int64(2), mirror.DuplicatesSkippedForTest())
+}
+
+func TestDedup_StopsCheckingAPartitionAfterTheFirstNewRecord(t *testing.T) {
+	mirror, produced := newDedupMirror(t, partitionRecords("dr.orders", 0, "v0", "v1"))
+
+	// v0 shows up again later; it is a new record, not a leftover of the restart.
+	mirror.HandleRecordForTest(verifyRecord("orders", 2, "v2"))
+	mirror.HandleRecordForTest(verifyRecord("orders", 3, "v0"))
+
+	require.Len(t, *produced, 2)
+	assert.Zero(t, mirror.DuplicatesSkippedForTest())
+}
+
+func TestDedup_ReplacesUpstreamProvenance(t *testing.T) {
+	mirror, produced := newDedupMirror(t, nil)
+
+	record := verifyRecord("orders", 7, "v7")
+	record.Headers = append(record.Headers, kgo.RecordHeader{Key: kafka.ProvenanceHeader, Value: []byte("upstream/3/99")})
+	mirror.HandleRecordForTest(record)
+
+	require.Len(t, *produced, 1)
+	count := 0
+	for _, header := range (*produced)[0].Headers {
+		if header.Key == kafka.ProvenanceHeader {
+			count++
+		}
+	}
+	assert.Equal(t, 1, count)
+	assert.Equal(t, "orders/0/7", provenanceOf((*produced)[0]))
+	assert.Equal(t, "upstream/3/99", string(record.Headers[1].Value), "the source record is left alone")
+}
+
+func TestDedup_KeepsProvenanceOnTracedRecords(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	mirror, produced := newDedupMirror(t, nil)
+	mirror.SetTraceSampleRatioForTest(1)
+
+	upstream := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	record := verifyRecord("orders", 7, "v7")
+	record.Headers = append(record.Headers, kgo.RecordHeader{Key: "traceparent", Value: []byte(upstream)})
+	mirror.HandleRecordForTest(record)
+
+	require.Len(t, *produced, 1)
+	assert.Equal(t, "orders/0/7", provenanceOf((*produced)[0]))
+	traceparent := kafka.RecordHeaderCarrier{Record: (*produced)[0]}.Get("traceparent")
+	assert.Contains(t, traceparent, "4bf92f3577b34da6a3ce929d0e0e4736")
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka_test
+
+import (
+	"context"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/tests/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type discoveryFixture struct {
+	admin   *fakeAdmin
+	mirror  *kafka.KafMirrorImpl
+	changes []kafka.TopicChange
+	added   []string
+	purged  []string
+}
+
+func newDiscoveryFixture(t *testing.T, deletion config.SourceTopicDeletionConfig, sourcePartitions, targetPartitions int32) *discoveryFixture {
+	f := &discoveryFixture{
+		admin: &fakeAdmin{
+			info: &kafka.ClusterInfo{
+				BrokerCount: 3,
+				Topics: map[string]kafka.TopicInfo{
+					"orders":    {Name: "orders", Partitions: sourcePartitions, ReplicationFactor: 3},
+					"orders-dr": {Name: "orders-dr", Partitions: targetPartitions, ReplicationFactor: 3},
+				},
+			},
+		},
+	}
+	restore := kafka.SetAdminClientFactoryForTest(func(cfg config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		return f.admin, nil
+	})
+	t.Cleanup(restore)
+
+	consumer := &kafka.Consumer{Client: &mocks.MockKgoClient{
+		AddConsumeTopicsFunc:         func(topics ...string) { f.added = append(f.added, topics...) },
+		PurgeTopicsFromConsumingFunc: func(topics ...string) { f.purged = append(f.purged, topics...) },
+	}}
+	cfg := &config.Config{
+		Clusters: map[string]config.ClusterConfig{
+			"source": {Brokers: "localhost:9092"},
+			"target": {Brokers: "localhost:9093"},
+		},
+		Replication: config.ReplicationConfig{
+			JobID:               "job-a",
+			TopicConfigSync:     config.TopicConfigSyncConfig{Mode: config.TopicConfigSyncModeOff},
+			SourceTopicDeletion: deletion,
+		},
+		Topics: []config.TopicMapping{{Source: "orders", Target: "orders-dr", Enabled: true}},
+	}
+	mirror, err := kafka.NewDiscoveryMirrorForTest(cfg, consumer, map[string]string{"orders": "orders-dr"}, map[string]int32{"orders-dr": targetPartitions})
+	require.NoError(t, err)
+	mirror.OnTopicChange(func(jobID string, change kafka.TopicChange) {
+		assert.Equal(t, "job-a", jobID)
+		f.changes = append(f.changes, change)
+	})
+	f.mirror = mirror
+	return f
+}
+
+func (f *discoveryFixture) discover(t *testing.T) {
+	require.NoError(t, f.mirror.DiscoverTopicsForTest(context.Background()))
+}
+
+func (f *discoveryFixture) changeTypes() []string {
+	types := make([]string, len(f.changes))
+	for i, change := range f.changes {
+		types[i] = change.Type
+	}
+	return types
+}
+
+func TestDiscovery_GrowsTargetPartitions(t *testing.T) {
+	f := newDiscoveryFixture(t, config.SourceTopicDeletionConfig{}, 24, 12)
+	f.discover(t)
+
+	assert.Equal(t, map[string]int32{"orders-dr": 24}, f.admin.grown)
+	_, partitions := f.mirror.TopicStateForTest()
+	assert.Equal(t, int32(24), partitions["orders-dr"])
+	assert.Equal(t, []string{kafka.TopicChangePartitionsIncreased}, f.changeTypes())
+
+	// Nothing left to do on the next pass.
+	f.discover(t)
+	assert.Len(t, f.changes, 1)
+}
+
+func TestDiscovery_SourceDeletionStopAndResume(t *testing.T) {
+	f := newDiscoveryFixture(t, config.SourceTopicDeletionConfig{Policy: config.SourceTopicDeletionStop}, 12, 12)
+	delete(f.admin.info.Topics, "orders")
+	f.discover(t)
+	f.discover(t)
+
+	topicMap, partitions := f.mirror.TopicStateForTest()
+	assert.Empty(t, topicMap)
+	assert.Empty(t, partitions)
+	assert.Equal(t, []string{"orders"}, f.purged)
+	assert.Equal(t, []string{kafka.TopicChangeMirroringStopped}, f.changeTypes())
+	assert.Empty(t, f.admin.deleted)
+
+	f.admin.info.Topics["orders"] = kafka.TopicInfo{Name: "orders", Partitions: 12, ReplicationFactor: 3}
+	f.discover(t)
+
+	topicMap, _ = f.mirror.TopicStateForTest()
+	assert.Equal(t, map[string]string{"orders": "orders-dr"}, topicMap)
+	assert.Equal(t, []string{"orders"}, f.added)
+	assert.Equal(t, []string{kafka.TopicChangeMirroringStopped, kafka.TopicChangeMirroringResumed}, f.changeTypes())
+}
+
+func TestDiscovery_SourceDeletionIgnore(t *testing.T) {
+	f := newDiscoveryFixture(t, config.SourceTopicDeletionConfig{Policy: config.SourceTopicDeletionIgnore}, 12, 12)
+	delete(f.admin.info.Topics, "orders")
+	f.discover(t)
+	f.discover(t)
+
+	topicMap, _ := f.mirror.TopicStateForTest()
+	assert.Equal(t, map[string]string{"orders": "orders-dr"}, topicMap)
+	assert.Empty(t, f.purged)
+	assert.Equal(t, []string{kafka.TopicChangeSourceDeleted}, f.changeTypes())
+}
+
+func TestDiscovery_DeleteTargetAfterGracePeriod(t *testing.T) {
+	f := newDiscoveryFixture(t, config.SourceTopicDeletionConfig{Policy: config.SourceTopicDeletionDeleteTarget, GracePeriod: "0s"}, 12, 12)
+	delete(f.admin.info.Topics, "orders")
+	f.discover(t)
+
+	assert.Equal(t, []string{"orders-dr"}, f.admin.deleted)
+	assert.Equal(t, []string{
+		kafka.TopicChangeMirroringStopped,
+		kafka.TopicChangeTargetDeletionScheduled,
+		kafka.TopicChangeTargetDeleted,
+	}, f.changeTypes())
+}
+
+func TestDiscovery_DeleteTargetCancelledWhenSourceReturns(t *testing.T) {
+	f := newDiscoveryFixture(t, config.SourceTopicDeletionConfig{Policy: config.SourceTopicDeletionDeleteTarget, GracePeriod: "1h"}, 12, 12)
+	delete(f.admin.info.Topics, "orders")
+	f.discover(t)
+	assert.Empty(t, f.admin.deleted)
+
+	f.admin.info.Topics["orders"] = kafka.TopicInfo{Name: "orders", Partitions: 12, ReplicationFactor: 3}
+	f.discover(t)
+
+	assert.Empty(t, f.admin.deleted)
+	assert.Equal(t, []string{
+		kafka.TopicChangeMirroringStopped,
+		kafka.TopicChangeTargetDeletionScheduled,
+		kafka.TopicChangeMirroringResumed,
+		kafka.TopicChangeTargetDeletionCancelled,
+	}, f.changeTypes())
+}
+
+func TestValidateAndSyncClusters_MissingSourceTopic(t *testing.T) {
+	admin := &fakeAdmin{info: &kafka.ClusterInfo{Topics: map[string]kafka.TopicInfo{}}}
+	restore := kafka.SetAdminClientFactoryForTest(func(cfg config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		return admin, nil
+	})
+	t.Cleanup(restore)
+
+	cfg := &config.Config{
+		Clusters: map[string]config.ClusterConfig{
+			"source": {Brokers: "localhost:9092"},
+			"target": {Brokers: "localhost:9093"},
+		},
+		Replication: config.ReplicationConfig{JobID: "test-job"},
+	}
+
+	topicMap := map[string]string{"orders": "orders-dr"}
+	_, err := kafka.ValidateAndSyncClustersForTest(cfg, []string{"orders"}, topicMap)
+	require.NoError(t, err)
+	assert.Empty(t, topicMap)
+	assert.Empty(t, admin.ensureCalls)
+
+	cfg.Replication.SourceTopicDeletion.Policy = config.SourceTopicDeletionIgnore
+	_, err = kafka.ValidateAndSyncClustersForTest(cfg, []string{"orders"}, map[string]string{"orders": "orders-dr"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,154 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka_test
+
+import (
+	"context"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/tests/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const testDeadLetterTopic = "kaf-mirror-dlq-job-1"
+
+// deadLetterRecords returns count records of the dead-letter topic as the mirror
+// writes them, failed a minute apart.
+func deadLetterRecords(count int) []*kgo.Record {
+	failedAt := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	records := make([]*kgo.Record, count)
+	for i := range records {
+		records[i] = &kgo.Record{
+			Topic:  testDeadLetterTopic,
+			Offset: int64(i),
+			Value:  []byte(fmt.Sprintf("v%d", i)),
+			Headers: []kgo.RecordHeader{
+				{Key: "trace", Value: []byte("abc")},
+				{Key: kafka.DeadLetterHeaderSourceTopic, Value: []byte("orders")},
+				{Key: kafka.DeadLetterHeaderSourcePartition, Value: []byte("0")},
+				{Key: kafka.DeadLetterHeaderSourceOffset, Value: []byte(fmt.Sprint(100 + i))},
+				{Key: kafka.DeadLetterHeaderTargetTopic, Value: []byte("dr.orders")},
+				{Key: kafka.DeadLetterHeaderErrorClass, Value: []byte(config.ErrorClassRecordTooLarge)},
+				{Key: kafka.DeadLetterHeaderError, Value: []byte("MESSAGE_TOO_LARGE")},
+				{Key: kafka.DeadLetterHeaderAttempts, Value: []byte("1")},
+				{Key: kafka.DeadLetterHeaderFailedAt, Value: []byte(failedAt.Add(time.Duration(i) * time.Minute).Format(time.RFC3339Nano))},
+			},
+		}
+	}
+	return records
+}
+
+// deadLetterJob points the admin client and record reader factories at a
+// dead-letter topic holding records, of which replayed were replayed before.
+func deadLetterJob(t *testing.T, records []*kgo.Record, replayed int64) (*config.Config, *fakeAdmin) {
+	admin := &fakeAdmin{
+		hwms: map[string][]kafka.OffsetInfo{testDeadLetterTopic: offsets(testDeadLetterTopic, int64(len(records)))},
+		groups: map[string]map[string][]kafka.OffsetInfo{
+			kafka.DeadLetterReplayGroup("job-1"): {testDeadLetterTopic: offsets(testDeadLetterTopic, replayed)},
+		},
+	}
+	t.Cleanup(kafka.SetAdminClientFactoryForTest(func(config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		return admin, nil
+	}))
+	t.Cleanup(kafka.SetRecordReaderFactoryForTest(func(config.ClusterConfig, config.ReplicationConfig, string) (kafka.RecordReader, error) {
+		return &fakeReader{records: records}, nil
+	}))
+	cfg := &config.Config{
+		Clusters: map[string]config.ClusterConfig{
+			"source": {Brokers: "source:9092"},
+			"target": {Brokers: "target:9092"},
+		},
+		Replication: config.ReplicationConfig{
+			JobID:       "job-1",
+			ErrorPolicy: config.ErrorPolicy{Action: config.ErrorActionDeadLetter},
+		},
+	}
+	return cfg, admin
+}
+
+func TestReadDeadLetters_ReturnsNewestFirstWithMetadata(t *testing.T) {
+	cfg, _ := deadLetterJob(t, deadLetterRecords(5), 2)
+
+	list, err := kafka.ReadDeadLetters(context.Background(), cfg, 3)
+	require.NoError(t, err)
+
+	assert.Equal(t, "target", list.Cluster)
+	assert.Equal(t, testDeadLetterTopic, list.Topic)
+	assert.Equal(t, int64(5), list.Total)
+	assert.Equal(t, int64(3), list.Pending)
+	require.Len(t, list.Records, 3)
+	assert.Equal(t, int64(4), list.Records[0].Offset)
+	assert.Equal(t, int64(104), list.Records[0].SourceOffset)
+	assert.Equal(t, "dr.orders", list.Records[0].TargetTopic)
+	assert.Equal(t, config.ErrorClassRecordTooLarge, list.Records[0].ErrorClass)
+	assert.Equal(t, "MESSAGE_TOO_LARGE", list.Records[0].Error)
+	assert.False(t, list.Records[0].Replayed)
+	assert.Equal(t, int64(2), list.Records[2].Offset)
+}
+
+func TestReplayDeadLetters_ProducesToTargetAndCommitsProgress(t *testing.T) {
+	cfg, admin := deadLetterJob(t, deadLetterRecords(5), 1)
+	producer, accepted := recordingProducer(0, nil)
+	t.Cleanup(kafka.SetProducerFactoryForTest(func(config.ClusterConfig, config.ReplicationConfig, string) (*kafka.Producer, error) {
+		return producer, nil
+	}))
+
+	result, err := kafka.ReplayDeadLetters(context.Background(), cfg, 10)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, result.Replayed)
+	assert.Zero(t, result.Failed)
+	assert.Zero(t, result.Pending)
+	require.Len(t, accepted(), 4)
+	replayed := accepted()[0]
+	assert.Equal(t, "dr.orders", replayed.Topic)
+	assert.Equal(t, "v1", string(replayed.Value))
+	assert.Equal(t, []kgo.RecordHeader{{Key: "trace", Value: []byte("abc")}}, replayed.Headers)
+	assert.Equal(t, map[string]map[int32]int64{testDeadLetterTopic: {0: 5}}, admin.commits[kafka.DeadLetterReplayGroup("job-1")])
+}
+
+func TestReplayDeadLetters_StopsAtFirstFailure(t *testing.T) {
+	cfg, admin := deadLetterJob(t, deadLetterRecords(5), 0)
+	var produced []string
+	producer := &kafka.Producer{Client: &mocks.MockKgoClient{
+		ProduceFunc: func(ctx context.Context, r *kgo.Record, cb func(*kgo.Record, error)) {
+			produced = append(produced, string(r.Value))
+			if string(r.Value) == "v2" {
+				cb(r, kerr.MessageTooLarge)
+				return
+			}
+			cb(r, nil)
+		},
+	}}
+	t.Cleanup(kafka.SetProducerFactoryForTest(func(config.ClusterConfig, config.ReplicationConfig, string) (*kafka.Producer, error) {
+		return producer, nil
+	}))
+
+	result, err := kafka.ReplayDeadLetters(context.Background(), cfg, 10)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Replayed)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, int64(3), result.Pending)
+	require.Len(t, result.Errors, 1)
+	assert.Contains(t, result.Errors[0], "offset 2")
+	assert.Equal(t, []string{"v0", "v1", "v2"}, produced)
+	// The failed record is replayed first next time.
+	assert.Equal(t, map[string]map[int32]int64{testDeadLetterTopic: {0: 2}}, admin.commits[kafka.DeadLetterReplayGroup("job-1")])
+}
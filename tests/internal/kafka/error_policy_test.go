@@ -0,0 +1,369 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka_test
+
+import (
+	"context"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/tests/mocks"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// recordingProducer returns a producer that fails the first failures records it is
+// given with err, and the records it accepted.
+func recordingProducer(failures int, err error) (*kafka.Producer, func() []*kgo.Record) {
+	var mu sync.Mutex
+	var accepted []*kgo.Record
+	attempts := 0
+	producer := &kafka.Producer{Client: &mocks.MockKgoClient{
+		ProduceFunc: func(ctx context.Context, r *kgo.Record, cb func(*kgo.Record, error)) {
+			mu.Lock()
+			attempts++
+			fail := attempts <= failures
+			if !fail {
+				accepted = append(accepted, r)
+			}
+			mu.Unlock()
+			if fail {
+				cb(r, err)
+				return
+			}
+			cb(r, nil)
+		},
+	}}
+	return producer, func() []*kgo.Record {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]*kgo.Record(nil), accepted...)
+	}
+}
+
+func newPolicyMirror(producer *kafka.Producer) *kafka.KafMirrorImpl {
+	return kafka.NewKafMirrorImplForTest(producer, map[string]string{"orders": "dr.orders"}, map[string]int32{"dr.orders": 1})
+}
+
+func headerValue(record *kgo.Record, key string) string {
+	for _, header := range record.Headers {
+		if header.Key == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+func TestClassifyProduceError(t *testing.T) {
+	cases := map[error]string{
+		kerr.MessageTooLarge:                          config.ErrorClassRecordTooLarge,
+		kerr.TopicAuthorizationFailed:                 config.ErrorClassAuthorization,
+		kerr.UnknownTopicOrPartition:                  config.ErrorClassUnknownTopic,
+		kerr.CorruptMessage:                           config.ErrorClassInvalidRecord,
+		kgo.ErrRecordTimeout:                          config.ErrorClassTimeout,
+		fmt.Errorf("wrapped: %w", kerr.InvalidRecord): config.ErrorClassInvalidRecord,
+		fmt.Errorf("broker went away"):                config.ErrorClassOther,
+	}
+	for err, class := range cases {
+		assert.Equal(t, class, kafka.ClassifyProduceError(err), err.Error())
+	}
+}
+
+func TestErrorPolicy_RetriesUntilProduced(t *testing.T) {
+	producer, accepted := recordingProducer(2, kerr.RequestTimedOut)
+	mirror := newPolicyMirror(producer)
+	mirror.SetErrorPolicyForTest(config.ErrorPolicy{
+		Classes:      map[string]string{config.ErrorClassTimeout: config.ErrorActionRetry},
+		MaxRetries:   3,
+		RetryBackoff: "1ms",
+	}, nil, nil)
+
+	mirror.HandleRecordForTest(verifyRecord("orders", 7, "v7"))
+
+	require.Eventually(t, func() bool { return len(accepted()) == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, "v7", string(accepted()[0].Value))
+	assert.Equal(t, map[string]int{config.ErrorClassTimeout: 2}, mirror.ErrorClassCountsForTest())
+	assert.Zero(t, mirror.DeadLetteredForTest())
+}
+
+func TestErrorPolicy_RetryKeepsPartitionOrder(t *testing.T) {
+	producer, accepted := recordingProducer(1, kerr.RequestTimedOut)
+	mirror := newPolicyMirror(producer)
+	var mu sync.Mutex
+	var paused, resumed []map[string][]int32
+	mirror.Consumer = &kafka.Consumer{Client: &mocks.MockKgoClient{
+		PauseFetchPartitionsFunc: func(p map[string][]int32) {
+			mu.Lock()
+			defer mu.Unlock()
+			paused = append(paused, p)
+		},
+		ResumeFetchPartitionsFunc: func(p map[string][]int32) {
+			mu.Lock()
+			defer mu.Unlock()
+			resumed = append(resumed, p)
+		},
+	}}
+	mirror.SetErrorPolicyForTest(config.ErrorPolicy{Action: config.ErrorActionRetry, RetryBackoff: "20ms"}, nil, nil)
+
+	for _, record := range partitionRecords("orders", 0, "v0", "v1", "v2") {
+		mirror.HandleRecordForTest(record)
+	}
+	assert.Empty(t, accepted(), "later records wait for the retried one")
+
+	require.Eventually(t, func() bool { return len(accepted()) == 3 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{"v0", "v1", "v2"}, recordValuesOf(accepted()))
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(resumed) == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []map[string][]int32{{"orders": {0}}}, paused)
+	assert.Equal(t, []map[string][]int32{{"orders": {0}}}, resumed)
+
+	// The partition is no longer held.
+	mirror.HandleRecordForTest(verifyRecord("orders", 3, "v3"))
+	assert.Len(t, accepted(), 4)
+}
+
+// Held records are drained off the consumer goroutine while it keeps mirroring the
+// other partitions, both through the dedup filter. Run with -race.
+func TestErrorPolicy_DrainRunsAlongsideOtherPartitions(t *testing.T) {
+	const partitions = 1000
+	t.Cleanup(kafka.SetAdminClientFactoryForTest(func(config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		return &fakeAdmin{hwms: map[string][]kafka.OffsetInfo{"dr.orders": offsets("dr.orders", make([]int64, partitions)...)}}, nil
+	}))
+	t.Cleanup(kafka.SetRecordReaderFactoryForTest(func(config.ClusterConfig, config.ReplicationConfig, string) (kafka.RecordReader, error) {
+		return &fakeReader{}, nil
+	}))
+	var mu sync.Mutex
+	var produced []*kgo.Record
+	accepted := func() []*kgo.Record {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]*kgo.Record(nil), produced...)
+	}
+	failed := false
+	producer := &kafka.Producer{Client: &mocks.MockKgoClient{
+		ProduceFunc: func(ctx context.Context, r *kgo.Record, cb func(*kgo.Record, error)) {
+			mu.Lock()
+			fail := !failed
+			failed = true
+			if !fail {
+				produced = append(produced, r)
+			}
+			mu.Unlock()
+			if fail {
+				cb(r, kerr.RequestTimedOut)
+				return
+			}
+			// Slows the drain of partition 0 so it overlaps the other partitions.
+			if r.Partition == 0 {
+				time.Sleep(100 * time.Microsecond)
+			}
+			cb(r, nil)
+		},
+	}}
+	mirror := kafka.NewKafMirrorImplForTest(producer, map[string]string{"orders": "dr.orders"}, map[string]int32{"dr.orders": partitions})
+	require.NoError(t, mirror.EnableDedupForTest(&config.Config{
+		Clusters:    map[string]config.ClusterConfig{"target": {Brokers: "target:9092"}},
+		Replication: config.ReplicationConfig{JobID: "job-1", Dedup: config.DedupConfig{Enabled: true}},
+	}))
+	mirror.SetErrorPolicyForTest(config.ErrorPolicy{Action: config.ErrorActionRetry, RetryBackoff: "1ms"}, nil, nil)
+
+	for _, record := range partitionRecords("orders", 0, recordValues(0, 500)...) {
+		mirror.HandleRecordForTest(record)
+	}
+	require.Eventually(t, func() bool { return len(accepted()) > 0 }, time.Second, time.Millisecond, "the retry succeeded")
+	for partition := int32(1); partition < partitions; partition++ {
+		for _, record := range partitionRecords("orders", 0, recordValues(0, 2)...) {
+			record.Partition = partition
+			mirror.HandleRecordForTest(record)
+		}
+	}
+
+	require.Eventually(t, func() bool { return len(accepted()) == 500+(partitions-1)*2 }, 5*time.Second, 5*time.Millisecond)
+	var drained []*kgo.Record
+	for _, record := range accepted() {
+		if record.Partition == 0 {
+			drained = append(drained, record)
+		}
+	}
+	assert.Equal(t, recordValues(0, 500), recordValuesOf(drained))
+}
+
+func recordValuesOf(records []*kgo.Record) []string {
+	values := make([]string, len(records))
+	for i, record := range records {
+		values[i] = string(record.Value)
+	}
+	return values
+}
+
+func TestErrorPolicy_DeadLettersRecordWithErrorMetadata(t *testing.T) {
+	producer, _ := recordingProducer(1, kerr.MessageTooLarge)
+	deadLetters, dead := recordingProducer(0, nil)
+	mirror := newPolicyMirror(producer)
+	mirror.SetErrorPolicyForTest(config.ErrorPolicy{
+		Classes: map[string]string{config.ErrorClassRecordTooLarge: config.ErrorActionDeadLetter},
+	}, deadLetters, nil)
+
+	record := verifyRecord("orders", 42, "too large")
+	record.Headers = []kgo.RecordHeader{{Key: "trace", Value: []byte("abc")}}
+	mirror.HandleRecordForTest(record)
+
+	require.Len(t, dead(), 1)
+	letter := dead()[0]
+	assert.Equal(t, "kaf-mirror-dlq-", letter.Topic)
+	assert.Equal(t, "too large", string(letter.Value))
+	assert.Equal(t, "abc", headerValue(letter, "trace"))
+	assert.Equal(t, "orders", headerValue(letter, kafka.DeadLetterHeaderSourceTopic))
+	assert.Equal(t, "42", headerValue(letter, kafka.DeadLetterHeaderSourceOffset))
+	assert.Equal(t, "dr.orders", headerValue(letter, kafka.DeadLetterHeaderTargetTopic))
+	assert.Equal(t, config.ErrorClassRecordTooLarge, headerValue(letter, kafka.DeadLetterHeaderErrorClass))
+	assert.Equal(t, "1", headerValue(letter, kafka.DeadLetterHeaderAttempts))
+	assert.Equal(t, int64(1), mirror.DeadLetteredForTest())
+}
+
+func TestErrorPolicy_SkipsByDefault(t *testing.T) {
+	producer, accepted := recordingProducer(1, kerr.InvalidRecord)
+	mirror := newPolicyMirror(producer)
+	mirror.SetErrorPolicyForTest(config.ErrorPolicy{
+		Classes: map[string]string{config.ErrorClassAuthorization: config.ErrorActionHalt},
+	}, nil, nil)
+
+	mirror.HandleRecordForTest(verifyRecord("orders", 0, "bad"))
+	mirror.HandleRecordForTest(verifyRecord("orders", 1, "good"))
+
+	require.Len(t, accepted(), 1)
+	assert.Equal(t, "good", string(accepted()[0].Value))
+	assert.Equal(t, map[string]int{config.ErrorClassInvalidRecord: 1}, mirror.ErrorClassCountsForTest())
+}
+
+func TestErrorPolicy_HaltsWhenRetriesAreExhausted(t *testing.T) {
+	producer, accepted := recordingProducer(100, kerr.TopicAuthorizationFailed)
+	mirror := newPolicyMirror(producer)
+	halted := make(chan string, 1)
+	mirror.SetErrorPolicyForTest(config.ErrorPolicy{
+		Action:       config.ErrorActionRetry,
+		MaxRetries:   1,
+		RetryBackoff: "1ms",
+	}, nil, func(jobID, reason string) { halted <- reason })
+
+	mirror.HandleRecordForTest(verifyRecord("orders", 3, "v3"))
+
+	select {
+	case reason := <-halted:
+		assert.Contains(t, reason, "offset 3")
+		assert.Contains(t, reason, config.ErrorClassAuthorization)
+	case <-time.After(time.Second):
+		t.Fatal("job was not halted")
+	}
+	// Nothing is mirrored past the failed record.
+	mirror.HandleRecordForTest(verifyRecord("orders", 4, "v4"))
+	assert.Empty(t, accepted())
+	assert.Equal(t, map[string]int{config.ErrorClassAuthorization: 2}, mirror.ErrorClassCountsForTest())
+}
+
+// pendingProducer keeps produce callbacks until the test completes them, by record
+// value. Failed records are rejected as unauthorized.
+func pendingProducer() (*kafka.Producer, func(value string, fail bool)) {
+	var mu sync.Mutex
+	pending := map[string]func(error){}
+	producer := &kafka.Producer{Client: &mocks.MockKgoClient{
+		ProduceFunc: func(ctx context.Context, r *kgo.Record, cb func(*kgo.Record, error)) {
+			mu.Lock()
+			defer mu.Unlock()
+			pending[string(r.Value)] = func(err error) { cb(r, err) }
+		},
+	}}
+	return producer, func(value string, fail bool) {
+		mu.Lock()
+		complete := pending[value]
+		mu.Unlock()
+		if fail {
+			complete(kerr.TopicAuthorizationFailed)
+			return
+		}
+		complete(nil)
+	}
+}
+
+// markingConsumer returns a consumer that records the offsets marked for commit.
+func markingConsumer() (*kafka.Consumer, func() []int64) {
+	var mu sync.Mutex
+	var marked []int64
+	consumer := &kafka.Consumer{Client: &mocks.MockKgoClient{
+		MarkCommitRecordsFunc: func(records ...*kgo.Record) {
+			mu.Lock()
+			defer mu.Unlock()
+			for _, record := range records {
+				marked = append(marked, record.Offset)
+			}
+		},
+	}}
+	return consumer, func() []int64 {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int64(nil), marked...)
+	}
+}
+
+func TestErrorPolicy_CommitsRecordsOnceEarlierOnesSettle(t *testing.T) {
+	producer, complete := pendingProducer()
+	mirror := newPolicyMirror(producer)
+	consumer, marked := markingConsumer()
+	mirror.Consumer = consumer
+	mirror.SetErrorPolicyForTest(config.ErrorPolicy{}, nil, nil)
+
+	for _, record := range partitionRecords("orders", 0, "v0", "v1", "v2") {
+		mirror.HandleRecordForTest(record)
+	}
+	complete("v2", false)
+	complete("v1", false)
+	assert.Empty(t, marked(), "v0 is still in flight")
+
+	// A record the policy skips is settled too.
+	complete("v0", true)
+	assert.Equal(t, []int64{2}, marked())
+}
+
+func TestErrorPolicy_DoesNotCommitHaltedRecord(t *testing.T) {
+	producer, complete := pendingProducer()
+	mirror := newPolicyMirror(producer)
+	consumer, marked := markingConsumer()
+	mirror.Consumer = consumer
+	halted := make(chan string, 1)
+	mirror.SetErrorPolicyForTest(config.ErrorPolicy{Action: config.ErrorActionHalt}, nil,
+		func(jobID, reason string) { halted <- reason })
+
+	for _, record := range partitionRecords("orders", 0, "v0", "v1", "v2") {
+		mirror.HandleRecordForTest(record)
+	}
+	complete("v0", false)
+	complete("v2", false)
+	complete("v1", true)
+	select {
+	case <-halted:
+	case <-time.After(time.Second):
+		t.Fatal("job was not halted")
+	}
+	mirror.HandleRecordForTest(verifyRecord("orders", 3, "v3"))
+
+	// The restart resumes at the halted record.
+	assert.Equal(t, []int64{0}, marked())
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka_test
+
+import (
+	"context"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/kafka"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func offsets(topic string, hwms ...int64) []kafka.OffsetInfo {
+	result := make([]kafka.OffsetInfo, len(hwms))
+	for i, hwm := range hwms {
+		result[i] = kafka.OffsetInfo{Topic: topic, Partition: int32(i), Offset: hwm, HighWaterMark: hwm}
+	}
+	return result
+}
+
+func openTestJobClusters(t *testing.T, source, target *fakeAdmin) *kafka.JobClusters {
+	restore := kafka.SetAdminClientFactoryForTest(func(cfg config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		if cfg.Brokers == "source:9092" {
+			return source, nil
+		}
+		return target, nil
+	})
+	t.Cleanup(restore)
+
+	clusters, err := kafka.OpenJobClusters(&config.Config{
+		Clusters: map[string]config.ClusterConfig{
+			"source": {Brokers: "source:9092"},
+			"target": {Brokers: "target:9092"},
+		},
+		Topics:      []config.TopicMapping{{Source: "orders", Target: "dr.orders", Enabled: true}},
+		Replication: config.ReplicationConfig{JobID: "job-1"},
+	})
+	require.NoError(t, err)
+	return clusters
+}
+
+func TestTranslateGroupOffsets_KeepsDistanceFromEnd(t *testing.T) {
+	committed := map[string]map[int32]int64{"orders": {0: 90, 1: 200, 2: 5}}
+	sourceHWMs := map[string]map[int32]int64{"orders": {0: 100, 1: 200, 2: 500}}
+	// The target started mirroring late and holds less history.
+	targetHWMs := map[string]map[int32]int64{"dr.orders": {0: 40, 1: 150, 2: 300}}
+
+	translated := kafka.TranslateGroupOffsets(committed, sourceHWMs, targetHWMs, map[string]string{"orders": "dr.orders"})
+
+	assert.Equal(t, map[string]map[int32]int64{"dr.orders": {0: 30, 1: 150, 2: 0}}, translated)
+}
+
+func TestJobClusters_OutstandingRecords(t *testing.T) {
+	source := &fakeAdmin{hwms: map[string][]kafka.OffsetInfo{"orders": offsets("orders", 100, 50)}}
+	target := &fakeAdmin{hwms: map[string][]kafka.OffsetInfo{"dr.orders": offsets("dr.orders", 90, 50)}}
+	clusters := openTestJobClusters(t, source, target)
+
+	outstanding, err := clusters.OutstandingRecords(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), outstanding)
+
+	target.hwms["dr.orders"] = offsets("dr.orders", 100, 50)
+	outstanding, err = clusters.OutstandingRecords(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, outstanding)
+}
+
+func TestJobClusters_SyncConsumerGroupOffsets(t *testing.T) {
+	source := &fakeAdmin{
+		hwms: map[string][]kafka.OffsetInfo{"orders": offsets("orders", 100, 50)},
+		groups: map[string]map[string][]kafka.OffsetInfo{
+			"billing":                {"orders": offsets("orders", 95, 50)},
+			"audit":                  {"orders": offsets("orders", 10, 10)},
+			"kaf-mirror-job-other":   {"orders": offsets("orders", 100, 50)},
+			"shipping-no-order-read": {"payments": offsets("payments", 1)},
+		},
+	}
+	target := &fakeAdmin{hwms: map[string][]kafka.OffsetInfo{"dr.orders": offsets("dr.orders", 100, 50)}}
+	clusters := openTestJobClusters(t, source, target)
+
+	preview, err := clusters.SyncConsumerGroupOffsets(context.Background(), []string{"bill*"}, true)
+	require.NoError(t, err)
+	require.Len(t, preview, 1)
+	assert.Equal(t, "billing", preview[0].Group)
+	assert.Equal(t, map[string]map[int32]int64{"dr.orders": {0: 95, 1: 50}}, preview[0].Offsets)
+	assert.Empty(t, target.commits, "dry run must not commit")
+
+	results, err := clusters.SyncConsumerGroupOffsets(context.Background(), nil, false)
+	require.NoError(t, err)
+	groups := []string{}
+	for _, result := range results {
+		groups = append(groups, result.Group)
+	}
+	assert.Equal(t, []string{"audit", "billing"}, groups)
+	assert.Equal(t, map[string]map[int32]int64{"dr.orders": {0: 10, 1: 10}}, target.commits["audit"])
+	assert.Equal(t, map[string]map[int32]int64{"dr.orders": {0: 95, 1: 50}}, target.commits["billing"])
+	assert.NotContains(t, target.commits, "kaf-mirror-job-other")
+}
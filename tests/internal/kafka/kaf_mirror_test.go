@@ -25,6 +25,19 @@ import (
 type fakeAdmin struct {
 	info        *kafka.ClusterInfo
 	ensureCalls []string
+	configs     map[string]map[string]string // topic configs, also set on creation
+	altered     map[string]map[string]string
+	validated   map[string]map[string]string
+	grown       map[string]int32
+	deleted     []string
+	acls        []kafka.ACLEntry
+	quotas      []kafka.ClientQuota
+	altQuotas   []kafka.ClientQuota
+	hwms        map[string][]kafka.OffsetInfo
+	groups      map[string]map[string][]kafka.OffsetInfo // group -> topic -> committed offsets
+	commits     map[string]map[string]map[int32]int64
+	atTime      map[string][]kafka.OffsetInfo // offsets returned for any timestamp
+	atMillis    int64
 }
 
 func (f *fakeAdmin) GetClusterInfo(ctx context.Context) (*kafka.ClusterInfo, error) {
@@ -32,14 +45,53 @@ func (f *fakeAdmin) GetClusterInfo(ctx context.Context) (*kafka.ClusterInfo, err
 }
 
 func (f *fakeAdmin) GetConsumerGroupOffsets(ctx context.Context, groupID string, topics []string) (map[string][]kafka.OffsetInfo, error) {
-	return map[string][]kafka.OffsetInfo{}, nil
+	result := map[string][]kafka.OffsetInfo{}
+	for _, topic := range topics {
+		if offsets, ok := f.groups[groupID][topic]; ok {
+			result[topic] = offsets
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeAdmin) ListConsumerGroups(ctx context.Context) ([]string, error) {
+	groups := make([]string, 0, len(f.groups))
+	for group := range f.groups {
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+func (f *fakeAdmin) CommitConsumerGroupOffsets(ctx context.Context, groupID string, offsets map[string]map[int32]int64) error {
+	if f.commits == nil {
+		f.commits = map[string]map[string]map[int32]int64{}
+	}
+	f.commits[groupID] = offsets
+	return nil
 }
 
 func (f *fakeAdmin) GetTopicHighWaterMarks(ctx context.Context, topics []string) (map[string][]kafka.OffsetInfo, error) {
-	return map[string][]kafka.OffsetInfo{}, nil
+	result := map[string][]kafka.OffsetInfo{}
+	for _, topic := range topics {
+		if offsets, ok := f.hwms[topic]; ok {
+			result[topic] = offsets
+		}
+	}
+	return result, nil
 }
 
-func (f *fakeAdmin) EnsureTopicExists(ctx context.Context, topicName string, partitions int32, replicationFactor int16) error {
+func (f *fakeAdmin) GetOffsetsForTimestamp(ctx context.Context, topics []string, millis int64) (map[string][]kafka.OffsetInfo, error) {
+	f.atMillis = millis
+	result := map[string][]kafka.OffsetInfo{}
+	for _, topic := range topics {
+		if offsets, ok := f.atTime[topic]; ok {
+			result[topic] = offsets
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeAdmin) EnsureTopicExists(ctx context.Context, topicName string, partitions int32, replicationFactor int16, configs map[string]string) error {
 	f.ensureCalls = append(f.ensureCalls, topicName)
 	if f.info.Topics == nil {
 		f.info.Topics = map[string]kafka.TopicInfo{}
@@ -50,7 +102,81 @@ func (f *fakeAdmin) EnsureTopicExists(ctx context.Context, topicName string, par
 			Partitions:        partitions,
 			ReplicationFactor: replicationFactor,
 		}
+		if f.configs == nil {
+			f.configs = map[string]map[string]string{}
+		}
+		f.configs[topicName] = map[string]string{}
+		for key, value := range configs {
+			f.configs[topicName][key] = value
+		}
+	}
+	return nil
+}
+
+func (f *fakeAdmin) DescribeTopicConfigs(ctx context.Context, topics ...string) (map[string]map[string]string, error) {
+	result := map[string]map[string]string{}
+	for _, topic := range topics {
+		if _, exists := f.info.Topics[topic]; !exists {
+			continue
+		}
+		configs := map[string]string{}
+		for key, value := range f.configs[topic] {
+			configs[key] = value
+		}
+		result[topic] = configs
 	}
+	return result, nil
+}
+
+func (f *fakeAdmin) AlterTopicConfigs(ctx context.Context, topic string, configs map[string]string, validateOnly bool) error {
+	record := &f.altered
+	if validateOnly {
+		record = &f.validated
+	}
+	if *record == nil {
+		*record = map[string]map[string]string{}
+	}
+	(*record)[topic] = configs
+	if !validateOnly {
+		for key, value := range configs {
+			f.configs[topic][key] = value
+		}
+	}
+	return nil
+}
+
+func (f *fakeAdmin) CreatePartitions(ctx context.Context, topicName string, totalPartitions int32) error {
+	if f.grown == nil {
+		f.grown = map[string]int32{}
+	}
+	f.grown[topicName] = totalPartitions
+	info := f.info.Topics[topicName]
+	info.Partitions = totalPartitions
+	f.info.Topics[topicName] = info
+	return nil
+}
+
+func (f *fakeAdmin) DeleteTopic(ctx context.Context, topicName string) error {
+	f.deleted = append(f.deleted, topicName)
+	delete(f.info.Topics, topicName)
+	return nil
+}
+
+func (f *fakeAdmin) DescribeACLs(ctx context.Context) ([]kafka.ACLEntry, error) {
+	return append([]kafka.ACLEntry(nil), f.acls...), nil
+}
+
+func (f *fakeAdmin) CreateACLs(ctx context.Context, acls []kafka.ACLEntry) error {
+	f.acls = append(f.acls, acls...)
+	return nil
+}
+
+func (f *fakeAdmin) DescribeClientQuotas(ctx context.Context) ([]kafka.ClientQuota, error) {
+	return append([]kafka.ClientQuota(nil), f.quotas...), nil
+}
+
+func (f *fakeAdmin) AlterClientQuotas(ctx context.Context, quotas []kafka.ClientQuota, validateOnly bool) error {
+	f.altQuotas = append(f.altQuotas, quotas...)
 	return nil
 }
 
@@ -0,0 +1,169 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/tests/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestLatencyTracker_PercentilesFromAcks(t *testing.T) {
+	tracker := kafka.NewLatencyTracker()
+	now := time.Now()
+
+	for i := 1; i <= 100; i++ {
+		sourceTs := now.Add(-time.Duration(i) * 100 * time.Millisecond)
+		tracker.ObserveConsumed("orders", 0, sourceTs, 100)
+		tracker.ObserveAck("orders", 0, sourceTs, now, 100, nil)
+	}
+
+	snap := tracker.Snapshot(map[string]map[int32]int64{"orders": {0: 0}}, now)
+	require.Len(t, snap.Partitions, 1)
+	p := snap.Partitions[0]
+	assert.Equal(t, 100, p.Samples)
+	assert.InDelta(t, 5.0, p.LagSecondsP50, 0.001)
+	assert.InDelta(t, 9.9, p.LagSecondsP99, 0.001)
+	assert.Equal(t, 0.0, p.LagSeconds, "caught-up partition is not behind")
+	assert.InDelta(t, 5.0, snap.P50, 0.001)
+	assert.InDelta(t, 9.9, snap.P99, 0.001)
+
+	// Samples are reset after each snapshot.
+	snap = tracker.Snapshot(map[string]map[int32]int64{"orders": {0: 0}}, now)
+	assert.Equal(t, 0, snap.Partitions[0].Samples)
+	assert.Equal(t, 0.0, snap.P99)
+}
+
+func TestLatencyTracker_IdlePartitionEstimates(t *testing.T) {
+	tracker := kafka.NewLatencyTracker()
+	now := time.Now()
+
+	// orders/0 replicated a record from 40s ago and still has a backlog.
+	replicated := now.Add(-40 * time.Second)
+	tracker.ObserveConsumed("orders", 0, replicated, 100)
+	tracker.ObserveAck("orders", 0, replicated, replicated.Add(time.Second), 100, nil)
+	tracker.Snapshot(nil, now)
+
+	// orders/1 fetched a record from 10s ago that has not been acked yet.
+	tracker.ObserveConsumed("orders", 1, now.Add(-10*time.Second), 100)
+
+	snap := tracker.Snapshot(map[string]map[int32]int64{
+		"orders":   {0: 500, 1: 0},
+		"payments": {0: 0},
+	}, now)
+	require.Len(t, snap.Partitions, 3)
+
+	assert.InDelta(t, 40.0, snap.Partitions[0].LagSeconds, 0.001)
+	assert.InDelta(t, 40.0, snap.Partitions[0].LagSecondsP99, 0.001)
+	assert.Equal(t, int64(500), snap.Partitions[0].MessageLag)
+	assert.InDelta(t, 10.0, snap.Partitions[1].LagSeconds, 0.001)
+	assert.Equal(t, "payments", snap.Partitions[2].Topic)
+	assert.Equal(t, 0.0, snap.Partitions[2].LagSeconds)
+
+	assert.InDelta(t, 40.0, snap.Max, 0.001)
+	require.Len(t, snap.Topics, 2)
+	assert.Equal(t, "orders", snap.Topics[0].Topic)
+	assert.Equal(t, 2, snap.Topics[0].Partitions)
+	assert.Equal(t, int64(500), snap.Topics[0].MessageLag)
+	assert.InDelta(t, 40.0, snap.Topics[0].LagSecondsMax, 0.001)
+}
+
+func TestLatencyTracker_FailedSendsAreNotSampled(t *testing.T) {
+	tracker := kafka.NewLatencyTracker()
+	now := time.Now()
+	sourceTs := now.Add(-time.Second)
+
+	tracker.ObserveConsumed("orders", 0, sourceTs, 100)
+	tracker.ObserveAck("orders", 0, sourceTs, now, 100, errors.New("broker unavailable"))
+
+	snap := tracker.Snapshot(map[string]map[int32]int64{"orders": {0: 0}}, now)
+	assert.Equal(t, 0, snap.Partitions[0].Samples)
+}
+
+func TestHandleRecord_TracksEndToEndLatency(t *testing.T) {
+	var producedRecord *kgo.Record
+	mockClient := &mocks.MockKgoClient{
+		ProduceFunc: func(ctx context.Context, r *kgo.Record, f func(*kgo.Record, error)) {
+			producedRecord = r
+			f(r, nil)
+		},
+	}
+
+	km := kafka.NewKafMirrorImplForTest(
+		&kafka.Producer{Client: mockClient},
+		map[string]string{"orders": "orders_copy"},
+		nil,
+	)
+
+	sourceTs := time.Now().Add(-3 * time.Second)
+	km.HandleRecordForTest(&kgo.Record{
+		Topic:     "orders",
+		Partition: 1,
+		Value:     []byte("payload"),
+		Timestamp: sourceTs,
+	})
+
+	require.NotNil(t, producedRecord)
+	assert.True(t, producedRecord.Timestamp.Equal(sourceTs))
+
+	snap := km.LagSnapshotForTest(map[string]map[int32]int64{"orders": {1: 0}}, time.Now())
+	require.Len(t, snap.Partitions, 1)
+	assert.Equal(t, 1, snap.Partitions[0].Samples)
+	assert.InDelta(t, 3.0, snap.Partitions[0].LagSecondsP50, 0.5)
+}
+
+func TestConsumerPartitionLags(t *testing.T) {
+	consumer := kafka.NewConsumerForTest(
+		map[string]map[int32]int64{"orders": {0: 10, 1: 5}},
+		map[string]map[int32]int64{"orders": {0: 7}},
+	)
+
+	lags := consumer.PartitionLags()
+	assert.Equal(t, int64(2), lags["orders"][0])
+	assert.Equal(t, int64(5), lags["orders"][1])
+}
+
+func TestLatencyTracker_PartitionCounters(t *testing.T) {
+	tracker := kafka.NewLatencyTracker()
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		tracker.ObserveConsumed("orders", 2, now, 50)
+	}
+	tracker.ObserveAck("orders", 2, now, now, 50, nil)
+	tracker.ObserveAck("orders", 2, now, now, 50, nil)
+	tracker.ObserveAck("orders", 2, now, now, 50, errors.New("record too large"))
+
+	snap := tracker.Snapshot(map[string]map[int32]int64{"orders": {2: 4}}, now)
+	require.Len(t, snap.Metrics, 1)
+	m := snap.Metrics[0]
+	assert.Equal(t, "orders", m.Topic)
+	assert.Equal(t, int32(2), m.Partition)
+	assert.Equal(t, int64(3), m.MessagesConsumed)
+	assert.Equal(t, int64(150), m.BytesConsumed)
+	assert.Equal(t, int64(2), m.MessagesReplicated)
+	assert.Equal(t, int64(100), m.BytesTransferred)
+	assert.Equal(t, int64(1), m.ErrorCount)
+	assert.Equal(t, int64(4), m.MessageLag)
+
+	// Counters are per interval.
+	snap = tracker.Snapshot(map[string]map[int32]int64{"orders": {2: 0}}, now)
+	assert.Equal(t, int64(0), snap.Metrics[0].MessagesConsumed)
+	assert.Equal(t, int64(0), snap.Metrics[0].ErrorCount)
+}
@@ -0,0 +1,214 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka_test
+
+import (
+	"context"
+	"encoding/binary"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/tests/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+const ordersSchemaV1 = `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"}]}`
+const ordersSchemaV2 = `{"type":"record","name":"Order","fields":[{"name":"id","type":"string"},{"name":"qty","type":"int","default":0}]}`
+
+func wireFormat(id int, payload string) []byte {
+	data := make([]byte, 5, 5+len(payload))
+	binary.BigEndian.PutUint32(data[1:], uint32(id))
+	return append(data, payload...)
+}
+
+func newTestRegistries(t *testing.T) (*mocks.MockSchemaRegistry, *mocks.MockSchemaRegistry, *kafka.SchemaReplicator) {
+	source := mocks.NewMockSchemaRegistry(100)
+	target := mocks.NewMockSchemaRegistry(1)
+	t.Cleanup(source.Close)
+	t.Cleanup(target.Close)
+
+	replicator := kafka.NewSchemaReplicator(
+		config.SchemaRegistryConfig{URL: source.URL()},
+		config.SchemaRegistryConfig{URL: target.URL()},
+	)
+	require.NotNil(t, replicator)
+	return source, target, replicator
+}
+
+func TestNewSchemaReplicator_NeedsTwoRegistries(t *testing.T) {
+	assert.Nil(t, kafka.NewSchemaReplicator(config.SchemaRegistryConfig{}, config.SchemaRegistryConfig{URL: "http://target:8081"}))
+	assert.Nil(t, kafka.NewSchemaReplicator(config.SchemaRegistryConfig{URL: "http://shared:8081"}, config.SchemaRegistryConfig{URL: "http://shared:8081/"}))
+}
+
+func TestRenameSubject_FollowsTopicMapping(t *testing.T) {
+	topicMap := map[string]string{"orders": "dr.orders", "orders-eu": "dr.orders-eu"}
+
+	assert.Equal(t, "dr.orders-value", kafka.RenameSubjectForTest("orders-value", topicMap))
+	assert.Equal(t, "dr.orders-key", kafka.RenameSubjectForTest("orders-key", topicMap))
+	assert.Equal(t, "dr.orders-eu-value", kafka.RenameSubjectForTest("orders-eu-value", topicMap))
+	assert.Equal(t, "com.example.Order", kafka.RenameSubjectForTest("com.example.Order", topicMap))
+}
+
+func TestSchemaReplicator_SyncSubjectsCopiesVersionsInOrder(t *testing.T) {
+	source, target, replicator := newTestRegistries(t)
+	v1 := source.Register("orders-value", kafka.RegisteredSchema{Schema: ordersSchemaV1})
+	v2 := source.Register("orders-value", kafka.RegisteredSchema{Schema: ordersSchemaV2})
+	source.Register("payments-value", kafka.RegisteredSchema{Schema: ordersSchemaV1})
+
+	copied, err := replicator.SyncSubjects(context.Background(), map[string]string{"orders": "dr.orders", "unused": "unused"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, copied)
+
+	subjects := target.Subjects()
+	assert.Equal(t, []int{1, 2}, subjects["dr.orders-value"])
+	assert.NotContains(t, subjects, "payments-value")
+	assert.Equal(t, map[int]int{v1: 1, v2: 2}, replicator.IDMap())
+}
+
+func TestSchemaReplicator_CopiesReferencedSchemas(t *testing.T) {
+	source, target, replicator := newTestRegistries(t)
+	source.Register("com.example.Address", kafka.RegisteredSchema{Schema: `{"type":"record","name":"Address","fields":[]}`})
+	source.Register("orders-value", kafka.RegisteredSchema{
+		Schema:     `{"type":"record","name":"Order","fields":[{"name":"address","type":"com.example.Address"}]}`,
+		References: []kafka.SchemaReference{{Name: "com.example.Address", Subject: "com.example.Address", Version: 1}},
+	})
+
+	_, err := replicator.SyncSubjects(context.Background(), map[string]string{"orders": "dr.orders"})
+	require.NoError(t, err)
+
+	subjects := target.Subjects()
+	assert.Len(t, subjects["com.example.Address"], 1)
+	assert.Len(t, subjects["dr.orders-value"], 1)
+}
+
+func TestHandleRecord_RewritesSchemaIDs(t *testing.T) {
+	source, target, replicator := newTestRegistries(t)
+	target.Register("other-value", kafka.RegisteredSchema{Schema: `"string"`})
+	keyID := source.Register("orders-key", kafka.RegisteredSchema{Schema: `"string"`})
+	valueID := source.Register("orders-value", kafka.RegisteredSchema{Schema: ordersSchemaV1})
+
+	var producedRecord *kgo.Record
+	mockClient := &mocks.MockKgoClient{
+		ProduceFunc: func(ctx context.Context, r *kgo.Record, f func(*kgo.Record, error)) {
+			producedRecord = r
+			f(r, nil)
+		},
+	}
+	km := kafka.NewKafMirrorImplForTest(&kafka.Producer{Client: mockClient}, map[string]string{"orders": "dr.orders"}, nil)
+	km.SetSchemaReplicatorForTest(replicator)
+
+	sourceValue := wireFormat(valueID, "order-1")
+	km.HandleRecordForTest(&kgo.Record{
+		Topic: "orders",
+		Key:   wireFormat(keyID, "k1"),
+		Value: sourceValue,
+	})
+
+	require.NotNil(t, producedRecord)
+	// "string" already has ID 1 in the target registry and is reused for the key.
+	assert.Equal(t, wireFormat(1, "k1"), producedRecord.Key)
+	assert.Equal(t, wireFormat(2, "order-1"), producedRecord.Value)
+	assert.Equal(t, wireFormat(valueID, "order-1"), sourceValue, "source bytes must not be modified")
+
+	subjects := target.Subjects()
+	assert.Equal(t, []int{1}, subjects["dr.orders-key"])
+	assert.Equal(t, []int{2}, subjects["dr.orders-value"])
+
+	// The ID map is cached, so later records do not hit the registries.
+	requests := source.Requests() + target.Requests()
+	km.HandleRecordForTest(&kgo.Record{Topic: "orders", Value: wireFormat(valueID, "order-2")})
+	assert.Equal(t, wireFormat(2, "order-2"), producedRecord.Value)
+	assert.Equal(t, requests, source.Requests()+target.Requests())
+}
+
+func TestHandleRecord_PassesThroughNonSchemaPayloads(t *testing.T) {
+	_, _, replicator := newTestRegistries(t)
+
+	var producedRecord *kgo.Record
+	mockClient := &mocks.MockKgoClient{
+		ProduceFunc: func(ctx context.Context, r *kgo.Record, f func(*kgo.Record, error)) {
+			producedRecord = r
+			f(r, nil)
+		},
+	}
+	km := kafka.NewKafMirrorImplForTest(&kafka.Producer{Client: mockClient}, map[string]string{"orders": "dr.orders"}, nil)
+	km.SetSchemaReplicatorForTest(replicator)
+
+	km.HandleRecordForTest(&kgo.Record{Topic: "orders", Key: []byte("plain"), Value: []byte(`{"id":"1"}`)})
+	require.NotNil(t, producedRecord)
+	assert.Equal(t, []byte("plain"), producedRecord.Key)
+	assert.Equal(t, []byte(`{"id":"1"}`), producedRecord.Value)
+
+	// A wire format prefix with an ID the source registry does not know is kept as is.
+	unknown := wireFormat(4242, "x")
+	km.HandleRecordForTest(&kgo.Record{Topic: "orders", Value: unknown})
+	assert.Equal(t, unknown, producedRecord.Value)
+}
+
+func TestHandleRecord_RegistersSharedSchemaForEveryTopic(t *testing.T) {
+	source, target, replicator := newTestRegistries(t)
+	ordersID := source.Register("orders-value", kafka.RegisteredSchema{Schema: ordersSchemaV1})
+	paymentsID := source.Register("payments-value", kafka.RegisteredSchema{Schema: ordersSchemaV1})
+	require.Equal(t, ordersID, paymentsID)
+
+	var produced []*kgo.Record
+	mockClient := &mocks.MockKgoClient{
+		ProduceFunc: func(ctx context.Context, r *kgo.Record, f func(*kgo.Record, error)) {
+			produced = append(produced, r)
+			f(r, nil)
+		},
+	}
+	km := kafka.NewKafMirrorImplForTest(&kafka.Producer{Client: mockClient},
+		map[string]string{"orders": "dr.orders", "payments": "dr.payments"}, nil)
+	km.SetSchemaReplicatorForTest(replicator)
+
+	km.HandleRecordForTest(&kgo.Record{Topic: "orders", Value: wireFormat(ordersID, "order-1")})
+	km.HandleRecordForTest(&kgo.Record{Topic: "payments", Value: wireFormat(paymentsID, "payment-1")})
+
+	require.Len(t, produced, 2)
+	subjects := target.Subjects()
+	assert.Equal(t, []int{1}, subjects["dr.orders-value"])
+	assert.Equal(t, []int{1}, subjects["dr.payments-value"])
+	assert.Equal(t, wireFormat(1, "payment-1"), produced[1].Value)
+}
+
+func TestHandleRecord_AppliesErrorPolicyWhenSchemaRemapFails(t *testing.T) {
+	source, target, replicator := newTestRegistries(t)
+	valueID := source.Register("orders-value", kafka.RegisteredSchema{Schema: ordersSchemaV1})
+	target.Close() // the target registry is unreachable
+
+	var produced []*kgo.Record
+	mockClient := &mocks.MockKgoClient{
+		ProduceFunc: func(ctx context.Context, r *kgo.Record, f func(*kgo.Record, error)) {
+			produced = append(produced, r)
+			f(r, nil)
+		},
+	}
+	deadLetters, dead := recordingProducer(0, nil)
+	km := kafka.NewKafMirrorImplForTest(&kafka.Producer{Client: mockClient}, map[string]string{"orders": "dr.orders"}, nil)
+	km.SetSchemaReplicatorForTest(replicator)
+	km.SetErrorPolicyForTest(config.ErrorPolicy{
+		Classes: map[string]string{config.ErrorClassSchema: config.ErrorActionDeadLetter},
+	}, deadLetters, nil)
+
+	sourceValue := wireFormat(valueID, "order-1")
+	km.HandleRecordForTest(&kgo.Record{Topic: "orders", Offset: 5, Value: sourceValue})
+
+	assert.Empty(t, produced, "records with source schema IDs must not reach the target")
+	assert.Equal(t, map[string]int{config.ErrorClassSchema: 1}, km.ErrorClassCountsForTest())
+	require.Len(t, dead(), 1)
+	assert.Equal(t, sourceValue, dead()[0].Value)
+	assert.Equal(t, config.ErrorClassSchema, headerValue(dead()[0], kafka.DeadLetterHeaderErrorClass))
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka_test
+
+import (
+	"context"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/kafka"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// retainedOffsets returns partitions holding records from logStart up to their high water marks.
+func retainedOffsets(topic string, logStart int64, hwms ...int64) []kafka.OffsetInfo {
+	result := offsets(topic, hwms...)
+	for i := range result {
+		result[i].LogStartOffset = logStart
+	}
+	return result
+}
+
+func TestResolveStartOffsets_Modes(t *testing.T) {
+	admin := &fakeAdmin{
+		hwms:   map[string][]kafka.OffsetInfo{"orders": retainedOffsets("orders", 10, 100, 50)},
+		atTime: map[string][]kafka.OffsetInfo{"orders": {{Topic: "orders", Partition: 0, Offset: 70}, {Topic: "orders", Partition: 1, Offset: -1}}},
+	}
+	ctx := context.Background()
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	newOffsets := func(position config.StartPosition) []int64 {
+		resolved, err := kafka.ResolveStartOffsets(ctx, admin, position, []string{"orders"}, now)
+		require.NoError(t, err)
+		result := []int64{}
+		for _, partition := range resolved {
+			assert.Equal(t, int64(-1), partition.CommittedOffset)
+			assert.Equal(t, partition.HighWaterMark-partition.Offset, partition.Lag)
+			result = append(result, partition.Offset)
+		}
+		return result
+	}
+
+	assert.Equal(t, []int64{10, 10}, newOffsets(config.StartPosition{Mode: config.StartPositionEarliest}))
+	assert.Equal(t, []int64{100, 50}, newOffsets(config.StartPosition{Mode: config.StartPositionLatest}))
+	// Partition 1 has no record after the timestamp and starts at its end.
+	assert.Equal(t, []int64{70, 50}, newOffsets(config.StartPosition{Mode: config.StartPositionTimestamp, Timestamp: "7d"}))
+	assert.Equal(t, now.Add(-7*24*time.Hour).UnixMilli(), admin.atMillis)
+	assert.Equal(t, []int64{42}, newOffsets(config.StartPosition{Mode: config.StartPositionOffsets, Offsets: map[string]map[int32]int64{"orders": {1: 42}}}))
+}
+
+func TestResolveStartOffsets_RejectsOffsetsOutsideTheLog(t *testing.T) {
+	admin := &fakeAdmin{hwms: map[string][]kafka.OffsetInfo{"orders": retainedOffsets("orders", 10, 100)}}
+	ctx := context.Background()
+
+	_, err := kafka.ResolveStartOffsets(ctx, admin, config.StartPosition{Mode: config.StartPositionOffsets, Offsets: map[string]map[int32]int64{"orders": {0: 5}}}, []string{"orders"}, time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "outside the available range 10-100")
+
+	_, err = kafka.ResolveStartOffsets(ctx, admin, config.StartPosition{Mode: config.StartPositionOffsets, Offsets: map[string]map[int32]int64{"orders": {3: 50}}}, []string{"orders"}, time.Now())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no partition 3")
+
+	_, err = kafka.ResolveStartOffsets(ctx, admin, config.StartPosition{Mode: config.StartPositionLatest}, []string{"missing"}, time.Now())
+	assert.Error(t, err)
+}
+
+func TestApplyStartPositions_OnlyUncommittedPartitions(t *testing.T) {
+	source := &fakeAdmin{
+		hwms: map[string][]kafka.OffsetInfo{
+			"orders":   retainedOffsets("orders", 0, 100, 50),
+			"payments": retainedOffsets("payments", 5, 30),
+			"audit":    retainedOffsets("audit", 0, 80),
+		},
+		groups: map[string]map[string][]kafka.OffsetInfo{
+			"kaf-mirror-job-job-1": {"orders": {{Topic: "orders", Partition: 0, Offset: 60}, {Topic: "orders", Partition: 1, Offset: -1}}},
+		},
+	}
+	restore := kafka.SetAdminClientFactoryForTest(func(cfg config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		return source, nil
+	})
+	defer restore()
+
+	cfg := &config.Config{
+		Clusters:    map[string]config.ClusterConfig{"source": {Brokers: "source:9092"}},
+		Replication: config.ReplicationConfig{JobID: "job-1", StartPosition: config.StartPosition{Mode: config.StartPositionLatest}},
+		Topics: []config.TopicMapping{
+			{Source: "orders", Enabled: true},
+			{Source: "pay.*", Enabled: true, StartPosition: config.StartPosition{Mode: config.StartPositionEarliest}},
+			{Source: "audit", Enabled: true},
+		},
+	}
+	require.NoError(t, kafka.ApplyStartPositionsForTest(cfg, "kaf-mirror-job-job-1", []string{"orders", "payments", "audit"}))
+
+	assert.Equal(t, map[string]map[int32]int64{
+		"orders":   {1: 50},
+		"payments": {0: 5},
+		"audit":    {0: 80},
+	}, source.commits["kaf-mirror-job-job-1"])
+}
+
+func TestApplyStartPositions_NothingConfigured(t *testing.T) {
+	restore := kafka.SetAdminClientFactoryForTest(func(cfg config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		t.Fatal("no admin client is needed without start positions")
+		return nil, nil
+	})
+	defer restore()
+
+	cfg := &config.Config{Topics: []config.TopicMapping{{Source: "orders", Enabled: true}}}
+	assert.NoError(t, kafka.ApplyStartPositionsForTest(cfg, "kaf-mirror-job-job-1", []string{"orders"}))
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka_test
+
+import (
+	"context"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/kafka"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicConfigPolicy_Desired(t *testing.T) {
+	policy := kafka.NewTopicConfigPolicy(config.TopicConfigSyncConfig{
+		Include:   []string{"retention.*", "cleanup.policy", "min.insync.replicas"},
+		Exclude:   []string{"retention.bytes"},
+		Overrides: map[string]string{"retention.ms": "1209600000"},
+	})
+
+	desired := policy.Desired(map[string]string{
+		"retention.ms":        "604800000",
+		"retention.bytes":     "-1",
+		"cleanup.policy":      "compact",
+		"min.insync.replicas": "3",
+		"segment.bytes":       "1073741824",
+	}, 2)
+
+	assert.Equal(t, map[string]string{
+		"retention.ms":        "1209600000",
+		"cleanup.policy":      "compact",
+		"min.insync.replicas": "2",
+	}, desired)
+}
+
+func TestTopicConfigPolicy_DefaultsAndOff(t *testing.T) {
+	policy := kafka.NewTopicConfigPolicy(config.TopicConfigSyncConfig{})
+	assert.True(t, policy.Enabled())
+	assert.True(t, policy.Allowed("max.message.bytes"))
+	assert.False(t, policy.Allowed("unclean.leader.election.enable"))
+
+	off := kafka.NewTopicConfigPolicy(config.TopicConfigSyncConfig{Mode: config.TopicConfigSyncModeOff})
+	assert.False(t, off.Enabled())
+}
+
+func newTopicConfigAdmin() *fakeAdmin {
+	return &fakeAdmin{
+		info: &kafka.ClusterInfo{
+			BrokerCount: 3,
+			Topics: map[string]kafka.TopicInfo{
+				"source-a": {Name: "source-a", Partitions: 3, ReplicationFactor: 3},
+				"source-b": {Name: "source-b", Partitions: 1, ReplicationFactor: 3},
+				"target-b": {Name: "target-b", Partitions: 1, ReplicationFactor: 3},
+			},
+		},
+		configs: map[string]map[string]string{
+			"source-a": {"cleanup.policy": "compact", "retention.ms": "-1", "unclean.leader.election.enable": "true"},
+			"source-b": {"cleanup.policy": "delete", "retention.ms": "86400000"},
+			"target-b": {"cleanup.policy": "delete", "retention.ms": "604800000"},
+		},
+	}
+}
+
+func topicConfigTestConfig(sync config.TopicConfigSyncConfig) *config.Config {
+	return &config.Config{
+		Clusters: map[string]config.ClusterConfig{
+			"source": {Brokers: "localhost:9092"},
+			"target": {Brokers: "localhost:9093"},
+		},
+		Replication: config.ReplicationConfig{JobID: "test-job", TopicConfigSync: sync},
+		Topics: []config.TopicMapping{
+			{Source: "source-a", Target: "target-a", Enabled: true},
+			{Source: "source-b", Target: "target-b", Enabled: true},
+		},
+	}
+}
+
+func TestValidateAndSyncClusters_CopiesTopicConfigs(t *testing.T) {
+	admin := newTopicConfigAdmin()
+	restore := kafka.SetAdminClientFactoryForTest(func(cfg config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		return admin, nil
+	})
+	t.Cleanup(restore)
+
+	cfg := topicConfigTestConfig(config.TopicConfigSyncConfig{})
+	_, err := kafka.ValidateAndSyncClustersForTest(cfg, []string{"source-a", "source-b"}, map[string]string{"source-a": "target-a", "source-b": "target-b"})
+	require.NoError(t, err)
+
+	// New topics are created with the allowed source configs.
+	assert.Equal(t, map[string]string{"cleanup.policy": "compact", "retention.ms": "-1"}, admin.configs["target-a"])
+	// Existing topics are brought back in line.
+	assert.Equal(t, map[string]string{"retention.ms": "86400000"}, admin.altered["target-b"])
+}
+
+func TestValidateAndSyncClusters_DryRunOnlyCopiesAtCreation(t *testing.T) {
+	admin := newTopicConfigAdmin()
+	restore := kafka.SetAdminClientFactoryForTest(func(cfg config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		return admin, nil
+	})
+	t.Cleanup(restore)
+
+	cfg := topicConfigTestConfig(config.TopicConfigSyncConfig{Mode: config.TopicConfigSyncModeDryRun})
+	_, err := kafka.ValidateAndSyncClustersForTest(cfg, []string{"source-a", "source-b"}, map[string]string{"source-a": "target-a", "source-b": "target-b"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "compact", admin.configs["target-a"]["cleanup.policy"])
+	assert.Empty(t, admin.altered)
+	assert.Equal(t, "604800000", admin.configs["target-b"]["retention.ms"])
+}
+
+func TestCheckTopicConfigDrift_ReportsWithoutChanges(t *testing.T) {
+	admin := newTopicConfigAdmin()
+	restore := kafka.SetAdminClientFactoryForTest(func(cfg config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		return admin, nil
+	})
+	t.Cleanup(restore)
+
+	cfg := topicConfigTestConfig(config.TopicConfigSyncConfig{Overrides: map[string]string{"retention.ms": "1209600000"}})
+	report, err := kafka.CheckTopicConfigDrift(context.Background(), cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, "test-job", report.JobID)
+	assert.True(t, report.DryRun)
+	require.Len(t, report.Topics, 2)
+
+	missing := report.Topics[0]
+	assert.Equal(t, "target-a", missing.TargetTopic)
+	assert.Contains(t, missing.Error, "not found")
+
+	drifted := report.Topics[1]
+	require.Len(t, drifted.Drift, 1)
+	assert.Equal(t, kafka.TopicConfigDrift{
+		Key:          "retention.ms",
+		SourceValue:  "86400000",
+		TargetValue:  "604800000",
+		DesiredValue: "1209600000",
+		Override:     true,
+	}, drifted.Drift[0])
+	assert.Equal(t, 1, report.DriftedTopics)
+	assert.Empty(t, admin.altered)
+	assert.Empty(t, admin.validated)
+}
+
+func TestSyncTopicConfigs_DryRunValidatesOnly(t *testing.T) {
+	admin := newTopicConfigAdmin()
+	restore := kafka.SetAdminClientFactoryForTest(func(cfg config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		return admin, nil
+	})
+	t.Cleanup(restore)
+
+	cfg := topicConfigTestConfig(config.TopicConfigSyncConfig{})
+	report, err := kafka.SyncTopicConfigs(context.Background(), cfg, true)
+	require.NoError(t, err)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 0, report.AppliedTopics)
+	assert.Equal(t, map[string]string{"retention.ms": "86400000"}, admin.validated["target-b"])
+	assert.Empty(t, admin.altered)
+
+	report, err = kafka.SyncTopicConfigs(context.Background(), cfg, false)
+	require.NoError(t, err)
+	assert.False(t, report.DryRun)
+	assert.Equal(t, 1, report.AppliedTopics)
+	assert.Equal(t, "86400000", admin.configs["target-b"]["retention.ms"])
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/tests/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHandleRecord_TracesSampledRecords(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var produced []*kgo.Record
+	var produceErr error
+	mockClient := &mocks.MockKgoClient{
+		ProduceFunc: func(ctx context.Context, r *kgo.Record, f func(*kgo.Record, error)) {
+			produced = append(produced, r)
+			f(r, produceErr)
+		},
+	}
+	km := kafka.NewKafMirrorImplForTest(
+		&kafka.Producer{Client: mockClient},
+		map[string]string{"orders": "orders_copy"},
+		nil,
+	)
+
+	// Not sampled: no spans and the headers pass through untouched.
+	km.HandleRecordForTest(&kgo.Record{Topic: "orders", Value: []byte("a")})
+	require.Len(t, produced, 1)
+	assert.Empty(t, produced[0].Headers)
+	assert.Empty(t, recorder.Ended())
+
+	// Sampled with an upstream traceparent: the mirror continues the source trace.
+	km.SetTraceSampleRatioForTest(1)
+	upstream := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	source := &kgo.Record{
+		Topic:     "orders",
+		Partition: 1,
+		Offset:    99,
+		Value:     []byte("b"),
+		Headers: []kgo.RecordHeader{
+			{Key: "traceparent", Value: []byte(upstream)},
+			{Key: "app", Value: []byte("shop")},
+		},
+	}
+	km.HandleRecordForTest(source)
+	require.Len(t, produced, 2)
+	assert.Equal(t, upstream, string(source.Headers[0].Value), "source headers are not modified")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	produceSpan, consumeSpan := spans[0], spans[1]
+	assert.Equal(t, "orders_copy publish", produceSpan.Name())
+	assert.Equal(t, trace.SpanKindProducer, produceSpan.SpanKind())
+	assert.Equal(t, "orders receive", consumeSpan.Name())
+	assert.Equal(t, trace.SpanKindConsumer, consumeSpan.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", consumeSpan.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", consumeSpan.Parent().SpanID().String())
+	assert.Equal(t, consumeSpan.SpanContext().SpanID(), produceSpan.Parent().SpanID())
+
+	carrier := kafka.RecordHeaderCarrier{Record: produced[1]}
+	assert.Equal(t, "shop", carrier.Get("app"))
+	target := otel.GetTextMapPropagator().Extract(context.Background(), carrier)
+	assert.Equal(t, produceSpan.SpanContext().SpanID(), trace.SpanContextFromContext(target).SpanID(),
+		"the copy carries the producer span as its parent")
+
+	// A failed produce marks the spans as errors.
+	produceErr = errors.New("broker unavailable")
+	km.HandleRecordForTest(&kgo.Record{Topic: "orders", Value: []byte("c")})
+	spans = recorder.Ended()
+	require.Len(t, spans, 4)
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+	assert.Equal(t, codes.Error, spans[3].Status().Code)
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafka_test
+
+import (
+	"context"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/tests/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// fakeReader serves the records of one partition from memory.
+type fakeReader struct {
+	records []*kgo.Record
+}
+
+func (f *fakeReader) ReadRange(ctx context.Context, topic string, partition int32, start, end int64) ([]*kgo.Record, error) {
+	var result []*kgo.Record
+	for _, record := range f.records {
+		if record.Offset >= start && record.Offset < end {
+			result = append(result, record)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeReader) Close() {}
+
+func verifyRecord(topic string, offset int64, value string) *kgo.Record {
+	return &kgo.Record{
+		Topic:     topic,
+		Offset:    offset,
+		Key:       []byte("key-" + value),
+		Value:     []byte(value),
+		Headers:   []kgo.RecordHeader{{Key: "source", Value: []byte("app")}},
+		Timestamp: time.UnixMilli(1700000000000),
+	}
+}
+
+// partitionRecords returns records with the given values at offsets from first on.
+func partitionRecords(topic string, first int64, values ...string) []*kgo.Record {
+	records := make([]*kgo.Record, len(values))
+	for i, value := range values {
+		records[i] = verifyRecord(topic, first+int64(i), value)
+	}
+	return records
+}
+
+func recordValues(from, to int) []string {
+	values := make([]string, 0, to-from)
+	for i := from; i < to; i++ {
+		values = append(values, fmt.Sprintf("v%d", i))
+	}
+	return values
+}
+
+func verifyJob(t *testing.T, source, target *fakeAdmin, sourceRecords, targetRecords []*kgo.Record, sampleRate float64) database.PartitionVerification {
+	restoreAdmin := kafka.SetAdminClientFactoryForTest(func(cfg config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		if cfg.Brokers == "source:9092" {
+			return source, nil
+		}
+		return target, nil
+	})
+	t.Cleanup(restoreAdmin)
+	restoreReader := kafka.SetRecordReaderFactoryForTest(func(cfg config.ClusterConfig, _ config.ReplicationConfig, _ string) (kafka.RecordReader, error) {
+		if cfg.Brokers == "source:9092" {
+			return &fakeReader{records: sourceRecords}, nil
+		}
+		return &fakeReader{records: targetRecords}, nil
+	})
+	t.Cleanup(restoreReader)
+
+	results, err := kafka.VerifyContent(context.Background(), &config.Config{
+		Clusters: map[string]config.ClusterConfig{
+			"source": {Brokers: "source:9092"},
+			"target": {Brokers: "target:9092"},
+		},
+		Topics:      []config.TopicMapping{{Source: "orders", Target: "dr.orders", Enabled: true}},
+		Replication: config.ReplicationConfig{JobID: "job-1"},
+	}, sampleRate)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	return results[0]
+}
+
+func TestVerifyContent_TranslatesOffsetsOfShorterTarget(t *testing.T) {
+	// The target started mirroring at source offset 10 and is 5 records behind.
+	source := &fakeAdmin{
+		hwms:   map[string][]kafka.OffsetInfo{"orders": offsets("orders", 30)},
+		groups: map[string]map[string][]kafka.OffsetInfo{"kaf-mirror-job-job-1": {"orders": offsets("orders", 25)}},
+	}
+	target := &fakeAdmin{hwms: map[string][]kafka.OffsetInfo{"dr.orders": offsets("dr.orders", 15)}}
+
+	result := verifyJob(t, source, target,
+		partitionRecords("orders", 0, recordValues(0, 30)...),
+		partitionRecords("dr.orders", 0, recordValues(10, 25)...), 1)
+
+	assert.Equal(t, int64(-10), result.OffsetDelta)
+	assert.Equal(t, int64(10), result.StartOffset)
+	assert.Equal(t, int64(25), result.EndOffset, "records not mirrored yet are not compared")
+	assert.Equal(t, int64(15), result.RecordsCompared)
+	assert.Equal(t, int64(15), result.Matched)
+	assert.Zero(t, result.Mismatched+result.Missing+result.Duplicates)
+	assert.Equal(t, result.SourceDigest, result.TargetDigest)
+}
+
+func TestVerifyContent_ReportsMismatchedMissingAndDuplicateRecords(t *testing.T) {
+	source := &fakeAdmin{hwms: map[string][]kafka.OffsetInfo{"orders": offsets("orders", 10)}}
+	target := &fakeAdmin{hwms: map[string][]kafka.OffsetInfo{"dr.orders": offsets("dr.orders", 10)}}
+
+	// v3 was written twice, v5 differs and v7 never arrived.
+	targetValues := []string{"v0", "v1", "v2", "v3", "v3", "v4", "changed", "v6", "v8", "v9"}
+	result := verifyJob(t, source, target,
+		partitionRecords("orders", 0, recordValues(0, 10)...),
+		partitionRecords("dr.orders", 0, targetValues...), 1)
+
+	assert.Equal(t, int64(10), result.RecordsCompared)
+	assert.Equal(t, int64(8), result.Matched)
+	assert.Equal(t, int64(1), result.Duplicates)
+	assert.Equal(t, []int64{4}, result.DuplicateOffsets, "duplicates are listed by target offset")
+	assert.Equal(t, int64(1), result.Mismatched)
+	assert.Equal(t, []int64{5}, result.MismatchedOffsets)
+	assert.Equal(t, int64(1), result.Missing)
+	assert.Equal(t, []int64{7}, result.MissingOffsets)
+	assert.NotEqual(t, result.SourceDigest, result.TargetDigest)
+}
+
+func TestVerifyContent_SamplesWindows(t *testing.T) {
+	source := &fakeAdmin{hwms: map[string][]kafka.OffsetInfo{"orders": offsets("orders", 2500)}}
+	target := &fakeAdmin{hwms: map[string][]kafka.OffsetInfo{"dr.orders": offsets("dr.orders", 2500)}}
+	values := recordValues(0, 2500)
+
+	result := verifyJob(t, source, target,
+		partitionRecords("orders", 0, values...), partitionRecords("dr.orders", 0, values...), 0.5)
+
+	assert.Equal(t, int64(1500), result.RecordsCompared, "every other window is compared, including the newest")
+	assert.Equal(t, int64(1500), result.Matched)
+	assert.Zero(t, result.Mismatched+result.Missing+result.Duplicates)
+}
+
+func TestConsumer_ReadRangeStopsAtHighWatermark(t *testing.T) {
+	var added map[string]map[int32]kgo.Offset
+	var removed map[string][]int32
+	consumer := &kafka.Consumer{Client: &mocks.MockKgoClient{
+		AddConsumePartitionsFunc:    func(p map[string]map[int32]kgo.Offset) { added = p },
+		RemoveConsumePartitionsFunc: func(p map[string][]int32) { removed = p },
+		PollFetchesFunc: func(ctx context.Context) kgo.Fetches {
+			return kgo.Fetches{{Topics: []kgo.FetchTopic{{Topic: "orders", Partitions: []kgo.FetchPartition{{
+				Partition:     0,
+				HighWatermark: 6,
+				Records:       partitionRecords("orders", 3, "v3", "v4", "v5"),
+			}}}}}}
+		},
+	}}
+
+	records, err := consumer.ReadRange(context.Background(), "orders", 0, 3, 10)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, int64(5), records[2].Offset)
+	assert.Contains(t, added["orders"], int32(0))
+	assert.Equal(t, map[string][]int32{"orders": {0}}, removed, "the partition is released after the read")
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager_test
+
+import (
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/internal/manager"
+	"kaf-mirror/tests/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// backfillMirror is a mock mirror that reports its ranges as copied when started.
+type backfillMirror struct {
+	mocks.MockKafMirror
+	ranges  []config.BackfillRange
+	handler func(jobID string, progress []kafka.BackfillProgress, done bool)
+}
+
+func (m *backfillMirror) OnBackfillProgress(handler func(jobID string, progress []kafka.BackfillProgress, done bool)) {
+	m.handler = handler
+}
+
+func (m *backfillMirror) Start(jobID string, metricsCallback func(database.ReplicationMetric), onPanic func(jobID string, reason string)) {
+	var progress []kafka.BackfillProgress
+	for _, r := range m.ranges {
+		progress = append(progress, kafka.BackfillProgress{Topic: r.Topic, Partition: r.Partition, NextOffset: r.EndOffset, Done: true})
+	}
+	m.handler(jobID, progress, true)
+}
+
+func TestJobManager_CreateBackfillJobForOffsetRange(t *testing.T) {
+	db, jm, _, _ := setupFailoverTest(t)
+
+	status, err := jm.CreateBackfillJob("job-f", manager.BackfillRequest{
+		Name: "orders-replay",
+		From: config.StartPosition{Mode: config.StartPositionOffsets, Offsets: map[string]map[int32]int64{"orders": {0: 10, 1: 50}}},
+	}, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, manager.BackfillPaused, status.Status)
+	assert.Equal(t, int64(90), status.TotalOffsets, "partition 1 is already at its high water mark")
+	assert.Equal(t, 0.0, status.ProgressPercent)
+	require.Len(t, status.Ranges, 1)
+	assert.Equal(t, int64(10), status.Ranges[0].StartOffset)
+	assert.Equal(t, int64(100), status.Ranges[0].EndOffset)
+
+	job, err := database.GetJob(db, status.JobID)
+	require.NoError(t, err)
+	assert.Equal(t, database.JobTypeBackfill, job.JobType)
+	assert.Equal(t, "paused", job.Status)
+	mappings, err := database.GetMappingsForJob(db, status.JobID)
+	require.NoError(t, err)
+	require.Len(t, mappings, 1)
+	assert.Equal(t, "dr.orders", mappings[0].TargetTopicPattern)
+
+	_, err = jm.CreateBackfillJob("job-f", manager.BackfillRequest{
+		From: config.StartPosition{Mode: config.StartPositionOffsets, Offsets: map[string]map[int32]int64{"orders": {1: 50}}},
+	}, "alice")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no records between")
+
+	_, err = jm.CreateBackfillJob(status.JobID, manager.BackfillRequest{From: config.StartPosition{Mode: config.StartPositionEarliest}}, "alice")
+	assert.Error(t, err, "a backfill job cannot be backfilled")
+
+	_, err = jm.BackfillStatus("job-f")
+	assert.ErrorIs(t, err, manager.ErrNotBackfillJob)
+}
+
+func TestJobManager_BackfillFromGapsResolvesGaps(t *testing.T) {
+	db, jm, _, _ := setupFailoverTest(t)
+	now := time.Now()
+	require.NoError(t, database.DetectMirrorGaps(db, "job-f", []database.MirrorGap{
+		{JobID: "job-f", SourceTopic: "orders", TargetTopic: "dr.orders", PartitionID: 0, GapStartOffset: 90, GapEndOffset: 95, GapSize: 5, DetectedAt: now, GapType: "offset_mismatch", ResolutionStatus: "unresolved"},
+		{JobID: "job-f", SourceTopic: "orders", TargetTopic: "dr.orders", PartitionID: 0, GapStartOffset: 92, GapEndOffset: 100, GapSize: 8, DetectedAt: now, GapType: "offset_mismatch", ResolutionStatus: "unresolved"},
+		{JobID: "job-f", SourceTopic: "orders", TargetTopic: "dr.orders", PartitionID: 1, GapStartOffset: 40, GapEndOffset: 60, GapSize: 20, DetectedAt: now, GapType: "offset_mismatch", ResolutionStatus: "unresolved"},
+	}))
+
+	status, err := jm.CreateBackfillFromGaps("job-f", manager.BackfillFromGapsRequest{}, "alice")
+	require.NoError(t, err)
+	require.Len(t, status.Ranges, 2)
+	assert.Equal(t, int64(90), status.Ranges[0].StartOffset)
+	assert.Equal(t, int64(100), status.Ranges[0].EndOffset)
+	assert.Len(t, status.Ranges[0].GapIDs, 2, "gaps of the same partition are merged")
+	assert.Equal(t, int64(50), status.Ranges[1].EndOffset, "ranges end at the source high water mark")
+
+	unresolved, err := database.GetUnresolvedMirrorGaps(db, "job-f")
+	require.NoError(t, err)
+	assert.Empty(t, unresolved, "the gaps are in progress")
+	_, err = jm.CreateBackfillFromGaps("job-f", manager.BackfillFromGapsRequest{}, "alice")
+	assert.Error(t, err, "gaps in progress are not backfilled twice")
+
+	mirror := &backfillMirror{}
+	jm.KafMirrorFactory = func(cfg *config.Config) (kafka.KafMirror, error) {
+		mirror.ranges = cfg.Replication.Backfill
+		return mirror, nil
+	}
+	require.NoError(t, jm.StartJob(status.JobID))
+	require.Len(t, mirror.ranges, 2)
+	assert.Equal(t, int64(90), mirror.ranges[0].NextOffset)
+
+	require.Eventually(t, func() bool {
+		current, err := jm.BackfillStatus(status.JobID)
+		return err == nil && current.Status == manager.BackfillCompleted
+	}, 5*time.Second, 20*time.Millisecond)
+
+	current, err := jm.BackfillStatus(status.JobID)
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, current.ProgressPercent)
+	require.Eventually(t, func() bool {
+		gaps, err := database.GetMirrorGaps(db, "job-f")
+		if err != nil {
+			return false
+		}
+		for _, gap := range gaps {
+			if gap.ResolutionStatus != "resolved" {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 20*time.Millisecond)
+
+	job, err := database.GetJob(db, status.JobID)
+	require.NoError(t, err)
+	assert.Equal(t, "paused", job.Status, "a finished backfill stops itself")
+	err = jm.StartJob(status.JobID)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already completed")
+}
@@ -0,0 +1,269 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager_test
+
+import (
+	"context"
+	"encoding/json"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/internal/manager"
+	"kaf-mirror/tests/mocks"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failoverAdmin serves offsets from memory and records offset commits.
+type failoverAdmin struct {
+	mocks.MockAdminClient
+	mu      sync.Mutex
+	hwms    map[string][]kafka.OffsetInfo
+	groups  map[string]map[string][]kafka.OffsetInfo
+	commits map[string]map[string]map[int32]int64
+}
+
+func (f *failoverAdmin) setHighWaterMarks(topic string, hwms ...int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.hwms == nil {
+		f.hwms = map[string][]kafka.OffsetInfo{}
+	}
+	f.hwms[topic] = partitionOffsets(topic, hwms...)
+}
+
+func (f *failoverAdmin) committed(group string) map[string]map[int32]int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.commits[group]
+}
+
+func (f *failoverAdmin) GetTopicHighWaterMarks(ctx context.Context, topics []string) (map[string][]kafka.OffsetInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := map[string][]kafka.OffsetInfo{}
+	for _, topic := range topics {
+		if offsets, ok := f.hwms[topic]; ok {
+			result[topic] = offsets
+		}
+	}
+	return result, nil
+}
+
+func (f *failoverAdmin) GetConsumerGroupOffsets(ctx context.Context, groupID string, topics []string) (map[string][]kafka.OffsetInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.groups[groupID], nil
+}
+
+func (f *failoverAdmin) ListConsumerGroups(ctx context.Context) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	groups := []string{}
+	for group := range f.groups {
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+func (f *failoverAdmin) CommitConsumerGroupOffsets(ctx context.Context, groupID string, offsets map[string]map[int32]int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.commits == nil {
+		f.commits = map[string]map[string]map[int32]int64{}
+	}
+	f.commits[groupID] = offsets
+	return nil
+}
+
+func (f *failoverAdmin) Close() {}
+
+func partitionOffsets(topic string, offsets ...int64) []kafka.OffsetInfo {
+	result := make([]kafka.OffsetInfo, len(offsets))
+	for i, offset := range offsets {
+		result[i] = kafka.OffsetInfo{Topic: topic, Partition: int32(i), Offset: offset, HighWaterMark: offset}
+	}
+	return result
+}
+
+// setupFailoverTest creates job-f mirroring orders from src to dr.orders on tgt,
+// with a billing consumer group on the source.
+func setupFailoverTest(t *testing.T) (*sqlx.DB, *manager.JobManager, *failoverAdmin, *failoverAdmin) {
+	db, jm, _ := setupManagerTest(t)
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "src", Brokers: "source:9092", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "tgt", Brokers: "target:9092", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateJob(db, &database.ReplicationJob{ID: "job-f", Name: "orders", SourceClusterName: "src", TargetClusterName: "tgt", Status: "paused"}))
+	require.NoError(t, database.UpdateMappingsForJob(db, "job-f", []database.TopicMapping{{SourceTopicPattern: "orders", TargetTopicPattern: "dr.orders", Enabled: true}}))
+
+	source := &failoverAdmin{groups: map[string]map[string][]kafka.OffsetInfo{
+		"billing": {"orders": partitionOffsets("orders", 95, 50)},
+	}}
+	source.setHighWaterMarks("orders", 100, 50)
+	target := &failoverAdmin{}
+	target.setHighWaterMarks("dr.orders", 100, 50)
+
+	restore := kafka.SetAdminClientFactoryForTest(func(cfg config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		if cfg.Brokers == "source:9092" {
+			return source, nil
+		}
+		return target, nil
+	})
+	t.Cleanup(restore)
+	return db, jm, source, target
+}
+
+func waitForFailover(t *testing.T, jm *manager.JobManager, id int) *database.FailoverOperation {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		op, err := jm.GetFailover("job-f", id)
+		require.NoError(t, err)
+		if op.Status != database.FailoverRunning {
+			return op
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("failover %d still running", id)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestJobManager_FailoverWithReverseJob(t *testing.T) {
+	db, jm, _, target := setupFailoverTest(t)
+	mirror := &mocks.MockKafMirror{}
+	jm.KafMirrorFactory = func(cfg *config.Config) (kafka.KafMirror, error) {
+		return mirror, nil
+	}
+	require.NoError(t, jm.StartJob("job-f"))
+
+	started, err := jm.StartFailover("job-f", database.FailoverOptions{CreateReverseJob: true, Reason: "DR drill"}, "alice")
+	require.NoError(t, err)
+	op := waitForFailover(t, jm, started.ID)
+
+	require.Equal(t, database.FailoverCompleted, op.Status, "last error: %v", op.LastError)
+	names := []string{}
+	for _, step := range op.Steps {
+		assert.Equal(t, database.FailoverStepCompleted, step.Status, step.Name)
+		names = append(names, step.Name)
+	}
+	assert.Equal(t, []string{"drain", "checkpoint", "validate", "sync_offsets", "stop_job", "reverse_job"}, names)
+
+	job, err := database.GetJob(db, "job-f")
+	require.NoError(t, err)
+	assert.Equal(t, "paused", job.Status)
+
+	require.NotNil(t, op.CheckpointID)
+	checkpoint, err := database.GetMigrationCheckpoint(db, *op.CheckpointID)
+	require.NoError(t, err)
+	assert.Equal(t, "DR drill", *checkpoint.MigrationReason)
+	assert.Equal(t, "alice", checkpoint.CreatedBy)
+	assert.NotNil(t, checkpoint.ValidationResults)
+	var targetHWMs map[string]map[int32]int64
+	require.NoError(t, json.Unmarshal([]byte(checkpoint.TargetHighWaterMarks), &targetHWMs))
+	assert.Equal(t, map[string]map[int32]int64{"dr.orders": {0: 100, 1: 50}}, targetHWMs)
+
+	assert.Equal(t, map[string]map[int32]int64{"dr.orders": {0: 95, 1: 50}}, target.committed("billing"))
+
+	require.NotNil(t, op.ReverseJobID)
+	reverse, err := database.GetJob(db, *op.ReverseJobID)
+	require.NoError(t, err)
+	assert.Equal(t, "orders-failback", reverse.Name)
+	assert.Equal(t, "tgt", reverse.SourceClusterName)
+	assert.Equal(t, "src", reverse.TargetClusterName)
+	assert.Equal(t, "paused", reverse.Status)
+	mappings, err := database.GetMappingsForJob(db, reverse.ID)
+	require.NoError(t, err)
+	require.Len(t, mappings, 1)
+	assert.Equal(t, "dr.orders", mappings[0].SourceTopicPattern)
+	assert.Equal(t, "orders", mappings[0].TargetTopicPattern)
+	assert.Equal(t, targetHWMs, target.committed(kafka.JobConsumerGroup(reverse.ID)))
+
+	events, err := database.ListOperationalEvents(db)
+	require.NoError(t, err)
+	failoverEvents := 0
+	for _, event := range events {
+		if event.EventType == "failover" {
+			failoverEvents++
+			assert.Equal(t, "alice", event.Initiator)
+		}
+	}
+	assert.Equal(t, 8, failoverEvents, "start, six steps and completion")
+}
+
+func TestJobManager_FailoverResumesFailedStep(t *testing.T) {
+	db, jm, _, target := setupFailoverTest(t)
+	defer manager.SetFailoverPollIntervalForTest(5 * time.Millisecond)()
+	target.setHighWaterMarks("dr.orders", 90, 50)
+
+	started, err := jm.StartFailover("job-f", database.FailoverOptions{DrainTimeout: "30ms"}, "alice")
+	require.NoError(t, err)
+	op := waitForFailover(t, jm, started.ID)
+
+	require.Equal(t, database.FailoverFailed, op.Status)
+	require.NotNil(t, op.LastError)
+	assert.Contains(t, *op.LastError, "lag did not reach zero within 30ms, 10 records outstanding")
+	assert.Equal(t, database.FailoverStepFailed, op.Steps[0].Status)
+	assert.Equal(t, database.FailoverStepPending, op.Steps[1].Status)
+	assert.Nil(t, target.committed("billing"))
+
+	_, err = jm.StartFailover("job-f", database.FailoverOptions{DrainTimeout: "nope"}, "alice")
+	assert.Error(t, err)
+
+	target.setHighWaterMarks("dr.orders", 100, 50)
+	_, err = jm.ResumeFailover("job-f", op.ID, "bob")
+	require.NoError(t, err)
+	op = waitForFailover(t, jm, op.ID)
+
+	require.Equal(t, database.FailoverCompleted, op.Status, "last error: %v", op.LastError)
+	assert.Nil(t, op.LastError)
+	assert.Equal(t, database.FailoverStepCompleted, op.Steps[0].Status)
+	assert.Equal(t, database.FailoverStepSkipped, op.Steps[5].Status)
+	assert.Nil(t, op.ReverseJobID)
+	assert.NotNil(t, target.committed("billing"))
+
+	_, err = jm.ResumeFailover("job-f", op.ID, "bob")
+	assert.Error(t, err, "completed failovers cannot be resumed")
+
+	ops, err := database.ListFailoverOperations(db, "job-f")
+	require.NoError(t, err)
+	assert.Len(t, ops, 1)
+}
+
+func TestJobManager_PlanFailoverChangesNothing(t *testing.T) {
+	db, jm, _, target := setupFailoverTest(t)
+	target.setHighWaterMarks("dr.orders", 90, 50)
+
+	report, err := jm.PlanFailover("job-f", database.FailoverOptions{CreateReverseJob: true}, "alice")
+	require.NoError(t, err)
+
+	assert.True(t, report.DryRun)
+	assert.Equal(t, manager.FailoverPlanned, report.Status)
+	require.Len(t, report.Steps, 6)
+	for _, step := range report.Steps {
+		assert.Equal(t, database.FailoverStepPlanned, step.Status, step.Name)
+	}
+	assert.Equal(t, "10 records outstanding, would wait up to 5m0s for zero lag", report.Steps[0].Details)
+	assert.True(t, strings.HasSuffix(report.Steps[3].Details, ": billing"), report.Steps[3].Details)
+
+	assert.Nil(t, target.committed("billing"))
+	ops, err := database.ListFailoverOperations(db, "job-f")
+	require.NoError(t, err)
+	assert.Empty(t, ops)
+	jobs, err := database.ListJobs(db)
+	require.NoError(t, err)
+	assert.Len(t, jobs, 1)
+}
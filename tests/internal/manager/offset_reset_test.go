@@ -0,0 +1,112 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager_test
+
+import (
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/internal/manager"
+	"kaf-mirror/tests/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobManager_ResetJobOffsetsPreviewAndApply(t *testing.T) {
+	db, jm, source, _ := setupFailoverTest(t)
+	source.groups[kafka.JobConsumerGroup("job-f")] = map[string][]kafka.OffsetInfo{"orders": partitionOffsets("orders", 80, 50)}
+	req := manager.OffsetResetRequest{Position: config.StartPosition{Mode: config.StartPositionEarliest}}
+
+	preview, err := jm.ResetJobOffsets("job-f", req, true, "alice")
+	require.NoError(t, err)
+	assert.True(t, preview.DryRun)
+	assert.Equal(t, "kaf-mirror-job-job-f", preview.ConsumerGroup)
+	require.Len(t, preview.Partitions, 2)
+	assert.Equal(t, int64(80), preview.Partitions[0].CommittedOffset)
+	assert.Equal(t, int64(0), preview.Partitions[0].Offset)
+	assert.Equal(t, int64(100), preview.Partitions[0].Lag)
+	assert.Nil(t, source.committed("kaf-mirror-job-job-f"), "a preview commits nothing")
+
+	reset, err := jm.ResetJobOffsets("job-f", req, false, "alice")
+	require.NoError(t, err)
+	assert.False(t, reset.DryRun)
+	assert.Equal(t, map[string]map[int32]int64{"orders": {0: 0, 1: 0}}, source.committed("kaf-mirror-job-job-f"))
+
+	events, err := database.ListOperationalEvents(db)
+	require.NoError(t, err)
+	found := false
+	for _, event := range events {
+		if event.EventType == "offset_reset" {
+			found = true
+			assert.Equal(t, "alice", event.Initiator)
+			assert.Contains(t, event.Details, "reset 2 partitions of [orders] to the earliest start position")
+		}
+	}
+	assert.True(t, found, "the reset is recorded as an operational event")
+}
+
+func TestJobManager_ResetJobOffsetsValidates(t *testing.T) {
+	_, jm, source, _ := setupFailoverTest(t)
+
+	_, err := jm.ResetJobOffsets("job-f", manager.OffsetResetRequest{}, true, "alice")
+	assert.Error(t, err, "a mode is required")
+
+	_, err = jm.ResetJobOffsets("job-f", manager.OffsetResetRequest{
+		Position: config.StartPosition{Mode: config.StartPositionLatest},
+		Topics:   []string{"payments"},
+	}, true, "alice")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "payments is not mirrored")
+
+	_, err = jm.ResetJobOffsets("job-f", manager.OffsetResetRequest{
+		Position: config.StartPosition{Mode: config.StartPositionOffsets, Offsets: map[string]map[int32]int64{"orders": {0: 500}}},
+	}, false, "alice")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "outside the available range 0-100")
+	assert.Nil(t, source.committed("kaf-mirror-job-job-f"))
+
+	jm.KafMirrorFactory = func(cfg *config.Config) (kafka.KafMirror, error) {
+		return &mocks.MockKafMirror{}, nil
+	}
+	require.NoError(t, jm.StartJob("job-f"))
+	_, err = jm.ResetJobOffsets("job-f", manager.OffsetResetRequest{Position: config.StartPosition{Mode: config.StartPositionLatest}}, false, "alice")
+	assert.ErrorIs(t, err, manager.ErrResetRunningJob)
+
+	preview, err := jm.ResetJobOffsets("job-f", manager.OffsetResetRequest{Position: config.StartPosition{Mode: config.StartPositionLatest}}, true, "alice")
+	require.NoError(t, err, "previews work while the job runs")
+	assert.Equal(t, int64(100), preview.Partitions[0].Offset)
+}
+
+func TestJobManager_BuildsJobConfigWithStartPositions(t *testing.T) {
+	db, jm, _, _ := setupFailoverTest(t)
+	job, err := database.GetJob(db, "job-f")
+	require.NoError(t, err)
+	job.StartPosition = &database.StartPosition{Mode: config.StartPositionTimestamp, Timestamp: "7d"}
+	require.NoError(t, database.UpdateJob(db, job))
+	require.NoError(t, database.UpdateMappingsForJob(db, "job-f", []database.TopicMapping{{
+		SourceTopicPattern: "orders", TargetTopicPattern: "dr.orders", Enabled: true,
+		StartPosition: &database.StartPosition{Mode: config.StartPositionLatest},
+	}}))
+
+	var built *config.Config
+	jm.KafMirrorFactory = func(cfg *config.Config) (kafka.KafMirror, error) {
+		built = cfg
+		return &mocks.MockKafMirror{}, nil
+	}
+	require.NoError(t, jm.StartJob("job-f"))
+
+	require.NotNil(t, built)
+	assert.Equal(t, config.StartPosition{Mode: config.StartPositionTimestamp, Timestamp: "7d"}, built.Replication.StartPosition)
+	assert.Equal(t, config.StartPosition{Mode: config.StartPositionLatest}, built.Topics[0].StartPosition)
+}
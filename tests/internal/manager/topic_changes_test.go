@@ -0,0 +1,66 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager_test
+
+import (
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/tests/mocks"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// notifyingMirror is a mock mirror that reports topic changes.
+type notifyingMirror struct {
+	mocks.MockKafMirror
+	handler func(jobID string, change kafka.TopicChange)
+}
+
+func (m *notifyingMirror) OnTopicChange(handler func(jobID string, change kafka.TopicChange)) {
+	m.handler = handler
+}
+
+func TestJobManager_RecordsTopicChanges(t *testing.T) {
+	db, jm, _ := setupManagerTest(t)
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "src", Brokers: "localhost:9092", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "tgt", Brokers: "localhost:9093", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateJob(db, &database.ReplicationJob{ID: "job-t", Name: "orders", SourceClusterName: "src", TargetClusterName: "tgt", Status: "paused"}))
+
+	mirror := &notifyingMirror{}
+	jm.KafMirrorFactory = func(cfg *config.Config) (kafka.KafMirror, error) {
+		return mirror, nil
+	}
+	require.NoError(t, jm.StartJob("job-t"))
+	require.NotNil(t, mirror.handler)
+
+	mirror.handler("job-t", kafka.TopicChange{
+		Type:        kafka.TopicChangePartitionsIncreased,
+		SourceTopic: "orders",
+		TargetTopic: "orders-dr",
+		Details:     "Source topic orders grew to 24 partitions, target topic orders-dr grown from 12",
+	})
+
+	events, err := database.ListOperationalEvents(db)
+	require.NoError(t, err)
+	var found *database.OperationalEvent
+	for i := range events {
+		if events[i].EventType == kafka.TopicChangePartitionsIncreased {
+			found = &events[i]
+		}
+	}
+	require.NotNil(t, found)
+	assert.Equal(t, "system", found.Initiator)
+	assert.Equal(t, "Job job-t: Source topic orders grew to 24 partitions, target topic orders-dr grown from 12", found.Details)
+}
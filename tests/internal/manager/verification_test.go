@@ -0,0 +1,129 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager_test
+
+import (
+	"context"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/internal/manager"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// generatedReader returns a record per offset whose value names the partition
+// and offset, optionally with one offset holding a different value.
+type generatedReader struct {
+	changedOffset int64
+}
+
+func (r generatedReader) ReadRange(ctx context.Context, topic string, partition int32, start, end int64) ([]*kgo.Record, error) {
+	var records []*kgo.Record
+	for offset := start; offset < end; offset++ {
+		value := fmt.Sprintf("%d-%d", partition, offset)
+		if offset == r.changedOffset {
+			value = "changed"
+		}
+		records = append(records, &kgo.Record{Topic: topic, Partition: partition, Offset: offset, Value: []byte(value)})
+	}
+	return records, nil
+}
+
+func (r generatedReader) Close() {}
+
+func setRecordReaders(t *testing.T, target generatedReader) {
+	restore := kafka.SetRecordReaderFactoryForTest(func(cfg config.ClusterConfig, _ config.ReplicationConfig, _ string) (kafka.RecordReader, error) {
+		if cfg.Brokers == "source:9092" {
+			return generatedReader{changedOffset: -1}, nil
+		}
+		return target, nil
+	})
+	t.Cleanup(restore)
+}
+
+func waitForVerification(t *testing.T, jm *manager.JobManager, id int) *database.ContentVerification {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		v, err := jm.GetContentVerification("job-f", id)
+		require.NoError(t, err)
+		if v.Status != database.VerificationRunning {
+			return v
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("content verification %d still running", id)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestJobManager_ContentVerification(t *testing.T) {
+	db, jm, _, _ := setupFailoverTest(t)
+	setRecordReaders(t, generatedReader{changedOffset: 60})
+
+	started, err := jm.StartContentVerification("job-f", 0, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, database.VerificationRunning, started.Status)
+	assert.Equal(t, 1.0, started.SampleRate, "no sample rate compares everything")
+
+	v := waitForVerification(t, jm, started.ID)
+	require.Equal(t, database.VerificationCompleted, v.Status, "last error: %v", v.LastError)
+	assert.False(t, v.Consistent)
+	assert.Equal(t, int64(150), v.RecordsCompared)
+	assert.Equal(t, int64(1), v.MismatchedRecords)
+	assert.Zero(t, v.MissingRecords+v.DuplicateRecords)
+	require.Len(t, v.Partitions, 2)
+	assert.Equal(t, []int64{60}, v.Partitions[0].MismatchedOffsets)
+
+	state, err := database.GetMirrorStateData(db, "job-f")
+	require.NoError(t, err)
+	require.NotNil(t, state.ContentVerification, "the latest result is part of the mirror state")
+	assert.Equal(t, v.ID, state.ContentVerification.ID)
+
+	events, err := database.ListOperationalEvents(db)
+	require.NoError(t, err)
+	found := false
+	for _, event := range events {
+		if event.EventType == "content_verification" {
+			found = true
+			assert.Equal(t, "alice", event.Initiator)
+			assert.Contains(t, event.Details, "1 mismatched")
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestJobManager_ContentVerificationRejectsConcurrentRuns(t *testing.T) {
+	db, jm, _, _ := setupFailoverTest(t)
+	setRecordReaders(t, generatedReader{changedOffset: -1})
+
+	_, err := jm.StartContentVerification("job-f", 1.5, "alice")
+	assert.Error(t, err)
+
+	running := &database.ContentVerification{JobID: "job-f", Status: database.VerificationRunning, SampleRate: 1}
+	require.NoError(t, database.CreateContentVerification(db, running))
+	_, err = jm.StartContentVerification("job-f", 1, "alice")
+	assert.ErrorIs(t, err, manager.ErrVerificationInProgress)
+
+	running.Status = database.VerificationCompleted
+	running.Consistent = true
+	require.NoError(t, database.UpdateContentVerification(db, running))
+	started, err := jm.StartContentVerification("job-f", 0.5, "alice")
+	require.NoError(t, err)
+	v := waitForVerification(t, jm, started.ID)
+	assert.True(t, v.Consistent, "last error: %v", v.LastError)
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manager_test
+
+import (
+	"encoding/json"
+	"io"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/tests/mocks"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobManager_WebhookLifecycleEvents(t *testing.T) {
+	db, jm, _ := setupManagerTest(t)
+
+	var mu sync.Mutex
+	var events []string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Event string `json:"event"`
+			JobID string `json:"job_id"`
+			Data  struct {
+				Status       string `json:"status"`
+				FailedReason string `json:"failed_reason"`
+			} `json:"data"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		if json.Unmarshal(body, &payload) == nil {
+			mu.Lock()
+			events = append(events, payload.Event+":"+payload.JobID+":"+payload.Data.Status+payload.Data.FailedReason)
+			mu.Unlock()
+		}
+	}))
+	defer receiver.Close()
+	require.NoError(t, database.CreateWebhook(db, &database.Webhook{
+		Name: "ops", URL: receiver.URL, Secret: "s", Enabled: true,
+		EventTypes: "job.started,job.stopped,job.failed",
+	}))
+
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "src", Brokers: "localhost:9092", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "tgt", Brokers: "localhost:9093", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateJob(db, &database.ReplicationJob{ID: "job-w", Name: "orders", SourceClusterName: "src", TargetClusterName: "tgt", Status: "paused"}))
+
+	var onPanic func(jobID, reason string)
+	jm.KafMirrorFactory = func(cfg *config.Config) (kafka.KafMirror, error) {
+		return &mocks.MockKafMirror{
+			StartFunc: func(jobID string, _ func(database.ReplicationMetric), panicHandler func(string, string)) {
+				onPanic = panicHandler
+			},
+			StopFunc: func() {},
+		}, nil
+	}
+
+	require.NoError(t, jm.StartJob("job-w"))
+	require.NoError(t, jm.StopJob("job-w"))
+	require.NoError(t, jm.StartJob("job-w"))
+	onPanic("job-w", "broker unreachable")
+
+	expected := []string{"job.started:job-w:active", "job.stopped:job-w:paused", "job.started:job-w:active", "job.failed:job-w:failedbroker unreachable"}
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events) == len(expected)
+	}, 5*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, expected, events)
+}
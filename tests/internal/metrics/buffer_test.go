@@ -0,0 +1,217 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/metrics"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakySink records delivered batches and fails while down is set.
+type flakySink struct {
+	mu      sync.Mutex
+	down    bool
+	batches [][]string
+	block   chan struct{}
+}
+
+func (f *flakySink) Send(metric database.ReplicationMetric) error {
+	return f.SendBatch([]database.ReplicationMetric{metric})
+}
+
+func (f *flakySink) SendBatch(batch []database.ReplicationMetric) error {
+	if f.block != nil {
+		<-f.block
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.down {
+		return errors.New("connection refused")
+	}
+	ids := make([]string, 0, len(batch))
+	for _, m := range batch {
+		ids = append(ids, m.JobID)
+	}
+	f.batches = append(f.batches, ids)
+	return nil
+}
+
+func (f *flakySink) setDown(down bool) {
+	f.mu.Lock()
+	f.down = down
+	f.mu.Unlock()
+}
+
+func (f *flakySink) delivered() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var ids []string
+	for _, b := range f.batches {
+		ids = append(ids, b...)
+	}
+	return ids
+}
+
+func fastBuffer() config.SinkBufferConfig {
+	return config.SinkBufferConfig{
+		QueueSize:      10,
+		BatchSize:      3,
+		FlushInterval:  "10ms",
+		MaxRetries:     2,
+		InitialBackoff: "1ms",
+		MaxBackoff:     "2ms",
+	}
+}
+
+func TestBufferedSink_BatchesMetrics(t *testing.T) {
+	sink := &flakySink{}
+	buffered, err := metrics.NewBufferedSink("fake", sink, fastBuffer())
+	require.NoError(t, err)
+
+	for _, id := range []string{"a", "b", "c", "d"} {
+		require.NoError(t, buffered.Send(database.ReplicationMetric{JobID: id}))
+	}
+	require.NoError(t, buffered.Close())
+
+	assert.Equal(t, []string{"a", "b", "c", "d"}, sink.delivered())
+	sink.mu.Lock()
+	assert.Equal(t, []string{"a", "b", "c"}, sink.batches[0], "full batches go out together")
+	sink.mu.Unlock()
+
+	health := buffered.Health()
+	assert.True(t, health.Healthy)
+	assert.Equal(t, uint64(4), health.Sent)
+	assert.NotNil(t, health.LastSuccessAt)
+}
+
+func TestBufferedSink_SpillsDuringOutageAndReplays(t *testing.T) {
+	sink := &flakySink{down: true}
+	cfg := fastBuffer()
+	cfg.SpillDir = t.TempDir()
+	buffered, err := metrics.NewBufferedSink("fake", sink, cfg)
+	require.NoError(t, err)
+	defer buffered.Close()
+
+	require.NoError(t, buffered.Send(database.ReplicationMetric{JobID: "a", Timestamp: time.Now()}))
+	require.Eventually(t, func() bool { return buffered.Health().Spilled == 1 }, time.Second, 5*time.Millisecond)
+
+	health := buffered.Health()
+	assert.False(t, health.Healthy)
+	assert.Equal(t, "connection refused", health.LastError)
+	assert.Equal(t, uint64(2), health.FailedAttempts, "one attempt plus one retry")
+	assert.Positive(t, health.SpillBytes)
+	assert.Zero(t, health.Dropped)
+
+	sink.setDown(false)
+	require.NoError(t, buffered.Send(database.ReplicationMetric{JobID: "b"}))
+	require.Eventually(t, func() bool { return len(sink.delivered()) == 2 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{"b", "a"}, sink.delivered(), "spilled metrics are replayed after recovery")
+
+	health = buffered.Health()
+	assert.True(t, health.Healthy)
+	assert.Zero(t, health.SpillBytes)
+}
+
+func TestBufferedSink_DropsWhenQueueFullWithoutSpill(t *testing.T) {
+	sink := &flakySink{block: make(chan struct{})}
+	cfg := fastBuffer()
+	cfg.QueueSize = 2
+	cfg.BatchSize = 1
+	buffered, err := metrics.NewBufferedSink("fake", sink, cfg)
+	require.NoError(t, err)
+
+	// The worker is stuck on the first metric; Send must still return at once.
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			buffered.Send(database.ReplicationMetric{JobID: "x"})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Send blocked on a stalled sink")
+	}
+
+	health := buffered.Health()
+	assert.Equal(t, 2, health.QueueCapacity)
+	assert.GreaterOrEqual(t, health.Dropped, uint64(7))
+
+	close(sink.block)
+	require.NoError(t, buffered.Close())
+	assert.Equal(t, uint64(10), buffered.Health().Sent+buffered.Health().Dropped)
+}
+
+func TestNewSink_FansOutToAllConfiguredSinks(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string]int{}
+	record := func(name string, status int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			received[name] += strings.Count(string(body), `"job_id"`)
+			mu.Unlock()
+			w.WriteHeader(status)
+		}))
+	}
+	loki := record("loki", http.StatusNoContent)
+	defer loki.Close()
+	splunk := record("splunk", http.StatusOK)
+	defer splunk.Close()
+
+	sink, err := metrics.NewSink(config.MonitoringConfig{
+		Enabled: true,
+		Sinks:   []string{"loki", "splunk"},
+		Loki:    config.LokiConfig{Endpoint: loki.URL},
+		Splunk:  config.SplunkConfig{HECEndpoint: splunk.URL, HECToken: "t"},
+		Buffer:  fastBuffer(),
+	})
+	require.NoError(t, err)
+	fanOut, ok := sink.(*metrics.FanOut)
+	require.True(t, ok)
+
+	require.NoError(t, sink.Send(database.ReplicationMetric{JobID: "job-1", Timestamp: time.Now()}))
+	require.NoError(t, sink.Send(database.ReplicationMetric{JobID: "job-2", Timestamp: time.Now()}))
+	require.NoError(t, fanOut.Close())
+
+	mu.Lock()
+	assert.Equal(t, 2, received["loki"])
+	assert.Equal(t, 2, received["splunk"])
+	mu.Unlock()
+
+	health := fanOut.Health()
+	require.Len(t, health, 2)
+	assert.Equal(t, "loki", health[0].Name)
+	assert.Equal(t, "splunk", health[1].Name)
+	for _, h := range health {
+		assert.True(t, h.Healthy)
+		assert.Equal(t, uint64(2), h.Sent)
+	}
+
+	out, err := json.Marshal(health[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"dropped":0`)
+}
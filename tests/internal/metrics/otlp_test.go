@@ -0,0 +1,126 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics_test
+
+import (
+	"io"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/metrics"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// metricsCollector stands in for an OTLP/HTTP collector and keeps the
+// gauge data points it receives, keyed by metric name.
+type metricsCollector struct {
+	mu     sync.Mutex
+	points map[string][]*metricpb.NumberDataPoint
+}
+
+func (c *metricsCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/metrics" {
+		http.NotFound(w, r)
+		return
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req colmetricpb.ExportMetricsServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	for _, rm := range req.ResourceMetrics {
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if g := m.GetGauge(); g != nil {
+					c.points[m.Name] = append(c.points[m.Name], g.DataPoints...)
+				}
+			}
+		}
+	}
+	c.mu.Unlock()
+	out, _ := proto.Marshal(&colmetricpb.ExportMetricsServiceResponse{})
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Write(out)
+}
+
+func attr(p *metricpb.NumberDataPoint, key string) *commonpb.AnyValue {
+	for _, kv := range p.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return &commonpb.AnyValue{}
+}
+
+func TestOTLPSink_ExportsJobAndPartitionGauges(t *testing.T) {
+	collector := &metricsCollector{points: make(map[string][]*metricpb.NumberDataPoint)}
+	srv := httptest.NewServer(collector)
+	defer srv.Close()
+
+	sink, err := metrics.NewOTLPSink(config.OTLPConfig{
+		Endpoint:       strings.TrimPrefix(srv.URL, "http://"),
+		Protocol:       "http",
+		Insecure:       true,
+		ExportInterval: "1h",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Send(database.ReplicationMetric{
+		JobID:              "job-1",
+		MessagesReplicated: 42,
+		CurrentLag:         7,
+		PartitionLags: []database.PartitionLag{
+			{Topic: "orders", Partition: 3, LagSeconds: 1.5, MessageLag: 12},
+		},
+	}))
+	// Close flushes the pending collection to the collector.
+	require.NoError(t, sink.Close())
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	replicated := collector.points["kaf_mirror_messages_replicated"]
+	require.Len(t, replicated, 1)
+	assert.Equal(t, 42.0, replicated[0].GetAsDouble())
+	assert.Equal(t, "job-1", attr(replicated[0], "job_id").GetStringValue())
+
+	partitionLag := collector.points["kaf_mirror_partition_message_lag"]
+	require.Len(t, partitionLag, 1)
+	assert.Equal(t, int64(12), partitionLag[0].GetAsInt())
+	assert.Equal(t, "orders", attr(partitionLag[0], "topic").GetStringValue())
+	assert.Equal(t, int64(3), attr(partitionLag[0], "partition").GetIntValue())
+}
+
+func TestNewSink_OTLPRequiresKnownProtocol(t *testing.T) {
+	_, err := metrics.NewSink(config.MonitoringConfig{
+		Enabled:  true,
+		Platform: "otlp",
+		OTLP:     config.OTLPConfig{Endpoint: "localhost:4317", Protocol: "thrift"},
+	})
+	assert.Error(t, err)
+}
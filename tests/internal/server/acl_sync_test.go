@@ -0,0 +1,84 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/tests/mocks"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobACLSyncAPI(t *testing.T) {
+	ctx := setupTestServer(t)
+	db := ctx.Server.Db
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "src", Brokers: "localhost:9092", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "tgt", Brokers: "localhost:9093", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateJob(db, &database.ReplicationJob{
+		ID: "job-a", Name: "job-a", SourceClusterName: "src", TargetClusterName: "tgt", Status: "paused",
+	}))
+	require.NoError(t, database.UpdateMappingsForJob(db, "job-a", []database.TopicMapping{
+		{SourceTopicPattern: "orders", TargetTopicPattern: "orders-dr", Enabled: true},
+	}))
+
+	read := kafka.ACLEntry{Principal: "User:app", Host: "*", ResourceType: "topic", ResourceName: "orders", PatternType: "literal", Operation: "read", Permission: "allow"}
+	translated := read
+	translated.ResourceName = "orders-dr"
+
+	source := &mocks.MockAdminClient{}
+	source.On("DescribeACLs", mock.Anything).Return([]kafka.ACLEntry{read}, nil)
+	source.On("Close").Return()
+	target := &mocks.MockAdminClient{}
+	target.On("DescribeACLs", mock.Anything).Return([]kafka.ACLEntry{}, nil)
+	target.On("Close").Return()
+	restore := kafka.SetAdminClientFactoryForTest(func(cfg config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		if cfg.Brokers == "localhost:9092" {
+			return source, nil
+		}
+		return target, nil
+	})
+	t.Cleanup(restore)
+
+	var report kafka.ACLSyncReport
+	status := alertsRequest(t, ctx, "GET", "/api/v1/jobs/job-a/acl-sync", "", &report)
+	require.Equal(t, http.StatusOK, status)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, []kafka.ACLEntry{translated}, report.Missing)
+	target.AssertNotCalled(t, "CreateACLs", mock.Anything, mock.Anything)
+
+	target.On("CreateACLs", mock.Anything, []kafka.ACLEntry{translated}).Return(nil).Once()
+	status = alertsRequest(t, ctx, "POST", "/api/v1/jobs/job-a/acl-sync", "", &report)
+	require.Equal(t, http.StatusOK, status)
+	assert.False(t, report.DryRun)
+	assert.Equal(t, 1, report.CreatedACLs)
+	target.AssertExpectations(t)
+
+	events, err := database.ListOperationalEvents(db)
+	require.NoError(t, err)
+	found := false
+	for _, event := range events {
+		if event.EventType == "acl_sync" {
+			found = true
+			assert.Equal(t, "testuser", event.Initiator)
+		}
+	}
+	assert.True(t, found, "applied ACL sync should be recorded as an operational event")
+
+	status = alertsRequest(t, ctx, "GET", "/api/v1/jobs/missing/acl-sync", "", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+}
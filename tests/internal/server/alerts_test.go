@@ -0,0 +1,157 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"kaf-mirror/internal/database"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func alertsRequest(t *testing.T, ctx *TestContext, method, path, payload string, out interface{}) int {
+	var body *bytes.Buffer
+	if payload != "" {
+		body = bytes.NewBufferString(payload)
+	} else {
+		body = &bytes.Buffer{}
+	}
+	req := httptest.NewRequest(method, path, body)
+	req.Header.Set("Content-Type", "application/json")
+	addAuthHeader(req, ctx.Token)
+
+	resp, err := ctx.Server.App.Test(req)
+	require.NoError(t, err)
+	if out != nil && resp.StatusCode < 300 {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+	return resp.StatusCode
+}
+
+func TestAlertRulesAPI(t *testing.T) {
+	ctx := setupTestServer(t)
+	require.NoError(t, database.CreateJob(ctx.Server.Db, &database.ReplicationJob{
+		ID: "job-a", Name: "job-a", SourceClusterName: "src", TargetClusterName: "tgt", Status: "paused",
+	}))
+
+	var rule struct {
+		ID        int      `json:"id"`
+		Name      string   `json:"name"`
+		Type      string   `json:"type"`
+		JobID     string   `json:"job_id"`
+		Threshold float64  `json:"threshold"`
+		For       string   `json:"for"`
+		Severity  string   `json:"severity"`
+		Channels  []string `json:"channels"`
+		Enabled   bool     `json:"enabled"`
+	}
+	status := alertsRequest(t, ctx, "POST", "/api/v1/alerts/rules",
+		`{"name":"orders-lag","type":"lag","job_id":"job-a","threshold":1000,"for":"5m","severity":"critical"}`, &rule)
+	require.Equal(t, 201, status)
+	assert.Equal(t, "orders-lag", rule.Name)
+	assert.Equal(t, "lag", rule.Type)
+	assert.Equal(t, "5m", rule.For)
+	assert.Equal(t, []string{}, rule.Channels)
+	assert.True(t, rule.Enabled)
+
+	t.Run("Validation", func(t *testing.T) {
+		assert.Equal(t, 409, alertsRequest(t, ctx, "POST", "/api/v1/alerts/rules", `{"name":"orders-lag","type":"lag"}`, nil))
+		assert.Equal(t, 400, alertsRequest(t, ctx, "POST", "/api/v1/alerts/rules", `{"name":"x","type":"cpu"}`, nil))
+		assert.Equal(t, 400, alertsRequest(t, ctx, "POST", "/api/v1/alerts/rules", `{"name":"x","type":"lag","for":"soon"}`, nil))
+		assert.Equal(t, 400, alertsRequest(t, ctx, "POST", "/api/v1/alerts/rules", `{"name":"x","type":"lag","severity":"panic"}`, nil))
+		assert.Equal(t, 400, alertsRequest(t, ctx, "POST", "/api/v1/alerts/rules", `{"name":"x","type":"lag","job_id":"missing"}`, nil))
+		assert.Equal(t, 400, alertsRequest(t, ctx, "POST", "/api/v1/alerts/rules", `{"name":"x","type":"lag","channels":["nowhere"]}`, nil))
+	})
+
+	t.Run("UpdateKeepsUnsetFields", func(t *testing.T) {
+		status := alertsRequest(t, ctx, "PUT", fmt.Sprintf("/api/v1/alerts/rules/%d", rule.ID), `{"threshold":0,"enabled":false}`, &rule)
+		require.Equal(t, 200, status)
+		assert.Equal(t, float64(0), rule.Threshold)
+		assert.False(t, rule.Enabled)
+		assert.Equal(t, "critical", rule.Severity)
+		assert.Equal(t, "job-a", rule.JobID)
+	})
+
+	t.Run("ListAndDelete", func(t *testing.T) {
+		var rules []map[string]interface{}
+		require.Equal(t, 200, alertsRequest(t, ctx, "GET", "/api/v1/alerts/rules", "", &rules))
+		assert.Len(t, rules, 1)
+
+		assert.Equal(t, 204, alertsRequest(t, ctx, "DELETE", fmt.Sprintf("/api/v1/alerts/rules/%d", rule.ID), "", nil))
+		assert.Equal(t, 404, alertsRequest(t, ctx, "DELETE", fmt.Sprintf("/api/v1/alerts/rules/%d", rule.ID), "", nil))
+	})
+}
+
+func TestAlertsAcknowledgeAndSilenceAPI(t *testing.T) {
+	ctx := setupTestServer(t)
+	require.NoError(t, database.CreateJob(ctx.Server.Db, &database.ReplicationJob{
+		ID: "job-a", Name: "job-a", SourceClusterName: "src", TargetClusterName: "tgt", Status: "active",
+	}))
+	rule := database.AlertRule{Name: "failed", RuleType: database.AlertRuleJobFailed, Severity: "critical", Enabled: true}
+	require.NoError(t, database.CreateAlertRule(ctx.Server.Db, &rule))
+	alert := database.Alert{
+		RuleID: rule.ID, RuleName: rule.Name, RuleType: rule.RuleType, JobID: "job-a",
+		Severity: "critical", Value: 1, Summary: "Job job-a failed", FiredAt: time.Now(),
+	}
+	require.NoError(t, database.CreateAlert(ctx.Server.Db, &alert))
+
+	var alerts []database.Alert
+	require.Equal(t, 200, alertsRequest(t, ctx, "GET", "/api/v1/alerts?status=firing", "", &alerts))
+	require.Len(t, alerts, 1)
+	assert.Equal(t, alert.ID, alerts[0].ID)
+	require.Equal(t, 200, alertsRequest(t, ctx, "GET", "/api/v1/alerts?status=resolved", "", &alerts))
+	assert.Empty(t, alerts)
+	assert.Equal(t, 400, alertsRequest(t, ctx, "GET", "/api/v1/alerts?status=bogus", "", nil))
+
+	var acked database.Alert
+	require.Equal(t, 200, alertsRequest(t, ctx, "POST", fmt.Sprintf("/api/v1/alerts/%d/ack", alert.ID), "", &acked))
+	require.NotNil(t, acked.AcknowledgedBy)
+	assert.Equal(t, "testuser", *acked.AcknowledgedBy)
+	assert.Equal(t, 409, alertsRequest(t, ctx, "POST", fmt.Sprintf("/api/v1/alerts/%d/ack", alert.ID), "", nil))
+	assert.Equal(t, 404, alertsRequest(t, ctx, "POST", "/api/v1/alerts/999/ack", "", nil))
+
+	var silence database.AlertSilence
+	status := alertsRequest(t, ctx, "POST", "/api/v1/alerts/silences",
+		fmt.Sprintf(`{"job_id":"job-a","rule_id":%d,"duration":"2h","reason":"maintenance"}`, rule.ID), &silence)
+	require.Equal(t, 201, status)
+	assert.Equal(t, "testuser", silence.CreatedBy)
+	assert.WithinDuration(t, time.Now().Add(2*time.Hour), silence.EndsAt, time.Minute)
+
+	silenced, err := database.IsAlertSilenced(ctx.Server.Db, rule.ID, "job-a", time.Now())
+	require.NoError(t, err)
+	assert.True(t, silenced)
+	silenced, err = database.IsAlertSilenced(ctx.Server.Db, rule.ID, "job-b", time.Now())
+	require.NoError(t, err)
+	assert.False(t, silenced)
+
+	assert.Equal(t, 400, alertsRequest(t, ctx, "POST", "/api/v1/alerts/silences", `{"duration":"2h"}`, nil))
+	assert.Equal(t, 400, alertsRequest(t, ctx, "POST", "/api/v1/alerts/silences", `{"job_id":"job-a","duration":"-1h"}`, nil))
+
+	var silences []database.AlertSilence
+	require.Equal(t, 200, alertsRequest(t, ctx, "GET", "/api/v1/alerts/silences", "", &silences))
+	assert.Len(t, silences, 1)
+	assert.Equal(t, 204, alertsRequest(t, ctx, "DELETE", fmt.Sprintf("/api/v1/alerts/silences/%d", silence.ID), "", nil))
+	require.Equal(t, 200, alertsRequest(t, ctx, "GET", "/api/v1/alerts/silences", "", &silences))
+	assert.Empty(t, silences)
+
+	// Alerting is disabled in the test configuration.
+	var channels []map[string]string
+	require.Equal(t, 200, alertsRequest(t, ctx, "GET", "/api/v1/alerts/channels", "", &channels))
+	assert.Empty(t, channels)
+	assert.Equal(t, 503, alertsRequest(t, ctx, "POST", "/api/v1/alerts/channels/hook/test", "", nil))
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/internal/manager"
+	"kaf-mirror/tests/mocks"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillFromGapsAPI(t *testing.T) {
+	ctx := setupTestServer(t)
+	db := ctx.Server.Db
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "src", Brokers: "localhost:9092", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "tgt", Brokers: "localhost:9093", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateJob(db, &database.ReplicationJob{
+		ID: "job-a", Name: "job-a", SourceClusterName: "src", TargetClusterName: "tgt", Status: "paused",
+	}))
+	require.NoError(t, database.UpdateMappingsForJob(db, "job-a", []database.TopicMapping{
+		{SourceTopicPattern: "orders", TargetTopicPattern: "orders-dr", Enabled: true},
+	}))
+	require.NoError(t, database.DetectMirrorGaps(db, "job-a", []database.MirrorGap{{
+		JobID: "job-a", SourceTopic: "orders", TargetTopic: "orders-dr", PartitionID: 0, GapStartOffset: 70, GapEndOffset: 100,
+		GapSize: 30, DetectedAt: time.Now(), GapType: "offset_mismatch", ResolutionStatus: "unresolved",
+	}}))
+
+	source := &mocks.MockAdminClient{}
+	source.On("GetTopicHighWaterMarks", mock.Anything, []string{"orders"}).Return(map[string][]kafka.OffsetInfo{
+		"orders": {{Topic: "orders", Partition: 0, Offset: 100, HighWaterMark: 100, LogStartOffset: 80}},
+	}, nil)
+	source.On("Close").Return()
+	target := &mocks.MockAdminClient{}
+	target.On("Close").Return()
+	restore := kafka.SetAdminClientFactoryForTest(func(cfg config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		if cfg.Brokers == "localhost:9092" {
+			return source, nil
+		}
+		return target, nil
+	})
+	t.Cleanup(restore)
+
+	var created manager.BackfillStatus
+	status := alertsRequest(t, ctx, "POST", "/api/v1/jobs/job-a/mirror/gaps/backfill", `{"name":"job-a-gaps"}`, &created)
+	require.Equal(t, http.StatusCreated, status)
+	assert.Equal(t, "job-a-gaps", created.Name)
+	require.Len(t, created.Ranges, 1)
+	assert.Equal(t, int64(80), created.Ranges[0].StartOffset, "expired offsets are skipped")
+	assert.Equal(t, int64(20), created.TotalOffsets)
+
+	var progress manager.BackfillStatus
+	status = alertsRequest(t, ctx, "GET", "/api/v1/jobs/"+created.JobID+"/backfill", "", &progress)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, manager.BackfillPaused, progress.Status)
+	assert.Equal(t, 0.0, progress.ProgressPercent)
+
+	status = alertsRequest(t, ctx, "GET", "/api/v1/jobs/job-a/backfill", "", nil)
+	assert.Equal(t, http.StatusBadRequest, status, "job-a is not a backfill job")
+	status = alertsRequest(t, ctx, "POST", "/api/v1/jobs/job-a/mirror/gaps/backfill", "", nil)
+	assert.Equal(t, http.StatusBadRequest, status, "the gap is already being backfilled")
+	status = alertsRequest(t, ctx, "POST", "/api/v1/jobs/job-a/backfills", `{"from":{"mode":"timestamp","timestamp":"soon"}}`, nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status = alertsRequest(t, ctx, "POST", "/api/v1/jobs/missing/backfills", `{"from":{"mode":"earliest"}}`, nil)
+	assert.Equal(t, http.StatusNotFound, status)
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"kaf-mirror/internal/database"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportReport(t *testing.T, ctx *TestContext, id int, format string) (int, string, []byte) {
+	req := httptest.NewRequest("GET", fmt.Sprintf("/api/v1/compliance/report/%d/export?format=%s", id, format), nil)
+	addAuthHeader(req, ctx.Token)
+	resp, err := ctx.Server.App.Test(req, -1)
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, resp.Header.Get("Content-Type"), body
+}
+
+func TestComplianceReportExport(t *testing.T) {
+	ctx := setupTestServer(t)
+
+	require.NoError(t, database.RecordSecurityEvent(ctx.Server.Db, database.EventPermissionDenied, "testuser",
+		map[string]interface{}{"permission": "users:delete"}))
+	report, err := database.GenerateComplianceReport(ctx.Server.Db, "daily", 1)
+	require.NoError(t, err)
+
+	t.Run("HTML", func(t *testing.T) {
+		status, contentType, body := exportReport(t, ctx, report.ID, "html")
+		require.Equal(t, 200, status)
+		assert.Contains(t, contentType, "text/html")
+		for _, section := range []string{"Access Audit", "Security Events", "Configuration Changes", "Data Processing", "AI Usage"} {
+			assert.Contains(t, string(body), "<h2>"+section+"</h2>")
+		}
+		assert.Contains(t, string(body), "users:delete")
+	})
+
+	t.Run("PDF", func(t *testing.T) {
+		status, contentType, body := exportReport(t, ctx, report.ID, "pdf")
+		require.Equal(t, 200, status)
+		assert.Equal(t, "application/pdf", contentType)
+		assert.True(t, bytes.HasPrefix(body, []byte("%PDF-")))
+	})
+
+	t.Run("Bundle", func(t *testing.T) {
+		status, contentType, body := exportReport(t, ctx, report.ID, "bundle")
+		require.Equal(t, 200, status)
+		assert.Equal(t, "application/zip", contentType)
+
+		archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+		require.NoError(t, err)
+		contents := make(map[string][]byte)
+		for _, f := range archive.File {
+			rc, err := f.Open()
+			require.NoError(t, err)
+			data, err := io.ReadAll(rc)
+			rc.Close()
+			require.NoError(t, err)
+			contents[f.Name] = data
+		}
+
+		for _, name := range []string{"report.json", "report.html", "report.pdf", "report.csv", "events.jsonl", "audit-verification.json", "manifest.json"} {
+			assert.Contains(t, contents, name)
+		}
+		assert.NotContains(t, contents, "manifest.sig", "no HMAC key configured")
+		assert.Contains(t, string(contents["events.jsonl"]), database.EventPermissionDenied)
+
+		var manifest database.EvidenceManifest
+		require.NoError(t, json.Unmarshal(contents["manifest.json"], &manifest))
+		assert.Equal(t, report.ID, manifest.ReportID)
+		require.Len(t, manifest.Files, len(contents)-1)
+		for _, f := range manifest.Files {
+			sum := sha256.Sum256(contents[f.Path])
+			assert.Equal(t, hex.EncodeToString(sum[:]), f.SHA256, f.Path)
+		}
+	})
+
+	t.Run("InvalidFormat", func(t *testing.T) {
+		status, _, _ := exportReport(t, ctx, report.ID, "docx")
+		assert.Equal(t, 400, status)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		status, _, _ := exportReport(t, ctx, 9999, "pdf")
+		assert.Equal(t, 404, status)
+	})
+}
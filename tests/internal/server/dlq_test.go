@@ -0,0 +1,81 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/tests/mocks"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobDeadLetterAPI(t *testing.T) {
+	ctx := setupTestServer(t)
+	db := ctx.Server.Db
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "src", Brokers: "localhost:9092", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "tgt", Brokers: "localhost:9093", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateJob(db, &database.ReplicationJob{
+		ID: "job-a", Name: "job-a", SourceClusterName: "src", TargetClusterName: "tgt", Status: "paused",
+		ErrorPolicy: &database.ErrorPolicy{Action: config.ErrorActionDeadLetter, DeadLetter: config.DeadLetterConfig{Topic: "orders-dlq"}},
+	}))
+	job, err := database.GetJob(db, "job-a")
+	require.NoError(t, err)
+	require.NotNil(t, job.ErrorPolicy)
+	assert.Equal(t, "orders-dlq", job.ErrorPolicy.DeadLetter.Topic)
+
+	target := &mocks.MockAdminClient{}
+	target.On("GetTopicHighWaterMarks", mock.Anything, []string{"orders-dlq"}).Return(map[string][]kafka.OffsetInfo{}, nil)
+	target.On("GetConsumerGroupOffsets", mock.Anything, "kaf-mirror-dlq-replay-job-a", []string{"orders-dlq"}).Return(map[string][]kafka.OffsetInfo{}, nil)
+	target.On("Close").Return()
+	restore := kafka.SetAdminClientFactoryForTest(func(cfg config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		return target, nil
+	})
+	t.Cleanup(restore)
+
+	var list kafka.DeadLetterList
+	status := alertsRequest(t, ctx, "GET", "/api/v1/jobs/job-a/dlq?limit=10", "", &list)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "target", list.Cluster)
+	assert.Equal(t, "orders-dlq", list.Topic)
+	assert.Empty(t, list.Records)
+
+	var replay kafka.DeadLetterReplay
+	status = alertsRequest(t, ctx, "POST", "/api/v1/jobs/job-a/dlq/replay", `{"limit":50}`, &replay)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "orders-dlq", replay.Topic)
+	assert.Zero(t, replay.Replayed)
+
+	status = alertsRequest(t, ctx, "GET", "/api/v1/jobs/job-a/dlq?limit=-1", "", nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status = alertsRequest(t, ctx, "GET", "/api/v1/jobs/missing/dlq", "", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+	status = alertsRequest(t, ctx, "POST", "/api/v1/jobs/missing/dlq/replay", `{}`, nil)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestCreateJobRejectsInvalidErrorPolicy(t *testing.T) {
+	ctx := setupTestServer(t)
+
+	body := `{"name":"job-b","source_cluster_name":"src","target_cluster_name":"tgt","error_policy":{"action":"ignore"}}`
+	status := alertsRequest(t, ctx, "POST", "/api/v1/jobs", body, nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	body = `{"name":"job-b","source_cluster_name":"src","target_cluster_name":"tgt","error_policy":{"classes":{"timeout":"retry"},"retry_backoff":"-1s"}}`
+	status = alertsRequest(t, ctx, "POST", "/api/v1/jobs", body, nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
@@ -0,0 +1,74 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"fmt"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/tests/mocks"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFailoverAPI_RunsAfterRequestReturns(t *testing.T) {
+	ctx := setupTestServer(t)
+	db := ctx.Server.Db
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "src", Brokers: "localhost:9092", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "tgt", Brokers: "localhost:9093", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateJob(db, &database.ReplicationJob{
+		ID: "job-f", Name: "job-f", SourceClusterName: "src", TargetClusterName: "tgt", Status: "paused",
+	}))
+	require.NoError(t, database.UpdateMappingsForJob(db, "job-f", []database.TopicMapping{
+		{SourceTopicPattern: "orders", TargetTopicPattern: "orders-dr", Enabled: true},
+	}))
+
+	// The drain step blocks until the request has returned and Fiber has served another one.
+	release := make(chan time.Time)
+	admin := &mocks.MockAdminClient{}
+	admin.On("GetTopicHighWaterMarks", mock.Anything, mock.Anything).WaitUntil(release).Return(map[string][]kafka.OffsetInfo{}, nil)
+	admin.On("GetConsumerGroupOffsets", mock.Anything, mock.Anything, mock.Anything).Return(map[string][]kafka.OffsetInfo{}, nil)
+	admin.On("ListConsumerGroups", mock.Anything).Return([]string{}, nil)
+	admin.On("Close").Return()
+	t.Cleanup(kafka.SetAdminClientFactoryForTest(func(cfg config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		return admin, nil
+	}))
+
+	var started database.FailoverOperation
+	status := alertsRequest(t, ctx, "POST", "/api/v1/jobs/job-f/failover", "", &started)
+	require.Equal(t, http.StatusAccepted, status)
+	alertsRequest(t, ctx, "GET", "/api/v1/jobs/job-x/failovers", "", nil)
+	close(release)
+
+	var op database.FailoverOperation
+	require.Eventually(t, func() bool {
+		status := alertsRequest(t, ctx, "GET", fmt.Sprintf("/api/v1/jobs/job-f/failovers/%d", started.ID), "", &op)
+		return status == http.StatusOK && op.Status != database.FailoverRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "job-f", op.JobID)
+	assert.Equal(t, database.FailoverCompleted, op.Status, "last error: %v", op.LastError)
+
+	events, err := database.ListOperationalEvents(db)
+	require.NoError(t, err)
+	for _, event := range events {
+		if event.EventType == "failover" {
+			assert.True(t, strings.HasPrefix(event.Details, "Job job-f: "), event.Details)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/joblogs"
+	"kaf-mirror/pkg/logger"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetJobLogs_FiltersByLevel(t *testing.T) {
+	ctx := setupTestServer(t)
+	require.NoError(t, database.CreateJob(ctx.Server.Db, &database.ReplicationJob{
+		ID: "job-logs", Name: "job-logs", SourceClusterName: "src", TargetClusterName: "tgt", Status: "paused",
+	}))
+
+	log := logger.With(logger.JobID("job-logs"), logger.Component("test"))
+	log.Info("started")
+	log.Warn("lag rising")
+	log.Error("produce failed")
+	logger.With(logger.JobID("other-job")).Error("not mine")
+
+	var resp struct {
+		JobID string          `json:"job_id"`
+		Logs  []joblogs.Entry `json:"logs"`
+	}
+	status := alertsRequest(t, ctx, "GET", "/api/v1/jobs/job-logs/logs?level=warn", "", &resp)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "job-logs", resp.JobID)
+	require.Len(t, resp.Logs, 2)
+	assert.Equal(t, "lag rising", resp.Logs[0].Message)
+	assert.Equal(t, "WARN", resp.Logs[0].Level)
+	assert.Equal(t, "test", resp.Logs[0].Component)
+	assert.Equal(t, "produce failed", resp.Logs[1].Message)
+
+	status = alertsRequest(t, ctx, "GET", "/api/v1/jobs/job-logs/logs?limit=1", "", &resp)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, resp.Logs, 1)
+	assert.Equal(t, "produce failed", resp.Logs[0].Message)
+
+	assert.Equal(t, http.StatusBadRequest, alertsRequest(t, ctx, "GET", "/api/v1/jobs/job-logs/logs?level=loud", "", nil))
+	assert.Equal(t, http.StatusBadRequest, alertsRequest(t, ctx, "GET", "/api/v1/jobs/job-logs/logs?since=yesterday", "", nil))
+	assert.Equal(t, http.StatusNotFound, alertsRequest(t, ctx, "GET", "/api/v1/jobs/missing/logs", "", nil))
+}
+
+func TestGetJobLogs_FollowStreamsNewRecords(t *testing.T) {
+	ctx := setupTestServer(t)
+	require.NoError(t, database.CreateJob(ctx.Server.Db, &database.ReplicationJob{
+		ID: "job-follow", Name: "job-follow", SourceClusterName: "src", TargetClusterName: "tgt", Status: "paused",
+	}))
+	log := logger.With(logger.JobID("job-follow"))
+	log.Warn("backlog")
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go ctx.Server.App.Listener(ln)
+
+	req, err := http.NewRequest("GET", fmt.Sprintf("http://%s/api/v1/jobs/job-follow/logs?follow=true&level=warn", ln.Addr()), nil)
+	require.NoError(t, err)
+	addAuthHeader(req, ctx.Token)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	lines := make(chan joblogs.Entry, 10)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			var entry joblogs.Entry
+			if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+				lines <- entry
+			}
+		}
+	}()
+	next := func() joblogs.Entry {
+		select {
+		case entry, ok := <-lines:
+			require.True(t, ok, "stream ended early")
+			return entry
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a streamed record")
+		}
+		return joblogs.Entry{}
+	}
+
+	assert.Equal(t, "backlog", next().Message)
+	log.Info("filtered")
+	log.Error("live")
+	assert.Equal(t, "live", next().Message)
+
+	require.NoError(t, ctx.Server.Shutdown())
+	select {
+	case _, ok := <-lines:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream was not closed on shutdown")
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"kaf-mirror/internal/database"
+	"kaf-mirror/tests/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type lagResponse struct {
+	JobID         string                  `json:"job_id"`
+	Running       bool                    `json:"running"`
+	MessageLag    int                     `json:"message_lag"`
+	LagSecondsP50 float64                 `json:"lag_seconds_p50"`
+	LagSecondsP99 float64                 `json:"lag_seconds_p99"`
+	LagSecondsMax float64                 `json:"lag_seconds_max"`
+	Topics        []database.TopicLag     `json:"topics"`
+	Partitions    []database.PartitionLag `json:"partitions"`
+}
+
+func TestGetJobLag_RunningJobReportsPartitions(t *testing.T) {
+	ctx := setupTestServer(t)
+	require.NoError(t, database.CreateJob(ctx.Server.Db, &database.ReplicationJob{
+		ID: "job-lag", Name: "job-lag", SourceClusterName: "src", TargetClusterName: "tgt", Status: "active",
+	}))
+
+	ctx.Manager.Mu.Lock()
+	ctx.Manager.KafMirrors["job-lag"] = &mocks.MockKafMirror{}
+	ctx.Manager.Mu.Unlock()
+
+	ctx.Manager.ProcessMetrics(database.ReplicationMetric{
+		JobID:         "job-lag",
+		CurrentLag:    42,
+		LagSecondsP50: 0.4,
+		LagSecondsP99: 2.5,
+		LagSecondsMax: 31,
+		TopicLags: []database.TopicLag{
+			{Topic: "orders", Partitions: 2, MessageLag: 42, LagSecondsP50: 0.4, LagSecondsP99: 2.5, LagSecondsMax: 31},
+		},
+		PartitionLags: []database.PartitionLag{
+			{Topic: "orders", Partition: 0, MessageLag: 0, LagSecondsP50: 0.3, LagSecondsP99: 0.9, Samples: 120},
+			{Topic: "orders", Partition: 1, MessageLag: 42, LagSeconds: 31, LagSecondsP50: 31, LagSecondsP99: 31},
+		},
+		Timestamp: time.Now(),
+	})
+
+	var lag lagResponse
+	require.Equal(t, 200, alertsRequest(t, ctx, "GET", "/api/v1/jobs/job-lag/lag", "", &lag))
+	assert.True(t, lag.Running)
+	assert.Equal(t, 42, lag.MessageLag)
+	assert.Equal(t, 2.5, lag.LagSecondsP99)
+	assert.Equal(t, 31.0, lag.LagSecondsMax)
+	require.Len(t, lag.Topics, 1)
+	require.Len(t, lag.Partitions, 2)
+	assert.Equal(t, 120, lag.Partitions[0].Samples)
+	assert.Equal(t, 31.0, lag.Partitions[1].LagSeconds)
+}
+
+func TestGetJobLag_StoppedJobFallsBackToPersistedMetrics(t *testing.T) {
+	ctx := setupTestServer(t)
+	require.NoError(t, database.CreateJob(ctx.Server.Db, &database.ReplicationJob{
+		ID: "job-idle", Name: "job-idle", SourceClusterName: "src", TargetClusterName: "tgt", Status: "paused",
+	}))
+	require.NoError(t, database.InsertMetrics(ctx.Server.Db, &database.ReplicationMetric{
+		JobID: "job-idle", CurrentLag: 7, LagSecondsP50: 1.5, LagSecondsP99: 12, LagSecondsMax: 20,
+	}))
+
+	var lag lagResponse
+	require.Equal(t, 200, alertsRequest(t, ctx, "GET", "/api/v1/jobs/job-idle/lag", "", &lag))
+	assert.False(t, lag.Running)
+	assert.Equal(t, 7, lag.MessageLag)
+	assert.Equal(t, 1.5, lag.LagSecondsP50)
+	assert.Equal(t, 12.0, lag.LagSecondsP99)
+	assert.Equal(t, 20.0, lag.LagSecondsMax)
+	assert.Empty(t, lag.Partitions)
+
+	assert.Equal(t, 404, alertsRequest(t, ctx, "GET", "/api/v1/jobs/missing/lag", "", nil))
+}
+
+func TestGetPartitionMetrics(t *testing.T) {
+	ctx := setupTestServer(t)
+	require.NoError(t, database.CreateJob(ctx.Server.Db, &database.ReplicationJob{
+		ID: "job-parts", Name: "job-parts", SourceClusterName: "src", TargetClusterName: "tgt", Status: "active",
+	}))
+
+	now := time.Now()
+	ctx.Manager.ProcessMetrics(database.ReplicationMetric{
+		JobID:     "job-parts",
+		Timestamp: now,
+		PartitionMetrics: []database.PartitionMetric{
+			{JobID: "job-parts", Topic: "orders", Partition: 0, Timestamp: now, MessagesReplicated: 500, MessagesConsumed: 500},
+			{JobID: "job-parts", Topic: "orders", Partition: 1, Timestamp: now, MessageLag: 1200, LagSeconds: 90},
+			{JobID: "job-parts", Topic: "payments", Partition: 0, Timestamp: now, MessagesReplicated: 20, ErrorCount: 3},
+		},
+	})
+
+	var resp struct {
+		JobID      string                      `json:"job_id"`
+		Partitions []database.PartitionSummary `json:"partitions"`
+		Series     []database.PartitionMetric  `json:"series"`
+	}
+	require.Equal(t, 200, alertsRequest(t, ctx, "GET", "/api/v1/jobs/job-parts/metrics/partitions", "", &resp))
+	assert.Equal(t, "job-parts", resp.JobID)
+	require.Len(t, resp.Partitions, 3)
+	assert.Len(t, resp.Series, 3)
+	assert.Equal(t, int64(1200), resp.Partitions[1].MessageLag)
+	assert.Equal(t, 90.0, resp.Partitions[1].LagSeconds)
+	assert.Equal(t, int64(3), resp.Partitions[2].ErrorCount)
+
+	resp.Series = nil
+	require.Equal(t, 200, alertsRequest(t, ctx, "GET", "/api/v1/jobs/job-parts/metrics/partitions?topic=orders&series=false", "", &resp))
+	assert.Len(t, resp.Partitions, 2)
+	assert.Empty(t, resp.Series)
+
+	assert.Equal(t, 404, alertsRequest(t, ctx, "GET", "/api/v1/jobs/missing/metrics/partitions", "", nil))
+}
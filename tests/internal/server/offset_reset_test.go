@@ -0,0 +1,89 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/internal/manager"
+	"kaf-mirror/tests/mocks"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobResetOffsetsAPI(t *testing.T) {
+	ctx := setupTestServer(t)
+	db := ctx.Server.Db
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "src", Brokers: "localhost:9092", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "tgt", Brokers: "localhost:9093", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateJob(db, &database.ReplicationJob{
+		ID: "job-a", Name: "job-a", SourceClusterName: "src", TargetClusterName: "tgt", Status: "paused",
+	}))
+	require.NoError(t, database.UpdateMappingsForJob(db, "job-a", []database.TopicMapping{
+		{SourceTopicPattern: "orders", TargetTopicPattern: "orders-dr", Enabled: true},
+	}))
+
+	source := &mocks.MockAdminClient{}
+	source.On("GetTopicHighWaterMarks", mock.Anything, []string{"orders"}).Return(map[string][]kafka.OffsetInfo{
+		"orders": {{Topic: "orders", Partition: 0, Offset: 100, HighWaterMark: 100, LogStartOffset: 20}},
+	}, nil)
+	source.On("GetConsumerGroupOffsets", mock.Anything, "kaf-mirror-job-job-a", []string{"orders"}).Return(map[string][]kafka.OffsetInfo{}, nil)
+	source.On("Close").Return()
+	target := &mocks.MockAdminClient{}
+	target.On("Close").Return()
+	restore := kafka.SetAdminClientFactoryForTest(func(cfg config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		if cfg.Brokers == "localhost:9092" {
+			return source, nil
+		}
+		return target, nil
+	})
+	t.Cleanup(restore)
+
+	var reset manager.OffsetReset
+	status := alertsRequest(t, ctx, "POST", "/api/v1/jobs/job-a/reset-offsets?dry_run=true", `{"position":{"mode":"earliest"}}`, &reset)
+	require.Equal(t, http.StatusOK, status)
+	assert.True(t, reset.DryRun)
+	require.Len(t, reset.Partitions, 1)
+	assert.Equal(t, int64(20), reset.Partitions[0].Offset)
+	assert.Equal(t, int64(-1), reset.Partitions[0].CommittedOffset)
+	source.AssertNotCalled(t, "CommitConsumerGroupOffsets", mock.Anything, mock.Anything, mock.Anything)
+
+	source.On("CommitConsumerGroupOffsets", mock.Anything, "kaf-mirror-job-job-a", map[string]map[int32]int64{"orders": {0: 60}}).Return(nil).Once()
+	status = alertsRequest(t, ctx, "POST", "/api/v1/jobs/job-a/reset-offsets", `{"position":{"mode":"offsets","offsets":{"orders":{"0":60}}}}`, &reset)
+	require.Equal(t, http.StatusOK, status)
+	assert.False(t, reset.DryRun)
+	source.AssertExpectations(t)
+
+	status = alertsRequest(t, ctx, "POST", "/api/v1/jobs/job-a/reset-offsets", `{"position":{"mode":"offsets","offsets":{"orders":{"0":5}}}}`, nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status = alertsRequest(t, ctx, "POST", "/api/v1/jobs/job-a/reset-offsets", `{"position":{"mode":"yesterday"}}`, nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status = alertsRequest(t, ctx, "POST", "/api/v1/jobs/missing/reset-offsets", `{"position":{"mode":"earliest"}}`, nil)
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestCreateJobRejectsInvalidStartPosition(t *testing.T) {
+	ctx := setupTestServer(t)
+
+	body := `{"name":"job-b","source_cluster_name":"src","target_cluster_name":"tgt","start_position":{"mode":"timestamp","timestamp":"soon"}}`
+	status := alertsRequest(t, ctx, "POST", "/api/v1/jobs", body, nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	body = `{"name":"job-b","source_cluster_name":"src","target_cluster_name":"tgt","topic_mappings":[{"source_topic_pattern":"orders","target_topic_pattern":"orders","enabled":true,"start_position":{"mode":"offsets"}}]}`
+	status = alertsRequest(t, ctx, "POST", "/api/v1/jobs", body, nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
@@ -0,0 +1,119 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"kaf-mirror/internal/database"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func login(t *testing.T, ctx *TestContext, username, password string) int {
+	body, _ := json.Marshal(map[string]string{"username": username, "password": password})
+	req := httptest.NewRequest("POST", "/auth/token", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := ctx.Server.App.Test(req)
+	require.NoError(t, err)
+	return resp.StatusCode
+}
+
+func eventsOfType(t *testing.T, ctx *TestContext, eventType string) []database.OperationalEvent {
+	var events []database.OperationalEvent
+	err := ctx.Server.Db.Select(&events, "SELECT * FROM operational_events WHERE event_type = ? ORDER BY id", eventType)
+	require.NoError(t, err)
+	return events
+}
+
+func TestSecurityAuditEvents(t *testing.T) {
+	ctx := setupTestServer(t)
+
+	t.Run("LoginFailuresLockAccount", func(t *testing.T) {
+		user, err := database.CreateUser(ctx.Server.Db, "alice", "correct-password", false)
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, 401, login(t, ctx, "alice", "wrong"))
+		}
+		// Locked: even the correct password is rejected
+		assert.Equal(t, 401, login(t, ctx, "alice", "correct-password"))
+
+		assert.Len(t, eventsOfType(t, ctx, database.EventLoginFailed), 4)
+		locked := eventsOfType(t, ctx, database.EventAccountLocked)
+		require.Len(t, locked, 1)
+		assert.Contains(t, locked[0].Details, `"failed_attempts":3`)
+
+		require.NoError(t, database.ClearFailedLogins(ctx.Server.Db, user.ID))
+		assert.Equal(t, 200, login(t, ctx, "alice", "correct-password"))
+		assert.Len(t, eventsOfType(t, ctx, database.EventLoginSucceeded), 1)
+	})
+
+	t.Run("UnknownUserIsRecorded", func(t *testing.T) {
+		assert.Equal(t, 401, login(t, ctx, "nobody", "x"))
+		events := eventsOfType(t, ctx, database.EventLoginFailed)
+		assert.Contains(t, events[len(events)-1].Details, `"reason":"unknown user"`)
+	})
+
+	t.Run("PermissionDenialRecordsPermission", func(t *testing.T) {
+		user, err := database.CreateUser(ctx.Server.Db, "monitor", "password", false)
+		require.NoError(t, err)
+		require.NoError(t, database.SetUserRole(ctx.Server.Db, user.ID, "monitoring"))
+		token, _, err := database.CreateApiToken(ctx.Server.Db, user.ID, "test", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		assert.Equal(t, 403, doRequest(t, ctx, "DELETE", "/api/v1/users/1", token))
+
+		denied := eventsOfType(t, ctx, database.EventPermissionDenied)
+		require.Len(t, denied, 1)
+		assert.Equal(t, "monitor", denied[0].Initiator)
+		assert.Contains(t, denied[0].Details, `"permission":"users:delete"`)
+	})
+
+	t.Run("InvalidTokenIsRecorded", func(t *testing.T) {
+		assert.Equal(t, 401, doRequest(t, ctx, "GET", "/api/v1/jobs", "not-a-token"))
+		assert.Len(t, eventsOfType(t, ctx, database.EventAuthFailed), 1)
+	})
+
+	t.Run("SensitiveReadIsRecorded", func(t *testing.T) {
+		doRequest(t, ctx, "GET", "/api/v1/config", ctx.Token)
+		doRequest(t, ctx, "GET", "/api/v1/jobs", ctx.Token)
+
+		reads := eventsOfType(t, ctx, database.EventSensitiveRead)
+		require.Len(t, reads, 1)
+		assert.Equal(t, "testuser", reads[0].Initiator)
+	})
+
+	t.Run("TokenLifecycleIsRecorded", func(t *testing.T) {
+		status, _, id := createToken(t, ctx, ctx.Token, `{"name":"audited"}`)
+		require.Equal(t, 201, status)
+		assert.Equal(t, 204, doRequest(t, ctx, "DELETE", fmt.Sprintf("/api/v1/tokens/%d", id), ctx.Token))
+
+		assert.Len(t, eventsOfType(t, ctx, database.EventTokenCreated), 1)
+		assert.Len(t, eventsOfType(t, ctx, database.EventTokenRevoked), 1)
+	})
+
+	t.Run("ComplianceReportSummarizesEvents", func(t *testing.T) {
+		report, err := database.GenerateComplianceReport(ctx.Server.Db, "daily", 1)
+		require.NoError(t, err)
+		security := report.ReportData["security_events"].(map[string]interface{})
+		assert.Equal(t, 5, security["login_failures"])
+		assert.Equal(t, 1, security["account_lockouts"])
+		assert.Equal(t, 1, security["permission_denied_events"])
+		assert.Equal(t, map[string]int{"users:delete": 1}, security["denied_permissions"])
+	})
+}
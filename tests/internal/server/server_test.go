@@ -30,8 +30,10 @@ import (
 )
 
 type TestContext struct {
-	Server *server.Server
-	Token  string
+	Server  *server.Server
+	Manager *manager.JobManager
+	Hub     *server.Hub
+	Token   string
 }
 
 func setupTestServer(t *testing.T) *TestContext {
@@ -41,6 +43,12 @@ func setupTestServer(t *testing.T) *TestContext {
 			Port: 8080,
 			Mode: "test",
 		},
+		Auth: config.AuthConfig{
+			Lockout: config.LockoutConfig{MaxFailedAttempts: 3, Window: "15m", Duration: "15m"},
+		},
+		Audit: config.AuditConfig{
+			SensitiveReads: []string{"/api/v1/config"},
+		},
 	}
 	db, err := database.InitDB(":memory:")
 	assert.NoError(t, err)
@@ -67,8 +75,10 @@ func setupTestServer(t *testing.T) *TestContext {
 	srv := server.New(cfg, db, jobManager, hub, "test")
 
 	return &TestContext{
-		Server: srv,
-		Token:  token,
+		Server:  srv,
+		Manager: jobManager,
+		Hub:     hub,
+		Token:   token,
 	}
 }
 
@@ -96,6 +106,58 @@ func TestHealthCheck(t *testing.T) {
 	assert.Contains(t, healthResp, "uptime")
 }
 
+func TestHealthCheck_ReportsMetricSinks(t *testing.T) {
+	loki := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer loki.Close()
+
+	cfg := &config.Config{Server: config.ServerConfig{Port: 8080}}
+	cfg.Monitoring = config.MonitoringConfig{
+		Enabled:  true,
+		Platform: "loki",
+		Loki:     config.LokiConfig{Endpoint: loki.URL},
+		Buffer:   config.SinkBufferConfig{FlushInterval: "10ms", MaxRetries: 1},
+	}
+	db, err := database.InitDB(":memory:")
+	assert.NoError(t, err)
+	hub := server.NewHub()
+	jobManager := manager.New(db, cfg, hub)
+	defer jobManager.Close()
+	srv := server.New(cfg, db, jobManager, hub, "test")
+
+	jobManager.ProcessMetrics(database.ReplicationMetric{JobID: "job-1", Timestamp: time.Now()})
+
+	var healthResp struct {
+		Status       string `json:"status"`
+		MetricsSinks []struct {
+			Name      string `json:"name"`
+			Healthy   bool   `json:"healthy"`
+			Dropped   int    `json:"dropped"`
+			LastError string `json:"last_error"`
+		} `json:"metrics_sinks"`
+	}
+	assert.Eventually(t, func() bool {
+		resp, err := srv.App.Test(httptest.NewRequest("GET", "/health", nil))
+		if err != nil || resp.StatusCode != 200 {
+			return false
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if json.Unmarshal(body, &healthResp) != nil || len(healthResp.MetricsSinks) != 1 {
+			return false
+		}
+		return healthResp.MetricsSinks[0].Dropped == 1
+	}, 2*time.Second, 10*time.Millisecond, "the undeliverable metric is dropped without a spill directory")
+
+	assert.Equal(t, "degraded", healthResp.Status)
+	if assert.Len(t, healthResp.MetricsSinks, 1) {
+		sink := healthResp.MetricsSinks[0]
+		assert.Equal(t, "loki", sink.Name)
+		assert.False(t, sink.Healthy)
+		assert.Contains(t, sink.LastError, "503")
+	}
+}
+
 func TestClustersAPI(t *testing.T) {
 	ctx := setupTestServer(t)
 
@@ -0,0 +1,126 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"fmt"
+	"kaf-mirror/internal/database"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJobSLOAPI(t *testing.T) {
+	ctx := setupTestServer(t)
+	db := ctx.Server.Db
+	require.NoError(t, database.CreateJob(db, &database.ReplicationJob{
+		ID: "job-a", Name: "job-a", SourceClusterName: "src", TargetClusterName: "tgt", Status: "active",
+	}))
+
+	var slo database.JobSLO
+	status := alertsRequest(t, ctx, "POST", "/api/v1/jobs/job-a/slo",
+		`{"name":"time-lag","type":"lag_seconds","objective":99,"threshold":60}`, &slo)
+	require.Equal(t, 201, status)
+	assert.Equal(t, "job-a", slo.JobID)
+	assert.True(t, slo.Enabled)
+
+	t.Run("Validation", func(t *testing.T) {
+		assert.Equal(t, 409, alertsRequest(t, ctx, "POST", "/api/v1/jobs/job-a/slo", `{"name":"time-lag","type":"gaps"}`, nil))
+		assert.Equal(t, 400, alertsRequest(t, ctx, "POST", "/api/v1/jobs/job-a/slo", `{"name":"x","type":"uptime","threshold":1}`, nil))
+		assert.Equal(t, 400, alertsRequest(t, ctx, "POST", "/api/v1/jobs/job-a/slo", `{"name":"x","type":"lag_seconds","objective":120,"threshold":1}`, nil))
+		assert.Equal(t, 404, alertsRequest(t, ctx, "POST", "/api/v1/jobs/missing/slo", `{"name":"x","type":"gaps"}`, nil))
+	})
+
+	// 100 minutes in the last day, 2 of them over the 60s threshold.
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		lag := 1.0
+		if i%50 == 10 {
+			lag = 90
+		}
+		_, err := db.Exec(`INSERT INTO aggregated_metrics (job_id, timestamp, messages_replicated_delta, bytes_transferred_delta, avg_lag, error_count_delta, lag_seconds_max)
+			VALUES (?, ?, 100, 100, 0, 0, ?)`, "job-a", now.Add(-time.Duration(i*10+5)*time.Minute), lag)
+		require.NoError(t, err)
+	}
+
+	t.Run("Status", func(t *testing.T) {
+		var statuses []database.SLOStatus
+		require.Equal(t, 200, alertsRequest(t, ctx, "GET", "/api/v1/jobs/job-a/slo", "", &statuses))
+		require.Len(t, statuses, 1)
+		require.Len(t, statuses[0].Windows, 2)
+		week := statuses[0].Windows[0]
+		assert.Equal(t, 100, week.TotalWindows)
+		assert.Equal(t, 2, week.Bad)
+		assert.InDelta(t, 98.0, week.AttainmentPercent, 0.001)
+		assert.False(t, week.Met)
+		assert.Equal(t, 0.0, week.ErrorBudgetRemainingPercent)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		var updated database.JobSLO
+		path := fmt.Sprintf("/api/v1/jobs/job-a/slo/%d", slo.ID)
+		require.Equal(t, 200, alertsRequest(t, ctx, "PUT", path, `{"objective":97.5}`, &updated))
+		assert.Equal(t, 97.5, updated.Objective)
+		assert.Equal(t, 60.0, updated.Threshold, "fields left out keep their value")
+		assert.Equal(t, 404, alertsRequest(t, ctx, "PUT", fmt.Sprintf("/api/v1/jobs/other/slo/%d", slo.ID), `{"objective":90}`, nil))
+	})
+
+	t.Run("Overview", func(t *testing.T) {
+		var gaps database.JobSLO
+		require.Equal(t, 201, alertsRequest(t, ctx, "POST", "/api/v1/jobs/job-a/slo", `{"name":"no-gaps","type":"gaps"}`, &gaps))
+		require.NoError(t, database.DetectMirrorGaps(db, "job-a", []database.MirrorGap{{
+			JobID: "job-a", SourceTopic: "orders", TargetTopic: "orders", PartitionID: 0, DetectedAt: time.Now().UTC(),
+			GapStartOffset: 10, GapEndOffset: 20, GapSize: 10, GapType: "missing_messages", ResolutionStatus: "unresolved",
+		}}))
+
+		var overview struct {
+			Total    int                  `json:"total"`
+			Met      int                  `json:"met"`
+			Breached int                  `json:"breached"`
+			SLOs     []database.SLOStatus `json:"slos"`
+		}
+		require.Equal(t, 200, alertsRequest(t, ctx, "GET", "/api/v1/slo", "", &overview))
+		assert.Equal(t, 2, overview.Total)
+		assert.Equal(t, 1, overview.Met)
+		assert.Equal(t, 1, overview.Breached)
+	})
+
+	t.Run("ComplianceReport", func(t *testing.T) {
+		report, err := database.GenerateComplianceReport(db, "weekly", 1)
+		require.NoError(t, err)
+
+		serviceLevels := report.ReportData["service_levels"].(map[string]interface{})
+		assert.Equal(t, 2, serviceLevels["total_slos"])
+		assert.Equal(t, 1, serviceLevels["slos_breached"])
+
+		summary := report.ReportData["compliance_summary"].(map[string]interface{})
+		assert.Equal(t, "ATTENTION_REQUIRED", summary["compliance_status"])
+		breakdown := summary["health_score_breakdown"].(map[string]interface{})
+		assert.Equal(t, 5.0, breakdown["slo_breach_penalty"])
+
+		status, _, body := exportReport(t, ctx, report.ID, "html")
+		require.Equal(t, 200, status)
+		html := string(body)
+		assert.Contains(t, html, "<h2>Service Level Objectives</h2>")
+		assert.Contains(t, html, "no-gaps")
+		assert.Contains(t, html, "BREACHED")
+		assert.Contains(t, html, "SLO breach penalty")
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		path := fmt.Sprintf("/api/v1/jobs/job-a/slo/%d", slo.ID)
+		assert.Equal(t, 204, alertsRequest(t, ctx, "DELETE", path, "", nil))
+		assert.Equal(t, 404, alertsRequest(t, ctx, "DELETE", path, "", nil))
+	})
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"kaf-mirror/internal/database"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createToken(t *testing.T, ctx *TestContext, authToken, payload string) (int, string, int) {
+	req := httptest.NewRequest("POST", "/api/v1/tokens", bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	addAuthHeader(req, authToken)
+
+	resp, err := ctx.Server.App.Test(req)
+	require.NoError(t, err)
+	if resp.StatusCode != 201 {
+		return resp.StatusCode, "", 0
+	}
+
+	var created struct {
+		Token    string `json:"token"`
+		ApiToken struct {
+			ID int `json:"id"`
+		} `json:"api_token"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	return resp.StatusCode, created.Token, created.ApiToken.ID
+}
+
+func doRequest(t *testing.T, ctx *TestContext, method, path, token string) int {
+	req := httptest.NewRequest(method, path, nil)
+	addAuthHeader(req, token)
+	resp, err := ctx.Server.App.Test(req)
+	require.NoError(t, err)
+	return resp.StatusCode
+}
+
+func TestScopedTokens(t *testing.T) {
+	ctx := setupTestServer(t)
+
+	jobA := &database.ReplicationJob{ID: "job-a", Name: "job-a", SourceClusterName: "src", TargetClusterName: "tgt", Status: "paused"}
+	jobB := &database.ReplicationJob{ID: "job-b", Name: "job-b", SourceClusterName: "src", TargetClusterName: "tgt", Status: "paused"}
+	require.NoError(t, database.CreateJob(ctx.Server.Db, jobA))
+	require.NoError(t, database.CreateJob(ctx.Server.Db, jobB))
+
+	status, ciToken, ciTokenID := createToken(t, ctx, ctx.Token,
+		`{"name":"ci","permissions":["jobs:view","jobs:start"],"job_ids":["job-a"],"expires_in":"720h"}`)
+	require.Equal(t, 201, status)
+	require.NotEmpty(t, ciToken)
+
+	t.Run("IntersectsScopeWithRole", func(t *testing.T) {
+		assert.Equal(t, 200, doRequest(t, ctx, "GET", "/api/v1/jobs/job-a", ciToken))
+		assert.Equal(t, 403, doRequest(t, ctx, "GET", "/api/v1/clusters", ciToken), "clusters:view is outside the token scope")
+		assert.Equal(t, 403, doRequest(t, ctx, "DELETE", "/api/v1/jobs/job-a", ciToken), "jobs:delete is outside the token scope")
+	})
+
+	t.Run("RestrictsJobs", func(t *testing.T) {
+		assert.Equal(t, 403, doRequest(t, ctx, "GET", "/api/v1/jobs/job-b", ciToken))
+		assert.Equal(t, 403, doRequest(t, ctx, "POST", "/api/v1/jobs/start-all", ciToken))
+
+		req := httptest.NewRequest("GET", "/api/v1/jobs", nil)
+		addAuthHeader(req, ciToken)
+		resp, err := ctx.Server.App.Test(req)
+		require.NoError(t, err)
+		var jobs []database.ReplicationJob
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&jobs))
+		require.Len(t, jobs, 1)
+		assert.Equal(t, "job-a", jobs[0].ID)
+	})
+
+	t.Run("DeniesCrossJobEndpoints", func(t *testing.T) {
+		scope := `"permissions":["jobs:view","events:view","metrics:view","alerts:view","ai:insights:view"]`
+		status, scoped, _ := createToken(t, ctx, ctx.Token, `{"name":"scoped-reader",`+scope+`,"job_ids":["job-a"]}`)
+		require.Equal(t, 201, status)
+		status, unscoped, _ := createToken(t, ctx, ctx.Token, `{"name":"reader",`+scope+`}`)
+		require.Equal(t, 201, status)
+
+		for _, path := range []string{"/api/v1/events", "/api/v1/ai/insights", "/api/v1/slo", "/api/v1/alerts"} {
+			assert.Equal(t, 403, doRequest(t, ctx, "GET", path, scoped), path)
+			assert.NotEqual(t, 403, doRequest(t, ctx, "GET", path, unscoped), path)
+		}
+		assert.Equal(t, 200, doRequest(t, ctx, "GET", "/api/v1/jobs/job-a", scoped))
+		assert.Equal(t, 200, doRequest(t, ctx, "GET", "/api/v1/jobs/job-a/slo", scoped))
+		assert.Equal(t, 403, doRequest(t, ctx, "GET", "/api/v1/jobs/job-b/slo", scoped))
+	})
+
+	t.Run("ScopedTokensCannotManageTokens", func(t *testing.T) {
+		status, _, _ := createToken(t, ctx, ciToken, `{"name":"escalate"}`)
+		assert.Equal(t, 403, status)
+	})
+
+	t.Run("RejectsPermissionsOutsideRole", func(t *testing.T) {
+		monitor, err := database.CreateUser(ctx.Server.Db, "monitor", "pw", false)
+		require.NoError(t, err)
+		require.NoError(t, database.SetUserRole(ctx.Server.Db, monitor.ID, "monitoring"))
+		monitorToken, _, err := database.CreateApiToken(ctx.Server.Db, monitor.ID, "session", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		status, _, _ := createToken(t, ctx, monitorToken, `{"name":"too-much","permissions":["jobs:delete"]}`)
+		assert.Equal(t, 400, status)
+	})
+
+	t.Run("EnforcesIPAllowlist", func(t *testing.T) {
+		status, blocked, _ := createToken(t, ctx, ctx.Token, `{"name":"office","allowed_ips":["10.0.0.0/8"]}`)
+		require.Equal(t, 201, status)
+		assert.Equal(t, 403, doRequest(t, ctx, "GET", "/api/v1/jobs", blocked))
+
+		status, allowed, _ := createToken(t, ctx, ctx.Token, `{"name":"local","allowed_ips":["0.0.0.0/0"]}`)
+		require.Equal(t, 201, status)
+		assert.Equal(t, 200, doRequest(t, ctx, "GET", "/api/v1/jobs", allowed))
+
+		status, _, _ = createToken(t, ctx, ctx.Token, `{"name":"bad-ip","allowed_ips":["not-an-ip"]}`)
+		assert.Equal(t, 400, status)
+	})
+
+	t.Run("ListShowsLastUsed", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/tokens", nil)
+		addAuthHeader(req, ctx.Token)
+		resp, err := ctx.Server.App.Test(req)
+		require.NoError(t, err)
+		require.Equal(t, 200, resp.StatusCode)
+
+		var tokens []map[string]interface{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&tokens))
+		var found bool
+		for _, tok := range tokens {
+			if tok["name"] == "ci" {
+				found = true
+				assert.NotNil(t, tok["last_used_at"])
+				assert.Equal(t, []interface{}{"jobs:view", "jobs:start"}, tok["permissions"])
+			}
+			assert.NotContains(t, tok, "token_hash")
+		}
+		assert.True(t, found)
+	})
+
+	t.Run("RevokesSingleToken", func(t *testing.T) {
+		assert.Equal(t, 204, doRequest(t, ctx, "DELETE", fmt.Sprintf("/api/v1/tokens/%d", ciTokenID), ctx.Token))
+		assert.Equal(t, 401, doRequest(t, ctx, "GET", "/api/v1/jobs/job-a", ciToken))
+		assert.Equal(t, 200, doRequest(t, ctx, "GET", "/api/v1/jobs", ctx.Token), "other tokens stay valid")
+	})
+}
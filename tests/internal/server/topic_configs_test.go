@@ -0,0 +1,89 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/tests/mocks"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopicConfigOverridesAndDriftAPI(t *testing.T) {
+	ctx := setupTestServer(t)
+	db := ctx.Server.Db
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "src", Brokers: "localhost:9092", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "tgt", Brokers: "localhost:9093", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateJob(db, &database.ReplicationJob{
+		ID: "job-a", Name: "job-a", SourceClusterName: "src", TargetClusterName: "tgt", Status: "paused",
+	}))
+	require.NoError(t, database.UpdateMappingsForJob(db, "job-a", []database.TopicMapping{
+		{SourceTopicPattern: "orders", TargetTopicPattern: "orders-dr", Enabled: true},
+	}))
+
+	var overrides []database.TopicConfigOverride
+	status := alertsRequest(t, ctx, "PUT", "/api/v1/jobs/job-a/topic-configs/overrides", `{"overrides":{"retention.ms":"1209600000"}}`, &overrides)
+	require.Equal(t, http.StatusOK, status)
+	require.Len(t, overrides, 1)
+	assert.Equal(t, "retention.ms", overrides[0].Key)
+	assert.Equal(t, "testuser", overrides[0].UpdatedBy)
+
+	status = alertsRequest(t, ctx, "PUT", "/api/v1/jobs/job-a/topic-configs/overrides", `{"overrides":{" ":"1"}}`, nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	source := &mocks.MockAdminClient{}
+	source.On("DescribeTopicConfigs", mock.Anything, []string{"orders"}).Return(map[string]map[string]string{
+		"orders": {"retention.ms": "604800000", "cleanup.policy": "compact"},
+	}, nil)
+	source.On("Close").Return()
+	target := &mocks.MockAdminClient{}
+	target.On("DescribeTopicConfigs", mock.Anything, []string{"orders-dr"}).Return(map[string]map[string]string{
+		"orders-dr": {"retention.ms": "604800000", "cleanup.policy": "compact"},
+	}, nil)
+	target.On("GetClusterInfo", mock.Anything).Return(&kafka.ClusterInfo{Topics: map[string]kafka.TopicInfo{
+		"orders-dr": {Name: "orders-dr", ReplicationFactor: 3},
+	}}, nil)
+	target.On("Close").Return()
+	restore := kafka.SetAdminClientFactoryForTest(func(cfg config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		if cfg.Brokers == "localhost:9092" {
+			return source, nil
+		}
+		return target, nil
+	})
+	t.Cleanup(restore)
+
+	var report kafka.TopicConfigReport
+	status = alertsRequest(t, ctx, "GET", "/api/v1/jobs/job-a/topic-configs/drift", "", &report)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "job-a", report.JobID)
+	assert.Equal(t, 1, report.DriftedTopics)
+	require.Len(t, report.Topics, 1)
+	require.Len(t, report.Topics[0].Drift, 1)
+	assert.Equal(t, "1209600000", report.Topics[0].Drift[0].DesiredValue)
+	assert.True(t, report.Topics[0].Drift[0].Override)
+
+	target.On("AlterTopicConfigs", mock.Anything, "orders-dr", map[string]string{"retention.ms": "1209600000"}, true).Return(nil).Once()
+	status = alertsRequest(t, ctx, "POST", "/api/v1/jobs/job-a/topic-configs/sync?dry_run=true", "", &report)
+	require.Equal(t, http.StatusOK, status)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 0, report.AppliedTopics)
+	target.AssertExpectations(t)
+
+	status = alertsRequest(t, ctx, "GET", "/api/v1/jobs/missing/topic-configs/drift", "", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+}
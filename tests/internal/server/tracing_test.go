@@ -0,0 +1,72 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"kaf-mirror/internal/server/middleware"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingMiddleware_RecordsServerSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var handlerSpan trace.SpanContext
+	app := fiber.New()
+	app.Use(middleware.Tracing())
+	app.Get("/api/v1/jobs/:id", func(c *fiber.Ctx) error {
+		handlerSpan = trace.SpanContextFromContext(c.UserContext())
+		return c.SendString("ok")
+	})
+	app.Post("/api/v1/jobs/:id/start", func(c *fiber.Ctx) error {
+		return fiber.NewError(fiber.StatusServiceUnavailable, "cluster unreachable")
+	})
+
+	req := httptest.NewRequest("GET", "/api/v1/jobs/job-1", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	resp, err = app.Test(httptest.NewRequest("POST", "/api/v1/jobs/job-1/start", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	get := spans[0]
+	assert.Equal(t, "GET /api/v1/jobs/:id", get.Name())
+	assert.Equal(t, trace.SpanKindServer, get.SpanKind())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", get.SpanContext().TraceID().String())
+	assert.Equal(t, get.SpanContext().SpanID(), handlerSpan.SpanID(), "handlers see the request span")
+	assert.Contains(t, get.Attributes(), attribute.Int("http.response.status_code", fiber.StatusOK))
+	assert.Equal(t, codes.Unset, get.Status().Code)
+
+	post := spans[1]
+	assert.Equal(t, "POST /api/v1/jobs/:id/start", post.Name())
+	assert.Contains(t, post.Attributes(), attribute.Int("http.response.status_code", fiber.StatusServiceUnavailable))
+	assert.Equal(t, codes.Error, post.Status().Code)
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/kafka"
+	"kaf-mirror/tests/mocks"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+type emptyRecordReader struct{}
+
+func (emptyRecordReader) ReadRange(ctx context.Context, topic string, partition int32, start, end int64) ([]*kgo.Record, error) {
+	return nil, nil
+}
+
+func (emptyRecordReader) Close() {}
+
+func TestContentVerificationAPI(t *testing.T) {
+	ctx := setupTestServer(t)
+	db := ctx.Server.Db
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "src", Brokers: "localhost:9092", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateCluster(db, &database.KafkaCluster{Name: "tgt", Brokers: "localhost:9093", SecurityConfig: "{}"}))
+	require.NoError(t, database.CreateJob(db, &database.ReplicationJob{
+		ID: "job-a", Name: "job-a", SourceClusterName: "src", TargetClusterName: "tgt", Status: "paused",
+	}))
+	require.NoError(t, database.UpdateMappingsForJob(db, "job-a", []database.TopicMapping{
+		{SourceTopicPattern: "orders", TargetTopicPattern: "orders-dr", Enabled: true},
+	}))
+
+	admin := &mocks.MockAdminClient{}
+	admin.On("GetTopicHighWaterMarks", mock.Anything, mock.Anything).Return(map[string][]kafka.OffsetInfo{}, nil)
+	admin.On("GetConsumerGroupOffsets", mock.Anything, mock.Anything, mock.Anything).Return(map[string][]kafka.OffsetInfo{}, nil)
+	admin.On("Close").Return()
+	t.Cleanup(kafka.SetAdminClientFactoryForTest(func(cfg config.ClusterConfig) (kafka.AdminClientAPI, error) {
+		return admin, nil
+	}))
+	t.Cleanup(kafka.SetRecordReaderFactoryForTest(func(config.ClusterConfig, config.ReplicationConfig, string) (kafka.RecordReader, error) {
+		return emptyRecordReader{}, nil
+	}))
+
+	status := alertsRequest(t, ctx, "POST", "/api/v1/jobs/job-a/mirror/verify", `{"sample_rate":2}`, nil)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status = alertsRequest(t, ctx, "POST", "/api/v1/jobs/missing/mirror/verify", "", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+
+	var started database.ContentVerification
+	status = alertsRequest(t, ctx, "POST", "/api/v1/jobs/job-a/mirror/verify", `{"sample_rate":0.25}`, &started)
+	require.Equal(t, http.StatusAccepted, status)
+	assert.Equal(t, 0.25, started.SampleRate)
+
+	var v database.ContentVerification
+	require.Eventually(t, func() bool {
+		status := alertsRequest(t, ctx, "GET", fmt.Sprintf("/api/v1/jobs/job-a/mirror/verifications/%d", started.ID), "", &v)
+		return status == http.StatusOK && v.Status != database.VerificationRunning
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, database.VerificationCompleted, v.Status)
+	assert.Equal(t, "testuser", v.Initiator)
+
+	var list []database.ContentVerification
+	status = alertsRequest(t, ctx, "GET", "/api/v1/jobs/job-a/mirror/verifications", "", &list)
+	require.Equal(t, http.StatusOK, status)
+	assert.Len(t, list, 1)
+	status = alertsRequest(t, ctx, "GET", fmt.Sprintf("/api/v1/jobs/other/mirror/verifications/%d", started.ID), "", nil)
+	assert.Equal(t, http.StatusNotFound, status)
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"fmt"
+	"io"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/webhooks"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type webhookResult struct {
+	ID         int      `json:"id"`
+	Name       string   `json:"name"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	JobID      string   `json:"job_id"`
+	Enabled    bool     `json:"enabled"`
+	CreatedBy  string   `json:"created_by"`
+	Secret     string   `json:"secret"`
+}
+
+type deliveryResult struct {
+	ID        int    `json:"id"`
+	EventType string `json:"event_type"`
+	Status    string `json:"status"`
+	Attempts  int    `json:"attempts"`
+	ReplayOf  *int   `json:"replay_of"`
+}
+
+func TestWebhooksAPI(t *testing.T) {
+	ctx := setupTestServer(t)
+	require.NoError(t, database.CreateJob(ctx.Server.Db, &database.ReplicationJob{
+		ID: "job-a", Name: "job-a", SourceClusterName: "src", TargetClusterName: "tgt", Status: "paused",
+	}))
+
+	var created webhookResult
+	status := alertsRequest(t, ctx, "POST", "/api/v1/webhooks",
+		`{"name":"servicenow","url":"https://example.com/hook","event_types":["job.failed","job.gap_detected"],"job_id":"job-a"}`, &created)
+	require.Equal(t, 201, status)
+	assert.Equal(t, []string{"job.failed", "job.gap_detected"}, created.EventTypes)
+	assert.Equal(t, "job-a", created.JobID)
+	assert.Equal(t, "testuser", created.CreatedBy)
+	assert.True(t, created.Enabled)
+	assert.NotEmpty(t, created.Secret, "a secret is generated and returned on create")
+
+	var fetched webhookResult
+	require.Equal(t, 200, alertsRequest(t, ctx, "GET", fmt.Sprintf("/api/v1/webhooks/%d", created.ID), "", &fetched))
+	assert.Empty(t, fetched.Secret, "the secret is not returned afterwards")
+
+	assert.Equal(t, 409, alertsRequest(t, ctx, "POST", "/api/v1/webhooks", `{"name":"servicenow","url":"https://example.com/other"}`, nil))
+	assert.Equal(t, 400, alertsRequest(t, ctx, "POST", "/api/v1/webhooks", `{"name":"bad","url":"https://example.com","event_types":["job.deleted"]}`, nil))
+	assert.Equal(t, 400, alertsRequest(t, ctx, "POST", "/api/v1/webhooks", `{"name":"bad","url":"not a url"}`, nil))
+	assert.Equal(t, 400, alertsRequest(t, ctx, "POST", "/api/v1/webhooks", `{"name":"bad","url":"https://example.com","job_id":"missing"}`, nil))
+
+	var updated webhookResult
+	require.Equal(t, 200, alertsRequest(t, ctx, "PUT", fmt.Sprintf("/api/v1/webhooks/%d", created.ID), `{"enabled":false}`, &updated))
+	assert.False(t, updated.Enabled)
+	assert.Equal(t, []string{"job.failed", "job.gap_detected"}, updated.EventTypes, "fields left out are kept")
+	assert.Empty(t, updated.Secret)
+
+	require.Equal(t, 200, alertsRequest(t, ctx, "PUT", fmt.Sprintf("/api/v1/webhooks/%d", created.ID), `{"rotate_secret":true}`, &updated))
+	assert.NotEmpty(t, updated.Secret)
+	assert.NotEqual(t, created.Secret, updated.Secret)
+
+	var eventTypes []string
+	require.Equal(t, 200, alertsRequest(t, ctx, "GET", "/api/v1/webhooks/events", "", &eventTypes))
+	assert.Equal(t, database.WebhookEventTypes, eventTypes)
+
+	var list []webhookResult
+	require.Equal(t, 200, alertsRequest(t, ctx, "GET", "/api/v1/webhooks", "", &list))
+	assert.Len(t, list, 1)
+
+	assert.Equal(t, 204, alertsRequest(t, ctx, "DELETE", fmt.Sprintf("/api/v1/webhooks/%d", created.ID), "", nil))
+	assert.Equal(t, 404, alertsRequest(t, ctx, "GET", fmt.Sprintf("/api/v1/webhooks/%d", created.ID), "", nil))
+}
+
+func TestWebhooksAPI_TestDeliveriesAndReplay(t *testing.T) {
+	ctx := setupTestServer(t)
+
+	var mu sync.Mutex
+	var signatures []bool
+	statusCode := http.StatusInternalServerError
+	var secret string
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		expected := webhooks.Sign(secret, r.Header.Get(webhooks.HeaderTimestamp), body)
+		signatures = append(signatures, expected == r.Header.Get(webhooks.HeaderSignature))
+		w.WriteHeader(statusCode)
+	}))
+	defer receiver.Close()
+
+	var created webhookResult
+	require.Equal(t, 201, alertsRequest(t, ctx, "POST", "/api/v1/webhooks",
+		fmt.Sprintf(`{"name":"chatops","url":%q}`, receiver.URL), &created))
+	mu.Lock()
+	secret = created.Secret
+	mu.Unlock()
+
+	var delivery deliveryResult
+	require.Equal(t, 200, alertsRequest(t, ctx, "POST", fmt.Sprintf("/api/v1/webhooks/%d/test", created.ID), "", &delivery))
+	assert.Equal(t, database.WebhookEventTest, delivery.EventType)
+	assert.Equal(t, database.WebhookDeliveryPending, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+
+	// Completed deliveries only; the pending test delivery cannot be replayed yet.
+	assert.Equal(t, 409, alertsRequest(t, ctx, "POST", fmt.Sprintf("/api/v1/webhooks/deliveries/%d/replay", delivery.ID), "", nil))
+	_, err := ctx.Server.Db.Exec("UPDATE webhook_deliveries SET status = 'failed', next_attempt_at = NULL WHERE id = ?", delivery.ID)
+	require.NoError(t, err)
+
+	mu.Lock()
+	statusCode = http.StatusNoContent
+	mu.Unlock()
+
+	var replay struct {
+		Replayed   int              `json:"replayed"`
+		Deliveries []deliveryResult `json:"deliveries"`
+	}
+	require.Equal(t, 200, alertsRequest(t, ctx, "POST", fmt.Sprintf("/api/v1/webhooks/%d/replay", created.ID), "", &replay))
+	require.Equal(t, 1, replay.Replayed)
+	require.NotNil(t, replay.Deliveries[0].ReplayOf)
+	assert.Equal(t, delivery.ID, *replay.Deliveries[0].ReplayOf)
+
+	require.Eventually(t, func() bool {
+		var deliveries []deliveryResult
+		alertsRequest(t, ctx, "GET", fmt.Sprintf("/api/v1/webhooks/%d/deliveries?status=delivered", created.ID), "", &deliveries)
+		return len(deliveries) == 1
+	}, 5*time.Second, 20*time.Millisecond)
+
+	var all []deliveryResult
+	require.Equal(t, 200, alertsRequest(t, ctx, "GET", fmt.Sprintf("/api/v1/webhooks/%d/deliveries", created.ID), "", &all))
+	assert.Len(t, all, 2)
+	assert.Equal(t, 400, alertsRequest(t, ctx, "GET", fmt.Sprintf("/api/v1/webhooks/%d/deliveries?status=bogus", created.ID), "", nil))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []bool{true, true}, signatures)
+}
+
+func TestWebhooksAPI_Permissions(t *testing.T) {
+	ctx := setupTestServer(t)
+	user, err := database.CreateUser(ctx.Server.Db, "monitor", "password", false)
+	require.NoError(t, err)
+	require.NoError(t, database.SetUserRole(ctx.Server.Db, user.ID, "monitoring"))
+	token, _, err := database.CreateApiToken(ctx.Server.Db, user.ID, "monitor", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	monitor := &TestContext{Server: ctx.Server, Manager: ctx.Manager, Hub: ctx.Hub, Token: token}
+
+	assert.Equal(t, 200, alertsRequest(t, monitor, "GET", "/api/v1/webhooks", "", nil))
+	assert.Equal(t, 403, alertsRequest(t, monitor, "POST", "/api/v1/webhooks", `{"name":"x","url":"https://example.com"}`, nil))
+}
@@ -0,0 +1,301 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/joblogs"
+	"kaf-mirror/pkg/logger"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type wsEvent struct {
+	Version int             `json:"v"`
+	Type    string          `json:"type"`
+	Channel string          `json:"channel"`
+	JobID   string          `json:"job_id"`
+	Error   string          `json:"error"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// startListener serves the test server on a local port and returns its address.
+func startListener(t *testing.T, ctx *TestContext) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go ctx.Server.App.Listener(ln)
+	t.Cleanup(func() { ctx.Server.Shutdown() })
+	return ln.Addr().String()
+}
+
+func dialWS(t *testing.T, addr, token string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial(fmt.Sprintf("ws://%s/ws?token=%s", addr, token), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func readEvent(t *testing.T, conn *websocket.Conn) wsEvent {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var ev wsEvent
+	require.NoError(t, conn.ReadJSON(&ev))
+	assert.Equal(t, 1, ev.Version)
+	return ev
+}
+
+func sendRequest(t *testing.T, conn *websocket.Conn, req map[string]interface{}) {
+	require.NoError(t, conn.WriteJSON(req))
+}
+
+func subscribe(t *testing.T, conn *websocket.Conn, req map[string]interface{}) {
+	req["action"] = "subscribe"
+	sendRequest(t, conn, req)
+	ev := readEvent(t, conn)
+	require.Equal(t, "subscribed", ev.Type, ev.Error)
+}
+
+func TestWebSocket_WelcomeAndPing(t *testing.T) {
+	ctx := setupTestServer(t)
+	conn := dialWS(t, startListener(t, ctx), ctx.Token)
+
+	welcome := readEvent(t, conn)
+	require.Equal(t, "welcome", welcome.Type)
+	var data struct {
+		SchemaVersion int      `json:"schema_version"`
+		Channels      []string `json:"channels"`
+	}
+	require.NoError(t, json.Unmarshal(welcome.Data, &data))
+	assert.Equal(t, 1, data.SchemaVersion)
+	assert.Equal(t, []string{"alerts", "events", "insights", "job_logs", "job_metrics"}, data.Channels)
+
+	sendRequest(t, conn, map[string]interface{}{"action": "ping"})
+	assert.Equal(t, "pong", readEvent(t, conn).Type)
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("hello")))
+	assert.Equal(t, "error", readEvent(t, conn).Type)
+
+	sendRequest(t, conn, map[string]interface{}{"action": "subscribe", "channel": "everything"})
+	ev := readEvent(t, conn)
+	assert.Equal(t, "error", ev.Type)
+	assert.Equal(t, "unknown channel", ev.Error)
+}
+
+func TestWebSocket_JobMetricsFilteredByJob(t *testing.T) {
+	ctx := setupTestServer(t)
+	conn := dialWS(t, startListener(t, ctx), ctx.Token)
+	readEvent(t, conn)
+
+	subscribe(t, conn, map[string]interface{}{"channel": "job_metrics", "job_ids": []string{"job-a"}})
+	ctx.Manager.ProcessMetrics(database.ReplicationMetric{JobID: "job-b", MessagesReplicated: 1})
+	ctx.Manager.ProcessMetrics(database.ReplicationMetric{JobID: "job-a", MessagesReplicated: 42})
+
+	ev := readEvent(t, conn)
+	assert.Equal(t, "job_metrics", ev.Type)
+	assert.Equal(t, "job_metrics", ev.Channel)
+	assert.Equal(t, "job-a", ev.JobID)
+	var metric database.ReplicationMetric
+	require.NoError(t, json.Unmarshal(ev.Data, &metric))
+	assert.Equal(t, 42, metric.MessagesReplicated)
+
+	sendRequest(t, conn, map[string]interface{}{"action": "unsubscribe", "channel": "job_metrics"})
+	assert.Equal(t, "unsubscribed", readEvent(t, conn).Type)
+	ctx.Manager.ProcessMetrics(database.ReplicationMetric{JobID: "job-a"})
+	sendRequest(t, conn, map[string]interface{}{"action": "ping"})
+	assert.Equal(t, "pong", readEvent(t, conn).Type)
+}
+
+func TestWebSocket_EventsAndInsights(t *testing.T) {
+	ctx := setupTestServer(t)
+	conn := dialWS(t, startListener(t, ctx), ctx.Token)
+	readEvent(t, conn)
+
+	subscribe(t, conn, map[string]interface{}{"channel": "events"})
+	subscribe(t, conn, map[string]interface{}{"channel": "insights"})
+
+	require.NoError(t, database.CreateOperationalEvent(ctx.Server.Db, &database.OperationalEvent{
+		EventType: "job_started", Initiator: "tester", Details: "ws test",
+	}))
+	ev := readEvent(t, conn)
+	require.Equal(t, "event", ev.Type)
+	assert.Contains(t, string(ev.Data), "ws test")
+
+	jobID := "job-a"
+	require.NoError(t, database.InsertAIInsight(ctx.Server.Db, &database.AIInsight{
+		JobID: &jobID, InsightType: "anomaly", SeverityLevel: "high", Recommendation: "scale out",
+	}))
+	for {
+		ev = readEvent(t, conn)
+		if ev.Type != "event" {
+			break
+		}
+	}
+	assert.Equal(t, "insight", ev.Type)
+	assert.Equal(t, "job-a", ev.JobID)
+	assert.Contains(t, string(ev.Data), "scale out")
+}
+
+func TestWebSocket_ChannelPermissions(t *testing.T) {
+	ctx := setupTestServer(t)
+	addr := startListener(t, ctx)
+
+	user, err := database.CreateUser(ctx.Server.Db, "auditor", "password", false)
+	require.NoError(t, err)
+	require.NoError(t, database.SetUserRole(ctx.Server.Db, user.ID, "compliance"))
+	token, _, err := database.CreateApiToken(ctx.Server.Db, user.ID, "ws", time.Now().Add(time.Hour))
+	require.NoError(t, err)
+
+	conn := dialWS(t, addr, token)
+	var data struct {
+		Channels []string `json:"channels"`
+	}
+	require.NoError(t, json.Unmarshal(readEvent(t, conn).Data, &data))
+	assert.NotContains(t, data.Channels, "insights")
+	assert.Contains(t, data.Channels, "events")
+
+	sendRequest(t, conn, map[string]interface{}{"action": "subscribe", "channel": "insights"})
+	ev := readEvent(t, conn)
+	assert.Equal(t, "error", ev.Type)
+	assert.Contains(t, ev.Error, "ai:insights:view")
+	subscribe(t, conn, map[string]interface{}{"channel": "events"})
+
+	admin, err := database.GetUserByUsername(ctx.Server.Db, "testuser")
+	require.NoError(t, err)
+	scoped, _, err := database.CreateScopedApiToken(ctx.Server.Db, admin.ID, database.ApiTokenScope{
+		Name: "scoped", Permissions: []string{"metrics:view"}, JobIDs: []string{"job-a"}, ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	conn = dialWS(t, addr, scoped)
+	require.NoError(t, json.Unmarshal(readEvent(t, conn).Data, &data))
+	assert.Equal(t, []string{"job_metrics"}, data.Channels)
+
+	sendRequest(t, conn, map[string]interface{}{"action": "subscribe", "channel": "job_metrics", "job_id": "job-b"})
+	assert.Equal(t, "error", readEvent(t, conn).Type)
+
+	subscribe(t, conn, map[string]interface{}{"channel": "job_metrics"})
+	ctx.Manager.ProcessMetrics(database.ReplicationMetric{JobID: "job-b"})
+	ctx.Manager.ProcessMetrics(database.ReplicationMetric{JobID: "job-a"})
+	assert.Equal(t, "job-a", readEvent(t, conn).JobID)
+}
+
+func TestWebSocket_ScopedTokenSkipsCrossJobEvents(t *testing.T) {
+	ctx := setupTestServer(t)
+	addr := startListener(t, ctx)
+
+	admin, err := database.GetUserByUsername(ctx.Server.Db, "testuser")
+	require.NoError(t, err)
+	scoped, _, err := database.CreateScopedApiToken(ctx.Server.Db, admin.ID, database.ApiTokenScope{
+		Name: "scoped", Permissions: []string{"events:view", "alerts:view"}, JobIDs: []string{"job-a"}, ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	conn := dialWS(t, addr, scoped)
+	readEvent(t, conn)
+	subscribe(t, conn, map[string]interface{}{"channel": "events"})
+	subscribe(t, conn, map[string]interface{}{"channel": "alerts"})
+
+	ctx.Hub.Publish("events", "", "cluster-wide")
+	ctx.Hub.Publish("alerts", "job-b", "other job")
+	ctx.Hub.Publish("alerts", "job-a", "own job")
+	ev := readEvent(t, conn)
+	assert.Equal(t, "alerts", ev.Channel)
+	assert.Equal(t, "job-a", ev.JobID)
+}
+
+func TestWebSocket_JobLogsSubscription(t *testing.T) {
+	ctx := setupTestServer(t)
+	conn := dialWS(t, startListener(t, ctx), ctx.Token)
+	readEvent(t, conn)
+
+	sendRequest(t, conn, map[string]interface{}{"action": "subscribe", "channel": "job_logs"})
+	assert.Equal(t, "job_id is required", readEvent(t, conn).Error)
+
+	subscribe(t, conn, map[string]interface{}{"channel": "job_logs", "job_id": "job-ws", "level": "warn"})
+
+	log := logger.With(logger.JobID("job-ws"))
+	log.Info("filtered")
+	log.Warn("over the limit")
+	ev := readEvent(t, conn)
+	assert.Equal(t, "job_log", ev.Type)
+	assert.Equal(t, "job_logs", ev.Channel)
+	assert.Equal(t, "job-ws", ev.JobID)
+	var entry joblogs.Entry
+	require.NoError(t, json.Unmarshal(ev.Data, &entry))
+	assert.Equal(t, "over the limit", entry.Message)
+
+	sendRequest(t, conn, map[string]interface{}{"action": "unsubscribe", "channel": "job_logs", "job_id": "job-ws"})
+	assert.Equal(t, "unsubscribed", readEvent(t, conn).Type)
+}
+
+func TestWebSocket_Heartbeats(t *testing.T) {
+	ctx := setupTestServer(t)
+	ctx.Hub.PingInterval = 50 * time.Millisecond
+	addr := startListener(t, ctx)
+
+	// A reading client answers pings and stays connected.
+	conn := dialWS(t, addr, ctx.Token)
+	var pings atomic.Int32
+	conn.SetPingHandler(func(data string) error {
+		pings.Add(1)
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	require.Eventually(t, func() bool { return pings.Load() >= 4 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 1, ctx.Hub.Clients())
+
+	// A client that never reads never answers pings and is dropped.
+	dialWS(t, addr, ctx.Token)
+	require.Eventually(t, func() bool { return ctx.Hub.Clients() == 1 }, 5*time.Second, 10*time.Millisecond)
+	assert.GreaterOrEqual(t, pings.Load(), int32(4))
+}
+
+func TestWebSocket_SlowClientIsDisconnected(t *testing.T) {
+	ctx := setupTestServer(t)
+	ctx.Hub.SendQueueSize = 4
+	conn := dialWS(t, startListener(t, ctx), ctx.Token)
+	readEvent(t, conn)
+	subscribe(t, conn, map[string]interface{}{"channel": "events"})
+
+	payload := strings.Repeat("x", 64*1024)
+	for i := 0; i < 500 && ctx.Hub.Clients() > 0; i++ {
+		ctx.Hub.Publish("events", "", payload)
+	}
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(10*time.Second)))
+	for {
+		_, _, err := conn.ReadMessage()
+		if err == nil {
+			continue
+		}
+		var closeErr *websocket.CloseError
+		require.True(t, errors.As(err, &closeErr), "unexpected error: %v", err)
+		assert.Equal(t, websocket.ClosePolicyViolation, closeErr.Code)
+		break
+	}
+	require.Eventually(t, func() bool { return ctx.Hub.Clients() == 0 }, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package telemetry_test
+
+import (
+	"context"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/telemetry"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+)
+
+// traceCollector stands in for an OTLP/gRPC collector and keeps the spans it receives.
+type traceCollector struct {
+	coltracepb.UnimplementedTraceServiceServer
+
+	mu       sync.Mutex
+	spans    []*tracepb.Span
+	services []string
+}
+
+func (c *traceCollector) Export(_ context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rs := range req.ResourceSpans {
+		for _, kv := range rs.Resource.GetAttributes() {
+			if kv.Key == "service.name" {
+				c.services = append(c.services, kv.Value.GetStringValue())
+			}
+		}
+		for _, ss := range rs.ScopeSpans {
+			c.spans = append(c.spans, ss.Spans...)
+		}
+	}
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func TestSetup_ExportsSpansOverGRPC(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	collector := &traceCollector{}
+	grpcServer := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(grpcServer, collector)
+	go grpcServer.Serve(lis)
+	defer grpcServer.Stop()
+
+	shutdown, err := telemetry.Setup(context.Background(), config.MonitoringConfig{
+		OTLP: config.OTLPConfig{
+			Endpoint:    lis.Addr().String(),
+			Protocol:    "grpc",
+			Insecure:    true,
+			ServiceName: "mirror-test",
+		},
+		Tracing: config.TracingConfig{Enabled: true, SampleRatio: 1},
+	}, "1.2.3")
+	require.NoError(t, err)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "StartJob")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	require.Len(t, collector.spans, 1)
+	assert.Equal(t, "StartJob", collector.spans[0].Name)
+	assert.Equal(t, []string{"mirror-test"}, collector.services)
+}
+
+func TestSetup_DisabledTracingInstallsPropagatorOnly(t *testing.T) {
+	shutdown, err := telemetry.Setup(context.Background(), config.MonitoringConfig{}, "")
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+	assert.Contains(t, otel.GetTextMapPropagator().Fields(), "traceparent")
+}
@@ -0,0 +1,222 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"kaf-mirror/internal/config"
+	"kaf-mirror/internal/database"
+	"kaf-mirror/internal/webhooks"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver is a local webhook endpoint answering with the queued status codes,
+// then with 200.
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	requests []receivedRequest
+}
+
+func newReceiver(t *testing.T, statuses ...int) *receiver {
+	r := &receiver{statuses: statuses}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.requests = append(r.requests, receivedRequest{header: req.Header.Clone(), body: body})
+		status := http.StatusOK
+		if len(r.statuses) > 0 {
+			status, r.statuses = r.statuses[0], r.statuses[1:]
+		}
+		r.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *receiver) received() []receivedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedRequest(nil), r.requests...)
+}
+
+func (r *receiver) respond(statuses ...int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.statuses = statuses
+}
+
+func setup(t *testing.T, cfg config.WebhooksConfig) (*sqlx.DB, *webhooks.Dispatcher) {
+	db, err := database.InitDB(":memory:")
+	require.NoError(t, err)
+	dispatcher := webhooks.NewDispatcher(db, cfg)
+	t.Cleanup(func() {
+		dispatcher.Close()
+		db.Close()
+	})
+	return db, dispatcher
+}
+
+func createWebhook(t *testing.T, db *sqlx.DB, webhook database.Webhook) *database.Webhook {
+	webhook.Enabled = true
+	if webhook.Secret == "" {
+		webhook.Secret = "s3cret"
+	}
+	require.NoError(t, webhook.Validate())
+	require.NoError(t, database.CreateWebhook(db, &webhook))
+	return &webhook
+}
+
+func waitForDeliveries(t *testing.T, db *sqlx.DB, webhookID int, status string, n int) []database.WebhookDelivery {
+	var deliveries []database.WebhookDelivery
+	require.Eventually(t, func() bool {
+		var err error
+		deliveries, err = database.ListWebhookDeliveries(db, webhookID, status, 0)
+		return err == nil && len(deliveries) == n
+	}, 5*time.Second, 10*time.Millisecond)
+	return deliveries
+}
+
+func TestDispatcherSignsAndFiltersEvents(t *testing.T) {
+	db, dispatcher := setup(t, config.WebhooksConfig{})
+	rcv := newReceiver(t)
+	failures := createWebhook(t, db, database.Webhook{Name: "failures", URL: rcv.URL,
+		EventTypes: database.WebhookEventJobFailed})
+	jobB := createWebhook(t, db, database.Webhook{Name: "job-b", URL: rcv.URL + "/b", JobID: "job-b"})
+
+	dispatcher.Emit(database.WebhookEventJobStarted, "job-a", map[string]string{"name": "orders"})
+	dispatcher.Emit(database.WebhookEventJobFailed, "job-a", map[string]string{"failed_reason": "boom"})
+
+	delivered := waitForDeliveries(t, db, failures.ID, database.WebhookDeliveryDelivered, 1)
+	assert.Equal(t, database.WebhookEventJobFailed, delivered[0].EventType)
+	assert.Equal(t, 1, delivered[0].Attempts)
+	require.NotNil(t, delivered[0].ResponseStatus)
+	assert.Equal(t, http.StatusOK, *delivered[0].ResponseStatus)
+
+	requests := rcv.received()
+	require.Len(t, requests, 1)
+	req := requests[0]
+	assert.Equal(t, database.WebhookEventJobFailed, req.header.Get(webhooks.HeaderEvent))
+	timestamp := req.header.Get(webhooks.HeaderTimestamp)
+	assert.Equal(t, webhooks.Sign("s3cret", timestamp, req.body), req.header.Get(webhooks.HeaderSignature))
+	assert.NotEqual(t, webhooks.Sign("wrong", timestamp, req.body), req.header.Get(webhooks.HeaderSignature))
+
+	var payload struct {
+		EventID string            `json:"event_id"`
+		Event   string            `json:"event"`
+		JobID   string            `json:"job_id"`
+		Data    map[string]string `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(req.body, &payload))
+	assert.Equal(t, delivered[0].EventID, payload.EventID)
+	assert.Equal(t, "job-a", payload.JobID)
+	assert.Equal(t, "boom", payload.Data["failed_reason"])
+
+	deliveries, err := database.ListWebhookDeliveries(db, jobB.ID, "", 0)
+	require.NoError(t, err)
+	assert.Empty(t, deliveries, "webhook scoped to job-b must not receive job-a events")
+}
+
+func TestDispatcherRetriesWithBackoff(t *testing.T) {
+	db, dispatcher := setup(t, config.WebhooksConfig{MaxAttempts: 5, InitialBackoff: "20ms", MaxBackoff: "40ms"})
+	rcv := newReceiver(t, http.StatusServiceUnavailable, http.StatusInternalServerError)
+	webhook := createWebhook(t, db, database.Webhook{Name: "flaky", URL: rcv.URL})
+
+	dispatcher.Emit(database.WebhookEventJobStopped, "job-a", nil)
+
+	delivered := waitForDeliveries(t, db, webhook.ID, database.WebhookDeliveryDelivered, 1)
+	assert.Equal(t, 3, delivered[0].Attempts)
+	assert.Nil(t, delivered[0].LastError)
+	assert.Len(t, rcv.received(), 3)
+}
+
+func TestDispatcherMarksFailedDeliveriesAndReplays(t *testing.T) {
+	db, dispatcher := setup(t, config.WebhooksConfig{MaxAttempts: 2, InitialBackoff: "10ms"})
+	rcv := newReceiver(t, http.StatusBadGateway, http.StatusBadGateway)
+	webhook := createWebhook(t, db, database.Webhook{Name: "down", URL: rcv.URL})
+
+	dispatcher.Emit(database.WebhookEventGapDetected, "job-a", map[string]int{"gap_count": 2})
+
+	failed := waitForDeliveries(t, db, webhook.ID, database.WebhookDeliveryFailed, 1)
+	assert.Equal(t, 2, failed[0].Attempts)
+	require.NotNil(t, failed[0].LastError)
+	assert.Contains(t, *failed[0].LastError, "502")
+
+	rcv.respond()
+	replays, err := dispatcher.ReplayFailed(webhook.ID)
+	require.NoError(t, err)
+	require.Len(t, replays, 1)
+	require.NotNil(t, replays[0].ReplayOf)
+	assert.Equal(t, failed[0].ID, *replays[0].ReplayOf)
+
+	delivered := waitForDeliveries(t, db, webhook.ID, database.WebhookDeliveryDelivered, 1)
+	assert.Equal(t, failed[0].EventID, delivered[0].EventID)
+	requests := rcv.received()
+	assert.Equal(t, requests[0].body, requests[len(requests)-1].body, "replays resend the original payload")
+
+	again, err := dispatcher.ReplayFailed(webhook.ID)
+	require.NoError(t, err)
+	assert.Empty(t, again, "a failed delivery is only replayed once")
+
+	_, err = dispatcher.Replay(delivered[0].ID)
+	require.NoError(t, err, "delivered events can be replayed individually")
+}
+
+func TestDispatcherTestEvent(t *testing.T) {
+	db, dispatcher := setup(t, config.WebhooksConfig{})
+	rcv := newReceiver(t, http.StatusForbidden)
+	webhook := createWebhook(t, db, database.Webhook{Name: "test", URL: rcv.URL, EventTypes: database.WebhookEventJobFailed})
+
+	delivery, err := dispatcher.Test(context.Background(), webhook.ID)
+	require.NoError(t, err)
+	assert.Equal(t, database.WebhookEventTest, delivery.EventType)
+	assert.Equal(t, database.WebhookDeliveryPending, delivery.Status, "a failed test is retried")
+	require.NotNil(t, delivery.ResponseStatus)
+	assert.Equal(t, http.StatusForbidden, *delivery.ResponseStatus)
+
+	delivery, err = dispatcher.Test(context.Background(), webhook.ID)
+	require.NoError(t, err)
+	assert.Equal(t, database.WebhookDeliveryDelivered, delivery.Status)
+	assert.Len(t, rcv.received(), 2)
+}
+
+func TestWebhookValidate(t *testing.T) {
+	valid := database.Webhook{Name: "ok", URL: "https://example.com/hook", EventTypes: "job.started,job.failed"}
+	assert.NoError(t, valid.Validate())
+
+	for name, webhook := range map[string]database.Webhook{
+		"missing name":  {URL: "https://example.com"},
+		"relative url":  {Name: "x", URL: "/hook"},
+		"bad scheme":    {Name: "x", URL: "ftp://example.com"},
+		"unknown event": {Name: "x", URL: "https://example.com", EventTypes: "job.deleted"},
+		"test event":    {Name: "x", URL: "https://example.com", EventTypes: database.WebhookEventTest},
+	} {
+		assert.Error(t, webhook.Validate(), name)
+	}
+}
@@ -43,13 +43,68 @@ func (m *MockAdminClient) GetTopicHighWaterMarks(ctx context.Context, topics []s
 	return args.Get(0).(map[string][]kafka.OffsetInfo), args.Error(1)
 }
 
+func (m *MockAdminClient) GetOffsetsForTimestamp(ctx context.Context, topics []string, millis int64) (map[string][]kafka.OffsetInfo, error) {
+	args := m.Called(ctx, topics, millis)
+	return args.Get(0).(map[string][]kafka.OffsetInfo), args.Error(1)
+}
+
 func (m *MockAdminClient) ValidateTopicCompatibility(ctx context.Context, sourceInfo, targetInfo kafka.TopicInfo) error {
 	args := m.Called(ctx, sourceInfo, targetInfo)
 	return args.Error(0)
 }
 
-func (m *MockAdminClient) EnsureTopicExists(ctx context.Context, topicName string, partitions int32, replicationFactor int16) error {
-	args := m.Called(ctx, topicName, partitions, replicationFactor)
+func (m *MockAdminClient) EnsureTopicExists(ctx context.Context, topicName string, partitions int32, replicationFactor int16, configs map[string]string) error {
+	args := m.Called(ctx, topicName, partitions, replicationFactor, configs)
+	return args.Error(0)
+}
+
+func (m *MockAdminClient) DescribeTopicConfigs(ctx context.Context, topics ...string) (map[string]map[string]string, error) {
+	args := m.Called(ctx, topics)
+	return args.Get(0).(map[string]map[string]string), args.Error(1)
+}
+
+func (m *MockAdminClient) AlterTopicConfigs(ctx context.Context, topic string, configs map[string]string, validateOnly bool) error {
+	args := m.Called(ctx, topic, configs, validateOnly)
+	return args.Error(0)
+}
+
+func (m *MockAdminClient) CreatePartitions(ctx context.Context, topicName string, totalPartitions int32) error {
+	args := m.Called(ctx, topicName, totalPartitions)
+	return args.Error(0)
+}
+
+func (m *MockAdminClient) DeleteTopic(ctx context.Context, topicName string) error {
+	args := m.Called(ctx, topicName)
+	return args.Error(0)
+}
+
+func (m *MockAdminClient) DescribeACLs(ctx context.Context) ([]kafka.ACLEntry, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]kafka.ACLEntry), args.Error(1)
+}
+
+func (m *MockAdminClient) ListConsumerGroups(ctx context.Context) ([]string, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockAdminClient) CommitConsumerGroupOffsets(ctx context.Context, groupID string, offsets map[string]map[int32]int64) error {
+	args := m.Called(ctx, groupID, offsets)
+	return args.Error(0)
+}
+
+func (m *MockAdminClient) CreateACLs(ctx context.Context, acls []kafka.ACLEntry) error {
+	args := m.Called(ctx, acls)
+	return args.Error(0)
+}
+
+func (m *MockAdminClient) DescribeClientQuotas(ctx context.Context) ([]kafka.ClientQuota, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]kafka.ClientQuota), args.Error(1)
+}
+
+func (m *MockAdminClient) AlterClientQuotas(ctx context.Context, quotas []kafka.ClientQuota, validateOnly bool) error {
+	args := m.Called(ctx, quotas, validateOnly)
 	return args.Error(0)
 }
 
@@ -12,12 +12,19 @@
 
 package mocks
 
+import "sync"
+
 // MockHub is a mock implementation of the Hub interface.
 type MockHub struct {
+	mu         sync.Mutex
 	Broadcasts int
+	Channels   []string
 }
 
-// BroadcastJSON increments the broadcast count.
-func (m *MockHub) BroadcastJSON(v interface{}) {
+// Publish increments the broadcast count and records the channel.
+func (m *MockHub) Publish(channel, jobID string, data interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	m.Broadcasts++
+	m.Channels = append(m.Channels, channel)
 }
@@ -24,6 +24,12 @@ type MockKgoClient struct {
 	PollFetchesFunc func(context.Context) kgo.Fetches
 	ProduceFunc     func(context.Context, *kgo.Record, func(*kgo.Record, error))
 	AddConsumeTopicsFunc func(...string)
+	PurgeTopicsFromConsumingFunc func(...string)
+	AddConsumePartitionsFunc func(map[string]map[int32]kgo.Offset)
+	RemoveConsumePartitionsFunc func(map[string][]int32)
+	PauseFetchPartitionsFunc func(map[string][]int32)
+	ResumeFetchPartitionsFunc func(map[string][]int32)
+	MarkCommitRecordsFunc func(...*kgo.Record)
 	CloseFunc       func()
 }
 
@@ -53,6 +59,43 @@ func (m *MockKgoClient) AddConsumeTopics(topics ...string) {
 	}
 }
 
+func (m *MockKgoClient) PurgeTopicsFromConsuming(topics ...string) {
+	if m.PurgeTopicsFromConsumingFunc != nil {
+		m.PurgeTopicsFromConsumingFunc(topics...)
+	}
+}
+
+func (m *MockKgoClient) AddConsumePartitions(partitions map[string]map[int32]kgo.Offset) {
+	if m.AddConsumePartitionsFunc != nil {
+		m.AddConsumePartitionsFunc(partitions)
+	}
+}
+
+func (m *MockKgoClient) RemoveConsumePartitions(partitions map[string][]int32) {
+	if m.RemoveConsumePartitionsFunc != nil {
+		m.RemoveConsumePartitionsFunc(partitions)
+	}
+}
+
+func (m *MockKgoClient) PauseFetchPartitions(partitions map[string][]int32) map[string][]int32 {
+	if m.PauseFetchPartitionsFunc != nil {
+		m.PauseFetchPartitionsFunc(partitions)
+	}
+	return partitions
+}
+
+func (m *MockKgoClient) ResumeFetchPartitions(partitions map[string][]int32) {
+	if m.ResumeFetchPartitionsFunc != nil {
+		m.ResumeFetchPartitionsFunc(partitions)
+	}
+}
+
+func (m *MockKgoClient) MarkCommitRecords(records ...*kgo.Record) {
+	if m.MarkCommitRecordsFunc != nil {
+		m.MarkCommitRecordsFunc(records...)
+	}
+}
+
 func (m *MockKgoClient) Close() {
 	if m.CloseFunc != nil {
 		m.CloseFunc()
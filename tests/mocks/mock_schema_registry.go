@@ -0,0 +1,219 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mocks
+
+import (
+	"encoding/json"
+	"kaf-mirror/internal/kafka"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MockSchemaRegistry is an in-memory stand-in for a Confluent-compatible Schema
+// Registry, served over a local HTTP server. Like the real registry, the same
+// schema gets the same ID in every subject it is registered under.
+type MockSchemaRegistry struct {
+	Server *httptest.Server
+
+	mu       sync.Mutex
+	nextID   int
+	schemas  map[int]kafka.RegisteredSchema
+	ids      map[string]int
+	subjects map[string][]int // subject -> schema IDs, index+1 is the version
+	requests int
+}
+
+// NewMockSchemaRegistry starts a registry that hands out IDs from firstID.
+// Callers should Close it when done.
+func NewMockSchemaRegistry(firstID int) *MockSchemaRegistry {
+	m := &MockSchemaRegistry{
+		nextID:   firstID,
+		schemas:  make(map[int]kafka.RegisteredSchema),
+		ids:      make(map[string]int),
+		subjects: make(map[string][]int),
+	}
+	m.Server = httptest.NewServer(http.HandlerFunc(m.serve))
+	return m
+}
+
+// URL returns the base URL of the registry.
+func (m *MockSchemaRegistry) URL() string {
+	return m.Server.URL
+}
+
+// Close shuts the registry down.
+func (m *MockSchemaRegistry) Close() {
+	m.Server.Close()
+}
+
+// Register registers a schema under subject and returns its ID.
+func (m *MockSchemaRegistry) Register(subject string, schema kafka.RegisteredSchema) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	id, _ := m.register(subject, schema)
+	return id
+}
+
+// Subjects returns the registered subjects and their schema IDs by version.
+func (m *MockSchemaRegistry) Subjects() map[string][]int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string][]int, len(m.subjects))
+	for subject, ids := range m.subjects {
+		out[subject] = append([]int(nil), ids...)
+	}
+	return out
+}
+
+// Requests returns the number of HTTP requests served.
+func (m *MockSchemaRegistry) Requests() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.requests
+}
+
+func schemaKey(schema kafka.RegisteredSchema) string {
+	refs, _ := json.Marshal(schema.References)
+	return schema.SchemaType + "\x00" + schema.Schema + "\x00" + string(refs)
+}
+
+func (m *MockSchemaRegistry) register(subject string, schema kafka.RegisteredSchema) (int, int) {
+	key := schemaKey(schema)
+	id, ok := m.ids[key]
+	if !ok {
+		id = m.nextID
+		m.nextID++
+		m.ids[key] = id
+		m.schemas[id] = kafka.RegisteredSchema{Schema: schema.Schema, SchemaType: schema.SchemaType, References: schema.References}
+	}
+	for i, existing := range m.subjects[subject] {
+		if existing == id {
+			return id, i + 1
+		}
+	}
+	m.subjects[subject] = append(m.subjects[subject], id)
+	return id, len(m.subjects[subject])
+}
+
+func (m *MockSchemaRegistry) version(subject string, version int) (kafka.RegisteredSchema, bool) {
+	ids := m.subjects[subject]
+	if version < 1 || version > len(ids) {
+		return kafka.RegisteredSchema{}, false
+	}
+	schema := m.schemas[ids[version-1]]
+	schema.Subject = subject
+	schema.Version = version
+	schema.ID = ids[version-1]
+	return schema, true
+}
+
+func (m *MockSchemaRegistry) serve(w http.ResponseWriter, req *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests++
+
+	parts := strings.Split(strings.Trim(req.URL.EscapedPath(), "/"), "/")
+	for i, part := range parts {
+		if unescaped, err := url.PathUnescape(part); err == nil {
+			parts[i] = unescaped
+		}
+	}
+
+	switch {
+	case len(parts) >= 3 && parts[0] == "schemas" && parts[1] == "ids" && req.Method == http.MethodGet:
+		id, err := strconv.Atoi(parts[2])
+		schema, ok := m.schemas[id]
+		if err != nil || !ok {
+			notFound(w, 40403, "Schema not found")
+			return
+		}
+		if len(parts) == 4 && parts[3] == "versions" {
+			var versions []kafka.SubjectVersion
+			for subject, ids := range m.subjects {
+				for i, registered := range ids {
+					if registered == id {
+						versions = append(versions, kafka.SubjectVersion{Subject: subject, Version: i + 1})
+					}
+				}
+			}
+			writeJSON(w, versions)
+			return
+		}
+		writeJSON(w, schema)
+
+	case len(parts) == 3 && parts[0] == "subjects" && parts[2] == "versions" && req.Method == http.MethodGet:
+		ids, ok := m.subjects[parts[1]]
+		if !ok {
+			notFound(w, 40401, "Subject not found")
+			return
+		}
+		versions := make([]int, len(ids))
+		for i := range ids {
+			versions[i] = i + 1
+		}
+		writeJSON(w, versions)
+
+	case len(parts) == 4 && parts[0] == "subjects" && parts[2] == "versions" && req.Method == http.MethodGet:
+		version, _ := strconv.Atoi(parts[3])
+		schema, ok := m.version(parts[1], version)
+		if !ok {
+			notFound(w, 40402, "Version not found")
+			return
+		}
+		writeJSON(w, schema)
+
+	case len(parts) == 3 && parts[0] == "subjects" && parts[2] == "versions" && req.Method == http.MethodPost:
+		var schema kafka.RegisteredSchema
+		if err := json.NewDecoder(req.Body).Decode(&schema); err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		id, _ := m.register(parts[1], schema)
+		writeJSON(w, map[string]int{"id": id})
+
+	case len(parts) == 2 && parts[0] == "subjects" && req.Method == http.MethodPost:
+		var schema kafka.RegisteredSchema
+		if err := json.NewDecoder(req.Body).Decode(&schema); err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		id, ok := m.ids[schemaKey(schema)]
+		if ok {
+			for i, registered := range m.subjects[parts[1]] {
+				if registered == id {
+					found, _ := m.version(parts[1], i+1)
+					writeJSON(w, found)
+					return
+				}
+			}
+		}
+		notFound(w, 40403, "Schema not found")
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func notFound(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	w.WriteHeader(http.StatusNotFound)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"error_code": code, "message": message})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Scalytics, Inc. and Scalytics Europe, LTD
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//     http://www.apache.org/licenses/LICENSE-2.0
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"kaf-mirror/pkg/logger"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONFormat_EmitsContextualFields(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := logger.New(&buf, logger.INFO, "json")
+	require.NoError(t, err)
+
+	l.With(logger.JobID("job-1"), logger.Component("consumer")).
+		With(logger.Cluster("source"), logger.Topic("orders"), logger.Partition(3)).
+		Warn("lag is %d", 42)
+	l.Debug("filtered out")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 1)
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, "WARN", record["level"])
+	assert.Equal(t, "lag is 42", record["msg"])
+	assert.Equal(t, "job-1", record["job_id"])
+	assert.Equal(t, "consumer", record["component"])
+	assert.Equal(t, "source", record["cluster"])
+	assert.Equal(t, "orders", record["topic"])
+	assert.Equal(t, 3.0, record["partition"])
+	assert.Contains(t, record["caller"], "logger_test.go:")
+	assert.Contains(t, record, "time")
+}
+
+func TestJSONFormat_AITags(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := logger.New(&buf, logger.DEBUG, "json")
+	require.NoError(t, err)
+
+	l.With(logger.JobID("job-1")).AI(logger.ERROR, "incident", "escalation", "error rate %.1f%%", 12.5)
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "ERROR", record["level"])
+	assert.Equal(t, "incident", record["ai_category"])
+	assert.Equal(t, "escalation", record["ai_subcategory"])
+	assert.Equal(t, "job-1", record["job_id"])
+	assert.Equal(t, "error rate 12.5%", record["msg"])
+}
+
+func TestTextFormat_RendersFieldsAsTags(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := logger.New(&buf, logger.INFO, "")
+	require.NoError(t, err)
+
+	l.With(logger.Topic("orders"), logger.JobID("job-1"), logger.Partition(0)).Info("replicated")
+	l.Info("plain")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Regexp(t, `^\[[0-9-]+ [0-9:.]+\] INFO  \[logger_test\.go:\d+\] \[job:job-1\] \[topic:orders\] \[partition:0\] replicated$`, lines[0])
+	assert.Regexp(t, `\[logger_test\.go:\d+\] plain$`, lines[1])
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := logger.ParseFormat("JSON")
+	assert.NoError(t, err)
+	assert.Equal(t, logger.FormatJSON, format)
+
+	_, err = logger.ParseFormat("xml")
+	assert.Error(t, err)
+}
+
+func TestAddListener_ReceivesRecordsWithFields(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := logger.New(&buf, logger.INFO, "text")
+	require.NoError(t, err)
+
+	var records []logger.Record
+	remove := logger.AddListener(func(rec logger.Record) { records = append(records, rec) })
+
+	l.With(logger.JobID("job-1"), logger.Component("producer"), logger.Topic("orders"), logger.Partition(2)).Error("produce failed: %s", "timeout")
+	l.Debug("below level")
+	remove()
+	l.Info("after removal")
+
+	require.Len(t, records, 1)
+	rec := records[0]
+	assert.Equal(t, "ERROR", rec.Level)
+	assert.Equal(t, "produce failed: timeout", rec.Message)
+	assert.Equal(t, "job-1", rec.JobID)
+	assert.Equal(t, "producer", rec.Component)
+	assert.Equal(t, "orders", rec.Topic)
+	require.NotNil(t, rec.Partition)
+	assert.Equal(t, int32(2), *rec.Partition)
+	assert.Contains(t, rec.Caller, "logger_test.go:")
+	assert.False(t, rec.Time.IsZero())
+}